@@ -0,0 +1,152 @@
+// Package cmd is the package that contains all of the commands for the application.
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/cloudchecker"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/util"
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+	"go.uber.org/multierr"
+)
+
+var (
+	// errInvalidOutputFormat is the error that is returned when the output format is invalid.
+	errInvalidOutputFormat = errors.New("invalid output format: must be " + outputFormatText + " or " + outputFormatJSON)
+
+	// errFailedToWriteCapabilities is the error that is returned when the capabilities cannot be written.
+	errFailedToWriteCapabilities = errors.New("failed to write capabilities")
+)
+
+const (
+	// flagOutput is the name of the flag for the output format.
+	flagOutput = "output"
+	// flagOutputShort is the short name of the flag for the output format.
+	flagOutputShort = "o"
+)
+
+const (
+	// outputFormatText is the output format for humans.
+	outputFormatText = "text"
+
+	// outputFormatJSON is the output format for machines.
+	outputFormatJSON = "json"
+)
+
+// capabilitiesCmd is the command to print which checks run on each cloud provider and how.
+type capabilitiesCmd struct {
+	// logger is the logger.
+	logger *log.Logger
+	// cobraCmd is the Cobra command.
+	cobraCmd *cobra.Command
+}
+
+var _ cmd = &capabilitiesCmd{}
+
+// yesNo returns the human-readable representation of the boolean.
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+
+	return "no"
+}
+
+// writeCapabilitiesText writes the capabilities to the writer as a table per cloud provider.
+func writeCapabilitiesText(w io.Writer, capabilities []cloudchecker.CloudCapabilities) error {
+	// notApplicable is the value of the columns that do not apply to an unsupported check.
+	const notApplicable = "-"
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	for i, c := range capabilities {
+		if i > 0 {
+			if _, err := fmt.Fprintln(tw); err != nil {
+				return err
+			}
+		}
+
+		if _, err := fmt.Fprintf(tw, "%s\nCHECK\tSUPPORTED\tROLE ONLY\tLIVE\tMETHOD\n", c.Cloud); err != nil {
+			return err
+		}
+
+		for _, check := range c.Checks {
+			roleOnly, live, method := yesNo(check.RoleOnly), yesNo(check.Live), check.Method
+
+			if !check.Supported {
+				roleOnly, live, method = notApplicable, notApplicable, notApplicable
+			}
+
+			if _, err := fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", check.Name, yesNo(check.Supported), roleOnly, live, method); err != nil {
+				return err
+			}
+		}
+	}
+
+	return tw.Flush()
+}
+
+// writeCapabilitiesJSON writes the capabilities to the writer as JSON.
+func writeCapabilitiesJSON(w io.Writer, capabilities []cloudchecker.CloudCapabilities) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(capabilities)
+}
+
+// run is the run function for the Capabilities command.
+func (c *capabilitiesCmd) run(cobraCmd *cobra.Command, _ []string) {
+	var write func(io.Writer, []cloudchecker.CloudCapabilities) error
+
+	switch util.Flag(cobraCmd, flagOutput) {
+	case outputFormatText:
+		write = writeCapabilitiesText
+	case outputFormatJSON:
+		write = writeCapabilitiesJSON
+	default:
+		c.logger.Fatal(errInvalidOutputFormat)
+	}
+
+	if err := write(cobraCmd.OutOrStdout(), cloudchecker.Capabilities()); err != nil {
+		c.logger.Fatal(multierr.Combine(errFailedToWriteCapabilities, err))
+	}
+}
+
+// flags sets the flags for the Capabilities command.
+func (c *capabilitiesCmd) flags() {
+	c.cobraCmd.Flags().StringP(flagOutput, flagOutputShort, outputFormatText, "output format ("+outputFormatText+" or "+outputFormatJSON+")")
+}
+
+// newCapabilitiesCmd returns a new capabilitiesCmd.
+func newCapabilitiesCmd(logger *log.Logger, cobraCmd *cobra.Command) *capabilitiesCmd {
+	return &capabilitiesCmd{
+		logger:   logger,
+		cobraCmd: cobraCmd,
+	}
+}
+
+// Capabilities returns a Cobra command to print which checks run on each cloud provider and how.
+func Capabilities(logger *log.Logger) *cobra.Command {
+	cobraCmd := &cobra.Command{
+		Use:   "capabilities",
+		Short: "Print the checks that run on each cloud provider",
+		Long: `Capabilities prints, for each cloud provider, which checks the check command runs, whether they run with the --` + flagRoleOnly + ` flag, whether
+they connect to endpoints outside of the cluster, and how they are performed.
+
+It is derived from the same list of checks that the pod runs, so it always reflects the behavior of this version of the application.`,
+		Args: cobra.NoArgs,
+	}
+
+	cmd := newCapabilitiesCmd(logger, cobraCmd)
+
+	cobraCmd.Run = cmd.run
+
+	cmd.flags()
+
+	return cobraCmd
+}
@@ -0,0 +1,53 @@
+// Package cmd is the package that contains all of the commands for the application.
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/cloud"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/cloudchecker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWriteCapabilitiesText is a test that tests that the writeCapabilitiesText function writes a table per cloud provider.
+func TestWriteCapabilitiesText(t *testing.T) {
+	capabilities := []cloudchecker.CloudCapabilities{
+		{Cloud: cloud.AWS, Checks: []cloudchecker.CheckCapability{
+			{Name: "JWTs", Supported: true, RoleOnly: true, Method: "requests tokens", Live: true},
+		}},
+		{Cloud: cloud.GCP, Checks: []cloudchecker.CheckCapability{
+			{Name: "JWTs", RoleOnly: true},
+		}},
+	}
+
+	var buf bytes.Buffer
+
+	require.NoError(t, writeCapabilitiesText(&buf, capabilities))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+
+	require.Len(t, lines, 7)
+	assert.Equal(t, "aws", lines[0])
+	assert.Equal(t, []string{"JWTs", "yes", "yes", "yes", "requests", "tokens"}, strings.Fields(lines[2]))
+	assert.Empty(t, lines[3])
+	assert.Equal(t, "gcp", lines[4])
+	assert.Equal(t, []string{"JWTs", "no", "-", "-", "-"}, strings.Fields(lines[6]))
+}
+
+// TestWriteCapabilitiesJSON is a test that tests that the writeCapabilitiesJSON function writes the capabilities that can be decoded back.
+func TestWriteCapabilitiesJSON(t *testing.T) {
+	capabilities := cloudchecker.Capabilities()
+
+	var buf bytes.Buffer
+
+	require.NoError(t, writeCapabilitiesJSON(&buf, capabilities))
+
+	var got []cloudchecker.CloudCapabilities
+
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+	assert.Equal(t, capabilities, got)
+}
@@ -3,26 +3,50 @@ package cmd
 
 import (
 	"context"
+	"crypto/x509"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/clock"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/cloud"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/cloud/gcpcloudutil"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/envconfig"
+	pkgerrors "github.com/AlphaSense-Engineering/privatecloud-cli/pkg/errors"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/awsjwtretriever"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/mysqlchecker"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/podsecuritychecker"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/postgresqlchecker"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/providerconfigchecker"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/smtpchecker"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/ssochecker"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/k8s/kubeutil"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/podprotocol"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/registry"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/report"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/util"
 	"github.com/charmbracelet/log"
 	"github.com/spf13/cobra"
 	"go.uber.org/multierr"
+	"golang.org/x/net/http/httpproxy"
 	"gopkg.in/yaml.v2"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/kubernetes"
 	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/rest"
@@ -35,15 +59,30 @@ var (
 	// errFailedToCreateServiceAccount is the error that is returned when the service account cannot be created.
 	errFailedToCreateServiceAccount = errors.New("failed to create ServiceAccount")
 
+	// errFailedToCreateSecret is the error that is returned when the secret of the HTTPS proxy of the pod cannot be created.
+	errFailedToCreateSecret = errors.New("failed to create Secret")
+
 	// errFailedToCreateRole is the error that is returned when the role cannot be created.
 	errFailedToCreateRole = errors.New("failed to create Role")
 
 	// errFailedToCreateClusterRole is the error that is returned when the cluster role cannot be created.
 	errFailedToCreateClusterRole = errors.New("failed to create ClusterRole")
 
+	// errFailedToGetClusterRole is the error that is returned when the cluster role cannot be retrieved.
+	errFailedToGetClusterRole = errors.New("failed to get ClusterRole")
+
+	// errClusterRoleExists is the error that is returned when the cluster role already exists with different rules, as it is not overwritten.
+	errClusterRoleExists = errors.New("ClusterRole already exists with different rules and is not overwritten, delete it or rename it")
+
 	// errFailedToCreateRoleBinding is the error that is returned when the role binding cannot be created.
 	errFailedToCreateRoleBinding = errors.New("failed to create RoleBinding")
 
+	// errDanglingRoleBinding is the error that is returned when a role binding references a role that does not exist.
+	errDanglingRoleBinding = errors.New("role binding references a missing role")
+
+	// errFailedToVerifyRoleBinding is the error that is returned when a role binding or the role it references cannot be retrieved.
+	errFailedToVerifyRoleBinding = errors.New("failed to verify role binding")
+
 	// errFailedToCreateClusterRoleBinding is the error that is returned when the cluster role binding cannot be created.
 	errFailedToCreateClusterRoleBinding = errors.New("failed to create ClusterRoleBinding")
 
@@ -64,14 +103,54 @@ var (
 
 	// errFailedToDeleteServiceAccount is the error that is returned when the service account cannot be deleted.
 	errFailedToDeleteServiceAccount = errors.New("failed to delete ServiceAccount")
+
+	// errFailedToDeleteSecret is the error that is returned when the secret of the HTTPS proxy of the pod cannot be deleted.
+	errFailedToDeleteSecret = errors.New("failed to delete Secret")
+
+	// errInvalidImagePullPolicy is the error that is returned when the image pull policy is invalid.
+	errInvalidImagePullPolicy = errors.New("invalid image pull policy: must be Always, IfNotPresent or Never")
+
+	// errFailedToGetImagePullSecret is the error that is returned when the image pull secret cannot be read to check the image of the Pod.
+	errFailedToGetImagePullSecret = errors.New("failed to get image pull secret")
+
+	// errPodImageNotAvailable is the error that is returned when the registry does not serve the image of the Pod.
+	errPodImageNotAvailable = errors.New("image of the Pod is not available")
+
+	// errInvalidResultsConfigMap is the error that is returned when the ConfigMap to write the results of the check to is not a valid reference.
+	errInvalidResultsConfigMap = errors.New("invalid results ConfigMap: must be in the namespace/name format")
+
+	// errFailedToWriteResultsConfigMap is the error that is returned when the results of the check cannot be written to the ConfigMap.
+	errFailedToWriteResultsConfigMap = errors.New("failed to write results ConfigMap")
+
+	// errFailedToPrintResults is the error that is returned when the results of the check cannot be printed.
+	errFailedToPrintResults = errors.New("failed to print results")
+
+	// errFailedToWriteMetricsFile is the error that is returned when the results of the check cannot be written to the metrics file.
+	errFailedToWriteMetricsFile = errors.New("failed to write metrics file")
+
+	// errInvalidTimeout is the error that is returned when the timeout of the check is negative.
+	errInvalidTimeout = errors.New("invalid timeout: must not be negative")
+
+	// errInvalidPodNamespace is the error that is returned when the namespace of the Pod is not a valid namespace name.
+	errInvalidPodNamespace = errors.New("invalid Pod namespace")
+
+	// errCheckTimedOut is the error that is returned when the check does not finish before its timeout.
+	errCheckTimedOut = errors.New("check timed out")
+
+	// errFailedToVerifyCleanup is the error that is returned when the resources cannot be got to verify that they are gone after the cleanup.
+	errFailedToVerifyCleanup = errors.New("failed to verify cleanup")
 )
 
 const (
 	// flagKubeConfig is the name of the flag for the Kubernetes configuration file.
 	flagKubeConfig = "kubeconfig"
+	// flagKubeConfigData is the name of the flag for the base64 encoded Kubernetes configuration.
+	flagKubeConfigData = "kubeconfig-data"
 
 	// flagCleanupOnly is the name of the flag for the cleanup only flag.
 	flagCleanupOnly = "cleanup-only"
+	// flagVerifyCleanup is the name of the flag for verifying that the resources are gone once they are cleaned up.
+	flagVerifyCleanup = "verify-cleanup"
 
 	// flagDockerRepo is the name of the flag for the Docker repository.
 	flagDockerRepo = "docker-repo"
@@ -79,16 +158,161 @@ const (
 	flagDockerImage = "docker-image"
 	// flagImagePullSecret is the name of the flag for the image pull secret.
 	flagImagePullSecret = "image-pull-secret" // nolint:gosec
+	// flagImagePullPolicy is the name of the flag for the image pull policy.
+	flagImagePullPolicy = "image-pull-policy"
+	// flagCheckImage is the name of the flag for checking that the registry serves the image of the Pod before creating it.
+	flagCheckImage = "check-image"
 
 	// flagGoogleCloudSDKDockerRepo is the name of the flag for the Google Cloud SDK Docker repository.
 	flagGoogleCloudSDKDockerRepo = "google-cloud-sdk-docker-repo"
 	// flagGoogleCloudSDKDockerImage is the name of the flag for the Google Cloud SDK Docker image.
 	flagGoogleCloudSDKDockerImage = "google-cloud-sdk-docker-image"
+
+	// flagDBCAFile is the name of the flag for the CA bundle file used to verify the database TLS connections.
+	flagDBCAFile = "db-ca-file"
+
+	// flagCheckStorageClassProvisioner is the name of the flag for checking the provisioner of the default storage class.
+	flagCheckStorageClassProvisioner = "check-storage-class-provisioner"
+	// flagStorageClassProvisioners is the name of the flag for the provisioners expected of the default storage class.
+	flagStorageClassProvisioners = "storage-class-provisioners"
+
+	// flagMySQLSecret is the name of the flag for the name of the secret that contains the MySQL credentials.
+	flagMySQLSecret = "mysql-secret"
+	// flagPostgreSQLSecret is the name of the flag for the name of the secret that contains the PostgreSQL credentials.
+	flagPostgreSQLSecret = "postgresql-secret"
+	// flagSMTPSecret is the name of the flag for the name of the secret that contains the SMTP credentials.
+	flagSMTPSecret = "smtp-secret"
+	// flagMySQLSecretKeys is the name of the flag for the keys of the secret that contains the MySQL credentials that replace the default ones.
+	flagMySQLSecretKeys = "mysql-secret-keys"
+	// flagPostgreSQLSecretKeys is the name of the flag for the keys of the secret that contains the PostgreSQL credentials that replace the default ones.
+	flagPostgreSQLSecretKeys = "postgresql-secret-keys"
+	// flagSMTPSecretKeys is the name of the flag for the keys of the secret that contains the SMTP credentials that replace the default ones.
+	flagSMTPSecretKeys = "smtp-secret-keys"
+	// flagCheckSMTPConnection is the name of the flag for connecting to the SMTP server and authenticating with the credentials of the secret.
+	flagCheckSMTPConnection = "check-smtp-connection"
+
+	// flagSSOSecret is the name of the flag for the names of the secrets that contain the SSO configurations.
+	flagSSOSecret = "sso-secret"
+	// flagSSOSecretSelector is the name of the flag for the label selector of the secrets that contain the SSO configurations.
+	flagSSOSecretSelector = "sso-secret-selector"
+	// flagSSOSAMLKeys is the name of the flag for the keys that the SAML SSO configurations are required to have besides the entity ID.
+	flagSSOSAMLKeys = "sso-saml-keys"
+
+	// flagCrossplaneNamespace is the name of the flag for the namespace of the service accounts of the Crossplane providers that the AWS role trusts.
+	flagCrossplaneNamespace = "crossplane-namespace"
+	// flagCrossplaneServiceAccountsPrefix is the name of the flag for the prefix of the names of the service accounts of the Crossplane providers that the
+	// AWS role trusts.
+	flagCrossplaneServiceAccountsPrefix = "crossplane-service-accounts-prefix"
+	// flagAllowedExtraPolicyStatements is the name of the flag for the SIDs of the statements that the policies of the AWS Crossplane role may have on top of
+	// the expected ones.
+	flagAllowedExtraPolicyStatements = "allowed-extra-policy-statements"
+
+	// flagExpectedPermissionsFile is the name of the flag for the file that overrides the expected permissions of the Crossplane role in Azure and GCP.
+	flagExpectedPermissionsFile = "expected-permissions-file"
+
+	// flagRoleOnly is the name of the flag for only checking the Crossplane role.
+	flagRoleOnly = "role-only"
+
+	// flagPodSecurityProfile is the name of the flag for the pod security profile of the pods.
+	flagPodSecurityProfile = "pod-security-profile"
+
+	// flagCheckSpiceDB is the name of the flag for checking the capabilities that SpiceDB requires from the PostgreSQL user.
+	flagCheckSpiceDB = "check-spicedb"
+	// flagSpiceDBDatabase is the name of the flag for the name of the database that SpiceDB uses.
+	flagSpiceDBDatabase = "spicedb-database"
+
+	// flagMySQLExpectedConfig is the name of the flag for the override of the expected MySQL configuration.
+	flagMySQLExpectedConfig = "mysql-expected-config"
+
+	// flagPostgreSQLExpectedConfig is the name of the flag for the override of the expected PostgreSQL configuration.
+	flagPostgreSQLExpectedConfig = "postgresql-expected-config"
+
+	// flagConnectTimeout is the name of the flag for the maximum duration of establishing a connection to the endpoints outside of the cluster.
+	flagConnectTimeout = "connect-timeout"
+
+	// flagHTTPSProxy is the name of the flag for the HTTPS proxy of the requests to the endpoints outside of the cluster.
+	flagHTTPSProxy = "https-proxy"
+	// flagNoProxy is the name of the flag for the hosts that bypass the HTTPS proxy.
+	flagNoProxy = "no-proxy"
+	// flagCACert is the name of the flag for the CA bundle file that the servers of the HTTPS requests are verified against along with the CAs of the system.
+	flagCACert = "ca-cert"
+
+	// flagRequiresGPU is the name of the flag for whether the deployment requires GPU nodes.
+	flagRequiresGPU = "requires-gpu"
+
+	// flagMaxClockSkew is the name of the flag for the maximum difference between the clock of the Pod and the one of the OIDC server that is not warned
+	// about.
+	flagMaxClockSkew = "max-clock-skew"
+
+	// flagCheckOIDCDiscovery is the name of the flag for checking that the discovery document of the OIDC server supports the signing algorithm and the
+	// audiences of the JWTs.
+	flagCheckOIDCDiscovery = "check-oidc-discovery"
+	// flagOIDCAudiences is the name of the flag for the audiences that the discovery document of the OIDC server is checked to support.
+	flagOIDCAudiences = "oidc-audiences"
+
+	// flagMaxConcurrency is the name of the flag for the maximum number of the checks of the cloud that the Pod runs at once.
+	flagMaxConcurrency = "max-concurrency"
+
+	// flagSinceVersion is the name of the flag for the lowest version of the Private Cloud that the version of the environment configuration is checked to
+	// not be older than.
+	flagSinceVersion = "since-version"
+
+	// flagNamespacePrefix is the name of the flag for the prefix of the namespaces of the install.
+	flagNamespacePrefix = "namespace-prefix"
+
+	// flagWriteResultsConfigMap is the name of the flag for the ConfigMap, in the namespace/name format, that the results of the check are written to.
+	flagWriteResultsConfigMap = "write-results-configmap"
+	// flagPrintResults is the name of the flag for printing the results of the check as a JSON object.
+	flagPrintResults = "print-results"
+	// flagStreamResults is the name of the flag for printing the results of the check as newline-delimited JSON, one line per result as it is reported.
+	flagStreamResults = "stream-results"
+	// flagMetricsFile is the name of the flag for the file that the results of the check are written to in the OpenMetrics text format.
+	flagMetricsFile = "metrics-file"
+
+	// flagWarningsAsErrors is the name of the flag for failing the check if it logs warnings.
+	flagWarningsAsErrors = "warnings-as-errors"
+
+	// flagTimeout is the name of the flag for the maximum duration of the check.
+	flagTimeout = "timeout"
+
+	// flagPodNamespace is the name of the flag for the namespace of the Pod, its ServiceAccount and the subjects of its RoleBindings.
+	flagPodNamespace = "pod-namespace"
+
+	// flagLocal is the name of the flag for running the checks from the command rather than from the Pod, without creating any resources in the cluster.
+	flagLocal = "local"
 )
 
-// namespaceDefault is the default namespace.
+// runIDLength is the length of the identifier of the run.
+const runIDLength = 8
+
+// namespaceDefault is the default namespace, which the Pod is created in unless the Pod namespace flag is set.
 const namespaceDefault = "default"
 
+const (
+	// exitCodePassed is the exit status of the check that passed without warnings.
+	exitCodePassed = 0
+
+	// exitCodeFailed is the exit status of the check that failed, or that logged warnings when they are treated as errors.
+	exitCodeFailed = 1
+
+	// exitCodePassedWithWarnings is the exit status of the check that passed but logged warnings.
+	exitCodePassedWithWarnings = 2
+)
+
+const (
+	// podServiceAccountName is the name of the service account that the pod runs as.
+	podServiceAccountName = constant.AppName + "-sa"
+
+	// podRoleName is the name of the roles and the cluster role of the pod.
+	podRoleName = constant.AppName + "-role"
+
+	// podRoleBindingName is the name of the role bindings and the cluster role binding of the pod.
+	podRoleBindingName = constant.AppName + "-rolebinding"
+
+	// podProxySecretName is the name of the secret that holds the HTTPS proxy of the pod.
+	podProxySecretName = constant.AppName + "-proxy"
+)
+
 // constRoleNamespaces is the list of namespaces for the roles.
 //
 // Do not modify this variable, it is supposed to be constant.
@@ -100,6 +324,53 @@ var constRoleNamespaces = []string{
 	constant.NamespacePlatform,
 }
 
+// constInstallNamespaces is the list of the namespaces of the install, which are put under the namespace prefix.
+//
+// Do not modify this variable, it is supposed to be constant.
+var constInstallNamespaces = []string{
+	constant.NamespaceAlphaSense,
+	constant.NamespaceMySQL,
+	constant.NamespacePostgres,
+	constant.NamespacePlatform,
+}
+
+// prefixedNamespace returns the namespace under the namespace prefix if it is one of the install, or the namespace itself otherwise, as the Crossplane one
+// is set by its own flag.
+func prefixedNamespace(namespacePrefix string, namespace string) string {
+	if !slices.Contains(constInstallNamespaces, namespace) {
+		return namespace
+	}
+
+	return kubeutil.PrefixNamespace(namespacePrefix, namespace)
+}
+
+// prefixedRoleNamespaces returns the namespaces for the roles under the namespace prefix, with the namespace of the service accounts of the Crossplane
+// providers in place of the crossplane one.
+func prefixedRoleNamespaces(namespacePrefix string, crossplaneNamespace string) []string {
+	namespaces := make([]string, 0, len(constRoleNamespaces))
+
+	for _, ns := range constRoleNamespaces {
+		if ns == constant.NamespaceCrossplane {
+			namespaces = append(namespaces, crossplaneNamespace)
+
+			continue
+		}
+
+		namespaces = append(namespaces, prefixedNamespace(namespacePrefix, ns))
+	}
+
+	return namespaces
+}
+
+// constImagePullPolicies is the list of the valid image pull policies for the pod.
+//
+// Do not modify this variable, it is supposed to be constant.
+var constImagePullPolicies = []corev1.PullPolicy{
+	corev1.PullAlways,
+	corev1.PullIfNotPresent,
+	corev1.PullNever,
+}
+
 // checkCmd is the command to check the infrastructure.
 type checkCmd struct {
 	// logger is the logger.
@@ -111,33 +382,212 @@ type checkCmd struct {
 	envConfig *envconfig.EnvConfig
 	// kubeConfig is the Kubernetes configuration.
 	kubeConfig *rest.Config
+	// kubeContext is the context of the Kubernetes configuration whose cluster is checked, or empty for the current one.
+	kubeContext string
+
+	// runID is the identifier of the run, set as a label on every created resource.
+	runID string
+	// startedAt is the time at which the run started.
+	startedAt time.Time
+	// keepClusterRole is whether the cluster role of the Pod already exists with the expected rules and is not managed by the application, so it is used
+	// as is, and neither overwritten nor deleted by the cleanup.
+	keepClusterRole bool
 
 	// clientset is the Kubernetes clientset.
-	clientset *kubernetes.Clientset
+	clientset kubernetes.Interface
 	// clientsetNamespace is the Kubernetes clientset for the Namespace.
 	clientsetNamespace typedcorev1.NamespaceInterface
 	// clientsetSA is the Kubernetes clientset for the ServiceAccount.
 	clientsetSA typedcorev1.ServiceAccountInterface
+	// clientsetSecret is the Kubernetes clientset for the Secret.
+	clientsetSecret typedcorev1.SecretInterface
 	// clientsetPod is the Kubernetes clientset for the Pod.
 	clientsetPod typedcorev1.PodInterface
+
+	// newClientset is the function that the clientset is created from the Kubernetes configuration with, or nil for kubernetes.NewForConfig.
+	newClientset func(config *rest.Config) (kubernetes.Interface, error)
+	// clock is the clock that the creation times of the resources are read from and that the Pod is polled on.
+	clock clock.Clock
+	// registryClient is the HTTP client that the registry of the image of the Pod is queried with, or nil for one that honors the connect timeout.
+	registryClient *http.Client
+	// proxyConfig is the proxy configuration of the requests that the command makes to the endpoints outside of the cluster, or nil for the one of the
+	// environment.
+	proxyConfig *httpproxy.Config
+	// rootCAs is the pool of the CAs that the servers of the requests that the command makes to the endpoints outside of the cluster are verified against,
+	// or nil for the ones of the system.
+	rootCAs *x509.CertPool
+	// caBundle is the PEM encoded CA bundle of the CA certificate flag that the pool of the CAs is loaded with, passed to the Pod, or nil if the flag is not
+	// set.
+	caBundle []byte
+
+	// exitOnWarnings is whether the check exits with the status of the check that passed with warnings, rather than returning, if it logs warnings.
+	exitOnWarnings bool
+	// warnings is the number of the warnings that the check logged in the run so far, the ones of the Pod included.
+	warnings int
+	// podResults is the list of the results of the checks that the Pod logged, or nil if it did not.
+	podResults []report.Result
+	// resultsStream is the writer that prints each of the results of the checks as it is reported, or nil if the flags do not ask for it.
+	resultsStream *report.StreamWriter
+	// podSchemaSkewed is whether the schema version of the messages of the Pod does not match the one of the CLI, which is warned about once.
+	podSchemaSkewed bool
 }
 
 var _ cmd = &checkCmd{}
 
+// namespace returns the namespace under the namespace prefix of the flags if it is one of the install, or the one of the service accounts of the Crossplane
+// providers if it is the crossplane one.
+func (c *checkCmd) namespace(namespace string) string {
+	if namespace == constant.NamespaceCrossplane {
+		return c.crossplaneNamespace()
+	}
+
+	return prefixedNamespace(util.Flag(c.cobraCmd, flagNamespacePrefix), namespace)
+}
+
+// crossplaneNamespace returns the namespace of the service accounts of the Crossplane providers, or the crossplane one if the flag is empty.
+func (c *checkCmd) crossplaneNamespace() string {
+	if namespace := util.Flag(c.cobraCmd, flagCrossplaneNamespace); namespace != constant.EmptyString {
+		return namespace
+	}
+
+	return constant.NamespaceCrossplane
+}
+
+// podNamespace returns the namespace of the Pod, its ServiceAccount and the subjects of its RoleBindings, or the default one if the flag is empty.
+func (c *checkCmd) podNamespace() string {
+	if namespace := util.Flag(c.cobraCmd, flagPodNamespace); namespace != constant.EmptyString {
+		return namespace
+	}
+
+	return namespaceDefault
+}
+
+// validatePodNamespace returns an error if the namespace of the Pod is not a valid namespace name.
+func validatePodNamespace(namespace string) error {
+	if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+		return fmt.Errorf("%w %q: %s", errInvalidPodNamespace, namespace, strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// ensuredNamespaces returns the namespaces that are ensured to exist, the ones for the roles and the one of the Pod, unless it is the default one, which
+// always exists.
+func (c *checkCmd) ensuredNamespaces() []string {
+	namespaces := c.roleNamespaces()
+
+	if podNamespace := c.podNamespace(); podNamespace != namespaceDefault && !slices.Contains(namespaces, podNamespace) {
+		namespaces = append([]string{podNamespace}, namespaces...)
+	}
+
+	return namespaces
+}
+
+// roleNamespaces returns the namespaces for the roles under the namespace prefix of the flags, with the one of the service accounts of the Crossplane
+// providers in place of the crossplane one.
+func (c *checkCmd) roleNamespaces() []string {
+	return prefixedRoleNamespaces(util.Flag(c.cobraCmd, flagNamespacePrefix), c.crossplaneNamespace())
+}
+
 // setupClientsets sets up the clientsets.
-func (c *checkCmd) setupClientsets() (err error) {
-	c.clientset, err = kubernetes.NewForConfig(c.kubeConfig)
+func (c *checkCmd) setupClientsets() error {
+	newClientset := c.newClientset
+	if newClientset == nil {
+		newClientset = func(config *rest.Config) (kubernetes.Interface, error) { return kubernetes.NewForConfig(config) }
+	}
+
+	clientset, err := newClientset(c.kubeConfig)
 	if err != nil {
 		return multierr.Combine(errFailedToCreateKubernetesClientset, err)
 	}
 
+	c.setClientset(clientset)
+
+	return nil
+}
+
+// setClientset sets the clientset and the clientsets derived from it.
+func (c *checkCmd) setClientset(clientset kubernetes.Interface) {
+	c.clientset = clientset
+
 	c.clientsetNamespace = c.clientset.CoreV1().Namespaces()
 
-	c.clientsetSA = c.clientset.CoreV1().ServiceAccounts(namespaceDefault)
+	c.clientsetSA = c.clientset.CoreV1().ServiceAccounts(c.podNamespace())
+
+	c.clientsetSecret = c.clientset.CoreV1().Secrets(c.podNamespace())
+
+	c.clientsetPod = c.clientset.CoreV1().Pods(c.podNamespace())
+}
+
+// objectMeta returns the object metadata for a resource created by the Check command, with the labels and the annotations identifying it.
+//
+// Pass an empty namespace for cluster-scoped resources.
+func (c *checkCmd) objectMeta(name string, namespace string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      name,
+		Namespace: namespace,
+		Labels: map[string]string{
+			constant.LabelManagedBy: constant.AppName,
+			constant.LabelVersion:   constant.BuildVersion,
+			constant.LabelRunID:     c.runID,
+		},
+		Annotations: map[string]string{
+			constant.AnnotationCreatedAt: c.clock.Now().UTC().Format(time.RFC3339),
+		},
+	}
+}
+
+// ensureNamespaces ensures that the namespaces for the roles and the one of the Pod exist, creating the missing ones.
+//
+// It fails listing all of the namespaces that are missing and cannot be created, e.g. due to insufficient permissions.
+func (c *checkCmd) ensureNamespaces(ctx context.Context) error {
+	const (
+		// logMsgNamespaceEnsured is the message that is logged when the namespace is ensured.
+		logMsgNamespaceEnsured = "ensured %s Namespace"
+
+		// logMsgNamespaceCreated is the message that is logged when the namespace is created.
+		logMsgNamespaceCreated = "created %s Namespace"
+	)
+
+	var (
+		missing []string
+		errs    error
+	)
+
+	for _, ns := range c.ensuredNamespaces() {
+		_, err := c.clientsetNamespace.Get(ctx, ns, metav1.GetOptions{})
+		if err == nil {
+			c.logger.Debugf(logMsgNamespaceEnsured, ns)
+
+			continue
+		}
+
+		if !k8serrors.IsNotFound(err) {
+			return multierr.Combine(errFailedToEnsureNamespace, err)
+		}
+
+		// The namespaces are intentionally not labeled as managed by the application, as they are used by Private Cloud and must outlive the check, as can
+		// the one of the Pod, which may be shared with other workloads.
+		if _, err = c.clientsetNamespace.Create(ctx, &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: ns,
+			},
+		}, metav1.CreateOptions{}); err != nil && !k8serrors.IsAlreadyExists(err) {
+			missing = append(missing, ns)
+
+			errs = multierr.Append(errs, err)
+
+			continue
+		}
+
+		c.logger.Debugf(logMsgNamespaceCreated, ns)
+	}
 
-	c.clientsetPod = c.clientset.CoreV1().Pods(namespaceDefault)
+	if len(missing) > 0 {
+		return multierr.Combine(errFailedToEnsureNamespace, pkgerrors.NewNamespacesMissing(missing), errs)
+	}
 
-	return
+	return nil
 }
 
 // createServiceAccount creates the service account.
@@ -146,21 +596,201 @@ func (c *checkCmd) createServiceAccount(ctx context.Context, serviceAccountName
 	const logMsgServiceAccountCreated = "created %s/%s ServiceAccount"
 
 	serviceAccount := &corev1.ServiceAccount{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      serviceAccountName,
-			Namespace: namespaceDefault,
-		},
+		ObjectMeta: c.objectMeta(serviceAccountName, c.podNamespace()),
 	}
 
 	if _, err := c.clientsetSA.Create(ctx, serviceAccount, metav1.CreateOptions{}); err != nil {
 		return multierr.Combine(errFailedToCreateServiceAccount, err)
 	}
 
-	c.logger.Debugf(logMsgServiceAccountCreated, namespaceDefault, serviceAccount.Name)
+	c.logger.Debugf(logMsgServiceAccountCreated, c.podNamespace(), serviceAccount.Name)
+
+	return nil
+}
+
+// createProxySecret creates the secret that holds the HTTPS proxy of the pod, if the flag of the HTTPS proxy is set, so that the password that its URL
+// may contain is not part of the spec of the pod.
+func (c *checkCmd) createProxySecret(ctx context.Context) error {
+	// logMsgSecretCreated is the message that is logged when the secret is created.
+	const logMsgSecretCreated = "created %s/%s Secret"
+
+	httpsProxy := util.Flag(c.cobraCmd, flagHTTPSProxy)
+	if httpsProxy == constant.EmptyString {
+		return nil
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: c.objectMeta(podProxySecretName, c.podNamespace()),
+		Data:       map[string][]byte{envVarHTTPSProxy: []byte(httpsProxy)},
+	}
+
+	if _, err := c.clientsetSecret.Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+		return multierr.Combine(errFailedToCreateSecret, err)
+	}
+
+	c.logger.Debugf(logMsgSecretCreated, c.podNamespace(), secret.Name)
 
 	return nil
 }
 
+// clusterPolicyRules returns the rules of the cluster role of the pod, given the namespace of the service accounts of the Crossplane providers.
+func clusterPolicyRules(crossplaneNamespace string) []rbacv1.PolicyRule {
+	return []rbacv1.PolicyRule{
+		{APIGroups: []string{"storage.k8s.io"}, Resources: []string{"storageclasses"}, Verbs: []string{rbacv1.VerbAll}},
+		{APIGroups: []string{constant.EmptyString}, Resources: []string{"nodes"}, Verbs: []string{rbacv1.VerbAll}},
+		{
+			APIGroups: []string{
+				providerconfigchecker.GVR(cloud.AWS).Group,
+				providerconfigchecker.GVR(cloud.Azure).Group,
+				providerconfigchecker.GVR(cloud.GCP).Group,
+			},
+			Resources: []string{providerconfigchecker.GVR(cloud.AWS).Resource},
+			Verbs:     []string{"get"},
+		},
+		// The namespace is read to check the PodSecurity standard it enforces before creating the GCP Crossplane role checker pod in it.
+		{
+			APIGroups:     []string{constant.EmptyString},
+			Resources:     []string{"namespaces"},
+			ResourceNames: []string{crossplaneNamespace},
+			Verbs:         []string{"get"},
+		},
+	}
+}
+
+// policyRuleString returns the human-readable representation of the policy rule, listing only the fields that are set.
+func policyRuleString(rule rbacv1.PolicyRule) string {
+	var parts []string
+
+	for _, field := range []struct {
+		name   string
+		values []string
+	}{
+		{"apiGroups", rule.APIGroups},
+		{"resources", rule.Resources},
+		{"resourceNames", rule.ResourceNames},
+		{"nonResourceURLs", rule.NonResourceURLs},
+		{"verbs", rule.Verbs},
+	} {
+		if len(field.values) > 0 {
+			parts = append(parts, fmt.Sprintf("%s=%q", field.name, field.values))
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// diffPolicyRules returns the diff of the existing rules against the expected ones, with a line prefixed with "-" for each of the expected rules that is
+// missing, and a line prefixed with "+" for each of the existing rules that is not expected. It returns nil if the rules are the same.
+func diffPolicyRules(expected []rbacv1.PolicyRule, existing []rbacv1.PolicyRule) []string {
+	toSet := func(rules []rbacv1.PolicyRule) map[string]struct{} {
+		set := make(map[string]struct{}, len(rules))
+
+		for _, rule := range rules {
+			set[policyRuleString(rule)] = struct{}{}
+		}
+
+		return set
+	}
+
+	contains := func(set map[string]struct{}, s string) bool {
+		_, ok := set[s]
+
+		return ok
+	}
+
+	expectedSet, existingSet := toSet(expected), toSet(existing)
+
+	var diff []string
+
+	for _, rule := range expected {
+		if s := policyRuleString(rule); !contains(existingSet, s) {
+			diff = append(diff, "- "+s)
+		}
+	}
+
+	for _, rule := range existing {
+		if s := policyRuleString(rule); !contains(expectedSet, s) {
+			diff = append(diff, "+ "+s)
+		}
+	}
+
+	return diff
+}
+
+// checkClusterRoleConflict checks whether the cluster role already exists, so that an existing one, such as a role managed by an operator or aggregated
+// from other roles, is neither overwritten nor deleted by the cleanup.
+//
+// An existing cluster role that is managed by the application was left behind by a previous run, e.g. an interrupted one, and is reported so that the
+// resources of that run are cleaned up first. One that is not is used as is if it has the expected rules, and the diff of its rules against the expected
+// ones is logged as a warning otherwise.
+//
+// It returns the cluster role if it was left behind by a previous run and nil otherwise, or an error if it has different rules and is not managed by the
+// application, or cannot be retrieved.
+func (c *checkCmd) checkClusterRoleConflict(ctx context.Context, roleName string) (*rbacv1.ClusterRole, error) {
+	const (
+		// logMsgClusterRoleAggregated is the message that is logged when the existing cluster role is aggregated, so its rules are managed by the controller.
+		logMsgClusterRoleAggregated = "%s ClusterRole already exists and is aggregated, its rules are managed by the aggregation controller"
+
+		// logMsgClusterRoleRulesDiffer is the message that is logged when the rules of the existing cluster role differ from the expected ones.
+		logMsgClusterRoleRulesDiffer = "%s ClusterRole already exists with different rules (- expected, + existing):\n%s"
+
+		// logMsgClusterRoleLeftBehind is the message that is logged when the existing cluster role was left behind by a previous run.
+		logMsgClusterRoleLeftBehind = "%s ClusterRole was left behind by a previous run, cleaning up its resources first"
+
+		// logMsgClusterRoleKept is the message that is logged when the existing cluster role is used as is.
+		logMsgClusterRoleKept = "%s ClusterRole already exists with the expected rules and is not managed by %s, using it as is without deleting it"
+	)
+
+	c.keepClusterRole = false
+
+	existing, err := c.clientset.RbacV1().ClusterRoles().Get(ctx, roleName, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, multierr.Combine(errFailedToGetClusterRole, err)
+	}
+
+	if existing.Labels[constant.LabelManagedBy] == constant.AppName {
+		c.warnf(logMsgClusterRoleLeftBehind, roleName)
+
+		return existing, nil
+	}
+
+	if existing.AggregationRule != nil {
+		c.warnf(logMsgClusterRoleAggregated, roleName)
+	}
+
+	if diff := diffPolicyRules(clusterPolicyRules(c.crossplaneNamespace()), existing.Rules); diff != nil {
+		c.warnf(logMsgClusterRoleRulesDiffer, roleName, strings.Join(diff, "\n"))
+
+		return nil, fmt.Errorf("%w: %s", errClusterRoleExists, roleName)
+	}
+
+	c.warnf(logMsgClusterRoleKept, roleName, constant.AppName)
+
+	c.keepClusterRole = true
+
+	return nil, nil
+}
+
+// checkVersions is the function that warns if the version of the environment configuration is older than the lowest supported one of the flag, as the
+// checks of an incompatible CLI may report false failures or miss the actual ones.
+//
+// Nothing is checked unless the flag is set, as the CLI does not know the versions of the Private Cloud that it supports; they are listed in the
+// compatibility matrix.
+func (c *checkCmd) checkVersions() {
+	since := util.Flag(c.cobraCmd, flagSinceVersion)
+	if since == constant.EmptyString {
+		return
+	}
+
+	if err := c.envConfig.CheckSinceVersion(since); err != nil {
+		c.warnf("%v", err)
+	}
+}
+
 // createRoles creates the roles.
 //
 // nolint:funlen
@@ -177,10 +807,10 @@ func (c *checkCmd) createRoles(ctx context.Context, roleName string) error {
 		namespace string
 		rules     []rbacv1.PolicyRule
 	}{
-		{constant.NamespaceAlphaSense, []rbacv1.PolicyRule{
+		{c.namespace(constant.NamespaceAlphaSense), []rbacv1.PolicyRule{
 			{APIGroups: []string{constant.EmptyString}, Resources: []string{"secrets"}, Verbs: []string{rbacv1.VerbAll}},
 		}},
-		{constant.NamespaceCrossplane, []rbacv1.PolicyRule{
+		{c.namespace(constant.NamespaceCrossplane), []rbacv1.PolicyRule{
 			{APIGroups: []string{constant.EmptyString}, Resources: []string{"pods"}, Verbs: []string{rbacv1.VerbAll}},
 			{APIGroups: []string{constant.EmptyString}, Resources: []string{"pods/log"}, Verbs: []string{rbacv1.VerbAll}},
 			{APIGroups: []string{constant.EmptyString}, Resources: []string{"pods"}, Verbs: []string{rbacv1.VerbAll}},
@@ -188,29 +818,21 @@ func (c *checkCmd) createRoles(ctx context.Context, roleName string) error {
 			{APIGroups: []string{constant.EmptyString}, Resources: []string{"serviceaccounts"}, Verbs: []string{rbacv1.VerbAll}},
 			{APIGroups: []string{constant.EmptyString}, Resources: []string{"serviceaccounts/token"}, Verbs: []string{rbacv1.VerbAll}},
 		}},
-		{constant.NamespaceMySQL, []rbacv1.PolicyRule{
+		{c.namespace(constant.NamespaceMySQL), []rbacv1.PolicyRule{
 			{APIGroups: []string{constant.EmptyString}, Resources: []string{"secrets"}, Verbs: []string{rbacv1.VerbAll}}},
 		},
-		{constant.NamespacePostgres, []rbacv1.PolicyRule{
+		{c.namespace(constant.NamespacePostgres), []rbacv1.PolicyRule{
 			{APIGroups: []string{constant.EmptyString}, Resources: []string{"secrets"}, Verbs: []string{rbacv1.VerbAll}}},
 		},
-		{constant.NamespacePlatform, []rbacv1.PolicyRule{
+		{c.namespace(constant.NamespacePlatform), []rbacv1.PolicyRule{
 			{APIGroups: []string{constant.EmptyString}, Resources: []string{"secrets"}, Verbs: []string{rbacv1.VerbAll}}},
 		},
 	}
 
-	clusterPolicyRules := []rbacv1.PolicyRule{
-		{APIGroups: []string{"storage.k8s.io"}, Resources: []string{"storageclasses"}, Verbs: []string{rbacv1.VerbAll}},
-		{APIGroups: []string{constant.EmptyString}, Resources: []string{"nodes"}, Verbs: []string{rbacv1.VerbAll}},
-	}
-
 	for _, pair := range namespacePolicyRules {
 		role := &rbacv1.Role{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      roleName,
-				Namespace: pair.namespace,
-			},
-			Rules: pair.rules,
+			ObjectMeta: c.objectMeta(roleName, pair.namespace),
+			Rules:      pair.rules,
 		}
 
 		if _, err := c.clientset.RbacV1().Roles(pair.namespace).Create(ctx, role, metav1.CreateOptions{}); err != nil {
@@ -220,11 +842,13 @@ func (c *checkCmd) createRoles(ctx context.Context, roleName string) error {
 		c.logger.Debugf(logMsgRoleCreated, pair.namespace, role.Name)
 	}
 
+	if c.keepClusterRole {
+		return nil
+	}
+
 	clusterRole := &rbacv1.ClusterRole{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: roleName,
-		},
-		Rules: clusterPolicyRules,
+		ObjectMeta: c.objectMeta(roleName, constant.EmptyString),
+		Rules:      clusterPolicyRules(c.crossplaneNamespace()),
 	}
 
 	if _, err := c.clientset.RbacV1().ClusterRoles().Create(ctx, clusterRole, metav1.CreateOptions{}); err != nil {
@@ -252,16 +876,13 @@ func (c *checkCmd) createRoleBindings(ctx context.Context, serviceAccountName st
 	constSubjects := []rbacv1.Subject{{
 		Kind:      rbacv1.ServiceAccountKind,
 		Name:      serviceAccountName,
-		Namespace: namespaceDefault,
+		Namespace: c.podNamespace(),
 	}}
 
-	for _, ns := range constRoleNamespaces {
+	for _, ns := range c.roleNamespaces() {
 		if _, err := c.clientset.RbacV1().RoleBindings(ns).Create(ctx, &rbacv1.RoleBinding{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      roleBindingName,
-				Namespace: ns,
-			},
-			Subjects: constSubjects,
+			ObjectMeta: c.objectMeta(roleBindingName, ns),
+			Subjects:   constSubjects,
 			RoleRef: rbacv1.RoleRef{
 				APIGroup: rbacv1.GroupName,
 				Kind:     "Role",
@@ -275,10 +896,8 @@ func (c *checkCmd) createRoleBindings(ctx context.Context, serviceAccountName st
 	}
 
 	if _, err := c.clientset.RbacV1().ClusterRoleBindings().Create(ctx, &rbacv1.ClusterRoleBinding{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: roleBindingName,
-		},
-		Subjects: constSubjects,
+		ObjectMeta: c.objectMeta(roleBindingName, constant.EmptyString),
+		Subjects:   constSubjects,
 		RoleRef: rbacv1.RoleRef{
 			APIGroup: rbacv1.GroupName,
 			Kind:     "ClusterRole",
@@ -293,55 +912,410 @@ func (c *checkCmd) createRoleBindings(ctx context.Context, serviceAccountName st
 	return nil
 }
 
-// createPod creates the pod.
+// verifyRoleBindings verifies that the role bindings and the cluster role binding reference roles that exist, so that the leftovers of an earlier run or
+// a role that is missing despite its creation succeeding do not leave the pod without its permissions.
 //
-// nolint:funlen
-func (c *checkCmd) createPod(ctx context.Context, serviceAccountName string) error {
-	envConfigBytes, err := yaml.Marshal(c.envConfig)
-	if err != nil {
-		return multierr.Combine(errFailedToMarshalEnvConfig, err)
+// It returns an error listing every role binding that references a missing role, or that cannot be verified.
+func (c *checkCmd) verifyRoleBindings(ctx context.Context, roleBindingName string) error {
+	// resolveRoleRef is the function that returns an error if the role that the role reference of the binding in the namespace points at is missing.
+	resolveRoleRef := func(namespace string, roleRef rbacv1.RoleRef) error {
+		var err error
+
+		if roleRef.Kind == "ClusterRole" {
+			_, err = c.clientset.RbacV1().ClusterRoles().Get(ctx, roleRef.Name, metav1.GetOptions{})
+		} else {
+			_, err = c.clientset.RbacV1().Roles(namespace).Get(ctx, roleRef.Name, metav1.GetOptions{})
+		}
+
+		return err
 	}
 
-	envVars := []corev1.EnvVar{{
-		Name:  envVarEnvConfig,
-		Value: base64.StdEncoding.EncodeToString(envConfigBytes),
-	}}
+	var errs error
 
-	for _, flag := range []struct {
-		name  string
-		value string
-	}{
-		{envVarGoogleCloudSDKDockerRepo, util.Flag(c.cobraCmd, flagGoogleCloudSDKDockerRepo)},
-		{envVarGoogleCloudSDKDockerImage, util.Flag(c.cobraCmd, flagGoogleCloudSDKDockerImage)},
-	} {
-		if flag.value != constant.EmptyString {
-			envVars = append(envVars, corev1.EnvVar{
-				Name:  flag.name,
-				Value: flag.value,
-			})
+	for _, ns := range c.roleNamespaces() {
+		roleBinding, err := c.clientset.RbacV1().RoleBindings(ns).Get(ctx, roleBindingName, metav1.GetOptions{})
+		if err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("%w: %s/%s RoleBinding: %w", errFailedToVerifyRoleBinding, ns, roleBindingName, err))
+
+			continue
+		}
+
+		if err := resolveRoleRef(ns, roleBinding.RoleRef); k8serrors.IsNotFound(err) {
+			errs = multierr.Append(errs, fmt.Errorf(
+				"%w: %s/%s RoleBinding references %s %s", errDanglingRoleBinding, ns, roleBindingName, roleBinding.RoleRef.Kind, roleBinding.RoleRef.Name,
+			))
+		} else if err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("%w: %s/%s RoleBinding: %w", errFailedToVerifyRoleBinding, ns, roleBindingName, err))
 		}
 	}
 
-	pod := &corev1.Pod{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: constant.AppName,
-		},
-		Spec: corev1.PodSpec{
-			ServiceAccountName: serviceAccountName,
-			Containers: []corev1.Container{{
-				Name: constant.AppName,
-				Image: strings.Join(
-					[]string{
-						util.Flag(c.cobraCmd, flagDockerRepo),
-						util.Flag(c.cobraCmd, flagDockerImage),
-					},
-					string(constant.HTTPPathSeparator),
-				),
-				Env:             envVars,
-				ImagePullPolicy: corev1.PullAlways,
-			}},
-			RestartPolicy: corev1.RestartPolicyNever,
-		},
+	clusterRoleBinding, err := c.clientset.RbacV1().ClusterRoleBindings().Get(ctx, roleBindingName, metav1.GetOptions{})
+	if err != nil {
+		return multierr.Append(errs, fmt.Errorf("%w: %s ClusterRoleBinding: %w", errFailedToVerifyRoleBinding, roleBindingName, err))
+	}
+
+	if err := resolveRoleRef(constant.EmptyString, clusterRoleBinding.RoleRef); k8serrors.IsNotFound(err) {
+		errs = multierr.Append(errs, fmt.Errorf(
+			"%w: %s ClusterRoleBinding references %s %s", errDanglingRoleBinding, roleBindingName, clusterRoleBinding.RoleRef.Kind, clusterRoleBinding.RoleRef.Name,
+		))
+	} else if err != nil {
+		errs = multierr.Append(errs, fmt.Errorf("%w: %s ClusterRoleBinding: %w", errFailedToVerifyRoleBinding, roleBindingName, err))
+	}
+
+	return errs
+}
+
+// imagePullPolicy returns the image pull policy for the pod, or an error if the flag value is not a valid image pull policy.
+func (c *checkCmd) imagePullPolicy() (corev1.PullPolicy, error) {
+	policy := corev1.PullPolicy(util.Flag(c.cobraCmd, flagImagePullPolicy))
+
+	if !slices.Contains(constImagePullPolicies, policy) {
+		return constant.EmptyString, errInvalidImagePullPolicy
+	}
+
+	return policy, nil
+}
+
+// podImage returns the reference of the Docker image of the pod.
+func (c *checkCmd) podImage() string {
+	return strings.Join(
+		[]string{
+			util.Flag(c.cobraCmd, flagDockerRepo),
+			util.Flag(c.cobraCmd, flagDockerImage),
+		},
+		string(constant.HTTPPathSeparator),
+	)
+}
+
+// checkPodImage checks that the registry serves the image of the pod, with the credentials of the image pull secret if set, so that a wrong repository,
+// image or secret fails the check before any resources are created rather than as a Pod stuck pulling the image.
+func (c *checkCmd) checkPodImage(ctx context.Context) error {
+	// logMsgPodImageAvailable is the message that is logged when the image of the pod is available.
+	const logMsgPodImageAvailable = "image %s is available"
+
+	image := c.podImage()
+
+	ref, err := registry.ParseReference(image)
+	if err != nil {
+		return err
+	}
+
+	var creds *registry.Credentials
+
+	if imagePullSecretName := util.Flag(c.cobraCmd, flagImagePullSecret); imagePullSecretName != constant.EmptyString {
+		secret, err := kubeutil.GetSecret(ctx, c.clientset, c.podNamespace(), imagePullSecretName)
+		if err != nil {
+			return multierr.Combine(errFailedToGetImagePullSecret, err)
+		}
+
+		if creds, err = registry.CredentialsFromDockerConfig(secret.Data[corev1.DockerConfigJsonKey], ref.Host); err != nil {
+			return multierr.Combine(errFailedToGetImagePullSecret, err)
+		}
+	}
+
+	client := c.registryClient
+	if client == nil {
+		client = util.NewHTTPClient(util.FlagDuration(c.cobraCmd, flagConnectTimeout), c.proxyConfig, c.rootCAs)
+	}
+
+	if err := registry.CheckManifest(ctx, client, ref, creds); err != nil {
+		return fmt.Errorf("%w: %s: %w", errPodImageNotAvailable, image, err)
+	}
+
+	c.logger.Debugf(logMsgPodImageAvailable, image)
+
+	return nil
+}
+
+// readFlags is the function that reads the proxy configuration and the CA bundle of the CA certificate flag from the flags, loading the pool of the CAs
+// of the system and of the bundle once for the requests of the command and of the Pod, or nil for both if the flag is not set.
+//
+// It returns an error if the HTTPS proxy is not valid, or if the CA bundle cannot be read or contains no certificates.
+func (c *checkCmd) readFlags() error {
+	proxyConfig, err := util.NewProxyConfig(util.Flag(c.cobraCmd, flagHTTPSProxy), util.Flag(c.cobraCmd, flagNoProxy))
+	if err != nil {
+		return err
+	}
+
+	var caBundle []byte
+
+	var rootCAs *x509.CertPool
+
+	if caCertFile := util.Flag(c.cobraCmd, flagCACert); caCertFile != constant.EmptyString {
+		if caBundle, err = os.ReadFile(caCertFile); err != nil { // nolint:gosec
+			return multierr.Combine(errFailedToReadCABundle, err)
+		}
+
+		if rootCAs, err = util.SystemCertPoolWithCABundle(caBundle); err != nil {
+			return multierr.Combine(errFailedToReadCABundle, err)
+		}
+	}
+
+	c.proxyConfig = proxyConfig
+	c.caBundle = caBundle
+	c.rootCAs = rootCAs
+
+	return nil
+}
+
+// secretKeys is the function that returns the keys of the MySQL, the PostgreSQL and the SMTP secrets of the flags that replace the default ones.
+//
+// It returns an error if a key that they replace is not one of the default ones of the check.
+func (c *checkCmd) secretKeys() (map[string]string, map[string]string, map[string]string, error) {
+	var keys [3]map[string]string
+
+	for i, flag := range []struct {
+		name        string
+		defaultKeys []string
+	}{
+		{flagMySQLSecretKeys, mysqlchecker.SecretKeys()},
+		{flagPostgreSQLSecretKeys, postgresqlchecker.SecretKeys()},
+		{flagSMTPSecretKeys, smtpchecker.SecretKeys()},
+	} {
+		value, err := c.cobraCmd.Flags().GetStringToString(flag.name)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		if err := kubeutil.ValidateSecretKeys(value, flag.defaultKeys); err != nil {
+			return nil, nil, nil, fmt.Errorf("--%s: %w", flag.name, err)
+		}
+
+		keys[i] = value
+	}
+
+	return keys[0], keys[1], keys[2], nil
+}
+
+// buildPod builds the pod with the given pod security profile.
+//
+// nolint:funlen
+func (c *checkCmd) buildPod(serviceAccountName string, podSecurityProfile string) (*corev1.Pod, error) {
+	envConfigBytes, err := yaml.Marshal(c.envConfig)
+	if err != nil {
+		return nil, multierr.Combine(errFailedToMarshalEnvConfig, err)
+	}
+
+	imagePullPolicy, err := c.imagePullPolicy()
+	if err != nil {
+		return nil, err
+	}
+
+	// The requested pod security profile is passed to the pod rather than the one the pod is built with, as the pods that it creates run in another
+	// namespace, which it checks on its own.
+	envVars := []corev1.EnvVar{{
+		Name:  envVarEnvConfig,
+		Value: base64.StdEncoding.EncodeToString(envConfigBytes),
+	}, {
+		Name:  envVarPodSecurityProfile,
+		Value: util.Flag(c.cobraCmd, flagPodSecurityProfile),
+	}, {
+		Name:  envVarConnectTimeout,
+		Value: util.FlagDuration(c.cobraCmd, flagConnectTimeout).String(),
+	}, {
+		Name:  envVarMaxClockSkew,
+		Value: util.FlagDuration(c.cobraCmd, flagMaxClockSkew).String(),
+	}, {
+		Name:  envVarMaxConcurrency,
+		Value: strconv.Itoa(util.FlagInt(c.cobraCmd, flagMaxConcurrency)),
+	}}
+
+	if util.FlagBool(c.cobraCmd, flagRoleOnly) {
+		envVars = append(envVars, corev1.EnvVar{
+			Name:  envVarRoleOnly,
+			Value: strconv.FormatBool(true),
+		})
+	}
+
+	if util.FlagBool(c.cobraCmd, flagCheckStorageClassProvisioner) {
+		envVars = append(envVars, corev1.EnvVar{
+			Name:  envVarCheckStorageClassProvisioner,
+			Value: strconv.FormatBool(true),
+		})
+	}
+
+	if util.FlagBool(c.cobraCmd, flagCheckOIDCDiscovery) {
+		envVars = append(envVars, corev1.EnvVar{
+			Name:  envVarCheckOIDCDiscovery,
+			Value: strconv.FormatBool(true),
+		})
+	}
+
+	// The proxy flags are passed to the pod as the standard environment variables rather than the proxy of the environment of the command, which is the one
+	// of the machine that runs it rather than of the cluster. The HTTPS proxy is read from its secret, as its URL may contain a password.
+	if util.Flag(c.cobraCmd, flagHTTPSProxy) != constant.EmptyString {
+		envVars = append(envVars, corev1.EnvVar{
+			Name: envVarHTTPSProxy,
+			ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: podProxySecretName},
+				Key:                  envVarHTTPSProxy,
+			}},
+		})
+	}
+
+	if noProxy := util.Flag(c.cobraCmd, flagNoProxy); noProxy != constant.EmptyString {
+		envVars = append(envVars, corev1.EnvVar{
+			Name:  envVarNoProxy,
+			Value: noProxy,
+		})
+	}
+
+	if namespacePrefix := util.Flag(c.cobraCmd, flagNamespacePrefix); namespacePrefix != constant.EmptyString {
+		envVars = append(envVars, corev1.EnvVar{
+			Name:  envVarNamespacePrefix,
+			Value: namespacePrefix,
+		})
+	}
+
+	if c.reportsResults() {
+		envVars = append(envVars, corev1.EnvVar{
+			Name:  envVarReportResults,
+			Value: strconv.FormatBool(true),
+		})
+	}
+
+	// The environment configuration already tells the pod whether the deployment requires GPU nodes, so the flag is only passed to override it.
+	if c.cobraCmd.Flags().Changed(flagRequiresGPU) {
+		envVars = append(envVars, corev1.EnvVar{
+			Name:  envVarRequiresGPU,
+			Value: strconv.FormatBool(util.FlagBool(c.cobraCmd, flagRequiresGPU)),
+		})
+	}
+
+	if util.FlagBool(c.cobraCmd, flagCheckSpiceDB) {
+		envVars = append(envVars, corev1.EnvVar{
+			Name:  envVarCheckSpiceDB,
+			Value: strconv.FormatBool(true),
+		})
+	}
+
+	if util.FlagBool(c.cobraCmd, flagCheckSMTPConnection) {
+		envVars = append(envVars, corev1.EnvVar{
+			Name:  envVarCheckSMTPConnection,
+			Value: strconv.FormatBool(true),
+		})
+	}
+
+	if dbCAFile := util.Flag(c.cobraCmd, flagDBCAFile); dbCAFile != constant.EmptyString {
+		dbCABundle, err := os.ReadFile(dbCAFile) // nolint:gosec
+		if err != nil {
+			return nil, multierr.Combine(errFailedToReadDBCABundle, err)
+		}
+
+		// Parse the CA bundle here as well, so that an invalid bundle is reported before any resources are created in the cluster.
+		if _, err := util.TLSConfigFromCABundle(dbCABundle); err != nil {
+			return nil, multierr.Combine(errFailedToReadDBCABundle, err)
+		}
+
+		envVars = append(envVars, corev1.EnvVar{
+			Name:  envVarDBCABundle,
+			Value: base64.StdEncoding.EncodeToString(dbCABundle),
+		})
+	}
+
+	if c.caBundle != nil {
+		envVars = append(envVars, corev1.EnvVar{
+			Name:  envVarCABundle,
+			Value: base64.StdEncoding.EncodeToString(c.caBundle),
+		})
+	}
+
+	if expectedPermissionsFile := util.Flag(c.cobraCmd, flagExpectedPermissionsFile); expectedPermissionsFile != constant.EmptyString {
+		expectedPermissions, err := os.ReadFile(expectedPermissionsFile) // nolint:gosec
+		if err != nil {
+			return nil, multierr.Combine(errFailedToReadExpectedPermissions, err)
+		}
+
+		envVars = append(envVars, corev1.EnvVar{
+			Name:  envVarExpectedPermissions,
+			Value: base64.StdEncoding.EncodeToString(expectedPermissions),
+		})
+	}
+
+	ssoSecretNames, err := c.cobraCmd.Flags().GetStringSlice(flagSSOSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	ssoSAMLKeys, err := c.cobraCmd.Flags().GetStringSlice(flagSSOSAMLKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	storageClassProvisioners, err := c.cobraCmd.Flags().GetStringSlice(flagStorageClassProvisioners)
+	if err != nil {
+		return nil, err
+	}
+
+	oidcAudiences, err := c.cobraCmd.Flags().GetStringSlice(flagOIDCAudiences)
+	if err != nil {
+		return nil, err
+	}
+
+	allowedExtraPolicyStatements, err := c.cobraCmd.Flags().GetStringSlice(flagAllowedExtraPolicyStatements)
+	if err != nil {
+		return nil, err
+	}
+
+	mysqlExpectedConfig, err := c.cobraCmd.Flags().GetStringToString(flagMySQLExpectedConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	postgresqlExpectedConfig, err := c.cobraCmd.Flags().GetStringToString(flagPostgreSQLExpectedConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	mysqlSecretKeys, postgresqlSecretKeys, smtpSecretKeys, err := c.secretKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, flag := range []struct {
+		name  string
+		value string
+	}{
+		{envVarGoogleCloudSDKDockerRepo, util.Flag(c.cobraCmd, flagGoogleCloudSDKDockerRepo)},
+		{envVarGoogleCloudSDKDockerImage, util.Flag(c.cobraCmd, flagGoogleCloudSDKDockerImage)},
+		{envVarStorageClassProvisioners, strings.Join(storageClassProvisioners, listSeparator)},
+		{envVarOIDCAudiences, strings.Join(oidcAudiences, listSeparator)},
+		{envVarMySQLSecret, util.Flag(c.cobraCmd, flagMySQLSecret)},
+		{envVarPostgreSQLSecret, util.Flag(c.cobraCmd, flagPostgreSQLSecret)},
+		{envVarSpiceDBDatabase, util.Flag(c.cobraCmd, flagSpiceDBDatabase)},
+		{envVarSMTPSecret, util.Flag(c.cobraCmd, flagSMTPSecret)},
+		{envVarMySQLSecretKeys, encodeKeyValues(mysqlSecretKeys)},
+		{envVarPostgreSQLSecretKeys, encodeKeyValues(postgresqlSecretKeys)},
+		{envVarSMTPSecretKeys, encodeKeyValues(smtpSecretKeys)},
+		{envVarSSOSecrets, strings.Join(ssoSecretNames, listSeparator)},
+		{envVarSSOSecretSelector, util.Flag(c.cobraCmd, flagSSOSecretSelector)},
+		{envVarSSOSAMLKeys, strings.Join(ssoSAMLKeys, listSeparator)},
+		{envVarCrossplaneNamespace, util.Flag(c.cobraCmd, flagCrossplaneNamespace)},
+		{envVarCrossplaneServiceAccountsPrefix, util.Flag(c.cobraCmd, flagCrossplaneServiceAccountsPrefix)},
+		{envVarAllowedExtraPolicyStatements, strings.Join(allowedExtraPolicyStatements, listSeparator)},
+		{envVarMySQLExpectedConfig, encodeKeyValues(mysqlExpectedConfig)},
+		{envVarPostgreSQLExpectedConfig, encodeKeyValues(postgresqlExpectedConfig)},
+	} {
+		if flag.value != constant.EmptyString {
+			envVars = append(envVars, corev1.EnvVar{
+				Name:  flag.name,
+				Value: flag.value,
+			})
+		}
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: c.objectMeta(constant.AppName, c.podNamespace()),
+		Spec: corev1.PodSpec{
+			ServiceAccountName: serviceAccountName,
+			Containers: []corev1.Container{{
+				Name:            constant.AppName,
+				Image:           c.podImage(),
+				Env:             envVars,
+				ImagePullPolicy: imagePullPolicy,
+			}},
+			RestartPolicy: corev1.RestartPolicyNever,
+		},
 	}
 
 	imagePullSecretName := util.Flag(c.cobraCmd, flagImagePullSecret)
@@ -352,17 +1326,40 @@ func (c *checkCmd) createPod(ctx context.Context, serviceAccountName string) err
 		}}
 	}
 
+	if err := kubeutil.ApplyPodSecurityProfile(&pod.Spec, podSecurityProfile); err != nil {
+		return nil, err
+	}
+
+	return pod, nil
+}
+
+// createPod creates the pod.
+func (c *checkCmd) createPod(ctx context.Context, serviceAccountName string) error {
+	podSecurityChecker := podsecuritychecker.New(handler.CheckContext{Logger: c.logger, Clientset: c.clientset}, c.podNamespace())
+
+	podSecurityProfile, err := util.UnwrapValErr[string](podSecurityChecker.Handle(ctx, util.Flag(c.cobraCmd, flagPodSecurityProfile)))
+	if err != nil {
+		return err
+	}
+
+	pod, err := c.buildPod(serviceAccountName, podSecurityProfile)
+	if err != nil {
+		return err
+	}
+
 	if _, err = c.clientsetPod.Create(ctx, pod, metav1.CreateOptions{}); err != nil {
 		return multierr.Combine(errFailedToCreatePod, err)
 	}
 
-	c.logger.Debugf(constant.LogMsgPodCreated, namespaceDefault, constant.AppName)
+	c.logger.Debugf(constant.LogMsgPodCreated, c.podNamespace(), constant.AppName)
 
 	return nil
 }
 
-// printPodLogs prints the pod logs.
-func (c *checkCmd) printPodLogs(logs []string) error {
+// printPodLogs prints the pod logs, handling the messages of the pod protocol among them and keeping the results of the checks if the pod wrote them.
+//
+// It returns whether the pod logged a fatal error.
+func (c *checkCmd) printPodLogs(logs []string) (bool, error) {
 	// logMsgPrintingPodLogs is the message that is logged when the pod logs are printed.
 	const logMsgPrintingPodLogs = "printing Pod logs..."
 
@@ -376,58 +1373,426 @@ func (c *checkCmd) printPodLogs(logs []string) error {
 		Level string `json:"level"`
 		// Message is the Message of the log entry.
 		Message string `json:"msg"`
+		// Version is the Version of the pod, only set in the log entry that carries it, by the pods older than the pod protocol.
+		Version string `json:"version,omitempty"`
+		// Results is the Results of the checks, only set in the log entry that carries them, by the pods older than the pod protocol.
+		Results []report.Result `json:"results,omitempty"`
 	}
 
-	var shouldExitOne bool
+	var fatal bool
 
 	for _, logStr := range logs {
+		m, ok, err := podprotocol.Parse([]byte(logStr))
+		if err != nil {
+			return false, err
+		}
+
+		if ok {
+			c.printPodMessage(m)
+
+			continue
+		}
+
 		var e logEntry
 
 		if err := json.Unmarshal([]byte(logStr), &e); err != nil {
-			return err
+			return false, err
 		}
 
 		if e.Level == constant.EmptyString || e.Message == constant.EmptyString || e.Timestamp == constant.EmptyString {
 			continue
 		}
 
+		if e.Message == logMsgPodVersion {
+			c.checkPodVersion(e.Version)
+
+			continue
+		}
+
+		if e.Message == logMsgPodResults {
+			c.podResults = e.Results
+
+			for _, r := range e.Results {
+				c.streamResult(r)
+			}
+
+			continue
+		}
+
 		level, err := log.ParseLevel(e.Level)
 		if err != nil {
-			return err
+			return false, err
 		}
 
 		parsedTime, err := time.Parse(log.DefaultTimeFormat, e.Timestamp)
 		if err != nil {
-			return err
+			return false, err
 		}
 
 		c.logger.SetTimeFunction(func(_ time.Time) time.Time { return parsedTime })
 
 		c.logger.Log(level, e.Message)
 
+		if level == log.WarnLevel {
+			c.warnings++
+		}
+
 		if level == log.FatalLevel {
-			shouldExitOne = true
+			fatal = true
 		}
 	}
 
 	// Reset the time function to the default one, converting to UTC.
 	c.logger.SetTimeFunction(constant.LogDefaultTimeFunc)
 
-	if shouldExitOne {
-		os.Exit(1)
+	return fatal, nil
+}
+
+// checkPodVersion is the function that warns if the version of the Pod does not match the one of the CLI.
+func (c *checkCmd) checkPodVersion(version string) {
+	const (
+		// logMsgPodVersionMatches is the message that is logged when the pod version matches the CLI version.
+		logMsgPodVersionMatches = "Pod version %s matches CLI version"
+
+		// logMsgPodVersionMismatch is the message that is logged when the pod version does not match the CLI version.
+		logMsgPodVersionMismatch = "Pod version %s does not match CLI version %s; check that the --%s flag points to the image matching the CLI version"
+	)
+
+	// The version skew between the CLI and the pod image is only a warning, as the checks may still be compatible.
+	if version == constant.BuildVersion {
+		c.logger.Debugf(logMsgPodVersionMatches, version)
+
+		return
+	}
+
+	c.warnf(logMsgPodVersionMismatch, version, constant.BuildVersion, flagDockerImage)
+}
+
+// printPodMessage is the function that handles the message of the pod protocol that the Pod wrote, logging it at the time it was written.
+//
+// The messages of the types that the CLI does not know are ignored, as the Pod of a newer schema version may write them.
+func (c *checkCmd) printPodMessage(m podprotocol.PodMessage) {
+	const (
+		// logMsgPodSchemaMismatch is the message that is logged when the schema version of the messages of the Pod does not match the one of the CLI.
+		logMsgPodSchemaMismatch = "Pod protocol schema version %d does not match CLI protocol schema version %d, so the results of the Pod may be misread; " +
+			"check that the --%s flag points to the image matching the CLI version"
+
+		// logMsgPodCheckStarted is the message that is logged when the Pod starts a check.
+		logMsgPodCheckStarted = "Pod started %s check"
+
+		// logMsgPodSummary is the message that is logged with the summary of the results of the checks of the Pod.
+		logMsgPodSummary = "Pod checks: %d passed, %d passed with warnings, %d failed, %d skipped"
+
+		// logMsgPodCheckSkipped is the message that is logged for each of the checks that the Pod did not run, along with the summary.
+		logMsgPodCheckSkipped = "Pod skipped %s check (%s): %s"
+
+		// logMsgUnknownPodMessage is the message that is logged when the type of the message of the Pod is not known.
+		logMsgUnknownPodMessage = "ignoring Pod message of unknown type %q"
+	)
+
+	c.logger.SetTimeFunction(func(_ time.Time) time.Time { return m.Time })
+
+	if m.Schema != podprotocol.SchemaVersion && !c.podSchemaSkewed {
+		c.podSchemaSkewed = true
+
+		c.warnf(logMsgPodSchemaMismatch, m.Schema, podprotocol.SchemaVersion, flagDockerImage)
+	}
+
+	switch m.Type {
+	case podprotocol.TypeVersion:
+		c.checkPodVersion(m.Version)
+	case podprotocol.TypeCheckStart:
+		c.logger.Debugf(logMsgPodCheckStarted, m.Check)
+	case podprotocol.TypeCheckResult:
+		if m.Result != nil {
+			c.podResults = append(c.podResults, *m.Result)
+
+			c.streamResult(*m.Result)
+		}
+	case podprotocol.TypeSummary:
+		if m.Summary != nil {
+			c.logger.Infof(logMsgPodSummary, m.Summary.Passed, m.Summary.Warning, m.Summary.Failed, m.Summary.Skipped)
+
+			for _, s := range report.Skipped(c.podResults) {
+				c.logger.Infof(logMsgPodCheckSkipped, s.Check, s.Reason, s.Message)
+			}
+		}
+	default:
+		c.logger.Debugf(logMsgUnknownPodMessage, m.Type)
+	}
+}
+
+// streamResult is the function that prints the result of the check as soon as it is reported, if the flags ask for it, logging the error of printing it.
+func (c *checkCmd) streamResult(r report.Result) {
+	if c.resultsStream == nil {
+		return
+	}
+
+	if err := c.resultsStream.Write(r); err != nil {
+		c.logger.Error(err)
+	}
+}
+
+// parseResultsConfigMap parses the reference to the ConfigMap that the results of the check are written to, in the namespace/name format.
+//
+// It returns the namespace and the name of the ConfigMap, or an error if the reference is not valid.
+func parseResultsConfigMap(ref string) (string, string, error) {
+	namespace, name, ok := strings.Cut(ref, "/")
+	if !ok || len(validation.IsDNS1123Label(namespace)) > 0 || len(validation.IsDNS1123Subdomain(name)) > 0 {
+		return constant.EmptyString, constant.EmptyString, fmt.Errorf("%w: %q", errInvalidResultsConfigMap, ref)
+	}
+
+	return namespace, name, nil
+}
+
+// status is the function that returns the overall status of the check from whether it failed and from whether it logged warnings.
+func (c *checkCmd) status(failed bool) report.Status {
+	switch {
+	case failed:
+		return report.StatusFailed
+	case c.warnings > 0:
+		return report.StatusWarning
+	default:
+		return report.StatusPassed
+	}
+}
+
+// results is the function that returns the results of the checks that the Pod logged, or an empty list if it logged none, e.g. as it is of an older
+// version.
+func (c *checkCmd) results() []report.Result {
+	if c.podResults == nil {
+		return []report.Result{}
+	}
+
+	return c.podResults
+}
+
+// reportsResults is the function that returns whether the results of the checks are reported, to the ConfigMap, to the standard output or to the metrics
+// file, so that the Pod is asked to write them.
+func (c *checkCmd) reportsResults() bool {
+	return util.Flag(c.cobraCmd, flagWriteResultsConfigMap) != constant.EmptyString || util.FlagBool(c.cobraCmd, flagPrintResults) ||
+		util.FlagBool(c.cobraCmd, flagStreamResults) || util.Flag(c.cobraCmd, flagMetricsFile) != constant.EmptyString
+}
+
+// resultsFailed is the function that returns whether any of the results of the checks failed.
+func resultsFailed(results []report.Result) bool {
+	return slices.ContainsFunc(results, func(r report.Result) bool { return r.Status == report.StatusFailed })
+}
+
+// printedResults is the type of the JSON object that the results of the check are printed as.
+type printedResults struct {
+	// Status is the overall status of the check.
+	Status report.Status `json:"status"`
+	// Version is the version of the CLI.
+	Version string `json:"version"`
+	// RunID is the identifier of the run.
+	RunID string `json:"runID"`
+	// Cloud is the cloud provider of the environment configuration, which the coverage of the requirements is reported for.
+	Cloud cloud.Cloud `json:"cloud,omitempty"`
+	// Context is the context of the Kubernetes configuration whose cluster was checked, empty for the current one.
+	Context string `json:"context,omitempty"`
+	// Results is the results of the checks, including the skipped ones.
+	Results []report.Result `json:"results"`
+	// Summary is the number of the results of each status.
+	Summary report.Summary `json:"summary"`
+	// Skipped is the list of the checks that were not run, along with the reason why, so that the coverage of the check is explicit.
+	Skipped []report.SkippedCheck `json:"skipped"`
+}
+
+// printResults prints the results of the checks that the Pod logged to the writer as a single JSON object, along with the overall status, so that the
+// pipelines do not have to parse the logs.
+func (c *checkCmd) printResults(w io.Writer, failed bool) error {
+	results := c.results()
+
+	if err := json.NewEncoder(w).Encode(printedResults{
+		Status:  c.status(failed),
+		Version: constant.BuildVersion,
+		RunID:   c.runID,
+		Cloud:   cloud.Cloud(c.envConfig.Spec.CloudSpec.Provider),
+		Context: c.kubeContext,
+		Results: results,
+		Summary: report.Summarize(results),
+		Skipped: report.Skipped(results),
+	}); err != nil {
+		return multierr.Combine(errFailedToPrintResults, err)
 	}
 
 	return nil
 }
 
+// writeResultsConfigMap writes the results of the checks that the Pod logged to the ConfigMap, along with the overall status, the times at which the run
+// started and finished and the version of the CLI, overwriting the ones of the previous run.
+//
+// The ConfigMap is written with the permissions of the command, not of the Pod, and its namespace must exist.
+func (c *checkCmd) writeResultsConfigMap(ctx context.Context, namespace string, name string, failed bool) error {
+	const (
+		// logMsgResultsConfigMapWritten is the message that is logged when the results are written to the ConfigMap.
+		logMsgResultsConfigMapWritten = "wrote results to %s/%s ConfigMap"
+
+		// keyStatus is the key of the overall status of the check in the data of the ConfigMap.
+		keyStatus = "status"
+
+		// keyVersion is the key of the version of the CLI in the data of the ConfigMap.
+		keyVersion = "version"
+
+		// keyRunID is the key of the identifier of the run in the data of the ConfigMap.
+		keyRunID = "runID"
+
+		// keyStartedAt is the key of the time at which the run started in the data of the ConfigMap.
+		keyStartedAt = "startedAt"
+
+		// keyFinishedAt is the key of the time at which the run finished in the data of the ConfigMap.
+		keyFinishedAt = "finishedAt"
+
+		// keyResults is the key of the results of the checks, as a JSON array, in the data of the ConfigMap.
+		keyResults = "results.json"
+
+		// keySummary is the key of the number of the results of each status, as a JSON object, in the data of the ConfigMap.
+		keySummary = "summary.json"
+	)
+
+	results := c.results()
+
+	resultsJSON, err := json.Marshal(results)
+	if err != nil {
+		return multierr.Combine(errFailedToWriteResultsConfigMap, err)
+	}
+
+	summaryJSON, err := json.Marshal(report.Summarize(results))
+	if err != nil {
+		return multierr.Combine(errFailedToWriteResultsConfigMap, err)
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: c.objectMeta(name, namespace),
+		Data: map[string]string{
+			keyStatus:     string(c.status(failed)),
+			keyVersion:    constant.BuildVersion,
+			keyRunID:      c.runID,
+			keyStartedAt:  c.startedAt.UTC().Format(time.RFC3339),
+			keyFinishedAt: c.clock.Now().UTC().Format(time.RFC3339),
+			keyResults:    string(resultsJSON),
+			keySummary:    string(summaryJSON),
+		},
+	}
+
+	configMaps := c.clientset.CoreV1().ConfigMaps(namespace)
+
+	_, err = configMaps.Create(ctx, configMap, metav1.CreateOptions{})
+	if k8serrors.IsAlreadyExists(err) {
+		_, err = configMaps.Update(ctx, configMap, metav1.UpdateOptions{})
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w %s/%s: %w", errFailedToWriteResultsConfigMap, namespace, name, err)
+	}
+
+	c.logger.Infof(logMsgResultsConfigMapWritten, namespace, name)
+
+	return nil
+}
+
+// writeMetricsFile writes the results of the checks that the Pod logged to the file in the OpenMetrics text format, along with the overall status and the
+// time at which the run finished, overwriting the ones of the previous run.
+//
+// The file is written to a temporary file in the same directory first and renamed over the previous one, so that the textfile collector of node_exporter
+// never reads a partially written file.
+func (c *checkCmd) writeMetricsFile(path string, failed bool) error {
+	const (
+		// logMsgMetricsFileWritten is the message that is logged when the results are written to the metrics file.
+		logMsgMetricsFileWritten = "wrote results to %s metrics file"
+
+		// metricsFileMode is the mode of the metrics file, readable by the collectors that run as other users.
+		metricsFileMode = 0o644
+	)
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return multierr.Combine(errFailedToWriteMetricsFile, err)
+	}
+
+	// The temporary file is only left once it is renamed, so removing it afterwards fails harmlessly.
+	defer os.Remove(tmp.Name()) // nolint:errcheck
+
+	err = multierr.Combine(
+		report.WriteOpenMetrics(tmp, c.results(), c.status(failed), c.clock.Now()),
+		tmp.Chmod(metricsFileMode),
+		tmp.Close(),
+	)
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w %s: %w", errFailedToWriteMetricsFile, path, err)
+	}
+
+	c.logger.Infof(logMsgMetricsFileWritten, path)
+
+	return nil
+}
+
+// warnf is the function that logs the warning, counting it towards the exit status of the check.
+func (c *checkCmd) warnf(format string, args ...any) {
+	c.warnings++
+
+	c.logger.Warnf(format, args...)
+}
+
+// exitCode is the function that returns the exit status of the check from whether it failed and from the number of the warnings it logged, which fail it
+// if they are treated as errors.
+func exitCode(failed bool, warnings int, warningsAsErrors bool) int {
+	switch {
+	case failed, warnings > 0 && warningsAsErrors:
+		return exitCodeFailed
+	case warnings > 0:
+		return exitCodePassedWithWarnings
+	default:
+		return exitCodePassed
+	}
+}
+
+// commandContext returns the context of the Cobra command, which the Install command sets to the one that is cancelled on SIGINT or SIGTERM, or the
+// background one if it has none.
+func commandContext(cobraCmd *cobra.Command) context.Context {
+	if ctx := cobraCmd.Context(); ctx != nil {
+		return ctx
+	}
+
+	return context.Background()
+}
+
+// withOptionalTimeout returns a child of the context that is done once the timeout elapses, or one that is only cancelled if the timeout is zero.
+func withOptionalTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout == 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
+// timedOut returns the error combined with the one of the check timing out if the deadline of the context is exceeded, so that the timeout is named rather
+// than only the failure of the request that it interrupted.
+func (c *checkCmd) timedOut(ctx context.Context, err error) error {
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return err
+	}
+
+	return multierr.Combine(fmt.Errorf("%w after %s", errCheckTimedOut, util.FlagDuration(c.cobraCmd, flagTimeout)), err)
+}
+
 // cleanupResources cleans up the resources.
 //
-// nolint:funlen
+// If the run ID is not empty, the resources that carry the label of another run are left as is, so that the cleanup of the resources left behind by a
+// previous run, or of the ones of this run, does not delete the ones of a run that is still going on.
+//
+// nolint:funlen,gocognit
 func (c *checkCmd) cleanupResources(
 	ctx context.Context,
 	roleBindingName string,
 	roleName string,
 	serviceAccountName string,
+	runID string,
 	allowNotFound bool,
 	shouldExitOne bool,
 ) (*corev1.Pod, error) {
@@ -438,132 +1803,430 @@ func (c *checkCmd) cleanupResources(
 		// logMsgClusterRoleDeleted is the message that is logged when the cluster role is deleted.
 		logMsgClusterRoleDeleted = "deleted %s ClusterRole"
 
-		// logMsgRoleBindingDeleted is the message that is logged when the role binding is deleted.
-		logMsgRoleBindingDeleted = "deleted %s/%s RoleBinding"
+		// logMsgRoleBindingDeleted is the message that is logged when the role binding is deleted.
+		logMsgRoleBindingDeleted = "deleted %s/%s RoleBinding"
+
+		// logMsgRoleDeleted is the message that is logged when the role is deleted.
+		logMsgRoleDeleted = "deleted %s/%s Role"
+
+		// logMsgServiceAccountDeleted is the message that is logged when the service account is deleted.
+		logMsgServiceAccountDeleted = "deleted %s/%s ServiceAccount"
+
+		// logMsgSecretDeleted is the message that is logged when the secret is deleted.
+		logMsgSecretDeleted = "deleted %s/%s Secret"
+
+		// logMsgOtherRunResourceKept is the message that is logged when a resource of another run is left as is.
+		logMsgOtherRunResourceKept = "left %s as is, as it belongs to the run %q"
+	)
+
+	// otherRun returns whether the resource exists and carries the label of another run than the one of the run ID.
+	otherRun := func(obj metav1.Object, err error) bool {
+		return err == nil && obj.GetLabels()[constant.LabelRunID] != runID
+	}
+
+	// keep returns whether the resource is left as is, as it belongs to another run, logging it if so; the resource is only retrieved if the run ID is set.
+	keep := func(name string, get func() (metav1.Object, error)) bool {
+		if runID == constant.EmptyString {
+			return false
+		}
+
+		obj, err := get()
+		if !otherRun(obj, err) {
+			return false
+		}
+
+		c.logger.Debugf(logMsgOtherRunResourceKept, name, obj.GetLabels()[constant.LabelRunID])
+
+		return true
+	}
+
+	rbac := c.clientset.RbacV1()
+
+	pod, err := c.clientsetPod.Get(ctx, constant.AppName, metav1.GetOptions{})
+	if err != nil && (!allowNotFound && !k8serrors.IsNotFound(err)) {
+		return nil, multierr.Combine(kubeutil.ErrFailedToGetPod, err)
+	}
+
+	if runID != constant.EmptyString && otherRun(pod, err) {
+		c.logger.Debugf(logMsgOtherRunResourceKept, fmt.Sprintf("%s/%s Pod", c.podNamespace(), constant.AppName), pod.Labels[constant.LabelRunID])
+
+		pod = nil
+	} else {
+		if err = c.clientsetPod.Delete(ctx, constant.AppName, metav1.DeleteOptions{}); err != nil && !allowNotFound && !k8serrors.IsNotFound(err) {
+			return pod, multierr.Combine(errFailedToDeletePod, err)
+		}
+
+		c.logger.Debugf(constant.LogMsgPodDeleted, c.podNamespace(), constant.AppName)
+	}
+
+	if !keep(fmt.Sprintf("%s/%s Secret", c.podNamespace(), podProxySecretName), func() (metav1.Object, error) {
+		return c.clientsetSecret.Get(ctx, podProxySecretName, metav1.GetOptions{})
+	}) {
+		// The secret only exists when the flag of the HTTPS proxy is set, so it is not an error for it to be missing.
+		if err = c.clientsetSecret.Delete(ctx, podProxySecretName, metav1.DeleteOptions{}); err != nil && !k8serrors.IsNotFound(err) {
+			return pod, multierr.Combine(errFailedToDeleteSecret, err)
+		}
+
+		if err == nil {
+			c.logger.Debugf(logMsgSecretDeleted, c.podNamespace(), podProxySecretName)
+		}
+	}
+
+	if !keep(roleBindingName+" ClusterRoleBinding", func() (metav1.Object, error) {
+		return rbac.ClusterRoleBindings().Get(ctx, roleBindingName, metav1.GetOptions{})
+	}) {
+		if err = rbac.ClusterRoleBindings().Delete(ctx, roleBindingName, metav1.DeleteOptions{}); err != nil && !allowNotFound && !k8serrors.IsNotFound(err) {
+			return pod, multierr.Combine(errFailedToDeleteRoleBinding, err)
+		}
+
+		c.logger.Debugf(logMsgClusterRoleBindingDeleted, roleBindingName)
+	}
+
+	// The existing cluster role that is used as is was not created by the run, so it is left as is.
+	if !c.keepClusterRole && !keep(roleName+" ClusterRole", func() (metav1.Object, error) {
+		return rbac.ClusterRoles().Get(ctx, roleName, metav1.GetOptions{})
+	}) {
+		if err = rbac.ClusterRoles().Delete(ctx, roleName, metav1.DeleteOptions{}); err != nil && !allowNotFound && !k8serrors.IsNotFound(err) {
+			return pod, multierr.Combine(errFailedToDeleteRole, err)
+		}
+
+		c.logger.Debugf(logMsgClusterRoleDeleted, roleName)
+	}
+
+	for _, ns := range c.roleNamespaces() {
+		if !keep(fmt.Sprintf("%s/%s RoleBinding", ns, roleBindingName), func() (metav1.Object, error) {
+			return rbac.RoleBindings(ns).Get(ctx, roleBindingName, metav1.GetOptions{})
+		}) {
+			if err = rbac.RoleBindings(ns).Delete(ctx, roleBindingName, metav1.DeleteOptions{}); err != nil && !allowNotFound && !k8serrors.IsNotFound(err) {
+				return pod, multierr.Combine(errFailedToDeleteRoleBinding, err)
+			}
+
+			c.logger.Debugf(logMsgRoleBindingDeleted, ns, roleBindingName)
+		}
+
+		if !keep(fmt.Sprintf("%s/%s Role", ns, roleName), func() (metav1.Object, error) {
+			return rbac.Roles(ns).Get(ctx, roleName, metav1.GetOptions{})
+		}) {
+			if err = rbac.Roles(ns).Delete(ctx, roleName, metav1.DeleteOptions{}); err != nil && !allowNotFound && !k8serrors.IsNotFound(err) {
+				return pod, multierr.Combine(errFailedToDeleteRole, err)
+			}
+
+			c.logger.Debugf(logMsgRoleDeleted, ns, roleName)
+		}
+	}
+
+	if !keep(fmt.Sprintf("%s/%s ServiceAccount", c.podNamespace(), serviceAccountName), func() (metav1.Object, error) {
+		return c.clientsetSA.Get(ctx, serviceAccountName, metav1.GetOptions{})
+	}) {
+		if err = c.clientsetSA.Delete(ctx, serviceAccountName, metav1.DeleteOptions{}); err != nil && !allowNotFound && !k8serrors.IsNotFound(err) {
+			return pod, multierr.Combine(errFailedToDeleteServiceAccount, err)
+		}
+
+		c.logger.Debugf(logMsgServiceAccountDeleted, c.podNamespace(), serviceAccountName)
+	}
+
+	if shouldExitOne && pod != nil && !allowNotFound && pod.Status.Phase == corev1.PodFailed {
+		os.Exit(1)
+	}
+
+	return pod, nil
+}
+
+// cleanupVerificationTimeout is the maximum duration that the resources are waited for to be gone once they are cleaned up.
+const cleanupVerificationTimeout = 30 * time.Second
+
+// cleanedUpResource is the type that describes a resource that the cleanup deletes, for verifying that it is gone.
+type cleanedUpResource struct {
+	// name is the name of the resource, as logged when it is deleted.
+	name string
+	// get is the function that gets the resource.
+	get func(ctx context.Context) (metav1.Object, error)
+}
+
+// cleanedUpResources is the function that returns the resources that the cleanup deletes, in the order they are deleted.
+func (c *checkCmd) cleanedUpResources(roleBindingName string, roleName string, serviceAccountName string) []cleanedUpResource {
+	rbac := c.clientset.RbacV1()
+
+	resources := []cleanedUpResource{
+		{
+			name: fmt.Sprintf("%s/%s Pod", c.podNamespace(), constant.AppName),
+			get: func(ctx context.Context) (metav1.Object, error) {
+				return c.clientsetPod.Get(ctx, constant.AppName, metav1.GetOptions{})
+			},
+		},
+		{
+			name: fmt.Sprintf("%s/%s Secret", c.podNamespace(), podProxySecretName),
+			get: func(ctx context.Context) (metav1.Object, error) {
+				return c.clientsetSecret.Get(ctx, podProxySecretName, metav1.GetOptions{})
+			},
+		},
+		{
+			name: fmt.Sprintf("%s ClusterRoleBinding", roleBindingName),
+			get: func(ctx context.Context) (metav1.Object, error) {
+				return rbac.ClusterRoleBindings().Get(ctx, roleBindingName, metav1.GetOptions{})
+			},
+		},
+	}
+
+	if !c.keepClusterRole {
+		resources = append(resources, cleanedUpResource{
+			name: fmt.Sprintf("%s ClusterRole", roleName),
+			get: func(ctx context.Context) (metav1.Object, error) {
+				return rbac.ClusterRoles().Get(ctx, roleName, metav1.GetOptions{})
+			},
+		})
+	}
+
+	for _, ns := range c.roleNamespaces() {
+		resources = append(resources,
+			cleanedUpResource{
+				name: fmt.Sprintf("%s/%s RoleBinding", ns, roleBindingName),
+				get: func(ctx context.Context) (metav1.Object, error) {
+					return rbac.RoleBindings(ns).Get(ctx, roleBindingName, metav1.GetOptions{})
+				},
+			},
+			cleanedUpResource{
+				name: fmt.Sprintf("%s/%s Role", ns, roleName),
+				get: func(ctx context.Context) (metav1.Object, error) {
+					return rbac.Roles(ns).Get(ctx, roleName, metav1.GetOptions{})
+				},
+			},
+		)
+	}
+
+	return append(resources, cleanedUpResource{
+		name: fmt.Sprintf("%s/%s ServiceAccount", c.podNamespace(), serviceAccountName),
+		get: func(ctx context.Context) (metav1.Object, error) {
+			return c.clientsetSA.Get(ctx, serviceAccountName, metav1.GetOptions{})
+		},
+	})
+}
+
+// verifyCleanup is the function that waits for the resources that the cleanup deleted to be gone, polling them every second on the clock, as their
+// deletion is asynchronous, and warns about each of the ones that linger once the cleanup verification timeout elapses, along with their finalizers.
+//
+// It returns an error if the resources cannot be got, or the error of the context if it is done before they are gone.
+func (c *checkCmd) verifyCleanup(ctx context.Context, roleBindingName string, roleName string, serviceAccountName string) error {
+	const (
+		// logMsgCleanupVerified is the message that is logged when the resources are verified to be gone.
+		logMsgCleanupVerified = "verified that the resources are gone after cleanup"
+
+		// logMsgResourceLingers is the message that is logged when a resource is not gone once the cleanup verification timeout elapses.
+		logMsgResourceLingers = "%s still exists %s after cleanup%s; delete it manually or run the cleanup command"
+
+		// logMsgFinalizers is the message that describes the finalizers of a lingering resource.
+		logMsgFinalizers = ", held by its finalizers %s"
+	)
+
+	resources := c.cleanedUpResources(roleBindingName, roleName, serviceAccountName)
+
+	deadline := c.clock.Now().Add(cleanupVerificationTimeout)
+
+	for {
+		var lingering []metav1.Object
+
+		var names []string
+
+		for _, resource := range resources {
+			obj, err := resource.get(ctx)
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+
+			if err != nil {
+				return fmt.Errorf("%w of %s: %w", errFailedToVerifyCleanup, resource.name, err)
+			}
+
+			// The resources of another run are left as is by the cleanup.
+			if obj.GetLabels()[constant.LabelRunID] != c.runID {
+				continue
+			}
+
+			lingering, names = append(lingering, obj), append(names, resource.name)
+		}
+
+		if len(lingering) == 0 {
+			c.logger.Debug(logMsgCleanupVerified)
+
+			return nil
+		}
+
+		if !c.clock.Now().Before(deadline) {
+			for i, obj := range lingering {
+				var finalizers string
+
+				if len(obj.GetFinalizers()) > 0 {
+					finalizers = fmt.Sprintf(logMsgFinalizers, strings.Join(obj.GetFinalizers(), ", "))
+				}
+
+				c.warnf(logMsgResourceLingers, names[i], cleanupVerificationTimeout, finalizers)
+			}
+
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-c.clock.After(time.Second):
+		}
+	}
+}
+
+// run is the run function for the Check command.
+//
+// nolint:funlen
+func (c *checkCmd) run(cobraCmd *cobra.Command, args []string) {
+	const (
+		// logMsgEnvConfigRead is the message that is logged when the environment configuration is read from the specified path.
+		logMsgEnvConfigRead = "read environment configuration from %s"
+
+		// logMsgRunID is the message that is logged with the identifier of the run.
+		logMsgRunID = "run ID is %s"
+	)
+
+	firstStepFile := args[0]
+
+	c.runID = utilrand.String(runIDLength)
+	c.startedAt = c.clock.Now()
+	c.warnings = 0
+	c.podResults = nil
+	c.resultsStream = nil
 
-		// logMsgRoleDeleted is the message that is logged when the role is deleted.
-		logMsgRoleDeleted = "deleted %s/%s Role"
+	if util.FlagBool(c.cobraCmd, flagStreamResults) {
+		c.resultsStream = report.NewStreamWriter(c.cobraCmd.OutOrStdout())
+	}
 
-		// logMsgServiceAccountDeleted is the message that is logged when the service account is deleted.
-		logMsgServiceAccountDeleted = "deleted %s/%s ServiceAccount"
-	)
+	c.logger.Debugf(logMsgRunID, c.runID)
 
-	pod, err := c.clientsetPod.Get(ctx, constant.AppName, metav1.GetOptions{})
-	if err != nil && (!allowNotFound && !k8serrors.IsNotFound(err)) {
-		return nil, multierr.Combine(kubeutil.ErrFailedToGetPod, err)
+	// Validate the image pull policy, the pod security profile, the connect timeout, the maximum clock skew, the maximum concurrency, the timeout, the Google
+	// Cloud SDK image, the HTTPS proxy, the namespace prefix and the results ConfigMap before any resources are created in the cluster.
+	if _, err := c.imagePullPolicy(); err != nil {
+		c.logger.Fatal(err)
 	}
 
-	if err = c.clientsetPod.Delete(ctx, constant.AppName, metav1.DeleteOptions{}); err != nil && !allowNotFound && !k8serrors.IsNotFound(err) {
-		return pod, multierr.Combine(errFailedToDeletePod, err)
+	if err := kubeutil.ValidatePodSecurityProfile(util.Flag(cobraCmd, flagPodSecurityProfile)); err != nil {
+		c.logger.Fatal(err)
 	}
 
-	c.logger.Debugf(constant.LogMsgPodDeleted, namespaceDefault, constant.AppName)
+	if util.FlagDuration(cobraCmd, flagConnectTimeout) <= 0 {
+		c.logger.Fatal(errInvalidConnectTimeout)
+	}
 
-	if err = c.clientset.RbacV1().ClusterRoleBindings().Delete(
-		ctx,
-		roleBindingName,
-		metav1.DeleteOptions{},
-	); err != nil && !allowNotFound && !k8serrors.IsNotFound(err) {
-		return pod, multierr.Combine(errFailedToDeleteRoleBinding, err)
+	if util.FlagDuration(cobraCmd, flagMaxClockSkew) < 0 {
+		c.logger.Fatal(errInvalidMaxClockSkew)
 	}
 
-	c.logger.Debugf(logMsgClusterRoleBindingDeleted, roleBindingName)
+	if since := util.Flag(cobraCmd, flagSinceVersion); since != constant.EmptyString {
+		if err := envconfig.ValidateVersion(since); err != nil {
+			c.logger.Fatal(err)
+		}
+	}
 
-	if err = c.clientset.RbacV1().ClusterRoles().Delete(
-		ctx,
-		roleName,
-		metav1.DeleteOptions{},
-	); err != nil && !allowNotFound && !k8serrors.IsNotFound(err) {
-		return pod, multierr.Combine(errFailedToDeleteRole, err)
+	if util.FlagInt(cobraCmd, flagMaxConcurrency) <= 0 {
+		c.logger.Fatal(errInvalidMaxConcurrency)
 	}
 
-	c.logger.Debugf(logMsgClusterRoleDeleted, roleName)
+	mysqlExpectedConfig, err := cobraCmd.Flags().GetStringToString(flagMySQLExpectedConfig)
+	if err != nil {
+		c.logger.Fatal(err)
+	}
 
-	for _, ns := range constRoleNamespaces {
-		if err = c.clientset.RbacV1().RoleBindings(ns).Delete(
-			ctx,
-			roleBindingName,
-			metav1.DeleteOptions{},
-		); err != nil && !allowNotFound && !k8serrors.IsNotFound(err) {
-			return pod, multierr.Combine(errFailedToDeleteRoleBinding, err)
-		}
+	if err := mysqlchecker.ValidateExpectedConfigOverride(mysqlExpectedConfig); err != nil {
+		c.logger.Fatal(err)
+	}
 
-		c.logger.Debugf(logMsgRoleBindingDeleted, ns, roleBindingName)
+	postgresqlExpectedConfig, err := cobraCmd.Flags().GetStringToString(flagPostgreSQLExpectedConfig)
+	if err != nil {
+		c.logger.Fatal(err)
+	}
 
-		if err = c.clientset.RbacV1().Roles(ns).Delete(
-			ctx,
-			roleName,
-			metav1.DeleteOptions{},
-		); err != nil && !allowNotFound && !k8serrors.IsNotFound(err) {
-			return pod, multierr.Combine(errFailedToDeleteRole, err)
-		}
+	if err := postgresqlchecker.ValidateExpectedConfigOverride(postgresqlExpectedConfig); err != nil {
+		c.logger.Fatal(err)
+	}
 
-		c.logger.Debugf(logMsgRoleDeleted, ns, roleName)
+	if _, _, _, err := c.secretKeys(); err != nil {
+		c.logger.Fatal(err)
 	}
 
-	if err = c.clientsetSA.Delete(ctx, serviceAccountName, metav1.DeleteOptions{}); err != nil && !allowNotFound && !k8serrors.IsNotFound(err) {
-		return pod, multierr.Combine(errFailedToDeleteServiceAccount, err)
+	timeout := util.FlagDuration(cobraCmd, flagTimeout)
+	if timeout < 0 {
+		c.logger.Fatal(errInvalidTimeout)
 	}
 
-	c.logger.Debugf(logMsgServiceAccountDeleted, namespaceDefault, serviceAccountName)
+	if err := gcpcloudutil.ValidateImageRef(gcpcloudutil.ImageRef(
+		util.Flag(cobraCmd, flagGoogleCloudSDKDockerRepo),
+		util.Flag(cobraCmd, flagGoogleCloudSDKDockerImage),
+	)); err != nil {
+		c.logger.Fatal(err)
+	}
 
-	if shouldExitOne && pod != nil && !allowNotFound && pod.Status.Phase == corev1.PodFailed {
-		os.Exit(1)
+	if err := c.readFlags(); err != nil {
+		c.logger.Fatal(err)
 	}
 
-	return pod, nil
-}
+	if err := kubeutil.ValidateNamespacePrefix(util.Flag(cobraCmd, flagNamespacePrefix), constInstallNamespaces...); err != nil {
+		c.logger.Fatal(err)
+	}
 
-// run is the run function for the Check command.
-//
-// nolint:funlen
-func (c *checkCmd) run(cobraCmd *cobra.Command, args []string) {
-	const (
-		// logMsgInfraCheckStarted is the message that is logged when the infrastructure check starts.
-		logMsgInfraCheckStarted = "started infrastructure check"
+	if err := validatePodNamespace(c.podNamespace()); err != nil {
+		c.logger.Fatal(err)
+	}
 
-		// logMsgEnvConfigRead is the message that is logged when the environment configuration is read from the specified path.
-		logMsgEnvConfigRead = "read environment configuration from %s"
+	resultsConfigMapRef := util.Flag(cobraCmd, flagWriteResultsConfigMap)
 
-		// logMsgNamespaceEnsured is the message that is logged when the namespace is ensured.
-		logMsgNamespaceEnsured = "ensured %s Namespace"
-	)
+	var resultsConfigMapNamespace, resultsConfigMapName string
 
-	firstStepFile := args[0]
+	if resultsConfigMapRef != constant.EmptyString {
+		if resultsConfigMapNamespace, resultsConfigMapName, err = parseResultsConfigMap(resultsConfigMapRef); err != nil {
+			c.logger.Fatal(err)
+		}
+	}
 
-	c.logger.Debugf(logMsgEnvConfigRead, firstStepFile)
+	logProxyConfig(c.logger, c.proxyConfig)
 
-	var err error
+	c.logger.Debugf(logMsgEnvConfigRead, firstStepFile)
 
 	c.envConfig, err = envconfig.NewFromPath(firstStepFile)
 	if err != nil {
 		c.logger.Fatal(multierr.Combine(errFailedToReadEnvConfig, err))
 	}
 
+	// The flag is not defined for the Install command, which checks the cluster of the current context only. The logger of the check of each cluster
+	// carries the fields of its own environment configuration.
+	if kubeContexts, _ := cobraCmd.Flags().GetStringSlice(flagContexts); len(kubeContexts) > 0 {
+		c.runContexts(kubeContexts, timeout, resultsConfigMapNamespace, resultsConfigMapName)
+
+		return
+	}
+
+	c.logger = withEnvConfigFields(c.logger, c.envConfig)
+
 	var path string
 
-	c.kubeConfig, path, err = kubeutil.Config(util.Flag(cobraCmd, flagKubeConfig))
+	c.kubeConfig, path, err = kubeutil.Config(util.Flag(cobraCmd, flagKubeConfig), util.Flag(cobraCmd, flagKubeConfigData))
 	if err != nil {
 		c.logger.Fatal(multierr.Combine(errFailedToGetKubeConfig, err))
 	}
 
 	c.logger.Debugf(logMsgKubeLoadedConfig, path)
 
-	serviceAccountName := fmt.Sprintf("%s-sa", constant.AppName)
-
-	roleName := fmt.Sprintf("%s-role", constant.AppName)
-
-	roleBindingName := fmt.Sprintf("%s-rolebinding", constant.AppName)
+	if path != kubeutil.PathInCluster && path != kubeutil.PathData {
+		if kubeContext, err := kubeutil.CurrentContext(path); err == nil {
+			c.logger.Debugf(logMsgKubeCurrentContext, kubeContext)
+		}
+	}
 
-	ctx := context.Background()
+	ctx, cancel := withOptionalTimeout(commandContext(cobraCmd), timeout)
+	defer cancel()
 
 	if err = c.setupClientsets(); err != nil {
 		c.logger.Fatal(err)
 	}
 
 	if util.FlagBool(cobraCmd, flagCleanupOnly) {
-		if _, err = c.cleanupResources(ctx, roleBindingName, roleName, serviceAccountName, true, true); err != nil {
-			c.logger.Fatal(err)
+		if _, err = c.cleanupResources(ctx, podRoleBindingName, podRoleName, podServiceAccountName, constant.EmptyString, true, true); err != nil {
+			c.logger.Fatal(c.timedOut(ctx, err))
 		}
 
 		return
@@ -571,73 +2234,222 @@ func (c *checkCmd) run(cobraCmd *cobra.Command, args []string) {
 
 	c.logger.Debug(logMsgKubeClientsetCreated)
 
-	if _, err := c.clientsetNamespace.Create(ctx, &corev1.Namespace{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: constant.NamespaceCrossplane,
-		},
-	}, metav1.CreateOptions{}); err != nil && !k8serrors.IsAlreadyExists(err) {
-		c.logger.Fatal(multierr.Combine(errFailedToEnsureNamespace, err))
+	fatal, failed, err := c.runCluster(ctx)
+	if err != nil {
+		c.logger.Fatal(err)
 	}
 
-	c.logger.Debugf(logMsgNamespaceEnsured, constant.NamespaceCrossplane)
+	c.finish(ctx, fatal, failed, resultsConfigMapNamespace, resultsConfigMapName)
+}
 
-	if err = c.createServiceAccount(ctx, serviceAccountName); err != nil {
-		c.logger.Fatal(err)
+// runCluster checks the cluster of the clientsets, running the checks in the Pod, or from the command if the flags ask for it, and cleaning up the
+// resources it created.
+//
+// It returns whether the check failed fatally and whether it failed, or an error if it could not finish, combined with the one of the timeout if the
+// deadline of the context is exceeded.
+func (c *checkCmd) runCluster(ctx context.Context) (bool, bool, error) {
+	const (
+		// logMsgInfraCheckStarted is the message that is logged when the infrastructure check starts.
+		logMsgInfraCheckStarted = "started infrastructure check"
+	)
+
+	c.checkVersions()
+
+	if util.FlagBool(c.cobraCmd, flagFix) {
+		options, err := c.localCheckOptions()
+		if err != nil {
+			return false, false, err
+		}
+
+		fixers, err := c.fixers(options)
+		if err != nil {
+			return false, false, err
+		}
+
+		if err = c.runFixes(ctx, fixers); err != nil {
+			return false, false, c.timedOut(ctx, err)
+		}
 	}
 
-	if err = c.createRoles(ctx, roleName); err != nil {
-		c.logger.Fatal(err)
+	if util.FlagBool(c.cobraCmd, flagLocal) {
+		localFatal, err := c.runLocal(ctx)
+		if err != nil {
+			return false, false, c.timedOut(ctx, err)
+		}
+
+		return localFatal, localFatal || resultsFailed(c.podResults), nil
 	}
 
-	if err = c.createRoleBindings(ctx, serviceAccountName, roleBindingName, roleName); err != nil {
-		c.logger.Fatal(err)
+	if util.FlagBool(c.cobraCmd, flagCheckImage) {
+		if err := c.checkPodImage(ctx); err != nil {
+			return false, false, c.timedOut(ctx, err)
+		}
 	}
 
-	if err = c.createPod(ctx, serviceAccountName); err != nil {
-		c.logger.Fatal(err)
+	if err := c.ensureNamespaces(ctx); err != nil {
+		return false, false, c.timedOut(ctx, err)
+	}
+
+	leftBehind, err := c.checkClusterRoleConflict(ctx, podRoleName)
+	if err != nil {
+		return false, false, c.timedOut(ctx, err)
+	}
+
+	if leftBehind != nil {
+		runID := leftBehind.Labels[constant.LabelRunID]
+
+		if _, err := c.cleanupResources(ctx, podRoleBindingName, podRoleName, podServiceAccountName, runID, true, false); err != nil {
+			return false, false, c.timedOut(ctx, err)
+		}
+	}
+
+	// The resources are cleaned up even once the deadline is exceeded, so the cleanup does not inherit it.
+	cleanupCtx := context.WithoutCancel(ctx)
+
+	// fail returns the error; if the deadline is exceeded, it names the timeout and cleans up the resources created so far first, as they would otherwise be
+	// left behind. It is only used once the cluster role is known not to exist yet or to be kept, so that the cleanup does not delete an existing one.
+	fail := func(err error) error {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			if _, cleanupErr := c.cleanupResources(cleanupCtx, podRoleBindingName, podRoleName, podServiceAccountName, c.runID, true, false); cleanupErr != nil {
+				err = multierr.Combine(err, cleanupErr)
+			}
+		}
+
+		return c.timedOut(ctx, err)
+	}
+
+	if err := c.createServiceAccount(ctx, podServiceAccountName); err != nil {
+		return false, false, fail(err)
+	}
+
+	if err := c.createRoles(ctx, podRoleName); err != nil {
+		return false, false, fail(err)
+	}
+
+	if err := c.createRoleBindings(ctx, podServiceAccountName, podRoleBindingName, podRoleName); err != nil {
+		return false, false, fail(err)
+	}
+
+	if err := c.verifyRoleBindings(ctx, podRoleBindingName); err != nil {
+		return false, false, fail(err)
+	}
+
+	if err := c.createProxySecret(ctx); err != nil {
+		return false, false, fail(err)
+	}
+
+	if err := c.createPod(ctx, podServiceAccountName); err != nil {
+		return false, false, fail(err)
 	}
 
 	c.logger.Info(logMsgInfraCheckStarted)
 
 	cleanup := func() (*corev1.Pod, error) {
-		if pod, err := c.cleanupResources(ctx, roleBindingName, roleName, serviceAccountName, false, false); err != nil {
+		if pod, err := c.cleanupResources(cleanupCtx, podRoleBindingName, podRoleName, podServiceAccountName, c.runID, false, false); err != nil {
 			return pod, err
 		}
 
 		return nil, nil
 	}
 
-	_, err = kubeutil.WaitForPodToSucceedOrFail(ctx, c.logger, c.clientset, namespaceDefault, constant.AppName)
+	_, err = kubeutil.WaitForPodToSucceedOrFail(ctx, c.logger, c.clock, c.clientset, c.podNamespace(), constant.AppName)
 	if err != nil {
 		if _, err := cleanup(); err != nil {
-			c.logger.Fatal(err)
+			return false, false, c.timedOut(ctx, err)
 		}
 
-		c.logger.Fatal(err)
+		return false, false, c.timedOut(ctx, err)
 	}
 
-	logs, err := kubeutil.PodLogs(ctx, c.logger, c.clientset, namespaceDefault, constant.AppName)
+	logs, err := kubeutil.PodLogs(ctx, c.logger, c.clientset, c.podNamespace(), constant.AppName)
 	if err != nil {
 		if _, err := cleanup(); err != nil {
-			c.logger.Fatal(err)
+			return false, false, c.timedOut(ctx, err)
 		}
 
-		c.logger.Fatal(err)
+		return false, false, c.timedOut(ctx, err)
+	}
+
+	pod, err := cleanup()
+	if err != nil {
+		return false, false, c.timedOut(ctx, err)
 	}
 
-	var pod *corev1.Pod
+	podFatal, err := c.printPodLogs(logs)
+	if err != nil {
+		return false, false, err
+	}
 
-	if pod, err = cleanup(); err != nil {
-		c.logger.Fatal(err)
+	if util.FlagBool(c.cobraCmd, flagVerifyCleanup) {
+		if err = c.verifyCleanup(cleanupCtx, podRoleBindingName, podRoleName, podServiceAccountName); err != nil {
+			return false, false, err
+		}
 	}
 
-	if err = c.printPodLogs(logs); err != nil {
-		c.logger.Fatal(err)
+	// The results that the Pod logged fail the check on their own, so that the exit status agrees with them.
+	podFailed := podFatal || pod != nil && pod.Status.Phase == corev1.PodFailed || resultsFailed(c.podResults)
+
+	return podFatal, podFailed, nil
+}
+
+// finish writes the results of the checks to the ConfigMap, prints them and writes them to the metrics file if the flags ask for it, and exits with the
+// status of the check.
+//
+// It returns only if the check passed, or passed with warnings and does not exit on them.
+func (c *checkCmd) finish(ctx context.Context, fatal bool, failed bool, resultsConfigMapNamespace string, resultsConfigMapName string) {
+	if resultsConfigMapName != constant.EmptyString {
+		if err := c.writeResultsConfigMap(ctx, resultsConfigMapNamespace, resultsConfigMapName, failed); err != nil {
+			c.logger.Fatal(c.timedOut(ctx, err))
+		}
+	}
+
+	if util.FlagBool(c.cobraCmd, flagPrintResults) {
+		if err := c.printResults(c.cobraCmd.OutOrStdout(), failed); err != nil {
+			c.logger.Fatal(err)
+		}
+	}
+
+	if c.resultsStream != nil {
+		if _, err := c.resultsStream.Close(); err != nil {
+			c.logger.Fatal(err)
+		}
+	}
+
+	if metricsFile := util.Flag(c.cobraCmd, flagMetricsFile); metricsFile != constant.EmptyString {
+		if err := c.writeMetricsFile(metricsFile, failed); err != nil {
+			c.logger.Fatal(err)
+		}
 	}
 
-	if pod != nil && pod.Status.Phase == corev1.PodFailed {
+	c.exit(fatal, failed)
+}
+
+// exit exits with the status of the check from whether it failed and from the warnings it logged.
+//
+// It returns only if the check passed, or passed with warnings and does not exit on them.
+func (c *checkCmd) exit(fatal bool, failed bool) {
+	const (
+		// logMsgPassedWithWarnings is the message that is logged when the check passed but logged warnings.
+		logMsgPassedWithWarnings = "infrastructure check passed with %d warning(s)"
+	)
+
+	if fatal {
 		os.Exit(1)
 	}
+
+	code := exitCode(failed, c.warnings, util.FlagBool(c.cobraCmd, flagWarningsAsErrors))
+
+	if code == exitCodePassedWithWarnings {
+		c.logger.Warnf(logMsgPassedWithWarnings, c.warnings)
+
+		if !c.exitOnWarnings {
+			return
+		}
+	}
+
+	if code != exitCodePassed {
+		os.Exit(code)
+	}
 }
 
 func (c *checkCmd) longMsg(msg string) string {
@@ -645,9 +2457,14 @@ func (c *checkCmd) longMsg(msg string) string {
 		`%s
 
 You may specify the Kubernetes configuration file to use by setting the --%s flag or by setting the KUBECONFIG environment variable.
+You may also pass the base64 encoded Kubernetes configuration by setting the --%s flag or the KUBECONFIG_DATA environment variable, which is not written
+to disk; the --%s flag takes precedence over the --%s flag, which takes precedence over the environment variables.
 If you do not specify the Kubernetes configuration file, the command will use the default Kubernetes configuration file located at your home directory.`,
 		msg,
 		flagKubeConfig,
+		flagKubeConfigData,
+		flagKubeConfigData,
+		flagKubeConfig,
 	)
 }
 
@@ -662,6 +2479,9 @@ func (c *checkCmd) flags(shouldAddCleanupOnlyFlag bool) {
 
 		// defaultGoogleCloudSDKDockerImage is the default image to use for the Google Cloud SDK image.
 		defaultGoogleCloudSDKDockerImage = "cloud-sdk:latest"
+
+		// defaultTimeout is the default maximum duration of the check.
+		defaultTimeout = 10 * time.Minute
 	)
 
 	var (
@@ -674,16 +2494,312 @@ func (c *checkCmd) flags(shouldAddCleanupOnlyFlag bool) {
 		constant.EmptyString,
 		"path to the Kubernetes configuration file to use for the check (or KUBECONFIG environment variable)",
 	)
+	c.cobraCmd.Flags().String(
+		flagKubeConfigData,
+		constant.EmptyString,
+		"base64 encoded Kubernetes configuration to use for the check, taking precedence over --"+flagKubeConfig+" (or KUBECONFIG_DATA environment variable)",
+	)
+	util.MarkFlagSensitive(c.cobraCmd, flagKubeConfigData)
 
 	if shouldAddCleanupOnlyFlag {
 		c.cobraCmd.Flags().Bool(flagCleanupOnly, false, "only clean up the resources and exit")
+		c.cobraCmd.Flags().Bool(
+			flagFix,
+			false,
+			"before the checks, fix the failures that are safe to fix automatically, such as marking the only StorageClass of the cluster as the default one "+
+				"or annotating the ServiceAccount of the GCP Crossplane role check, once confirmed, with the permissions of the Kubernetes configuration",
+		)
+
+		addYesFlag(c.cobraCmd)
+
+		c.cobraCmd.Flags().StringSlice(
+			flagContexts,
+			nil,
+			"the contexts of the Kubernetes configuration whose clusters to check, rather than the current one, each as name or name=path to check it with "+
+				"the environment configuration of the path rather than the given one; the summary of each is logged at the end, and the check fails if the "+
+				"one of any cluster fails",
+		)
+		c.cobraCmd.Flags().Int(
+			flagMaxContextConcurrency,
+			defaultMaxContextConcurrency,
+			"the maximum number of the clusters of --"+flagContexts+" that are checked at once",
+		)
 	}
 
+	c.cobraCmd.Flags().Bool(
+		flagWarningsAsErrors,
+		false,
+		fmt.Sprintf("fail the check, with the exit status %d rather than %d, if it passes but logs warnings", exitCodeFailed, exitCodePassedWithWarnings),
+	)
+	c.cobraCmd.Flags().Duration(
+		flagTimeout,
+		defaultTimeout,
+		"the maximum duration of the check, after which the resources it created are cleaned up and it fails; 0 for no timeout",
+	)
+
 	c.cobraCmd.Flags().String(flagDockerRepo, defaultDockerRepo, "the Docker repository to use for the Pod image")
 	c.cobraCmd.Flags().String(flagDockerImage, defaultDockerImage, "the Docker image to use for the Pod")
 	c.cobraCmd.Flags().String(flagImagePullSecret, constant.EmptyString, "the name of the image pull secret to use for the Pod")
-	c.cobraCmd.Flags().String(flagGoogleCloudSDKDockerRepo, defaultGoogleCloudSDKDockerRepo, "the Docker repository to use for the Google Cloud SDK image")
-	c.cobraCmd.Flags().String(flagGoogleCloudSDKDockerImage, defaultGoogleCloudSDKDockerImage, "the Docker image to use for the Google Cloud SDK")
+	c.cobraCmd.Flags().String(
+		flagImagePullPolicy,
+		string(corev1.PullAlways),
+		"the image pull policy to use for the Pod; valid values are Always, IfNotPresent or Never",
+	)
+	c.cobraCmd.Flags().Bool(
+		flagCheckImage,
+		false,
+		"check that the registry serves the Pod image, with the credentials of --"+flagImagePullSecret+" if set, before creating any resources",
+	)
+	c.cobraCmd.Flags().String(
+		flagGoogleCloudSDKDockerRepo,
+		defaultGoogleCloudSDKDockerRepo,
+		"the Docker repository to use for the Google Cloud SDK image, e.g. google or gcr.io/google.com/cloudsdktool",
+	)
+	c.cobraCmd.Flags().String(
+		flagGoogleCloudSDKDockerImage,
+		defaultGoogleCloudSDKDockerImage,
+		"the Docker image to use for the Google Cloud SDK, in the image[:tag|@digest] format, e.g. cloud-sdk:latest",
+	)
+	c.cobraCmd.Flags().String(
+		flagDBCAFile,
+		constant.EmptyString,
+		"path to the PEM encoded CA bundle to verify the MySQL and PostgreSQL TLS connections with; enables TLS when set",
+	)
+	c.cobraCmd.Flags().Bool(
+		flagCheckStorageClassProvisioner,
+		false,
+		"also check that the default storage class is provisioned by the disk CSI driver of the cloud provider, or one of --"+flagStorageClassProvisioners,
+	)
+	c.cobraCmd.Flags().StringSlice(
+		flagStorageClassProvisioners,
+		nil,
+		"the provisioners expected of the default storage class, replacing the disk CSI driver of the cloud provider; implies --"+
+			flagCheckStorageClassProvisioner,
+	)
+	c.cobraCmd.Flags().String(
+		flagMySQLSecret,
+		constant.EmptyString,
+		"the name of the secret in the MySQL namespace that contains the MySQL credentials; defaults to "+mysqlchecker.SecretName,
+	)
+	c.cobraCmd.Flags().String(
+		flagPostgreSQLSecret,
+		constant.EmptyString,
+		"the name of the secret in the PostgreSQL namespace that contains the PostgreSQL credentials; defaults to "+postgresqlchecker.SecretName,
+	)
+	c.cobraCmd.Flags().String(
+		flagSMTPSecret,
+		constant.EmptyString,
+		"the name of the secret in the AlphaSense namespace that contains the SMTP credentials; defaults to "+smtpchecker.SecretName,
+	)
+	c.cobraCmd.Flags().StringToString(
+		flagMySQLSecretKeys,
+		nil,
+		"the keys of the MySQL secret that replace the default ones, "+strings.Join(mysqlchecker.SecretKeys(), ", ")+", as default=key pairs, such as "+
+			"password=db-password",
+	)
+	c.cobraCmd.Flags().StringToString(
+		flagPostgreSQLSecretKeys,
+		nil,
+		"the keys of the PostgreSQL secret that replace the default ones, "+strings.Join(postgresqlchecker.SecretKeys(), ", ")+", as default=key pairs, "+
+			"such as password=db-password",
+	)
+	c.cobraCmd.Flags().StringToString(
+		flagSMTPSecretKeys,
+		nil,
+		"the keys of the SMTP secret that replace the default ones, "+strings.Join(smtpchecker.SecretKeys(), ", ")+", as default=key pairs, such as "+
+			"username=user",
+	)
+	c.cobraCmd.Flags().Bool(
+		flagCheckSMTPConnection,
+		false,
+		"also connect to the SMTP server, upgrading the connection with STARTTLS unless on port 465, and authenticate with the credentials of the secret",
+	)
+	c.cobraCmd.Flags().StringSlice(
+		flagSSOSecret,
+		nil,
+		"the names of the secrets in the platform namespace that contain the SSO configurations to check, SAML or OIDC; defaults to "+ssochecker.SecretName,
+	)
+	c.cobraCmd.Flags().String(
+		flagSSOSecretSelector,
+		constant.EmptyString,
+		"the label selector of the secrets in the platform namespace that contain the SSO configurations to check",
+	)
+	c.cobraCmd.MarkFlagsMutuallyExclusive(flagSSOSecret, flagSSOSecretSelector)
+	c.cobraCmd.Flags().StringSlice(
+		flagSSOSAMLKeys,
+		nil,
+		"the keys that the SAML SSO configurations must have besides the entity ID, e.g. saml-metadata,saml-cert; if set, the metadata is also validated",
+	)
+	c.cobraCmd.Flags().String(
+		flagCrossplaneNamespace,
+		constant.EmptyString,
+		"the namespace of the service accounts of the Crossplane providers, which the Crossplane role is expected to trust and the Pod ensures its own "+
+			"in; defaults to "+constant.NamespaceCrossplane,
+	)
+	c.cobraCmd.Flags().String(
+		flagCrossplaneServiceAccountsPrefix,
+		constant.EmptyString,
+		"the prefix of the names of the service accounts of the Crossplane providers that the trust policy of the AWS Crossplane role is expected to trust; "+
+			"defaults to "+awsjwtretriever.ServiceAccountsPrefix,
+	)
+	c.cobraCmd.Flags().StringSlice(
+		flagAllowedExtraPolicyStatements,
+		nil,
+		"the SIDs of the statements that the policies of the AWS Crossplane role may have on top of the expected ones, such as the tagging permissions "+
+			"added by the customer; any other extra statement is a mismatch",
+	)
+	c.cobraCmd.Flags().String(
+		flagExpectedPermissionsFile,
+		constant.EmptyString,
+		"path to the file listing the permissions, one per line, to merge with the expected permissions of the Azure or GCP Crossplane role; "+
+			"the permissions starting with - are no longer expected",
+	)
+	c.cobraCmd.Flags().Bool(
+		flagRequiresGPU,
+		true,
+		"whether the deployment requires GPU nodes; if false, the node group check is skipped rather than warning about the missing GPU nodes; "+
+			"defaults to spec.requiresGPU of the environment configuration",
+	)
+	c.cobraCmd.Flags().Bool(
+		flagCheckSpiceDB,
+		false,
+		"also check that the PostgreSQL user can create the SpiceDB database or use the existing one; some managed PostgreSQL services restrict this introspection",
+	)
+	c.cobraCmd.Flags().String(
+		flagSpiceDBDatabase,
+		constant.EmptyString,
+		"the name of the database that SpiceDB uses, checked with --"+flagCheckSpiceDB+"; defaults to "+postgresqlchecker.SpiceDBDatabase,
+	)
+	c.cobraCmd.Flags().StringToString(
+		flagMySQLExpectedConfig,
+		nil,
+		"the system variables of MySQL to expect instead of, or on top of, the default ones, as variable=value pairs, quoted if the value contains commas; a "+
+			"value starting with "+mysqlchecker.MinimumPrefix+" is the minimum acceptable one, such as wait_timeout="+mysqlchecker.MinimumPrefix+"1800",
+	)
+	c.cobraCmd.Flags().StringToString(
+		flagPostgreSQLExpectedConfig,
+		nil,
+		"the settings of PostgreSQL to expect, none by default, as setting=value pairs, quoted if the value contains commas, such as timezone=UTC",
+	)
+	c.cobraCmd.Flags().Duration(
+		flagConnectTimeout,
+		defaultConnectTimeout,
+		"the maximum duration of establishing a connection to the databases, the SMTP server and the HTTPS endpoints, such as the OIDC issuer, from the Pod",
+	)
+	c.cobraCmd.Flags().Duration(
+		flagMaxClockSkew,
+		defaultMaxClockSkew,
+		"the maximum difference between the clock of the Pod and the one of the OIDC server, as of the Date header of its response, that is not warned "+
+			"about, as a larger one makes the JWTs fail validation as not valid yet or expired; 0 disables the comparison",
+	)
+	c.cobraCmd.Flags().String(
+		flagSinceVersion,
+		constant.EmptyString,
+		"the lowest version of the Private Cloud that the CLI supports, as listed in the compatibility matrix, that the spec.version of the environment "+
+			"configuration is warned about being older than; not checked by default",
+	)
+	c.cobraCmd.Flags().Bool(
+		flagCheckOIDCDiscovery,
+		false,
+		"also check that the discovery document of the OIDC server lists RS256, the signing algorithm of the JWTs, in id_token_signing_alg_values_supported, "+
+			"and the audiences of the JWTs in audiences_supported if it lists them",
+	)
+	c.cobraCmd.Flags().StringSlice(
+		flagOIDCAudiences,
+		nil,
+		"the audiences that the discovery document of the OIDC server is expected to list, replacing the one of the JWTs of the cloud provider; implies --"+
+			flagCheckOIDCDiscovery,
+	)
+	c.cobraCmd.Flags().Int(
+		flagMaxConcurrency,
+		defaultMaxConcurrency,
+		"the maximum number of the checks of the cloud, such as the MySQL and the SMTP ones, that the Pod runs at once; 1 runs them one at a time",
+	)
+	c.cobraCmd.Flags().String(
+		flagHTTPSProxy,
+		constant.EmptyString,
+		"the proxy of the HTTPS requests to the endpoints outside of the cluster, such as the OIDC issuer and the registry of --"+flagCheckImage+
+			", overriding the HTTPS_PROXY environment variable of the Pod and of the command",
+	)
+	util.MarkFlagSensitive(c.cobraCmd, flagHTTPSProxy)
+	c.cobraCmd.Flags().String(
+		flagNoProxy,
+		constant.EmptyString,
+		"the comma-separated hosts, domains and CIDRs that bypass the HTTPS proxy, overriding the NO_PROXY environment variable of the Pod and of the command",
+	)
+	c.cobraCmd.Flags().String(
+		flagCACert,
+		constant.EmptyString,
+		"path to the PEM encoded CA bundle, e.g. of the internal CA of a corporate proxy, that the servers of the HTTPS requests to the endpoints outside of "+
+			"the cluster, such as the OIDC issuer, its JWKS and the registry of --"+flagCheckImage+", and the SMTP server of --"+flagCheckSMTPConnection+
+			", are verified against along with the CAs of the system",
+	)
+	c.cobraCmd.Flags().String(
+		flagNamespacePrefix,
+		constant.EmptyString,
+		"the prefix of the namespaces of the install on the multi-install clusters, joined with a hyphen, e.g. tenant1 for tenant1-mysql; the Roles and "+
+			"the secrets of the checks are in the prefixed "+strings.Join(constInstallNamespaces, ", ")+" namespaces, but not --"+flagCrossplaneNamespace,
+	)
+	c.cobraCmd.Flags().String(
+		flagPodNamespace,
+		namespaceDefault,
+		"the namespace to create the Pod and its ServiceAccount in, created if missing, for the clusters that do not allow creating Pods in the "+
+			namespaceDefault+" namespace",
+	)
+	c.cobraCmd.Flags().String(
+		flagWriteResultsConfigMap,
+		constant.EmptyString,
+		"the ConfigMap, in the namespace/name format, to write the status and the time of each of the checks, along with the CLI version, to once the "+
+			"Pod finishes, overwriting the results of the previous run; the namespace must exist",
+	)
+	c.cobraCmd.Flags().Bool(
+		flagPrintResults,
+		false,
+		"print the status of each of the checks, passed, warning, failed or skipped, along with its error, as a single JSON object to the standard output "+
+			"once the Pod finishes; the logs are still written to the standard error",
+	)
+	c.cobraCmd.Flags().Bool(
+		flagStreamResults,
+		false,
+		"print the status of each of the checks as a line of JSON to the standard output as soon as it is reported, followed by a line with the summary "+
+			"of the results; the logs are still written to the standard error",
+	)
+	c.cobraCmd.MarkFlagsMutuallyExclusive(flagPrintResults, flagStreamResults)
+	c.cobraCmd.Flags().String(
+		flagMetricsFile,
+		constant.EmptyString,
+		"the file to write the status of each of the checks, the overall status and the time of the run to in the OpenMetrics text format once the Pod "+
+			"finishes, e.g. for the textfile collector of node_exporter, replacing the file of the previous run atomically",
+	)
+	c.cobraCmd.Flags().Bool(flagRoleOnly, false, "only check the Crossplane role, skipping the storage, database, TLS, SMTP and SSO checks")
+	c.cobraCmd.Flags().Bool(
+		flagLocal,
+		false,
+		"run the checks from the command with the permissions of the Kubernetes configuration, without creating the Pod or any other resources in the "+
+			"cluster; the Crossplane role is only checked on AWS, with the credentials of the default chain of the AWS SDK, e.g. of the AWS_ACCESS_KEY_ID "+
+			"environment variable or of the AWS_PROFILE profile",
+	)
+	c.cobraCmd.Flags().Bool(
+		flagVerifyCleanup,
+		false,
+		fmt.Sprintf("wait up to %s for the resources to be gone once they are cleaned up, warning about the ones that linger, e.g. held by finalizers",
+			cleanupVerificationTimeout),
+	)
+	c.cobraCmd.Flags().String(
+		flagPodSecurityProfile,
+		kubeutil.PodSecurityProfileRestricted,
+		"the security profile of the Pods; "+kubeutil.PodSecurityProfileRestricted+" complies with the restricted PodSecurity standard, "+
+			kubeutil.PodSecurityProfileUnconfined+" leaves the security context unset for the clusters where the Pods need elevated access",
+	)
+
+	if shouldAddCleanupOnlyFlag {
+		// The cleanup and the fixes are run against the current context only, and a single metrics file or stream of results cannot hold the results
+		// of several clusters.
+		c.cobraCmd.MarkFlagsMutuallyExclusive(flagContexts, flagCleanupOnly)
+		c.cobraCmd.MarkFlagsMutuallyExclusive(flagContexts, flagFix)
+		c.cobraCmd.MarkFlagsMutuallyExclusive(flagContexts, flagMetricsFile)
+		c.cobraCmd.MarkFlagsMutuallyExclusive(flagContexts, flagStreamResults)
+	}
 }
 
 // newCheckCmd returns a new checkCmd.
@@ -691,6 +2807,7 @@ func newCheckCmd(logger *log.Logger, cobraCmd *cobra.Command) *checkCmd {
 	return &checkCmd{
 		logger:   logger,
 		cobraCmd: cobraCmd,
+		clock:    clock.Real{},
 	}
 }
 
@@ -706,6 +2823,7 @@ func Check(logger *log.Logger) *cobra.Command {
 	}
 
 	cmd := newCheckCmd(logger, cobraCmd)
+	cmd.exitOnWarnings = true
 
 	cobraCmd.Long = cmd.longMsg("Check reviews the infrastructure in your cloud environment to ensure it is ready for deployment.")
 
@@ -0,0 +1,329 @@
+// Package cmd is the package that contains all of the commands for the application.
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/cloud"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/awscrossplanerolechecker"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/cloudchecker"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/crossplanerolechecker"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/podprotocol"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/report"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/util"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/charmbracelet/log"
+	"go.uber.org/multierr"
+	"k8s.io/client-go/dynamic"
+)
+
+// errFailedToLoadAWSConfig is the error that is returned when the configuration of the AWS SDK, e.g. its shared configuration files, cannot be loaded.
+var errFailedToLoadAWSConfig = errors.New("failed to load AWS configuration")
+
+// podLogWriter is the writer of the log lines of the checks that run locally, which prints each of them as soon as it is complete, as the ones of the Pod
+// are printed, so that the warnings, the fatal errors and the results of the checks are counted alike.
+type podLogWriter struct {
+	// mu is the mutex that serializes the writes, as the logger and the writer of the messages of the pod protocol lock their own, and the checks that run
+	// at once write from several goroutines, and that guards the state of the Check command that printing the log lines updates.
+	mu sync.Mutex
+	// c is the Check command that prints the log lines.
+	c *checkCmd
+	// buf is the buffer of the log line that is not complete yet.
+	buf bytes.Buffer
+	// fatal is whether any of the printed log lines is of the fatal level.
+	fatal bool
+	// err is the error of the first log line that could not be printed, as the logger ignores the errors of its writer.
+	err error
+}
+
+// Write is the function that prints the complete log lines of the data, keeping the incomplete one until the rest of it is written.
+func (w *podLogWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf.Write(p)
+
+	for {
+		line, err := w.buf.ReadString('\n')
+		if err != nil {
+			w.buf.WriteString(line)
+
+			break
+		}
+
+		if line = strings.TrimSuffix(line, "\n"); line == constant.EmptyString {
+			continue
+		}
+
+		fatal, err := w.c.printPodLogs([]string{line})
+		if err != nil && w.err == nil {
+			w.err = err
+		}
+
+		w.fatal = w.fatal || fatal
+	}
+
+	return len(p), nil
+}
+
+// localCheckOptions returns the options of the checks that run locally from the flags, as the Pod reads them from its environment variables.
+//
+// The options of the checks that do not run locally, such as the Google Cloud SDK image and the override of the expected permissions, are not set.
+func (c *checkCmd) localCheckOptions() (handler.CheckOptions, error) {
+	var dbTLSConfig *tls.Config
+
+	if dbCAFile := util.Flag(c.cobraCmd, flagDBCAFile); dbCAFile != constant.EmptyString {
+		dbCABundle, err := os.ReadFile(dbCAFile) // nolint:gosec
+		if err != nil {
+			return handler.CheckOptions{}, multierr.Combine(errFailedToReadDBCABundle, err)
+		}
+
+		if dbTLSConfig, err = util.TLSConfigFromCABundle(dbCABundle); err != nil {
+			return handler.CheckOptions{}, multierr.Combine(errFailedToReadDBCABundle, err)
+		}
+	}
+
+	ssoSecretNames, err := c.cobraCmd.Flags().GetStringSlice(flagSSOSecret)
+	if err != nil {
+		return handler.CheckOptions{}, err
+	}
+
+	ssoSAMLKeys, err := c.cobraCmd.Flags().GetStringSlice(flagSSOSAMLKeys)
+	if err != nil {
+		return handler.CheckOptions{}, err
+	}
+
+	storageClassProvisioners, err := c.cobraCmd.Flags().GetStringSlice(flagStorageClassProvisioners)
+	if err != nil {
+		return handler.CheckOptions{}, err
+	}
+
+	oidcAudiences, err := c.cobraCmd.Flags().GetStringSlice(flagOIDCAudiences)
+	if err != nil {
+		return handler.CheckOptions{}, err
+	}
+
+	allowedExtraPolicyStatements, err := c.cobraCmd.Flags().GetStringSlice(flagAllowedExtraPolicyStatements)
+	if err != nil {
+		return handler.CheckOptions{}, err
+	}
+
+	mysqlExpectedConfig, err := c.cobraCmd.Flags().GetStringToString(flagMySQLExpectedConfig)
+	if err != nil {
+		return handler.CheckOptions{}, err
+	}
+
+	postgresqlExpectedConfig, err := c.cobraCmd.Flags().GetStringToString(flagPostgreSQLExpectedConfig)
+	if err != nil {
+		return handler.CheckOptions{}, err
+	}
+
+	mysqlSecretKeys, postgresqlSecretKeys, smtpSecretKeys, err := c.secretKeys()
+	if err != nil {
+		return handler.CheckOptions{}, err
+	}
+
+	requiresGPU := c.envConfig.GPURequired()
+	if c.cobraCmd.Flags().Changed(flagRequiresGPU) {
+		requiresGPU = util.FlagBool(c.cobraCmd, flagRequiresGPU)
+	}
+
+	return handler.CheckOptions{
+		DBTLSConfig:                      dbTLSConfig,
+		ConnectTimeout:                   util.FlagDuration(c.cobraCmd, flagConnectTimeout),
+		RootCAs:                          c.rootCAs,
+		CheckSpiceDB:                     util.FlagBool(c.cobraCmd, flagCheckSpiceDB),
+		MySQLExpectedConfigOverride:      mysqlExpectedConfig,
+		PostgreSQLExpectedConfigOverride: postgresqlExpectedConfig,
+		MaxClockSkew:                     util.FlagDuration(c.cobraCmd, flagMaxClockSkew),
+		CheckOIDCDiscovery:               util.FlagBool(c.cobraCmd, flagCheckOIDCDiscovery) || len(oidcAudiences) > 0,
+		OIDCAudiences:                    oidcAudiences,
+
+		SkipNodeGroups:                  !requiresGPU,
+		MaxConcurrency:                  util.FlagInt(c.cobraCmd, flagMaxConcurrency),
+		CheckStorageClassProvisioner:    util.FlagBool(c.cobraCmd, flagCheckStorageClassProvisioner) || len(storageClassProvisioners) > 0,
+		StorageClassProvisioners:        storageClassProvisioners,
+		MySQLSecretName:                 util.Flag(c.cobraCmd, flagMySQLSecret),
+		PostgreSQLSecretName:            util.Flag(c.cobraCmd, flagPostgreSQLSecret),
+		SpiceDBDatabase:                 util.Flag(c.cobraCmd, flagSpiceDBDatabase),
+		SMTPSecretName:                  util.Flag(c.cobraCmd, flagSMTPSecret),
+		MySQLSecretKeys:                 mysqlSecretKeys,
+		PostgreSQLSecretKeys:            postgresqlSecretKeys,
+		SMTPSecretKeys:                  smtpSecretKeys,
+		CheckSMTPConnection:             util.FlagBool(c.cobraCmd, flagCheckSMTPConnection),
+		SSOSecretNames:                  ssoSecretNames,
+		SSOSecretSelector:               util.Flag(c.cobraCmd, flagSSOSecretSelector),
+		SSOSAMLKeys:                     ssoSAMLKeys,
+		CrossplaneNamespace:             util.Flag(c.cobraCmd, flagCrossplaneNamespace),
+		CrossplaneServiceAccountsPrefix: util.Flag(c.cobraCmd, flagCrossplaneServiceAccountsPrefix),
+		AllowedExtraPolicyStatements:    allowedExtraPolicyStatements,
+		NamespacePrefix:                 util.Flag(c.cobraCmd, flagNamespacePrefix),
+	}, nil
+}
+
+// checkRoleLocally checks the Crossplane role on AWS with the credentials of the default chain of the AWS SDK, e.g. of the environment variables, of the
+// shared configuration and credentials files or of the instance, reporting it as skipped on the other cloud providers and without the credentials, as the
+// checks of the role there require the identities of the service accounts of the Crossplane providers, which only the Pod has.
+func checkRoleLocally(ctx context.Context, checkCtx handler.CheckContext) error {
+	const (
+		// logMsgCrossplaneRoleSkipped is the message that is logged when the Crossplane role check is skipped.
+		logMsgCrossplaneRoleSkipped = "skipped Crossplane role check; %s"
+
+		// msgCloudNotLocal is the reason for skipping the Crossplane role check on the cloud providers where it does not run locally.
+		msgCloudNotLocal = "not run in local mode on %s"
+
+		// msgNoAWSCredentials is the reason for skipping the Crossplane role check without the AWS credentials of the default chain.
+		msgNoAWSCredentials = "not run in local mode without the AWS credentials of the environment, the shared files or the instance"
+
+		// logMsgNoAWSCredentials is the message that is logged with the error of retrieving the AWS credentials of the default chain.
+		logMsgNoAWSCredentials = "failed to retrieve AWS credentials: %v"
+	)
+
+	skip := func(reason report.SkipReason, msg string) {
+		checkCtx.Logger.Infof(logMsgCrossplaneRoleSkipped, msg)
+
+		checkCtx.Results.Add(report.Result{Check: cloudchecker.CheckNameCrossplaneRole, Status: report.StatusSkipped, Message: msg, SkipReason: reason})
+	}
+
+	if checkCtx.VCloud != cloud.AWS {
+		skip(report.SkipReasonNotApplicable, fmt.Sprintf(msgCloudNotLocal, checkCtx.VCloud))
+
+		return nil
+	}
+
+	awsConfig, err := config.LoadDefaultConfig(ctx, config.WithRegion(checkCtx.EnvConfig.Spec.CloudSpec.CloudZone))
+	if err != nil {
+		return multierr.Combine(errFailedToLoadAWSConfig, err)
+	}
+
+	if _, err := awsConfig.Credentials.Retrieve(ctx); err != nil {
+		checkCtx.Logger.Debugf(logMsgNoAWSCredentials, err)
+
+		skip(report.SkipReasonPrerequisiteMissing, msgNoAWSCredentials)
+
+		return nil
+	}
+
+	checkCtx.CheckStarted(cloudchecker.CheckNameCrossplaneRole)
+
+	// The credentials are retrieved with the HTTP client of the AWS SDK, which honors its own settings such as AWS_CA_BUNDLE, and the IAM API is reached
+	// with the one of the checks, as the other endpoints are.
+	awsConfig.HTTPClient = checkCtx.HTTPClient
+
+	crossplaneRoleChecker := awscrossplanerolechecker.New(checkCtx, iam.NewFromConfig(awsConfig))
+
+	_, err = crossplaneRoleChecker.Handle(ctx)
+
+	checkCtx.Results.Add(report.NewResult(cloudchecker.CheckNameCrossplaneRole, constant.EmptyString, err))
+
+	if err != nil {
+		return multierr.Combine(crossplanerolechecker.ErrFailedToCheckCrossplaneRole, err)
+	}
+
+	checkCtx.Logger.Info(crossplanerolechecker.LogMsgCrossplaneRoleCheckedSuccessfully)
+
+	return nil
+}
+
+// runLocal runs the checks from the command rather than from the Pod, with the permissions of the Kubernetes configuration, without creating any resources
+// in the cluster, for the clusters that do not allow creating Pods.
+//
+// The checks of the cloud that the Pod runs, from the storage class to the OIDC URL, run alike, though the databases and the endpoints are reached from
+// the machine that runs the command; the Crossplane role is only checked on AWS, by checkRoleLocally. The checks log as the Pod does, and their logs are
+// printed as the ones of the Pod are.
+// It returns whether any of the checks failed, or an error if they could not be run.
+func (c *checkCmd) runLocal(ctx context.Context) (bool, error) {
+	const (
+		// logMsgRunningLocally is the message that is logged when the checks run locally.
+		logMsgRunningLocally = "running checks locally, without creating any resources in the cluster"
+
+		// logMsgRoleOnly is the message that is logged when only the Crossplane role is checked.
+		logMsgRoleOnly = "checking Crossplane role only, skipping the rest of the infrastructure checks"
+	)
+
+	options, err := c.localCheckOptions()
+	if err != nil {
+		return false, err
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(c.kubeConfig)
+	if err != nil {
+		return false, multierr.Combine(errFailedToCreateKubernetesDynamicClient, err)
+	}
+
+	c.logger.Info(logMsgRunningLocally)
+
+	w := &podLogWriter{c: c}
+
+	// The Check command filters the printed log lines by its own level, so the checks log at every level.
+	logger := withEnvConfigFields(log.NewWithOptions(w, log.Options{
+		Level:           log.DebugLevel,
+		ReportTimestamp: true,
+		TimeFunction:    constant.LogDefaultTimeFunc,
+		Formatter:       log.JSONFormatter,
+	}), c.envConfig)
+
+	// The messages of the pod protocol are written along with the log lines, to be read alike.
+	messages := podprotocol.NewWriter(w, c.clock)
+
+	vcloud := cloud.Cloud(c.envConfig.Spec.CloudSpec.Provider)
+
+	roleOnly := util.FlagBool(c.cobraCmd, flagRoleOnly)
+
+	var results *report.Collector
+
+	if c.reportsResults() {
+		results = resultMessagesCollector(c.clock, messages, logger)
+	}
+
+	checkCtx := handler.CheckContext{
+		Logger:        logger,
+		VCloud:        vcloud,
+		EnvConfig:     c.envConfig,
+		Clientset:     c.clientset,
+		DynamicClient: dynamicClient,
+		HTTPClient:    util.NewHTTPClient(options.ConnectTimeout, c.proxyConfig, c.rootCAs),
+		Results:       results,
+		Clock:         c.clock,
+		OnCheckStart: func(check string) {
+			if err := messages.Write(podprotocol.PodMessage{Type: podprotocol.TypeCheckStart, Check: check}); err != nil {
+				logger.Error(err)
+			}
+		},
+		Options: options,
+	}
+
+	if roleOnly {
+		logger.Info(logMsgRoleOnly)
+	} else {
+		_, err = cloudchecker.New(checkCtx).Handle(ctx)
+	}
+
+	if err == nil {
+		err = checkRoleLocally(ctx, checkCtx)
+	}
+
+	if err != nil {
+		logger.Log(log.FatalLevel, multierr.Combine(errFailedToCheckInfrastructure, err))
+	}
+
+	if results != nil {
+		added := results.Results()
+
+		if err := writeResultMessages(messages, cloudchecker.CompleteResults(vcloud, roleOnly, added), len(added)); err != nil {
+			return w.fatal, err
+		}
+	}
+
+	return w.fatal, w.err
+}
@@ -0,0 +1,335 @@
+// Package cmd is the package that contains all of the commands for the application.
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/clock"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/cloud"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/envconfig"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/cloudchecker"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/crossplanerolechecker"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/podprotocol"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/report"
+	"github.com/charmbracelet/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+)
+
+// TestPodLogWriter is a test that tests that the podLogWriter prints the log lines once they are complete, whatever the writes they are split across,
+// counting the warnings and the fatal errors as the ones of the Pod are counted.
+func TestPodLogWriter(t *testing.T) {
+	c := setupCheckCmdTest(t, nil)
+
+	w := &podLogWriter{c: c}
+
+	_, err := w.Write([]byte(`{"time":"2026/01/02 03:04:05","level":"warn","msg":"no nodes with GPU label found"}` + "\n" + `{"time":"2026/01/02`))
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, c.warnings)
+	assert.False(t, w.fatal)
+
+	_, err = w.Write([]byte(` 03:04:06","level":"fatal","msg":"failed to check infrastructure"}` + "\n\n"))
+	require.NoError(t, err)
+
+	assert.True(t, w.fatal)
+	assert.Zero(t, w.buf.Len())
+
+	_, err = w.Write([]byte("not JSON\n"))
+	require.NoError(t, err)
+
+	assert.Error(t, w.err)
+}
+
+// TestPodLogWriter_concurrent is a test that tests that the podLogWriter prints the log lines that are written to it concurrently, as the checks that run
+// at once log, and the messages of their starts are written, from several goroutines. Run it with -race.
+func TestPodLogWriter_concurrent(t *testing.T) {
+	const (
+		// goroutines is the number of the goroutines that write to the writer in the test.
+		goroutines = 8
+
+		// lines is the number of the log lines that each goroutine writes in the test.
+		lines = 50
+	)
+
+	c := setupCheckCmdTest(t, nil)
+
+	w := &podLogWriter{c: c}
+
+	logger := log.NewWithOptions(w, log.Options{
+		Level:           log.DebugLevel,
+		ReportTimestamp: true,
+		TimeFunction:    constant.LogDefaultTimeFunc,
+		Formatter:       log.JSONFormatter,
+	})
+
+	messages := podprotocol.NewWriter(w, c.clock)
+
+	var wg sync.WaitGroup
+
+	for i := range goroutines {
+		wg.Go(func() {
+			for range lines {
+				logger.Warn("no nodes with GPU label found")
+
+				assert.NoError(t, messages.Write(podprotocol.PodMessage{Type: podprotocol.TypeCheckStart, Check: fmt.Sprintf("check %d", i)}))
+			}
+		})
+	}
+
+	wg.Wait()
+
+	require.NoError(t, w.err)
+	assert.Equal(t, goroutines*lines, c.warnings)
+	assert.False(t, w.fatal)
+	assert.Zero(t, w.buf.Len())
+}
+
+// TestCheckCmd_localCheckOptions is a test that tests that the localCheckOptions function reads the options of the checks from the flags, as the Pod reads
+// them from its environment variables.
+func TestCheckCmd_localCheckOptions(t *testing.T) {
+	c := setupCheckCmdTest(t, map[string]string{
+		flagConnectTimeout:           "5s",
+		flagMaxConcurrency:           "4",
+		flagStorageClassProvisioners: "ebs.csi.aws.com",
+		flagOIDCAudiences:            "sts.amazonaws.com",
+		flagMySQLSecret:              "mysql-creds",
+		flagSpiceDBDatabase:          "authz",
+		flagCheckSMTPConnection:      "true",
+		flagSSOSecret:                "sso-saml,sso-oidc",
+		flagSSOSAMLKeys:              "saml-metadata",
+		flagCrossplaneNamespace:      "platform-crossplane",
+		flagNamespacePrefix:          "tenant1",
+		flagRequiresGPU:              "false",
+
+		flagAllowedExtraPolicyStatements: "AllowTagging",
+		flagPostgreSQLExpectedConfig:     "TimeZone=UTC",
+	})
+
+	options, err := c.localCheckOptions()
+	require.NoError(t, err)
+
+	assert.Equal(t, 5*time.Second, options.ConnectTimeout)
+	assert.Equal(t, 4, options.MaxConcurrency)
+	assert.True(t, options.CheckStorageClassProvisioner)
+	assert.Equal(t, []string{"ebs.csi.aws.com"}, options.StorageClassProvisioners)
+	assert.True(t, options.CheckOIDCDiscovery)
+	assert.Equal(t, []string{"sts.amazonaws.com"}, options.OIDCAudiences)
+	assert.Equal(t, "mysql-creds", options.MySQLSecretName)
+	assert.Empty(t, options.PostgreSQLSecretName)
+	assert.Equal(t, "authz", options.SpiceDBDatabase)
+	assert.True(t, options.CheckSMTPConnection)
+	assert.Equal(t, []string{"sso-saml", "sso-oidc"}, options.SSOSecretNames)
+	assert.Equal(t, []string{"saml-metadata"}, options.SSOSAMLKeys)
+	assert.Equal(t, "platform-crossplane", options.CrossplaneNamespace)
+	assert.Equal(t, "tenant1", options.NamespacePrefix)
+	assert.Equal(t, []string{"AllowTagging"}, options.AllowedExtraPolicyStatements)
+	assert.Equal(t, map[string]string{"TimeZone": "UTC"}, options.PostgreSQLExpectedConfigOverride)
+	assert.True(t, options.SkipNodeGroups)
+	assert.Nil(t, options.DBTLSConfig)
+
+	c = setupCheckCmdTest(t, map[string]string{flagDBCAFile: filepath.Join(t.TempDir(), "missing.pem")})
+
+	_, err = c.localCheckOptions()
+	assert.ErrorIs(t, err, errFailedToReadDBCABundle)
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, []byte("not a certificate"), 0o600))
+
+	c = setupCheckCmdTest(t, map[string]string{flagDBCAFile: caFile})
+
+	_, err = c.localCheckOptions()
+	assert.ErrorIs(t, err, errFailedToReadDBCABundle)
+}
+
+// unsetAWSCredentials is the function that unsets the AWS credentials of the default chain of the AWS SDK for the test, pointing its shared files to missing
+// ones and disabling the ones of the instance, so that none are found.
+func unsetAWSCredentials(t *testing.T) {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	for _, name := range []string{
+		"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_SESSION_TOKEN", "AWS_PROFILE", "AWS_WEB_IDENTITY_TOKEN_FILE",
+		"AWS_CONTAINER_CREDENTIALS_RELATIVE_URI", "AWS_CONTAINER_CREDENTIALS_FULL_URI",
+	} {
+		t.Setenv(name, constant.EmptyString)
+	}
+
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+	t.Setenv("AWS_EC2_METADATA_DISABLED", strconv.FormatBool(true))
+}
+
+// roundTripperFunc is the type of the function that implements the http.RoundTripper interface.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip is the function that calls the function with the request.
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// TestCheckRoleLocally_sharedCredentials is a test that tests that the checkRoleLocally function checks the Crossplane role on AWS with the credentials
+// of the shared credentials file, which the default chain of the AWS SDK reads, rather than skipping the check without the credentials of the environment.
+func TestCheckRoleLocally_sharedCredentials(t *testing.T) {
+	// errUnreachable is the error of the requests to the IAM API.
+	errUnreachable := errors.New("unreachable")
+
+	unsetAWSCredentials(t)
+
+	credentialsFile := filepath.Join(t.TempDir(), "credentials")
+	require.NoError(t, os.WriteFile(credentialsFile, []byte("[default]\naws_access_key_id = AKIDSHARED\naws_secret_access_key = secret\n"), 0o600))
+
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", credentialsFile)
+	t.Setenv("AWS_MAX_ATTEMPTS", "1")
+
+	var authorization string
+
+	results := report.NewCollector(clock.Real{})
+
+	checkCtx := handler.CheckContext{
+		Logger: log.New(io.Discard),
+		VCloud: cloud.AWS,
+		EnvConfig: &envconfig.EnvConfig{Spec: envconfig.Spec{ClusterName: "test", CloudSpec: envconfig.CloudSpec{
+			Provider:  string(cloud.AWS),
+			CloudZone: "us-east-1",
+			AWS:       &envconfig.AWSSpec{AccountID: "123456789012"},
+		}}},
+		Clientset: fake.NewClientset(),
+		HTTPClient: &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			authorization = req.Header.Get("Authorization")
+
+			return nil, errUnreachable
+		})},
+		Results: results,
+	}
+
+	err := checkRoleLocally(context.Background(), checkCtx)
+
+	require.ErrorIs(t, err, crossplanerolechecker.ErrFailedToCheckCrossplaneRole)
+	require.ErrorIs(t, err, errUnreachable)
+	assert.Contains(t, authorization, "Credential=AKIDSHARED/")
+
+	require.Len(t, results.Results(), 1)
+	assert.Equal(t, report.StatusFailed, results.Results()[0].Status)
+}
+
+// TestCheckCmd_runLocal is a test that tests that the runLocal function runs the checks without creating any resources in the cluster, reporting their
+// results as the ones of the Pod, and that the Crossplane role is only checked on AWS with the credentials of the environment.
+func TestCheckCmd_runLocal(t *testing.T) {
+	// notRunLocally is the reason of the Crossplane role check that is not run locally on GCP.
+	notRunLocally := fmt.Sprintf("not run in local mode on %s", cloud.GCP)
+
+	testCases := []struct {
+		name      string
+		vcloud    cloud.Cloud
+		flags     map[string]string
+		wantFatal bool
+		want      map[string]report.Status
+		wantMsg   string
+		wantSkip  report.SkipReason
+	}{
+		{
+			name:      "Failed",
+			vcloud:    cloud.GCP,
+			wantFatal: true,
+			want: map[string]report.Status{
+				cloudchecker.CheckNameStorageClass:   report.StatusFailed,
+				cloudchecker.CheckNameMySQL:          report.StatusSkipped,
+				cloudchecker.CheckNameCrossplaneRole: report.StatusSkipped,
+			},
+			wantMsg:  "not run as an earlier check failed",
+			wantSkip: report.SkipReasonPrerequisiteMissing,
+		},
+		{
+			name:      "Failed with concurrency",
+			vcloud:    cloud.GCP,
+			flags:     map[string]string{flagMaxConcurrency: "4"},
+			wantFatal: true,
+			want:      map[string]report.Status{cloudchecker.CheckNameCrossplaneRole: report.StatusSkipped},
+			wantMsg:   "not run as an earlier check failed",
+			wantSkip:  report.SkipReasonPrerequisiteMissing,
+		},
+		{
+			name:   "Role only on GCP",
+			vcloud: cloud.GCP,
+			flags:  map[string]string{flagRoleOnly: "true"},
+			want: map[string]report.Status{
+				cloudchecker.CheckNameStorageClass:   report.StatusSkipped,
+				cloudchecker.CheckNameCrossplaneRole: report.StatusSkipped,
+			},
+			wantMsg:  notRunLocally,
+			wantSkip: report.SkipReasonNotApplicable,
+		},
+		{
+			name:   "Role only on AWS without credentials",
+			vcloud: cloud.AWS,
+			flags:  map[string]string{flagRoleOnly: "true"},
+			want: map[string]report.Status{
+				cloudchecker.CheckNameStorageClass:   report.StatusSkipped,
+				cloudchecker.CheckNameCrossplaneRole: report.StatusSkipped,
+			},
+			wantMsg:  "not run in local mode without the AWS credentials of the environment, the shared files or the instance",
+			wantSkip: report.SkipReasonPrerequisiteMissing,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			unsetAWSCredentials(t)
+
+			flags := map[string]string{flagLocal: "true", flagPrintResults: "true"}
+
+			for name, value := range tc.flags {
+				flags[name] = value
+			}
+
+			c := setupCheckCmdTest(t, flags)
+			c.envConfig = &envconfig.EnvConfig{Spec: envconfig.Spec{ClusterName: "test", CloudSpec: envconfig.CloudSpec{Provider: string(tc.vcloud)}}}
+			c.kubeConfig = &rest.Config{Host: "https://127.0.0.1"}
+
+			clientset := fake.NewClientset()
+			c.setClientset(clientset)
+
+			fatal, err := c.runLocal(context.Background())
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.wantFatal, fatal)
+
+			statuses := map[string]report.Status{}
+			messages := map[string]string{}
+			skipReasons := map[string]report.SkipReason{}
+
+			for _, r := range c.podResults {
+				statuses[r.Check] = r.Status
+				messages[r.Check] = r.Message
+				skipReasons[r.Check] = r.SkipReason
+			}
+
+			for check, status := range tc.want {
+				assert.Equal(t, status, statuses[check], check)
+			}
+
+			assert.Equal(t, tc.wantMsg, messages[cloudchecker.CheckNameCrossplaneRole])
+			assert.Equal(t, tc.wantSkip, skipReasons[cloudchecker.CheckNameCrossplaneRole])
+
+			for _, action := range clientset.Actions() {
+				assert.Contains(t, []string{"get", "list"}, action.GetVerb(), "unexpected %s of %s", action.GetVerb(), action.GetResource().Resource)
+			}
+		})
+	}
+}
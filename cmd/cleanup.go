@@ -0,0 +1,334 @@
+// Package cmd is the package that contains all of the commands for the application.
+package cmd
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/clock"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/k8s/kubeutil"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/util"
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+	"go.uber.org/multierr"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+var (
+	// errFailedToListManagedResources is the error that is returned when the resources managed by the application cannot be listed.
+	errFailedToListManagedResources = errors.New("failed to list managed resources")
+
+	// errFailedToDeleteManagedResource is the error that is returned when a resource managed by the application cannot be deleted.
+	errFailedToDeleteManagedResource = errors.New("failed to delete managed resource")
+)
+
+// flagRetryBudget is the flag for the maximum total time that all of the retries of the cleanup sleep for.
+const flagRetryBudget = "retry-budget"
+
+// managedResource is a resource in the cluster that carries the label of being managed by the application.
+type managedResource struct {
+	// kind is the kind of the resource.
+	kind string
+	// namespace is the namespace of the resource, empty for cluster-scoped resources.
+	namespace string
+	// name is the name of the resource.
+	name string
+	// runID is the identifier of the run that created the resource.
+	runID string
+}
+
+// String returns the human-readable representation of the resource.
+func (r managedResource) String() string {
+	if r.namespace == constant.EmptyString {
+		return r.kind + " " + r.name
+	}
+
+	return r.kind + " " + r.namespace + "/" + r.name
+}
+
+// cleanupCmd is the command to clean up all resources left in the cluster by the application.
+type cleanupCmd struct {
+	// logger is the logger.
+	logger *log.Logger
+	// cobraCmd is the Cobra command.
+	cobraCmd *cobra.Command
+
+	// clientset is the Kubernetes clientset.
+	clientset kubernetes.Interface
+	// retryPolicy is the policy of retrying the requests to the Kubernetes API server that fail transiently.
+	retryPolicy util.BackoffPolicy
+	// retryBudget is the budget of the total time that all of the retries of the cleanup sleep for.
+	retryBudget *util.RetryBudget
+	// clock is the clock that the retries sleep on.
+	clock clock.Clock
+}
+
+var _ cmd = &cleanupCmd{}
+
+// managedBySelector returns the label selector matching the resources managed by the application.
+func managedBySelector() string {
+	return labels.SelectorFromSet(labels.Set{constant.LabelManagedBy: constant.AppName}).String()
+}
+
+// listResources lists the resources managed by the application, in the order in which they are supposed to be deleted.
+//
+// nolint:funlen
+func (c *cleanupCmd) listResources(ctx context.Context) ([]managedResource, error) {
+	opts := metav1.ListOptions{LabelSelector: managedBySelector()}
+
+	var resources []managedResource
+
+	add := func(kind string, obj metav1.Object) {
+		resources = append(resources, managedResource{
+			kind:      kind,
+			namespace: obj.GetNamespace(),
+			name:      obj.GetName(),
+			runID:     obj.GetLabels()[constant.LabelRunID],
+		})
+	}
+
+	pods, err := c.clientset.CoreV1().Pods(metav1.NamespaceAll).List(ctx, opts)
+	if err != nil {
+		return nil, multierr.Combine(errFailedToListManagedResources, err)
+	}
+
+	for i := range pods.Items {
+		add("Pod", &pods.Items[i])
+	}
+
+	secrets, err := c.clientset.CoreV1().Secrets(metav1.NamespaceAll).List(ctx, opts)
+	if err != nil {
+		return nil, multierr.Combine(errFailedToListManagedResources, err)
+	}
+
+	for i := range secrets.Items {
+		add("Secret", &secrets.Items[i])
+	}
+
+	clusterRoleBindings, err := c.clientset.RbacV1().ClusterRoleBindings().List(ctx, opts)
+	if err != nil {
+		return nil, multierr.Combine(errFailedToListManagedResources, err)
+	}
+
+	for i := range clusterRoleBindings.Items {
+		add("ClusterRoleBinding", &clusterRoleBindings.Items[i])
+	}
+
+	clusterRoles, err := c.clientset.RbacV1().ClusterRoles().List(ctx, opts)
+	if err != nil {
+		return nil, multierr.Combine(errFailedToListManagedResources, err)
+	}
+
+	for i := range clusterRoles.Items {
+		add("ClusterRole", &clusterRoles.Items[i])
+	}
+
+	roleBindings, err := c.clientset.RbacV1().RoleBindings(metav1.NamespaceAll).List(ctx, opts)
+	if err != nil {
+		return nil, multierr.Combine(errFailedToListManagedResources, err)
+	}
+
+	for i := range roleBindings.Items {
+		add("RoleBinding", &roleBindings.Items[i])
+	}
+
+	roles, err := c.clientset.RbacV1().Roles(metav1.NamespaceAll).List(ctx, opts)
+	if err != nil {
+		return nil, multierr.Combine(errFailedToListManagedResources, err)
+	}
+
+	for i := range roles.Items {
+		add("Role", &roles.Items[i])
+	}
+
+	serviceAccounts, err := c.clientset.CoreV1().ServiceAccounts(metav1.NamespaceAll).List(ctx, opts)
+	if err != nil {
+		return nil, multierr.Combine(errFailedToListManagedResources, err)
+	}
+
+	for i := range serviceAccounts.Items {
+		add("ServiceAccount", &serviceAccounts.Items[i])
+	}
+
+	return resources, nil
+}
+
+// deleteResource deletes the resource, ignoring it if it no longer exists and retrying the transient failures.
+func (c *cleanupCmd) deleteResource(ctx context.Context, r managedResource) error {
+	opts := metav1.DeleteOptions{}
+
+	err := util.RetryWithBackoff(ctx, c.clock, c.retryPolicy, c.retryBudget, kubeutil.IsRetryable, func(ctx context.Context) error {
+		switch r.kind {
+		case "Pod":
+			return c.clientset.CoreV1().Pods(r.namespace).Delete(ctx, r.name, opts)
+		case "Secret":
+			return c.clientset.CoreV1().Secrets(r.namespace).Delete(ctx, r.name, opts)
+		case "ClusterRoleBinding":
+			return c.clientset.RbacV1().ClusterRoleBindings().Delete(ctx, r.name, opts)
+		case "ClusterRole":
+			return c.clientset.RbacV1().ClusterRoles().Delete(ctx, r.name, opts)
+		case "RoleBinding":
+			return c.clientset.RbacV1().RoleBindings(r.namespace).Delete(ctx, r.name, opts)
+		case "Role":
+			return c.clientset.RbacV1().Roles(r.namespace).Delete(ctx, r.name, opts)
+		case "ServiceAccount":
+			return c.clientset.CoreV1().ServiceAccounts(r.namespace).Delete(ctx, r.name, opts)
+		}
+
+		return nil
+	})
+
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return multierr.Combine(errFailedToDeleteManagedResource, err)
+	}
+
+	return nil
+}
+
+// run is the run function for the Cleanup command.
+func (c *cleanupCmd) run(cobraCmd *cobra.Command, _ []string) {
+	const (
+		// logMsgNoManagedResources is the message that is logged when there are no resources to clean up.
+		logMsgNoManagedResources = "no resources managed by " + constant.AppName + " found"
+
+		// logMsgManagedResourcesFound is the message that is logged before the list of the resources to clean up.
+		logMsgManagedResourcesFound = "found %d resources managed by " + constant.AppName + ":"
+
+		// logMsgManagedResource is the message that is logged for each resource to clean up.
+		logMsgManagedResource = "  - %s (run ID: %s)"
+
+		// confirmationQuestion is the question that is asked to confirm the deletion.
+		confirmationQuestion = "Delete the resources listed above?"
+
+		// logMsgManagedResourceDeleted is the message that is logged when a resource is deleted.
+		logMsgManagedResourceDeleted = "deleted %s"
+	)
+
+	retryPolicy, err := retryPolicy(cobraCmd)
+	if err != nil {
+		c.logger.Fatal(err)
+	}
+
+	c.retryPolicy = retryPolicy
+
+	if c.retryBudget, err = util.NewRetryBudget(util.FlagDuration(cobraCmd, flagRetryBudget)); err != nil {
+		c.logger.Fatal(err)
+	}
+
+	kubeConfig, path, err := kubeutil.Config(util.Flag(cobraCmd, flagKubeConfig), util.Flag(cobraCmd, flagKubeConfigData))
+	if err != nil {
+		c.logger.Fatal(multierr.Combine(errFailedToGetKubeConfig, err))
+	}
+
+	c.logger.Debugf(logMsgKubeLoadedConfig, path)
+
+	if path != kubeutil.PathInCluster && path != kubeutil.PathData {
+		if kubeContext, err := kubeutil.CurrentContext(path); err == nil {
+			c.logger.Debugf(logMsgKubeCurrentContext, kubeContext)
+		}
+	}
+
+	if c.clientset, err = kubernetes.NewForConfig(kubeConfig); err != nil {
+		c.logger.Fatal(multierr.Combine(errFailedToCreateKubernetesClientset, err))
+	}
+
+	c.logger.Debug(logMsgKubeClientsetCreated)
+
+	ctx := context.Background()
+
+	var resources []managedResource
+
+	if err := util.RetryWithBackoff(ctx, c.clock, c.retryPolicy, c.retryBudget, kubeutil.IsRetryable, func(ctx context.Context) (err error) {
+		resources, err = c.listResources(ctx)
+
+		return err
+	}); err != nil {
+		c.logger.Fatal(err)
+	}
+
+	if len(resources) == 0 {
+		c.logger.Info(logMsgNoManagedResources)
+
+		return
+	}
+
+	c.logger.Infof(logMsgManagedResourcesFound, len(resources))
+
+	for _, r := range resources {
+		c.logger.Infof(logMsgManagedResource, r, r.runID)
+	}
+
+	if err := confirm(cobraCmd, cobraCmd.ErrOrStderr(), confirmationQuestion); err != nil {
+		c.logger.Fatal(err)
+	}
+
+	for _, r := range resources {
+		if err := c.deleteResource(ctx, r); err != nil {
+			c.logger.Fatal(err)
+		}
+
+		c.logger.Infof(logMsgManagedResourceDeleted, r)
+	}
+}
+
+// flags sets the flags for the Cleanup command.
+func (c *cleanupCmd) flags() {
+	c.cobraCmd.Flags().String(
+		flagKubeConfig,
+		constant.EmptyString,
+		"path to the Kubernetes configuration file to use for the cleanup (or KUBECONFIG environment variable)",
+	)
+	c.cobraCmd.Flags().String(
+		flagKubeConfigData,
+		constant.EmptyString,
+		"base64 encoded Kubernetes configuration to use for the cleanup, taking precedence over --"+flagKubeConfig+" (or KUBECONFIG_DATA environment variable)",
+	)
+	util.MarkFlagSensitive(c.cobraCmd, flagKubeConfigData)
+
+	// defaultRetryBudget is the default maximum total time that all of the retries of the cleanup sleep for.
+	const defaultRetryBudget = 5 * time.Minute
+
+	c.cobraCmd.Flags().Duration(
+		flagRetryBudget,
+		defaultRetryBudget,
+		"maximum total time that all of the retries of the cleanup sleep for, after which the failing deletions are no longer retried, 0 for no limit",
+	)
+
+	addYesFlag(c.cobraCmd)
+}
+
+// newCleanupCmd returns a new cleanupCmd.
+func newCleanupCmd(logger *log.Logger, cobraCmd *cobra.Command) *cleanupCmd {
+	return &cleanupCmd{
+		logger:   logger,
+		cobraCmd: cobraCmd,
+		clock:    clock.Real{},
+	}
+}
+
+// Cleanup returns a Cobra command to clean up all resources left in the cluster by the application.
+func Cleanup(logger *log.Logger) *cobra.Command {
+	cobraCmd := &cobra.Command{
+		Use:   "cleanup",
+		Short: "Clean up the resources left in the cluster",
+		Long: `Cleanup lists all resources labeled as managed by the application across all namespaces, such as the ones left behind by crashed checks,
+and deletes them.
+
+Unlike the --` + flagCleanupOnly + ` flag of the check command, it does not depend on the names of the resources. It asks for confirmation before deleting
+the resources when running in a terminal, and requires the --` + flagYes + ` flag otherwise.`,
+		Args: cobra.NoArgs,
+	}
+
+	cmd := newCleanupCmd(logger, cobraCmd)
+
+	cobraCmd.Run = cmd.run
+
+	cmd.flags()
+
+	return cobraCmd
+}
@@ -0,0 +1,187 @@
+// Package cmd is the package that contains all of the commands for the application.
+package cmd
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/clock"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/util"
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// managedObjectMeta is a function that returns the object metadata of a resource managed by the application for testing.
+func managedObjectMeta(name string, namespace string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      name,
+		Namespace: namespace,
+		Labels: map[string]string{
+			constant.LabelManagedBy: constant.AppName,
+			constant.LabelRunID:     "testrun1",
+		},
+	}
+}
+
+// setupCleanupCmdTest is a function that sets up a cleanupCmd for testing, with a fake clientset containing the given objects.
+func setupCleanupCmdTest(objects ...runtime.Object) (*cleanupCmd, *fake.Clientset) {
+	clientset := fake.NewClientset(objects...)
+
+	c := newCleanupCmd(log.New(io.Discard), &cobra.Command{})
+	c.clientset = clientset
+
+	return c, clientset
+}
+
+// testCleanupObjects is a function that returns a set of managed and unmanaged objects for testing.
+func testCleanupObjects() []runtime.Object {
+	return []runtime.Object{
+		&corev1.Pod{ObjectMeta: managedObjectMeta("managed-pod", "custom")},
+		&corev1.ServiceAccount{ObjectMeta: managedObjectMeta("managed-sa", "custom")},
+		&corev1.Secret{ObjectMeta: managedObjectMeta("managed-secret", "custom")},
+		&rbacv1.Role{ObjectMeta: managedObjectMeta("managed-role", constant.NamespaceMySQL)},
+		&rbacv1.RoleBinding{ObjectMeta: managedObjectMeta("managed-rolebinding", constant.NamespaceMySQL)},
+		&rbacv1.ClusterRole{ObjectMeta: managedObjectMeta("managed-clusterrole", constant.EmptyString)},
+		&rbacv1.ClusterRoleBinding{ObjectMeta: managedObjectMeta("managed-clusterrolebinding", constant.EmptyString)},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "unmanaged-pod", Namespace: "custom"}},
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{
+			Name:      "other-sa",
+			Namespace: "custom",
+			Labels:    map[string]string{constant.LabelManagedBy: "helm"},
+		}},
+		&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "unmanaged-clusterrole"}},
+	}
+}
+
+// TestCleanupCmd_listResources is a test that tests that only the resources managed by the application are listed, in the deletion order.
+func TestCleanupCmd_listResources(t *testing.T) {
+	c, _ := setupCleanupCmdTest(testCleanupObjects()...)
+
+	got, err := c.listResources(context.Background())
+	require.NoError(t, err)
+
+	want := []managedResource{
+		{kind: "Pod", namespace: "custom", name: "managed-pod", runID: "testrun1"},
+		{kind: "Secret", namespace: "custom", name: "managed-secret", runID: "testrun1"},
+		{kind: "ClusterRoleBinding", name: "managed-clusterrolebinding", runID: "testrun1"},
+		{kind: "ClusterRole", name: "managed-clusterrole", runID: "testrun1"},
+		{kind: "RoleBinding", namespace: constant.NamespaceMySQL, name: "managed-rolebinding", runID: "testrun1"},
+		{kind: "Role", namespace: constant.NamespaceMySQL, name: "managed-role", runID: "testrun1"},
+		{kind: "ServiceAccount", namespace: "custom", name: "managed-sa", runID: "testrun1"},
+	}
+
+	assert.Equal(t, want, got)
+}
+
+// TestCleanupCmd_listResources_empty is a test that tests that nothing is listed when there are no resources managed by the application.
+func TestCleanupCmd_listResources_empty(t *testing.T) {
+	c, _ := setupCleanupCmdTest(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "unmanaged-pod", Namespace: "custom"}})
+
+	got, err := c.listResources(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+// TestCleanupCmd_deleteResource is a test that tests that the listed resources are deleted and the unmanaged ones are left untouched.
+func TestCleanupCmd_deleteResource(t *testing.T) {
+	ctx := context.Background()
+
+	c, clientset := setupCleanupCmdTest(testCleanupObjects()...)
+
+	resources, err := c.listResources(ctx)
+	require.NoError(t, err)
+
+	for _, r := range resources {
+		require.NoError(t, c.deleteResource(ctx, r), r.String())
+	}
+
+	remaining, err := c.listResources(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, remaining)
+
+	_, err = clientset.CoreV1().Pods("custom").Get(ctx, "unmanaged-pod", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	_, err = clientset.CoreV1().ServiceAccounts("custom").Get(ctx, "other-sa", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	_, err = clientset.RbacV1().ClusterRoles().Get(ctx, "unmanaged-clusterrole", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	// Deleting an already deleted resource is not an error.
+	for _, r := range resources {
+		assert.NoError(t, c.deleteResource(ctx, r), r.String())
+	}
+}
+
+// TestManagedResource_String is a test that tests the String function of the managedResource.
+func TestManagedResource_String(t *testing.T) {
+	testCases := []struct {
+		name     string
+		resource managedResource
+		want     string
+	}{
+		{
+			name:     "Namespaced",
+			resource: managedResource{kind: "Pod", namespace: "default", name: "pod"},
+			want:     "Pod default/pod",
+		},
+		{
+			name:     "Cluster-scoped",
+			resource: managedResource{kind: "ClusterRole", name: "role"},
+			want:     "ClusterRole role",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.resource.String())
+		})
+	}
+}
+
+// TestCleanupCmd_deleteResource_retry is a test that tests that the deletion of a resource is retried when the Kubernetes API server throttles it.
+func TestCleanupCmd_deleteResource_retry(t *testing.T) {
+	ctx := context.Background()
+
+	c, clientset := setupCleanupCmdTest(&corev1.Pod{ObjectMeta: managedObjectMeta("managed-pod", "custom")})
+
+	policy, err := util.NewBackoffPolicy(2, time.Minute)
+	require.NoError(t, err)
+
+	clk := clock.NewFake(time.Time{})
+
+	c.retryPolicy = policy
+	c.clock = clk
+
+	var attempts int
+
+	clientset.PrependReactor("delete", "pods", func(k8stesting.Action) (bool, runtime.Object, error) {
+		attempts++
+
+		if attempts == 1 {
+			return true, nil, k8serrors.NewTooManyRequests("throttled", 1)
+		}
+
+		return false, nil, nil
+	})
+
+	require.NoError(t, c.deleteResource(ctx, managedResource{kind: "Pod", namespace: "custom", name: "managed-pod"}))
+	assert.Equal(t, 2, attempts)
+	assert.Len(t, clk.Sleeps(), 1)
+
+	_, err = clientset.CoreV1().Pods("custom").Get(ctx, "managed-pod", metav1.GetOptions{})
+	assert.True(t, k8serrors.IsNotFound(err))
+}
@@ -2,9 +2,19 @@
 package cmd
 
 import (
+	"encoding/json"
 	"errors"
+	"time"
 
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/clock"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/envconfig"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/podprotocol"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/report"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/util"
+	"github.com/charmbracelet/log"
 	"github.com/spf13/cobra"
+	"golang.org/x/net/http/httpproxy"
 )
 
 var (
@@ -16,29 +26,272 @@ var (
 
 	// errFailedToCreateKubernetesClientset is the error that is returned when the Kubernetes clientset cannot be created.
 	errFailedToCreateKubernetesClientset = errors.New("failed to create Kubernetes clientset")
+
+	// errFailedToReadDBCABundle is the error that is returned when the CA bundle for the database TLS connections cannot be read.
+	errFailedToReadDBCABundle = errors.New("failed to read database CA bundle")
+
+	// errFailedToReadCABundle is the error that is returned when the CA bundle for the HTTPS requests cannot be read.
+	errFailedToReadCABundle = errors.New("failed to read CA bundle")
+
+	// errFailedToReadExpectedPermissions is the error that is returned when the file that overrides the expected permissions cannot be read.
+	errFailedToReadExpectedPermissions = errors.New("failed to read expected permissions")
+
+	// errInvalidConnectTimeout is the error that is returned when the connect timeout is not positive.
+	errInvalidConnectTimeout = errors.New("invalid connect timeout: must be positive")
+
+	// errInvalidMaxClockSkew is the error that is returned when the maximum clock skew is negative.
+	errInvalidMaxClockSkew = errors.New("invalid maximum clock skew: must not be negative")
+
+	// errInvalidMaxConcurrency is the error that is returned when the maximum concurrency of the checks is not positive.
+	errInvalidMaxConcurrency = errors.New("invalid maximum concurrency: must be positive")
+
+	// errInvalidMySQLExpectedConfig is the error that is returned when the override of the expected MySQL configuration is not a JSON object of strings.
+	errInvalidMySQLExpectedConfig = errors.New("invalid override of the expected MySQL configuration: must be a JSON object of strings")
+
+	// errInvalidPostgreSQLExpectedConfig is the error that is returned when the override of the expected PostgreSQL configuration is not a JSON object of
+	// strings.
+	errInvalidPostgreSQLExpectedConfig = errors.New("invalid override of the expected PostgreSQL configuration: must be a JSON object of strings")
+
+	// errInvalidSecretKeys is the error that is returned when the keys of a secret that replace the default ones are not a JSON object of strings.
+	errInvalidSecretKeys = errors.New("invalid keys of the secret: must be a JSON object of strings")
 )
 
+// defaultConnectTimeout is the default maximum duration of establishing a connection to the endpoints outside of the cluster.
+const defaultConnectTimeout = 30 * time.Second
+
+// defaultMaxClockSkew is the default maximum difference between the clock of the Pod and the one of the OIDC server that is not warned about.
+const defaultMaxClockSkew = time.Minute
+
+// defaultMaxConcurrency is the default maximum number of the checks of the cloud that the pod runs at once, which runs them one at a time.
+const defaultMaxConcurrency = 1
+
 const (
 	// logMsgKubeLoadedConfig is the message that is logged when the Kubernetes configuration is loaded from the specified path.
 	logMsgKubeLoadedConfig = "loaded Kubernetes configuration from %s"
 
+	// logMsgKubeCurrentContext is the message that is logged with the current context of the loaded Kubernetes configuration.
+	logMsgKubeCurrentContext = "using Kubernetes context %s"
+
 	// logMsgKubeClientsetCreated is the message that is logged when the Kubernetes clientset is created.
 	logMsgKubeClientsetCreated = "created Kubernetes clientset from configuration"
+
+	// logMsgPodVersion is the message that the pods older than the pod protocol log first, along with their version, so that the Check command can detect
+	// a version skew.
+	logMsgPodVersion = "pod version"
+
+	// logMsgPodResults is the message that the pods older than the pod protocol log once the checks are over, along with their results, so that the Check
+	// command can write them to the ConfigMap.
+	logMsgPodResults = "pod results"
+
+	// logKeyCluster is the key of the cluster name, which every log line carries once the environment configuration is read.
+	logKeyCluster = "cluster"
+
+	// logKeyProvider is the key of the cloud provider, which every log line carries once the environment configuration is read.
+	logKeyProvider = "provider"
 )
 
 const (
 	// envVarEnvConfig is the name of the environment variable that contains the base64 encoded environment configuration.
 	envVarEnvConfig = "ENVCONFIG"
 
-	// envVarGoogleCloudSDKDockerRepo is the name of the environment variable that contains the Docker repository for the Google Cloud SDK.
+	// envVarGoogleCloudSDKDockerRepo is the name of the environment variable that contains the Docker repository for the Google Cloud SDK, e.g. google.
+	//
+	// Joined with the image, it must form a reference in the repo/image[:tag|@digest] format.
 	envVarGoogleCloudSDKDockerRepo = "GOOGLE_CLOUD_SDK_DOCKER_REPO"
 
-	// envVarGoogleCloudSDKDockerImage is the name of the environment variable that contains the Docker image for the Google Cloud SDK.
+	// envVarGoogleCloudSDKDockerImage is the name of the environment variable that contains the Docker image for the Google Cloud SDK, e.g. cloud-sdk:latest.
 	envVarGoogleCloudSDKDockerImage = "GOOGLE_CLOUD_SDK_DOCKER_IMAGE"
+
+	// envVarRoleOnly is the name of the environment variable that indicates that only the Crossplane role check should be performed.
+	envVarRoleOnly = "ROLE_ONLY"
+
+	// envVarDBCABundle is the name of the environment variable that contains the base64 encoded CA bundle for the database TLS connections.
+	envVarDBCABundle = "DB_CA_BUNDLE"
+
+	// envVarCABundle is the name of the environment variable that contains the base64 encoded CA bundle that the servers of the HTTPS requests are verified
+	// against along with the CAs of the system.
+	envVarCABundle = "CA_BUNDLE"
+
+	// envVarPodSecurityProfile is the name of the environment variable that contains the security profile of the pods that the pod creates.
+	envVarPodSecurityProfile = "POD_SECURITY_PROFILE"
+
+	// envVarMySQLExpectedConfig is the name of the environment variable that contains the override of the expected MySQL configuration, as a JSON object
+	// of the values of the system variables by name.
+	envVarMySQLExpectedConfig = "MYSQL_EXPECTED_CONFIG"
+
+	// envVarPostgreSQLExpectedConfig is the name of the environment variable that contains the override of the expected PostgreSQL configuration, as a
+	// JSON object of the values of the settings by name.
+	envVarPostgreSQLExpectedConfig = "POSTGRESQL_EXPECTED_CONFIG"
+
+	// envVarCheckSpiceDB is the name of the environment variable that indicates that the capabilities SpiceDB requires from the PostgreSQL user should be
+	// checked.
+	envVarCheckSpiceDB = "CHECK_SPICEDB"
+
+	// envVarConnectTimeout is the name of the environment variable that contains the maximum duration of establishing a connection to the endpoints outside
+	// of the cluster, in the format accepted by time.ParseDuration.
+	envVarConnectTimeout = "CONNECT_TIMEOUT"
+
+	// envVarMaxClockSkew is the name of the environment variable that contains the maximum difference between the clock of the pod and the one of the OIDC
+	// server that is not warned about, in the format accepted by time.ParseDuration.
+	envVarMaxClockSkew = "MAX_CLOCK_SKEW"
+
+	// envVarCheckOIDCDiscovery is the name of the environment variable that indicates that the discovery document of the OIDC server should be checked to
+	// support the signing algorithm and the audiences of the JWTs.
+	envVarCheckOIDCDiscovery = "CHECK_OIDC_DISCOVERY"
+
+	// envVarOIDCAudiences is the name of the environment variable that contains the audiences that the discovery document of the OIDC server is checked to
+	// support, separated by commas.
+	envVarOIDCAudiences = "OIDC_AUDIENCES"
+
+	// envVarMaxConcurrency is the name of the environment variable that contains the maximum number of the checks of the cloud that the pod runs at once.
+	envVarMaxConcurrency = "MAX_CONCURRENCY"
+
+	// envVarCheckStorageClassProvisioner is the name of the environment variable that indicates that the provisioner of the default storage class should be
+	// checked.
+	envVarCheckStorageClassProvisioner = "CHECK_STORAGE_CLASS_PROVISIONER"
+
+	// envVarStorageClassProvisioners is the name of the environment variable that contains the provisioners expected of the default storage class,
+	// separated by commas.
+	envVarStorageClassProvisioners = "STORAGE_CLASS_PROVISIONERS"
+
+	// envVarMySQLSecret is the name of the environment variable that contains the name of the secret that contains the MySQL credentials.
+	envVarMySQLSecret = "MYSQL_SECRET"
+	// envVarPostgreSQLSecret is the name of the environment variable that contains the name of the secret that contains the PostgreSQL credentials.
+	envVarPostgreSQLSecret = "POSTGRESQL_SECRET"
+	// envVarSpiceDBDatabase is the name of the environment variable that contains the name of the database that SpiceDB uses.
+	envVarSpiceDBDatabase = "SPICEDB_DATABASE"
+	// envVarSMTPSecret is the name of the environment variable that contains the name of the secret that contains the SMTP credentials.
+	envVarSMTPSecret = "SMTP_SECRET"
+	// envVarMySQLSecretKeys is the name of the environment variable that contains the keys of the MySQL secret that replace the default ones, as a JSON
+	// object of the keys by the default ones.
+	envVarMySQLSecretKeys = "MYSQL_SECRET_KEYS"
+	// envVarPostgreSQLSecretKeys is the name of the environment variable that contains the keys of the PostgreSQL secret that replace the default ones, as
+	// a JSON object of the keys by the default ones.
+	envVarPostgreSQLSecretKeys = "POSTGRESQL_SECRET_KEYS"
+	// envVarSMTPSecretKeys is the name of the environment variable that contains the keys of the SMTP secret that replace the default ones, as a JSON
+	// object of the keys by the default ones.
+	envVarSMTPSecretKeys = "SMTP_SECRET_KEYS"
+	// envVarCheckSMTPConnection is the name of the environment variable that indicates that the SMTP server should be connected to and authenticated with.
+	envVarCheckSMTPConnection = "CHECK_SMTP_CONNECTION"
+
+	// envVarSSOSecrets is the name of the environment variable that contains the names of the secrets that contain the SSO configurations, separated by
+	// commas.
+	envVarSSOSecrets = "SSO_SECRETS"
+
+	// envVarSSOSecretSelector is the name of the environment variable that contains the label selector of the secrets that contain the SSO configurations.
+	envVarSSOSecretSelector = "SSO_SECRET_SELECTOR"
+
+	// envVarSSOSAMLKeys is the name of the environment variable that contains the keys that the SAML SSO configurations are required to have besides the
+	// entity ID, separated by commas.
+	envVarSSOSAMLKeys = "SSO_SAML_KEYS"
+
+	// envVarCrossplaneNamespace is the name of the environment variable that contains the namespace of the service accounts of the Crossplane providers, which
+	// the Crossplane role trusts.
+	envVarCrossplaneNamespace = "CROSSPLANE_NAMESPACE"
+
+	// envVarCrossplaneServiceAccountsPrefix is the name of the environment variable that contains the prefix of the names of the service accounts of the
+	// Crossplane providers that the AWS Crossplane role trusts.
+	envVarCrossplaneServiceAccountsPrefix = "CROSSPLANE_SERVICE_ACCOUNTS_PREFIX"
+
+	// envVarAllowedExtraPolicyStatements is the name of the environment variable that contains the SIDs of the statements that the policies of the AWS
+	// Crossplane role may have on top of the expected ones.
+	envVarAllowedExtraPolicyStatements = "ALLOWED_EXTRA_POLICY_STATEMENTS"
+
+	// envVarExpectedPermissions is the name of the environment variable that contains the base64 encoded override of the expected permissions of the
+	// Crossplane role in Azure and GCP.
+	envVarExpectedPermissions = "EXPECTED_PERMISSIONS"
+
+	// envVarRequiresGPU is the name of the environment variable that indicates whether the deployment requires GPU nodes, overriding spec.requiresGPU of
+	// the environment configuration if set.
+	envVarRequiresGPU = "REQUIRES_GPU"
+
+	// envVarNamespacePrefix is the name of the environment variable that contains the prefix of the namespaces of the install that the secrets are read
+	// from.
+	envVarNamespacePrefix = "NAMESPACE_PREFIX"
+
+	// envVarReportResults is the name of the environment variable that indicates that the results of the checks should be logged for the Check command to
+	// write them to the ConfigMap.
+	envVarReportResults = "REPORT_RESULTS"
+
+	// envVarHTTPSProxy is the name of the environment variable that contains the HTTPS proxy of the requests to the endpoints outside of the cluster, the
+	// standard one that the HTTP clients read.
+	envVarHTTPSProxy = "HTTPS_PROXY"
+
+	// envVarNoProxy is the name of the environment variable that contains the hosts that bypass the HTTPS proxy, separated by commas, the standard one
+	// that the HTTP clients read.
+	envVarNoProxy = "NO_PROXY"
 )
 
+// listSeparator is the separator of the values of the environment variables that contain lists.
+const listSeparator = ","
+
+// encodeKeyValues is the function that returns the value of the environment variable of the map, as a JSON object with its keys in order so that the value
+// is the same on every run, or an empty string if the map is empty; unlike a list of key=value pairs, it keeps the values that contain the list separator
+// intact, e.g. the sql_mode of MySQL.
+func encodeKeyValues(m map[string]string) string {
+	if len(m) == 0 {
+		return constant.EmptyString
+	}
+
+	// A map of strings is always encoded, with its keys sorted.
+	data, _ := json.Marshal(m)
+
+	return string(data)
+}
+
 // cmd is the interface that all commands must implement.
 type cmd interface {
 	// run is the run function for the command.
 	run(*cobra.Command, []string)
 }
+
+// logProxyConfig is the function that logs the HTTPS proxy that the requests to the endpoints outside of the cluster go through, if any, so that whether a
+// proxy is in effect can be told from the logs.
+func logProxyConfig(logger *log.Logger, proxyConfig *httpproxy.Config) {
+	const (
+		// logMsgHTTPSProxy is the message that is logged when an HTTPS proxy is in effect.
+		logMsgHTTPSProxy = "using HTTPS proxy %s, bypassed for %q"
+
+		// logMsgNoHTTPSProxy is the message that is logged when no HTTPS proxy is in effect.
+		logMsgNoHTTPSProxy = "not using an HTTPS proxy"
+	)
+
+	if proxyConfig.HTTPSProxy == constant.EmptyString {
+		logger.Debug(logMsgNoHTTPSProxy)
+
+		return
+	}
+
+	// The password of the proxy, if any, is not logged.
+	logger.Infof(logMsgHTTPSProxy, util.RedactedProxy(proxyConfig.HTTPSProxy), proxyConfig.NoProxy)
+}
+
+// withEnvConfigFields is the function that returns a child of the logger that adds the cluster name and the cloud provider of the environment configuration
+// to every log line, so that the logs aggregated from many clusters can be filtered per environment.
+func withEnvConfigFields(logger *log.Logger, envConfig *envconfig.EnvConfig) *log.Logger {
+	return logger.With(logKeyCluster, envConfig.Spec.ClusterName, logKeyProvider, envConfig.Spec.CloudSpec.Provider)
+}
+
+// writeResultMessages is the function that writes the messages of the pod protocol of each of the results from the index on, followed by the one of the
+// summary of all of them; the results before the index are the ones whose messages were written as they were added, by resultMessagesCollector.
+func writeResultMessages(messages *podprotocol.Writer, results []report.Result, from int) error {
+	for _, r := range results[from:] {
+		if err := messages.Write(podprotocol.PodMessage{Type: podprotocol.TypeCheckResult, Result: &r}); err != nil {
+			return err
+		}
+	}
+
+	summary := report.Summarize(results)
+
+	return messages.Write(podprotocol.PodMessage{Type: podprotocol.TypeSummary, Summary: &summary})
+}
+
+// resultMessagesCollector is the function that returns the collector of the results that writes the message of the pod protocol of each of them as soon
+// as it is added, so that the results are printed as each check finishes, logging the errors of writing it with the logger.
+func resultMessagesCollector(clk clock.Clock, messages *podprotocol.Writer, logger *log.Logger) *report.Collector {
+	return report.NewStreamingCollector(clk, func(r report.Result) {
+		if err := messages.Write(podprotocol.PodMessage{Type: podprotocol.TypeCheckResult, Result: &r}); err != nil {
+			logger.Error(err)
+		}
+	})
+}
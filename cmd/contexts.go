@@ -0,0 +1,230 @@
+// Package cmd is the package that contains all of the commands for the application.
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/envconfig"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/k8s/kubeutil"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/util"
+	"go.uber.org/multierr"
+	"golang.org/x/sync/errgroup"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+)
+
+var (
+	// errInvalidMaxContextConcurrency is the error that is returned when the maximum number of the contexts that are checked at once is not positive.
+	errInvalidMaxContextConcurrency = errors.New("invalid maximum context concurrency: must be positive")
+
+	// errDuplicateContextCluster is the error that is returned when several of the contexts to check are of the same cluster, whose checks would conflict
+	// over the resources they create.
+	errDuplicateContextCluster = errors.New("contexts of the same cluster")
+)
+
+const (
+	// flagContexts is the name of the flag for the contexts of the Kubernetes configuration whose clusters are checked, rather than the current one.
+	flagContexts = "contexts"
+	// flagMaxContextConcurrency is the name of the flag for the maximum number of the contexts that are checked at once.
+	flagMaxContextConcurrency = "max-context-concurrency"
+)
+
+// defaultMaxContextConcurrency is the default maximum number of the contexts that are checked at once.
+const defaultMaxContextConcurrency = 4
+
+// logKeyContext is the key of the context of the Kubernetes configuration, which every log line of the check of a cluster carries when several are checked.
+const logKeyContext = "context"
+
+// contextRun is the type that contains the check of the cluster of a context of the Kubernetes configuration, and its outcome.
+type contextRun struct {
+	// c is the command that checks the cluster, with its own clientsets, warnings and results.
+	c *checkCmd
+	// fatal is whether the check failed fatally.
+	fatal bool
+	// failed is whether the check failed.
+	failed bool
+	// err is the error that the check could not finish with, or nil.
+	err error
+}
+
+// contextEnvConfigSeparator is the separator between the name of a context of the contexts flag and the path of the environment configuration of its
+// cluster.
+const contextEnvConfigSeparator = "="
+
+// contextRuns is the function that returns the checks of the clusters of the contexts, each with the Kubernetes configuration of its context and a run
+// identifier of its own, in the order of the contexts.
+//
+// A context given as name=path is checked with the environment configuration of the path, and the other ones with the one of the command.
+//
+// It returns an error if the Kubernetes configuration of a context cannot be loaded or its clientsets cannot be created, if the environment configuration
+// of a context cannot be read, or if several contexts are of the same cluster.
+func (c *checkCmd) contextRuns(kubeContexts []string) ([]*contextRun, error) {
+	// logMsgContextEnvConfigRead is the message that is logged when the environment configuration of the context is read from the specified path.
+	const logMsgContextEnvConfigRead = "read environment configuration of %s context from %s"
+
+	var (
+		runs     []*contextRun
+		clusters = map[string]string{}
+	)
+
+	for _, value := range kubeContexts {
+		kubeContext, envConfigPath, _ := strings.Cut(value, contextEnvConfigSeparator)
+
+		envConfig := c.envConfig
+
+		if envConfigPath != constant.EmptyString {
+			var err error
+
+			envConfig, err = envconfig.NewFromPath(envConfigPath)
+			if err != nil {
+				return nil, multierr.Combine(fmt.Errorf("%w of %s context", errFailedToReadEnvConfig, kubeContext), err)
+			}
+
+			c.logger.Debugf(logMsgContextEnvConfigRead, kubeContext, envConfigPath)
+		}
+
+		kubeConfig, path, err := kubeutil.ContextConfig(util.Flag(c.cobraCmd, flagKubeConfig), util.Flag(c.cobraCmd, flagKubeConfigData), kubeContext)
+		if err != nil {
+			return nil, multierr.Combine(errFailedToGetKubeConfig, err)
+		}
+
+		if other, ok := clusters[kubeConfig.Host]; ok {
+			return nil, fmt.Errorf("%w %s: %s and %s", errDuplicateContextCluster, kubeConfig.Host, other, kubeContext)
+		}
+
+		clusters[kubeConfig.Host] = kubeContext
+
+		cc := &checkCmd{
+			logger:         withEnvConfigFields(c.logger.With(logKeyContext, kubeContext), envConfig),
+			cobraCmd:       c.cobraCmd,
+			envConfig:      envConfig,
+			kubeConfig:     kubeConfig,
+			kubeContext:    kubeContext,
+			runID:          utilrand.String(runIDLength),
+			startedAt:      c.startedAt,
+			clock:          c.clock,
+			newClientset:   c.newClientset,
+			registryClient: c.registryClient,
+			proxyConfig:    c.proxyConfig,
+			rootCAs:        c.rootCAs,
+			caBundle:       c.caBundle,
+			exitOnWarnings: c.exitOnWarnings,
+		}
+
+		cc.logger.Debugf(logMsgKubeLoadedConfig, path)
+		cc.logger.Debugf(logMsgKubeCurrentContext, kubeContext)
+
+		if err := cc.setupClientsets(); err != nil {
+			return nil, err
+		}
+
+		runs = append(runs, &contextRun{c: cc})
+	}
+
+	return runs, nil
+}
+
+// checkContexts is the function that checks the clusters of the runs, up to the maximum context concurrency of the flags at once, each within the timeout
+// on its own, and writes the results of each to the ConfigMap in its cluster if the name of the ConfigMap is not empty.
+//
+// A cluster whose check fails does not stop the checks of the others.
+func (c *checkCmd) checkContexts(runs []*contextRun, timeout time.Duration, resultsConfigMapNamespace string, resultsConfigMapName string) {
+	var g errgroup.Group
+
+	g.SetLimit(util.FlagInt(c.cobraCmd, flagMaxContextConcurrency))
+
+	for _, run := range runs {
+		g.Go(func() error {
+			ctx, cancel := withOptionalTimeout(commandContext(c.cobraCmd), timeout)
+			defer cancel()
+
+			run.fatal, run.failed, run.err = run.c.runCluster(ctx)
+
+			if run.err == nil && resultsConfigMapName != constant.EmptyString {
+				if err := run.c.writeResultsConfigMap(ctx, resultsConfigMapNamespace, resultsConfigMapName, run.failed); err != nil {
+					run.err = run.c.timedOut(ctx, err)
+				}
+			}
+
+			return nil
+		})
+	}
+
+	_ = g.Wait()
+}
+
+// summarizeContexts is the function that logs the outcome of the check of the cluster of each of the runs, and counts their warnings as the ones of the
+// command.
+//
+// It returns whether any of the checks failed fatally, or could not finish, and whether any of them failed.
+func (c *checkCmd) summarizeContexts(runs []*contextRun) (bool, bool) {
+	const (
+		// logMsgContextPassed is the message that is logged when the check of the cluster of the context passed.
+		logMsgContextPassed = "%s context: infrastructure check passed"
+
+		// logMsgContextPassedWithWarnings is the message that is logged when the check of the cluster of the context passed but logged warnings.
+		logMsgContextPassedWithWarnings = "%s context: infrastructure check passed with %d warning(s)"
+
+		// logMsgContextFailed is the message that is logged when the check of the cluster of the context failed.
+		logMsgContextFailed = "%s context: infrastructure check failed"
+
+		// logMsgContextNotFinished is the message that is logged when the check of the cluster of the context could not finish.
+		logMsgContextNotFinished = "%s context: infrastructure check did not finish: %v"
+	)
+
+	var fatal, failed bool
+
+	c.warnings = 0
+
+	for _, run := range runs {
+		c.warnings += run.c.warnings
+
+		switch {
+		case run.err != nil:
+			fatal = true
+
+			c.logger.Errorf(logMsgContextNotFinished, run.c.kubeContext, run.err)
+		case run.fatal, run.failed:
+			fatal = fatal || run.fatal
+			failed = true
+
+			c.logger.Errorf(logMsgContextFailed, run.c.kubeContext)
+		case run.c.warnings > 0:
+			c.logger.Warnf(logMsgContextPassedWithWarnings, run.c.kubeContext, run.c.warnings)
+		default:
+			c.logger.Infof(logMsgContextPassed, run.c.kubeContext)
+		}
+	}
+
+	return fatal, failed
+}
+
+// runContexts checks the clusters of the contexts, rather than the one of the current context, prints the results of each if the flags ask for it, in the
+// order of the contexts, and exits with the status of the check, which fails if the check of any of the clusters fails.
+//
+// It returns only if the checks passed, or passed with warnings and do not exit on them.
+func (c *checkCmd) runContexts(kubeContexts []string, timeout time.Duration, resultsConfigMapNamespace string, resultsConfigMapName string) {
+	if util.FlagInt(c.cobraCmd, flagMaxContextConcurrency) <= 0 {
+		c.logger.Fatal(errInvalidMaxContextConcurrency)
+	}
+
+	runs, err := c.contextRuns(kubeContexts)
+	if err != nil {
+		c.logger.Fatal(err)
+	}
+
+	c.checkContexts(runs, timeout, resultsConfigMapNamespace, resultsConfigMapName)
+
+	if util.FlagBool(c.cobraCmd, flagPrintResults) {
+		for _, run := range runs {
+			if err := run.c.printResults(c.cobraCmd.OutOrStdout(), run.err != nil || run.failed); err != nil {
+				c.logger.Fatal(err)
+			}
+		}
+	}
+
+	c.exit(c.summarizeContexts(runs))
+}
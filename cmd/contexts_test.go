@@ -0,0 +1,297 @@
+// Package cmd is the package that contains all of the commands for the application.
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/cloud"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/envconfig"
+	"github.com/charmbracelet/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+)
+
+// testMultiContextKubeConfig is the Kubernetes configuration file content with the contexts of two clusters, and a third context of the first one.
+const testMultiContextKubeConfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: east
+  cluster:
+    server: https://10.0.1.1:6443
+- name: west
+  cluster:
+    server: https://10.0.2.1:6443
+contexts:
+- name: east
+  context:
+    cluster: east
+    user: test
+- name: east-admin
+  context:
+    cluster: east
+    user: test
+- name: west
+  context:
+    cluster: west
+    user: test
+current-context: east
+users:
+- name: test
+  user:
+    token: test
+`
+
+// lockedBuffer is the type of the buffer that the loggers of the checks of several clusters write to at once, as the child loggers do not share a lock.
+type lockedBuffer struct {
+	// mu is the mutex that guards the buffer.
+	mu sync.Mutex
+	// buf is the buffer.
+	buf bytes.Buffer
+}
+
+// Write is the function that writes the bytes to the buffer.
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+// String is the function that returns the contents of the buffer.
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.String()
+}
+
+// setupContextsTest is the function that returns a new checkCmd reading the Kubernetes configuration with several contexts, whose clientsets are the fake
+// ones of the hosts of the clusters.
+func setupContextsTest(t *testing.T, flags map[string]string, clientsets map[string]kubernetes.Interface) *checkCmd {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config")
+
+	require.NoError(t, os.WriteFile(path, []byte(testMultiContextKubeConfig), 0o600))
+
+	t.Setenv("KUBECONFIG_DATA", constant.EmptyString)
+
+	if flags == nil {
+		flags = map[string]string{}
+	}
+
+	flags[flagKubeConfig] = path
+
+	c := setupCheckCmdTest(t, flags)
+	c.newClientset = func(config *rest.Config) (kubernetes.Interface, error) {
+		if clientset, ok := clientsets[config.Host]; ok {
+			return clientset, nil
+		}
+
+		return fake.NewClientset(), nil
+	}
+
+	return c
+}
+
+// TestCheckCmd_contextRuns is a test that tests that the contextRuns function returns a check of the cluster of each of the contexts, with the Kubernetes
+// configuration of the context and the environment configuration of its path if it has one, and returns an error for the context that does not exist,
+// whose environment configuration cannot be read, or for several contexts of the same cluster.
+func TestCheckCmd_contextRuns(t *testing.T) {
+	envConfigPath := filepath.Join(t.TempDir(), "west.yaml")
+
+	require.NoError(t, os.WriteFile(envConfigPath, []byte(testInventoryAWSEnvConfig), 0o600))
+
+	testCases := []struct {
+		name             string
+		kubeContexts     []string
+		wantNames        []string
+		wantHosts        []string
+		wantClusterNames []string
+		wantErr          error
+	}{
+		{
+			name:             "Several clusters",
+			kubeContexts:     []string{"west", "east"},
+			wantNames:        []string{"west", "east"},
+			wantHosts:        []string{"https://10.0.2.1:6443", "https://10.0.1.1:6443"},
+			wantClusterNames: []string{"test", "test"},
+		},
+		{
+			name:             "Environment configuration of a context",
+			kubeContexts:     []string{"west=" + envConfigPath, "east"},
+			wantNames:        []string{"west", "east"},
+			wantHosts:        []string{"https://10.0.2.1:6443", "https://10.0.1.1:6443"},
+			wantClusterNames: []string{"acme", "test"},
+		},
+		{
+			name:         "Missing environment configuration",
+			kubeContexts: []string{"west=" + filepath.Join(t.TempDir(), "missing.yaml"), "east"},
+			wantErr:      errFailedToReadEnvConfig,
+		},
+		{
+			name:         "Missing context",
+			kubeContexts: []string{"east", "north"},
+			wantErr:      errFailedToGetKubeConfig,
+		},
+		{
+			name:         "Same cluster",
+			kubeContexts: []string{"east", "east-admin"},
+			wantErr:      errDuplicateContextCluster,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := setupContextsTest(t, nil, nil)
+			c.envConfig = &envconfig.EnvConfig{Spec: envconfig.Spec{ClusterName: "test"}}
+
+			runs, err := c.contextRuns(tc.kubeContexts)
+
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, runs, len(tc.kubeContexts))
+
+			for i, run := range runs {
+				assert.Equal(t, tc.wantNames[i], run.c.kubeContext)
+				assert.Equal(t, tc.wantHosts[i], run.c.kubeConfig.Host)
+				assert.Equal(t, tc.wantClusterNames[i], run.c.envConfig.Spec.ClusterName)
+				assert.NotNil(t, run.c.clientset)
+				assert.Len(t, run.c.runID, runIDLength)
+			}
+
+			assert.NotEqual(t, runs[0].c.runID, runs[1].c.runID)
+		})
+	}
+}
+
+// TestCheckCmd_checkContexts is a test that tests that the checkContexts function checks the cluster of each of the contexts on its own, writing its
+// results to the ConfigMap in its cluster, and that the summarizeContexts function logs the outcome of each and counts their warnings.
+func TestCheckCmd_checkContexts(t *testing.T) {
+	// resultsConfigMapName is the name of the ConfigMap that the results are written to.
+	const resultsConfigMapName = "check-results"
+
+	east := fake.NewClientset()
+	west := fake.NewClientset()
+
+	var buf lockedBuffer
+
+	flags := map[string]string{flagLocal: "true", flagRoleOnly: "true", flagPrintResults: "true", flagSinceVersion: "2.1.0"}
+
+	c := setupContextsTest(t, flags, map[string]kubernetes.Interface{
+		"https://10.0.1.1:6443": east,
+		"https://10.0.2.1:6443": west,
+	})
+	c.logger = log.New(&buf)
+	c.envConfig = &envconfig.EnvConfig{Spec: envconfig.Spec{
+		Version:     "2.3.0",
+		ClusterName: "test",
+		CloudSpec:   envconfig.CloudSpec{Provider: string(cloud.GCP)},
+	}}
+
+	westEnvConfigPath := filepath.Join(t.TempDir(), "west.yaml")
+
+	require.NoError(t, os.WriteFile(westEnvConfigPath, []byte(`kind: EnvConfig
+spec:
+  version: 2.0.1
+  clusterName: test
+  cloudSpec:
+    provider: gcp
+    gcp:
+      projectID: project
+      projectNumber: "123456789012"
+`), 0o600))
+
+	runs, err := c.contextRuns([]string{"east", "west=" + westEnvConfigPath})
+	require.NoError(t, err)
+
+	c.checkContexts(runs, 0, constant.NamespaceAlphaSense, resultsConfigMapName)
+
+	for _, run := range runs {
+		require.NoError(t, run.err)
+		assert.False(t, run.failed)
+	}
+
+	assert.Equal(t, 0, runs[0].c.warnings)
+	assert.Equal(t, 1, runs[1].c.warnings)
+
+	for _, clientset := range []kubernetes.Interface{east, west} {
+		_, err := clientset.CoreV1().ConfigMaps(constant.NamespaceAlphaSense).Get(context.Background(), resultsConfigMapName, metav1.GetOptions{})
+		assert.NoError(t, err)
+	}
+
+	fatal, failed := c.summarizeContexts(runs)
+
+	assert.False(t, fatal)
+	assert.False(t, failed)
+	assert.Equal(t, 1, c.warnings)
+	assert.Contains(t, buf.String(), "east context: infrastructure check passed")
+	assert.Contains(t, buf.String(), "west context: infrastructure check passed with 1 warning(s)")
+	assert.Contains(t, buf.String(), "context=west")
+
+	var out bytes.Buffer
+
+	require.NoError(t, runs[1].c.printResults(&out, runs[1].failed))
+	assert.Contains(t, out.String(), `"context":"west"`)
+	assert.Contains(t, out.String(), `"status":"warning"`)
+}
+
+// TestCheckCmd_summarizeContexts is a test that tests that the summarizeContexts function fails the check if the one of any of the clusters fails or does
+// not finish.
+func TestCheckCmd_summarizeContexts(t *testing.T) {
+	testCases := []struct {
+		name       string
+		runs       []*contextRun
+		wantFatal  bool
+		wantFailed bool
+		wantLog    string
+	}{
+		{
+			name:    "Passed",
+			runs:    []*contextRun{{c: &checkCmd{kubeContext: "east"}}, {c: &checkCmd{kubeContext: "west"}}},
+			wantLog: "west context: infrastructure check passed",
+		},
+		{
+			name:       "Failed",
+			runs:       []*contextRun{{c: &checkCmd{kubeContext: "east"}}, {c: &checkCmd{kubeContext: "west"}, failed: true}},
+			wantFailed: true,
+			wantLog:    "west context: infrastructure check failed",
+		},
+		{
+			name:      "Not finished",
+			runs:      []*contextRun{{c: &checkCmd{kubeContext: "east"}, err: errCheckTimedOut}, {c: &checkCmd{kubeContext: "west"}}},
+			wantFatal: true,
+			wantLog:   "east context: infrastructure check did not finish: check timed out",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			c := setupCheckCmdTest(t, nil)
+			c.logger = log.New(&buf)
+
+			fatal, failed := c.summarizeContexts(tc.runs)
+
+			assert.Equal(t, tc.wantFatal, fatal)
+			assert.Equal(t, tc.wantFailed, failed)
+			assert.Contains(t, buf.String(), tc.wantLog)
+		})
+	}
+}
@@ -0,0 +1,182 @@
+// Package cmd is the package that contains all of the commands for the application.
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/cloudchecker"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/util"
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+	"go.uber.org/multierr"
+)
+
+var (
+	// errFailedToReadResults is the error that is returned when the results printed by the check command cannot be read.
+	errFailedToReadResults = errors.New("failed to read results")
+
+	// errFailedToWriteCoverage is the error that is returned when the coverage of the requirements cannot be written.
+	errFailedToWriteCoverage = errors.New("failed to write coverage")
+)
+
+// flagResults is the name of the flag for the file of the results printed by the check command, whose statuses the coverage is reported with.
+const flagResults = "results"
+
+// coverageCmd is the command to print which of the technical requirements the checks validate on each cloud provider.
+type coverageCmd struct {
+	// logger is the logger.
+	logger *log.Logger
+	// cobraCmd is the Cobra command.
+	cobraCmd *cobra.Command
+}
+
+var _ cmd = &coverageCmd{}
+
+// writeCoverageText writes the coverage to the writer as a table per cloud provider.
+func writeCoverageText(w io.Writer, coverages []cloudchecker.CloudCoverage) error {
+	const (
+		// noChecks is the value of the checks column of a requirement that none of the checks validate.
+		noChecks = "-"
+
+		// msgUnlistedRequirements is the message that is written after the tables, as the requirements that are not listed are not checked.
+		msgUnlistedRequirements = "The requirements that are not listed are not checked; see %s for all of them.\n"
+	)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	for i, c := range coverages {
+		if i > 0 {
+			if _, err := fmt.Fprintln(tw); err != nil {
+				return err
+			}
+		}
+
+		if _, err := fmt.Fprintf(tw, "%s (%d of %d listed requirements checked)\nREQUIREMENT\tSTATUS\tCHECKS\tURL\n", c.Cloud, c.Checked, c.Total); err != nil {
+			return err
+		}
+
+		for _, req := range c.Requirements {
+			checks := strings.Join(req.Checks, ", ")
+
+			if len(req.Checks) == 0 {
+				checks = noChecks
+			}
+
+			if _, err := fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", req.Name, req.Status, checks, req.URL); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, "\n"+msgUnlistedRequirements, cloudchecker.DocsTechnicalRequirements)
+
+	return err
+}
+
+// writeCoverageJSON writes the coverage to the writer as JSON.
+func writeCoverageJSON(w io.Writer, coverages []cloudchecker.CloudCoverage) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(coverages)
+}
+
+// readResultsCoverage is the function that returns the coverage of the requirements of the cloud provider of the results that the check command printed
+// with --print-results to the file at the path, with the status of each from the results.
+func readResultsCoverage(path string) (cloudchecker.CloudCoverage, error) {
+	data, err := os.ReadFile(path) // nolint:gosec
+	if err != nil {
+		return cloudchecker.CloudCoverage{}, multierr.Combine(errFailedToReadResults, err)
+	}
+
+	var results printedResults
+
+	if err := json.Unmarshal(data, &results); err != nil {
+		return cloudchecker.CloudCoverage{}, multierr.Combine(errFailedToReadResults, err)
+	}
+
+	coverage, err := cloudchecker.RunCoverage(results.Cloud, results.Results)
+	if err != nil {
+		return cloudchecker.CloudCoverage{}, multierr.Combine(errFailedToReadResults, err)
+	}
+
+	return coverage, nil
+}
+
+// run is the run function for the Coverage command.
+func (c *coverageCmd) run(cobraCmd *cobra.Command, _ []string) {
+	var write func(io.Writer, []cloudchecker.CloudCoverage) error
+
+	switch util.Flag(cobraCmd, flagOutput) {
+	case outputFormatText:
+		write = writeCoverageText
+	case outputFormatJSON:
+		write = writeCoverageJSON
+	default:
+		c.logger.Fatal(errInvalidOutputFormat)
+	}
+
+	coverages := cloudchecker.Coverage()
+
+	if path := util.Flag(cobraCmd, flagResults); path != constant.EmptyString {
+		coverage, err := readResultsCoverage(path)
+		if err != nil {
+			c.logger.Fatal(err)
+		}
+
+		coverages = []cloudchecker.CloudCoverage{coverage}
+	}
+
+	if err := write(cobraCmd.OutOrStdout(), coverages); err != nil {
+		c.logger.Fatal(multierr.Combine(errFailedToWriteCoverage, err))
+	}
+}
+
+// flags sets the flags for the Coverage command.
+func (c *coverageCmd) flags() {
+	c.cobraCmd.Flags().StringP(flagOutput, flagOutputShort, outputFormatText, "output format ("+outputFormatText+" or "+outputFormatJSON+")")
+	c.cobraCmd.Flags().String(
+		flagResults, constant.EmptyString, "path to the results printed by the check command with --"+flagPrintResults+", to report the status of each requirement",
+	)
+}
+
+// newCoverageCmd returns a new coverageCmd.
+func newCoverageCmd(logger *log.Logger, cobraCmd *cobra.Command) *coverageCmd {
+	return &coverageCmd{
+		logger:   logger,
+		cobraCmd: cobraCmd,
+	}
+}
+
+// Coverage returns a Cobra command to print which of the technical requirements the checks validate on each cloud provider.
+func Coverage(logger *log.Logger) *cobra.Command {
+	cobraCmd := &cobra.Command{
+		Use:   "coverage",
+		Short: "Print the technical requirements that the checks validate",
+		Long: `Coverage prints, for each cloud provider, the sections of the technical requirements documentation, which of the checks validate each of them,
+and the ones that no check validates.
+
+With the --` + flagResults + ` flag, it prints the coverage of the cloud provider of the results that the check command printed with --` + flagPrintResults + `,
+with whether each requirement passed, failed or was skipped.`,
+		Args: cobra.NoArgs,
+	}
+
+	cmd := newCoverageCmd(logger, cobraCmd)
+
+	cobraCmd.Run = cmd.run
+
+	cmd.flags()
+
+	return cobraCmd
+}
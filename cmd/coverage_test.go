@@ -0,0 +1,120 @@
+// Package cmd is the package that contains all of the commands for the application.
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/cloud"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/cloudchecker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWriteCoverageText is a test that tests that the writeCoverageText function writes a table per cloud provider, followed by where all of the
+// requirements are listed.
+func TestWriteCoverageText(t *testing.T) {
+	coverages := []cloudchecker.CloudCoverage{
+		{Cloud: cloud.AWS, Checked: 1, Total: 2, Requirements: []cloudchecker.RequirementCoverage{
+			{Name: "TLS", URL: "https://example.com/#tls", Checks: []string{"TLS"}, Status: cloudchecker.RequirementStatusPassed},
+			{Name: "DNS", URL: "https://example.com/#dns", Checks: []string{}, Status: cloudchecker.RequirementStatusNotChecked},
+		}},
+		{Cloud: cloud.GCP, Checked: 1, Total: 1, Requirements: []cloudchecker.RequirementCoverage{
+			{Name: "TLS", URL: "https://example.com/#tls", Checks: []string{"TLS", "SMTP"}, Status: cloudchecker.RequirementStatusValidated},
+		}},
+	}
+
+	var buf bytes.Buffer
+
+	require.NoError(t, writeCoverageText(&buf, coverages))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+
+	require.Len(t, lines, 10)
+	assert.Equal(t, "aws (1 of 2 listed requirements checked)", strings.TrimSpace(lines[0]))
+	assert.Equal(t, []string{"TLS", "passed", "TLS", "https://example.com/#tls"}, strings.Fields(lines[2]))
+	assert.Equal(t, []string{"DNS", "not", "checked", "-", "https://example.com/#dns"}, strings.Fields(lines[3]))
+	assert.Empty(t, lines[4])
+	assert.Equal(t, "gcp (1 of 1 listed requirements checked)", strings.TrimSpace(lines[5]))
+	assert.Equal(t, []string{"TLS", "validated", "TLS,", "SMTP", "https://example.com/#tls"}, strings.Fields(lines[7]))
+	assert.Empty(t, lines[8])
+	assert.Equal(t, "The requirements that are not listed are not checked; see "+cloudchecker.DocsTechnicalRequirements+" for all of them.", lines[9])
+}
+
+// TestWriteCoverageJSON is a test that tests that the writeCoverageJSON function writes the coverage that can be decoded back.
+func TestWriteCoverageJSON(t *testing.T) {
+	coverages := cloudchecker.Coverage()
+
+	var buf bytes.Buffer
+
+	require.NoError(t, writeCoverageJSON(&buf, coverages))
+
+	var got []cloudchecker.CloudCoverage
+
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+	assert.Equal(t, coverages, got)
+}
+
+// TestReadResultsCoverage is a test that tests that the readResultsCoverage function reports the coverage of the cloud provider of the printed results,
+// and returns an error for the results that cannot be read.
+func TestReadResultsCoverage(t *testing.T) {
+	testCases := []struct {
+		name       string
+		results    string
+		wantCloud  cloud.Cloud
+		wantStatus map[string]cloudchecker.RequirementStatus
+		wantErr    error
+	}{
+		{
+			name: "Results",
+			results: `{"status":"failed","cloud":"gcp","results":[` +
+				`{"kind":"result","check":"storage class","status":"passed"},` +
+				`{"kind":"result","check":"SSO","status":"failed","message":"missing key"}]}`,
+			wantCloud: cloud.GCP,
+			wantStatus: map[string]cloudchecker.RequirementStatus{
+				cloudchecker.DocsPersistentVolumes: cloudchecker.RequirementStatusPassed,
+				cloudchecker.DocsSSOSecrets:        cloudchecker.RequirementStatusFailed,
+				cloudchecker.DocsTLSSecrets:        cloudchecker.RequirementStatusValidated,
+			},
+		},
+		{
+			name:    "No cloud",
+			results: `{"status":"passed","results":[]}`,
+			wantErr: errFailedToReadResults,
+		},
+		{
+			name:    "Invalid JSON",
+			results: `{"status":`,
+			wantErr: errFailedToReadResults,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "results.json")
+
+			require.NoError(t, os.WriteFile(path, []byte(tc.results), 0o600))
+
+			coverage, err := readResultsCoverage(path)
+
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantCloud, coverage.Cloud)
+
+			for _, req := range coverage.Requirements {
+				if status, ok := tc.wantStatus[req.URL]; ok {
+					assert.Equal(t, status, req.Status, req.URL)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+// Package cmd is the package that contains all of the commands for the application.
+package cmd
+
+import (
+	"encoding/base64"
+	"errors"
+	"io"
+	"os"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/envconfig"
+	pkgerrors "github.com/AlphaSense-Engineering/privatecloud-cli/pkg/errors"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/util"
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+	"go.uber.org/multierr"
+	"gopkg.in/yaml.v3"
+)
+
+// errFailedToWriteEnvConfig is the error that is returned when the decoded environment configuration cannot be written.
+var errFailedToWriteEnvConfig = errors.New("failed to write environment configuration")
+
+// redact returns the value redacted, or the empty value as is, so that whether the field is set can still be told.
+func redact(value string) string {
+	if value == constant.EmptyString {
+		return value
+	}
+
+	return util.RedactedValue
+}
+
+// redactEnvConfig returns a copy of the environment configuration with the identifiers of the cloud account and of the identities in it redacted, so that
+// it can be shared when debugging.
+func redactEnvConfig(envConfig *envconfig.EnvConfig) *envconfig.EnvConfig {
+	redacted := *envConfig
+
+	redacted.Spec.ClientID = redact(redacted.Spec.ClientID)
+
+	if spec := envConfig.Spec.CloudSpec.AWS; spec != nil {
+		aws := *spec
+
+		aws.AccountID = redact(aws.AccountID)
+
+		redacted.Spec.CloudSpec.AWS = &aws
+	}
+
+	if spec := envConfig.Spec.CloudSpec.Azure; spec != nil {
+		azure := *spec
+
+		azure.ClientID = redact(azure.ClientID)
+		azure.SubscriptionID = redact(azure.SubscriptionID)
+		azure.TenantID = redact(azure.TenantID)
+
+		redacted.Spec.CloudSpec.Azure = &azure
+	}
+
+	if spec := envConfig.Spec.CloudSpec.GCP; spec != nil {
+		gcp := *spec
+
+		gcp.ProjectNumber = redact(gcp.ProjectNumber)
+
+		redacted.Spec.CloudSpec.GCP = &gcp
+	}
+
+	return &redacted
+}
+
+// decodeEnvConfig returns the environment configuration that the base64 encoded data carries, as the Check command passes it to the pod, without
+// validating it, so that the environment configuration of a failed pod can be inspected whatever its problems.
+func decodeEnvConfig(data string) (*envconfig.EnvConfig, error) {
+	envConfigBytes, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, multierr.Combine(errFailedToDecodeEnvConfig, err)
+	}
+
+	envConfig, err := envconfig.Parse(envConfigBytes)
+	if err != nil {
+		return nil, multierr.Combine(errFailedToReadEnvConfig, err)
+	}
+
+	return envConfig, nil
+}
+
+// writeEnvConfigYAML writes the environment configuration to the writer as YAML.
+func writeEnvConfigYAML(w io.Writer, envConfig *envconfig.EnvConfig) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(envConfig); err != nil {
+		return err
+	}
+
+	return encoder.Close()
+}
+
+// decodeEnvConfigCmd is the command to decode the environment configuration that the Check command passes to the pod.
+type decodeEnvConfigCmd struct {
+	// logger is the logger.
+	logger *log.Logger
+	// cobraCmd is the Cobra command.
+	cobraCmd *cobra.Command
+}
+
+var _ cmd = &decodeEnvConfigCmd{}
+
+// run is the run function for the DecodeEnvConfig command.
+func (c *decodeEnvConfigCmd) run(cobraCmd *cobra.Command, args []string) {
+	data := os.Getenv(envVarEnvConfig)
+
+	if len(args) > 0 {
+		data = args[0]
+	}
+
+	if data == constant.EmptyString {
+		c.logger.Fatal(pkgerrors.NewEnvVarIsNotSetOrEmpty(envVarEnvConfig))
+	}
+
+	envConfig, err := decodeEnvConfig(data)
+	if err != nil {
+		c.logger.Fatal(err)
+	}
+
+	if err := writeEnvConfigYAML(cobraCmd.OutOrStdout(), redactEnvConfig(envConfig)); err != nil {
+		c.logger.Fatal(multierr.Combine(errFailedToWriteEnvConfig, err))
+	}
+}
+
+// newDecodeEnvConfigCmd returns a new decodeEnvConfigCmd.
+func newDecodeEnvConfigCmd(logger *log.Logger, cobraCmd *cobra.Command) *decodeEnvConfigCmd {
+	return &decodeEnvConfigCmd{
+		logger:   logger,
+		cobraCmd: cobraCmd,
+	}
+}
+
+// DecodeEnvConfig returns a Cobra command to decode the environment configuration that the Check command passes to the pod.
+func DecodeEnvConfig(logger *log.Logger) *cobra.Command {
+	cobraCmd := &cobra.Command{
+		Use:   "decode-envconfig [<base64_envconfig>]",
+		Short: "Decode the environment configuration that the check command passes to the pod",
+		Long: `Decode-envconfig decodes the base64 encoded environment configuration that the check command passes to the pod in the ENVCONFIG environment
+variable and prints it as YAML, so that what a failed pod was given can be inspected. The value is read from the argument, or from the ENVCONFIG
+environment variable if no argument is given, such as when run with kubectl exec in the pod.
+
+The identifiers of the cloud account and of the identities, such as the AWS account ID or the Azure subscription and tenant IDs, are redacted, so that the
+output can be shared. The environment configuration is not validated, so that it is printed whatever its problems.`,
+		Args: cobra.MaximumNArgs(1),
+	}
+
+	cmd := newDecodeEnvConfigCmd(logger, cobraCmd)
+
+	cobraCmd.Run = cmd.run
+
+	return cobraCmd
+}
@@ -0,0 +1,112 @@
+// Package cmd is the package that contains all of the commands for the application.
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/envconfig"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/util"
+	"github.com/charmbracelet/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testDecodeEnvConfigAzure is the base64 encoded environment configuration of Azure used in the decode tests, as the Check command passes it to the pod.
+const testDecodeEnvConfigAzure = "a2luZDogRW52Q29uZmlnCnNwZWM6CiAgY2x1c3Rlck5hbWU6IGFjbWUKICBjbG91ZFNwZWM6CiAgICBwcm92aWRlcjogYXp1cmUKICAgIGF6dXJlOgogICAgICBjbGllbnRJRDogY2xpZW50CiAgICAgIHJlc291cmNlR3JvdXA6IGdyb3VwCiAgICAgIHN1YnNjcmlwdGlvbklEOiBzdWJzY3JpcHRpb24KICAgICAgdGVuYW50SUQ6IHRlbmFudAo="
+
+// TestDecodeEnvConfig is a test that tests that the decodeEnvConfig function decodes the environment configuration that the Check command passes to the
+// pod, and returns an error if the data is not base64 encoded or carries no environment configuration.
+func TestDecodeEnvConfig(t *testing.T) {
+	envConfig, err := decodeEnvConfig(testDecodeEnvConfigAzure)
+	require.NoError(t, err)
+
+	assert.Equal(t, "EnvConfig", envConfig.Kind)
+	assert.Equal(t, "acme", envConfig.Spec.ClusterName)
+	assert.Equal(t, &envconfig.AzureSpec{ClientID: "client", ResourceGroup: "group", SubscriptionID: "subscription", TenantID: "tenant"},
+		envConfig.Spec.CloudSpec.Azure)
+
+	_, err = decodeEnvConfig("not base64")
+	require.ErrorIs(t, err, errFailedToDecodeEnvConfig)
+
+	// a2luZDogU2VjcmV0Cg== is "kind: Secret\n".
+	_, err = decodeEnvConfig("a2luZDogU2VjcmV0Cg==")
+	require.ErrorIs(t, err, errFailedToReadEnvConfig)
+}
+
+// TestRedactEnvConfig is a test that tests that the redactEnvConfig function redacts the identifiers of the cloud account and of the identities of every
+// cloud provider, keeps the empty ones empty, and leaves the environment configuration itself as is.
+func TestRedactEnvConfig(t *testing.T) {
+	envConfig := &envconfig.EnvConfig{
+		Kind: "EnvConfig",
+		Spec: envconfig.Spec{
+			ClientID:    "acme-client",
+			ClusterName: "acme",
+			CloudSpec: envconfig.CloudSpec{
+				Provider: "aws",
+				AWS:      &envconfig.AWSSpec{AccountID: "123456789012", OIDCURL: "oidc.eks.us-east-1.amazonaws.com/id/ABC"},
+				Azure:    &envconfig.AzureSpec{ClientID: "client", ResourceGroup: "group", SubscriptionID: "subscription"},
+				GCP:      &envconfig.GCPSpec{ProjectID: "project", ProjectNumber: "123"},
+			},
+		},
+	}
+
+	redacted := redactEnvConfig(envConfig)
+
+	assert.Equal(t, &envconfig.EnvConfig{
+		Kind: "EnvConfig",
+		Spec: envconfig.Spec{
+			ClientID:    util.RedactedValue,
+			ClusterName: "acme",
+			CloudSpec: envconfig.CloudSpec{
+				Provider: "aws",
+				AWS:      &envconfig.AWSSpec{AccountID: util.RedactedValue, OIDCURL: "oidc.eks.us-east-1.amazonaws.com/id/ABC"},
+				Azure:    &envconfig.AzureSpec{ClientID: util.RedactedValue, ResourceGroup: "group", SubscriptionID: util.RedactedValue},
+				GCP:      &envconfig.GCPSpec{ProjectID: "project", ProjectNumber: util.RedactedValue},
+			},
+		},
+	}, redacted)
+
+	assert.Equal(t, "123456789012", envConfig.Spec.CloudSpec.AWS.AccountID)
+	assert.Equal(t, "subscription", envConfig.Spec.CloudSpec.Azure.SubscriptionID)
+	assert.Equal(t, "123", envConfig.Spec.CloudSpec.GCP.ProjectNumber)
+}
+
+// TestDecodeEnvConfigCmd is a test that tests that the DecodeEnvConfig command prints the redacted environment configuration that it reads from the
+// argument or, if there is none, from the environment variable.
+func TestDecodeEnvConfigCmd(t *testing.T) {
+	testCases := []struct {
+		name string
+		args []string
+		env  string
+	}{
+		{name: "Argument", args: []string{testDecodeEnvConfigAzure}, env: "a2luZDogU2VjcmV0Cg=="},
+		{name: "Environment variable", env: testDecodeEnvConfigAzure},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(envVarEnvConfig, tc.env)
+
+			var out bytes.Buffer
+
+			cobraCmd := DecodeEnvConfig(log.New(&out))
+			cobraCmd.SetOut(&out)
+			cobraCmd.SetArgs(tc.args)
+
+			require.NoError(t, cobraCmd.Execute())
+
+			got, err := envconfig.Parse(out.Bytes())
+			require.NoError(t, err)
+
+			assert.Equal(t, "acme", got.Spec.ClusterName)
+			assert.Equal(t, &envconfig.AzureSpec{
+				ClientID:       util.RedactedValue,
+				ResourceGroup:  "group",
+				SubscriptionID: util.RedactedValue,
+				TenantID:       util.RedactedValue,
+			}, got.Spec.CloudSpec.Azure)
+			assert.NotContains(t, out.String(), ": subscription")
+		})
+	}
+}
@@ -0,0 +1,74 @@
+// Package cmd is the package that contains all of the commands for the application.
+package cmd
+
+import (
+	"os"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+)
+
+// logMsgDeprecated is the message that is logged when a deprecated flag or environment variable is used.
+const logMsgDeprecated = "%s is deprecated and will be removed in a future release, use %s instead"
+
+// deprecation is the type that describes a deprecated flag or environment variable and its replacement.
+type deprecation struct {
+	// name is the name of the deprecated flag or environment variable, without the leading dashes for the flags.
+	name string
+	// replacement is the replacement, as it is referred to in the warning, e.g. --connect-timeout.
+	replacement string
+}
+
+// constDeprecatedFlags is the registry of the deprecated flags, which apply to every command that has them.
+//
+// No flags are deprecated at the moment; the registry is kept so that the warning covers them once they are.
+//
+// Do not modify this variable, it is supposed to be constant.
+var constDeprecatedFlags = []deprecation{}
+
+// constDeprecatedEnvVars is the registry of the deprecated environment variables.
+//
+// No environment variables are deprecated at the moment; the registry is kept so that the warning covers them once they are.
+//
+// Do not modify this variable, it is supposed to be constant.
+var constDeprecatedEnvVars = []deprecation{}
+
+// markDeprecated hides the deprecated flags of the registry that the command has from its help.
+//
+// Cobra's own deprecation notice is not used, as it is printed to the standard output, which the machine-readable outputs of the commands use.
+func markDeprecated(cobraCmd *cobra.Command, flags []deprecation) {
+	for _, d := range flags {
+		if cobraCmd.Flags().Lookup(d.name) == nil {
+			continue
+		}
+
+		_ = cobraCmd.Flags().MarkHidden(d.name)
+	}
+}
+
+// warnDeprecated logs a warning for each of the deprecated flags of the registry that is set on the command, and for each of the deprecated environment
+// variables that is set.
+func warnDeprecated(logger *log.Logger, cobraCmd *cobra.Command, flags []deprecation, envVars []deprecation) {
+	for _, d := range flags {
+		if flag := cobraCmd.Flags().Lookup(d.name); flag != nil && flag.Changed {
+			logger.Warnf(logMsgDeprecated, "--"+d.name, d.replacement)
+		}
+	}
+
+	for _, d := range envVars {
+		if _, ok := os.LookupEnv(d.name); ok {
+			logger.Warnf(logMsgDeprecated, d.name, d.replacement)
+		}
+	}
+}
+
+// MarkDeprecated hides the deprecated flags that the command has from its help.
+func MarkDeprecated(cobraCmd *cobra.Command) {
+	markDeprecated(cobraCmd, constDeprecatedFlags)
+}
+
+// WarnDeprecated logs a warning for each of the deprecated flags that is set on the command and for each of the deprecated environment variables that is set,
+// pointing to their replacements.
+func WarnDeprecated(logger *log.Logger, cobraCmd *cobra.Command) {
+	warnDeprecated(logger, cobraCmd, constDeprecatedFlags, constDeprecatedEnvVars)
+}
@@ -0,0 +1,99 @@
+// Package cmd is the package that contains all of the commands for the application.
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/charmbracelet/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWarnDeprecated is a test that tests that the warnDeprecated function warns when, and only when, a deprecated flag is used.
+func TestWarnDeprecated(t *testing.T) {
+	flags := []deprecation{{name: flagStep, replacement: "--new-step"}, {name: flagSkipStep, replacement: "--new-skip-step"}}
+
+	testCases := []struct {
+		name      string
+		args      []string
+		wantWarns []string
+	}{
+		{
+			name: "No deprecated flags",
+			args: []string{"--" + flagForce},
+		},
+		{
+			name:      "Step",
+			args:      []string{"--" + flagStep, "2"},
+			wantWarns: []string{"--step is deprecated and will be removed in a future release, use --new-step instead"},
+		},
+		{
+			name: "Step and skip step",
+			args: []string{"--" + flagStep, "2", "--" + flagSkipStep, "3"},
+			wantWarns: []string{
+				"--step is deprecated and will be removed in a future release, use --new-step instead",
+				"--skip-step is deprecated and will be removed in a future release, use --new-skip-step instead",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			cobraCmd := Install(log.New(io.Discard))
+
+			require.NoError(t, cobraCmd.ParseFlags(tc.args))
+
+			warnDeprecated(log.New(&buf), cobraCmd, flags, nil)
+
+			if tc.wantWarns == nil {
+				assert.Empty(t, buf.String())
+
+				return
+			}
+
+			for _, want := range tc.wantWarns {
+				assert.Contains(t, buf.String(), want)
+			}
+		})
+	}
+}
+
+// TestWarnDeprecated_envVar is a test that tests that the warnDeprecated function warns when a deprecated environment variable is set.
+func TestWarnDeprecated_envVar(t *testing.T) {
+	// envVarDeprecated is the name of the deprecated environment variable used in the test.
+	const envVarDeprecated = "PRIVATECLOUD_CLI_TEST_DEPRECATED"
+
+	envVars := []deprecation{{name: envVarDeprecated, replacement: envVarConnectTimeout}}
+
+	var buf bytes.Buffer
+
+	warnDeprecated(log.New(&buf), Install(log.New(io.Discard)), nil, envVars)
+
+	assert.Empty(t, buf.String())
+
+	t.Setenv(envVarDeprecated, "1")
+
+	warnDeprecated(log.New(&buf), Install(log.New(io.Discard)), nil, envVars)
+
+	assert.Contains(t, buf.String(), envVarDeprecated+" is deprecated and will be removed in a future release, use "+envVarConnectTimeout+" instead")
+}
+
+// TestMarkDeprecated is a test that tests that the markDeprecated function hides the deprecated flags from the help, leaving them usable.
+func TestMarkDeprecated(t *testing.T) {
+	flags := []deprecation{{name: flagStep, replacement: "--new-step"}}
+
+	cobraCmd := Install(log.New(io.Discard))
+
+	markDeprecated(cobraCmd, flags)
+
+	assert.True(t, cobraCmd.Flags().Lookup(flagStep).Hidden)
+	assert.False(t, cobraCmd.Flags().Lookup(flagSkipStep).Hidden)
+
+	require.NoError(t, cobraCmd.ParseFlags([]string{"--" + flagStep, "2"}))
+
+	assert.NotPanics(t, func() { markDeprecated(Cleanup(log.New(io.Discard)), flags) })
+}
@@ -0,0 +1,246 @@
+// Package cmd is the package that contains all of the commands for the application.
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/cloud"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/cloud/gcpcloudutil"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/cloudchecker"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/serviceaccountchecker"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/storageclasschecker"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// errFailedToFix is the error that is returned when the remediation of a failure of a check cannot be applied.
+var errFailedToFix = errors.New("failed to fix")
+
+// flagFix is the name of the flag for fixing the failures of the checks that are safe to fix automatically.
+const flagFix = "fix"
+
+// checkNameServiceAccountAnnotation is the name of the check of the annotation of the service account of the Crossplane role check on GCP, which binds it to
+// the GCP service account of the Crossplane providers; it only runs with the --fix flag.
+const checkNameServiceAccountAnnotation = "service account annotation"
+
+// remediation is the type that contains the minimal change that fixes a failure of a check.
+type remediation struct {
+	// description is the description of the change, which is asked to be confirmed and logged once applied, along with how to revert it.
+	description string
+	// apply is the function that applies the change.
+	apply func(ctx context.Context) error
+}
+
+// fixer is the type that contains the fix of the failures of a check that are safe to fix automatically.
+type fixer struct {
+	// check is the name of the check.
+	check string
+	// handler is the handler of the check, run to detect the failure and once more to verify the fix.
+	handler handler.Handler
+	// remediate is the function that returns the remediation of the failure of the check, or nil if the failure cannot be fixed automatically.
+	remediate func(ctx context.Context, err error) (*remediation, error)
+}
+
+// fixers is the function that returns the fixers of the checks, in the order of the checks.
+//
+// It returns an error if the configuration of the cloud provider cannot be read.
+func (c *checkCmd) fixers(options handler.CheckOptions) ([]fixer, error) {
+	checkCtx := handler.CheckContext{
+		Logger:    c.logger,
+		VCloud:    cloud.Cloud(c.envConfig.Spec.CloudSpec.Provider),
+		EnvConfig: c.envConfig,
+		Clientset: c.clientset,
+		Options:   options,
+	}
+
+	fixers := []fixer{
+		{check: cloudchecker.CheckNameStorageClass, handler: storageclasschecker.New(checkCtx), remediate: c.remediateStorageClass},
+	}
+
+	if checkCtx.VCloud == cloud.GCP {
+		gcpSpec, err := c.envConfig.GCP()
+		if err != nil {
+			return nil, err
+		}
+
+		value := gcpcloudutil.ServiceAccountAnnotation(c.envConfig.Spec.ClusterName, gcpSpec.ProjectID)
+
+		fixers = append(fixers, fixer{
+			check: checkNameServiceAccountAnnotation,
+			handler: serviceaccountchecker.NewAnnotation(
+				checkCtx, constant.ServiceAccountNameGCP, gcpcloudutil.ServiceAccountAnnotationKey, value,
+			),
+			remediate: c.remediateServiceAccountAnnotation(options.ProviderServiceAccountsNamespace(), value),
+		})
+	}
+
+	return fixers, nil
+}
+
+// remediateStorageClass is the function that returns the remediation of the missing default storage class, which marks the storage class as the default
+// one when it is the only one of the cluster, as it is then the one that the volumes are provisioned with anyway.
+//
+// It returns nil if the failure is not the missing default storage class or if there is not exactly one storage class, or an error if the storage classes
+// cannot be listed.
+func (c *checkCmd) remediateStorageClass(ctx context.Context, err error) (*remediation, error) {
+	if !errors.Is(err, storageclasschecker.ErrNoDefaultStorageClass) {
+		return nil, nil
+	}
+
+	storageClasses, err := c.clientset.StorageV1().StorageClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(storageClasses.Items) != 1 {
+		return nil, nil
+	}
+
+	name := storageClasses.Items[0].Name
+
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"annotations": map[string]string{storageclasschecker.DefaultStorageClassAnnotation: strconv.FormatBool(true)},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &remediation{
+		description: fmt.Sprintf(
+			"annotate the %s StorageClass with %s=true, the only StorageClass of the cluster; remove the annotation to revert it",
+			name, storageclasschecker.DefaultStorageClassAnnotation,
+		),
+		apply: func(ctx context.Context) error {
+			_, err := c.clientset.StorageV1().StorageClasses().Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+
+			return err
+		},
+	}, nil
+}
+
+// remediateServiceAccountAnnotation is the function that returns the function that returns the remediation of the unexpected annotation of the service
+// account of the Crossplane role check on GCP in the namespace, which sets it to the value, as the service account that the Pod creates has it, but the one
+// that already exists, e.g. left behind by an older version or created by hand, is used as is.
+//
+// It returns nil if the failure is not the unexpected annotation, or an error if the service account cannot be retrieved.
+func (c *checkCmd) remediateServiceAccountAnnotation(namespace string, value string) func(ctx context.Context, err error) (*remediation, error) {
+	return func(ctx context.Context, err error) (*remediation, error) {
+		if !errors.Is(err, serviceaccountchecker.ErrUnexpectedAnnotation) {
+			return nil, nil
+		}
+
+		sa, err := c.clientset.CoreV1().ServiceAccounts(namespace).Get(ctx, constant.ServiceAccountNameGCP, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+
+		patch, err := json.Marshal(map[string]any{
+			"metadata": map[string]any{
+				"annotations": map[string]string{gcpcloudutil.ServiceAccountAnnotationKey: value},
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		revert := "remove the annotation to revert it"
+
+		if previous, ok := sa.Annotations[gcpcloudutil.ServiceAccountAnnotationKey]; ok {
+			revert = fmt.Sprintf("set it back to %s to revert it", previous)
+		}
+
+		return &remediation{
+			description: fmt.Sprintf(
+				"annotate the %s/%s ServiceAccount with %s=%s; %s", namespace, constant.ServiceAccountNameGCP, gcpcloudutil.ServiceAccountAnnotationKey, value, revert,
+			),
+			apply: func(ctx context.Context) error {
+				_, err := c.clientset.CoreV1().ServiceAccounts(namespace).Patch(
+					ctx, constant.ServiceAccountNameGCP, types.MergePatchType, patch, metav1.PatchOptions{},
+				)
+
+				return err
+			},
+		}, nil
+	}
+}
+
+// runFixes is the function that runs each check that can be fixed, and, if it fails in a way that is safe to fix automatically, applies the remediation once
+// confirmed and runs the check again, warning if it still fails.
+//
+// The remediations that are declined are skipped. It returns an error if a remediation cannot be confirmed, e.g. when not running in a terminal without the
+// --yes flag, or cannot be applied.
+func (c *checkCmd) runFixes(ctx context.Context, fixers []fixer) error {
+	const (
+		// logMsgNothingToFix is the message that is logged when the check passes, so there is nothing to fix.
+		logMsgNothingToFix = "%s check passed, nothing to fix"
+
+		// logMsgCannotFix is the message that is logged when the failure of the check cannot be fixed automatically.
+		logMsgCannotFix = "%s check failed in a way that cannot be fixed automatically: %v"
+
+		// logMsgFixDeclined is the message that is logged when the remediation is declined.
+		logMsgFixDeclined = "did not fix %s check: %s"
+
+		// logMsgFixed is the message that is logged once the remediation is applied.
+		logMsgFixed = "fixed %s check: %s"
+
+		// logMsgFixVerified is the message that is logged when the check passes once the remediation is applied.
+		logMsgFixVerified = "%s check passed once fixed"
+
+		// logMsgFixNotVerified is the message that is logged when the check still fails once the remediation is applied.
+		logMsgFixNotVerified = "%s check still failed once fixed: %v"
+	)
+
+	for _, f := range fixers {
+		_, checkErr := f.handler.Handle(ctx)
+		if checkErr == nil {
+			c.logger.Debugf(logMsgNothingToFix, f.check)
+
+			continue
+		}
+
+		r, err := f.remediate(ctx, checkErr)
+		if err != nil {
+			return fmt.Errorf("%w %s check: %w", errFailedToFix, f.check, err)
+		}
+
+		if r == nil {
+			c.logger.Infof(logMsgCannotFix, f.check, checkErr)
+
+			continue
+		}
+
+		if err := confirm(c.cobraCmd, c.cobraCmd.ErrOrStderr(), fmt.Sprintf("Fix the %s check: %s?", f.check, r.description)); err != nil {
+			if errors.Is(err, errNotConfirmed) {
+				c.logger.Infof(logMsgFixDeclined, f.check, r.description)
+
+				continue
+			}
+
+			return err
+		}
+
+		if err := r.apply(ctx); err != nil {
+			return fmt.Errorf("%w %s check: %w", errFailedToFix, f.check, err)
+		}
+
+		c.logger.Infof(logMsgFixed, f.check, r.description)
+
+		if _, err := f.handler.Handle(ctx); err != nil {
+			c.warnf(logMsgFixNotVerified, f.check, err)
+
+			continue
+		}
+
+		c.logger.Infof(logMsgFixVerified, f.check)
+	}
+
+	return nil
+}
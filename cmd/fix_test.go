@@ -0,0 +1,235 @@
+// Package cmd is the package that contains all of the commands for the application.
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/cloud/gcpcloudutil"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/envconfig"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/storageclasschecker"
+	"github.com/charmbracelet/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// TestCheckCmd_runFixes_storageClass is a test that tests that the runFixes function marks the only storage class of the cluster as the default one once
+// confirmed, verifies the fix by running the check again, and leaves the storage classes as they are when the failure cannot be fixed automatically.
+func TestCheckCmd_runFixes_storageClass(t *testing.T) {
+	// storageClass is the function that returns a storage class with the name, the provisioner and the annotations.
+	storageClass := func(name string, provisioner string, annotations map[string]string) *storagev1.StorageClass {
+		return &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: annotations}, Provisioner: provisioner}
+	}
+
+	testCases := []struct {
+		name         string
+		flags        map[string]string
+		options      handler.CheckOptions
+		objects      []runtime.Object
+		wantErr      error
+		wantDefault  []string
+		wantFixed    bool
+		wantLog      string
+		wantWarnings int
+	}{
+		{
+			name:        "Only storage class",
+			flags:       map[string]string{flagYes: "true"},
+			objects:     []runtime.Object{storageClass("gp3", "ebs.csi.aws.com", nil)},
+			wantDefault: []string{"gp3"},
+			wantFixed:   true,
+			wantLog:     "storage class check passed once fixed",
+		},
+		{
+			name:    "Several storage classes",
+			flags:   map[string]string{flagYes: "true"},
+			objects: []runtime.Object{storageClass("gp2", "ebs.csi.aws.com", nil), storageClass("gp3", "ebs.csi.aws.com", nil)},
+			wantLog: "storage class check failed in a way that cannot be fixed automatically: no default storage class found",
+		},
+		{
+			name:        "Default storage class",
+			flags:       map[string]string{flagYes: "true"},
+			objects:     []runtime.Object{storageClass("gp3", "ebs.csi.aws.com", map[string]string{storageclasschecker.DefaultStorageClassAnnotation: "true"})},
+			wantDefault: []string{"gp3"},
+		},
+		{
+			name:    "Not confirmed",
+			objects: []runtime.Object{storageClass("gp3", "ebs.csi.aws.com", nil)},
+			wantErr: errConfirmationRequired,
+		},
+		{
+			name:         "Still failing once fixed",
+			flags:        map[string]string{flagYes: "true"},
+			options:      handler.CheckOptions{CheckStorageClassProvisioner: true, StorageClassProvisioners: []string{"ebs.csi.aws.com"}},
+			objects:      []runtime.Object{storageClass("standard", "kubernetes.io/no-provisioner", nil)},
+			wantDefault:  []string{"standard"},
+			wantFixed:    true,
+			wantLog:      "storage class check still failed once fixed: unexpected provisioner of the default storage class",
+			wantWarnings: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			c := setupCheckCmdTest(t, tc.flags)
+			c.logger = log.New(&buf)
+			c.cobraCmd.SetErr(&bytes.Buffer{})
+
+			clientset := fake.NewClientset(tc.objects...)
+			c.setClientset(clientset)
+
+			fixers, err := c.fixers(tc.options)
+			require.NoError(t, err)
+
+			err = c.runFixes(context.Background(), fixers)
+
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			storageClasses, err := clientset.StorageV1().StorageClasses().List(context.Background(), metav1.ListOptions{})
+			require.NoError(t, err)
+
+			var defaults []string
+
+			for _, sc := range storageClasses.Items {
+				if sc.Annotations[storageclasschecker.DefaultStorageClassAnnotation] == "true" {
+					defaults = append(defaults, sc.Name)
+				}
+			}
+
+			assert.Equal(t, tc.wantDefault, defaults)
+			assert.Contains(t, buf.String(), tc.wantLog)
+			assert.Equal(t, tc.wantWarnings, c.warnings)
+
+			if tc.wantFixed {
+				assert.Contains(t, buf.String(), "fixed storage class check: annotate the "+tc.wantDefault[0]+" StorageClass")
+			} else {
+				assert.NotContains(t, buf.String(), "fixed storage class check")
+			}
+		})
+	}
+}
+
+// TestCheckCmd_runFixes_serviceAccountAnnotation is a test that tests that the runFixes function annotates the existing service account of the Crossplane
+// role check on GCP with its GCP service account once confirmed, logging how to revert it, and leaves the service account as it is when it is already
+// annotated or does not exist.
+func TestCheckCmd_runFixes_serviceAccountAnnotation(t *testing.T) {
+	// want is the expected annotation of the service account.
+	want := gcpcloudutil.ServiceAccountAnnotation("acme", "project")
+
+	// serviceAccount is the function that returns the service account of the Crossplane role check on GCP in the namespace with the annotations.
+	serviceAccount := func(namespace string, annotations map[string]string) *corev1.ServiceAccount {
+		return &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: constant.ServiceAccountNameGCP, Namespace: namespace, Annotations: annotations}}
+	}
+
+	testCases := []struct {
+		name           string
+		flags          map[string]string
+		options        handler.CheckOptions
+		objects        []runtime.Object
+		wantErr        error
+		wantAnnotation string
+		wantLog        string
+	}{
+		{
+			name:           "Missing annotation",
+			flags:          map[string]string{flagYes: "true"},
+			objects:        []runtime.Object{serviceAccount(constant.NamespaceCrossplane, nil)},
+			wantAnnotation: want,
+			wantLog: "fixed service account annotation check: annotate the crossplane/gcp-provider-sa ServiceAccount with iam.gke.io/gcp-service-account=" +
+				want + "; remove the annotation to revert it",
+		},
+		{
+			name:  "Other annotation",
+			flags: map[string]string{flagYes: "true"},
+			objects: []runtime.Object{
+				serviceAccount(constant.NamespaceCrossplane, map[string]string{gcpcloudutil.ServiceAccountAnnotationKey: "other@project.iam.gserviceaccount.com"}),
+			},
+			wantAnnotation: want,
+			wantLog:        "set it back to other@project.iam.gserviceaccount.com to revert it",
+		},
+		{
+			name:           "Namespace of the options",
+			flags:          map[string]string{flagYes: "true"},
+			options:        handler.CheckOptions{CrossplaneNamespace: "crossplane-system"},
+			objects:        []runtime.Object{serviceAccount("crossplane-system", nil)},
+			wantAnnotation: want,
+			wantLog:        "service account annotation check passed once fixed",
+		},
+		{
+			name:           "Expected annotation",
+			flags:          map[string]string{flagYes: "true"},
+			objects:        []runtime.Object{serviceAccount(constant.NamespaceCrossplane, map[string]string{gcpcloudutil.ServiceAccountAnnotationKey: want})},
+			wantAnnotation: want,
+		},
+		{
+			name:  "No service account",
+			flags: map[string]string{flagYes: "true"},
+		},
+		{
+			name:    "Not confirmed",
+			objects: []runtime.Object{serviceAccount(constant.NamespaceCrossplane, nil)},
+			wantErr: errConfirmationRequired,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			c := setupCheckCmdTest(t, tc.flags)
+			c.logger = log.New(&buf)
+			c.cobraCmd.SetErr(&bytes.Buffer{})
+			c.envConfig = &envconfig.EnvConfig{Spec: envconfig.Spec{
+				ClusterName: "acme",
+				CloudSpec:   envconfig.CloudSpec{Provider: "gcp", GCP: &envconfig.GCPSpec{ProjectID: "project"}},
+			}}
+
+			clientset := fake.NewClientset(tc.objects...)
+			c.setClientset(clientset)
+
+			fixers, err := c.fixers(tc.options)
+			require.NoError(t, err)
+
+			err = c.runFixes(context.Background(), fixers)
+
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			serviceAccounts, err := clientset.CoreV1().ServiceAccounts(tc.options.ProviderServiceAccountsNamespace()).List(
+				context.Background(), metav1.ListOptions{},
+			)
+			require.NoError(t, err)
+
+			if tc.objects == nil {
+				assert.Empty(t, serviceAccounts.Items)
+			} else {
+				require.Len(t, serviceAccounts.Items, 1)
+				assert.Equal(t, tc.wantAnnotation, serviceAccounts.Items[0].Annotations[gcpcloudutil.ServiceAccountAnnotationKey])
+			}
+
+			assert.Contains(t, buf.String(), tc.wantLog)
+			assert.Zero(t, c.warnings)
+
+			if tc.wantLog == constant.EmptyString {
+				assert.NotContains(t, buf.String(), "fixed service account annotation check")
+			}
+		})
+	}
+}
@@ -3,16 +3,30 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
 	"os/exec"
+	"os/signal"
 	"slices"
 	"strings"
+	"syscall"
 	"time"
 
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/clock"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/k8s/kubeutil"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/util"
 	"github.com/charmbracelet/log"
 	"github.com/spf13/cobra"
+	"go.uber.org/multierr"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 )
 
 var (
@@ -21,6 +35,28 @@ var (
 
 	// errInvalidStep is the error that is returned when the step is invalid.
 	errInvalidStep = errors.New("invalid step: must be 2 or 3")
+
+	// errKubectlUseContextTimedOut is the error that is returned when switching the kubectl context does not complete in time.
+	errKubectlUseContextTimedOut = errors.New("timed out switching kubectl context, check that the credentials plugin of the context does not wait for input")
+
+	// errInvalidPollJitter is the error that is returned when the jitter of the sleep intervals is not in the [0, 1) range.
+	errInvalidPollJitter = errors.New("invalid poll jitter: must be at least 0 and less than 1")
+
+	// errInvalidFieldManager is the error that is returned when the field manager of the server-side applies is blank.
+	errInvalidFieldManager = errors.New("invalid field manager: must not be blank")
+
+	// errEnvConfigControllerNotRunning is the error that is returned when the EnvConfig controller is not running, so the phases would never change.
+	errEnvConfigControllerNotRunning = errors.New("EnvConfig controller not running")
+
+	// errFailedToGetEnvConfigController is the error that is returned when the Deployment of the EnvConfig controller cannot be obtained.
+	errFailedToGetEnvConfigController = errors.New("failed to get EnvConfig controller")
+
+	// errServerDryRunFailed is the error that is returned when a file is rejected by the server-side dry run of its apply.
+	errServerDryRunFailed = errors.New("server-side dry run failed")
+
+	// errEnvConfigFailed is the error that is returned when the EnvConfig is in a phase that it does not leave on its own, so the phases waited for would
+	// never be reached.
+	errEnvConfigFailed = errors.New("EnvConfig failed")
 )
 
 const (
@@ -44,11 +80,59 @@ const (
 	flagStep = "step"
 	// flagSkipStep is the name of the flag for the skip step flag.
 	flagSkipStep = "skip-step"
+
+	// flagKubectlTimeout is the name of the flag for the timeout of a single kubectl invocation.
+	flagKubectlTimeout = "kubectl-timeout"
+
+	// flagPollJitter is the name of the flag for the fraction of the sleep intervals of the installation that is randomized.
+	flagPollJitter = "poll-jitter"
+
+	// flagVerify is the name of the flag for re-running the check once the installation is completed.
+	flagVerify = "verify"
+
+	// flagCheckController is the name of the flag for checking that the EnvConfig controller is running while waiting for the phases.
+	flagCheckController = "check-controller"
+
+	// flagPlan is the name of the flag for printing the plan of the installation instead of executing it.
+	flagPlan = "plan"
+
+	// flagFieldManager is the name of the flag for the field manager of the server-side applies.
+	flagFieldManager = "field-manager"
+
+	// flagValidateOnly is the name of the flag for validating the files with a server-side dry run before applying any of them.
+	flagValidateOnly = "validate-only"
+)
+
+const (
+	// envConfigControllerName is the name of the Deployment of the EnvConfig controller.
+	envConfigControllerName = "envconfig-controller"
+
+	// envConfigControllerNamespace is the namespace of the Deployment of the EnvConfig controller.
+	envConfigControllerNamespace = constant.NamespacePlatform
+
+	// reasonCrashLoopBackOff is the reason of the waiting state of a container that keeps crashing.
+	reasonCrashLoopBackOff = "CrashLoopBackOff"
 )
 
 // kubectlBin is the binary name for kubectl.
 const kubectlBin = "kubectl"
 
+// defaultPollJitter is the default fraction by which the sleep intervals of the installation are randomly lengthened or shortened, so that the installations
+// run at the same time, e.g. in a fleet rollout, do not poll the API servers in sync.
+const defaultPollJitter = 0.1
+
+// defaultFieldManager is the default field manager of the server-side applies, so that the fields set by the installation are attributed to the application
+// rather than to kubectl.
+const defaultFieldManager = constant.AppName
+
+// useContextTimeout is the maximum duration of switching the kubectl context.
+//
+// Switching the context normally completes instantly, so the timeout is much shorter than the one of the other kubectl invocations.
+const useContextTimeout = 30 * time.Second
+
+// execFunc is the type of the function that executes a command, killing it if the context is done before the command completes.
+type execFunc func(ctx context.Context, l *log.Logger, outBuf *bytes.Buffer, bin string, args ...string) error
+
 var (
 	// constPhasesToWaitForWithCrossplane is the list of phases to wait for to proceed to the second step of the installation.
 	//
@@ -64,8 +148,199 @@ var (
 	//
 	// Do not modify this variable, it is supposed to be constant.
 	constPhasesToWaitForCompleted = []string{"Ready"}
+
+	// constPhasesFailed is the list of the phases in which the EnvConfig is stuck until it is fixed, which abort the wait for the other phases.
+	//
+	// Do not modify this variable, it is supposed to be constant.
+	constPhasesFailed = []string{"Failed", "Error"}
 )
 
+// envConfigCondition is the type that contains a condition of the status of the EnvConfig.
+type envConfigCondition struct {
+	// Type is the type of the condition.
+	Type string `json:"type"`
+	// Status is the status of the condition, True, False or Unknown.
+	Status string `json:"status"`
+	// Reason is the reason for the last transition of the condition.
+	Reason string `json:"reason,omitempty"`
+	// Message is the message of the last transition of the condition.
+	Message string `json:"message,omitempty"`
+}
+
+// envConfigStatus is the type that contains the status of the EnvConfig.
+type envConfigStatus struct {
+	// Phase is the phase of the EnvConfig.
+	Phase string `json:"phase"`
+	// Message is the message that explains the phase, if any.
+	Message string `json:"message,omitempty"`
+	// Conditions is the list of the conditions of the EnvConfig.
+	Conditions []envConfigCondition `json:"conditions,omitempty"`
+}
+
+// failedPhaseError returns an error with the phase, the message and the conditions that are not True if the EnvConfig is in one of the failed phases, or
+// nil otherwise.
+func failedPhaseError(status envConfigStatus) error {
+	if !slices.Contains(constPhasesFailed, status.Phase) {
+		return nil
+	}
+
+	details := []string{fmt.Sprintf("phase %s", status.Phase)}
+
+	if status.Message != constant.EmptyString {
+		details = append(details, status.Message)
+	}
+
+	for _, condition := range status.Conditions {
+		if condition.Status == string(metav1.ConditionTrue) {
+			continue
+		}
+
+		detail := fmt.Sprintf("condition %s is %s", condition.Type, condition.Status)
+
+		if condition.Reason != constant.EmptyString {
+			detail += ": " + condition.Reason
+		}
+
+		if condition.Message != constant.EmptyString {
+			detail += ": " + condition.Message
+		}
+
+		details = append(details, detail)
+	}
+
+	return fmt.Errorf("%w: %s", errEnvConfigFailed, strings.Join(details, "; "))
+}
+
+const (
+	// countOnce is a constant that is used to apply a file once.
+	countOnce = 1
+
+	// countTwice is a constant that is used to apply a file twice.
+	countTwice = 2
+)
+
+// installActionKind is the type of the kind of an action of the installation.
+type installActionKind string
+
+const (
+	// installActionApply is the kind of the action that applies a file.
+	installActionApply installActionKind = "apply"
+
+	// installActionWait is the kind of the action that waits for the EnvConfig to be in any of the phases.
+	installActionWait installActionKind = "wait"
+)
+
+// installAction is the type that describes an action of the installation: applying a file a number of times, or waiting for the phases.
+type installAction struct {
+	// kind is the kind of the action.
+	kind installActionKind
+	// file is the file that is applied, empty for the waits.
+	file string
+	// count is the number of times the file is applied, 0 for the waits.
+	count int
+	// phases is the list of the phases that are waited for, nil for the applies.
+	phases []string
+}
+
+// String is the function that returns the human-readable description of the action.
+func (a installAction) String() string {
+	if a.kind == installActionWait {
+		return "wait for any of the phases: " + strings.Join(a.phases, ", ")
+	}
+
+	switch a.count {
+	case countOnce:
+		return fmt.Sprintf("apply %s once", a.file)
+	case countTwice:
+		return fmt.Sprintf("apply %s twice", a.file)
+	default:
+		return fmt.Sprintf("apply %s %d times", a.file, a.count)
+	}
+}
+
+// installFiles is the type that contains the files of the installation, as given in the arguments.
+type installFiles struct {
+	// secrets is the secrets file, or nil if it is not given.
+	secrets *string
+	// firstStep is the first step file.
+	firstStep string
+	// secondStep is the second step file.
+	secondStep string
+	// thirdStep is the third step file.
+	thirdStep string
+}
+
+// newInstallFiles returns the files of the installation from the arguments of the command, which begin with the context.
+func newInstallFiles(args []string) installFiles {
+	var files installFiles
+
+	firstStepFileIndex := 1
+
+	if len(args) == maxArgsCount {
+		files.secrets = &args[1]
+
+		firstStepFileIndex = 2
+	}
+
+	files.firstStep = args[firstStepFileIndex]
+	files.secondStep = args[firstStepFileIndex+1]
+	files.thirdStep = args[firstStepFileIndex+2]
+
+	return files
+}
+
+// installPlan returns the ordered actions of the installation beginning from the step and skipping the skipped step, either of which is 0 if it is not
+// set.
+func installPlan(files installFiles, step int, skipStep int) []installAction {
+	var plan []installAction
+
+	apply := func(file string, count int) {
+		plan = append(plan, installAction{kind: installActionApply, file: file, count: count})
+	}
+
+	wait := func(phases []string) {
+		plan = append(plan, installAction{kind: installActionWait, phases: phases})
+	}
+
+	if step == 0 {
+		if files.secrets != nil {
+			apply(*files.secrets, countOnce)
+		}
+
+		if skipStep != 1 {
+			apply(files.firstStep, countTwice)
+			wait(constPhasesToWaitForWithCrossplane)
+		}
+	}
+
+	// nolint:mnd
+	if (step == 0 || step == 2) && skipStep != 2 {
+		wait(constPhasesToWaitForWithCrossplane)
+		apply(files.secondStep, countOnce)
+		wait(constPhasesToWaitFor)
+	}
+
+	// nolint:mnd
+	if skipStep != 3 {
+		wait(constPhasesToWaitFor)
+		apply(files.thirdStep, countOnce)
+		wait(constPhasesToWaitForCompleted)
+	}
+
+	return plan
+}
+
+// writeInstallPlan writes the plan of the installation to the writer, one numbered action per line.
+func writeInstallPlan(w io.Writer, plan []installAction) error {
+	for i, action := range plan {
+		if _, err := fmt.Fprintf(w, "%d. %s\n", i+1, action); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // installCmd is the command to install Private Cloud Kubernetes resources from the YAML files.
 type installCmd struct {
 	// logger is the logger.
@@ -74,10 +349,55 @@ type installCmd struct {
 	cobraCmd *cobra.Command
 	// checkCmd is the Check command.
 	checkCmd *checkCmd
+	// check is the function that runs the check, the run function of the Check command unless replaced in the tests.
+	check func(cobraCmd *cobra.Command, args []string)
+
+	// exec is the function that executes the kubectl invocations.
+	exec execFunc
+	// useContextTimeout is the maximum duration of switching the kubectl context.
+	useContextTimeout time.Duration
+
+	// jitter is the fraction by which the sleep intervals are randomly lengthened or shortened, 0 to disable it.
+	jitter float64
+	// random is the function that returns the random values of the jitter, in the [0, 1) range.
+	random func() float64
+	// clock is the clock that the installation sleeps on between the applies and the polls.
+	clock clock.Clock
+
+	// clientset is the Kubernetes clientset, used to check the EnvConfig controller, nil if the check is disabled.
+	clientset kubernetes.Interface
+
+	// fieldManager is the field manager of the server-side applies, which the fields they set are attributed to.
+	fieldManager string
 }
 
 var _ cmd = &installCmd{}
 
+// readFlags is the function that reads the jitter of the sleep intervals and the field manager of the server-side applies from the flags.
+//
+// It returns an error if the jitter is not in the [0, 1) range or the field manager is blank.
+func (c *installCmd) readFlags(cobraCmd *cobra.Command) error {
+	jitter, err := cobraCmd.Flags().GetFloat64(flagPollJitter)
+	if err != nil {
+		return err
+	}
+
+	if jitter < 0 || jitter >= 1 {
+		return errInvalidPollJitter
+	}
+
+	fieldManager := util.Flag(cobraCmd, flagFieldManager)
+
+	if strings.TrimSpace(fieldManager) == constant.EmptyString {
+		return errInvalidFieldManager
+	}
+
+	c.jitter = jitter
+	c.fieldManager = fieldManager
+
+	return nil
+}
+
 // run is the run function for the Install command.
 //
 // nolint:funlen,gocognit
@@ -95,28 +415,42 @@ func (c *installCmd) run(cobraCmd *cobra.Command, args []string) {
 
 	c.logger.Info(logMsgInstallationStarted)
 
-	context := args[0]
+	// The context is cancelled on SIGINT or SIGTERM, so that any running kubectl invocation is killed instead of being left behind.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	var secretsFile *string
+	// The checks run before and after the installation are cancelled along with it.
+	cobraCmd.SetContext(ctx)
 
-	var firstStepFile, secondStepFile, thirdStepFile string
+	// The flags are validated before the check, which can take minutes, so that an invalid one is reported right away.
+	if err := c.readFlags(cobraCmd); err != nil {
+		c.logger.Fatal(err)
+	}
 
-	firstStepFileIndex := 1
+	kubeContext := args[0]
 
-	if len(args) == maxArgsCount {
-		secretsFile = &args[1]
+	files := newInstallFiles(args)
 
-		firstStepFileIndex = 2
+	step := util.FlagInt(cobraCmd, flagStep)
+	skipStep := util.FlagInt(cobraCmd, flagSkipStep)
+
+	// Step is 0 if the flag is not set, so we don't return an error in that case.
+	if step != 0 && step != 2 && step != 3 {
+		c.logger.Fatal(errInvalidStep)
 	}
 
-	firstStepFile = args[firstStepFileIndex]
+	plan := installPlan(files, step, skipStep)
 
-	secondStepFile = args[firstStepFileIndex+1]
+	if util.FlagBool(cobraCmd, flagPlan) {
+		if err := writeInstallPlan(cobraCmd.OutOrStdout(), plan); err != nil {
+			c.logger.Fatal(err)
+		}
 
-	thirdStepFile = args[firstStepFileIndex+2]
+		return
+	}
 
 	if !util.FlagBool(cobraCmd, flagForce) {
-		c.checkCmd.run(cobraCmd, []string{firstStepFile})
+		c.check(cobraCmd, []string{files.firstStep})
 	}
 
 	if _, err := exec.LookPath(kubectlBin); err != nil {
@@ -125,70 +459,183 @@ func (c *installCmd) run(cobraCmd *cobra.Command, args []string) {
 
 	c.logger.Debug(logMsgKubectlChecked)
 
-	if err := util.Exec(c.logger, nil, kubectlBin, "config", "use-context", context); err != nil {
+	if err := c.useContext(ctx, kubeContext); err != nil {
 		c.logger.Fatal(err)
 	}
 
-	const (
-		// countOnce is a constant that is used to apply a file once.
-		countOnce = 1
-
-		// countTwice is a constant that is used to apply a file twice.
-		countTwice = 2
-	)
-
-	step := util.FlagInt(cobraCmd, flagStep)
-	skipStep := util.FlagInt(cobraCmd, flagSkipStep)
+	if util.FlagBool(cobraCmd, flagCheckController) {
+		if err := c.setupClientset(cobraCmd); err != nil {
+			c.logger.Fatal(err)
+		}
+	}
 
-	// Step is 0 if the flag is not set, so we don't return an error in that case.
-	if step != 0 && step != 2 && step != 3 {
-		c.logger.Fatal(errInvalidStep)
+	if util.FlagBool(cobraCmd, flagValidateOnly) {
+		if err := c.validateFiles(ctx, plan); err != nil {
+			c.logger.Fatal(err)
+		}
 	}
 
-	// nolint:nestif
-	if step == 0 || (step != 2 && step != 3) {
-		if secretsFile != nil {
-			if err := c.applyFile(*secretsFile, countOnce); err != nil {
+	for _, action := range plan {
+		switch action.kind {
+		case installActionApply:
+			if err := c.applyFile(ctx, action.file, action.count); err != nil {
 				c.logger.Fatal(err)
 			}
+		case installActionWait:
+			c.waitForPhases(ctx, action.phases)
 		}
+	}
 
-		if skipStep != 1 {
-			if err := c.applyFile(firstStepFile, countTwice); err != nil {
-				c.logger.Fatal(err)
-			}
+	c.logger.Info(logMsgInstallationCompleted)
 
-			c.waitForPhases(constPhasesToWaitForWithCrossplane)
+	if util.FlagBool(cobraCmd, flagVerify) {
+		c.verify(ctx, cobraCmd, files.firstStep)
+	}
+}
+
+// verify is the function that re-runs the check once the EnvConfig is in the completed phase, confirming that the installed environment is healthy.
+//
+// The check logs with a prefix, telling its results apart from the ones of the check run before the installation. As with the latter, a failure of the
+// check exits the command.
+func (c *installCmd) verify(ctx context.Context, cobraCmd *cobra.Command, firstStepFile string) {
+	const (
+		// logPrefixVerify is the prefix of the log lines of the check run after the installation.
+		logPrefixVerify = "post-install check"
+
+		// logMsgVerifying is the message that is logged when the check is run after the installation.
+		logMsgVerifying = "verifying installation"
+
+		// logMsgVerified is the message that is logged when the check run after the installation succeeds.
+		logMsgVerified = "installation verified"
+	)
+
+	// The phases are waited for again, as the installation may have skipped the third phase, which waits for the completed one.
+	c.waitForPhases(ctx, constPhasesToWaitForCompleted)
+
+	c.logger.Info(logMsgVerifying)
+
+	c.checkCmd.logger = c.logger.WithPrefix(logPrefixVerify)
+
+	c.check(cobraCmd, []string{firstStepFile})
+
+	c.logger.Info(logMsgVerified)
+}
+
+// kubectl is the function that runs kubectl with the given arguments, killing it if it does not complete within the configured timeout or if
+// the context is cancelled.
+func (c *installCmd) kubectl(ctx context.Context, outBuf *bytes.Buffer, args ...string) error {
+	ctx, cancel := context.WithTimeout(ctx, util.FlagDuration(c.cobraCmd, flagKubectlTimeout))
+	defer cancel()
+
+	return c.exec(ctx, c.logger, outBuf, kubectlBin, args...)
+}
+
+// useContext is the function that switches kubectl to the given context, failing fast if it does not complete within a short timeout, as some
+// credentials plugins hang instead of failing.
+func (c *installCmd) useContext(ctx context.Context, kubeContext string) error {
+	ctx, cancel := context.WithTimeout(ctx, c.useContextTimeout)
+	defer cancel()
+
+	if err := c.exec(ctx, c.logger, nil, kubectlBin, "config", "use-context", kubeContext); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return multierr.Combine(errKubectlUseContextTimedOut, err)
 		}
+
+		return err
 	}
 
-	// nolint:nestif
-	if (step == 0 || (step == 2 && step != 3)) && skipStep != 2 {
-		c.waitForPhases(constPhasesToWaitForWithCrossplane)
+	return nil
+}
 
-		if err := c.applyFile(secondStepFile, countOnce); err != nil {
-			c.logger.Fatal(err)
+// setupClientset is the function that sets up the Kubernetes clientset from the configuration of the context that kubectl is switched to.
+func (c *installCmd) setupClientset(cobraCmd *cobra.Command) error {
+	kubeConfig, path, err := kubeutil.Config(util.Flag(cobraCmd, flagKubeConfig), util.Flag(cobraCmd, flagKubeConfigData))
+	if err != nil {
+		return multierr.Combine(errFailedToGetKubeConfig, err)
+	}
+
+	c.logger.Debugf(logMsgKubeLoadedConfig, path)
+
+	if c.clientset, err = kubernetes.NewForConfig(kubeConfig); err != nil {
+		return multierr.Combine(errFailedToCreateKubernetesClientset, err)
+	}
+
+	c.logger.Debug(logMsgKubeClientsetCreated)
+
+	return nil
+}
+
+// checkController is the function that checks that the Deployment of the EnvConfig controller exists and that none of its pods is crash-looping.
+//
+// It only warns if the Deployment has no available replicas otherwise, as the controller may still be starting.
+func (c *installCmd) checkController(ctx context.Context) error {
+	const (
+		// logMsgControllerNotAvailable is the message that is logged when the EnvConfig controller has no available replicas.
+		logMsgControllerNotAvailable = "EnvConfig controller %s/%s has no available replicas yet"
+	)
+
+	deployment, err := c.clientset.AppsV1().Deployments(envConfigControllerNamespace).Get(ctx, envConfigControllerName, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return fmt.Errorf("%w: Deployment %s/%s not found", errEnvConfigControllerNotRunning, envConfigControllerNamespace, envConfigControllerName)
 		}
 
-		c.waitForPhases(constPhasesToWaitFor)
+		return multierr.Combine(errFailedToGetEnvConfigController, err)
 	}
 
-	// nolint:mnd
-	if skipStep != 3 {
-		c.waitForPhases(constPhasesToWaitFor)
+	if deployment.Status.AvailableReplicas > 0 {
+		return nil
+	}
 
-		if err := c.applyFile(thirdStepFile, countOnce); err != nil {
-			c.logger.Fatal(err)
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return multierr.Combine(errFailedToGetEnvConfigController, err)
+	}
+
+	pods, err := c.clientset.CoreV1().Pods(envConfigControllerNamespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return multierr.Combine(errFailedToGetEnvConfigController, err)
+	}
+
+	for _, pod := range pods.Items {
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.State.Waiting != nil && status.State.Waiting.Reason == reasonCrashLoopBackOff {
+				return fmt.Errorf("%w: pod %s/%s is crash-looping", errEnvConfigControllerNotRunning, pod.Namespace, pod.Name)
+			}
 		}
+	}
+
+	c.logger.Warnf(logMsgControllerNotAvailable, envConfigControllerNamespace, envConfigControllerName)
+
+	return nil
+}
+
+// sleepFor is the function that sleeps for the interval with the jitter applied, logging the actual duration at the level.
+//
+// It returns the error of the context if it is done before the interval elapses.
+func (c *installCmd) sleepFor(ctx context.Context, level log.Level, interval time.Duration) error {
+	d := util.Jitter(interval, c.jitter, c.random)
 
-		c.waitForPhases(constPhasesToWaitForCompleted)
+	c.logger.Logf(level, logMsgSleeping, d)
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-c.clock.After(d):
+		return nil
 	}
+}
 
-	c.logger.Info(logMsgInstallationCompleted)
+// applyArgs is the function that returns the arguments of kubectl for the server-side apply of the file under the field manager, with the extra arguments
+// before the file.
+func (c *installCmd) applyArgs(file string, extra ...string) []string {
+	args := []string{"apply", "--server-side", "--force-conflicts", "--field-manager=" + c.fieldManager}
+
+	return append(append(args, extra...), "-f", file)
 }
 
 // applyFile is the function that applies the file.
-func (c *installCmd) applyFile(file string, count int) error {
+func (c *installCmd) applyFile(ctx context.Context, file string, count int) error {
 	const (
 		// errExitStatusOne is the error that is returned when the exit status is 1.
 		errExitStatusOne = "exit status 1"
@@ -209,7 +656,7 @@ func (c *installCmd) applyFile(file string, count int) error {
 	c.logger.Infof(logMsgApplyingFile, file)
 
 	for i := 0; i < count; i++ {
-		if err := util.Exec(c.logger, nil, kubectlBin, "apply", "--server-side", "--force-conflicts", "-f", file); err != nil {
+		if err := c.kubectl(ctx, nil, c.applyArgs(file)...); err != nil {
 			// If the resource mapping is not found on the first apply and the requested apply count is greater than 1,
 			// then we can safely ignore the error and proceed to the next apply.
 			if count > 1 && i == 0 && strings.Contains(err.Error(), errExitStatusOne) {
@@ -219,9 +666,9 @@ func (c *installCmd) applyFile(file string, count int) error {
 			}
 		}
 
-		c.logger.Infof(logMsgSleeping, sleepInterval)
-
-		time.Sleep(sleepInterval)
+		if err := c.sleepFor(ctx, log.InfoLevel, sleepInterval); err != nil {
+			return err
+		}
 	}
 
 	c.logger.Infof(logMsgFileApplied, file)
@@ -229,8 +676,50 @@ func (c *installCmd) applyFile(file string, count int) error {
 	return nil
 }
 
+// validateFiles is the function that validates each file that the plan applies with a server-side dry run of its apply, so that the schema and admission
+// errors are caught before anything is applied.
+//
+// It returns nothing on success, or the errors of all of the files that are rejected on failure.
+func (c *installCmd) validateFiles(ctx context.Context, plan []installAction) error {
+	const (
+		// logMsgValidatingFile is the message that is logged when validating the file.
+		logMsgValidatingFile = "validating file %s with a server-side dry run..."
+
+		// logMsgFileValidated is the message that is logged when the file is validated.
+		logMsgFileValidated = "file %s validated"
+	)
+
+	var (
+		errs      error
+		validated []string
+	)
+
+	for _, action := range plan {
+		if action.kind != installActionApply || slices.Contains(validated, action.file) {
+			continue
+		}
+
+		validated = append(validated, action.file)
+
+		c.logger.Infof(logMsgValidatingFile, action.file)
+
+		if err := c.kubectl(ctx, nil, c.applyArgs(action.file, "--dry-run=server")...); err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("%w: %s: %w", errServerDryRunFailed, action.file, err))
+
+			continue
+		}
+
+		c.logger.Infof(logMsgFileValidated, action.file)
+	}
+
+	return errs
+}
+
 // waitForPhases is the function that waits for the phase of the EnvConfig to be one of the phases in the list.
-func (c *installCmd) waitForPhases(phases []string) {
+//
+// If the clientset is set up, it fails as soon as the EnvConfig controller is found not to be running, instead of waiting for a phase that never comes. It
+// also fails as soon as the EnvConfig is in one of the failed phases, with its message and the conditions that are not True.
+func (c *installCmd) waitForPhases(ctx context.Context, phases []string) {
 	const (
 		// logMsgWaitingForPhases is the message that is logged when waiting for the EnvConfig to be in any of the specified phases.
 		logMsgWaitingForPhases = "waiting for environment to be in any of the following phases: %s (current phase: %s)"
@@ -246,9 +735,15 @@ func (c *installCmd) waitForPhases(phases []string) {
 	const sleepInterval = 30 * time.Second
 
 	for {
+		if c.clientset != nil {
+			if err := c.checkController(ctx); err != nil {
+				c.logger.Fatal(err)
+			}
+		}
+
 		var outBuf bytes.Buffer
 
-		if err := util.Exec(c.logger, &outBuf, kubectlBin, "get", "envconfig", "-o", "json"); err != nil {
+		if err := c.kubectl(ctx, &outBuf, "get", "envconfig", "-o", "json"); err != nil {
 			c.logger.Fatal(err)
 		}
 
@@ -257,10 +752,7 @@ func (c *installCmd) waitForPhases(phases []string) {
 			// Items is the list of items.
 			Items []struct {
 				// Status is the status of the item.
-				Status struct {
-					// Phase is the phase of the item.
-					Phase string `json:"phase"`
-				} `json:"status"`
+				Status envConfigStatus `json:"status"`
 			} `json:"items"`
 		}
 
@@ -284,22 +776,36 @@ func (c *installCmd) waitForPhases(phases []string) {
 			break
 		}
 
-		c.logger.Debugf(logMsgSleeping, sleepInterval)
+		// The failed phases are not left without a fix, so the wait fails at once rather than forever.
+		if err := failedPhaseError(data.Items[0].Status); err != nil {
+			c.logger.Fatal(err)
+		}
 
-		time.Sleep(sleepInterval)
+		if err := c.sleepFor(ctx, log.DebugLevel, sleepInterval); err != nil {
+			c.logger.Fatal(err)
+		}
 	}
 }
 
 // newInstallCmd is the constructor for the installCmd.
 func newInstallCmd(logger *log.Logger, cobraCmd *cobra.Command) *installCmd {
 	return &installCmd{
-		logger:   logger,
-		cobraCmd: cobraCmd,
+		logger:            logger,
+		cobraCmd:          cobraCmd,
+		exec:              util.ExecContext,
+		useContextTimeout: useContextTimeout,
+		jitter:            defaultPollJitter,
+		random:            rand.Float64,
+		clock:             clock.Real{},
+		fieldManager:      defaultFieldManager,
 	}
 }
 
 // Install returns a Cobra command to install Private Cloud Kubernetes resources from the YAML files.
 func Install(logger *log.Logger) *cobra.Command {
+	// defaultKubectlTimeout is the default maximum duration of a single kubectl invocation.
+	const defaultKubectlTimeout = 10 * time.Minute
+
 	cobraCmd := &cobra.Command{
 		Use:   "install <context> [<secrets_file>] <first_step_file> <second_step_file> <third_step_file>",
 		Short: "Install Private Cloud",
@@ -309,6 +815,7 @@ func Install(logger *log.Logger) *cobra.Command {
 	cmd := newInstallCmd(logger, cobraCmd)
 
 	cmd.checkCmd = newCheckCmd(logger, cobraCmd)
+	cmd.check = cmd.checkCmd.run
 
 	cobraCmd.Long = cmd.checkCmd.longMsg("Install installs Private Cloud Kubernetes resources from the specified YAML files.")
 
@@ -317,6 +824,21 @@ func Install(logger *log.Logger) *cobra.Command {
 	cobraCmd.Flags().BoolP(flagForce, flagForceShort, false, "force the installation")
 	cobraCmd.Flags().Int(flagStep, 0, "the installation step to begin from; valid values are 2 or 3")
 	cobraCmd.Flags().Int(flagSkipStep, 0, "the installation step to skip; valid values are 1, 2 or 3")
+	cobraCmd.Flags().Duration(flagKubectlTimeout, defaultKubectlTimeout, "the maximum duration of a single kubectl invocation")
+	cobraCmd.Flags().Float64(
+		flagPollJitter,
+		defaultPollJitter,
+		"the fraction, less than 1, by which the sleep intervals between the applies and the polls are randomly lengthened or shortened; 0 disables it",
+	)
+	cobraCmd.Flags().Bool(flagVerify, false, "run the check again once the installation is completed, confirming that the installed environment is healthy")
+	cobraCmd.Flags().Bool(flagCheckController, false, "fail if the EnvConfig controller is not running while waiting for the phases")
+	cobraCmd.Flags().String(flagFieldManager, defaultFieldManager, "the field manager of the server-side applies, which the fields they set are attributed to")
+	cobraCmd.Flags().Bool(
+		flagValidateOnly,
+		false,
+		"validate the files with a server-side dry run of their applies before applying any of them, failing the installation if one is rejected",
+	)
+	cobraCmd.Flags().Bool(flagPlan, false, "print the ordered applies and waits of the installation without executing them, the check included")
 
 	cmd.checkCmd.flags(false)
 
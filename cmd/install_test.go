@@ -0,0 +1,548 @@
+// Package cmd is the package that contains all of the commands for the application.
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/clock"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// TestInstallCmd_useContext is a test that tests the useContext function of the installCmd.
+func TestInstallCmd_useContext(t *testing.T) {
+	// errExitStatusOne is the error that is returned by the fake exec when kubectl fails.
+	errExitStatusOne := errors.New("exit status 1")
+
+	testCases := []struct {
+		name    string
+		exec    execFunc
+		wantErr error
+	}{
+		{
+			name: "success",
+			exec: func(context.Context, *log.Logger, *bytes.Buffer, string, ...string) error {
+				return nil
+			},
+		},
+		{
+			name: "failure",
+			exec: func(context.Context, *log.Logger, *bytes.Buffer, string, ...string) error {
+				return errExitStatusOne
+			},
+			wantErr: errExitStatusOne,
+		},
+		{
+			name: "hang",
+			exec: func(ctx context.Context, _ *log.Logger, _ *bytes.Buffer, _ string, _ ...string) error {
+				<-ctx.Done()
+
+				return ctx.Err()
+			},
+			wantErr: errKubectlUseContextTimedOut,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var gotArgs []string
+
+			c := newInstallCmd(log.New(io.Discard), &cobra.Command{})
+			c.useContextTimeout = 10 * time.Millisecond
+			c.exec = func(ctx context.Context, l *log.Logger, outBuf *bytes.Buffer, bin string, args ...string) error {
+				gotArgs = append([]string{bin}, args...)
+
+				return tc.exec(ctx, l, outBuf, bin, args...)
+			}
+
+			err := c.useContext(context.Background(), "test-context")
+
+			assert.Equal(t, []string{kubectlBin, "config", "use-context", "test-context"}, gotArgs)
+
+			if tc.wantErr == nil {
+				require.NoError(t, err)
+
+				return
+			}
+
+			require.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+// TestInstallCmd_plan is a test that tests that the plan flag prints the ordered applies and waits of the installation for the step flags, without
+// executing them.
+//
+// nolint:funlen
+func TestInstallCmd_plan(t *testing.T) {
+	// waitCrossplane is the line of the wait for the phases with Crossplane.
+	const waitCrossplane = "wait for any of the phases: Deploying, ConfiguringSolr, Bootstrap, Ready, Crossplane"
+
+	// waitThird is the line of the wait for the phases to proceed to the third step.
+	const waitThird = "wait for any of the phases: Deploying, ConfiguringSolr, Bootstrap, Ready"
+
+	// waitCompleted is the line of the wait for the phases of the completed installation.
+	const waitCompleted = "wait for any of the phases: Ready"
+
+	files := []string{"ctx", "secrets.yaml", "first.yaml", "second.yaml", "third.yaml"}
+
+	testCases := []struct {
+		name  string
+		files []string
+		args  []string
+		want  []string
+	}{
+		{
+			name:  "Default",
+			files: files,
+			want: []string{
+				"apply secrets.yaml once", "apply first.yaml twice", waitCrossplane,
+				waitCrossplane, "apply second.yaml once", waitThird,
+				waitThird, "apply third.yaml once", waitCompleted,
+			},
+		},
+		{
+			name:  "Without the secrets file",
+			files: []string{"ctx", "first.yaml", "second.yaml", "third.yaml"},
+			want: []string{
+				"apply first.yaml twice", waitCrossplane,
+				waitCrossplane, "apply second.yaml once", waitThird,
+				waitThird, "apply third.yaml once", waitCompleted,
+			},
+		},
+		{
+			name:  "From the second step",
+			files: files,
+			args:  []string{"--" + flagStep, "2"},
+			want:  []string{waitCrossplane, "apply second.yaml once", waitThird, waitThird, "apply third.yaml once", waitCompleted},
+		},
+		{
+			name:  "From the third step",
+			files: files,
+			args:  []string{"--" + flagStep, "3"},
+			want:  []string{waitThird, "apply third.yaml once", waitCompleted},
+		},
+		{
+			name:  "Skipping the first step",
+			files: files,
+			args:  []string{"--" + flagSkipStep, "1"},
+			want:  []string{"apply secrets.yaml once", waitCrossplane, "apply second.yaml once", waitThird, waitThird, "apply third.yaml once", waitCompleted},
+		},
+		{
+			name:  "Skipping the second step",
+			files: files,
+			args:  []string{"--" + flagSkipStep, "2"},
+			want:  []string{"apply secrets.yaml once", "apply first.yaml twice", waitCrossplane, waitThird, "apply third.yaml once", waitCompleted},
+		},
+		{
+			name:  "From the second step, skipping the third",
+			files: files,
+			args:  []string{"--" + flagStep, "2", "--" + flagSkipStep, "3"},
+			want:  []string{waitCrossplane, "apply second.yaml once", waitThird},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			cobraCmd := Install(log.New(io.Discard))
+			cobraCmd.SetOut(&buf)
+			cobraCmd.SetArgs(append(append([]string{"--" + flagPlan}, tc.args...), tc.files...))
+
+			require.NoError(t, cobraCmd.Execute())
+
+			want := make([]string, len(tc.want))
+
+			for i, line := range tc.want {
+				want[i] = fmt.Sprintf("%d. %s", i+1, line)
+			}
+
+			assert.Equal(t, want, strings.Split(strings.TrimSpace(buf.String()), "\n"))
+		})
+	}
+}
+
+// TestInstallCmd_checkController is a test that tests that the checkController function fails when, and only when, the EnvConfig controller is absent or
+// crash-looping.
+//
+// nolint:funlen
+func TestInstallCmd_checkController(t *testing.T) {
+	// labels is the labels of the pods of the EnvConfig controller used in the test.
+	labels := map[string]string{"app": envConfigControllerName}
+
+	deployment := func(availableReplicas int32) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: envConfigControllerName, Namespace: envConfigControllerNamespace},
+			Spec:       appsv1.DeploymentSpec{Selector: &metav1.LabelSelector{MatchLabels: labels}},
+			Status:     appsv1.DeploymentStatus{AvailableReplicas: availableReplicas},
+		}
+	}
+
+	pod := func(name string, namespace string, reason string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{
+					{State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: reason}}},
+				},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		objects  []runtime.Object
+		wantErr  string
+		wantWarn bool
+	}{
+		{
+			name:    "Absent",
+			wantErr: "EnvConfig controller not running: Deployment platform/envconfig-controller not found",
+		},
+		{
+			name:    "Available",
+			objects: []runtime.Object{deployment(1)},
+		},
+		{
+			name:    "Crash-looping",
+			objects: []runtime.Object{deployment(0), pod("controller", envConfigControllerNamespace, reasonCrashLoopBackOff)},
+			wantErr: "EnvConfig controller not running: pod platform/controller is crash-looping",
+		},
+		{
+			name:     "Starting",
+			objects:  []runtime.Object{deployment(0), pod("controller", envConfigControllerNamespace, "ContainerCreating")},
+			wantWarn: true,
+		},
+		{
+			name:     "Crash-looping elsewhere",
+			objects:  []runtime.Object{deployment(0), pod("controller", constant.NamespaceAlphaSense, reasonCrashLoopBackOff)},
+			wantWarn: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			c := newInstallCmd(log.New(&buf), &cobra.Command{})
+			c.clientset = fake.NewClientset(tc.objects...)
+
+			err := c.checkController(context.Background())
+
+			if tc.wantErr != constant.EmptyString {
+				require.ErrorIs(t, err, errEnvConfigControllerNotRunning)
+				assert.EqualError(t, err, tc.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			if tc.wantWarn {
+				assert.Contains(t, buf.String(), "has no available replicas yet")
+			} else {
+				assert.Empty(t, buf.String())
+			}
+		})
+	}
+}
+
+// TestInstallCmd_verify is a test that tests that the verify function runs the check, with its log lines prefixed, only once the EnvConfig is in the completed
+// phase.
+func TestInstallCmd_verify(t *testing.T) {
+	// firstStepFile is the path to the first step file used in the test.
+	const firstStepFile = "step1.yaml"
+
+	var (
+		buf   bytes.Buffer
+		calls []string
+	)
+
+	cobraCmd := &cobra.Command{}
+	cobraCmd.Flags().Duration(flagKubectlTimeout, time.Minute, constant.EmptyString)
+
+	c := newInstallCmd(log.New(&buf), cobraCmd)
+	c.checkCmd = newCheckCmd(log.New(io.Discard), cobraCmd)
+	c.exec = func(_ context.Context, _ *log.Logger, outBuf *bytes.Buffer, bin string, args ...string) error {
+		calls = append(calls, strings.Join(append([]string{bin}, args...), " "))
+
+		outBuf.WriteString(`{"items":[{"status":{"phase":"Ready"}}]}`)
+
+		return nil
+	}
+	c.check = func(_ *cobra.Command, args []string) {
+		calls = append(calls, "check "+strings.Join(args, " "))
+
+		c.checkCmd.logger.Info("checked")
+	}
+
+	c.verify(context.Background(), cobraCmd, firstStepFile)
+
+	assert.Equal(t, []string{"kubectl get envconfig -o json", "check " + firstStepFile}, calls)
+	assert.Contains(t, buf.String(), "post-install check: checked")
+	assert.Contains(t, buf.String(), "installation verified")
+}
+
+// TestInstallCmd_validateFiles is a test that tests that the validateFiles function dry runs each file of the plan once on the server, reporting the files
+// that are rejected.
+func TestInstallCmd_validateFiles(t *testing.T) {
+	// errInvalid is the error that is returned by the fake exec when the server rejects the second step file.
+	errInvalid := errors.New(`exit status 1: The Deployment "web" is invalid: spec.replicas: Invalid value: -1`)
+
+	plan := installPlan(newInstallFiles([]string{"ctx", "first.yaml", "second.yaml", "third.yaml"}), 0, 0)
+
+	testCases := []struct {
+		name    string
+		reject  string
+		wantErr bool
+	}{
+		{name: "Valid"},
+		{name: "Rejected", reject: "second.yaml", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls []string
+
+			cobraCmd := &cobra.Command{}
+			cobraCmd.Flags().Duration(flagKubectlTimeout, time.Minute, constant.EmptyString)
+
+			c := newInstallCmd(log.New(io.Discard), cobraCmd)
+			c.exec = func(_ context.Context, _ *log.Logger, _ *bytes.Buffer, bin string, args ...string) error {
+				calls = append(calls, strings.Join(append([]string{bin}, args...), " "))
+
+				if args[len(args)-1] == tc.reject {
+					return errInvalid
+				}
+
+				return nil
+			}
+
+			err := c.validateFiles(context.Background(), plan)
+
+			assert.Equal(t, []string{
+				"kubectl apply --server-side --force-conflicts --field-manager=privatecloud-cli --dry-run=server -f first.yaml",
+				"kubectl apply --server-side --force-conflicts --field-manager=privatecloud-cli --dry-run=server -f second.yaml",
+				"kubectl apply --server-side --force-conflicts --field-manager=privatecloud-cli --dry-run=server -f third.yaml",
+			}, calls)
+
+			if !tc.wantErr {
+				require.NoError(t, err)
+
+				return
+			}
+
+			require.ErrorIs(t, err, errServerDryRunFailed)
+			require.ErrorIs(t, err, errInvalid)
+			assert.ErrorContains(t, err, "second.yaml")
+			assert.ErrorContains(t, err, `Deployment "web" is invalid`)
+		})
+	}
+}
+
+// TestInstallCmd_applyFile_fieldManager is a test that tests that the applyFile function forwards the field manager to each server-side apply.
+func TestInstallCmd_applyFile_fieldManager(t *testing.T) {
+	testCases := []struct {
+		name         string
+		fieldManager string
+		want         string
+	}{
+		{name: "Default", want: "--field-manager=privatecloud-cli"},
+		{name: "Custom", fieldManager: "argocd", want: "--field-manager=argocd"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls []string
+
+			cobraCmd := &cobra.Command{}
+			cobraCmd.Flags().Duration(flagKubectlTimeout, time.Minute, constant.EmptyString)
+
+			c := newInstallCmd(log.New(io.Discard), cobraCmd)
+			c.clock = clock.NewFake(time.Time{})
+			c.exec = func(_ context.Context, _ *log.Logger, _ *bytes.Buffer, bin string, args ...string) error {
+				calls = append(calls, strings.Join(append([]string{bin}, args...), " "))
+
+				return nil
+			}
+
+			if tc.fieldManager != constant.EmptyString {
+				c.fieldManager = tc.fieldManager
+			}
+
+			require.NoError(t, c.applyFile(context.Background(), "first.yaml", countTwice))
+
+			want := "kubectl apply --server-side --force-conflicts " + tc.want + " -f first.yaml"
+
+			assert.Equal(t, []string{want, want}, calls)
+		})
+	}
+}
+
+// TestInstallCmd_sleepFor is a test that tests that the sleepFor function sleeps for the interval with the jitter applied, and for the interval itself when
+// the jitter is disabled.
+func TestInstallCmd_sleepFor(t *testing.T) {
+	testCases := []struct {
+		name   string
+		jitter float64
+		random float64
+		want   time.Duration
+	}{
+		{name: "Disabled", jitter: 0, random: 0, want: time.Minute},
+		{name: "Shortest", jitter: 0.1, random: 0, want: 54 * time.Second},
+		{name: "Longer", jitter: 0.1, random: 0.75, want: 63 * time.Second},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			clk := clock.NewFake(time.Time{})
+
+			c := newInstallCmd(log.New(io.Discard), &cobra.Command{})
+			c.jitter = tc.jitter
+			c.random = func() float64 { return tc.random }
+			c.clock = clk
+
+			require.NoError(t, c.sleepFor(context.Background(), log.DebugLevel, time.Minute))
+
+			assert.Equal(t, []time.Duration{tc.want}, clk.Sleeps())
+		})
+	}
+}
+
+// cancellingClock is the type of the clock that cancels the context when it is waited for, and never fires, as if SIGINT were received during a sleep.
+type cancellingClock struct {
+	*clock.Fake
+
+	// cancel is the function that cancels the context.
+	cancel context.CancelFunc
+}
+
+// After is the function that cancels the context and returns a channel that never receives.
+func (c cancellingClock) After(time.Duration) <-chan time.Time {
+	c.cancel()
+
+	return make(chan time.Time)
+}
+
+// TestInstallCmd_sleepFor_cancelled is a test that tests that the sleepFor function returns the error of the context once it is cancelled during the sleep,
+// rather than sleeping for the whole interval, and that the apply loop stops with it.
+func TestInstallCmd_sleepFor_cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var calls int
+
+	cobraCmd := &cobra.Command{}
+	cobraCmd.Flags().Duration(flagKubectlTimeout, time.Minute, constant.EmptyString)
+
+	c := newInstallCmd(log.New(io.Discard), cobraCmd)
+	c.clock = cancellingClock{Fake: clock.NewFake(time.Time{}), cancel: cancel}
+	c.exec = func(context.Context, *log.Logger, *bytes.Buffer, string, ...string) error {
+		calls++
+
+		return nil
+	}
+
+	require.ErrorIs(t, c.applyFile(ctx, "first.yaml", countTwice), context.Canceled)
+	assert.Equal(t, 1, calls)
+}
+
+// TestInstallCmd_readFlags is a test that tests that the readFlags function reads the jitter and the field manager from the flags, and returns an error for
+// the invalid ones.
+func TestInstallCmd_readFlags(t *testing.T) {
+	testCases := []struct {
+		name             string
+		flags            map[string]string
+		wantJitter       float64
+		wantFieldManager string
+		wantErr          error
+	}{
+		{name: "Default", wantJitter: defaultPollJitter, wantFieldManager: defaultFieldManager},
+		{name: "Custom", flags: map[string]string{flagPollJitter: "0.5", flagFieldManager: "argocd"}, wantJitter: 0.5, wantFieldManager: "argocd"},
+		{name: "Negative jitter", flags: map[string]string{flagPollJitter: "-0.1"}, wantErr: errInvalidPollJitter},
+		{name: "Jitter of 1", flags: map[string]string{flagPollJitter: "1"}, wantErr: errInvalidPollJitter},
+		{name: "Blank field manager", flags: map[string]string{flagFieldManager: " "}, wantErr: errInvalidFieldManager},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cobraCmd := Install(log.New(io.Discard))
+
+			for name, value := range tc.flags {
+				require.NoError(t, cobraCmd.Flags().Set(name, value))
+			}
+
+			c := newInstallCmd(log.New(io.Discard), cobraCmd)
+
+			err := c.readFlags(cobraCmd)
+
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.InDelta(t, tc.wantJitter, c.jitter, 0)
+			assert.Equal(t, tc.wantFieldManager, c.fieldManager)
+		})
+	}
+}
+
+// TestFailedPhaseError is a test that tests that the failedPhaseError function fails only on the failed phases of the EnvConfig, with its message and the
+// conditions that are not True.
+func TestFailedPhaseError(t *testing.T) {
+	testCases := []struct {
+		name    string
+		status  string
+		wantErr string
+	}{
+		{name: "Ready", status: `{"phase":"Ready"}`},
+		{name: "Deploying", status: `{"phase":"Deploying","message":"waiting for the deployments"}`},
+		{name: "Empty", status: `{}`},
+		{
+			name: "Failed",
+			status: `{"phase":"Failed","message":"Solr bootstrap failed","conditions":[` +
+				`{"type":"Crossplane","status":"True"},` +
+				`{"type":"Ready","status":"False","reason":"BootstrapError","message":"job solr-bootstrap failed"}]}`,
+			wantErr: "EnvConfig failed: phase Failed; Solr bootstrap failed; condition Ready is False: BootstrapError: job solr-bootstrap failed",
+		},
+		{name: "Error", status: `{"phase":"Error"}`, wantErr: "EnvConfig failed: phase Error"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var status envConfigStatus
+
+			require.NoError(t, json.Unmarshal([]byte(tc.status), &status))
+
+			err := failedPhaseError(status)
+
+			if tc.wantErr == constant.EmptyString {
+				require.NoError(t, err)
+
+				return
+			}
+
+			require.ErrorIs(t, err, errEnvConfigFailed)
+			assert.EqualError(t, err, tc.wantErr)
+		})
+	}
+}
@@ -0,0 +1,342 @@
+// Package cmd is the package that contains all of the commands for the application.
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"slices"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/cloud"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/cloud/awscloudutil"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/cloud/azurecloudutil"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/cloud/gcpcloudutil"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/envconfig"
+	pkgerrors "github.com/AlphaSense-Engineering/privatecloud-cli/pkg/errors"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/mysqlchecker"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/postgresqlchecker"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/smtpchecker"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/ssochecker"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/tlschecker"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/k8s/kubeutil"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/util"
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+	"go.uber.org/multierr"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// errInvalidInventoryOutputFormat is the error that is returned when the output format of the inventory is invalid.
+	errInvalidInventoryOutputFormat = errors.New("invalid output format: must be " + outputFormatJSON + " or " + outputFormatYAML)
+
+	// errFailedToWriteInventory is the error that is returned when the inventory cannot be written.
+	errFailedToWriteInventory = errors.New("failed to write inventory")
+)
+
+// outputFormatYAML is the output format for machines that prefer YAML.
+const outputFormatYAML = "yaml"
+
+// inventorySecret is the type that describes a secret that a check reads.
+type inventorySecret struct {
+	// Check is the name of the check that reads the secret.
+	Check string `json:"check" yaml:"check"`
+	// Namespace is the namespace of the secret.
+	Namespace string `json:"namespace" yaml:"namespace"`
+	// Name is the name of the secret, empty if the secrets are selected by the label selector.
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	// Selector is the label selector of the secrets, set only if the check selects them by it rather than by name.
+	Selector string `json:"selector,omitempty" yaml:"selector,omitempty"`
+	// Keys is the keys of the secret that the check reads, set only for the checks whose keys can be replaced.
+	Keys []string `json:"keys,omitempty" yaml:"keys,omitempty"`
+}
+
+// inventoryCrossplane is the type that describes the Crossplane resources that the checks expect on the cloud provider.
+type inventoryCrossplane struct {
+	// RoleName is the name of the Crossplane role, empty on GCP.
+	RoleName string `json:"roleName,omitempty" yaml:"roleName,omitempty"`
+	// RoleARN is the ARN of the Crossplane role, set only on AWS.
+	RoleARN string `json:"roleARN,omitempty" yaml:"roleARN,omitempty"`
+	// Namespace is the namespace of the service accounts of the Crossplane providers.
+	Namespace string `json:"namespace" yaml:"namespace"`
+	// ServiceAccount is the name of the service account in the namespace of the service accounts of the Crossplane providers that the pod ensures.
+	ServiceAccount string `json:"serviceAccount" yaml:"serviceAccount"`
+	// GCPServiceAccount is the GCP service account that the service account is annotated with, set only on GCP.
+	GCPServiceAccount string `json:"gcpServiceAccount,omitempty" yaml:"gcpServiceAccount,omitempty"`
+}
+
+// inventoryPod is the type that describes the resources that the Check command creates to run the pod.
+type inventoryPod struct {
+	// Namespace is the namespace of the pod.
+	Namespace string `json:"namespace" yaml:"namespace"`
+	// ServiceAccount is the name of the service account of the pod.
+	ServiceAccount string `json:"serviceAccount" yaml:"serviceAccount"`
+	// Role is the name of the roles and the cluster role of the pod.
+	Role string `json:"role" yaml:"role"`
+	// RoleBinding is the name of the role bindings and the cluster role binding of the pod.
+	RoleBinding string `json:"roleBinding" yaml:"roleBinding"`
+}
+
+// inventoryImages is the type that describes the Docker images that the Check command would use.
+type inventoryImages struct {
+	// Pod is the image of the pod.
+	Pod string `json:"pod" yaml:"pod"`
+	// GoogleCloudSDK is the image of the Google Cloud SDK pod, set only on GCP.
+	GoogleCloudSDK string `json:"googleCloudSDK,omitempty" yaml:"googleCloudSDK,omitempty"`
+}
+
+// inventory is the type that describes what the Check command sees, derived from the environment configuration and the flags.
+type inventory struct {
+	// Provider is the cloud provider.
+	Provider string `json:"provider" yaml:"provider"`
+	// ClusterName is the name of the cluster.
+	ClusterName string `json:"clusterName" yaml:"clusterName"`
+	// Namespaces is the list of the namespaces that the Check command and the pod touch.
+	Namespaces []string `json:"namespaces" yaml:"namespaces"`
+	// Pod is the resources that the Check command creates to run the pod.
+	Pod inventoryPod `json:"pod" yaml:"pod"`
+	// Crossplane is the Crossplane resources that the checks expect.
+	Crossplane inventoryCrossplane `json:"crossplane" yaml:"crossplane"`
+	// Secrets is the list of the secrets that the checks read.
+	Secrets []inventorySecret `json:"secrets" yaml:"secrets"`
+	// Images is the Docker images that the Check command would use.
+	Images inventoryImages `json:"images" yaml:"images"`
+}
+
+// inventorySecretName returns the name of the secret of the options, or the default one if it is empty.
+func inventorySecretName(name string, defaultName string) string {
+	if name == constant.EmptyString {
+		return defaultName
+	}
+
+	return name
+}
+
+// buildInventory builds the inventory from the environment configuration, the options of the checks, the images of the pod and of the Google Cloud SDK,
+// and the namespace of the pod.
+//
+// The namespaces and the secrets are the ones of the options, as the checks resolve them: the secrets are the ones named or selected in the options, or
+// else the default ones, with the keys of the options that replace the default ones.
+//
+// nolint:funlen
+func buildInventory(
+	envConfig *envconfig.EnvConfig,
+	options handler.CheckOptions,
+	podImage string,
+	googleCloudSDKImage string,
+	podNamespace string,
+) (*inventory, error) {
+	vcloud := cloud.Cloud(envConfig.Spec.CloudSpec.Provider)
+
+	clusterName := envConfig.Spec.ClusterName
+
+	serviceAccountName, err := crossplaneServiceAccountName(vcloud)
+	if err != nil {
+		return nil, err
+	}
+
+	crossplane := inventoryCrossplane{Namespace: options.ProviderServiceAccountsNamespace(), ServiceAccount: serviceAccountName}
+
+	images := inventoryImages{Pod: podImage}
+
+	switch vcloud {
+	case cloud.AWS:
+		awsSpec, err := envConfig.AWS()
+		if err != nil {
+			return nil, err
+		}
+
+		crossplane.RoleName = awscloudutil.CrossplaneRoleName(clusterName)
+		crossplane.RoleARN = awscloudutil.ARN(awsSpec.AccountID, clusterName, awscloudutil.ARNTypeRole, crossplane.RoleName, nil)
+	case cloud.Azure:
+		if _, err := envConfig.Azure(); err != nil {
+			return nil, err
+		}
+
+		crossplane.RoleName = azurecloudutil.CrossplaneRoleName(clusterName)
+	case cloud.GCP:
+		gcpSpec, err := envConfig.GCP()
+		if err != nil {
+			return nil, err
+		}
+
+		crossplane.GCPServiceAccount = gcpcloudutil.ServiceAccountAnnotation(clusterName, gcpSpec.ProjectID)
+
+		images.GoogleCloudSDK = googleCloudSDKImage
+	default:
+		return nil, pkgerrors.NewUnsupportedCloud(vcloud)
+	}
+
+	namespacePrefix := options.NamespacePrefix
+
+	namespaces := prefixedRoleNamespaces(namespacePrefix, options.ProviderServiceAccountsNamespace())
+
+	if !slices.Contains(namespaces, podNamespace) {
+		namespaces = append([]string{podNamespace}, namespaces...)
+	}
+
+	secrets := []inventorySecret{
+		{
+			Check:     "MySQL",
+			Namespace: prefixedNamespace(namespacePrefix, constant.NamespaceMySQL),
+			Name:      inventorySecretName(options.MySQLSecretName, mysqlchecker.SecretName),
+			Keys:      kubeutil.SecretKeys(mysqlchecker.SecretKeys(), options.MySQLSecretKeys),
+		},
+		{
+			Check:     "PostgreSQL",
+			Namespace: prefixedNamespace(namespacePrefix, constant.NamespacePostgres),
+			Name:      inventorySecretName(options.PostgreSQLSecretName, postgresqlchecker.SecretName),
+			Keys:      kubeutil.SecretKeys(postgresqlchecker.SecretKeys(), options.PostgreSQLSecretKeys),
+		},
+		{Check: "TLS", Namespace: prefixedNamespace(namespacePrefix, constant.NamespaceAlphaSense), Name: tlschecker.SecretName},
+		{
+			Check:     "SMTP",
+			Namespace: prefixedNamespace(namespacePrefix, constant.NamespaceAlphaSense),
+			Name:      inventorySecretName(options.SMTPSecretName, smtpchecker.SecretName),
+			Keys:      kubeutil.SecretKeys(smtpchecker.SecretKeys(), options.SMTPSecretKeys),
+		},
+	}
+
+	ssoNamespace := prefixedNamespace(namespacePrefix, constant.NamespacePlatform)
+
+	switch {
+	case options.SSOSecretSelector != constant.EmptyString:
+		secrets = append(secrets, inventorySecret{Check: "SSO", Namespace: ssoNamespace, Selector: options.SSOSecretSelector})
+	case len(options.SSOSecretNames) > 0:
+		for _, name := range options.SSOSecretNames {
+			secrets = append(secrets, inventorySecret{Check: "SSO", Namespace: ssoNamespace, Name: name})
+		}
+	default:
+		secrets = append(secrets, inventorySecret{Check: "SSO", Namespace: ssoNamespace, Name: ssochecker.SecretName})
+	}
+
+	return &inventory{
+		Provider:    string(vcloud),
+		ClusterName: clusterName,
+		Namespaces:  namespaces,
+		Pod: inventoryPod{
+			Namespace:      podNamespace,
+			ServiceAccount: podServiceAccountName,
+			Role:           podRoleName,
+			RoleBinding:    podRoleBindingName,
+		},
+		Crossplane: crossplane,
+		Secrets:    secrets,
+		Images:     images,
+	}, nil
+}
+
+// writeInventoryJSON writes the inventory to the writer as JSON.
+func writeInventoryJSON(w io.Writer, inv *inventory) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(inv)
+}
+
+// writeInventoryYAML writes the inventory to the writer as YAML.
+func writeInventoryYAML(w io.Writer, inv *inventory) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2) // nolint:mnd
+
+	if err := encoder.Encode(inv); err != nil {
+		return err
+	}
+
+	return encoder.Close()
+}
+
+// inventoryCmd is the command to print what the Check command sees, without touching the cluster.
+type inventoryCmd struct {
+	// logger is the logger.
+	logger *log.Logger
+	// cobraCmd is the Cobra command.
+	cobraCmd *cobra.Command
+
+	// checkCmd is the Check command, whose flags determine the inventory.
+	checkCmd *checkCmd
+}
+
+var _ cmd = &inventoryCmd{}
+
+// run is the run function for the Inventory command.
+func (c *inventoryCmd) run(cobraCmd *cobra.Command, args []string) {
+	var write func(io.Writer, *inventory) error
+
+	switch util.Flag(cobraCmd, flagOutput) {
+	case outputFormatJSON:
+		write = writeInventoryJSON
+	case outputFormatYAML:
+		write = writeInventoryYAML
+	default:
+		c.logger.Fatal(errInvalidInventoryOutputFormat)
+	}
+
+	envConfig, err := envconfig.NewFromPath(args[0])
+	if err != nil {
+		c.logger.Fatal(multierr.Combine(errFailedToReadEnvConfig, err))
+	}
+
+	// The options are the ones that the checks run locally with, which the Pod reads from its environment variables as well.
+	c.checkCmd.envConfig = envConfig
+
+	options, err := c.checkCmd.localCheckOptions()
+	if err != nil {
+		c.logger.Fatal(err)
+	}
+
+	inv, err := buildInventory(
+		envConfig,
+		options,
+		c.checkCmd.podImage(),
+		gcpcloudutil.ImageRef(util.Flag(cobraCmd, flagGoogleCloudSDKDockerRepo), util.Flag(cobraCmd, flagGoogleCloudSDKDockerImage)),
+		c.checkCmd.podNamespace(),
+	)
+	if err != nil {
+		c.logger.Fatal(err)
+	}
+
+	if err := write(cobraCmd.OutOrStdout(), inv); err != nil {
+		c.logger.Fatal(multierr.Combine(errFailedToWriteInventory, err))
+	}
+}
+
+// flags sets the flags for the Inventory command.
+func (c *inventoryCmd) flags() {
+	c.checkCmd.flags(false)
+
+	c.cobraCmd.Flags().StringP(flagOutput, flagOutputShort, outputFormatJSON, "output format ("+outputFormatJSON+" or "+outputFormatYAML+")")
+}
+
+// newInventoryCmd returns a new inventoryCmd.
+func newInventoryCmd(logger *log.Logger, cobraCmd *cobra.Command) *inventoryCmd {
+	return &inventoryCmd{
+		logger:   logger,
+		cobraCmd: cobraCmd,
+		checkCmd: newCheckCmd(logger, cobraCmd),
+	}
+}
+
+// Inventory returns a Cobra command to print what the Check command sees, without touching the cluster.
+func Inventory(logger *log.Logger) *cobra.Command {
+	cobraCmd := &cobra.Command{
+		Use:   "inventory <first_step_file>",
+		Short: "Print what the check command sees, without touching the cluster",
+		Long: `Inventory prints the cloud provider, the cluster name, the Crossplane role and its ARN, the namespaces, the resources and the secrets that the check
+command touches, and the Docker images it would use, in the JSON or YAML format.
+
+Everything is derived from the environment configuration of the first step file and the flags of the check command, which it accepts as well, so that the
+output can be attached to support tickets or reviewed before a change.`,
+		Args: cobra.ExactArgs(1),
+	}
+
+	cmd := newInventoryCmd(logger, cobraCmd)
+
+	cobraCmd.Run = cmd.run
+
+	cmd.flags()
+
+	return cobraCmd
+}
@@ -0,0 +1,343 @@
+// Package cmd is the package that contains all of the commands for the application.
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/cloud"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/envconfig"
+	pkgerrors "github.com/AlphaSense-Engineering/privatecloud-cli/pkg/errors"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler"
+	"github.com/charmbracelet/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// testInventoryAWSEnvConfig is the sample AWS environment configuration used in the inventory tests.
+const testInventoryAWSEnvConfig = `kind: EnvConfig
+spec:
+  clusterName: acme
+  cloudSpec:
+    provider: aws
+    cloudZone: us-east-1
+    aws:
+      accountID: "123456789012"
+      oidcUrl: oidc.eks.us-east-1.amazonaws.com/id/ABC
+`
+
+// testInventoryPodImage is the image of the pod used in the inventory tests.
+const testInventoryPodImage = "ghcr.io/alphasense-engineering/privatecloud-cli-pod:v1.2.3"
+
+// testInventoryGoogleCloudSDKImage is the image of the Google Cloud SDK used in the inventory tests.
+const testInventoryGoogleCloudSDKImage = "google/cloud-sdk:latest"
+
+// testInventorySecrets is the list of the secrets that the inventory is expected to contain on every cloud provider.
+//
+// Do not modify this variable, it is supposed to be constant.
+var testInventorySecrets = []inventorySecret{
+	{Check: "MySQL", Namespace: "mysql", Name: "default-creds", Keys: []string{"username", "password", "endpoint", "port", "ssl-mode"}},
+	{Check: "PostgreSQL", Namespace: "postgres", Name: "spicedb-creds", Keys: []string{"username", "password", "endpoint", "port"}},
+	{Check: "TLS", Namespace: "alphasense", Name: "default-tls"},
+	{Check: "SMTP", Namespace: "alphasense", Name: "sender-smtp", Keys: []string{"username", "password", "address", "host", "port"}},
+	{Check: "SSO", Namespace: "platform", Name: "sso-config"},
+}
+
+// TestBuildInventory is a test that tests that the buildInventory function derives the inventory from a sample environment configuration.
+//
+// nolint:funlen
+func TestBuildInventory(t *testing.T) {
+	pod := inventoryPod{
+		Namespace:      "default",
+		ServiceAccount: "privatecloud-cli-sa",
+		Role:           "privatecloud-cli-role",
+		RoleBinding:    "privatecloud-cli-rolebinding",
+	}
+
+	namespaces := []string{"default", "alphasense", "crossplane", "mysql", "postgres", "platform"}
+
+	testCases := []struct {
+		name string
+		data string
+		want *inventory
+	}{
+		{
+			name: "AWS",
+			data: testInventoryAWSEnvConfig,
+			want: &inventory{
+				Provider:    "aws",
+				ClusterName: "acme",
+				Namespaces:  namespaces,
+				Pod:         pod,
+				Crossplane: inventoryCrossplane{
+					RoleName:       "crossplane-provider-acme",
+					RoleARN:        "arn:aws:iam::123456789012:role/web-identity/acme/crossplane-provider-acme",
+					Namespace:      "crossplane",
+					ServiceAccount: "aws-privatecloud-cli",
+				},
+				Secrets: testInventorySecrets,
+				Images:  inventoryImages{Pod: testInventoryPodImage},
+			},
+		},
+		{
+			name: "Azure",
+			data: `kind: EnvConfig
+spec:
+  clusterName: acme
+  cloudSpec:
+    provider: azure
+    azure:
+      clientID: client
+      resourceGroup: group
+      subscriptionID: subscription
+      tenantID: tenant
+      oidcUrl: https://eastus.oic.prod-aks.azure.com/tenant/issuer/
+`,
+			want: &inventory{
+				Provider:    "azure",
+				ClusterName: "acme",
+				Namespaces:  namespaces,
+				Pod:         pod,
+				Crossplane: inventoryCrossplane{
+					RoleName:       "acme-crossplane-provider",
+					Namespace:      "crossplane",
+					ServiceAccount: "azure-provider-sa",
+				},
+				Secrets: testInventorySecrets,
+				Images:  inventoryImages{Pod: testInventoryPodImage},
+			},
+		},
+		{
+			name: "GCP",
+			data: `kind: EnvConfig
+spec:
+  clusterName: acme
+  cloudSpec:
+    provider: gcp
+    gcp:
+      projectID: project
+      projectNumber: "123456789012"
+`,
+			want: &inventory{
+				Provider:    "gcp",
+				ClusterName: "acme",
+				Namespaces:  namespaces,
+				Pod:         pod,
+				Crossplane: inventoryCrossplane{
+					Namespace:         "crossplane",
+					ServiceAccount:    "gcp-provider-sa",
+					GCPServiceAccount: "uxp-provider-acme@project.iam.gserviceaccount.com",
+				},
+				Secrets: testInventorySecrets,
+				Images:  inventoryImages{Pod: testInventoryPodImage, GoogleCloudSDK: testInventoryGoogleCloudSDKImage},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			envConfig, err := envconfig.NewFromBytes([]byte(tc.data))
+			require.NoError(t, err)
+
+			got, err := buildInventory(envConfig, handler.CheckOptions{}, testInventoryPodImage, testInventoryGoogleCloudSDKImage, namespaceDefault)
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+// TestBuildInventory_unsupportedCloud is a test that tests that the buildInventory function fails for an unsupported cloud provider.
+func TestBuildInventory_unsupportedCloud(t *testing.T) {
+	envConfig := &envconfig.EnvConfig{Spec: envconfig.Spec{CloudSpec: envconfig.CloudSpec{Provider: "oci"}}}
+
+	_, err := buildInventory(envConfig, handler.CheckOptions{}, testInventoryPodImage, testInventoryGoogleCloudSDKImage, namespaceDefault)
+
+	assert.EqualError(t, err, pkgerrors.NewUnsupportedCloud(cloud.Cloud("oci")).Error())
+}
+
+// TestInventoryCmd_run is a test that tests that the Inventory command writes the inventory of the first step file in the requested format.
+func TestInventoryCmd_run(t *testing.T) {
+	firstStepFile := filepath.Join(t.TempDir(), "step1.yaml")
+
+	require.NoError(t, os.WriteFile(firstStepFile, []byte(testInventoryAWSEnvConfig), 0o600))
+
+	testCases := []struct {
+		name      string
+		output    string
+		unmarshal func([]byte, any) error
+	}{
+		{name: "JSON", output: outputFormatJSON, unmarshal: json.Unmarshal},
+		{name: "YAML", output: outputFormatYAML, unmarshal: yaml.Unmarshal},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cobraCmd := Inventory(log.New(io.Discard))
+
+			var out bytes.Buffer
+
+			cobraCmd.SetOut(&out)
+			cobraCmd.SetArgs([]string{firstStepFile, "--" + flagOutput, tc.output, "--" + flagDockerImage, "privatecloud-cli-pod:v1.2.3"})
+
+			require.NoError(t, cobraCmd.Execute())
+
+			var got inventory
+
+			require.NoError(t, tc.unmarshal(out.Bytes(), &got))
+
+			assert.Equal(t, "aws", got.Provider)
+			assert.Equal(t, "arn:aws:iam::123456789012:role/web-identity/acme/crossplane-provider-acme", got.Crossplane.RoleARN)
+			assert.Equal(t, testInventoryPodImage, got.Images.Pod)
+			assert.Empty(t, got.Images.GoogleCloudSDK)
+			assert.Equal(t, testInventorySecrets, got.Secrets)
+		})
+	}
+}
+
+// TestInventoryCmd_run_secrets is a test that tests that the Inventory command lists the secrets of the checks under the names, the keys and the selector
+// of the flags, and the namespace of the service accounts of the Crossplane providers of the flags.
+func TestInventoryCmd_run_secrets(t *testing.T) {
+	firstStepFile := filepath.Join(t.TempDir(), "step1.yaml")
+
+	require.NoError(t, os.WriteFile(firstStepFile, []byte(testInventoryAWSEnvConfig), 0o600))
+
+	cobraCmd := Inventory(log.New(io.Discard))
+
+	var out bytes.Buffer
+
+	cobraCmd.SetOut(&out)
+	cobraCmd.SetArgs([]string{
+		firstStepFile,
+		"--" + flagMySQLSecret, "mysql-creds",
+		"--" + flagMySQLSecretKeys, "password=db-password",
+		"--" + flagPostgreSQLSecret, "postgresql-creds",
+		"--" + flagPostgreSQLSecretKeys, "username=db-user,endpoint=host",
+		"--" + flagSMTPSecret, "smtp-creds",
+		"--" + flagSMTPSecretKeys, "username=user",
+		"--" + flagSSOSecretSelector, "app=sso",
+		"--" + flagCrossplaneNamespace, "crossplane-system",
+	})
+
+	require.NoError(t, cobraCmd.Execute())
+
+	var got inventory
+
+	require.NoError(t, json.Unmarshal(out.Bytes(), &got))
+
+	assert.Equal(t, []inventorySecret{
+		{Check: "MySQL", Namespace: "mysql", Name: "mysql-creds", Keys: []string{"username", "db-password", "endpoint", "port", "ssl-mode"}},
+		{Check: "PostgreSQL", Namespace: "postgres", Name: "postgresql-creds", Keys: []string{"db-user", "password", "host", "port"}},
+		{Check: "TLS", Namespace: "alphasense", Name: "default-tls"},
+		{Check: "SMTP", Namespace: "alphasense", Name: "smtp-creds", Keys: []string{"user", "password", "address", "host", "port"}},
+		{Check: "SSO", Namespace: "platform", Selector: "app=sso"},
+	}, got.Secrets)
+	assert.Equal(t, "crossplane-system", got.Crossplane.Namespace)
+}
+
+// TestBuildInventory_options is a test that tests that the buildInventory function lists the SSO secrets and the namespace of the service accounts of the
+// Crossplane providers of the options, as the checks resolve them.
+func TestBuildInventory_options(t *testing.T) {
+	envConfig, err := envconfig.NewFromBytes([]byte(testInventoryAWSEnvConfig))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name           string
+		options        handler.CheckOptions
+		wantSSOSecrets []inventorySecret
+		wantNamespaces []string
+		wantCrossplane string
+	}{
+		{
+			name:           "Default",
+			wantSSOSecrets: []inventorySecret{{Check: "SSO", Namespace: "platform", Name: "sso-config"}},
+			wantNamespaces: []string{"default", "alphasense", "crossplane", "mysql", "postgres", "platform"},
+			wantCrossplane: "crossplane",
+		},
+		{
+			name:    "SSO secret names",
+			options: handler.CheckOptions{SSOSecretNames: []string{"sso-okta", "sso-azure"}},
+			wantSSOSecrets: []inventorySecret{
+				{Check: "SSO", Namespace: "platform", Name: "sso-okta"},
+				{Check: "SSO", Namespace: "platform", Name: "sso-azure"},
+			},
+			wantNamespaces: []string{"default", "alphasense", "crossplane", "mysql", "postgres", "platform"},
+			wantCrossplane: "crossplane",
+		},
+		{
+			name:           "SSO secret selector",
+			options:        handler.CheckOptions{SSOSecretSelector: "app=sso"},
+			wantSSOSecrets: []inventorySecret{{Check: "SSO", Namespace: "platform", Selector: "app=sso"}},
+			wantNamespaces: []string{"default", "alphasense", "crossplane", "mysql", "postgres", "platform"},
+			wantCrossplane: "crossplane",
+		},
+		{
+			name:           "Crossplane namespace",
+			options:        handler.CheckOptions{CrossplaneNamespace: "crossplane-system"},
+			wantSSOSecrets: []inventorySecret{{Check: "SSO", Namespace: "platform", Name: "sso-config"}},
+			wantNamespaces: []string{"default", "alphasense", "crossplane-system", "mysql", "postgres", "platform"},
+			wantCrossplane: "crossplane-system",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := buildInventory(envConfig, tc.options, testInventoryPodImage, testInventoryGoogleCloudSDKImage, namespaceDefault)
+			require.NoError(t, err)
+
+			var ssoSecrets []inventorySecret
+
+			for _, secret := range got.Secrets {
+				if secret.Check == "SSO" {
+					ssoSecrets = append(ssoSecrets, secret)
+				}
+			}
+
+			assert.Equal(t, tc.wantSSOSecrets, ssoSecrets)
+			assert.Equal(t, tc.wantNamespaces, got.Namespaces)
+			assert.Equal(t, tc.wantCrossplane, got.Crossplane.Namespace)
+		})
+	}
+}
+
+// TestBuildInventory_namespacePrefix is a test that tests that the buildInventory function lists the namespaces and the secrets of the install under the
+// namespace prefix.
+func TestBuildInventory_namespacePrefix(t *testing.T) {
+	envConfig, err := envconfig.NewFromBytes([]byte(testInventoryAWSEnvConfig))
+	require.NoError(t, err)
+
+	options := handler.CheckOptions{NamespacePrefix: "tenant1"}
+
+	got, err := buildInventory(envConfig, options, testInventoryPodImage, testInventoryGoogleCloudSDKImage, namespaceDefault)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"default", "tenant1-alphasense", "crossplane", "tenant1-mysql", "tenant1-postgres", "tenant1-platform"}, got.Namespaces)
+
+	for _, secret := range got.Secrets {
+		assert.Regexp(t, "^tenant1-", secret.Namespace, secret.Check)
+	}
+}
+
+// TestBuildInventory_podNamespace is a test that tests that the buildInventory function lists the namespace of the pod, once even if it is one for the roles.
+func TestBuildInventory_podNamespace(t *testing.T) {
+	envConfig, err := envconfig.NewFromBytes([]byte(testInventoryAWSEnvConfig))
+	require.NoError(t, err)
+
+	got, err := buildInventory(envConfig, handler.CheckOptions{}, testInventoryPodImage, testInventoryGoogleCloudSDKImage, "checks")
+	require.NoError(t, err)
+
+	assert.Equal(t, "checks", got.Pod.Namespace)
+	assert.Equal(t, []string{"checks", "alphasense", "crossplane", "mysql", "postgres", "platform"}, got.Namespaces)
+
+	got, err = buildInventory(envConfig, handler.CheckOptions{}, testInventoryPodImage, testInventoryGoogleCloudSDKImage, constant.NamespacePlatform)
+	require.NoError(t, err)
+
+	assert.Equal(t, constant.NamespacePlatform, got.Pod.Namespace)
+	assert.Equal(t, constRoleNamespaces, got.Namespaces)
+}
@@ -3,28 +3,43 @@ package cmd
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
-	"net/http"
+	"fmt"
+	"io"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/clock"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/cloud"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/cloud/gcpcloudutil"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/envconfig"
 	pkgerrors "github.com/AlphaSense-Engineering/privatecloud-cli/pkg/errors"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler"
-	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/awschecker"
-	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/azurechecker"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/cloudchecker"
-	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/gcpchecker"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/mysqlchecker"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/oidcchecker"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/postgresqlchecker"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/serviceaccountchecker"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/smtpchecker"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/k8s/kubeutil"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/podprotocol"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/report"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/util"
 	"github.com/charmbracelet/log"
 	"github.com/spf13/cobra"
 	"go.uber.org/multierr"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -32,6 +47,19 @@ var (
 	// errFailedToDecodeEnvConfig is the error that is returned when the envconfig data from the flag cannot be decoded.
 	errFailedToDecodeEnvConfig = errors.New("failed to decode envconfig")
 
+	// errFailedToDecodeDBCABundle is the error that is returned when the database CA bundle from the environment variable cannot be decoded.
+	errFailedToDecodeDBCABundle = errors.New("failed to decode database CA bundle")
+
+	// errFailedToDecodeCABundle is the error that is returned when the CA bundle from the environment variable cannot be decoded.
+	errFailedToDecodeCABundle = errors.New("failed to decode CA bundle")
+
+	// errFailedToDecodeExpectedPermissions is the error that is returned when the override of the expected permissions from the environment variable cannot
+	// be decoded.
+	errFailedToDecodeExpectedPermissions = errors.New("failed to decode expected permissions")
+
+	// errFailedToCreateKubernetesDynamicClient is the error that is returned when the Kubernetes dynamic client cannot be created.
+	errFailedToCreateKubernetesDynamicClient = errors.New("failed to create Kubernetes dynamic client")
+
 	// errFailedToEnsureServiceAccount is the error that is returned when the service account cannot be ensured.
 	errFailedToEnsureServiceAccount = errors.New("failed to ensure ServiceAccount")
 
@@ -43,12 +71,174 @@ var (
 
 	// errFailedToCheckInfrastructure is the error that is returned when the infrastructure check fails.
 	errFailedToCheckInfrastructure = errors.New("failed to check infrastructure")
+
+	// errCannotReachKubernetesAPI is the error that is returned when the Kubernetes API cannot be reached from the pod.
+	errCannotReachKubernetesAPI = errors.New("cannot reach Kubernetes API from pod")
 )
 
+const (
+	// apiFailureAuth is the kind of the failure to reach the Kubernetes API when the service account of the pod is not authenticated or not authorized.
+	apiFailureAuth = "authentication or authorization failure, check the ServiceAccount of the Pod and its token"
+
+	// apiFailureNetwork is the kind of the failure to reach the Kubernetes API when the connection to it fails.
+	apiFailureNetwork = "network failure, check that the network policies allow the Pod to reach the API server"
+
+	// apiFailureServer is the kind of the failure to reach the Kubernetes API when the API server responds with an error.
+	apiFailureServer = "API server error"
+)
+
+// connectTimeoutFromEnv returns the connect timeout from the environment variable, or the default one if it is not set, e.g. by an older Check command.
+func connectTimeoutFromEnv() (time.Duration, error) {
+	v := os.Getenv(envVarConnectTimeout)
+	if v == constant.EmptyString {
+		return defaultConnectTimeout, nil
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, multierr.Combine(errInvalidConnectTimeout, err)
+	}
+
+	if d <= 0 {
+		return 0, errInvalidConnectTimeout
+	}
+
+	return d, nil
+}
+
+// maxClockSkewFromEnv returns the maximum clock skew from the environment variable, or the default one if it is not set, e.g. by an older Check command.
+func maxClockSkewFromEnv() (time.Duration, error) {
+	v := os.Getenv(envVarMaxClockSkew)
+	if v == constant.EmptyString {
+		return defaultMaxClockSkew, nil
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, multierr.Combine(errInvalidMaxClockSkew, err)
+	}
+
+	if d < 0 {
+		return 0, errInvalidMaxClockSkew
+	}
+
+	return d, nil
+}
+
+// maxConcurrencyFromEnv returns the maximum concurrency of the checks from the environment variable, or the default one if it is not set, e.g. by an older
+// Check command.
+func maxConcurrencyFromEnv() (int, error) {
+	v := os.Getenv(envVarMaxConcurrency)
+	if v == constant.EmptyString {
+		return defaultMaxConcurrency, nil
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, multierr.Combine(errInvalidMaxConcurrency, err)
+	}
+
+	if n <= 0 {
+		return 0, errInvalidMaxConcurrency
+	}
+
+	return n, nil
+}
+
+// keyValuesFromEnv returns the map of the environment variable, which the Check command encodes as a JSON object of strings, or nil if it is not set.
+//
+// It returns the error, along with the one of decoding it, if it is not a JSON object of strings.
+func keyValuesFromEnv(name string, errInvalid error) (map[string]string, error) {
+	v := os.Getenv(name)
+	if v == constant.EmptyString {
+		return nil, nil
+	}
+
+	var pairs map[string]string
+
+	if err := json.Unmarshal([]byte(v), &pairs); err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalid, err)
+	}
+
+	return pairs, nil
+}
+
+// mysqlExpectedConfigFromEnv returns the override of the expected MySQL configuration from the environment variable, or nil if it is not set, e.g. by an
+// older Check command.
+func mysqlExpectedConfigFromEnv() (map[string]string, error) {
+	override, err := keyValuesFromEnv(envVarMySQLExpectedConfig, errInvalidMySQLExpectedConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return override, mysqlchecker.ValidateExpectedConfigOverride(override)
+}
+
+// postgresqlExpectedConfigFromEnv returns the override of the expected PostgreSQL configuration from the environment variable, or nil if it is not set,
+// e.g. by an older Check command.
+func postgresqlExpectedConfigFromEnv() (map[string]string, error) {
+	override, err := keyValuesFromEnv(envVarPostgreSQLExpectedConfig, errInvalidPostgreSQLExpectedConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return override, postgresqlchecker.ValidateExpectedConfigOverride(override)
+}
+
+// secretKeysFromEnv returns the keys of the secret that replace the default ones of the check from the environment variable, or nil if it is not set,
+// e.g. by an older Check command.
+func secretKeysFromEnv(name string, defaultKeys []string) (map[string]string, error) {
+	keys, err := keyValuesFromEnv(name, errInvalidSecretKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, kubeutil.ValidateSecretKeys(keys, defaultKeys)
+}
+
+// crossplaneServiceAccountName returns the name of the service account in the namespace of the Crossplane providers that the pod ensures for the cloud
+// provider.
+func crossplaneServiceAccountName(vcloud cloud.Cloud) (string, error) {
+	switch vcloud {
+	case cloud.AWS:
+		return constant.ServiceAccountNameAWS, nil
+	case cloud.Azure:
+		return constant.ServiceAccountNameAzure, nil
+	case cloud.GCP:
+		return constant.ServiceAccountNameGCP, nil
+	}
+
+	return constant.EmptyString, pkgerrors.NewUnsupportedCloud(vcloud)
+}
+
+// checkAPIServer returns an error telling the authentication and authorization failures apart from the network ones if the Kubernetes API cannot be
+// reached, and the version of the API server otherwise.
+func checkAPIServer(client discovery.ServerVersionInterface) (string, error) {
+	info, err := client.ServerVersion()
+	if err == nil {
+		return info.GitVersion, nil
+	}
+
+	failure := apiFailureNetwork
+
+	if k8serrors.IsUnauthorized(err) || k8serrors.IsForbidden(err) {
+		failure = apiFailureAuth
+	} else if errors.As(err, new(k8serrors.APIStatus)) {
+		failure = apiFailureServer
+	}
+
+	return constant.EmptyString, multierr.Combine(fmt.Errorf("%w: %s", errCannotReachKubernetesAPI, failure), err)
+}
+
+// flagPrintPlan is the name of the flag for logging the checks that would run, without running them.
+const flagPrintPlan = "print-plan"
+
 // podCmd is the command that checks the infrastructure of the cluster where it is running on.
 type podCmd struct {
 	// logger is the logger.
 	logger *log.Logger
+	// messages is the writer of the messages of the pod protocol, which writes them along with the log lines.
+	messages *podprotocol.Writer
 }
 
 var _ cmd = &podCmd{}
@@ -69,10 +259,39 @@ func (c *podCmd) logRelatedDocumentation(docs ...string) {
 	}
 }
 
+// printPlan logs the checks that the run function runs on the cloud provider, along with the cluster resources and the endpoints they read, without running
+// them.
+func (c *podCmd) printPlan(vcloud cloud.Cloud, roleOnly bool) error {
+	const (
+		// logMsgPlannedCheck is the message that is logged for each of the planned checks.
+		logMsgPlannedCheck = "planned check"
+
+		// logKeyCloud is the key of the cloud provider in the structured log entry of the planned check.
+		logKeyCloud = "cloud"
+
+		// logKeyName is the key of the name in the structured log entry of the planned check.
+		logKeyName = "name"
+
+		// logKeyReads is the key of the read resources in the structured log entry of the planned check.
+		logKeyReads = "reads"
+	)
+
+	plan, err := cloudchecker.Plan(vcloud, roleOnly)
+	if err != nil {
+		return err
+	}
+
+	for _, check := range plan {
+		c.logger.Info(logMsgPlannedCheck, logKeyCloud, vcloud, logKeyName, check.Name, logKeyReads, strings.Join(check.Reads, ", "))
+	}
+
+	return nil
+}
+
 // run is the run function for the Pod command.
 //
 // nolint:funlen,gocognit
-func (c *podCmd) run(_ *cobra.Command, _ []string) {
+func (c *podCmd) run(cobraCmd *cobra.Command, _ []string) {
 	const (
 		// logMsgPodStarted is the message that is logged when the pod starts.
 		logMsgPodStarted = "pod %s started"
@@ -80,57 +299,64 @@ func (c *podCmd) run(_ *cobra.Command, _ []string) {
 		// logMsgEnvConfigDecoded is the message that is logged when the environment configuration is decoded.
 		logMsgEnvConfigDecoded = "decoded environment configuration"
 
+		// logMsgDBCABundleDecoded is the message that is logged when the database CA bundle is decoded.
+		logMsgDBCABundleDecoded = "decoded database CA bundle, database connections will use TLS"
+
+		// logMsgCABundleDecoded is the message that is logged when the CA bundle is decoded.
+		logMsgCABundleDecoded = "decoded CA bundle, HTTPS requests will trust it along with the CAs of the system"
+
+		// logMsgKubernetesAPIReached is the message that is logged when the Kubernetes API is reached from the pod.
+		logMsgKubernetesAPIReached = "reached Kubernetes API, server version %s"
+
+		// logMsgExpectedPermissionsDecoded is the message that is logged when the override of the expected permissions is decoded.
+		logMsgExpectedPermissionsDecoded = "decoded override of %d expected permissions"
+
 		// logMsgServiceAccountEnsured is the message that is logged when the service account is ensured.
 		logMsgServiceAccountEnsured = "ensured %s/%s ServiceAccount"
 
+		// logMsgRoleOnly is the message that is logged when only the Crossplane role is checked.
+		logMsgRoleOnly = "checking Crossplane role only, skipping the rest of the infrastructure checks"
+
 		// logMsgInfraCheckCompletedSuccessfully is the message that is logged when the infrastructure check is completed successfully.
 		logMsgInfraCheckCompletedSuccessfully = "infrastructure check completed successfully"
 	)
 
 	const (
 		// docsPersistentVolumes is the URL to the documentation for persistent volumes.
-		docsPersistentVolumes = "https://developer.alpha-sense.com/enterprise/technical-requirements/#persistent-volumes"
+		docsPersistentVolumes = cloudchecker.DocsPersistentVolumes
 
 		// docsMySQLDatabaseCluster is the URL to the documentation for MySQL database cluster.
-		docsMySQLDatabaseCluster = "https://developer.alpha-sense.com/enterprise/technical-requirements/#mysql-database-cluster"
+		docsMySQLDatabaseCluster = cloudchecker.DocsMySQLDatabaseCluster
 
 		// docsMySQLSecrets is the URL to the documentation for MySQL secrets.
-		//
-		// nolint:gosec
-		docsMySQLSecrets = "https://developer.alpha-sense.com/enterprise/technical-requirements/#mysql-secrets"
+		docsMySQLSecrets = cloudchecker.DocsMySQLSecrets
 
 		// docsPostgreSQLDatabaseCluster is the URL to the documentation for PostgreSQL database cluster.
-		docsPostgreSQLDatabaseCluster = "https://developer.alpha-sense.com/enterprise/technical-requirements/#postgresql-database-cluster"
+		docsPostgreSQLDatabaseCluster = cloudchecker.DocsPostgreSQLDatabaseCluster
 
 		// docsPostgreSQLSecrets is the URL to the documentation for PostgreSQL secrets.
-		//
-		// nolint:gosec
-		docsPostgreSQLSecrets = "https://developer.alpha-sense.com/enterprise/technical-requirements/#postgresql-secrets"
+		docsPostgreSQLSecrets = cloudchecker.DocsPostgreSQLSecrets
 
 		// docsTLSSecrets is the URL to the documentation for TLS secrets.
-		//
-		// nolint:gosec
-		docsTLSSecrets = "https://developer.alpha-sense.com/enterprise/technical-requirements/#tls-secrets"
+		docsTLSSecrets = cloudchecker.DocsTLSSecrets
 
 		// docsSMTPSecrets is the URL to the documentation for SMTP secrets.
-		//
-		// nolint:gosec
-		docsSMTPSecrets = "https://developer.alpha-sense.com/enterprise/technical-requirements/#smtp-credentials-for-email-sending"
+		docsSMTPSecrets = cloudchecker.DocsSMTPSecrets
 
 		// docsSSOSecrets is the URL to the documentation for SSO secrets.
-		//
-		// nolint:gosec
-		docsSSOSecrets = "https://developer.alpha-sense.com/enterprise/technical-requirements/#sso-secret"
+		docsSSOSecrets = cloudchecker.DocsSSOSecrets
 
 		// docsAWSOIDC is the URL to the documentation for AWS OIDC.
-		docsAWSOIDC = "https://developer.alpha-sense.com/enterprise/technical-requirements/aws#oidc-provider-for-iam-role-for-service-account"
+		docsAWSOIDC = cloudchecker.DocsAWSOIDC
 
 		// docsAzureCrossplaneMI is the URL to the documentation for Azure Crossplane Managed Identity.
-		docsAzureCrossplaneMI = "https://developer.alpha-sense.com/enterprise/technical-requirements/azure#crossplane-managed-identity"
+		docsAzureCrossplaneMI = cloudchecker.DocsAzureCrossplaneMI
 	)
 
 	c.logger.SetFormatter(log.JSONFormatter)
 
+	c.writeMessage(podprotocol.PodMessage{Type: podprotocol.TypeVersion, Version: constant.BuildVersion})
+
 	c.logger.Debugf(logMsgPodStarted, constant.AppName)
 
 	envConfigBase64 := os.Getenv(envVarEnvConfig)
@@ -148,8 +374,23 @@ func (c *podCmd) run(_ *cobra.Command, _ []string) {
 		c.logger.Fatal(multierr.Combine(errFailedToReadEnvConfig, err))
 	}
 
+	// The child logger keeps the JSON formatter set above, so the fields are in every line that the Check command parses.
+	c.logger = withEnvConfigFields(c.logger, envConfig)
+
 	c.logger.Debug(logMsgEnvConfigDecoded)
 
+	vcloud := cloud.Cloud(envConfig.Spec.CloudSpec.Provider)
+
+	roleOnly := os.Getenv(envVarRoleOnly) == strconv.FormatBool(true)
+
+	if util.FlagBool(cobraCmd, flagPrintPlan) {
+		if err := c.printPlan(vcloud, roleOnly); err != nil {
+			c.logger.Fatal(err)
+		}
+
+		return
+	}
+
 	googleCloudSDKDockerRepo := os.Getenv(envVarGoogleCloudSDKDockerRepo)
 	if googleCloudSDKDockerRepo == constant.EmptyString {
 		c.logger.Fatal(pkgerrors.NewEnvVarIsNotSetOrEmpty(envVarGoogleCloudSDKDockerRepo))
@@ -160,7 +401,155 @@ func (c *podCmd) run(_ *cobra.Command, _ []string) {
 		c.logger.Fatal(pkgerrors.NewEnvVarIsNotSetOrEmpty(envVarGoogleCloudSDKDockerImage))
 	}
 
-	kubeConfig, path, err := kubeutil.Config(constant.EmptyString)
+	if err := gcpcloudutil.ValidateImageRef(gcpcloudutil.ImageRef(googleCloudSDKDockerRepo, googleCloudSDKDockerImage)); err != nil {
+		c.logger.Fatal(err)
+	}
+
+	// The pods created by an older Check command, which did not set the pod security profile, are hardened as well.
+	podSecurityProfile := os.Getenv(envVarPodSecurityProfile)
+	if podSecurityProfile == constant.EmptyString {
+		podSecurityProfile = kubeutil.PodSecurityProfileRestricted
+	}
+
+	if err := kubeutil.ValidatePodSecurityProfile(podSecurityProfile); err != nil {
+		c.logger.Fatal(err)
+	}
+
+	checkSpiceDB := os.Getenv(envVarCheckSpiceDB) == strconv.FormatBool(true)
+
+	checkSMTPConnection := os.Getenv(envVarCheckSMTPConnection) == strconv.FormatBool(true)
+
+	requiresGPU := envConfig.GPURequired()
+	if v := os.Getenv(envVarRequiresGPU); v != constant.EmptyString {
+		requiresGPU = v == strconv.FormatBool(true)
+	}
+
+	connectTimeout, err := connectTimeoutFromEnv()
+	if err != nil {
+		c.logger.Fatal(err)
+	}
+
+	maxClockSkew, err := maxClockSkewFromEnv()
+	if err != nil {
+		c.logger.Fatal(err)
+	}
+
+	maxConcurrency, err := maxConcurrencyFromEnv()
+	if err != nil {
+		c.logger.Fatal(err)
+	}
+
+	mysqlExpectedConfig, err := mysqlExpectedConfigFromEnv()
+	if err != nil {
+		c.logger.Fatal(err)
+	}
+
+	postgresqlExpectedConfig, err := postgresqlExpectedConfigFromEnv()
+	if err != nil {
+		c.logger.Fatal(err)
+	}
+
+	mysqlSecretKeys, err := secretKeysFromEnv(envVarMySQLSecretKeys, mysqlchecker.SecretKeys())
+	if err != nil {
+		c.logger.Fatal(err)
+	}
+
+	postgresqlSecretKeys, err := secretKeysFromEnv(envVarPostgreSQLSecretKeys, postgresqlchecker.SecretKeys())
+	if err != nil {
+		c.logger.Fatal(err)
+	}
+
+	smtpSecretKeys, err := secretKeysFromEnv(envVarSMTPSecretKeys, smtpchecker.SecretKeys())
+	if err != nil {
+		c.logger.Fatal(err)
+	}
+
+	// The proxy flags of the Check command reach the pod as the standard environment variables, so the proxy configuration is the one of the environment.
+	proxyConfig, err := util.NewProxyConfig(constant.EmptyString, constant.EmptyString)
+	if err != nil {
+		c.logger.Fatal(err)
+	}
+
+	logProxyConfig(c.logger, proxyConfig)
+
+	var rootCAs *x509.CertPool
+
+	if caBundleBase64 := os.Getenv(envVarCABundle); caBundleBase64 != constant.EmptyString {
+		caBundle, err := base64.StdEncoding.DecodeString(caBundleBase64)
+		if err != nil {
+			c.logger.Fatal(multierr.Combine(errFailedToDecodeCABundle, err))
+		}
+
+		if rootCAs, err = util.SystemCertPoolWithCABundle(caBundle); err != nil {
+			c.logger.Fatal(multierr.Combine(errFailedToDecodeCABundle, err))
+		}
+
+		c.logger.Debug(logMsgCABundleDecoded)
+	}
+
+	var dbTLSConfig *tls.Config
+
+	if dbCABundleBase64 := os.Getenv(envVarDBCABundle); dbCABundleBase64 != constant.EmptyString {
+		dbCABundle, err := base64.StdEncoding.DecodeString(dbCABundleBase64)
+		if err != nil {
+			c.logger.Fatal(multierr.Combine(errFailedToDecodeDBCABundle, err))
+		}
+
+		if dbTLSConfig, err = util.TLSConfigFromCABundle(dbCABundle); err != nil {
+			c.logger.Fatal(multierr.Combine(errFailedToDecodeDBCABundle, err))
+		}
+
+		c.logger.Debug(logMsgDBCABundleDecoded)
+	}
+
+	var storageClassProvisioners []string
+
+	if v := os.Getenv(envVarStorageClassProvisioners); v != constant.EmptyString {
+		storageClassProvisioners = strings.Split(v, listSeparator)
+	}
+
+	checkStorageClassProvisioner := os.Getenv(envVarCheckStorageClassProvisioner) == strconv.FormatBool(true) || len(storageClassProvisioners) > 0
+
+	var oidcAudiences []string
+
+	if v := os.Getenv(envVarOIDCAudiences); v != constant.EmptyString {
+		oidcAudiences = strings.Split(v, listSeparator)
+	}
+
+	checkOIDCDiscovery := os.Getenv(envVarCheckOIDCDiscovery) == strconv.FormatBool(true) || len(oidcAudiences) > 0
+
+	var ssoSecretNames []string
+
+	if v := os.Getenv(envVarSSOSecrets); v != constant.EmptyString {
+		ssoSecretNames = strings.Split(v, listSeparator)
+	}
+
+	var ssoSAMLKeys []string
+
+	if v := os.Getenv(envVarSSOSAMLKeys); v != constant.EmptyString {
+		ssoSAMLKeys = strings.Split(v, listSeparator)
+	}
+
+	var allowedExtraPolicyStatements []string
+
+	if v := os.Getenv(envVarAllowedExtraPolicyStatements); v != constant.EmptyString {
+		allowedExtraPolicyStatements = strings.Split(v, listSeparator)
+	}
+
+	var expectedPermissionsOverride []string
+
+	if expectedPermissionsBase64 := os.Getenv(envVarExpectedPermissions); expectedPermissionsBase64 != constant.EmptyString {
+		expectedPermissions, err := base64.StdEncoding.DecodeString(expectedPermissionsBase64)
+		if err != nil {
+			c.logger.Fatal(multierr.Combine(errFailedToDecodeExpectedPermissions, err))
+		}
+
+		expectedPermissionsOverride = util.ParsePermissions(expectedPermissions)
+
+		c.logger.Debugf(logMsgExpectedPermissionsDecoded, len(expectedPermissionsOverride))
+	}
+
+	kubeConfig, path, err := kubeutil.PodConfig(constant.EmptyString, constant.EmptyString)
 	if err != nil {
 		c.logger.Fatal(multierr.Combine(errFailedToGetKubeConfig, err))
 	}
@@ -174,54 +563,139 @@ func (c *podCmd) run(_ *cobra.Command, _ []string) {
 
 	c.logger.Debug(logMsgKubeClientsetCreated)
 
-	vcloud := cloud.Cloud(envConfig.Spec.CloudSpec.Provider)
+	serverVersion, err := checkAPIServer(clientset.Discovery())
+	if err != nil {
+		c.logger.Fatal(err)
+	}
+
+	c.logger.Debugf(logMsgKubernetesAPIReached, serverVersion)
+
+	dynamicClient, err := dynamic.NewForConfig(kubeConfig)
+	if err != nil {
+		c.logger.Fatal(multierr.Combine(errFailedToCreateKubernetesDynamicClient, err))
+	}
 
 	ctx := context.Background()
 
-	var serviceAccountName string
+	serviceAccountName, err := crossplaneServiceAccountName(vcloud)
+	if err != nil {
+		c.logger.Fatal(err)
+	}
 
-	if vcloud == cloud.AWS {
-		serviceAccountName = constant.ServiceAccountNameAWS
-	} else if vcloud == cloud.Azure {
-		serviceAccountName = constant.ServiceAccountNameAzure
-	} else if vcloud == cloud.GCP {
-		serviceAccountName = constant.ServiceAccountNameGCP
-	} else {
-		c.logger.Fatal(pkgerrors.NewUnsupportedCloud(vcloud))
+	crossplaneNamespace := os.Getenv(envVarCrossplaneNamespace)
+
+	if crossplaneNamespace == constant.EmptyString {
+		crossplaneNamespace = constant.NamespaceCrossplane
 	}
 
 	sa := &corev1.ServiceAccount{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      serviceAccountName,
-			Namespace: constant.NamespaceCrossplane,
+			Namespace: crossplaneNamespace,
 		},
 	}
 
 	if vcloud == cloud.GCP {
+		gcpSpec, err := envConfig.GCP()
+		if err != nil {
+			c.logger.Fatal(err)
+		}
+
 		sa.ObjectMeta.Annotations = map[string]string{
-			gcpcloudutil.ServiceAccountAnnotationKey: gcpcloudutil.ServiceAccountAnnotation(envConfig.Spec.ClusterName, envConfig.Spec.CloudSpec.GCP.ProjectID),
+			gcpcloudutil.ServiceAccountAnnotationKey: gcpcloudutil.ServiceAccountAnnotation(envConfig.Spec.ClusterName, gcpSpec.ProjectID),
 		}
 	}
 
-	if _, err = clientset.CoreV1().ServiceAccounts(constant.NamespaceCrossplane).Create(
+	if _, err = clientset.CoreV1().ServiceAccounts(crossplaneNamespace).Create(
 		ctx, sa, metav1.CreateOptions{},
 	); err != nil && !k8serrors.IsAlreadyExists(err) {
 		c.logger.Fatal(multierr.Combine(errFailedToEnsureServiceAccount, err))
 	}
 
-	c.logger.Debugf(logMsgServiceAccountEnsured, constant.NamespaceCrossplane, serviceAccountName)
+	c.logger.Debugf(logMsgServiceAccountEnsured, crossplaneNamespace, serviceAccountName)
 
-	httpClient := http.DefaultClient
+	var results *report.Collector
 
-	checker := cloudchecker.New(c.logger, vcloud, envConfig, clientset, httpClient)
+	if os.Getenv(envVarReportResults) == strconv.FormatBool(true) {
+		results = resultMessagesCollector(clock.Real{}, c.messages, c.logger)
+	}
+
+	checkCtx := handler.CheckContext{
+		Logger:        c.logger,
+		VCloud:        vcloud,
+		EnvConfig:     envConfig,
+		Clientset:     clientset,
+		DynamicClient: dynamicClient,
+		HTTPClient:    util.NewHTTPClient(connectTimeout, proxyConfig, rootCAs),
+		Results:       results,
+		Clock:         clock.Real{},
+		OnCheckStart: func(check string) {
+			c.writeMessage(podprotocol.PodMessage{Type: podprotocol.TypeCheckStart, Check: check})
+		},
+		Options: handler.CheckOptions{
+			DBTLSConfig:                      dbTLSConfig,
+			ConnectTimeout:                   connectTimeout,
+			RootCAs:                          rootCAs,
+			CheckSpiceDB:                     checkSpiceDB,
+			MySQLExpectedConfigOverride:      mysqlExpectedConfig,
+			PostgreSQLExpectedConfigOverride: postgresqlExpectedConfig,
+			GoogleCloudSDKDockerRepo:         googleCloudSDKDockerRepo,
+			GoogleCloudSDKDockerImage:        googleCloudSDKDockerImage,
+			PodSecurityProfile:               podSecurityProfile,
+			MaxClockSkew:                     maxClockSkew,
+			CheckOIDCDiscovery:               checkOIDCDiscovery,
+			OIDCAudiences:                    oidcAudiences,
+
+			SkipNodeGroups:                  !requiresGPU,
+			MaxConcurrency:                  maxConcurrency,
+			CheckStorageClassProvisioner:    checkStorageClassProvisioner,
+			StorageClassProvisioners:        storageClassProvisioners,
+			MySQLSecretName:                 os.Getenv(envVarMySQLSecret),
+			PostgreSQLSecretName:            os.Getenv(envVarPostgreSQLSecret),
+			SpiceDBDatabase:                 os.Getenv(envVarSpiceDBDatabase),
+			SMTPSecretName:                  os.Getenv(envVarSMTPSecret),
+			MySQLSecretKeys:                 mysqlSecretKeys,
+			PostgreSQLSecretKeys:            postgresqlSecretKeys,
+			SMTPSecretKeys:                  smtpSecretKeys,
+			CheckSMTPConnection:             checkSMTPConnection,
+			SSOSecretNames:                  ssoSecretNames,
+			SSOSecretSelector:               os.Getenv(envVarSSOSecretSelector),
+			SSOSAMLKeys:                     ssoSAMLKeys,
+			CrossplaneNamespace:             crossplaneNamespace,
+			CrossplaneServiceAccountsPrefix: os.Getenv(envVarCrossplaneServiceAccountsPrefix),
+			AllowedExtraPolicyStatements:    allowedExtraPolicyStatements,
+			ExpectedPermissionsOverride:     expectedPermissionsOverride,
+			NamespacePrefix:                 os.Getenv(envVarNamespacePrefix),
+		},
+	}
 
 	var jwksURI *string
 
-	rawJWKSURI, err := checker.Handle(ctx)
+	var rawJWKSURI []any
+
+	if roleOnly {
+		c.logger.Info(logMsgRoleOnly)
+
+		checkCtx.CheckStarted(cloudchecker.CheckNameOIDCURL)
+
+		// The JWKS URI is still required on AWS and Azure, as the JWTs used to assume the Crossplane role are validated against it.
+		if rawJWKSURI, err = oidcchecker.New(checkCtx).Handle(ctx); err != nil || rawJWKSURI != nil {
+			results.Add(report.NewResult(cloudchecker.CheckNameOIDCURL, constant.EmptyString, err))
+		}
+
+		if err != nil {
+			err = multierr.Combine(cloudchecker.ErrFailedToCheckOIDCURL, err)
+		}
+	} else {
+		rawJWKSURI, err = cloudchecker.New(checkCtx).Handle(ctx)
+	}
+
 	if err != nil { // nolint:nestif
 		// We don't use c.logger.Fatal() as it will exit the program immediately, and we want to output additional information after logging the fatal error.
 		c.logger.Log(log.FatalLevel, multierr.Combine(errFailedToCheckInfrastructure, err))
 
+		c.logResults(results, vcloud, roleOnly)
+
 		docMap := map[error][]string{
 			cloudchecker.ErrFailedToCheckStorageClass: {docsPersistentVolumes},
 			cloudchecker.ErrFailedToCheckMySQL:        {docsMySQLDatabaseCluster, docsMySQLSecrets},
@@ -265,40 +739,84 @@ func (c *podCmd) run(_ *cobra.Command, _ []string) {
 		jwksURI, _ = rawJWKSURI[0].(*string)
 	}
 
-	// In GCP, we don't need to check the OIDC URL as it's not used.
+	// In GCP, the JWKS URI is not required, as the Crossplane role is not checked with the JWTs.
 	if vcloud != cloud.GCP && jwksURI == nil {
+		c.logResults(results, vcloud, roleOnly)
+
 		c.logger.Fatal(multierr.Combine(errFailedToCheckInfrastructure, errJWKSURIRequired))
 	}
 
-	var concreteCloudChecker handler.Handler
+	concreteCloudChecker, err := cloudchecker.NewCloudRoleChecker(checkCtx, jwksURI)
+	if err != nil {
+		results.Add(report.NewFailed(cloudchecker.CheckNameCrossplaneRole, constant.EmptyString, err))
 
-	if vcloud == cloud.AWS {
-		concreteCloudChecker = awschecker.New(c.logger, envConfig, clientset, httpClient, jwksURI)
-	} else if vcloud == cloud.Azure {
-		concreteCloudChecker = azurechecker.New(c.logger, envConfig, clientset, httpClient, jwksURI)
-	} else if vcloud == cloud.GCP {
-		concreteCloudChecker = gcpchecker.New(c.logger, envConfig, clientset, googleCloudSDKDockerRepo, googleCloudSDKDockerImage)
-	}
+		c.logResults(results, vcloud, roleOnly)
 
-	if _, err := concreteCloudChecker.Handle(ctx); err != nil {
 		c.logger.Fatal(multierr.Combine(errFailedToCheckInfrastructure, err))
 	}
 
+	checkCtx.CheckStarted(cloudchecker.CheckNameCrossplaneRole)
+
+	_, err = concreteCloudChecker.Handle(ctx)
+
+	// The checkers of the Crossplane role are reported together, as they run as a chain that stops at the first failure.
+	results.Add(report.NewResult(cloudchecker.CheckNameCrossplaneRole, constant.EmptyString, err))
+
+	c.logResults(results, vcloud, roleOnly)
+
+	if err != nil {
+		if !errors.Is(err, serviceaccountchecker.ErrNoProviderServiceAccounts) {
+			c.logger.Fatal(multierr.Combine(errFailedToCheckInfrastructure, err))
+		}
+
+		// We don't use c.logger.Fatal() as it will exit the program immediately, and we want to output additional information after logging the fatal error.
+		c.logger.Log(log.FatalLevel, multierr.Combine(errFailedToCheckInfrastructure, err))
+
+		c.logRelatedDocumentation(docsAWSOIDC)
+
+		os.Exit(1)
+	}
+
 	c.logger.Info(logMsgInfraCheckCompletedSuccessfully)
 }
 
-// newPodCmd returns a new podCmd.
-func newPodCmd(logger *log.Logger) *podCmd {
+// writeMessage writes the message of the pod protocol, logging the error if it cannot be written, as the log lines are not failed on either.
+func (c *podCmd) writeMessage(m podprotocol.PodMessage) {
+	if err := c.messages.Write(m); err != nil {
+		c.logger.Error(err)
+	}
+}
+
+// logResults writes the results of the checks that were not run, reported as skipped, and the summary of all of the results, if they are reported, so
+// that the Check command can write them to the ConfigMap or print them; the results of the checks that ran are written as each of them finishes.
+//
+// It must be called before the pod exits, as the results are not written otherwise.
+func (c *podCmd) logResults(results *report.Collector, vcloud cloud.Cloud, roleOnly bool) {
+	if results == nil {
+		return
+	}
+
+	added := results.Results()
+
+	if err := writeResultMessages(c.messages, cloudchecker.CompleteResults(vcloud, roleOnly, added), len(added)); err != nil {
+		c.logger.Error(err)
+	}
+}
+
+// newPodCmd returns a new podCmd that writes the messages of the pod protocol to the writer, which is supposed to be the one of the logger.
+func newPodCmd(logger *log.Logger, out io.Writer) *podCmd {
 	return &podCmd{
-		logger: logger,
+		logger:   logger,
+		messages: podprotocol.NewWriter(out, clock.Real{}),
 	}
 }
 
 // Pod returns a Cobra command that checks the infrastructure of the cluster where it is running on.
 func Pod(logger *log.Logger) *cobra.Command {
-	cmd := newPodCmd(logger)
+	// The logger writes to the standard error, which the Check command reads the log lines and the messages from.
+	cmd := newPodCmd(logger, os.Stderr)
 
-	return &cobra.Command{
+	cobraCmd := &cobra.Command{
 		Use:   "pod",
 		Short: "Run the pod",
 		Long: `Pod checks the infrastructure of the cluster where it is running on.
@@ -309,4 +827,11 @@ You are not supposed to run this command manually, unless you know what you are
 		Run:    cmd.run,
 		Hidden: true,
 	}
+
+	cobraCmd.Flags().Bool(flagPrintPlan, false, "log the checks that would run for the cloud provider and the resources they would read, without running them")
+
+	// The flag is meant for development only, just like the command itself.
+	_ = cobraCmd.Flags().MarkHidden(flagPrintPlan)
+
+	return cobraCmd
 }
@@ -0,0 +1,369 @@
+// Package cmd is the package that contains all of the commands for the application.
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/mysqlchecker"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/k8s/kubeutil"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/envconfig"
+	"github.com/charmbracelet/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// TestConnectTimeoutFromEnv is a test that tests the connectTimeoutFromEnv function.
+func TestConnectTimeoutFromEnv(t *testing.T) {
+	testCases := []struct {
+		name    string
+		value   string
+		want    time.Duration
+		wantErr error
+	}{
+		{
+			name: "Unset",
+			want: defaultConnectTimeout,
+		},
+		{
+			name:  "Set",
+			value: "45s",
+			want:  45 * time.Second,
+		},
+		{
+			name:    "Not a duration",
+			value:   "soon",
+			wantErr: errInvalidConnectTimeout,
+		},
+		{
+			name:    "Not positive",
+			value:   "0s",
+			wantErr: errInvalidConnectTimeout,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(envVarConnectTimeout, tc.value)
+
+			got, err := connectTimeoutFromEnv()
+
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+// TestMaxClockSkewFromEnv is a test that tests the maxClockSkewFromEnv function.
+func TestMaxClockSkewFromEnv(t *testing.T) {
+	testCases := []struct {
+		name    string
+		value   string
+		want    time.Duration
+		wantErr error
+	}{
+		{name: "Unset", want: defaultMaxClockSkew},
+		{name: "Set", value: "5m", want: 5 * time.Minute},
+		{name: "Disabled", value: "0s", want: 0},
+		{name: "Not a duration", value: "soon", wantErr: errInvalidMaxClockSkew},
+		{name: "Negative", value: "-1s", wantErr: errInvalidMaxClockSkew},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(envVarMaxClockSkew, tc.value)
+
+			got, err := maxClockSkewFromEnv()
+
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+// TestMaxConcurrencyFromEnv is a test that tests the maxConcurrencyFromEnv function.
+func TestMaxConcurrencyFromEnv(t *testing.T) {
+	testCases := []struct {
+		name    string
+		value   string
+		want    int
+		wantErr error
+	}{
+		{name: "Unset", want: defaultMaxConcurrency},
+		{name: "Set", value: "4", want: 4},
+		{name: "Not a number", value: "many", wantErr: errInvalidMaxConcurrency},
+		{name: "Not positive", value: "0", wantErr: errInvalidMaxConcurrency},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(envVarMaxConcurrency, tc.value)
+
+			got, err := maxConcurrencyFromEnv()
+
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+// TestMySQLExpectedConfigFromEnv is a test that tests that the mysqlExpectedConfigFromEnv function reads the override of the expected MySQL configuration
+// that the Check command encodes into the environment variable, and rejects the one that is not valid.
+func TestMySQLExpectedConfigFromEnv(t *testing.T) {
+	testCases := []struct {
+		name       string
+		value      string
+		want       map[string]string
+		wantErr    error
+		wantErrMsg string
+	}{
+		{name: "Unset"},
+		{
+			name:  "Set",
+			value: encodeKeyValues(map[string]string{"wait_timeout": ">=1800", "connect_timeout": "10"}),
+			want:  map[string]string{"wait_timeout": ">=1800", "connect_timeout": "10"},
+		},
+		{
+			name:  "Value with a comma",
+			value: encodeKeyValues(map[string]string{"sql_mode": "STRICT_TRANS_TABLES,NO_ZERO_DATE"}),
+			want:  map[string]string{"sql_mode": "STRICT_TRANS_TABLES,NO_ZERO_DATE"},
+		},
+		{name: "Not a JSON object", value: "wait_timeout=1800", wantErr: errInvalidMySQLExpectedConfig},
+		{name: "Invalid minimum", value: `{"wait_timeout":">=long"}`, wantErrMsg: `the minimum of wait_timeout is not an integer: "long"`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(envVarMySQLExpectedConfig, tc.value)
+
+			got, err := mysqlExpectedConfigFromEnv()
+
+			switch {
+			case tc.wantErr != nil:
+				assert.ErrorIs(t, err, tc.wantErr)
+			case tc.wantErrMsg != constant.EmptyString:
+				assert.ErrorContains(t, err, tc.wantErrMsg)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, tc.want, got)
+			}
+		})
+	}
+
+	assert.Equal(t, `{"connect_timeout":"10","wait_timeout":"\u003e=1800"}`, encodeKeyValues(map[string]string{"wait_timeout": ">=1800", "connect_timeout": "10"}))
+	assert.Empty(t, encodeKeyValues(map[string]string{}))
+}
+
+// TestPostgreSQLExpectedConfigFromEnv is a test that tests that the postgresqlExpectedConfigFromEnv function reads the override of the expected PostgreSQL
+// configuration that the Check command encodes into the environment variable, and rejects the one that is not valid.
+func TestPostgreSQLExpectedConfigFromEnv(t *testing.T) {
+	testCases := []struct {
+		name       string
+		value      string
+		want       map[string]string
+		wantErr    error
+		wantErrMsg string
+	}{
+		{name: "Unset"},
+		{
+			name:  "Set",
+			value: encodeKeyValues(map[string]string{"TimeZone": "UTC", "statement_timeout": "30s"}),
+			want:  map[string]string{"TimeZone": "UTC", "statement_timeout": "30s"},
+		},
+		{
+			name:  "Value with a comma",
+			value: encodeKeyValues(map[string]string{"search_path": "public,extensions"}),
+			want:  map[string]string{"search_path": "public,extensions"},
+		},
+		{name: "Not a JSON object", value: "statement_timeout=30s", wantErr: errInvalidPostgreSQLExpectedConfig},
+		{name: "Invalid name", value: `{"ssl mode":"on"}`, wantErrMsg: `"ssl mode" is not the name of a setting`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(envVarPostgreSQLExpectedConfig, tc.value)
+
+			got, err := postgresqlExpectedConfigFromEnv()
+
+			switch {
+			case tc.wantErr != nil:
+				assert.ErrorIs(t, err, tc.wantErr)
+			case tc.wantErrMsg != constant.EmptyString:
+				assert.ErrorContains(t, err, tc.wantErrMsg)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, tc.want, got)
+			}
+		})
+	}
+}
+
+// TestSecretKeysFromEnv is a test that tests that the secretKeysFromEnv function reads the keys of the secret that the Check command encodes into the
+// environment variable, and rejects the ones that are not valid.
+func TestSecretKeysFromEnv(t *testing.T) {
+	testCases := []struct {
+		name    string
+		value   string
+		want    map[string]string
+		wantErr error
+	}{
+		{name: "Unset"},
+		{
+			name:  "Set",
+			value: encodeKeyValues(map[string]string{"password": "db-password"}),
+			want:  map[string]string{"password": "db-password"},
+		},
+		{name: "Not a JSON object", value: "password=db-password", wantErr: errInvalidSecretKeys},
+		{name: "Unknown key", value: `{"passwd":"db-password"}`, wantErr: kubeutil.ErrUnknownSecretKey},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(envVarMySQLSecretKeys, tc.value)
+
+			got, err := secretKeysFromEnv(envVarMySQLSecretKeys, mysqlchecker.SecretKeys())
+
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+// TestWithEnvConfigFields is a test that tests that the withEnvConfigFields function adds the cluster name and the cloud provider to every log line, with
+// either formatter.
+func TestWithEnvConfigFields(t *testing.T) {
+	envConfig := &envconfig.EnvConfig{}
+	envConfig.Spec.ClusterName = "prod-eu"
+	envConfig.Spec.CloudSpec.Provider = "aws"
+
+	t.Run("JSON", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := log.New(&buf)
+		logger.SetFormatter(log.JSONFormatter)
+
+		withEnvConfigFields(logger, envConfig).Info("first")
+		withEnvConfigFields(logger, envConfig).Warn("second")
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 2)
+
+		for _, line := range lines {
+			var entry map[string]any
+
+			require.NoError(t, json.Unmarshal([]byte(line), &entry))
+
+			assert.Equal(t, "prod-eu", entry[logKeyCluster])
+			assert.Equal(t, "aws", entry[logKeyProvider])
+		}
+	})
+
+	t.Run("Text", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		withEnvConfigFields(log.New(&buf), envConfig).Info("message")
+
+		assert.Contains(t, buf.String(), "cluster=prod-eu provider=aws")
+	})
+}
+
+// TestCheckAPIServer is a test that tests that the checkAPIServer function tells the authentication and authorization failures apart from the network ones.
+func TestCheckAPIServer(t *testing.T) {
+	// errConnectionRefused is the error that the fake clientset returns when the connection to the API server fails.
+	errConnectionRefused := &url.Error{Op: "Get", URL: "https://10.0.0.1:443/version", Err: errors.New("connect: connection refused")}
+
+	testCases := []struct {
+		name        string
+		err         error
+		want        string
+		wantFailure string
+	}{
+		{
+			name: "Reachable",
+			want: "v1.31.0",
+		},
+		{
+			name:        "Unauthorized",
+			err:         k8serrors.NewUnauthorized("token expired"),
+			wantFailure: apiFailureAuth,
+		},
+		{
+			name:        "Forbidden",
+			err:         k8serrors.NewForbidden(schema.GroupResource{}, "version", errors.New("denied")),
+			wantFailure: apiFailureAuth,
+		},
+		{
+			name:        "Network",
+			err:         errConnectionRefused,
+			wantFailure: apiFailureNetwork,
+		},
+		{
+			name:        "Server",
+			err:         k8serrors.NewInternalError(errors.New("etcd unavailable")),
+			wantFailure: apiFailureServer,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			clientset := fake.NewClientset()
+			clientset.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: "v1.31.0"}
+
+			if tc.err != nil {
+				clientset.PrependReactor("get", "version", func(k8stesting.Action) (bool, runtime.Object, error) {
+					return true, nil, tc.err
+				})
+			}
+
+			got, err := checkAPIServer(clientset.Discovery())
+
+			if tc.wantFailure == constant.EmptyString {
+				require.NoError(t, err)
+				assert.Equal(t, tc.want, got)
+
+				return
+			}
+
+			require.ErrorIs(t, err, errCannotReachKubernetesAPI)
+			require.ErrorIs(t, err, tc.err)
+			assert.Contains(t, err.Error(), tc.wantFailure)
+		})
+	}
+}
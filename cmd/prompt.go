@@ -0,0 +1,110 @@
+// Package cmd is the package that contains all of the commands for the application.
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/util"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"go.uber.org/multierr"
+)
+
+var (
+	// errConfirmationRequired is the error that is returned when a destructive operation is not confirmed and cannot be confirmed interactively.
+	errConfirmationRequired = errors.New("confirmation required: not running in a terminal, rerun with --" + flagYes + " to proceed")
+
+	// errNotConfirmed is the error that is returned when a destructive operation is declined at the confirmation prompt.
+	errNotConfirmed = errors.New("operation not confirmed")
+
+	// errFailedToReadConfirmation is the error that is returned when the answer to the confirmation prompt cannot be read.
+	errFailedToReadConfirmation = errors.New("failed to read confirmation")
+)
+
+const (
+	// flagYes is the name of the flag for confirming the destructive operations non-interactively.
+	flagYes = "yes"
+	// flagYesShort is the short name of the flag for confirming the destructive operations non-interactively.
+	flagYesShort = "y"
+	// flagAssumeYes is the alias of the flag for confirming the destructive operations non-interactively.
+	flagAssumeYes = "assume-yes"
+)
+
+// constConfirmationAnswers is the list of the answers to the confirmation prompt that confirm the operation.
+//
+// Do not modify this variable, it is supposed to be constant.
+var constConfirmationAnswers = []string{"y", "yes"}
+
+// addYesFlag adds the flag for confirming the destructive operations non-interactively to the command, along with its --assume-yes alias.
+func addYesFlag(cobraCmd *cobra.Command) {
+	cobraCmd.Flags().BoolP(flagYes, flagYesShort, false, "proceed with the destructive operations without asking for confirmation (alias --"+flagAssumeYes+")")
+
+	cobraCmd.Flags().SetNormalizeFunc(func(_ *pflag.FlagSet, name string) pflag.NormalizedName {
+		if name == flagAssumeYes {
+			name = flagYes
+		}
+
+		return pflag.NormalizedName(name)
+	})
+}
+
+// isTerminal returns whether the writer is a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+// readConfirmation writes the question to the writer and reads the answer from the reader, returning whether the answer confirms the operation.
+func readConfirmation(in io.Reader, out io.Writer, question string) (bool, error) {
+	if _, err := fmt.Fprintf(out, "%s [y/N]: ", question); err != nil {
+		return false, multierr.Combine(errFailedToReadConfirmation, err)
+	}
+
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, multierr.Combine(errFailedToReadConfirmation, err)
+	}
+
+	return slices.Contains(constConfirmationAnswers, strings.ToLower(strings.TrimSpace(answer))), nil
+}
+
+// confirm asks for the confirmation of the destructive operation described by the question on the writer, unless it is confirmed by the --yes flag.
+//
+// The writer is the one that the description of the operation is written to, such as the error output of the command, which is the writer of the logger.
+// The question is asked only when it is a terminal; otherwise the confirmation is required to come from the --yes flag. It returns nil if the operation is
+// confirmed, or an error otherwise.
+func confirm(cobraCmd *cobra.Command, out io.Writer, question string) error {
+	if util.FlagBool(cobraCmd, flagYes) {
+		return nil
+	}
+
+	if !isTerminal(out) {
+		return errConfirmationRequired
+	}
+
+	confirmed, err := readConfirmation(cobraCmd.InOrStdin(), out, question)
+	if err != nil {
+		return err
+	}
+
+	if !confirmed {
+		return errNotConfirmed
+	}
+
+	return nil
+}
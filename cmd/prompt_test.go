@@ -0,0 +1,100 @@
+// Package cmd is the package that contains all of the commands for the application.
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupPromptTest is a function that sets up a Cobra command with the --yes flag for testing, with the given input and the arguments parsed.
+func setupPromptTest(t *testing.T, input string, args ...string) (*cobra.Command, *bytes.Buffer) {
+	t.Helper()
+
+	cobraCmd := &cobra.Command{}
+
+	addYesFlag(cobraCmd)
+
+	require.NoError(t, cobraCmd.Flags().Parse(args))
+
+	out := &bytes.Buffer{}
+
+	cobraCmd.SetIn(strings.NewReader(input))
+
+	return cobraCmd, out
+}
+
+// TestConfirm is a test that tests the confirm function outside of a terminal.
+func TestConfirm(t *testing.T) {
+	testCases := []struct {
+		name    string
+		args    []string
+		wantErr error
+	}{
+		{
+			name:    "Refused without --yes",
+			wantErr: errConfirmationRequired,
+		},
+		{
+			name: "Confirmed with --yes",
+			args: []string{"--" + flagYes},
+		},
+		{
+			name: "Confirmed with -y",
+			args: []string{"-" + flagYesShort},
+		},
+		{
+			name: "Confirmed with --assume-yes",
+			args: []string{"--" + flagAssumeYes},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// The answer must be ignored, as the output is not a terminal.
+			cobraCmd, out := setupPromptTest(t, "yes\n", tc.args...)
+
+			err := confirm(cobraCmd, out, "Proceed?")
+
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Empty(t, out.String())
+		})
+	}
+}
+
+// TestReadConfirmation is a test that tests the readConfirmation function.
+func TestReadConfirmation(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+		want  bool
+	}{
+		{name: "y", input: "y\n", want: true},
+		{name: "Yes with spaces", input: "  Yes \n", want: true},
+		{name: "No", input: "n\n", want: false},
+		{name: "Empty answer", input: "\n", want: false},
+		{name: "EOF", input: "", want: false},
+		{name: "Answer without newline", input: "yes", want: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+
+			got, err := readConfirmation(strings.NewReader(tc.input), out, "Proceed?")
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.want, got)
+			assert.Equal(t, "Proceed? [y/N]: ", out.String())
+		})
+	}
+}
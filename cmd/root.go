@@ -3,9 +3,16 @@ package cmd
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"time"
 
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/util"
+	"github.com/charmbracelet/log"
+	"github.com/muesli/termenv"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 const (
@@ -14,8 +21,45 @@ const (
 
 	// flagVerboseShort is the short flag to enable verbose output.
 	flagVerboseShort = "v"
+
+	// flagRetries is the flag for the number of the retries of the operations that fail transiently.
+	flagRetries = "retries"
+
+	// flagRetryBaseDelay is the flag for the delay before the first retry of the operations that fail transiently.
+	flagRetryBaseDelay = "retry-base-delay"
+
+	// FlagNoColor is the flag to disable the colors of the output.
+	FlagNoColor = "no-color"
 )
 
+// envVarNoColor is the environment variable that disables the colors of the output when set to any value, see https://no-color.org.
+const envVarNoColor = "NO_COLOR"
+
+// colorDisabled returns whether the colors of the output written to the writer are disabled, either by the flag or the environment variable, or because
+// the writer is not a terminal, such as a file or the log of a CI job.
+func colorDisabled(w io.Writer, noColor bool) bool {
+	if noColor || os.Getenv(envVarNoColor) != constant.EmptyString {
+		return true
+	}
+
+	f, ok := w.(*os.File)
+
+	return !ok || !term.IsTerminal(int(f.Fd())) // nolint:gosec
+}
+
+// ConfigureColor disables the colors and the other styles of the logger that writes to the writer if they are disabled, so that the captured logs are
+// clean for parsing.
+func ConfigureColor(logger *log.Logger, w io.Writer, noColor bool) {
+	if colorDisabled(w, noColor) {
+		logger.SetColorProfile(termenv.Ascii)
+	}
+}
+
+// retryPolicy returns the policy of retrying the operations that fail transiently, as configured by the global flags.
+func retryPolicy(cobraCmd *cobra.Command) (util.BackoffPolicy, error) {
+	return util.NewBackoffPolicy(util.FlagInt(cobraCmd, flagRetries), util.FlagDuration(cobraCmd, flagRetryBaseDelay))
+}
+
 // rootCmd is the root command for the application.
 type rootCmd struct{}
 
@@ -41,7 +85,24 @@ func Root() *cobra.Command {
 		Version: fmt.Sprintf("%s (commit: %s, date: %s)", constant.BuildVersion, constant.BuildCommit, constant.BuildDate),
 	}
 
+	// defaultRetries is the default number of the retries of the operations that fail transiently.
+	const defaultRetries = 3
+
+	// defaultRetryBaseDelay is the default delay before the first retry, which doubles with each retry.
+	const defaultRetryBaseDelay = time.Second
+
 	cobraCmd.PersistentFlags().BoolP(FlagVerbose, flagVerboseShort, false, "verbose output")
+	cobraCmd.PersistentFlags().Bool(
+		FlagNoColor,
+		false,
+		"disable the colors of the output, which are also disabled by the "+envVarNoColor+" environment variable and when the output is not a terminal",
+	)
+	cobraCmd.PersistentFlags().Int(flagRetries, defaultRetries, "number of the retries of the operations that fail transiently, such as throttled API calls")
+	cobraCmd.PersistentFlags().Duration(
+		flagRetryBaseDelay,
+		defaultRetryBaseDelay,
+		"delay before the first retry of the operations that fail transiently, doubling with each retry",
+	)
 
 	return cobraCmd
 }
@@ -0,0 +1,101 @@
+// Package cmd is the package that contains all of the commands for the application.
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/util"
+	"github.com/charmbracelet/log"
+	"github.com/muesli/termenv"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRetryPolicy is a test that tests that the retryPolicy function builds the policy from the global flags of the subcommand.
+func TestRetryPolicy(t *testing.T) {
+	testCases := []struct {
+		name          string
+		args          []string
+		wantRetries   int
+		wantBaseDelay time.Duration
+		wantErr       error
+	}{
+		{name: "Default", wantRetries: 3, wantBaseDelay: time.Second},
+		{name: "Custom", args: []string{"--retries", "5", "--retry-base-delay", "250ms"}, wantRetries: 5, wantBaseDelay: 250 * time.Millisecond},
+		{name: "Negative retries", args: []string{"--retries", "-1"}, wantErr: util.ErrInvalidRetries},
+		{name: "Zero base delay", args: []string{"--retry-base-delay", "0s"}, wantErr: util.ErrInvalidRetryBaseDelay},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				policy util.BackoffPolicy
+				err    error
+			)
+
+			rootCmd := Root()
+			rootCmd.AddCommand(&cobra.Command{
+				Use: "sub",
+				Run: func(cobraCmd *cobra.Command, _ []string) {
+					policy, err = retryPolicy(cobraCmd)
+				},
+			})
+			rootCmd.SetArgs(append([]string{"sub"}, tc.args...))
+
+			require.NoError(t, rootCmd.Execute())
+
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantRetries, policy.Retries)
+			assert.Equal(t, tc.wantBaseDelay, policy.BaseDelay)
+		})
+	}
+}
+
+// TestConfigureColor is a test that tests that the ConfigureColor function disables the colors of the logger when the writer is not a terminal, and when
+// they are disabled by the flag or the environment variable.
+func TestConfigureColor(t *testing.T) {
+	// ansiEscape is the start of the ANSI escape sequences that color the output.
+	const ansiEscape = "\x1b["
+
+	testCases := []struct {
+		name    string
+		noColor bool
+		envVar  string
+	}{
+		{name: "Not a terminal"},
+		{name: "Flag", noColor: true},
+		{name: "Environment variable", envVar: "1"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(envVarNoColor, tc.envVar)
+
+			var buf bytes.Buffer
+
+			logger := log.New(&buf)
+			logger.SetColorProfile(termenv.TrueColor)
+
+			logger.Error("colored")
+			require.Contains(t, buf.String(), ansiEscape)
+
+			buf.Reset()
+
+			ConfigureColor(logger, &buf, tc.noColor)
+
+			logger.Error("plain", "key", "value")
+
+			assert.NotContains(t, buf.String(), ansiEscape)
+			assert.Contains(t, buf.String(), "ERRO plain key=value")
+		})
+	}
+}
@@ -0,0 +1,337 @@
+// Package cmd is the package that contains all of the commands for the application.
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/cloud"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/cloud/awscloudutil"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/cloud/azurecloudutil"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/cloud/gcpcloudutil"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/envconfig"
+	pkgerrors "github.com/AlphaSense-Engineering/privatecloud-cli/pkg/errors"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/oidcchecker"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/util"
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+	"go.uber.org/multierr"
+)
+
+var (
+	// errInvalidEnvConfig is the error that is returned when the environment configuration has problems.
+	errInvalidEnvConfig = errors.New("environment configuration is invalid")
+
+	// errFailedToWriteValidation is the error that is returned when the result of the validation cannot be written.
+	errFailedToWriteValidation = errors.New("failed to write validation")
+)
+
+const (
+	// fieldAPIVersion is the field of the API version in the environment configuration.
+	fieldAPIVersion = "apiVersion"
+
+	// fieldClusterName is the field of the cluster name in the environment configuration.
+	fieldClusterName = "spec.clusterName"
+
+	// fieldCloudSpec is the field of the cloud specification in the environment configuration.
+	fieldCloudSpec = "spec.cloudSpec"
+)
+
+// envConfigProblem is the type that describes a problem of the environment configuration.
+type envConfigProblem struct {
+	// Field is the field of the environment configuration, or the value derived from it, that has the problem.
+	Field string `json:"field"`
+	// Message is the description of the problem.
+	Message string `json:"message"`
+}
+
+// envConfigValidation is the type that contains the result of the validation of an environment configuration file.
+type envConfigValidation struct {
+	// File is the path of the file.
+	File string `json:"file"`
+	// Valid is whether the environment configuration has no problems.
+	Valid bool `json:"valid"`
+	// Problems is the list of the problems of the environment configuration.
+	Problems []envConfigProblem `json:"problems"`
+}
+
+// envConfigField is the type that describes a field of the environment configuration and its value.
+type envConfigField struct {
+	// name is the name of the field.
+	name string
+	// value is the value of the field.
+	value string
+}
+
+// envConfigDerivation is the type that describes a value that the checks derive from the fields of the environment configuration.
+type envConfigDerivation struct {
+	// name is the name of the derived value, as in the output of the Inventory command.
+	name string
+	// value is the derived value.
+	value string
+	// inputs is the fields the value is derived from.
+	inputs []envConfigField
+}
+
+// cloudSpecFields returns the fields of the block of the cloud provider that are set in the environment configuration, which must not be empty.
+func cloudSpecFields(envConfig *envconfig.EnvConfig) []envConfigField {
+	spec := envConfig.Spec.CloudSpec
+
+	var fields []envConfigField
+
+	if spec.AWS != nil {
+		fields = append(fields,
+			envConfigField{fieldCloudSpec + ".aws.accountID", spec.AWS.AccountID},
+			envConfigField{fieldCloudSpec + ".aws.oidcUrl", spec.AWS.OIDCURL},
+		)
+	}
+
+	if spec.Azure != nil {
+		fields = append(fields,
+			envConfigField{fieldCloudSpec + ".azure.clientID", spec.Azure.ClientID},
+			envConfigField{fieldCloudSpec + ".azure.resourceGroup", spec.Azure.ResourceGroup},
+			envConfigField{fieldCloudSpec + ".azure.subscriptionID", spec.Azure.SubscriptionID},
+			envConfigField{fieldCloudSpec + ".azure.tenantID", spec.Azure.TenantID},
+			envConfigField{fieldCloudSpec + ".azure.oidcUrl", spec.Azure.OIDCURL},
+		)
+	}
+
+	if spec.GCP != nil {
+		fields = append(fields,
+			envConfigField{fieldCloudSpec + ".gcp.projectID", spec.GCP.ProjectID},
+			envConfigField{fieldCloudSpec + ".gcp.projectNumber", spec.GCP.ProjectNumber},
+		)
+	}
+
+	return fields
+}
+
+// envConfigDerivations returns the values that the checks derive from the environment configuration on its cloud provider, or nothing if the block of
+// the cloud provider is not set.
+func envConfigDerivations(envConfig *envconfig.EnvConfig) []envConfigDerivation {
+	clusterName := envConfig.Spec.ClusterName
+
+	cluster := envConfigField{fieldClusterName, clusterName}
+
+	switch cloud.Cloud(envConfig.Spec.CloudSpec.Provider) {
+	case cloud.AWS:
+		awsSpec, err := envConfig.AWS()
+		if err != nil {
+			return nil
+		}
+
+		roleName := awscloudutil.CrossplaneRoleName(clusterName)
+
+		return []envConfigDerivation{
+			{name: "crossplane.roleName", value: roleName, inputs: []envConfigField{cluster}},
+			{
+				name:   "crossplane.roleARN",
+				value:  awscloudutil.ARN(awsSpec.AccountID, clusterName, awscloudutil.ARNTypeRole, roleName, nil),
+				inputs: []envConfigField{{fieldCloudSpec + ".aws.accountID", awsSpec.AccountID}, cluster},
+			},
+		}
+	case cloud.Azure:
+		if _, err := envConfig.Azure(); err != nil {
+			return nil
+		}
+
+		return []envConfigDerivation{
+			{name: "crossplane.roleName", value: azurecloudutil.CrossplaneRoleName(clusterName), inputs: []envConfigField{cluster}},
+		}
+	case cloud.GCP:
+		gcpSpec, err := envConfig.GCP()
+		if err != nil {
+			return nil
+		}
+
+		return []envConfigDerivation{
+			{
+				name:   "crossplane.gcpServiceAccount",
+				value:  gcpcloudutil.ServiceAccountAnnotation(clusterName, gcpSpec.ProjectID),
+				inputs: []envConfigField{cluster, {fieldCloudSpec + ".gcp.projectID", gcpSpec.ProjectID}},
+			},
+		}
+	default:
+		return nil
+	}
+}
+
+// validateEnvConfig returns all of the problems of the environment configuration: the support of its API version, the consistency of the blocks of the
+// cloud providers, the fields that are not set, the format of the OIDC URL of the cloud provider, and the values derived from the fields that are not set.
+func validateEnvConfig(envConfig *envconfig.EnvConfig) []envConfigProblem {
+	var problems []envConfigProblem
+
+	if err := envConfig.CheckAPIVersion(); err != nil {
+		problems = append(problems, envConfigProblem{Field: fieldAPIVersion, Message: err.Error()})
+	}
+
+	for _, err := range multierr.Errors(envConfig.Validate()) {
+		// The fields that are not set are reported one by one below, each under its own field.
+		var keysMissingErr *pkgerrors.KeysMissing[string]
+
+		if errors.As(err, &keysMissingErr) {
+			continue
+		}
+
+		problems = append(problems, envConfigProblem{Field: fieldCloudSpec, Message: err.Error()})
+	}
+
+	fields := append([]envConfigField{{fieldClusterName, envConfig.Spec.ClusterName}}, cloudSpecFields(envConfig)...)
+
+	for _, field := range fields {
+		if field.value == constant.EmptyString {
+			problems = append(problems, envConfigProblem{Field: field.name, Message: "is not set"})
+		}
+	}
+
+	vcloud := cloud.Cloud(envConfig.Spec.CloudSpec.Provider)
+
+	if oidcURL, err := envConfig.OIDCURL(); err == nil && oidcURL != constant.EmptyString {
+		if err := oidcchecker.ValidateURLFormat(vcloud, oidcURL); err != nil {
+			problems = append(problems, envConfigProblem{Field: fieldCloudSpec + "." + string(vcloud) + ".oidcUrl", Message: fmt.Sprintf("%s: %q", err, oidcURL)})
+		}
+	}
+
+	for _, derivation := range envConfigDerivations(envConfig) {
+		var empty []string
+
+		for _, input := range derivation.inputs {
+			if input.value == constant.EmptyString {
+				empty = append(empty, input.name)
+			}
+		}
+
+		if len(empty) > 0 {
+			problems = append(problems, envConfigProblem{
+				Field:   derivation.name,
+				Message: fmt.Sprintf("%q is derived from the empty %s", derivation.value, strings.Join(empty, ", ")),
+			})
+		}
+	}
+
+	return problems
+}
+
+// validateEnvConfigFile returns the result of the validation of the environment configuration file, or an error if it cannot be read.
+func validateEnvConfigFile(path string) (*envConfigValidation, error) {
+	data, err := os.ReadFile(path) // nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+
+	envConfig, err := envconfig.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	problems := validateEnvConfig(envConfig)
+
+	return &envConfigValidation{File: path, Valid: len(problems) == 0, Problems: problems}, nil
+}
+
+// writeValidationText writes the result of the validation to the writer, one problem per line.
+func writeValidationText(w io.Writer, validation *envConfigValidation) error {
+	if validation.Valid {
+		_, err := fmt.Fprintf(w, "%s: valid\n", validation.File)
+
+		return err
+	}
+
+	if _, err := fmt.Fprintf(w, "%s: %d problem(s)\n", validation.File, len(validation.Problems)); err != nil {
+		return err
+	}
+
+	for _, problem := range validation.Problems {
+		if _, err := fmt.Fprintf(w, "  %s: %s\n", problem.Field, problem.Message); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeValidationJSON writes the result of the validation to the writer as JSON.
+func writeValidationJSON(w io.Writer, validation *envConfigValidation) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(validation)
+}
+
+// validateCmd is the command to validate an environment configuration file, without touching the cluster.
+type validateCmd struct {
+	// logger is the logger.
+	logger *log.Logger
+	// cobraCmd is the Cobra command.
+	cobraCmd *cobra.Command
+}
+
+var _ cmd = &validateCmd{}
+
+// run is the run function for the Validate command.
+func (c *validateCmd) run(cobraCmd *cobra.Command, args []string) {
+	var write func(io.Writer, *envConfigValidation) error
+
+	switch util.Flag(cobraCmd, flagOutput) {
+	case outputFormatText:
+		write = writeValidationText
+	case outputFormatJSON:
+		write = writeValidationJSON
+	default:
+		c.logger.Fatal(errInvalidOutputFormat)
+	}
+
+	validation, err := validateEnvConfigFile(args[0])
+	if err != nil {
+		c.logger.Fatal(multierr.Combine(errFailedToReadEnvConfig, err))
+	}
+
+	if err := write(cobraCmd.OutOrStdout(), validation); err != nil {
+		c.logger.Fatal(multierr.Combine(errFailedToWriteValidation, err))
+	}
+
+	if !validation.Valid {
+		c.logger.Fatal(errInvalidEnvConfig)
+	}
+}
+
+// flags sets the flags for the Validate command.
+func (c *validateCmd) flags() {
+	c.cobraCmd.Flags().StringP(flagOutput, flagOutputShort, outputFormatText, "output format ("+outputFormatText+" or "+outputFormatJSON+")")
+}
+
+// newValidateCmd returns a new validateCmd.
+func newValidateCmd(logger *log.Logger, cobraCmd *cobra.Command) *validateCmd {
+	return &validateCmd{
+		logger:   logger,
+		cobraCmd: cobraCmd,
+	}
+}
+
+// Validate returns a Cobra command to validate an environment configuration file, without touching the cluster.
+func Validate(logger *log.Logger) *cobra.Command {
+	cobraCmd := &cobra.Command{
+		Use:   "validate <first_step_file>",
+		Short: "Validate the environment configuration, without touching the cluster",
+		Long: `Validate reads the environment configuration of the first step file and prints all of its problems at once: the blocks of the cloud providers that
+do not match the provider, the fields that are not set, the OIDC URL that does not have the format of the provider, and the Crossplane role name, ARN or
+service account that would be derived from the fields that are not set.
+
+It does not access the network or the cluster, so that the environment configuration can be linted before the check command is run. It exits with a
+non-zero status if there are problems.`,
+		Args: cobra.ExactArgs(1),
+	}
+
+	cmd := newValidateCmd(logger, cobraCmd)
+
+	cobraCmd.Run = cmd.run
+
+	cmd.flags()
+
+	return cobraCmd
+}
@@ -0,0 +1,213 @@
+// Package cmd is the package that contains all of the commands for the application.
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestValidateEnvConfigFile is a test that tests that the validateEnvConfigFile function reports all of the problems of the environment configuration.
+//
+// nolint:funlen
+func TestValidateEnvConfigFile(t *testing.T) {
+	testCases := []struct {
+		name         string
+		envConfig    string
+		wantProblems []envConfigProblem
+	}{
+		{
+			name:      "Valid AWS",
+			envConfig: testInventoryAWSEnvConfig,
+		},
+		{
+			name: "Valid Azure",
+			envConfig: `kind: EnvConfig
+spec:
+  clusterName: acme
+  cloudSpec:
+    provider: azure
+    azure:
+      clientID: client
+      resourceGroup: group
+      subscriptionID: subscription
+      tenantID: tenant
+      oidcUrl: https://eastus.oic.prod-aks.azure.com/tenant/issuer/
+`,
+		},
+		{
+			name: "Valid GCP",
+			envConfig: `kind: EnvConfig
+spec:
+  clusterName: acme
+  cloudSpec:
+    provider: gcp
+    gcp:
+      projectID: project
+      projectNumber: "123"
+`,
+		},
+		{
+			name: "Foreign block",
+			envConfig: `kind: EnvConfig
+spec:
+  clusterName: acme
+  cloudSpec:
+    provider: gcp
+    gcp:
+      projectID: project
+      projectNumber: "123"
+    aws:
+      accountID: "123456789012"
+      oidcUrl: oidc.eks.us-east-1.amazonaws.com/id/ABC
+`,
+			wantProblems: []envConfigProblem{
+				{Field: "spec.cloudSpec", Message: "cloud specification of another provider is set: spec.cloudSpec.provider is gcp but spec.cloudSpec.aws is set"},
+			},
+		},
+		{
+			name: "Unsupported API version",
+			envConfig: `apiVersion: alpha-sense.com/v2
+kind: EnvConfig
+spec:
+  clusterName: acme
+  cloudSpec:
+    provider: gcp
+    gcp:
+      projectID: project
+      projectNumber: "123"
+`,
+			wantProblems: []envConfigProblem{
+				{
+					Field: "apiVersion",
+					Message: "unsupported API version of the environment configuration: alpha-sense.com/v2 is not one of alpha-sense.com/v1; upgrade the CLI if " +
+						"the file is newer than it, or the file if it is older",
+				},
+			},
+		},
+		{
+			name: "Missing block",
+			envConfig: `kind: EnvConfig
+spec:
+  clusterName: acme
+  cloudSpec:
+    provider: azure
+`,
+			wantProblems: []envConfigProblem{
+				{Field: "spec.cloudSpec", Message: "cloud specification of the provider is missing: spec.cloudSpec.provider is azure but spec.cloudSpec.azure is not set"},
+			},
+		},
+		{
+			name: "Empty fields and wrong OIDC URL",
+			envConfig: `kind: EnvConfig
+spec:
+  cloudSpec:
+    provider: aws
+    aws:
+      oidcUrl: https://oidc.eks.us-east-1.amazonaws.com/id/ABC
+`,
+			wantProblems: []envConfigProblem{
+				{Field: "spec.clusterName", Message: "is not set"},
+				{Field: "spec.cloudSpec.aws.accountID", Message: "is not set"},
+				{Field: "spec.cloudSpec.aws.oidcUrl", Message: `format of OIDC URL is wrong: "https://oidc.eks.us-east-1.amazonaws.com/id/ABC"`},
+				{Field: "crossplane.roleName", Message: `"crossplane-provider-" is derived from the empty spec.clusterName`},
+				{
+					Field:   "crossplane.roleARN",
+					Message: `"arn:aws:iam:::role/web-identity//crossplane-provider-" is derived from the empty spec.cloudSpec.aws.accountID, spec.clusterName`,
+				},
+			},
+		},
+		{
+			name: "Wrong GCP OIDC URL",
+			envConfig: `kind: EnvConfig
+spec:
+  clusterName: acme
+  cloudSpec:
+    provider: gcp
+    gcp:
+      projectID: project
+      projectNumber: "123"
+      oidcUrl: https://container.googleapis.com/v1/projects/project
+`,
+			wantProblems: []envConfigProblem{
+				{Field: "spec.cloudSpec.gcp.oidcUrl", Message: `format of OIDC URL is wrong: "https://container.googleapis.com/v1/projects/project"`},
+			},
+		},
+		{
+			name: "Empty GCP project ID",
+			envConfig: `kind: EnvConfig
+spec:
+  clusterName: acme
+  cloudSpec:
+    provider: gcp
+    gcp:
+      projectNumber: "123"
+`,
+			wantProblems: []envConfigProblem{
+				{Field: "spec.cloudSpec.gcp.projectID", Message: "is not set"},
+				{Field: "crossplane.gcpServiceAccount", Message: `"uxp-provider-acme@.iam.gserviceaccount.com" is derived from the empty spec.cloudSpec.gcp.projectID`},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "envconfig.yaml")
+
+			require.NoError(t, os.WriteFile(path, []byte(tc.envConfig), 0o600))
+
+			got, err := validateEnvConfigFile(path)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.wantProblems, got.Problems)
+			assert.Equal(t, len(tc.wantProblems) == 0, got.Valid)
+		})
+	}
+}
+
+// TestValidateEnvConfigFile_unreadable is a test that tests that the validateEnvConfigFile function returns an error if the file has no environment
+// configuration.
+func TestValidateEnvConfigFile_unreadable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "envconfig.yaml")
+
+	_, err := validateEnvConfigFile(path)
+	require.Error(t, err)
+
+	require.NoError(t, os.WriteFile(path, []byte("kind: Secret\n"), 0o600))
+
+	_, err = validateEnvConfigFile(path)
+	require.Error(t, err)
+}
+
+// TestWriteValidation is a test that tests that the writeValidationText and writeValidationJSON functions write the result of the validation.
+func TestWriteValidation(t *testing.T) {
+	validation := &envConfigValidation{
+		File:     "first-step.yaml",
+		Problems: []envConfigProblem{{Field: "spec.clusterName", Message: "is not set"}},
+	}
+
+	var buf bytes.Buffer
+
+	require.NoError(t, writeValidationText(&buf, validation))
+	assert.Equal(t, []string{"first-step.yaml: 1 problem(s)", "  spec.clusterName: is not set"}, strings.Split(strings.TrimSpace(buf.String()), "\n"))
+
+	buf.Reset()
+
+	require.NoError(t, writeValidationText(&buf, &envConfigValidation{File: "first-step.yaml", Valid: true}))
+	assert.Equal(t, "first-step.yaml: valid\n", buf.String())
+
+	buf.Reset()
+
+	require.NoError(t, writeValidationJSON(&buf, validation))
+
+	var got envConfigValidation
+
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+	assert.Equal(t, *validation, got)
+}
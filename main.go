@@ -16,13 +16,21 @@ import (
 func addCommand(logger *log.Logger, rootCmd *cobra.Command, cmdFn func(*log.Logger) *cobra.Command) {
 	cobraCmd := cmdFn(logger)
 
+	cmd.MarkDeprecated(cobraCmd)
+
 	oldRun := cobraCmd.Run
 
 	cobraCmd.Run = func(cobraCmd *cobra.Command, args []string) {
+		cmd.ConfigureColor(logger, cobraCmd.ErrOrStderr(), util.FlagBool(cobraCmd, cmd.FlagNoColor))
+
 		if util.FlagBool(cobraCmd, cmd.FlagVerbose) {
 			logger.SetLevel(log.DebugLevel)
 		}
 
+		util.LogEffectiveConfig(logger, cobraCmd)
+
+		cmd.WarnDeprecated(logger, cobraCmd)
+
 		oldRun(cobraCmd, args)
 	}
 
@@ -31,17 +39,29 @@ func addCommand(logger *log.Logger, rootCmd *cobra.Command, cmdFn func(*log.Logg
 
 // main is the entry point for the application.
 func main() {
-	logger := log.NewWithOptions(os.Stderr, log.Options{
+	logOutput := os.Stderr
+
+	logger := log.NewWithOptions(logOutput, log.Options{
 		ReportTimestamp: true,
 		TimeFunction:    constant.LogDefaultTimeFunc,
 	})
 
 	rootCmd := cmd.Root()
 
+	// The error output of the commands is the writer of the logger, so that the confirmation prompts follow the logs on, and the colors are configured
+	// for, the writer that the logs actually go to.
+	rootCmd.SetErr(logOutput)
+
 	cmdFns := []func(*log.Logger) *cobra.Command{
+		cmd.Capabilities,
 		cmd.Check,
+		cmd.Coverage,
+		cmd.Cleanup,
+		cmd.DecodeEnvConfig,
 		cmd.Install,
+		cmd.Inventory,
 		cmd.Pod,
+		cmd.Validate,
 	}
 
 	for _, cmdFn := range cmdFns {
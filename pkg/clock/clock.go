@@ -0,0 +1,94 @@
+// Package clock is the package that contains the clock that the time-based logic reads the time from and sleeps with, so that the tests can replace it.
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is the interface that the time-based logic reads the time from and sleeps with.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// Sleep pauses for the duration.
+	Sleep(d time.Duration)
+	// After returns a channel that receives the current time once the duration has elapsed.
+	After(d time.Duration) <-chan time.Time
+}
+
+// Real is the type that implements the Clock with the functions of the time package.
+type Real struct{}
+
+var _ Clock = Real{}
+
+// Now is the function that returns the current time.
+func (Real) Now() time.Time {
+	return time.Now()
+}
+
+// Sleep is the function that pauses for the duration.
+func (Real) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+// After is the function that returns a channel that receives the current time once the duration has elapsed.
+func (Real) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// Fake is the type that implements the Clock for the tests, whose time only moves when it is slept on or waited for, both of which return at once.
+type Fake struct {
+	// mu is the mutex that guards the fields below.
+	mu sync.Mutex
+	// now is the current time.
+	now time.Time
+	// sleeps is the list of the durations slept on or waited for, in order.
+	sleeps []time.Duration
+}
+
+var _ Clock = &Fake{}
+
+// advance is the function that moves the current time forward by the duration, recording it, and returns the new current time.
+func (c *Fake) advance(d time.Duration) time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+	c.sleeps = append(c.sleeps, d)
+
+	return c.now
+}
+
+// Now is the function that returns the current time.
+func (c *Fake) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// Sleep is the function that moves the current time forward by the duration, without pausing.
+func (c *Fake) Sleep(d time.Duration) {
+	c.advance(d)
+}
+
+// After is the function that moves the current time forward by the duration and returns a channel that has already received it.
+func (c *Fake) After(d time.Duration) <-chan time.Time {
+	ch := make(chan time.Time, 1)
+	ch <- c.advance(d)
+
+	return ch
+}
+
+// Sleeps is the function that returns the durations slept on or waited for, in order.
+func (c *Fake) Sleeps() []time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]time.Duration(nil), c.sleeps...)
+}
+
+// NewFake is a function that returns a new Fake whose current time is now.
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
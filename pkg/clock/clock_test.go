@@ -0,0 +1,32 @@
+// Package clock is the package that contains the clock that the time-based logic reads the time from and sleeps with, so that the tests can replace it.
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFake is a test that tests that the Fake moves its time forward only when it is slept on or waited for, recording the durations.
+func TestFake(t *testing.T) {
+	start := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	c := NewFake(start)
+
+	assert.Equal(t, start, c.Now())
+
+	c.Sleep(time.Minute)
+
+	assert.Equal(t, start.Add(time.Minute), c.Now())
+
+	select {
+	case got := <-c.After(time.Second):
+		assert.Equal(t, start.Add(time.Minute+time.Second), got)
+	default:
+		t.Fatal("After did not fire")
+	}
+
+	assert.Equal(t, start.Add(time.Minute+time.Second), c.Now())
+	assert.Equal(t, []time.Duration{time.Minute, time.Second}, c.Sleeps())
+}
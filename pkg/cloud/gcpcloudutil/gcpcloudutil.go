@@ -1,12 +1,66 @@
 // Package gcpcloudutil is the package that contains the GCP cloud utility functions.
 package gcpcloudutil
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
+)
+
+// ErrInvalidImageRef is the error that is returned when the image reference is malformed.
+var ErrInvalidImageRef = errors.New("invalid image reference, expected the repo/image[:tag|@digest] format")
 
 // ServiceAccountAnnotationKey is the key for the GCP service account annotation.
 const ServiceAccountAnnotationKey = "iam.gke.io/gcp-service-account"
 
+// constImageRefRegexp is the regular expression that matches the image reference, following the Docker reference grammar.
+//
+// Do not modify this variable, it is supposed to be constant.
+var constImageRefRegexp = func() *regexp.Regexp {
+	const (
+		// domainComponent is the pattern of a single component of the registry domain.
+		domainComponent = `(?:[a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9-]*[a-zA-Z0-9])`
+
+		// domain is the pattern of the registry domain, with the optional port.
+		domain = domainComponent + `(?:\.` + domainComponent + `)*(?::[0-9]+)?`
+
+		// pathComponent is the pattern of a single component of the repository path.
+		pathComponent = `[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*`
+
+		// tag is the pattern of the tag.
+		tag = `[\w][\w.-]{0,127}`
+
+		// digest is the pattern of the digest.
+		digest = `[A-Za-z][A-Za-z0-9]*(?:[-_+.][A-Za-z][A-Za-z0-9]*)*:[0-9a-fA-F]{32,}`
+	)
+
+	return regexp.MustCompile(`^(?:` + domain + `/)?` + pathComponent + `(?:/` + pathComponent + `)+(?::` + tag + `)?(?:@` + digest + `)?$`)
+}()
+
 // ServiceAccountAnnotation is a function that returns the annotation for the service account.
 func ServiceAccountAnnotation(clusterName string, projectID string) string {
 	return fmt.Sprintf("uxp-provider-%s@%s.iam.gserviceaccount.com", clusterName, projectID)
 }
+
+// WorkloadIdentityPool is a function that returns the workload identity pool of the project, which is the audience of the JWTs of the workload identity
+// federation of its clusters.
+func WorkloadIdentityPool(projectID string) string {
+	return projectID + ".svc.id.goog"
+}
+
+// ImageRef is a function that returns the image reference of the Google Cloud SDK from its Docker repository and image, e.g. google and cloud-sdk:latest.
+func ImageRef(repo string, image string) string {
+	return strings.Join([]string{repo, image}, string(constant.HTTPPathSeparator))
+}
+
+// ValidateImageRef is a function that validates that the image reference is in the repo/image[:tag|@digest] format.
+func ValidateImageRef(ref string) error {
+	if !constImageRefRegexp.MatchString(ref) {
+		return fmt.Errorf("%w: %q", ErrInvalidImageRef, ref)
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+// Package gcpcloudutil is the package that contains the GCP cloud utility functions.
+package gcpcloudutil
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestValidateImageRef is a test that tests the ValidateImageRef function.
+func TestValidateImageRef(t *testing.T) {
+	// digest is a valid digest of an image.
+	digest := "sha256:" + strings.Repeat("a", 64)
+
+	testCases := []struct {
+		name    string
+		ref     string
+		wantErr bool
+	}{
+		{name: "Default", ref: ImageRef("google", "cloud-sdk:latest")},
+		{name: "Without tag", ref: ImageRef("google", "cloud-sdk")},
+		{name: "With digest", ref: ImageRef("google", "cloud-sdk@"+digest)},
+		{name: "With tag and digest", ref: ImageRef("google", "cloud-sdk:latest@"+digest)},
+		{name: "Registry with nested path", ref: ImageRef("gcr.io/google.com/cloudsdktool", "google-cloud-cli:stable")},
+		{name: "Registry with port", ref: ImageRef("registry.example.com:5000/mirror", "cloud-sdk:1.2.3")},
+		{name: "Empty repo", ref: ImageRef("", "cloud-sdk:latest"), wantErr: true},
+		{name: "Empty image", ref: ImageRef("google", ""), wantErr: true},
+		{name: "Image without repo", ref: "cloud-sdk:latest", wantErr: true},
+		{name: "Uppercase image", ref: ImageRef("google", "Cloud-SDK:latest"), wantErr: true},
+		{name: "Empty tag", ref: ImageRef("google", "cloud-sdk:"), wantErr: true},
+		{name: "Short digest", ref: ImageRef("google", "cloud-sdk@sha256:abc"), wantErr: true},
+		{name: "Whitespace", ref: ImageRef("google", "cloud sdk:latest"), wantErr: true},
+		{name: "Scheme", ref: ImageRef("https://gcr.io", "cloud-sdk:latest"), wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateImageRef(tc.ref)
+
+			if tc.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidImageRef)
+				assert.ErrorContains(t, err, tc.ref)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
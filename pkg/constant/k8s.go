@@ -42,3 +42,17 @@ const (
 	// SecretPortKey is the key of the port in the secret.
 	SecretPortKey = "port"
 )
+
+const (
+	// LabelManagedBy is the label that identifies the tool managing the resource.
+	LabelManagedBy = "app.kubernetes.io/managed-by"
+
+	// LabelVersion is the label that identifies the version of the tool that created the resource.
+	LabelVersion = "app.kubernetes.io/version"
+
+	// LabelRunID is the label that identifies the run of the tool that created the resource.
+	LabelRunID = AppName + "/run-id"
+
+	// AnnotationCreatedAt is the annotation that contains the time, in the RFC 3339 format, at which the tool created the resource.
+	AnnotationCreatedAt = AppName + "/created-at"
+)
@@ -0,0 +1,35 @@
+// Package constant is the package that contains the constant variables.
+package constant
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestK8sConstants is a test that tests the Kubernetes constants that the checkers rely on.
+//
+// Refer to each of the constants here, so that removing or renaming one of them breaks the build of the tests as well as the checkers.
+func TestK8sConstants(t *testing.T) {
+	testCases := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{name: "NamespaceAlphaSense", got: NamespaceAlphaSense, want: "alphasense"},
+		{name: "NamespaceCrossplane", got: NamespaceCrossplane, want: "crossplane"},
+		{name: "NamespaceMySQL", got: NamespaceMySQL, want: "mysql"},
+		{name: "NamespacePlatform", got: NamespacePlatform, want: "platform"},
+		{name: "NamespacePostgres", got: NamespacePostgres, want: "postgres"},
+		{name: "SecretUsernameKey", got: SecretUsernameKey, want: "username"},
+		{name: "SecretPasswordKey", got: SecretPasswordKey, want: "password"},
+		{name: "SecretEndpointKey", got: SecretEndpointKey, want: "endpoint"},
+		{name: "SecretPortKey", got: SecretPortKey, want: "port"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.got)
+		})
+	}
+}
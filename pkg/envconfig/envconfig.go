@@ -4,16 +4,53 @@ package envconfig
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"io"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/cloud"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
 	pkgerrors "github.com/AlphaSense-Engineering/privatecloud-cli/pkg/errors"
+	"go.uber.org/multierr"
 	"gopkg.in/yaml.v3"
 )
 
-// errNoEnvConfigKindFound is the error that is returned when no environment configuration kind is found in the YAML file.
-var errNoEnvConfigKindFound = errors.New("no environment configuration kind found in the YAML file")
+var (
+	// ErrCloudSpecMissing is the error that is returned when the cloud specification of the cloud provider of the environment configuration is missing.
+	ErrCloudSpecMissing = errors.New("cloud specification of the provider is missing")
+
+	// ErrProviderMismatch is the error that is returned when the cloud specification of another cloud provider than the one of the environment
+	// configuration is accessed.
+	ErrProviderMismatch = errors.New("cloud provider does not match")
+
+	// ErrForeignCloudSpec is the error that is returned when the cloud specification of another cloud provider than the one of the environment
+	// configuration is set.
+	ErrForeignCloudSpec = errors.New("cloud specification of another provider is set")
+
+	// ErrUnsupportedAPIVersion is the error that is returned when the API version of the environment configuration is not one of the supported ones.
+	ErrUnsupportedAPIVersion = errors.New("unsupported API version of the environment configuration")
+
+	// ErrUnsupportedVersion is the error that is returned when the version of the Private Cloud of the environment configuration is older than the lowest
+	// supported one.
+	ErrUnsupportedVersion = errors.New("unsupported Private Cloud version")
+
+	// errInvalidVersion is the error that is returned when a version of the Private Cloud is not in the major.minor.patch format.
+	errInvalidVersion = errors.New("invalid Private Cloud version: must be in the major.minor.patch format")
+
+	// errNoEnvConfigKindFound is the error that is returned when no environment configuration kind is found in the YAML file.
+	errNoEnvConfigKindFound = errors.New("no environment configuration kind found in the YAML file")
+)
+
+// constSupportedAPIVersions is the list of the API versions of the environment configuration whose schema this version of the CLI reads.
+//
+// Do not modify this variable, it is supposed to be constant.
+var constSupportedAPIVersions = []string{"alpha-sense.com/v1"}
+
+// keyCloudSpec is the key of the cloud specification in the environment configuration, which the keys of the blocks of the providers are nested in.
+const keyCloudSpec = "spec.cloudSpec"
 
 // AWSSpec is the type that represents the AWS cloud specification of the environment configuration.
 type AWSSpec struct {
@@ -45,6 +82,9 @@ type GCPSpec struct {
 	ProjectID string `yaml:"projectID"`
 	// ProjectNumber is the GCP project number.
 	ProjectNumber string `yaml:"projectNumber"`
+
+	// OIDCURL is the OIDC URL of the workload identity federation of the cluster, or empty if it is not checked.
+	OIDCURL string `yaml:"oidcUrl,omitempty"`
 }
 
 // CloudSpec is the type that represents the cloud specification of the environment configuration.
@@ -74,6 +114,8 @@ type Spec struct {
 	InstallID string `yaml:"installID"`
 	// Version is the version.
 	Version string `yaml:"version"`
+	// RequiresGPU is whether the deployment requires GPU nodes, or nil if it does; the CPU-only deployments set it to false.
+	RequiresGPU *bool `yaml:"requiresGPU,omitempty"`
 
 	// CloudSpec is the cloud specification.
 	CloudSpec CloudSpec `yaml:"cloudSpec"`
@@ -92,7 +134,7 @@ type EnvConfig struct {
 	// Servers should convert recognized schemas to the latest internal value, and
 	// may reject unrecognized values.
 	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources
-	APIVersion string `json:"apiVersion,omitempty"`
+	APIVersion string `json:"apiVersion,omitempty" yaml:"apiVersion,omitempty"`
 
 	// Name must be unique within a namespace. Is required when creating resources, although
 	// some resources may allow a client to request the generation of an appropriate name
@@ -116,20 +158,260 @@ type EnvConfig struct {
 	Spec Spec `yaml:"spec"`
 }
 
-// OIDCURL returns the OIDC URL.
-func (e *EnvConfig) OIDCURL() string {
+// requiredField is the type that describes a field of the environment configuration that must be set, along with its value.
+type requiredField struct {
+	// key is the key of the field.
+	key string
+	// value is the value of the field.
+	value string
+}
+
+// requiredFields returns the fields of the environment configuration that must be set on its cloud provider: the cluster name and the fields of the block
+// of the provider, if the block is set.
+func (e *EnvConfig) requiredFields() []requiredField {
+	fields := []requiredField{{"spec.clusterName", e.Spec.ClusterName}}
+
+	spec := e.Spec.CloudSpec
+
+	switch cloud.Cloud(spec.Provider) {
+	case cloud.AWS:
+		if spec.AWS != nil {
+			fields = append(fields,
+				requiredField{keyCloudSpec + ".aws.accountID", spec.AWS.AccountID},
+				requiredField{keyCloudSpec + ".aws.oidcUrl", spec.AWS.OIDCURL},
+			)
+		}
+	case cloud.Azure:
+		if spec.Azure != nil {
+			fields = append(fields,
+				requiredField{keyCloudSpec + ".azure.clientID", spec.Azure.ClientID},
+				requiredField{keyCloudSpec + ".azure.resourceGroup", spec.Azure.ResourceGroup},
+				requiredField{keyCloudSpec + ".azure.subscriptionID", spec.Azure.SubscriptionID},
+				requiredField{keyCloudSpec + ".azure.tenantID", spec.Azure.TenantID},
+				requiredField{keyCloudSpec + ".azure.oidcUrl", spec.Azure.OIDCURL},
+			)
+		}
+	case cloud.GCP:
+		if spec.GCP != nil {
+			fields = append(fields,
+				requiredField{keyCloudSpec + ".gcp.projectID", spec.GCP.ProjectID},
+				requiredField{keyCloudSpec + ".gcp.projectNumber", spec.GCP.ProjectNumber},
+			)
+		}
+	}
+
+	return fields
+}
+
+// Validate returns an error if the cloud specification of the environment configuration does not match its cloud provider, that is if the block of the
+// provider is missing or if the block of another provider is set, or if any of the required fields, from the cluster name to the fields of the block of the
+// provider, is not set, in which case the error is a pkgerrors.KeysMissing listing all of them.
+func (e *EnvConfig) Validate() error {
+	provider := cloud.Cloud(e.Spec.CloudSpec.Provider)
+
+	blocks := []struct {
+		cloud cloud.Cloud
+		set   bool
+	}{
+		{cloud.AWS, e.Spec.CloudSpec.AWS != nil},
+		{cloud.Azure, e.Spec.CloudSpec.Azure != nil},
+		{cloud.GCP, e.Spec.CloudSpec.GCP != nil},
+	}
+
+	supported := false
+
+	var err error
+
+	for _, block := range blocks {
+		if block.cloud == provider {
+			supported = true
+
+			if !block.set {
+				err = multierr.Append(err, fmt.Errorf(
+					"%w: %s.provider is %s but %s.%s is not set", ErrCloudSpecMissing, keyCloudSpec, provider, keyCloudSpec, block.cloud,
+				))
+			}
+		} else if block.set {
+			err = multierr.Append(err, fmt.Errorf(
+				"%w: %s.provider is %s but %s.%s is set", ErrForeignCloudSpec, keyCloudSpec, provider, keyCloudSpec, block.cloud,
+			))
+		}
+	}
+
+	if !supported {
+		return pkgerrors.NewUnsupportedCloud(provider)
+	}
+
+	var missing []string
+
+	for _, field := range e.requiredFields() {
+		if field.value == constant.EmptyString {
+			missing = append(missing, field.key)
+		}
+	}
+
+	if len(missing) > 0 {
+		err = multierr.Append(err, pkgerrors.NewKeysMissing(missing))
+	}
+
+	return err
+}
+
+// CheckAPIVersion returns an error if the API version of the environment configuration is set but is not one of the supported ones, as its schema may
+// differ from the one this version of the CLI reads.
+func (e *EnvConfig) CheckAPIVersion() error {
+	if e.APIVersion == constant.EmptyString || slices.Contains(constSupportedAPIVersions, e.APIVersion) {
+		return nil
+	}
+
+	return fmt.Errorf(
+		"%w: %s is not one of %s; upgrade the CLI if the file is newer than it, or the file if it is older",
+		ErrUnsupportedAPIVersion, e.APIVersion, strings.Join(constSupportedAPIVersions, ", "),
+	)
+}
+
+// parseVersion parses the version in the major.minor.patch format, with an optional v prefix and with the pre-release and build suffixes ignored; the
+// missing minor and patch numbers are 0.
+//
+// It returns the major, the minor and the patch numbers, or an error if the version is not in the format.
+func parseVersion(version string) ([3]int, error) {
+	var parsed [3]int
+
+	core, _, _ := strings.Cut(strings.TrimPrefix(version, "v"), "+")
+	core, _, _ = strings.Cut(core, "-")
+
+	parts := strings.Split(core, ".")
+	if len(parts) > len(parsed) {
+		return parsed, fmt.Errorf("%w: %q", errInvalidVersion, version)
+	}
+
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return parsed, fmt.Errorf("%w: %q", errInvalidVersion, version)
+		}
+
+		parsed[i] = n
+	}
+
+	return parsed, nil
+}
+
+// ValidateVersion returns an error if the version is not in the major.minor.patch format.
+func ValidateVersion(version string) error {
+	_, err := parseVersion(version)
+
+	return err
+}
+
+// CheckSinceVersion returns an error if the version of the environment configuration is set but is older than the given lowest supported version, or is
+// not in the major.minor.patch format.
+func (e *EnvConfig) CheckSinceVersion(since string) error {
+	if e.Spec.Version == constant.EmptyString {
+		return nil
+	}
+
+	version, err := parseVersion(e.Spec.Version)
+	if err != nil {
+		return err
+	}
+
+	minimum, err := parseVersion(since)
+	if err != nil {
+		return err
+	}
+
+	if slices.Compare(version[:], minimum[:]) < 0 {
+		return fmt.Errorf("%w: %s is older than %s; use an older CLI, as listed in the compatibility matrix", ErrUnsupportedVersion, e.Spec.Version, since)
+	}
+
+	return nil
+}
+
+// GPURequired returns whether the deployment requires GPU nodes, which it does unless the environment configuration sets spec.requiresGPU to false.
+func (e *EnvConfig) GPURequired() bool {
+	return e.Spec.RequiresGPU == nil || *e.Spec.RequiresGPU
+}
+
+// cloudSpec is a function that returns the cloud specification block of the cloud provider, or an error if the environment configuration is for another
+// cloud provider or if the block is not set.
+func cloudSpec[T any](e *EnvConfig, vcloud cloud.Cloud, spec *T) (*T, error) {
+	if provider := cloud.Cloud(e.Spec.CloudSpec.Provider); provider != vcloud {
+		return nil, fmt.Errorf("%w: spec.cloudSpec.provider is %s, not %s", ErrProviderMismatch, provider, vcloud)
+	}
+
+	if spec == nil {
+		return nil, fmt.Errorf("%w: spec.cloudSpec.%s is not set", ErrCloudSpecMissing, vcloud)
+	}
+
+	return spec, nil
+}
+
+// AWS returns the AWS cloud specification, or an error if the environment configuration is not for AWS or if the specification is not set.
+func (e *EnvConfig) AWS() (*AWSSpec, error) {
+	return cloudSpec(e, cloud.AWS, e.Spec.CloudSpec.AWS)
+}
+
+// Azure returns the Azure cloud specification, or an error if the environment configuration is not for Azure or if the specification is not set.
+func (e *EnvConfig) Azure() (*AzureSpec, error) {
+	return cloudSpec(e, cloud.Azure, e.Spec.CloudSpec.Azure)
+}
+
+// GCP returns the GCP cloud specification, or an error if the environment configuration is not for GCP or if the specification is not set.
+func (e *EnvConfig) GCP() (*GCPSpec, error) {
+	return cloudSpec(e, cloud.GCP, e.Spec.CloudSpec.GCP)
+}
+
+// OIDCURL returns the OIDC URL, which is optional on GCP, or an error if the cloud provider is unsupported or if its cloud specification is not set.
+func (e *EnvConfig) OIDCURL() (string, error) {
 	switch v := cloud.Cloud(e.Spec.CloudSpec.Provider); v {
 	case cloud.AWS:
-		return e.Spec.CloudSpec.AWS.OIDCURL
+		awsSpec, err := e.AWS()
+		if err != nil {
+			return constant.EmptyString, err
+		}
+
+		return awsSpec.OIDCURL, nil
 	case cloud.Azure:
-		return e.Spec.CloudSpec.Azure.OIDCURL
+		azureSpec, err := e.Azure()
+		if err != nil {
+			return constant.EmptyString, err
+		}
+
+		return azureSpec.OIDCURL, nil
+	case cloud.GCP:
+		gcpSpec, err := e.GCP()
+		if err != nil {
+			return constant.EmptyString, err
+		}
+
+		return gcpSpec.OIDCURL, nil
 	default:
-		panic(pkgerrors.NewUnsupportedCloud(v))
+		return constant.EmptyString, pkgerrors.NewUnsupportedCloud(v)
 	}
 }
 
-// NewFromBytes returns a new EnvConfig from the given bytes.
+// NewFromBytes returns a new EnvConfig from the given bytes, or an error if its API version is not supported or if it is not valid.
 func NewFromBytes(data []byte) (*EnvConfig, error) {
+	envConfig, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := envConfig.CheckAPIVersion(); err != nil {
+		return nil, err
+	}
+
+	if err := envConfig.Validate(); err != nil {
+		return nil, err
+	}
+
+	return envConfig, nil
+}
+
+// Parse returns the EnvConfig of the first document of the given bytes of its kind, without validating it, so that all of its problems can be reported
+// at once.
+func Parse(data []byte) (*EnvConfig, error) {
 	decoder := yaml.NewDecoder(bytes.NewReader(data))
 
 	for {
@@ -0,0 +1,512 @@
+// Package envconfig is the package that implements the environment configuration type.
+package envconfig
+
+import (
+	"testing"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/cloud"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
+	pkgerrors "github.com/AlphaSense-Engineering/privatecloud-cli/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEnvConfig_Validate is a test that tests that the Validate function rejects every combination of the blocks of the providers that does not match the
+// cloud provider.
+func TestEnvConfig_Validate(t *testing.T) {
+	for _, provider := range []cloud.Cloud{cloud.AWS, cloud.Azure, cloud.GCP} {
+		// Every combination of the AWS, Azure and GCP blocks, encoded as the bits of the mask in this order.
+		for mask := range 8 {
+			awsSet, azureSet, gcpSet := mask&1 != 0, mask&2 != 0, mask&4 != 0
+
+			cloudSpec := CloudSpec{Provider: string(provider)}
+
+			if awsSet {
+				cloudSpec.AWS = testAWSSpec()
+			}
+
+			if azureSet {
+				cloudSpec.Azure = testAzureSpec()
+			}
+
+			if gcpSet {
+				cloudSpec.GCP = testGCPSpec()
+			}
+
+			set := map[cloud.Cloud]bool{cloud.AWS: awsSet, cloud.Azure: azureSet, cloud.GCP: gcpSet}
+
+			wantMissing := !set[provider]
+
+			var wantForeign []string
+
+			for _, other := range []cloud.Cloud{cloud.AWS, cloud.Azure, cloud.GCP} {
+				if other != provider && set[other] {
+					wantForeign = append(wantForeign, "spec.cloudSpec."+string(other)+" is set")
+				}
+			}
+
+			t.Run(string(provider)+"/"+cloudSpecName(set), func(t *testing.T) {
+				err := (&EnvConfig{Spec: Spec{ClusterName: "acme", CloudSpec: cloudSpec}}).Validate()
+
+				if !wantMissing && wantForeign == nil {
+					require.NoError(t, err)
+
+					return
+				}
+
+				if wantMissing {
+					require.ErrorIs(t, err, ErrCloudSpecMissing)
+					assert.ErrorContains(t, err, "spec.cloudSpec.provider is "+string(provider)+" but spec.cloudSpec."+string(provider)+" is not set")
+				} else {
+					assert.NotErrorIs(t, err, ErrCloudSpecMissing)
+				}
+
+				if wantForeign == nil {
+					assert.NotErrorIs(t, err, ErrForeignCloudSpec)
+
+					return
+				}
+
+				require.ErrorIs(t, err, ErrForeignCloudSpec)
+
+				for _, foreign := range wantForeign {
+					assert.ErrorContains(t, err, foreign)
+				}
+			})
+		}
+	}
+}
+
+// testAWSSpec is a function that returns an AWS block with all of its required fields set.
+func testAWSSpec() *AWSSpec {
+	return &AWSSpec{AccountID: "123456789012", OIDCURL: "https://oidc.eks.us-east-1.amazonaws.com/id/ABCDEF0123456789ABCDEF0123456789"}
+}
+
+// testAzureSpec is a function that returns an Azure block with all of its required fields set.
+func testAzureSpec() *AzureSpec {
+	return &AzureSpec{
+		ClientID:       "client",
+		ResourceGroup:  "group",
+		SubscriptionID: "subscription",
+		TenantID:       "tenant",
+		OIDCURL:        "https://eastus.oic.prod-aks.azure.com/tenant/issuer/",
+	}
+}
+
+// testGCPSpec is a function that returns a GCP block with all of its required fields set.
+func testGCPSpec() *GCPSpec {
+	return &GCPSpec{ProjectID: "project", ProjectNumber: "123456789012"}
+}
+
+// cloudSpecName is a function that returns the name of the test case for the set blocks of the providers.
+func cloudSpecName(set map[cloud.Cloud]bool) string {
+	name := "blocks"
+
+	for _, c := range []cloud.Cloud{cloud.AWS, cloud.Azure, cloud.GCP} {
+		if set[c] {
+			name += "-" + string(c)
+		}
+	}
+
+	return name
+}
+
+// TestEnvConfig_Validate_unsupportedProvider is a test that tests that the Validate function rejects an unsupported cloud provider.
+func TestEnvConfig_Validate_unsupportedProvider(t *testing.T) {
+	err := (&EnvConfig{Spec: Spec{CloudSpec: CloudSpec{Provider: "oci", AWS: &AWSSpec{}}}}).Validate()
+
+	assert.EqualError(t, err, pkgerrors.NewUnsupportedCloud("oci").Error())
+}
+
+// TestEnvConfig_Validate_requiredFields is a test that tests that the Validate function lists all of the required fields that are not set, from the cluster
+// name to the fields of the block of the provider, in a single error.
+func TestEnvConfig_Validate_requiredFields(t *testing.T) {
+	testCases := []struct {
+		name      string
+		envConfig *EnvConfig
+		want      []string
+	}{
+		{
+			name:      "Valid",
+			envConfig: &EnvConfig{Spec: Spec{ClusterName: "acme", CloudSpec: CloudSpec{Provider: string(cloud.GCP), GCP: testGCPSpec()}}},
+		},
+		{
+			name:      "Cluster name missing",
+			envConfig: &EnvConfig{Spec: Spec{CloudSpec: CloudSpec{Provider: string(cloud.AWS), AWS: testAWSSpec()}}},
+			want:      []string{"spec.clusterName"},
+		},
+		{
+			name:      "AWS fields missing",
+			envConfig: &EnvConfig{Spec: Spec{ClusterName: "acme", CloudSpec: CloudSpec{Provider: string(cloud.AWS), AWS: &AWSSpec{}}}},
+			want:      []string{"spec.cloudSpec.aws.accountID", "spec.cloudSpec.aws.oidcUrl"},
+		},
+		{
+			name: "Azure fields missing",
+			envConfig: &EnvConfig{Spec: Spec{ClusterName: "acme", CloudSpec: CloudSpec{
+				Provider: string(cloud.Azure),
+				Azure:    &AzureSpec{ClientID: "client", TenantID: "tenant"},
+			}}},
+			want: []string{"spec.cloudSpec.azure.resourceGroup", "spec.cloudSpec.azure.subscriptionID", "spec.cloudSpec.azure.oidcUrl"},
+		},
+		{
+			name:      "GCP fields missing with the cluster name",
+			envConfig: &EnvConfig{Spec: Spec{CloudSpec: CloudSpec{Provider: string(cloud.GCP), GCP: &GCPSpec{ProjectID: "project"}}}},
+			want:      []string{"spec.clusterName", "spec.cloudSpec.gcp.projectNumber"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.envConfig.Validate()
+
+			if tc.want == nil {
+				require.NoError(t, err)
+
+				return
+			}
+
+			var keysMissingErr *pkgerrors.KeysMissing[string]
+
+			require.ErrorAs(t, err, &keysMissingErr)
+			assert.EqualError(t, keysMissingErr, pkgerrors.NewKeysMissing(tc.want).Error())
+		})
+	}
+}
+
+// TestNewFromBytes is a test that tests that the NewFromBytes function validates the environment configuration.
+func TestNewFromBytes(t *testing.T) {
+	testCases := []struct {
+		name       string
+		data       string
+		wantErr    error
+		wantErrMsg string
+	}{
+		{
+			name: "Valid",
+			data: `kind: EnvConfig
+spec:
+  clusterName: acme
+  cloudSpec:
+    provider: aws
+    aws:
+      accountID: "123456789012"
+      oidcUrl: https://oidc.eks.us-east-1.amazonaws.com/id/ABCDEF0123456789ABCDEF0123456789
+`,
+		},
+		{
+			name: "Provider block missing",
+			data: `kind: EnvConfig
+spec:
+  clusterName: acme
+  cloudSpec:
+    provider: aws
+    gcp:
+      projectID: project
+`,
+			wantErr: ErrCloudSpecMissing,
+		},
+		{
+			name: "Supported API version",
+			data: `apiVersion: alpha-sense.com/v1
+kind: EnvConfig
+spec:
+  clusterName: acme
+  cloudSpec:
+    provider: aws
+    aws:
+      accountID: "123456789012"
+      oidcUrl: https://oidc.eks.us-east-1.amazonaws.com/id/ABCDEF0123456789ABCDEF0123456789
+`,
+		},
+		{
+			name: "Unsupported API version",
+			data: `apiVersion: alpha-sense.com/v2
+kind: EnvConfig
+spec:
+  clusterName: acme
+  cloudSpec:
+    provider: aws
+    aws:
+      accountID: "123456789012"
+      oidcUrl: https://oidc.eks.us-east-1.amazonaws.com/id/ABCDEF0123456789ABCDEF0123456789
+`,
+			wantErr: ErrUnsupportedAPIVersion,
+		},
+		{
+			name: "Required fields missing",
+			data: `kind: EnvConfig
+spec:
+  cloudSpec:
+    provider: aws
+    aws:
+      accountID: "123456789012"
+`,
+			wantErrMsg: "keys missing: spec.clusterName, spec.cloudSpec.aws.oidcUrl",
+		},
+		{
+			name: "No EnvConfig",
+			data: `kind: Other
+`,
+			wantErr: errNoEnvConfigKindFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			envConfig, err := NewFromBytes([]byte(tc.data))
+
+			if tc.wantErr != nil || tc.wantErrMsg != constant.EmptyString {
+				if tc.wantErr != nil {
+					require.ErrorIs(t, err, tc.wantErr)
+				} else {
+					require.EqualError(t, err, tc.wantErrMsg)
+				}
+
+				assert.Nil(t, envConfig)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "123456789012", envConfig.Spec.CloudSpec.AWS.AccountID)
+		})
+	}
+}
+
+// TestParse is a test that tests that the Parse function returns the environment configuration without validating it.
+func TestParse(t *testing.T) {
+	data := `kind: Other
+---
+kind: EnvConfig
+spec:
+  cloudSpec:
+    provider: aws
+    gcp:
+      projectID: project
+`
+
+	envConfig, err := Parse([]byte(data))
+	require.NoError(t, err)
+	assert.Equal(t, "project", envConfig.Spec.CloudSpec.GCP.ProjectID)
+	require.ErrorIs(t, envConfig.Validate(), ErrCloudSpecMissing)
+
+	_, err = Parse([]byte("kind: Other\n"))
+	require.ErrorIs(t, err, errNoEnvConfigKindFound)
+}
+
+// TestEnvConfig_cloudSpecAccessors is a test that tests that the AWS, Azure and GCP functions return the block of the cloud provider, or an error if the
+// block is not set or if the environment configuration is for another cloud provider.
+//
+// nolint:funlen
+func TestEnvConfig_cloudSpecAccessors(t *testing.T) {
+	awsSpec := &AWSSpec{AccountID: "123456789012"}
+	azureSpec := &AzureSpec{TenantID: "tenant"}
+	gcpSpec := &GCPSpec{ProjectID: "project"}
+
+	testCases := []struct {
+		name      string
+		cloudSpec CloudSpec
+		wantAWS   error
+		wantAzure error
+		wantGCP   error
+	}{
+		{
+			name:      "AWS present",
+			cloudSpec: CloudSpec{Provider: string(cloud.AWS), AWS: awsSpec},
+			wantAzure: ErrProviderMismatch,
+			wantGCP:   ErrProviderMismatch,
+		},
+		{
+			name:      "AWS absent",
+			cloudSpec: CloudSpec{Provider: string(cloud.AWS)},
+			wantAWS:   ErrCloudSpecMissing,
+			wantAzure: ErrProviderMismatch,
+			wantGCP:   ErrProviderMismatch,
+		},
+		{
+			name:      "Azure present",
+			cloudSpec: CloudSpec{Provider: string(cloud.Azure), Azure: azureSpec},
+			wantAWS:   ErrProviderMismatch,
+			wantGCP:   ErrProviderMismatch,
+		},
+		{
+			name:      "Azure absent",
+			cloudSpec: CloudSpec{Provider: string(cloud.Azure)},
+			wantAWS:   ErrProviderMismatch,
+			wantAzure: ErrCloudSpecMissing,
+			wantGCP:   ErrProviderMismatch,
+		},
+		{
+			name:      "GCP present",
+			cloudSpec: CloudSpec{Provider: string(cloud.GCP), GCP: gcpSpec},
+			wantAWS:   ErrProviderMismatch,
+			wantAzure: ErrProviderMismatch,
+		},
+		{
+			name:      "GCP absent",
+			cloudSpec: CloudSpec{Provider: string(cloud.GCP)},
+			wantAWS:   ErrProviderMismatch,
+			wantAzure: ErrProviderMismatch,
+			wantGCP:   ErrCloudSpecMissing,
+		},
+		{
+			name:      "Foreign block present",
+			cloudSpec: CloudSpec{Provider: string(cloud.GCP), GCP: gcpSpec, AWS: awsSpec},
+			wantAWS:   ErrProviderMismatch,
+			wantAzure: ErrProviderMismatch,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			envConfig := &EnvConfig{Spec: Spec{CloudSpec: tc.cloudSpec}}
+
+			assertAccessor(t, awsSpec, tc.wantAWS)(envConfig.AWS())
+			assertAccessor(t, azureSpec, tc.wantAzure)(envConfig.Azure())
+			assertAccessor(t, gcpSpec, tc.wantGCP)(envConfig.GCP())
+		})
+	}
+}
+
+// assertAccessor is a function that returns a function asserting that the accessor returned the expected block, or the expected error if it is not nil.
+func assertAccessor[T any](t *testing.T, want *T, wantErr error) func(*T, error) {
+	return func(got *T, err error) {
+		t.Helper()
+
+		if wantErr != nil {
+			assert.ErrorIs(t, err, wantErr)
+			assert.Nil(t, got)
+
+			return
+		}
+
+		assert.NoError(t, err)
+		assert.Same(t, want, got)
+	}
+}
+
+// TestEnvConfig_OIDCURL is a test that tests the OIDCURL function.
+func TestEnvConfig_OIDCURL(t *testing.T) {
+	testCases := []struct {
+		name      string
+		cloudSpec CloudSpec
+		want      string
+		wantErr   error
+	}{
+		{
+			name:      "AWS",
+			cloudSpec: CloudSpec{Provider: string(cloud.AWS), AWS: &AWSSpec{OIDCURL: "aws-oidc"}},
+			want:      "aws-oidc",
+		},
+		{
+			name:      "Azure",
+			cloudSpec: CloudSpec{Provider: string(cloud.Azure), Azure: &AzureSpec{OIDCURL: "azure-oidc"}},
+			want:      "azure-oidc",
+		},
+		{
+			name:      "AWS block absent",
+			cloudSpec: CloudSpec{Provider: string(cloud.AWS)},
+			wantErr:   ErrCloudSpecMissing,
+		},
+		{
+			name:      "GCP",
+			cloudSpec: CloudSpec{Provider: string(cloud.GCP), GCP: &GCPSpec{OIDCURL: "gcp-oidc"}},
+			want:      "gcp-oidc",
+		},
+		{
+			name:      "GCP without OIDC URL",
+			cloudSpec: CloudSpec{Provider: string(cloud.GCP), GCP: &GCPSpec{}},
+		},
+		{
+			name:      "Unsupported",
+			cloudSpec: CloudSpec{Provider: "unsupported"},
+			wantErr:   pkgerrors.NewUnsupportedCloud("unsupported"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := (&EnvConfig{Spec: Spec{CloudSpec: tc.cloudSpec}}).OIDCURL()
+
+			if tc.wantErr != nil {
+				assert.ErrorContains(t, err, tc.wantErr.Error())
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+// TestEnvConfig_GPURequired is a test that tests that the GPURequired function defaults to requiring GPU nodes unless spec.requiresGPU is false.
+func TestEnvConfig_GPURequired(t *testing.T) {
+	testCases := []struct {
+		name string
+		spec string
+		want bool
+	}{
+		{name: "Not set", spec: "clusterName: acme", want: true},
+		{name: "Required", spec: "requiresGPU: true", want: true},
+		{name: "Not required", spec: "requiresGPU: false", want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			envConfig, err := Parse([]byte("kind: EnvConfig\nspec:\n  " + tc.spec + "\n"))
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.want, envConfig.GPURequired())
+		})
+	}
+}
+
+// TestEnvConfig_CheckSinceVersion is a test that tests that the CheckSinceVersion function accepts the versions that are not older than the lowest
+// supported one, and the unset one, and rejects the others.
+func TestEnvConfig_CheckSinceVersion(t *testing.T) {
+	// since is the lowest supported version used in the test.
+	const since = "2.1.0"
+
+	testCases := []struct {
+		name    string
+		version string
+		since   string
+		wantErr error
+	}{
+		{name: "Not set"},
+		{name: "Minimum", version: since},
+		{name: "Prefixed", version: "v2.4.1"},
+		{name: "Pre-release", version: "2.5.0-rc.1"},
+		{name: "Without patch", version: "2.1"},
+		{name: "Older", version: "2.0.1", wantErr: ErrUnsupportedVersion},
+		{name: "Invalid", version: "latest", wantErr: errInvalidVersion},
+		{name: "Invalid lowest supported", version: "2.1.0", since: "2.x", wantErr: errInvalidVersion},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			minimum := tc.since
+			if minimum == constant.EmptyString {
+				minimum = since
+			}
+
+			err := (&EnvConfig{Spec: Spec{Version: tc.version}}).CheckSinceVersion(minimum)
+
+			if tc.wantErr == nil {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+// TestValidateVersion is a test that tests that the ValidateVersion function accepts the versions in the major.minor.patch format and rejects the others.
+func TestValidateVersion(t *testing.T) {
+	assert.NoError(t, ValidateVersion("v2.1.0"))
+	assert.ErrorIs(t, ValidateVersion("2.1.0.0"), errInvalidVersion)
+	assert.ErrorIs(t, ValidateVersion("latest"), errInvalidVersion)
+}
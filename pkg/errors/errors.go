@@ -26,6 +26,11 @@ func (e *ErrWithChangelog) Error() string {
 	return fmt.Errorf("%w: %#v", e.err, e.changelog).Error()
 }
 
+// Changelog is a function that returns the changelog.
+func (e *ErrWithChangelog) Changelog() diff.Changelog {
+	return e.changelog
+}
+
 // NewErrWithChangelog is a function that returns a new ErrWithChangelog error.
 func NewErrWithChangelog(err error, changelog diff.Changelog) error {
 	return &ErrWithChangelog{err: err, changelog: changelog}
@@ -49,7 +54,17 @@ func NewEnvVarIsNotSetOrEmpty(envVar string) error {
 	return &EnvVarIsNotSetOrEmpty{envVar: envVar}
 }
 
-// KeyExpectedGot is the error that is returned when the key is expected to be a certain value, but it is not.
+// Comparison is the type of how the value of a key is compared with the expected one.
+type Comparison string
+
+const (
+	// ComparisonExact is the comparison of the value that is expected to be the expected one.
+	ComparisonExact Comparison = "exact"
+	// ComparisonAtLeast is the comparison of the numeric value that is expected to be at least the expected one.
+	ComparisonAtLeast Comparison = "at least"
+)
+
+// KeyExpectedGot is the error that is returned when the key is expected to be a certain value, or at least a certain value, but it is not.
 type KeyExpectedGot struct {
 	// key is the key that is mismatched.
 	key string
@@ -57,18 +72,35 @@ type KeyExpectedGot struct {
 	expected string
 	// got is the got value.
 	got string
+	// comparison is how the got value is compared with the expected one.
+	comparison Comparison
 }
 
 var _ error = &KeyExpectedGot{}
 
 // Error is a function that returns the error message.
 func (e *KeyExpectedGot) Error() string {
+	if e.comparison == ComparisonAtLeast {
+		return fmt.Sprintf("expected %s to be at least %s, got %s", e.key, e.expected, e.got)
+	}
+
 	return fmt.Sprintf("expected %s to be %s, got %s", e.key, e.expected, e.got)
 }
 
-// NewKeyExpectedGot is a function that returns a new KeyExpectedGot error.
+// Comparison is a function that returns how the got value is compared with the expected one, which tells the exact-match failures apart from the
+// threshold ones.
+func (e *KeyExpectedGot) Comparison() Comparison {
+	return e.comparison
+}
+
+// NewKeyExpectedGot is a function that returns a new KeyExpectedGot error of a key that is expected to be the expected value.
 func NewKeyExpectedGot(key, expected, got string) *KeyExpectedGot {
-	return &KeyExpectedGot{key: key, expected: expected, got: got}
+	return &KeyExpectedGot{key: key, expected: expected, got: got, comparison: ComparisonExact}
+}
+
+// NewKeyExpectedAtLeastGot is a function that returns a new KeyExpectedGot error of a key that is expected to be at least the expected value.
+func NewKeyExpectedAtLeastGot(key, expected, got string) *KeyExpectedGot {
+	return &KeyExpectedGot{key: key, expected: expected, got: got, comparison: ComparisonAtLeast}
 }
 
 // KeysEmpty is the error that is returned when the keys are empty.
@@ -95,6 +127,30 @@ func NewKeysEmpty[K comparable](keys []K) error {
 	return &KeysEmpty[K]{keys: keys}
 }
 
+// KeysBlank is the error that is returned when the values of the keys are whitespace-only, such as the trailing newline that echo adds.
+type KeysBlank[K comparable] struct {
+	// keys is the list of keys whose values are whitespace-only.
+	keys []K
+}
+
+var _ error = &KeysBlank[any]{}
+
+// Error is a function that returns the error message.
+func (e *KeysBlank[K]) Error() string {
+	strKeys := make([]string, len(e.keys))
+
+	for i, key := range e.keys {
+		strKeys[i] = fmt.Sprintf("%v", key)
+	}
+
+	return fmt.Sprintf("keys whitespace-only: %s", strings.Join(strKeys, ", "))
+}
+
+// NewKeysBlank is a function that returns a new KeysBlank error.
+func NewKeysBlank[K comparable](keys []K) error {
+	return &KeysBlank[K]{keys: keys}
+}
+
 // KeysMissing is the error that is returned when the keys are missing.
 type KeysMissing[K comparable] struct {
 	// keys is the list of keys that are missing.
@@ -119,6 +175,57 @@ func NewKeysMissing[K comparable](keys []K) error {
 	return &KeysMissing[K]{keys: keys}
 }
 
+// NamespacesMissing is the error that is returned when the namespaces are missing and cannot be created.
+type NamespacesMissing struct {
+	// namespaces is the list of namespaces that are missing.
+	namespaces []string
+}
+
+var _ error = &NamespacesMissing{}
+
+// Error is a function that returns the error message.
+func (e *NamespacesMissing) Error() string {
+	return fmt.Sprintf("namespaces missing and cannot be created: %s", strings.Join(e.namespaces, ", "))
+}
+
+// NewNamespacesMissing is a function that returns a new NamespacesMissing error.
+func NewNamespacesMissing(namespaces []string) error {
+	return &NamespacesMissing{namespaces: namespaces}
+}
+
+// JWTsPartiallyRetrieved is the error that is returned when the JWTs are retrieved for some of the service accounts only.
+type JWTsPartiallyRetrieved struct {
+	// serviceAccounts is the list of the service accounts the JWTs cannot be retrieved for.
+	serviceAccounts []string
+	// errs is the list of the errors, one per service account.
+	errs []error
+}
+
+var _ error = &JWTsPartiallyRetrieved{}
+
+// Error is a function that returns the error message.
+func (e *JWTsPartiallyRetrieved) Error() string {
+	failures := make([]string, len(e.serviceAccounts))
+
+	for i, sa := range e.serviceAccounts {
+		failures[i] = fmt.Sprintf("%s: %s", sa, e.errs[i])
+	}
+
+	return fmt.Sprintf("failed to retrieve JWTs for service accounts: %s", strings.Join(failures, "; "))
+}
+
+// ServiceAccounts is a function that returns the list of the service accounts the JWTs cannot be retrieved for.
+func (e *JWTsPartiallyRetrieved) ServiceAccounts() []string {
+	return e.serviceAccounts
+}
+
+// NewJWTsPartiallyRetrieved is a function that returns a new JWTsPartiallyRetrieved error.
+//
+// The errors are expected to be in the same order as the service accounts.
+func NewJWTsPartiallyRetrieved(serviceAccounts []string, errs []error) error {
+	return &JWTsPartiallyRetrieved{serviceAccounts: serviceAccounts, errs: errs}
+}
+
 // RoleMissingPermissions is the error that is returned when the role is missing permissions.
 type RoleMissingPermissions struct {
 	// missingPermissions is the list of missing permissions.
@@ -154,3 +261,55 @@ func (e *UnsupportedCloud) Error() string {
 func NewUnsupportedCloud(cloud cloud.Cloud) error {
 	return &UnsupportedCloud{cloud: cloud}
 }
+
+// CommandFailed is the error that is returned when the command fails, carrying the tail of its standard error output.
+type CommandFailed struct {
+	// err is the error returned by the command.
+	err error
+	// stderrTail is the tail of the standard error output of the command.
+	stderrTail string
+}
+
+var _ error = &CommandFailed{}
+
+// Error is a function that returns the error message.
+func (e *CommandFailed) Error() string {
+	return fmt.Sprintf("%s: %s", e.err, e.stderrTail)
+}
+
+// Unwrap is a function that returns the error returned by the command.
+func (e *CommandFailed) Unwrap() error {
+	return e.err
+}
+
+// NewCommandFailed is a function that returns a new CommandFailed error.
+func NewCommandFailed(err error, stderrTail string) error {
+	return &CommandFailed{err: err, stderrTail: stderrTail}
+}
+
+// SecretNotFound is the error that is returned when the secret is not found, naming its namespace and name.
+type SecretNotFound struct {
+	// namespace is the namespace of the secret.
+	namespace string
+	// name is the name of the secret.
+	name string
+	// err is the error returned by the Kubernetes API.
+	err error
+}
+
+var _ error = &SecretNotFound{}
+
+// Error is a function that returns the error message.
+func (e *SecretNotFound) Error() string {
+	return fmt.Sprintf("secret %s/%s not found", e.namespace, e.name)
+}
+
+// Unwrap is a function that returns the error returned by the Kubernetes API.
+func (e *SecretNotFound) Unwrap() error {
+	return e.err
+}
+
+// NewSecretNotFound is a function that returns a new SecretNotFound error.
+func NewSecretNotFound(namespace string, name string, err error) error {
+	return &SecretNotFound{namespace: namespace, name: name, err: err}
+}
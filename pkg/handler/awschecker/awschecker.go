@@ -3,17 +3,20 @@ package awschecker
 
 import (
 	"context"
-	"net/http"
+	"errors"
 
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/cloud/awscloudutil"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/envconfig"
+	pkgerrors "github.com/AlphaSense-Engineering/privatecloud-cli/pkg/errors"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/awscrossplanerolechecker"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/awsjwtretriever"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/crossplanerolechecker"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/jwtchecker"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/jwtretriever"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/providerconfigchecker"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/serviceaccountchecker"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/util"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/credentials"
@@ -21,35 +24,42 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"github.com/charmbracelet/log"
 	"go.uber.org/multierr"
-	"k8s.io/client-go/kubernetes"
 )
 
 // AWSChecker is the type that contains the infrastructure check functions for AWS.
 type AWSChecker struct {
+	// checkCtx is the check context, passed to the checkers it sets up.
+	checkCtx handler.CheckContext
+
 	// logger is the logger.
 	logger *log.Logger
 	// envConfig is the environment configuration.
 	envConfig *envconfig.EnvConfig
-	// clientset is the Kubernetes client.
-	clientset kubernetes.Interface
-	// httpClient is the HTTP client.
-	httpClient *http.Client
 	// jwksURI is the JWKS URI.
 	jwksURI *string
 
+	// serviceAccountChecker is the Crossplane provider service accounts checker.
+	serviceAccountChecker *serviceaccountchecker.ServiceAccountChecker
 	// jwtRetriever is the JWT retriever.
 	jwtRetriever *awsjwtretriever.AWSJWTRetriever
 	// jwtChecker is the JWT checker.
 	jwtChecker *jwtchecker.JWTChecker
+	// providerConfigChecker is the Crossplane ProviderConfig checker.
+	providerConfigChecker *providerconfigchecker.ProviderConfigChecker
 }
 
 var _ handler.Handler = &AWSChecker{}
 
 // setup is the function that sets up the AWS checker.
 func (c *AWSChecker) setup() {
-	c.jwtRetriever = awsjwtretriever.New(c.clientset)
+	c.serviceAccountChecker = serviceaccountchecker.New(c.checkCtx, awsjwtretriever.Prefix(c.checkCtx.Options), constant.ServiceAccountNameAWS)
+
+	c.jwtRetriever = awsjwtretriever.New(c.checkCtx)
+
+	// The OIDC URL of AWS has no scheme, unlike the issuer of the JWTs, but the JWT checker compares the issuers regardless of it.
+	c.jwtChecker = jwtchecker.New(c.checkCtx, c.jwksURI, awsjwtretriever.Audience, util.Deref(util.DiscardErr(c.envConfig.AWS())).OIDCURL)
 
-	c.jwtChecker = jwtchecker.New(c.httpClient, c.jwksURI)
+	c.providerConfigChecker = providerconfigchecker.New(c.checkCtx)
 }
 
 // Handle is the function that handles the infrastructure check.
@@ -59,7 +69,35 @@ func (c *AWSChecker) setup() {
 //
 // nolint:funlen
 func (c *AWSChecker) Handle(ctx context.Context, _ ...any) ([]any, error) {
+	const (
+		// logMsgProviderServiceAccountsFound is the message that is logged with the number of the provider service accounts found.
+		logMsgProviderServiceAccountsFound = "found %d %s* provider service accounts in the %s namespace"
+	)
+
+	awsSpec, err := c.envConfig.AWS()
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := util.UnwrapValErr[int](c.serviceAccountChecker.Handle(ctx))
+	if err != nil {
+		return nil, multierr.Combine(jwtretriever.ErrFailedToRetrieveJWTs, err)
+	}
+
+	c.logger.Debugf(
+		logMsgProviderServiceAccountsFound, count, awsjwtretriever.Prefix(c.checkCtx.Options), c.checkCtx.Options.ProviderServiceAccountsNamespace(),
+	)
+
 	jwts, err := util.ConvertSliceErr[any, *string](c.jwtRetriever.Handle(ctx))
+
+	// The JWTs retrieved for the rest of the service accounts are still checked, the failed ones are only reported.
+	var partialErr *pkgerrors.JWTsPartiallyRetrieved
+	if len(jwts) > 0 && errors.As(err, &partialErr) {
+		c.logger.Warn(partialErr.Error())
+
+		err = nil
+	}
+
 	if err != nil {
 		return nil, multierr.Combine(jwtretriever.ErrFailedToRetrieveJWTs, err)
 	}
@@ -83,7 +121,7 @@ func (c *AWSChecker) Handle(ctx context.Context, _ ...any) ([]any, error) {
 
 		assumedRole, err = stsClient.AssumeRoleWithWebIdentity(ctx, &sts.AssumeRoleWithWebIdentityInput{
 			RoleArn: aws.String(awscloudutil.ARN(
-				c.envConfig.Spec.CloudSpec.AWS.AccountID,
+				awsSpec.AccountID,
 				c.envConfig.Spec.ClusterName,
 				awscloudutil.ARNTypeRole,
 				awscloudutil.CrossplaneRoleName(c.envConfig.Spec.ClusterName),
@@ -96,7 +134,7 @@ func (c *AWSChecker) Handle(ctx context.Context, _ ...any) ([]any, error) {
 			break
 		}
 
-		crossplaneRoleChecker := awscrossplanerolechecker.New(c.logger, c.envConfig, iam.NewFromConfig(aws.Config{
+		crossplaneRoleChecker := awscrossplanerolechecker.New(c.checkCtx, iam.NewFromConfig(aws.Config{
 			Region: region,
 			Credentials: credentials.NewStaticCredentialsProvider(
 				*assumedRole.Credentials.AccessKeyId,
@@ -116,17 +154,30 @@ func (c *AWSChecker) Handle(ctx context.Context, _ ...any) ([]any, error) {
 
 	c.logger.Info(crossplanerolechecker.LogMsgCrossplaneRoleCheckedSuccessfully)
 
+	if _, err := c.providerConfigChecker.Handle(ctx); err != nil {
+		return nil, multierr.Combine(providerconfigchecker.ErrFailedToCheckProviderConfig, err)
+	}
+
+	c.logger.Info(providerconfigchecker.LogMsgProviderConfigCheckedSuccessfully)
+
 	return nil, nil
 }
 
+// Contract is the function that returns the contract of the results of the infrastructure check.
+//
+// It returns nothing.
+func (c *AWSChecker) Contract() handler.Contract {
+	return handler.Contract{}
+}
+
 // New is the function that creates a new AWSChecker.
-func New(logger *log.Logger, envConfig *envconfig.EnvConfig, clientset kubernetes.Interface, httpClient *http.Client, jwksURI *string) *AWSChecker {
+func New(checkCtx handler.CheckContext, jwksURI *string) *AWSChecker {
 	c := &AWSChecker{
-		logger:     logger,
-		envConfig:  envConfig,
-		clientset:  clientset,
-		httpClient: httpClient,
-		jwksURI:    jwksURI,
+		checkCtx: checkCtx,
+
+		logger:    checkCtx.Logger,
+		envConfig: checkCtx.EnvConfig,
+		jwksURI:   jwksURI,
 	}
 
 	c.setup()
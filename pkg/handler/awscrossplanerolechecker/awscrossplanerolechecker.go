@@ -2,21 +2,30 @@
 package awscrossplanerolechecker
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/url"
+	"regexp"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/cloud/awscloudutil"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/envconfig"
 	pkgerrors "github.com/AlphaSense-Engineering/privatecloud-cli/pkg/errors"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/awsjwtretriever"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/util"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/iam"
 	"github.com/charmbracelet/log"
 	"github.com/r3labs/diff/v3"
+	"go.uber.org/multierr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 )
 
 var (
@@ -34,6 +43,17 @@ var (
 
 	// errPolicyDocumentMismatch is an error that occurs when the policy document does not match the expected document.
 	errPolicyDocumentMismatch = errors.New("policy document does not match")
+
+	// errInvalidPolicyStatementActions is an error that occurs when the actions of the policy statement are neither a string nor an array of strings.
+	errInvalidPolicyStatementActions = errors.New("invalid policy statement actions: must be a string or an array of strings")
+)
+
+const (
+	// defaultMaxSessionDuration is the maximum session duration of the roles that do not set one, which IAM omits from them.
+	defaultMaxSessionDuration = time.Hour
+
+	// recommendedMaxSessionDuration is the shortest maximum session duration of the role that is not warned about.
+	recommendedMaxSessionDuration = 2 * time.Hour
 )
 
 // rolePolicyCondition is the struct for the AWS role policy condition.
@@ -100,13 +120,53 @@ func (s *rolePolicyStatement) MarshalJSON() ([]byte, error) {
 	return json.Marshal(aux)
 }
 
+// unmarshalActions is the function that unmarshals the actions of the policy statement under the key, which are either a string or an array of strings,
+// or returns nil if they are absent.
+//
+// Anything else, such as null, a number or an array with other values, is rejected rather than dropped, as the statement would otherwise compare as
+// allowing fewer actions than it does.
+func unmarshalActions(key string, data json.RawMessage) (*[]*string, error) {
+	if data == nil {
+		return nil, nil
+	}
+
+	switch bytes.TrimSpace(data)[0] {
+	case '"':
+		var action string
+
+		if err := json.Unmarshal(data, &action); err != nil {
+			return nil, multierr.Combine(fmt.Errorf("%w: %s", errInvalidPolicyStatementActions, key), err)
+		}
+
+		return &[]*string{aws.String(action)}, nil
+	case '[':
+		var actions []*string
+
+		if err := json.Unmarshal(data, &actions); err != nil {
+			return nil, multierr.Combine(fmt.Errorf("%w: %s", errInvalidPolicyStatementActions, key), err)
+		}
+
+		for i, action := range actions {
+			if action == nil {
+				return nil, fmt.Errorf("%w: %s[%d] is null", errInvalidPolicyStatementActions, key, i)
+			}
+		}
+
+		return &actions, nil
+	default:
+		return nil, fmt.Errorf("%w: %s is %s", errInvalidPolicyStatementActions, key, data)
+	}
+}
+
 // UnmarshalJSON is a custom JSON unmarshaller for awsRolePolicyStatement.
+//
+// It returns an error if the actions or the not actions are neither a string nor an array of strings.
 func (s *rolePolicyStatement) UnmarshalJSON(data []byte) error {
 	type Alias rolePolicyStatement
 
 	aux := &struct {
-		Action    any `json:"Action,omitempty"`
-		NotAction any `json:"NotAction,omitempty"`
+		Action    json.RawMessage `json:"Action"`
+		NotAction json.RawMessage `json:"NotAction"`
 
 		*Alias
 	}{
@@ -117,36 +177,18 @@ func (s *rolePolicyStatement) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	switch v := aux.Action.(type) {
-	case string:
-		s.Action = &[]*string{aws.String(v)}
-	case []any:
-		var actions []*string
-
-		for _, a := range v {
-			if str, ok := a.(string); ok {
-				actions = append(actions, aws.String(str))
-			}
-		}
-
-		s.Action = &actions
+	action, err := unmarshalActions("Action", aux.Action)
+	if err != nil {
+		return err
 	}
 
-	switch v := aux.NotAction.(type) {
-	case string:
-		s.NotAction = &[]*string{aws.String(v)}
-	case []any:
-		var notActions []*string
-
-		for _, a := range v {
-			if str, ok := a.(string); ok {
-				notActions = append(notActions, aws.String(str))
-			}
-		}
-
-		s.NotAction = &notActions
+	notAction, err := unmarshalActions("NotAction", aux.NotAction)
+	if err != nil {
+		return err
 	}
 
+	s.Action, s.NotAction = action, notAction
+
 	return nil
 }
 
@@ -190,7 +232,7 @@ var (
 				},
 				Condition: &rolePolicyCondition{
 					StringLike: &map[string]*string{
-						"${OIDC_ID}:sub": aws.String("system:serviceaccount:crossplane:aws-*"),
+						"${OIDC_ID}:sub": aws.String("system:serviceaccount:${CROSSPLANE_NAMESPACE}:${SERVICE_ACCOUNTS_PREFIX}*"),
 					},
 				},
 			},
@@ -589,13 +631,24 @@ type AWSCrossplaneRoleChecker struct {
 	logger *log.Logger
 	// envConfig is the environment configuration.
 	envConfig *envconfig.EnvConfig
+	// namespace is the namespace of the service accounts of the Crossplane providers that assume the role.
+	namespace string
+	// prefix is the prefix of the names of the service accounts of the Crossplane providers that assume the role.
+	prefix string
+	// clientset is the Kubernetes client, or nil to skip the check of the trust of the service accounts.
+	clientset kubernetes.Interface
 	// iam is the AWS IAM client.
 	iam *iam.Client
+	// allowExtraStatement is the function that returns whether the statement, which none of the expected policy documents has, is ignored in the
+	// comparison of the policy documents, or nil to ignore none of them.
+	allowExtraStatement func(stmt *rolePolicyStatement) bool
 }
 
 var _ handler.Handler = &AWSCrossplaneRoleChecker{}
 
 // fillPlaceholdersString is a function that fills the placeholders in the string.
+//
+// The placeholders of the AWS cloud specification are filled with empty strings if it is not set, which Handle rejects before anything is filled.
 func (c *AWSCrossplaneRoleChecker) fillPlaceholdersString(s string) string {
 	const (
 		// clusterNamePlaceholder is the placeholder for the cluster name.
@@ -606,13 +659,25 @@ func (c *AWSCrossplaneRoleChecker) fillPlaceholdersString(s string) string {
 
 		// oidcURLPlaceholder is the placeholder for the OIDC URL.
 		oidcURLPlaceholder = "${OIDC_ID}"
+
+		// namespacePlaceholder is the placeholder for the namespace of the service accounts of the Crossplane providers.
+		namespacePlaceholder = "${CROSSPLANE_NAMESPACE}"
+
+		// prefixPlaceholder is the placeholder for the prefix of the names of the service accounts of the Crossplane providers.
+		prefixPlaceholder = "${SERVICE_ACCOUNTS_PREFIX}"
 	)
 
+	awsSpec := util.Deref(util.DiscardErr(c.envConfig.AWS()))
+
 	s = strings.ReplaceAll(s, clusterNamePlaceholder, c.envConfig.Spec.ClusterName)
 
-	s = strings.ReplaceAll(s, accountIDPlaceholder, c.envConfig.Spec.CloudSpec.AWS.AccountID)
+	s = strings.ReplaceAll(s, accountIDPlaceholder, awsSpec.AccountID)
+
+	s = strings.ReplaceAll(s, oidcURLPlaceholder, awsSpec.OIDCURL)
 
-	s = strings.ReplaceAll(s, oidcURLPlaceholder, c.envConfig.Spec.CloudSpec.AWS.OIDCURL)
+	s = strings.ReplaceAll(s, namespacePlaceholder, c.namespace)
+
+	s = strings.ReplaceAll(s, prefixPlaceholder, c.prefix)
 
 	return s
 }
@@ -628,10 +693,27 @@ func (c *AWSCrossplaneRoleChecker) fillPlaceholdersMap(m *map[string]*string) *m
 	return &newMap
 }
 
-// validatePolicyDocument is a function that validates the AWS policy document.
-//
-// nolint:gocognit
-func (c *AWSCrossplaneRoleChecker) validatePolicyDocument(document rolePolicyDocument, expectedDocument rolePolicyDocument) diff.Changelog {
+// copyPolicyDocument is a function that returns a deep copy of the AWS policy document, so that filling the placeholders of the copy does not modify the
+// expected documents, which are filled differently by the checkers with different options.
+func copyPolicyDocument(document rolePolicyDocument) rolePolicyDocument {
+	data, err := json.Marshal(document)
+	if err != nil {
+		panic(err)
+	}
+
+	var documentCopy rolePolicyDocument
+
+	if err := json.Unmarshal(data, &documentCopy); err != nil {
+		panic(err)
+	}
+
+	return documentCopy
+}
+
+// fillPolicyDocument is a function that returns a copy of the expected AWS policy document with its placeholders filled.
+func (c *AWSCrossplaneRoleChecker) fillPolicyDocument(expectedDocument rolePolicyDocument) rolePolicyDocument {
+	expectedDocument = copyPolicyDocument(expectedDocument)
+
 	for _, stmt := range expectedDocument.Statement {
 		if stmt.Principal != nil && stmt.Principal.Federated != nil {
 			*stmt.Principal.Federated = c.fillPlaceholdersString(util.Deref(stmt.Principal.Federated))
@@ -652,6 +734,56 @@ func (c *AWSCrossplaneRoleChecker) validatePolicyDocument(document rolePolicyDoc
 		}
 	}
 
+	return expectedDocument
+}
+
+// sidAllowlist is a function that returns the function that allows the extra statements whose SIDs are among the SIDs, or nil if there are none.
+func sidAllowlist(sids []string) func(stmt *rolePolicyStatement) bool {
+	if len(sids) == 0 {
+		return nil
+	}
+
+	return func(stmt *rolePolicyStatement) bool {
+		return stmt.SID != nil && slices.Contains(sids, *stmt.SID)
+	}
+}
+
+// withoutAllowedExtraStatements is a function that returns a copy of the AWS policy document without the statements that the expected one does not have,
+// by SID, and that are allowed as extra ones.
+//
+// The statements that share the SID of an expected statement are always kept, so that allowing an SID does not hide a change of an expected statement.
+func (c *AWSCrossplaneRoleChecker) withoutAllowedExtraStatements(document rolePolicyDocument, expectedDocument rolePolicyDocument) rolePolicyDocument {
+	if c.allowExtraStatement == nil {
+		return document
+	}
+
+	statements := make([]*rolePolicyStatement, 0, len(document.Statement))
+
+	for _, stmt := range document.Statement {
+		expected := stmt.SID != nil && slices.ContainsFunc(expectedDocument.Statement, func(expectedStmt *rolePolicyStatement) bool {
+			return expectedStmt.SID != nil && *expectedStmt.SID == *stmt.SID
+		})
+
+		if !expected && c.allowExtraStatement(stmt) {
+			continue
+		}
+
+		statements = append(statements, stmt)
+	}
+
+	document.Statement = statements
+
+	return document
+}
+
+// validatePolicyDocument is a function that validates the AWS policy document, ignoring the extra statements that are allowed.
+//
+// nolint:gocognit
+func (c *AWSCrossplaneRoleChecker) validatePolicyDocument(document rolePolicyDocument, expectedDocument rolePolicyDocument) diff.Changelog {
+	expectedDocument = c.fillPolicyDocument(expectedDocument)
+
+	document = c.withoutAllowedExtraStatements(document, expectedDocument)
+
 	changelog, err := diff.Diff(expectedDocument, document)
 	if err != nil {
 		panic(err)
@@ -746,13 +878,335 @@ func (c *AWSCrossplaneRoleChecker) processPolicyDocumentByARN(ctx context.Contex
 	return nil
 }
 
+// overridingDeny is the struct for a Deny statement of the actual policy document that denies actions that an Allow statement of the expected policy
+// documents requires.
+type overridingDeny struct {
+	// Deny is the name of the Deny statement of the actual policy document.
+	Deny string
+	// Allow is the name of the Allow statement of the expected policy documents.
+	Allow string
+	// Actions is the actions of the Allow statement that the Deny statement denies.
+	Actions []string
+	// Conditional is whether the Deny statement has a condition, so that it denies the actions only when the condition is satisfied.
+	Conditional bool
+}
+
+// namedStatement is the struct for a policy statement along with its name in the policy document that it belongs to.
+type namedStatement struct {
+	// stmt is the policy statement.
+	stmt *rolePolicyStatement
+	// name is the name of the policy statement, as returned by statementName.
+	name string
+}
+
+// statementName is the function that returns the SID of the policy statement, or its index in the policy document if it has none.
+func statementName(stmt *rolePolicyStatement, index int) string {
+	if stmt.SID != nil {
+		return *stmt.SID
+	}
+
+	return fmt.Sprintf("#%d", index)
+}
+
+// statementResource is the function that returns the resource of the policy statement, or any resource if it has none.
+func statementResource(stmt *rolePolicyStatement) string {
+	if stmt.Resource == nil {
+		return "*"
+	}
+
+	return *stmt.Resource
+}
+
+// patternsOverlap is the function that returns whether the patterns, in which '*' and '?' are wildcards, may match the same value, which is the case when
+// either of them matches the other.
+func patternsOverlap(a string, b string) bool {
+	return stringLikeMatches(a, b) || stringLikeMatches(b, a)
+}
+
+// actionsOverlap is the function that returns whether the action patterns may match the same action, ignoring the case as IAM does.
+func actionsOverlap(a string, b string) bool {
+	return patternsOverlap(strings.ToLower(a), strings.ToLower(b))
+}
+
+// deniesAction is the function that returns whether the Deny statement denies the action.
+//
+// The actions of the Deny statement that the expected Deny statement of the same SID also lists are not taken into account, as the expected policy
+// documents deny them on purpose.
+func deniesAction(deny *rolePolicyStatement, expectedDeny *rolePolicyStatement, action string) bool {
+	if deny.NotAction != nil {
+		for _, notAction := range *deny.NotAction {
+			if stringLikeMatches(strings.ToLower(util.Deref(notAction)), strings.ToLower(action)) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	if deny.Action == nil {
+		return false
+	}
+
+	for _, denied := range *deny.Action {
+		if !actionsOverlap(util.Deref(denied), action) {
+			continue
+		}
+
+		if expectedDeny != nil && expectedDeny.Action != nil &&
+			slices.ContainsFunc(*expectedDeny.Action, func(expected *string) bool { return util.Deref(expected) == util.Deref(denied) }) {
+			continue
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// overridingDenies is the function that returns the Deny statements of the actual policy document whose actions and resources overlap the ones of the Allow
+// statements of the expected policy documents, which IAM evaluates first, so that the role is restricted although the Allow statements match.
+//
+// The Allow statements with NotAction are not taken into account, as the actions that they allow are not listed. The Deny statements with a condition are
+// reported as conditional, as whether they apply depends on the request.
+//
+// nolint:gocognit
+func overridingDenies(document rolePolicyDocument, expectedDocuments []rolePolicyDocument) []overridingDeny {
+	expectedDenies := map[string]*rolePolicyStatement{}
+
+	var allows []namedStatement
+
+	for _, expectedDocument := range expectedDocuments {
+		for j, stmt := range expectedDocument.Statement {
+			switch util.Deref(stmt.Effect) {
+			case "Deny":
+				if stmt.SID != nil {
+					expectedDenies[*stmt.SID] = stmt
+				}
+			case "Allow":
+				if stmt.Action != nil {
+					allows = append(allows, namedStatement{stmt: stmt, name: statementName(stmt, j)})
+				}
+			}
+		}
+	}
+
+	var overriding []overridingDeny
+
+	for i, deny := range document.Statement {
+		if util.Deref(deny.Effect) != "Deny" {
+			continue
+		}
+
+		var expectedDeny *rolePolicyStatement
+
+		if deny.SID != nil {
+			expectedDeny = expectedDenies[*deny.SID]
+		}
+
+		for _, allow := range allows {
+			if !patternsOverlap(statementResource(deny), statementResource(allow.stmt)) {
+				continue
+			}
+
+			var actions []string
+
+			for _, action := range *allow.stmt.Action {
+				if deniesAction(deny, expectedDeny, util.Deref(action)) {
+					actions = append(actions, util.Deref(action))
+				}
+			}
+
+			if len(actions) > 0 {
+				overriding = append(overriding, overridingDeny{
+					Deny:        statementName(deny, i),
+					Allow:       allow.name,
+					Actions:     actions,
+					Conditional: deny.Condition != nil,
+				})
+			}
+		}
+	}
+
+	return overriding
+}
+
+// checkOverridingDenies is the function that warns about each of the Deny statements of the actual policy document that deny the actions that the Allow
+// statements of the expected policy documents require, as the role may then be unable to do what the Crossplane providers need although the policy
+// documents match the expected ones.
+func (c *AWSCrossplaneRoleChecker) checkOverridingDenies(policyARN string, document rolePolicyDocument) {
+	const (
+		// logMsgOverridingDeny is the message that is logged when a Deny statement overrides a required Allow statement.
+		logMsgOverridingDeny = "statement %s of %s policy %s %s, which statement %s of the expected policy documents allows; the role may be " +
+			"functionally restricted although its policy documents match the expected ones"
+
+		// denies is the verb of the message of a Deny statement without a condition.
+		denies = "denies"
+
+		// deniesConditionally is the verb of the message of a Deny statement with a condition.
+		deniesConditionally = "conditionally denies"
+	)
+
+	expectedDocuments := make([]rolePolicyDocument, 0, len(constExpectedPolicyDocuments))
+
+	for _, expectedDocument := range constExpectedPolicyDocuments {
+		expectedDocuments = append(expectedDocuments, c.fillPolicyDocument(expectedDocument))
+	}
+
+	for _, overriding := range overridingDenies(document, expectedDocuments) {
+		verb := denies
+
+		if overriding.Conditional {
+			verb = deniesConditionally
+		}
+
+		c.logger.Warnf(logMsgOverridingDeny, overriding.Deny, policyARN, verb, strings.Join(overriding.Actions, ", "), overriding.Allow)
+	}
+}
+
+// subjectConditionKeySuffix is the suffix of the key of the condition on the subject of the web identity token in the assume role policy document.
+const subjectConditionKeySuffix = ":sub"
+
+// serviceAccountSubject is the function that returns the subject of the web identity tokens of the service account in the namespace.
+func serviceAccountSubject(namespace string, name string) string {
+	return fmt.Sprintf("system:serviceaccount:%s:%s", namespace, name)
+}
+
+// stringLikeMatches is the function that returns whether the value matches the pattern of a StringLike condition, in which '*' matches any sequence of
+// characters and '?' matches any single character.
+func stringLikeMatches(pattern string, value string) bool {
+	expr := regexp.QuoteMeta(pattern)
+	expr = strings.ReplaceAll(expr, `\*`, ".*")
+	expr = strings.ReplaceAll(expr, `\?`, ".")
+
+	return regexp.MustCompile("^" + expr + "$").MatchString(value)
+}
+
+// trustsSubject is the function that returns whether an Allow statement of the assume role policy document trusts the subject.
+//
+// The subject must satisfy all of the conditions on it of the statement, as each of the condition operators must be satisfied; a statement without them
+// trusts any subject.
+func trustsSubject(document rolePolicyDocument, subject string) bool {
+	for _, stmt := range document.Statement {
+		if util.Deref(stmt.Effect) != "Allow" {
+			continue
+		}
+
+		trusted := true
+
+		if stmt.Condition != nil && stmt.Condition.StringEquals != nil {
+			for key, value := range *stmt.Condition.StringEquals {
+				if strings.HasSuffix(key, subjectConditionKeySuffix) && util.Deref(value) != subject {
+					trusted = false
+				}
+			}
+		}
+
+		if stmt.Condition != nil && stmt.Condition.StringLike != nil {
+			for key, value := range *stmt.Condition.StringLike {
+				if strings.HasSuffix(key, subjectConditionKeySuffix) && !stringLikeMatches(util.Deref(value), subject) {
+					trusted = false
+				}
+			}
+		}
+
+		if trusted {
+			return true
+		}
+	}
+
+	return false
+}
+
+// untrustedServiceAccounts is the function that returns the names of the service accounts in the namespace whose subjects the assume role policy document
+// does not trust.
+func untrustedServiceAccounts(document rolePolicyDocument, namespace string, names []string) []string {
+	var untrusted []string
+
+	for _, name := range names {
+		if !trustsSubject(document, serviceAccountSubject(namespace, name)) {
+			untrusted = append(untrusted, name)
+		}
+	}
+
+	return untrusted
+}
+
+// checkServiceAccountsTrust is the function that warns about each of the service accounts of the Crossplane providers whose subject the assume role
+// policy document does not trust, which are not able to assume the role although they are named like the ones that are.
+func (c *AWSCrossplaneRoleChecker) checkServiceAccountsTrust(ctx context.Context, document rolePolicyDocument) error {
+	const (
+		// logMsgServiceAccountNotTrusted is the message that is logged when the assume role policy document does not trust a service account.
+		logMsgServiceAccountNotTrusted = "assume role policy document does not trust service account %s/%s, its subject %s does not match the condition"
+	)
+
+	if c.clientset == nil {
+		return nil
+	}
+
+	serviceAccounts, err := c.clientset.CoreV1().ServiceAccounts(c.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	var names []string
+
+	for _, sa := range serviceAccounts.Items {
+		if strings.HasPrefix(sa.Name, c.prefix) {
+			names = append(names, sa.Name)
+		}
+	}
+
+	for _, name := range untrustedServiceAccounts(document, c.namespace, names) {
+		c.logger.Warnf(logMsgServiceAccountNotTrusted, c.namespace, name, serviceAccountSubject(c.namespace, name))
+	}
+
+	return nil
+}
+
+// checkMaxSessionDuration is the function that logs the maximum session duration of the role, warning if it is shorter than the recommended one, as the
+// long-running operations of the Crossplane providers that assume the role fail intermittently when their sessions expire in the middle of them.
+func (c *AWSCrossplaneRoleChecker) checkMaxSessionDuration(roleName string, maxSessionDuration *int32) {
+	const (
+		// logMsgMaxSessionDuration is the message that is logged with the maximum session duration of the role.
+		logMsgMaxSessionDuration = "maximum session duration of %s role is %s"
+
+		// logMsgMaxSessionDurationTooShort is the message that is logged when the maximum session duration of the role is shorter than the recommended one.
+		logMsgMaxSessionDurationTooShort = "maximum session duration of %s role is %s, shorter than the recommended %s; the long-running operations of the " +
+			"Crossplane providers may fail when their sessions expire"
+	)
+
+	duration := defaultMaxSessionDuration
+
+	if maxSessionDuration != nil {
+		duration = time.Duration(*maxSessionDuration) * time.Second
+	}
+
+	if duration < recommendedMaxSessionDuration {
+		c.logger.Warnf(logMsgMaxSessionDurationTooShort, roleName, duration, recommendedMaxSessionDuration)
+
+		return
+	}
+
+	c.logger.Infof(logMsgMaxSessionDuration, roleName, duration)
+}
+
 // Handle is the function that handles the AWS Crossplane role check.
 //
+// The service accounts in the namespace of the options named like the ones that assume the role are checked against the subject condition of the actual assume
+// role policy document, warning about the ones it does not trust. The maximum session duration of the role is warned about if it is shorter than the
+// recommended one. The Deny statements of the attached policies that deny what the expected Allow statements allow are warned about, as the policy
+// documents match the expected ones all the same.
+//
 // The arguments are not used.
 // It returns nothing on success, or an error on failure.
 //
 // nolint:funlen,gocognit
 func (c *AWSCrossplaneRoleChecker) Handle(ctx context.Context, _ ...any) ([]any, error) {
+	awsSpec, err := c.envConfig.AWS()
+	if err != nil {
+		return nil, err
+	}
+
 	roleName := awscloudutil.CrossplaneRoleName(c.envConfig.Spec.ClusterName)
 
 	role, err := c.iam.GetRole(ctx, &iam.GetRoleInput{RoleName: aws.String(roleName)})
@@ -760,6 +1214,8 @@ func (c *AWSCrossplaneRoleChecker) Handle(ctx context.Context, _ ...any) ([]any,
 		return nil, err
 	}
 
+	c.checkMaxSessionDuration(roleName, role.Role.MaxSessionDuration)
+
 	if role.Role.AssumeRolePolicyDocument == nil {
 		return nil, errNoAssumeRolePolicyDocument
 	}
@@ -775,13 +1231,17 @@ func (c *AWSCrossplaneRoleChecker) Handle(ctx context.Context, _ ...any) ([]any,
 		return nil, err
 	}
 
+	if err := c.checkServiceAccountsTrust(ctx, assumeRolePolicyDocument); err != nil {
+		return nil, err
+	}
+
 	changelog := c.validatePolicyDocument(assumeRolePolicyDocument, constExpectedAssumeRolePolicyDocument)
 	if len(changelog) > 0 {
 		return nil, pkgerrors.NewErrWithChangelog(errAssumeRolePolicyDocumentMismatch, changelog)
 	}
 
 	boundaryPolicyARN := aws.String(awscloudutil.ARN(
-		c.envConfig.Spec.CloudSpec.AWS.AccountID,
+		awsSpec.AccountID,
 		c.envConfig.Spec.ClusterName,
 		awscloudutil.ARNTypePolicy,
 		roleName,
@@ -849,6 +1309,8 @@ func (c *AWSCrossplaneRoleChecker) Handle(ctx context.Context, _ ...any) ([]any,
 			continue
 		}
 
+		c.checkOverridingDenies(*attached.PolicyArn, policyDocument)
+
 		for j, expected := range constExpectedPolicyDocuments {
 			if matched[j] != nil {
 				continue
@@ -876,11 +1338,26 @@ func (c *AWSCrossplaneRoleChecker) Handle(ctx context.Context, _ ...any) ([]any,
 	return nil, nil
 }
 
+// Contract is the function that returns the contract of the results of the AWS Crossplane role check.
+//
+// It returns nothing.
+func (c *AWSCrossplaneRoleChecker) Contract() handler.Contract {
+	return handler.Contract{}
+}
+
 // New is the function that creates a new AWSCrossplaneRoleChecker.
-func New(logger *log.Logger, envConfig *envconfig.EnvConfig, iam *iam.Client) *AWSCrossplaneRoleChecker {
+//
+// The service accounts of the Crossplane providers are expected in the namespace and with the prefix of the options, or else in the crossplane namespace
+// with the aws- prefix. The extra statements of the policy documents whose SIDs the options allow are ignored, and any other one is a mismatch.
+func New(checkCtx handler.CheckContext, iam *iam.Client) *AWSCrossplaneRoleChecker {
 	return &AWSCrossplaneRoleChecker{
-		logger:    logger,
-		envConfig: envConfig,
+		logger:    checkCtx.Logger,
+		envConfig: checkCtx.EnvConfig,
+		namespace: checkCtx.Options.ProviderServiceAccountsNamespace(),
+		prefix:    awsjwtretriever.Prefix(checkCtx.Options),
+		clientset: checkCtx.Clientset,
 		iam:       iam,
+
+		allowExtraStatement: sidAllowlist(checkCtx.Options.AllowedExtraPolicyStatements),
 	}
 }
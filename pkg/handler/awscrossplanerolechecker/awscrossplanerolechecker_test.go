@@ -2,20 +2,39 @@
 package awscrossplanerolechecker
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/cloud"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/envconfig"
+	pkgerrors "github.com/AlphaSense-Engineering/privatecloud-cli/pkg/errors"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/handlertest"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/report"
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/charmbracelet/log"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
 )
 
 // setupAWSCrossplaneRoleCheckerTest is a function that sets up a awsCrossplaneRoleChecker for testing.
 func setupAWSCrossplaneRoleCheckerTest() *AWSCrossplaneRoleChecker {
 	return &AWSCrossplaneRoleChecker{
+		namespace: "crossplane",
+		prefix:    "aws-",
 		envConfig: &envconfig.EnvConfig{
 			Spec: envconfig.Spec{
 				ClusterName: "test",
 				CloudSpec: envconfig.CloudSpec{
+					Provider: string(cloud.AWS),
 					AWS: &envconfig.AWSSpec{
 						AccountID: "1234567890",
 						OIDCURL:   "oidc.eks.us-west-2.amazonaws.com/id/1234567890",
@@ -828,3 +847,665 @@ func Test_validatePolicyDocument(t *testing.T) {
 		})
 	}
 }
+
+// Test_validatePolicyDocument_subject is a test that tests that the subject condition of the expected assume role policy document follows the namespace
+// and the prefix of the service accounts of the Crossplane providers of the options, defaulting to the crossplane namespace and the aws- prefix.
+func Test_validatePolicyDocument_subject(t *testing.T) {
+	document := func(subject string) rolePolicyDocument {
+		return rolePolicyDocument{
+			Version: aws.String("2012-10-17"),
+			Statement: []*rolePolicyStatement{
+				{
+					Effect: aws.String("Allow"),
+					Principal: &rolePolicyPrincipal{
+						Federated: aws.String("arn:aws:iam::1234567890:oidc-provider/oidc.eks.us-west-2.amazonaws.com/id/1234567890"),
+					},
+					Action: &[]*string{aws.String("sts:AssumeRoleWithWebIdentity")},
+					Condition: &rolePolicyCondition{
+						StringLike: &map[string]*string{"oidc.eks.us-west-2.amazonaws.com/id/1234567890:sub": aws.String(subject)},
+					},
+				},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		options  handler.CheckOptions
+		subject  string
+		expected bool
+	}{
+		{name: "Default", subject: "system:serviceaccount:crossplane:aws-*", expected: true},
+		{
+			name:     "Custom namespace and prefix",
+			options:  handler.CheckOptions{CrossplaneNamespace: "crossplane-system", CrossplaneServiceAccountsPrefix: "provider-aws-"},
+			subject:  "system:serviceaccount:crossplane-system:provider-aws-*",
+			expected: true,
+		},
+		{
+			name:    "Custom namespace only",
+			options: handler.CheckOptions{CrossplaneNamespace: "crossplane-system"},
+			subject: "system:serviceaccount:crossplane:aws-*",
+		},
+		{
+			name:    "Default against custom",
+			subject: "system:serviceaccount:crossplane-system:provider-aws-*",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := New(handler.CheckContext{EnvConfig: setupAWSCrossplaneRoleCheckerTest().envConfig, Options: tc.options}, nil)
+
+			result := c.validatePolicyDocument(document(tc.subject), constExpectedAssumeRolePolicyDocument)
+
+			assert.Equal(t, tc.expected, len(result) == 0, "%#v", result)
+		})
+	}
+
+	assert.Equal(
+		t,
+		"system:serviceaccount:${CROSSPLANE_NAMESPACE}:${SERVICE_ACCOUNTS_PREFIX}*",
+		*(*constExpectedAssumeRolePolicyDocument.Statement[0].Condition.StringLike)["${OIDC_ID}:sub"],
+		"the expected document is not supposed to be modified",
+	)
+}
+
+// Test_validatePolicyDocument_allowedExtraStatements is a test that tests that the extra statements of the policy document are ignored only if their SIDs
+// are allowed, and that allowing the SID of an expected statement does not hide a change of it.
+func Test_validatePolicyDocument_allowedExtraStatements(t *testing.T) {
+	// expectedStatement is the expected statement of the policy document used in the test.
+	expectedStatement := &rolePolicyStatement{
+		Action:   &[]*string{aws.String("iam:CreateServiceLinkedRole")},
+		Effect:   aws.String("Allow"),
+		Resource: aws.String("*"),
+		SID:      aws.String("AllowIAMForServiceLinkedRoles"),
+	}
+
+	expectedDocument := rolePolicyDocument{Version: aws.String("2012-10-17"), Statement: []*rolePolicyStatement{expectedStatement}}
+
+	// taggingStatement is the extra statement that the customer adds to the policy document in the test.
+	taggingStatement := &rolePolicyStatement{
+		Action:   &[]*string{aws.String("tag:TagResources"), aws.String("tag:UntagResources")},
+		Effect:   aws.String("Allow"),
+		Resource: aws.String("*"),
+		SID:      aws.String("AllowTagging"),
+	}
+
+	testCases := []struct {
+		name       string
+		allowed    []string
+		statements []*rolePolicyStatement
+		expected   bool
+	}{
+		{name: "No extra statement", statements: []*rolePolicyStatement{expectedStatement}, expected: true},
+		{name: "Extra statement without allowlist", statements: []*rolePolicyStatement{taggingStatement, expectedStatement}},
+		{
+			name:       "Allowlisted extra statement",
+			allowed:    []string{"AllowTagging"},
+			statements: []*rolePolicyStatement{taggingStatement, expectedStatement},
+			expected:   true,
+		},
+		{
+			name:       "Extra statement not allowlisted",
+			allowed:    []string{"AllowKMS"},
+			statements: []*rolePolicyStatement{expectedStatement, taggingStatement},
+		},
+		{
+			name:    "Allowlisted expected statement changed",
+			allowed: []string{"AllowIAMForServiceLinkedRoles"},
+			statements: []*rolePolicyStatement{{
+				Action:   &[]*string{aws.String("iam:CreateServiceLinkedRole")},
+				Effect:   aws.String("Allow"),
+				Resource: aws.String("arn:aws:iam::1234567890:role/other"),
+				SID:      aws.String("AllowIAMForServiceLinkedRoles"),
+			}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := New(handler.CheckContext{
+				EnvConfig: setupAWSCrossplaneRoleCheckerTest().envConfig,
+				Options:   handler.CheckOptions{AllowedExtraPolicyStatements: tc.allowed},
+			}, nil)
+
+			document := rolePolicyDocument{Version: aws.String("2012-10-17"), Statement: tc.statements}
+
+			result := c.validatePolicyDocument(document, expectedDocument)
+
+			assert.Equal(t, tc.expected, len(result) == 0, "%#v", result)
+			assert.Len(t, document.Statement, len(tc.statements), "the document is not supposed to be modified")
+		})
+	}
+}
+
+// Test_untrustedServiceAccounts is a test that tests that the untrustedServiceAccounts function returns the service accounts whose subjects the assume
+// role policy document does not trust.
+func Test_untrustedServiceAccounts(t *testing.T) {
+	// subjectKey is the key of the subject condition used in the test.
+	const subjectKey = "oidc.eks.us-west-2.amazonaws.com/id/1234567890:sub"
+
+	document := func(condition *rolePolicyCondition) rolePolicyDocument {
+		return rolePolicyDocument{
+			Version: aws.String("2012-10-17"),
+			Statement: []*rolePolicyStatement{
+				{
+					Effect:    aws.String("Allow"),
+					Action:    &[]*string{aws.String("sts:AssumeRoleWithWebIdentity")},
+					Condition: condition,
+				},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		document rolePolicyDocument
+		names    []string
+		want     []string
+	}{
+		{
+			name:     "Matching",
+			document: document(&rolePolicyCondition{StringLike: &map[string]*string{subjectKey: aws.String("system:serviceaccount:crossplane:aws-*")}}),
+			names:    []string{"aws-provider-s3", "aws-provider-rds"},
+		},
+		{
+			name:     "Non-matching",
+			document: document(&rolePolicyCondition{StringLike: &map[string]*string{subjectKey: aws.String("system:serviceaccount:crossplane:aws-provider-*")}}),
+			names:    []string{"aws-provider-s3", "aws-s3"},
+			want:     []string{"aws-s3"},
+		},
+		{
+			name:     "Single character wildcard",
+			document: document(&rolePolicyCondition{StringLike: &map[string]*string{subjectKey: aws.String("system:serviceaccount:crossplane:aws-s?")}}),
+			names:    []string{"aws-s3", "aws-s33"},
+			want:     []string{"aws-s33"},
+		},
+		{
+			name:     "Other namespace",
+			document: document(&rolePolicyCondition{StringLike: &map[string]*string{subjectKey: aws.String("system:serviceaccount:platform:aws-*")}}),
+			names:    []string{"aws-provider-s3"},
+			want:     []string{"aws-provider-s3"},
+		},
+		{
+			name: "String equals",
+			document: document(&rolePolicyCondition{
+				StringLike:   &map[string]*string{subjectKey: aws.String("system:serviceaccount:crossplane:aws-*")},
+				StringEquals: &map[string]*string{subjectKey: aws.String("system:serviceaccount:crossplane:aws-provider-s3")},
+			}),
+			names: []string{"aws-provider-s3", "aws-provider-rds"},
+			want:  []string{"aws-provider-rds"},
+		},
+		{
+			name:     "No condition",
+			document: document(nil),
+			names:    []string{"aws-provider-s3"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, untrustedServiceAccounts(tc.document, "crossplane", tc.names))
+		})
+	}
+}
+
+// TestAWSCrossplaneRoleChecker_checkServiceAccountsTrust is a test that tests that the checkServiceAccountsTrust function warns about the AWS service
+// accounts in the crossplane namespace that the assume role policy document does not trust, and only about them.
+func TestAWSCrossplaneRoleChecker_checkServiceAccountsTrust(t *testing.T) {
+	serviceAccount := func(namespace string, name string) *corev1.ServiceAccount {
+		return &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+	}
+
+	var buf bytes.Buffer
+
+	c := setupAWSCrossplaneRoleCheckerTest()
+	c.logger = log.New(&buf)
+	c.clientset = fake.NewClientset(
+		serviceAccount("crossplane", "aws-provider-s3"),
+		serviceAccount("crossplane", "aws-s3"),
+		serviceAccount("crossplane", "crossplane"),
+		serviceAccount("platform", "aws-other"),
+	)
+
+	document := rolePolicyDocument{
+		Statement: []*rolePolicyStatement{
+			{
+				Effect: aws.String("Allow"),
+				Condition: &rolePolicyCondition{
+					StringLike: &map[string]*string{
+						"oidc.eks.us-west-2.amazonaws.com/id/1234567890:sub": aws.String("system:serviceaccount:crossplane:aws-provider-*"),
+					},
+				},
+			},
+		},
+	}
+
+	require.NoError(t, c.checkServiceAccountsTrust(context.Background(), document))
+
+	assert.Contains(t, buf.String(), "crossplane/aws-s3")
+	assert.NotContains(t, buf.String(), "aws-provider-s3")
+	assert.NotContains(t, buf.String(), "aws-other")
+	assert.NotContains(t, buf.String(), "crossplane/crossplane")
+}
+
+// Test_overridingDenies is a test that tests that the overridingDenies function returns the Deny statements of the policy document that deny the actions
+// that the Allow statements of the expected policy documents allow on the same resources, and only them.
+func Test_overridingDenies(t *testing.T) {
+	// bucketsARN is the ARN of the buckets used in the test.
+	const bucketsARN = "arn:aws:s3:::test-*"
+
+	expectedDocuments := []rolePolicyDocument{
+		{
+			Statement: []*rolePolicyStatement{
+				{
+					Effect:   aws.String("Deny"),
+					Action:   &[]*string{aws.String("s3:DeleteBucket")},
+					Resource: aws.String(bucketsARN),
+					SID:      aws.String("DenyDeletingBuckets"),
+				},
+				{
+					Effect:   aws.String("Allow"),
+					Action:   &[]*string{aws.String("s3:CreateBucket"), aws.String("s3:Get*"), aws.String("s3:DeleteBucket")},
+					Resource: aws.String(bucketsARN),
+					SID:      aws.String("AllowBuckets"),
+				},
+				{
+					Effect:   aws.String("Allow"),
+					Action:   &[]*string{aws.String("sts:GetCallerIdentity")},
+					Resource: aws.String("*"),
+					SID:      aws.String("AllowCallerIdentity"),
+				},
+			},
+		},
+		{
+			Statement: []*rolePolicyStatement{
+				{
+					Effect:   aws.String("Deny"),
+					Action:   &[]*string{aws.String("ec2:DeleteVpc")},
+					Resource: aws.String("*"),
+					SID:      aws.String("DenyDeletingVPCs"),
+				},
+				{
+					Effect:   aws.String("Allow"),
+					Action:   &[]*string{aws.String("ec2:DescribeRegions")},
+					Resource: aws.String("*"),
+				},
+			},
+		},
+	}
+
+	document := func(statements ...*rolePolicyStatement) rolePolicyDocument {
+		return rolePolicyDocument{Version: aws.String("2012-10-17"), Statement: statements}
+	}
+
+	testCases := []struct {
+		name     string
+		document rolePolicyDocument
+		want     []overridingDeny
+	}{
+		{
+			name:     "Expected",
+			document: expectedDocuments[0],
+		},
+		{
+			name: "Overriding",
+			document: document(&rolePolicyStatement{
+				Effect:   aws.String("Deny"),
+				Action:   &[]*string{aws.String("S3:*")},
+				Resource: aws.String("*"),
+				SID:      aws.String("DenyS3"),
+			}),
+			want: []overridingDeny{{Deny: "DenyS3", Allow: "AllowBuckets", Actions: []string{"s3:CreateBucket", "s3:Get*", "s3:DeleteBucket"}}},
+		},
+		{
+			name: "Other resource",
+			document: document(&rolePolicyStatement{
+				Effect:   aws.String("Deny"),
+				Action:   &[]*string{aws.String("s3:*")},
+				Resource: aws.String("arn:aws:s3:::other"),
+			}),
+		},
+		{
+			name: "Other action",
+			document: document(&rolePolicyStatement{
+				Effect:   aws.String("Deny"),
+				Action:   &[]*string{aws.String("s3:PutObject")},
+				Resource: aws.String("*"),
+			}),
+		},
+		{
+			name: "Expected Deny with extra action",
+			document: document(&rolePolicyStatement{
+				Effect:   aws.String("Deny"),
+				Action:   &[]*string{aws.String("s3:DeleteBucket"), aws.String("s3:GetObject")},
+				Resource: aws.String(bucketsARN),
+				SID:      aws.String("DenyDeletingBuckets"),
+			}),
+			want: []overridingDeny{{Deny: "DenyDeletingBuckets", Allow: "AllowBuckets", Actions: []string{"s3:Get*"}}},
+		},
+		{
+			name: "NotAction",
+			document: document(&rolePolicyStatement{
+				Effect:    aws.String("Deny"),
+				NotAction: &[]*string{aws.String("s3:*")},
+			}),
+			want: []overridingDeny{
+				{Deny: "#0", Allow: "AllowCallerIdentity", Actions: []string{"sts:GetCallerIdentity"}},
+				// The Allow statement without a SID is named after its index in its own policy document.
+				{Deny: "#0", Allow: "#1", Actions: []string{"ec2:DescribeRegions"}},
+			},
+		},
+		{
+			name: "Conditional",
+			document: document(&rolePolicyStatement{
+				Effect:    aws.String("Deny"),
+				Action:    &[]*string{aws.String("sts:GetCallerIdentity")},
+				Resource:  aws.String("*"),
+				SID:       aws.String("DenyOutsideVPC"),
+				Condition: &rolePolicyCondition{StringEquals: &map[string]*string{"aws:SourceVpc": aws.String("vpc-1")}},
+			}),
+			want: []overridingDeny{{Deny: "DenyOutsideVPC", Allow: "AllowCallerIdentity", Actions: []string{"sts:GetCallerIdentity"}, Conditional: true}},
+		},
+		{
+			name: "Allow",
+			document: document(&rolePolicyStatement{
+				Effect:   aws.String("Allow"),
+				Action:   &[]*string{aws.String("*")},
+				Resource: aws.String("*"),
+			}),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, overridingDenies(tc.document, expectedDocuments))
+		})
+	}
+}
+
+// TestAWSCrossplaneRoleChecker_checkOverridingDenies is a test that tests that the checkOverridingDenies function warns about the Deny statements of the
+// policy that override the Allow statements of the expected policy documents, telling the conditional ones apart, and not about the Deny statements that the
+// expected policy documents have.
+func TestAWSCrossplaneRoleChecker_checkOverridingDenies(t *testing.T) {
+	// policyARN is the ARN of the policy used in the test.
+	const policyARN = "arn:aws:iam::1234567890:policy/custom"
+
+	var buf bytes.Buffer
+
+	c := setupAWSCrossplaneRoleCheckerTest()
+	c.logger = log.New(&buf)
+
+	for _, expectedDocument := range constExpectedPolicyDocuments {
+		c.checkOverridingDenies(policyARN, c.fillPolicyDocument(expectedDocument))
+	}
+
+	assert.Empty(t, buf.String())
+
+	c.checkOverridingDenies(policyARN, rolePolicyDocument{
+		Statement: []*rolePolicyStatement{
+			{
+				Effect:   aws.String("Deny"),
+				Action:   &[]*string{aws.String("sts:*")},
+				Resource: aws.String("*"),
+				SID:      aws.String("DenySTS"),
+			},
+		},
+	})
+
+	assert.Contains(t, buf.String(), "statement DenySTS of "+policyARN+" policy denies sts:GetCallerIdentity")
+	assert.Contains(t, buf.String(), "AllowCallSTSToGetCurrentIdentity")
+
+	buf.Reset()
+
+	c.checkOverridingDenies(policyARN, rolePolicyDocument{
+		Statement: []*rolePolicyStatement{
+			{
+				Effect:    aws.String("Deny"),
+				Action:    &[]*string{aws.String("sts:*")},
+				Resource:  aws.String("*"),
+				SID:       aws.String("DenySTSOutsideVPC"),
+				Condition: &rolePolicyCondition{StringEquals: &map[string]*string{"aws:SourceVpc": aws.String("vpc-1")}},
+			},
+		},
+	})
+
+	assert.Contains(t, buf.String(), "statement DenySTSOutsideVPC of "+policyARN+" policy conditionally denies sts:GetCallerIdentity")
+}
+
+// Test_validatePolicyDocument_report is a test that tests that the changelog of a mismatch of the policy documents is included in the JSON report.
+func Test_validatePolicyDocument_report(t *testing.T) {
+	document := rolePolicyDocument{
+		Version: aws.String("2012-10-17"),
+		Statement: []*rolePolicyStatement{
+			{
+				Effect: aws.String("Allow"),
+				Principal: &rolePolicyPrincipal{
+					Federated: aws.String("arn:aws:iam::1234567890:oidc-provider/oidc.eks.us-west-2.amazonaws.com/id/1234567890"),
+				},
+				Action: &[]*string{aws.String("sts:AssumeRoleWithWebIdentity")},
+				Condition: &rolePolicyCondition{
+					StringLike: &map[string]*string{"oidc.eks.us-west-2.amazonaws.com/id/1234567890:sub": aws.String("system:serviceaccount:default:aws-*")},
+				},
+			},
+		},
+	}
+
+	c := setupAWSCrossplaneRoleCheckerTest()
+
+	changelog := c.validatePolicyDocument(document, constExpectedAssumeRolePolicyDocument)
+	require.NotEmpty(t, changelog)
+
+	var buf bytes.Buffer
+
+	w := report.NewStreamWriter(&buf)
+
+	require.NoError(t, w.Write(report.NewFailed("AWS Crossplane role", "test", pkgerrors.NewErrWithChangelog(errAssumeRolePolicyDocumentMismatch, changelog))))
+
+	var got struct {
+		Changes []map[string]any `json:"changes"`
+	}
+
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+
+	assert.Equal(t, []map[string]any{
+		{
+			"type": "update",
+			"path": []any{"Statement", "0", "Condition", "StringLike", "oidc.eks.us-west-2.amazonaws.com/id/1234567890:sub"},
+			"from": "system:serviceaccount:crossplane:aws-*",
+			"to":   "system:serviceaccount:default:aws-*",
+		},
+	}, got.Changes)
+}
+
+// TestAWSCrossplaneRoleChecker_Handle_maxSessionDuration is a test that tests that the Handle function warns about the maximum session duration of the role
+// that a stubbed IAM returns, if it is shorter than the recommended one.
+func TestAWSCrossplaneRoleChecker_Handle_maxSessionDuration(t *testing.T) {
+	testCases := []struct {
+		name               string
+		maxSessionDuration string
+		want               string
+		wantWarn           bool
+	}{
+		{
+			name:     "Default",
+			want:     "maximum session duration of crossplane-provider-test role is 1h0m0s, shorter than the recommended 2h0m0s",
+			wantWarn: true,
+		},
+		{
+			name:               "Short",
+			maxSessionDuration: "<MaxSessionDuration>5400</MaxSessionDuration>",
+			want:               "maximum session duration of crossplane-provider-test role is 1h30m0s, shorter than the recommended 2h0m0s",
+			wantWarn:           true,
+		},
+		{
+			name:               "Long enough",
+			maxSessionDuration: "<MaxSessionDuration>43200</MaxSessionDuration>",
+			want:               "maximum session duration of crossplane-provider-test role is 12h0m0s",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// The role has no assume role policy document, so that the check stops right after getting it.
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.NoError(t, r.ParseForm())
+				assert.Equal(t, "GetRole", r.PostForm.Get("Action"))
+				assert.Equal(t, "crossplane-provider-test", r.PostForm.Get("RoleName"))
+
+				w.Header().Set("Content-Type", "text/xml")
+
+				_, _ = w.Write([]byte(`<GetRoleResponse xmlns="https://iam.amazonaws.com/doc/2010-05-08/"><GetRoleResult><Role>` +
+					`<RoleName>crossplane-provider-test</RoleName>` + tc.maxSessionDuration + `</Role></GetRoleResult></GetRoleResponse>`))
+			}))
+			defer server.Close()
+
+			var buf bytes.Buffer
+
+			c := setupAWSCrossplaneRoleCheckerTest()
+			c.logger = log.New(&buf)
+			c.iam = iam.New(iam.Options{
+				Region:       "us-east-1",
+				BaseEndpoint: aws.String(server.URL),
+				Credentials:  aws.AnonymousCredentials{},
+				HTTPClient:   server.Client(),
+			})
+
+			results, err := c.Handle(context.Background())
+			handlertest.AssertContract(t, c, results, err)
+			require.ErrorIs(t, err, errNoAssumeRolePolicyDocument)
+
+			assert.Contains(t, buf.String(), tc.want)
+			assert.Equal(t, tc.wantWarn, bytes.Contains(buf.Bytes(), []byte("WARN")))
+		})
+	}
+}
+
+// testRolePolicyStatementRegressions is the list of the statements that the unmarshaller used to accept by dropping the actions it could not read.
+//
+// Do not modify this variable, it is supposed to be constant.
+var testRolePolicyStatementRegressions = []string{
+	`{"Effect": "Allow", "Action": null}`,
+	`{"Effect": "Allow", "Action": 0}`,
+	`{"Effect": "Allow", "Action": {"s3:GetObject": true}}`,
+	`{"Effect": "Allow", "Action": ["s3:GetObject", 1]}`,
+	`{"Effect": "Allow", "Action": ["s3:GetObject", null]}`,
+	`{"Effect": "Allow", "Action": [["s3:GetObject"]]}`,
+	`{"Effect": "Deny", "NotAction": true}`,
+	`{"Effect": "Deny", "NotAction": ["iam:*", {}]}`,
+}
+
+// Test_rolePolicyStatement_UnmarshalJSON is a test that tests that the UnmarshalJSON function reads the actions and the not actions of the statement as a
+// string or an array of strings, and rejects the statements with any other actions.
+func Test_rolePolicyStatement_UnmarshalJSON(t *testing.T) {
+	testCases := []struct {
+		name          string
+		data          string
+		wantAction    *[]*string
+		wantNotAction *[]*string
+	}{
+		{
+			name: "No actions",
+			data: `{"Effect": "Allow"}`,
+		},
+		{
+			name:       "String action",
+			data:       `{"Effect": "Allow", "Action": "s3:GetObject"}`,
+			wantAction: &[]*string{aws.String("s3:GetObject")},
+		},
+		{
+			name:       "Array of actions",
+			data:       `{"Effect": "Allow", "Action": ["s3:GetObject", "s3:PutObject"]}`,
+			wantAction: &[]*string{aws.String("s3:GetObject"), aws.String("s3:PutObject")},
+		},
+		{
+			name:       "Empty array of actions",
+			data:       `{"Effect": "Allow", "Action": []}`,
+			wantAction: &[]*string{},
+		},
+		{
+			name:          "String not action",
+			data:          `{"Effect": "Deny", "NotAction": "iam:*"}`,
+			wantNotAction: &[]*string{aws.String("iam:*")},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got rolePolicyStatement
+
+			require.NoError(t, json.Unmarshal([]byte(tc.data), &got))
+
+			assert.Equal(t, tc.wantAction, got.Action)
+			assert.Equal(t, tc.wantNotAction, got.NotAction)
+		})
+	}
+
+	for _, data := range testRolePolicyStatementRegressions {
+		t.Run(data, func(t *testing.T) {
+			var got rolePolicyStatement
+
+			require.ErrorIs(t, json.Unmarshal([]byte(data), &got), errInvalidPolicyStatementActions)
+		})
+	}
+}
+
+// FuzzRolePolicyStatement_UnmarshalJSON is a fuzz test that tests that the UnmarshalJSON function either rejects the statement, or reads all of its actions
+// and not actions, so that they survive the round trip through the MarshalJSON function.
+func FuzzRolePolicyStatement_UnmarshalJSON(f *testing.F) {
+	f.Add(`{"Effect": "Allow", "Action": "s3:GetObject"}`)
+	f.Add(`{"Effect": "Allow", "Action": ["s3:GetObject", "s3:PutObject"], "Resource": "*"}`)
+	f.Add(`{"Effect": "Deny", "NotAction": ["iam:*"]}`)
+	f.Add(`{"Effect": "Allow", "Action": []}`)
+
+	for _, data := range testRolePolicyStatementRegressions {
+		f.Add(data)
+	}
+
+	f.Fuzz(func(t *testing.T, data string) {
+		var got rolePolicyStatement
+
+		if err := json.Unmarshal([]byte(data), &got); err != nil {
+			return
+		}
+
+		// raw is the statement decoded without the custom unmarshaller, matching the keys the same way as it does, e.g. case-insensitively.
+		var raw struct {
+			Action    any
+			NotAction any
+		}
+
+		require.NoError(t, json.Unmarshal([]byte(data), &raw))
+
+		for key, tc := range map[string]struct {
+			raw     any
+			actions *[]*string
+		}{"Action": {raw.Action, got.Action}, "NotAction": {raw.NotAction, got.NotAction}} {
+			actions := tc.actions
+
+			switch v := tc.raw.(type) {
+			case nil:
+				assert.Nil(t, actions, key)
+			case string:
+				require.NotNil(t, actions, key)
+				assert.Equal(t, []*string{aws.String(v)}, *actions, key)
+			case []any:
+				require.NotNil(t, actions, key)
+				assert.Len(t, *actions, len(v), key)
+			default:
+				t.Fatalf("%s of %T accepted", key, v)
+			}
+		}
+
+		marshalled, err := json.Marshal(&got)
+		require.NoError(t, err)
+
+		var roundTripped rolePolicyStatement
+
+		require.NoError(t, json.Unmarshal(marshalled, &roundTripped))
+
+		assert.Equal(t, got.Action, roundTripped.Action, "Action")
+		assert.Equal(t, got.NotAction, roundTripped.NotAction, "NotAction")
+	})
+}
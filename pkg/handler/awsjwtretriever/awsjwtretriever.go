@@ -3,21 +3,40 @@ package awsjwtretriever
 
 import (
 	"context"
+	"reflect"
 	"strings"
+	"time"
 
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
+	pkgerrors "github.com/AlphaSense-Engineering/privatecloud-cli/pkg/errors"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/jwtretriever"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/util"
+	"github.com/charmbracelet/log"
+	"go.uber.org/multierr"
 	authenticationv1 "k8s.io/api/authentication/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
+const (
+	// ServiceAccountsPrefix is the prefix of the service accounts in AWS configuration.
+	ServiceAccountsPrefix = "aws-"
+
+	// Audience is the audience of the AWS JWTs.
+	Audience = "amazonaws.com"
+)
+
 // AWSJWTRetriever is the JWT retriever for AWS.
 type AWSJWTRetriever struct {
+	// logger is the logger.
+	logger *log.Logger
 	// clientset is the Kubernetes client.
 	clientset kubernetes.Interface
+	// namespace is the namespace of the service accounts of the Crossplane providers.
+	namespace string
+	// prefix is the prefix of the names of the service accounts of the Crossplane providers.
+	prefix string
 }
 
 var _ handler.Handler = &AWSJWTRetriever{}
@@ -26,50 +45,83 @@ var _ handler.Handler = &AWSJWTRetriever{}
 //
 // The arguments are not used.
 // It returns a slice of JWTs on success, or an error on failure.
+// If the JWTs are retrieved for some of the service accounts only, it returns them along with a *pkgerrors.JWTsPartiallyRetrieved error.
 func (c *AWSJWTRetriever) Handle(ctx context.Context, _ ...any) (jwts []any, err error) {
-	const (
-		// serviceAccountsPrefix is the prefix of the service accounts in AWS configuration.
-		serviceAccountsPrefix = "aws-"
-
-		// audience is the audience of the AWS JWTs.
-		audience = "amazonaws.com"
-	)
-
-	clientsetSA := c.clientset.CoreV1().ServiceAccounts(constant.NamespaceCrossplane)
+	clientsetSA := c.clientset.CoreV1().ServiceAccounts(c.namespace)
 
 	serviceAccounts, err := clientsetSA.List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return nil, err
 	}
 
+	var (
+		failedServiceAccounts []string
+		errs                  []error
+	)
+
 	for _, sa := range serviceAccounts.Items {
-		if !strings.HasPrefix(sa.Name, serviceAccountsPrefix) {
+		if !strings.HasPrefix(sa.Name, c.prefix) {
 			continue
 		}
 
 		req, err := clientsetSA.CreateToken(ctx, sa.Name, &authenticationv1.TokenRequest{
 			Spec: authenticationv1.TokenRequestSpec{
-				Audiences:         []string{audience},
+				Audiences:         []string{Audience},
 				ExpirationSeconds: util.Ref(jwtretriever.TokenExpirationSeconds),
 			},
 		}, metav1.CreateOptions{})
 		if err != nil {
-			return nil, err
+			failedServiceAccounts = append(failedServiceAccounts, sa.Name)
+
+			errs = append(errs, err)
+
+			continue
 		}
 
 		if req.Status.Token != constant.EmptyString {
+			jwtretriever.WarnIfLifetimeClamped(c.logger, sa.Name, req.Status.Token, time.Now())
+
 			jwts = append(jwts, &req.Status.Token)
 		}
 	}
 
+	if failedServiceAccounts != nil {
+		err = pkgerrors.NewJWTsPartiallyRetrieved(failedServiceAccounts, errs)
+	}
+
 	if jwts == nil {
-		err = jwtretriever.ErrNoJWTsRetrieved
+		err = multierr.Combine(jwtretriever.ErrNoJWTsRetrieved, err)
 	}
 
 	return jwts, err
 }
 
+// Contract is the function that returns the contract of the results of the JWT retrieval for AWS.
+//
+// It returns any number of the JWTs, along with the error if they are retrieved for some of the service accounts only.
+func (c *AWSJWTRetriever) Contract() handler.Contract {
+	return handler.Contract{Results: []reflect.Type{reflect.TypeFor[*string]()}, Variadic: true, PartialOnError: true}
+}
+
+// Prefix is the function that returns the prefix of the names of the service accounts of the Crossplane providers of the options, or ServiceAccountsPrefix
+// if it is empty.
+func Prefix(options handler.CheckOptions) string {
+	if options.CrossplaneServiceAccountsPrefix == constant.EmptyString {
+		return ServiceAccountsPrefix
+	}
+
+	return options.CrossplaneServiceAccountsPrefix
+}
+
 // New creates a new AWSJWTRetriever.
-func New(clientset kubernetes.Interface) *AWSJWTRetriever {
-	return &AWSJWTRetriever{clientset: clientset}
+//
+// The JWTs are retrieved for the service accounts in the namespace and with the prefix of the options, or else in the crossplane namespace with the aws-
+// prefix.
+func New(checkCtx handler.CheckContext) *AWSJWTRetriever {
+	return &AWSJWTRetriever{
+		logger:    checkCtx.Logger,
+		clientset: checkCtx.Clientset,
+		namespace: checkCtx.Options.ProviderServiceAccountsNamespace(),
+		prefix:    Prefix(checkCtx.Options),
+	}
 }
@@ -0,0 +1,161 @@
+// Package awsjwtretriever contains the JWT retriever for AWS.
+package awsjwtretriever
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
+	pkgerrors "github.com/AlphaSense-Engineering/privatecloud-cli/pkg/errors"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/handlertest"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/jwtretriever"
+	"github.com/charmbracelet/log"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// errCreateToken is the error that is returned by the fake clientset when the token cannot be created.
+var errCreateToken = errors.New("create token failed")
+
+// setupFakeClientset is a function that returns a fake clientset with the given service accounts in the crossplane namespace, where the token creation
+// fails for the service accounts in the failing list.
+func setupFakeClientset(serviceAccounts []string, failing []string) *fake.Clientset {
+	objects := make([]runtime.Object, len(serviceAccounts))
+
+	for i, name := range serviceAccounts {
+		objects[i] = &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: constant.NamespaceCrossplane}}
+	}
+
+	clientset := fake.NewClientset(objects...)
+
+	clientset.PrependReactor("create", "serviceaccounts", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "token" {
+			return false, nil, nil
+		}
+
+		name := action.(k8stesting.CreateActionImpl).Name
+
+		for _, f := range failing {
+			if f == name {
+				return true, nil, errCreateToken
+			}
+		}
+
+		return true, &authenticationv1.TokenRequest{Status: authenticationv1.TokenRequestStatus{Token: "token-" + name}}, nil
+	})
+
+	return clientset
+}
+
+// TestAWSJWTRetriever_Handle is a test that tests the Handle function of the AWSJWTRetriever.
+func TestAWSJWTRetriever_Handle(t *testing.T) {
+	testCases := []struct {
+		name            string
+		serviceAccounts []string
+		failing         []string
+		wantJWTs        []string
+		wantPartial     []string
+		wantNoJWTs      bool
+	}{
+		{
+			name:            "All service accounts succeed",
+			serviceAccounts: []string{"aws-a", "aws-b", "other"},
+			wantJWTs:        []string{"token-aws-a", "token-aws-b"},
+		},
+		{
+			name:            "One service account fails",
+			serviceAccounts: []string{"aws-a", "aws-b", "aws-c"},
+			failing:         []string{"aws-b"},
+			wantJWTs:        []string{"token-aws-a", "token-aws-c"},
+			wantPartial:     []string{"aws-b"},
+		},
+		{
+			name:            "All service accounts fail",
+			serviceAccounts: []string{"aws-a", "aws-b"},
+			failing:         []string{"aws-a", "aws-b"},
+			wantPartial:     []string{"aws-a", "aws-b"},
+			wantNoJWTs:      true,
+		},
+		{
+			name:            "No matching service accounts",
+			serviceAccounts: []string{"other"},
+			wantNoJWTs:      true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := New(handler.CheckContext{Logger: log.New(io.Discard), Clientset: setupFakeClientset(tc.serviceAccounts, tc.failing)})
+
+			jwts, err := c.Handle(context.Background())
+			handlertest.AssertContract(t, c, jwts, err)
+
+			got := make([]string, len(jwts))
+
+			for i, jwt := range jwts {
+				got[i] = *jwt.(*string)
+			}
+
+			if tc.wantJWTs != nil {
+				assert.Equal(t, tc.wantJWTs, got)
+			} else {
+				assert.Empty(t, got)
+			}
+
+			if tc.wantNoJWTs {
+				assert.ErrorIs(t, err, jwtretriever.ErrNoJWTsRetrieved)
+			} else {
+				assert.NotErrorIs(t, err, jwtretriever.ErrNoJWTsRetrieved)
+			}
+
+			if tc.wantPartial == nil {
+				if !tc.wantNoJWTs {
+					assert.NoError(t, err)
+				}
+
+				return
+			}
+
+			var partialErr *pkgerrors.JWTsPartiallyRetrieved
+
+			require.ErrorAs(t, err, &partialErr)
+			assert.Equal(t, tc.wantPartial, partialErr.ServiceAccounts())
+			assert.ErrorContains(t, err, errCreateToken.Error())
+		})
+	}
+}
+
+// TestAWSJWTRetriever_Handle_clampedLifetime is a test that tests that the Handle function of the AWSJWTRetriever warns when the API server issues a JWT
+// expiring earlier than requested.
+func TestAWSJWTRetriever_Handle_clampedLifetime(t *testing.T) {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(10 * time.Minute)),
+	}).SignedString([]byte("test-key"))
+	require.NoError(t, err)
+
+	clientset := fake.NewClientset(&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "aws-a", Namespace: constant.NamespaceCrossplane}})
+
+	clientset.PrependReactor("create", "serviceaccounts", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, &authenticationv1.TokenRequest{Status: authenticationv1.TokenRequestStatus{Token: token}}, nil
+	})
+
+	var buf bytes.Buffer
+
+	jwts, err := New(handler.CheckContext{Logger: log.New(&buf), Clientset: clientset}).Handle(context.Background())
+	require.NoError(t, err)
+
+	assert.Len(t, jwts, 1)
+	assert.Contains(t, buf.String(), "JWT of service account aws-a expires in")
+}
@@ -3,7 +3,6 @@ package azurechecker
 
 import (
 	"context"
-	"net/http"
 
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/envconfig"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler"
@@ -12,24 +11,23 @@ import (
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/crossplanerolechecker"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/jwtchecker"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/jwtretriever"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/providerconfigchecker"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/util"
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization"
 	"github.com/charmbracelet/log"
 	"go.uber.org/multierr"
-	"k8s.io/client-go/kubernetes"
 )
 
 // AzureChecker is the type that contains the infrastructure check functions for Azure.
 type AzureChecker struct {
+	// checkCtx is the check context, passed to the checkers it sets up.
+	checkCtx handler.CheckContext
+
 	// logger is the logger.
 	logger *log.Logger
 	// envConfig is the environment configuration.
 	envConfig *envconfig.EnvConfig
-	// clientset is the Kubernetes client.
-	clientset kubernetes.Interface
-	// httpClient is the HTTP client.
-	httpClient *http.Client
 	// jwksURI is the JWKS URI.
 	jwksURI *string
 
@@ -37,15 +35,19 @@ type AzureChecker struct {
 	jwtRetriever *azurejwtretriever.AzureJWTRetriever
 	// jwtChecker is the JWT checker.
 	jwtChecker *jwtchecker.JWTChecker
+	// providerConfigChecker is the Crossplane ProviderConfig checker.
+	providerConfigChecker *providerconfigchecker.ProviderConfigChecker
 }
 
 var _ handler.Handler = &AzureChecker{}
 
 // setup is the function that sets up the Azure checker.
 func (c *AzureChecker) setup() {
-	c.jwtRetriever = azurejwtretriever.New(c.clientset)
+	c.jwtRetriever = azurejwtretriever.New(c.checkCtx)
+
+	c.jwtChecker = jwtchecker.New(c.checkCtx, c.jwksURI, azurejwtretriever.Audience, util.Deref(util.DiscardErr(c.envConfig.Azure())).OIDCURL)
 
-	c.jwtChecker = jwtchecker.New(c.httpClient, c.jwksURI)
+	c.providerConfigChecker = providerconfigchecker.New(c.checkCtx)
 }
 
 // Handle is the function that handles the infrastructure check.
@@ -55,6 +57,11 @@ func (c *AzureChecker) setup() {
 //
 // nolint:funlen
 func (c *AzureChecker) Handle(ctx context.Context, _ ...any) ([]any, error) {
+	azureSpec, err := c.envConfig.Azure()
+	if err != nil {
+		return nil, err
+	}
+
 	jwts, err := util.ConvertSliceErr[any, *string](c.jwtRetriever.Handle(ctx))
 	if err != nil {
 		return nil, multierr.Combine(jwtretriever.ErrFailedToRetrieveJWTs, err)
@@ -72,8 +79,8 @@ func (c *AzureChecker) Handle(ctx context.Context, _ ...any) ([]any, error) {
 
 	err = func() error {
 		cred, err := azidentity.NewClientAssertionCredential(
-			c.envConfig.Spec.CloudSpec.Azure.TenantID,
-			c.envConfig.Spec.CloudSpec.Azure.ClientID,
+			azureSpec.TenantID,
+			azureSpec.ClientID,
 			func(context.Context) (string, error) {
 				return *jwt, nil
 			},
@@ -88,7 +95,7 @@ func (c *AzureChecker) Handle(ctx context.Context, _ ...any) ([]any, error) {
 			return err
 		}
 
-		crossplaneRoleChecker := azurecrossplanerolechecker.New(c.envConfig, roleDefClient)
+		crossplaneRoleChecker := azurecrossplanerolechecker.New(c.checkCtx, roleDefClient)
 
 		if _, err := crossplaneRoleChecker.Handle(ctx); err != nil {
 			return err
@@ -103,17 +110,30 @@ func (c *AzureChecker) Handle(ctx context.Context, _ ...any) ([]any, error) {
 
 	c.logger.Info(crossplanerolechecker.LogMsgCrossplaneRoleCheckedSuccessfully)
 
+	if _, err := c.providerConfigChecker.Handle(ctx); err != nil {
+		return nil, multierr.Combine(providerconfigchecker.ErrFailedToCheckProviderConfig, err)
+	}
+
+	c.logger.Info(providerconfigchecker.LogMsgProviderConfigCheckedSuccessfully)
+
 	return nil, nil
 }
 
+// Contract is the function that returns the contract of the results of the infrastructure check.
+//
+// It returns nothing.
+func (c *AzureChecker) Contract() handler.Contract {
+	return handler.Contract{}
+}
+
 // New is the function that creates a new AzureChecker.
-func New(logger *log.Logger, envConfig *envconfig.EnvConfig, clientset kubernetes.Interface, httpClient *http.Client, jwksURI *string) *AzureChecker {
+func New(checkCtx handler.CheckContext, jwksURI *string) *AzureChecker {
 	c := &AzureChecker{
-		logger:     logger,
-		envConfig:  envConfig,
-		clientset:  clientset,
-		httpClient: httpClient,
-		jwksURI:    jwksURI,
+		checkCtx: checkCtx,
+
+		logger:    checkCtx.Logger,
+		envConfig: checkCtx.EnvConfig,
+		jwksURI:   jwksURI,
 	}
 
 	c.setup()
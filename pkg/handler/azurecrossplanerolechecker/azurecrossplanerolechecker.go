@@ -3,6 +3,7 @@ package azurecrossplanerolechecker
 
 import (
 	"context"
+	_ "embed"
 	"errors"
 	"fmt"
 	"strings"
@@ -14,6 +15,7 @@ import (
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/util"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization"
+	"github.com/charmbracelet/log"
 )
 
 var (
@@ -24,102 +26,28 @@ var (
 	errDuplicatePermission = errors.New("duplicate permission")
 )
 
-// constExpectedRolePermissions are the expected permissions for the Crossplane role in Azure.
+// expectedRolePermissionsFile is the embedded list of the expected permissions for the Crossplane role in Azure.
+//
+//go:embed expectedpermissions.txt
+var expectedRolePermissionsFile []byte
+
+// constExpectedRolePermissions are the expected permissions for the Crossplane role in Azure, loaded from the embedded list.
 //
 // These are listed at https://developer.alpha-sense.com/enterprise/technical-requirements/azure.
 //
 // Do not modify this variable, it is supposed to be constant.
-var constExpectedRolePermissions = map[string]struct{}{
-	"Microsoft.Authorization/policies/audit/action":                                        {},
-	"Microsoft.Authorization/policies/auditIfNotExists/action":                             {},
-	"Microsoft.Authorization/roleAssignments/delete":                                       {},
-	"Microsoft.Authorization/roleAssignments/read":                                         {},
-	"Microsoft.Authorization/roleAssignments/write":                                        {},
-	"Microsoft.Authorization/roleDefinitions/delete":                                       {},
-	"Microsoft.Authorization/roleDefinitions/read":                                         {},
-	"Microsoft.Authorization/roleDefinitions/write":                                        {},
-	"Microsoft.Cache/redis/accessPolicies/delete":                                          {},
-	"Microsoft.Cache/redis/accessPolicies/read":                                            {},
-	"Microsoft.Cache/redis/accessPolicies/write":                                           {},
-	"Microsoft.Cache/redis/accessPolicyAssignments/delete":                                 {},
-	"Microsoft.Cache/redis/accessPolicyAssignments/read":                                   {},
-	"Microsoft.Cache/redis/accessPolicyAssignments/write":                                  {},
-	"Microsoft.Cache/redis/delete":                                                         {},
-	"Microsoft.Cache/redis/detectors/read":                                                 {},
-	"Microsoft.Cache/redis/eventGridFilters/delete":                                        {},
-	"Microsoft.Cache/redis/eventGridFilters/read":                                          {},
-	"Microsoft.Cache/redis/eventGridFilters/write":                                         {},
-	"Microsoft.Cache/redis/firewallRules/delete":                                           {},
-	"Microsoft.Cache/redis/firewallRules/read":                                             {},
-	"Microsoft.Cache/redis/firewallRules/write":                                            {},
-	"Microsoft.Cache/redis/linkedServers/delete":                                           {},
-	"Microsoft.Cache/redis/linkedServers/read":                                             {},
-	"Microsoft.Cache/redis/linkedServers/write":                                            {},
-	"Microsoft.Cache/redis/listKeys/action":                                                {},
-	"Microsoft.Cache/redis/metricDefinitions/read":                                         {},
-	"Microsoft.Cache/redis/patchSchedules/delete":                                          {},
-	"Microsoft.Cache/redis/patchSchedules/read":                                            {},
-	"Microsoft.Cache/redis/patchSchedules/write":                                           {},
-	"Microsoft.Cache/redis/PrivateEndpointConnectionsApproval/action":                      {},
-	"Microsoft.Cache/redis/privateEndpointConnectionProxies/delete":                        {},
-	"Microsoft.Cache/redis/privateEndpointConnectionProxies/read":                          {},
-	"Microsoft.Cache/redis/privateEndpointConnectionProxies/validate/action":               {},
-	"Microsoft.Cache/redis/privateEndpointConnectionProxies/write":                         {},
-	"Microsoft.Cache/redis/privateEndpointConnections/delete":                              {},
-	"Microsoft.Cache/redis/privateEndpointConnections/read":                                {},
-	"Microsoft.Cache/redis/privateEndpointConnections/write":                               {},
-	"Microsoft.Cache/redis/privateLinkResources/read":                                      {},
-	"Microsoft.Cache/redis/read":                                                           {},
-	"Microsoft.Cache/redis/write":                                                          {},
-	"Microsoft.ManagedIdentity/userAssignedIdentities/delete":                              {},
-	"Microsoft.ManagedIdentity/userAssignedIdentities/federatedIdentityCredentials/delete": {},
-	"Microsoft.ManagedIdentity/userAssignedIdentities/federatedIdentityCredentials/read":   {},
-	"Microsoft.ManagedIdentity/userAssignedIdentities/federatedIdentityCredentials/write":  {},
-	"Microsoft.ManagedIdentity/userAssignedIdentities/read":                                {},
-	"Microsoft.ManagedIdentity/userAssignedIdentities/write":                               {},
-	"Microsoft.Network/virtualNetworks/read":                                               {},
-	"Microsoft.Network/virtualNetworks/subnets/join/action":                                {},
-	"Microsoft.Network/virtualNetworks/subnets/joinViaServiceEndpoint/action":              {},
-	"Microsoft.Network/virtualNetworks/subnets/read":                                       {},
-	"Microsoft.ServiceBus/namespaces/Delete":                                               {},
-	"Microsoft.ServiceBus/namespaces/queues/Delete":                                        {},
-	"Microsoft.ServiceBus/namespaces/queues/read":                                          {},
-	"Microsoft.ServiceBus/namespaces/queues/write":                                         {},
-	"Microsoft.ServiceBus/namespaces/read":                                                 {},
-	"Microsoft.ServiceBus/namespaces/topics/Delete":                                        {},
-	"Microsoft.ServiceBus/namespaces/topics/read":                                          {},
-	"Microsoft.ServiceBus/namespaces/topics/subscriptions/Delete":                          {},
-	"Microsoft.ServiceBus/namespaces/topics/subscriptions/read":                            {},
-	"Microsoft.ServiceBus/namespaces/topics/subscriptions/rules/Delete":                    {},
-	"Microsoft.ServiceBus/namespaces/topics/subscriptions/rules/read":                      {},
-	"Microsoft.ServiceBus/namespaces/topics/subscriptions/rules/write":                     {},
-	"Microsoft.ServiceBus/namespaces/topics/subscriptions/write":                           {},
-	"Microsoft.ServiceBus/namespaces/topics/write":                                         {},
-	"Microsoft.ServiceBus/namespaces/write":                                                {},
-	"Microsoft.Storage/skus/read":                                                          {},
-	"Microsoft.Storage/storageAccounts/blobServices/containers/delete":                     {},
-	"Microsoft.Storage/storageAccounts/blobServices/containers/read":                       {},
-	"Microsoft.Storage/storageAccounts/blobServices/containers/write":                      {},
-	"Microsoft.Storage/storageAccounts/blobServices/generateUserDelegationKey/action":      {},
-	"Microsoft.Storage/storageAccounts/blobServices/read":                                  {},
-	"Microsoft.Storage/storageAccounts/blobServices/write":                                 {},
-	"Microsoft.Storage/storageAccounts/delete":                                             {},
-	"Microsoft.Storage/storageAccounts/fileServices/read":                                  {},
-	"Microsoft.Storage/storageAccounts/listkeys/action":                                    {},
-	"Microsoft.Storage/storageAccounts/managementPolicies/delete":                          {},
-	"Microsoft.Storage/storageAccounts/managementPolicies/read":                            {},
-	"Microsoft.Storage/storageAccounts/managementPolicies/write":                           {},
-	"Microsoft.Storage/storageAccounts/read":                                               {},
-	"Microsoft.Storage/storageAccounts/regeneratekey/action":                               {},
-	"Microsoft.Storage/storageAccounts/write":                                              {},
-}
+var constExpectedRolePermissions = util.ParsePermissions(expectedRolePermissionsFile)
 
 // AzureCrossplaneRoleChecker is the type that contains the check functions for Azure Crossplane role.
 type AzureCrossplaneRoleChecker struct {
+	// logger is the logger.
+	logger *log.Logger
 	// envConfig is the environment configuration.
 	envConfig *envconfig.EnvConfig
 	// roleDefClient is the Azure role definitions client.
 	roleDefClient *armauthorization.RoleDefinitionsClient
+	// expectedRolePermissions is the set of the expected permissions, with the override merged in.
+	expectedRolePermissions map[string]struct{}
 }
 
 var _ handler.Handler = &AzureCrossplaneRoleChecker{}
@@ -131,7 +59,12 @@ var _ handler.Handler = &AzureCrossplaneRoleChecker{}
 //
 // nolint:funlen
 func (c *AzureCrossplaneRoleChecker) Handle(ctx context.Context, _ ...any) ([]any, error) {
-	scope := fmt.Sprintf("subscriptions/%s/resourceGroups/%s", c.envConfig.Spec.CloudSpec.Azure.SubscriptionID, c.envConfig.Spec.CloudSpec.Azure.ResourceGroup)
+	azureSpec, err := c.envConfig.Azure()
+	if err != nil {
+		return nil, err
+	}
+
+	scope := fmt.Sprintf("subscriptions/%s/resourceGroups/%s", azureSpec.SubscriptionID, azureSpec.ResourceGroup)
 
 	listPager := c.roleDefClient.NewListPager(scope, nil)
 
@@ -182,7 +115,7 @@ func (c *AzureCrossplaneRoleChecker) Handle(ctx context.Context, _ ...any) ([]an
 		}
 	}
 
-	for k := range constExpectedRolePermissions {
+	for k := range c.expectedRolePermissions {
 		if _, ok := foundPermissions[k]; ok {
 			continue
 		}
@@ -197,10 +130,20 @@ func (c *AzureCrossplaneRoleChecker) Handle(ctx context.Context, _ ...any) ([]an
 	return nil, nil
 }
 
+// Contract is the function that returns the contract of the results of the Azure Crossplane role check.
+//
+// It returns nothing.
+func (c *AzureCrossplaneRoleChecker) Contract() handler.Contract {
+	return handler.Contract{}
+}
+
 // New is the function that creates a new AzureCrossplaneRoleChecker.
-func New(envConfig *envconfig.EnvConfig, roleDefClient *armauthorization.RoleDefinitionsClient) *AzureCrossplaneRoleChecker {
+func New(checkCtx handler.CheckContext, roleDefClient *armauthorization.RoleDefinitionsClient) *AzureCrossplaneRoleChecker {
 	return &AzureCrossplaneRoleChecker{
-		envConfig:     envConfig,
+		logger:        checkCtx.Logger,
+		envConfig:     checkCtx.EnvConfig,
 		roleDefClient: roleDefClient,
+
+		expectedRolePermissions: util.MergePermissions(constExpectedRolePermissions, checkCtx.Options.ExpectedPermissionsOverride),
 	}
 }
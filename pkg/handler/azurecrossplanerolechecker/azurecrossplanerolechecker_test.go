@@ -0,0 +1,45 @@
+// Package azurecrossplanerolechecker is the package that contains the check functions for Azure Crossplane role.
+package azurecrossplanerolechecker
+
+import (
+	"io"
+	"testing"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler"
+	"github.com/charmbracelet/log"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestConstExpectedRolePermissions is a test that tests that the embedded list of the expected permissions loads, without comments or duplicates.
+func TestConstExpectedRolePermissions(t *testing.T) {
+	assert.NotEmpty(t, constExpectedRolePermissions)
+	assert.Contains(t, constExpectedRolePermissions, "Microsoft.Authorization/roleAssignments/write")
+
+	seen := make(map[string]struct{}, len(constExpectedRolePermissions))
+
+	for _, permission := range constExpectedRolePermissions {
+		assert.NotContains(t, permission, "#")
+		assert.NotContains(t, seen, permission, "duplicate permission %s", permission)
+
+		seen[permission] = struct{}{}
+	}
+}
+
+// TestNew_expectedRolePermissionsOverride is a test that tests that the override of the expected permissions is merged with the embedded ones.
+func TestNew_expectedRolePermissionsOverride(t *testing.T) {
+	// added is the permission that the override adds.
+	const added = "Microsoft.Cache/redis/export/action"
+
+	// removed is the permission that the override removes.
+	const removed = "Microsoft.Storage/skus/read"
+
+	c := New(handler.CheckContext{
+		Logger:  log.New(io.Discard),
+		Options: handler.CheckOptions{ExpectedPermissionsOverride: []string{added, "-" + removed}},
+	}, nil)
+
+	assert.Len(t, c.expectedRolePermissions, len(constExpectedRolePermissions))
+	assert.Contains(t, c.expectedRolePermissions, added)
+	assert.NotContains(t, c.expectedRolePermissions, removed)
+	assert.Contains(t, c.expectedRolePermissions, "Microsoft.Authorization/roleAssignments/write")
+}
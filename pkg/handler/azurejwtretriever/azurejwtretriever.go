@@ -3,20 +3,30 @@ package azurejwtretriever
 
 import (
 	"context"
+	"reflect"
+	"time"
 
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/jwtretriever"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/util"
+	"github.com/charmbracelet/log"
 	authenticationv1 "k8s.io/api/authentication/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
+// Audience is the audience of the Azure JWTs.
+const Audience = "api://AzureADTokenExchange"
+
 // AzureJWTRetriever is the JWT retriever for Azure.
 type AzureJWTRetriever struct {
+	// logger is the logger.
+	logger *log.Logger
 	// clientset is the Kubernetes client.
 	clientset kubernetes.Interface
+	// namespace is the namespace of the service account of the Crossplane provider.
+	namespace string
 }
 
 var _ handler.Handler = &AzureJWTRetriever{}
@@ -26,16 +36,11 @@ var _ handler.Handler = &AzureJWTRetriever{}
 // The arguments are not used.
 // It returns a slice of JWTs on success, or an error on failure.
 func (c *AzureJWTRetriever) Handle(ctx context.Context, _ ...any) (jwts []any, err error) {
-	const (
-		// audience is the audience of the Azure JWTs.
-		audience = "api://AzureADTokenExchange"
-	)
-
-	clientsetSA := c.clientset.CoreV1().ServiceAccounts(constant.NamespaceCrossplane)
+	clientsetSA := c.clientset.CoreV1().ServiceAccounts(c.namespace)
 
 	req, err := clientsetSA.CreateToken(ctx, constant.ServiceAccountNameAzure, &authenticationv1.TokenRequest{
 		Spec: authenticationv1.TokenRequestSpec{
-			Audiences:         []string{audience},
+			Audiences:         []string{Audience},
 			ExpirationSeconds: util.Ref(jwtretriever.TokenExpirationSeconds),
 		},
 	}, metav1.CreateOptions{})
@@ -44,6 +49,8 @@ func (c *AzureJWTRetriever) Handle(ctx context.Context, _ ...any) (jwts []any, e
 	}
 
 	if req.Status.Token != constant.EmptyString {
+		jwtretriever.WarnIfLifetimeClamped(c.logger, constant.ServiceAccountNameAzure, req.Status.Token, time.Now())
+
 		jwts = append(jwts, &req.Status.Token)
 	}
 
@@ -54,7 +61,16 @@ func (c *AzureJWTRetriever) Handle(ctx context.Context, _ ...any) (jwts []any, e
 	return jwts, err
 }
 
+// Contract is the function that returns the contract of the results of the JWT retrieval for Azure.
+//
+// It returns any number of the JWTs.
+func (c *AzureJWTRetriever) Contract() handler.Contract {
+	return handler.Contract{Results: []reflect.Type{reflect.TypeFor[*string]()}, Variadic: true}
+}
+
 // New creates a new AzureJWTRetriever.
-func New(clientset kubernetes.Interface) *AzureJWTRetriever {
-	return &AzureJWTRetriever{clientset: clientset}
+//
+// The JWT is retrieved for the service account in the namespace of the options, or else in the crossplane namespace.
+func New(checkCtx handler.CheckContext) *AzureJWTRetriever {
+	return &AzureJWTRetriever{logger: checkCtx.Logger, clientset: checkCtx.Clientset, namespace: checkCtx.Options.ProviderServiceAccountsNamespace()}
 }
@@ -0,0 +1,165 @@
+// Package handler is the package that contains the handler interface.
+package handler
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"time"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/clock"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/cloud"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/envconfig"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/k8s/kubeutil"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/report"
+	"github.com/charmbracelet/log"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+// CheckOptions is the type that contains the configuration options of the checks.
+type CheckOptions struct {
+	// DBTLSConfig is the TLS configuration for the database connections, or nil to connect without TLS.
+	DBTLSConfig *tls.Config
+	// ConnectTimeout is the maximum duration of establishing a connection to the endpoints outside of the cluster: the databases, the SMTP server and the
+	// HTTPS endpoints.
+	ConnectTimeout time.Duration
+	// RootCAs is the pool of the CAs that the servers outside of the cluster that the checks connect to with TLS other than the databases, such as the SMTP
+	// server, are verified against, the ones of the system along with the ones of the CA bundle, or nil for the ones of the system.
+	RootCAs *x509.CertPool
+	// CheckSpiceDB is whether the capabilities that SpiceDB requires from the PostgreSQL user are checked.
+	CheckSpiceDB bool
+	// SpiceDBDatabase is the name of the database that SpiceDB uses, or empty for the default one.
+	SpiceDBDatabase string
+	// MySQLExpectedConfigOverride is the override of the expected system variables of the MySQL, merged over the default ones; the values starting with
+	// ">=" are the minimum acceptable ones, rather than the exact ones.
+	MySQLExpectedConfigOverride map[string]string
+	// PostgreSQLExpectedConfigOverride is the override of the expected settings of the PostgreSQL, merged over the default ones.
+	PostgreSQLExpectedConfigOverride map[string]string
+
+	// GoogleCloudSDKDockerRepo is the Docker repository for the Google Cloud SDK.
+	GoogleCloudSDKDockerRepo string
+	// GoogleCloudSDKDockerImage is the Docker image for the Google Cloud SDK.
+	GoogleCloudSDKDockerImage string
+	// PodSecurityProfile is the security profile of the pods that the checks create.
+	PodSecurityProfile string
+
+	// MaxClockSkew is the maximum difference between the clock of the pod and the one of the OIDC server that is not warned about, or zero to not compare
+	// them.
+	MaxClockSkew time.Duration
+	// CheckOIDCDiscovery is whether the discovery document of the OIDC server is checked to support the signing algorithm and the audiences of the JWTs.
+	CheckOIDCDiscovery bool
+	// OIDCAudiences is the audiences that the discovery document of the OIDC server is checked to support, or empty for the one of the JWTs of the cloud
+	// provider.
+	OIDCAudiences []string
+
+	// SkipNodeGroups is whether the node group check is skipped, as the deployment does not require GPU nodes.
+	SkipNodeGroups bool
+	// MaxConcurrency is the maximum number of the checks of the cloud that run at once, or 0 or 1 to run them one at a time.
+	MaxConcurrency int
+
+	// CheckStorageClassProvisioner is whether the provisioner of the default storage class is checked against the expected ones.
+	CheckStorageClassProvisioner bool
+	// StorageClassProvisioners is the provisioners expected of the default storage class, or empty for the ones of the cloud provider.
+	StorageClassProvisioners []string
+
+	// MySQLSecretName is the name of the secret that contains the MySQL credentials, or empty for the default one.
+	MySQLSecretName string
+	// PostgreSQLSecretName is the name of the secret that contains the PostgreSQL credentials, or empty for the default one.
+	PostgreSQLSecretName string
+	// SMTPSecretName is the name of the secret that contains the SMTP credentials, or empty for the default one.
+	SMTPSecretName string
+	// MySQLSecretKeys is the keys of the secret that contains the MySQL credentials that replace the default ones, by the default ones.
+	MySQLSecretKeys map[string]string
+	// PostgreSQLSecretKeys is the keys of the secret that contains the PostgreSQL credentials that replace the default ones, by the default ones.
+	PostgreSQLSecretKeys map[string]string
+	// SMTPSecretKeys is the keys of the secret that contains the SMTP credentials that replace the default ones, by the default ones.
+	SMTPSecretKeys map[string]string
+
+	// CheckSMTPConnection is whether the SMTP server is connected to, upgrading the connection with STARTTLS, and authenticated with.
+	CheckSMTPConnection bool
+
+	// SSOSecretNames is the names of the secrets that contain the SSO configurations, or empty for the single default one.
+	SSOSecretNames []string
+	// SSOSecretSelector is the label selector of the secrets that contain the SSO configurations, taking precedence over their names.
+	SSOSecretSelector string
+	// SSOSAMLKeys is the keys that the SAML SSO configurations are required to have besides the entity ID, e.g. saml-metadata and saml-cert, or empty for
+	// the entity ID only; if set, the metadata of the SAML SSO configurations that have any is also validated.
+	SSOSAMLKeys []string
+
+	// CrossplaneNamespace is the namespace of the service accounts of the Crossplane providers, which the Crossplane role trusts, or empty for the
+	// crossplane namespace.
+	CrossplaneNamespace string
+	// CrossplaneServiceAccountsPrefix is the prefix of the names of the service accounts of the Crossplane providers that the AWS Crossplane role trusts, or
+	// empty for the aws- prefix.
+	CrossplaneServiceAccountsPrefix string
+	// AllowedExtraPolicyStatements is the SIDs of the statements that the policy documents of the AWS Crossplane role may have on top of the expected
+	// ones, or empty to allow none.
+	AllowedExtraPolicyStatements []string
+
+	// ExpectedPermissionsOverride is the override of the expected permissions of the Crossplane role in Azure and GCP, merged with the embedded ones;
+	// the permissions starting with '-' are removed from them.
+	ExpectedPermissionsOverride []string
+
+	// NamespacePrefix is the prefix of the namespaces of the install that the secrets are read from, or empty for the unprefixed ones.
+	NamespacePrefix string
+}
+
+// Namespace is the function that returns the namespace of the install under the namespace prefix of the options.
+func (o CheckOptions) Namespace(namespace string) string {
+	return kubeutil.PrefixNamespace(o.NamespacePrefix, namespace)
+}
+
+// ProviderServiceAccountsNamespace is the function that returns the namespace of the service accounts of the Crossplane providers of the options, or the
+// crossplane namespace if it is empty.
+func (o CheckOptions) ProviderServiceAccountsNamespace() string {
+	if o.CrossplaneNamespace == constant.EmptyString {
+		return constant.NamespaceCrossplane
+	}
+
+	return o.CrossplaneNamespace
+}
+
+// CheckContext is the type that contains the dependencies shared by the checkers, passed to the New function of each of them.
+//
+// Each checker uses only the dependencies it needs; the ones it does not need may be left unset.
+type CheckContext struct {
+	// Logger is the logger.
+	Logger *log.Logger
+	// VCloud is the cloud provider.
+	VCloud cloud.Cloud
+	// EnvConfig is the environment configuration.
+	EnvConfig *envconfig.EnvConfig
+	// Clientset is the Kubernetes client.
+	Clientset kubernetes.Interface
+	// DynamicClient is the Kubernetes dynamic client.
+	DynamicClient dynamic.Interface
+	// HTTPClient is the HTTP client.
+	HTTPClient *http.Client
+	// Results is the collector of the results of the checks, or nil if they are not reported.
+	Results *report.Collector
+	// OnCheckStart is the function that is called with the name of each of the checks as it starts, or nil if their starts are not reported.
+	OnCheckStart func(check string)
+	// Clock is the clock that the checks read the time from and poll on, or nil for the real one.
+	Clock clock.Clock
+
+	// Options is the configuration options of the checks.
+	Options CheckOptions
+}
+
+// ClockOrReal is the function that returns the clock of the context, or the real one if it is not set.
+func (c CheckContext) ClockOrReal() clock.Clock {
+	if c.Clock == nil {
+		return clock.Real{}
+	}
+
+	return c.Clock
+}
+
+// CheckStarted is the function that reports that the check starts, if the starts of the checks are reported.
+func (c CheckContext) CheckStarted(check string) {
+	if c.OnCheckStart != nil {
+		c.OnCheckStart(check)
+	}
+}
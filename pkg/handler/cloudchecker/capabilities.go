@@ -0,0 +1,74 @@
+// Package cloudchecker is the package that contains cloud checking related variables and constants.
+package cloudchecker
+
+import (
+	"slices"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/cloud"
+)
+
+// methodClusterRead is the method of the checks that only read the cluster resources.
+const methodClusterRead = "reads the cluster resources"
+
+// constSupportedClouds is the list of the cloud providers the pod runs the checks on.
+//
+// Do not modify this variable, it is supposed to be constant.
+var constSupportedClouds = []cloud.Cloud{cloud.AWS, cloud.Azure, cloud.GCP}
+
+// CheckCapability is the type that describes whether and how a check runs on a cloud provider.
+type CheckCapability struct {
+	// Name is the name of the check.
+	Name string `json:"name"`
+	// Supported is whether the check runs on the cloud provider.
+	Supported bool `json:"supported"`
+	// RoleOnly is whether the check runs when only the Crossplane role is checked.
+	RoleOnly bool `json:"roleOnly"`
+	// Method is how the check is performed on the cloud provider, empty if the check is not supported.
+	Method string `json:"method,omitempty"`
+	// Live is whether the check connects to an endpoint outside of the Kubernetes API server.
+	Live bool `json:"live"`
+}
+
+// CloudCapabilities is the type that describes the checks of a cloud provider.
+type CloudCapabilities struct {
+	// Cloud is the cloud provider.
+	Cloud cloud.Cloud `json:"cloud"`
+	// Checks is the list of all of the checks, in the order they are run.
+	Checks []CheckCapability `json:"checks"`
+}
+
+// Capabilities is the function that returns, for every supported cloud provider, all of the checks that the pod may run and whether and how each
+// of them runs on the cloud provider.
+//
+// It is derived from the same registry as Plan, so that it cannot diverge from what the pod actually does.
+func Capabilities() []CloudCapabilities {
+	capabilities := make([]CloudCapabilities, 0, len(constSupportedClouds))
+
+	for _, vcloud := range constSupportedClouds {
+		checks := make([]CheckCapability, 0, len(constRegisteredChecks))
+
+		for _, check := range constRegisteredChecks {
+			capability := CheckCapability{
+				Name:      check.name,
+				Supported: check.clouds == nil || slices.Contains(check.clouds, vcloud),
+				RoleOnly:  check.roleOnly,
+			}
+
+			if capability.Supported {
+				capability.Method = methodClusterRead
+
+				if check.method != nil {
+					capability.Method = check.method(vcloud)
+				}
+
+				capability.Live = check.live
+			}
+
+			checks = append(checks, capability)
+		}
+
+		capabilities = append(capabilities, CloudCapabilities{Cloud: vcloud, Checks: checks})
+	}
+
+	return capabilities
+}
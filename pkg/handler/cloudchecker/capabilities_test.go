@@ -0,0 +1,65 @@
+// Package cloudchecker is the package that contains cloud checking related variables and constants.
+package cloudchecker
+
+import (
+	"testing"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/cloud"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCapabilities is a test that tests that the Capabilities function agrees with the Plan function for every cloud provider.
+func TestCapabilities(t *testing.T) {
+	capabilities := Capabilities()
+
+	require.Len(t, capabilities, len(constSupportedClouds))
+
+	for _, c := range capabilities {
+		t.Run(string(c.Cloud), func(t *testing.T) {
+			require.Len(t, c.Checks, len(constRegisteredChecks))
+
+			for _, roleOnly := range []bool{false, true} {
+				plan, err := Plan(c.Cloud, roleOnly)
+				require.NoError(t, err)
+
+				var want []string
+
+				for _, check := range plan {
+					want = append(want, check.Name)
+				}
+
+				var got []string
+
+				for _, check := range c.Checks {
+					if check.Supported && (!roleOnly || check.RoleOnly) {
+						got = append(got, check.Name)
+					}
+				}
+
+				assert.Equal(t, want, got)
+			}
+
+			for _, check := range c.Checks {
+				assert.Equal(t, check.Supported, check.Method != "", check.Name)
+			}
+		})
+	}
+}
+
+// TestCapabilities_roleCheckMethod is a test that tests that the Crossplane role check method differs between the cloud providers.
+func TestCapabilities_roleCheckMethod(t *testing.T) {
+	methods := map[cloud.Cloud]string{}
+
+	for _, c := range Capabilities() {
+		for _, check := range c.Checks {
+			if check.Name == "Crossplane role" {
+				methods[c.Cloud] = check.Method
+			}
+		}
+	}
+
+	assert.Contains(t, methods[cloud.AWS], "IAM")
+	assert.Contains(t, methods[cloud.GCP], "pod")
+	assert.NotEqual(t, methods[cloud.AWS], methods[cloud.Azure])
+}
@@ -4,10 +4,9 @@ package cloudchecker
 import (
 	"context"
 	"errors"
-	"net/http"
+	"reflect"
 
-	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/cloud"
-	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/envconfig"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/mysqlchecker"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/nodegroupchecker"
@@ -17,10 +16,11 @@ import (
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/ssochecker"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/storageclasschecker"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/tlschecker"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/report"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/util"
 	"github.com/charmbracelet/log"
 	"go.uber.org/multierr"
-	"k8s.io/client-go/kubernetes"
+	"golang.org/x/sync/errgroup"
 )
 
 var (
@@ -48,16 +48,13 @@ var (
 
 // CloudChecker is the type that contains the infrastructure check functions for cloud.
 type CloudChecker struct {
+	// checkCtx is the check context, passed to the checkers it sets up.
+	checkCtx handler.CheckContext
+
 	// logger is the logger.
 	logger *log.Logger
-	// vcloud is the cloud provider.
-	vcloud cloud.Cloud
-	// envConfig is the environment configuration.
-	envConfig *envconfig.EnvConfig
-	// clientset is the Kubernetes client.
-	clientset kubernetes.Interface
-	// httpClient is the HTTP client.
-	httpClient *http.Client
+	// results is the collector of the results of the checks, or nil if they are not reported.
+	results *report.Collector
 
 	// storageClassChecker is the storage class checker.
 	storageClassChecker *storageclasschecker.StorageClassChecker
@@ -77,41 +74,84 @@ type CloudChecker struct {
 
 	// oidcChecker is the OIDC checker.
 	oidcChecker *oidcchecker.OIDCChecker
+
+	// skipNodeGroups is whether the node group check is skipped, as the deployment does not require GPU nodes.
+	skipNodeGroups bool
+	// maxConcurrency is the maximum number of the checks that run at once, one at a time if it is not greater than 1.
+	maxConcurrency int
 }
 
 var _ handler.Handler = &CloudChecker{}
 
 // setup is the function that sets up the cloud checker.
 func (c *CloudChecker) setup() {
-	c.storageClassChecker = storageclasschecker.New(c.clientset)
+	c.storageClassChecker = storageclasschecker.New(c.checkCtx)
 
-	c.nodeGroupChecker = nodegroupchecker.New(c.clientset)
+	c.nodeGroupChecker = nodegroupchecker.New(c.checkCtx)
 
-	c.mySQLChecker = mysqlchecker.New(c.clientset)
+	c.mySQLChecker = mysqlchecker.New(c.checkCtx)
 
-	c.postgresqlChecker = postgresqlchecker.New(c.clientset)
+	c.postgresqlChecker = postgresqlchecker.New(c.checkCtx)
 
-	c.tlsChecker = tlschecker.New(c.clientset)
+	c.tlsChecker = tlschecker.New(c.checkCtx)
 
-	c.smtpChecker = smtpchecker.New(c.clientset)
+	c.smtpChecker = smtpchecker.New(c.checkCtx)
 
-	c.ssoChecker = ssochecker.New(c.clientset)
+	c.ssoChecker = ssochecker.New(c.checkCtx)
 
-	c.oidcChecker = oidcchecker.New(c.vcloud, c.envConfig, c.httpClient)
+	c.oidcChecker = oidcchecker.New(c.checkCtx)
 }
 
-// Handle is the function that handles the infrastructure check.
-//
-// Checks in this function are ordered in the same way as they are listed at https://developer.alpha-sense.com/enterprise/technical-requirements.
+// record is the function that adds the result of the check to the collector, failed with the error if it is not nil and passed otherwise.
+func (c *CloudChecker) record(check string, err error) {
+	c.results.Add(report.NewResult(check, constant.EmptyString, err))
+}
+
+// checkFunc is the type of the function that runs a check of the cloud, returning the function that logs and records its outcome, and the error that
+// aborts the other checks if the check failed.
+type checkFunc func(ctx context.Context) (outcome func(), err error)
+
+// cancelled is the function that returns whether the check failed as its context was cancelled, rather than on its own.
+func cancelled(ctx context.Context, err error) bool {
+	return ctx.Err() != nil && errors.Is(err, context.Canceled)
+}
+
+// check is the function that returns the checkFunc that runs the handler, wrapping its error with errFailed and logging logMsgSuccess if it passes.
 //
-// The arguments are not used.
-// It returns the JWKS URI on success, or an error on failure.
+// The check that fails as its context is cancelled, once another check that runs at the same time fails, is recorded as skipped rather than failed.
+func (c *CloudChecker) check(name string, errFailed error, logMsgSuccess string, h handler.Handler) checkFunc {
+	// msgCancelled is the reason for skipping the check that was cancelled.
+	const msgCancelled = "cancelled as another check failed"
+
+	return func(ctx context.Context) (func(), error) {
+		c.checkCtx.CheckStarted(name)
+
+		if _, err := h.Handle(ctx); err != nil {
+			if cancelled(ctx, err) {
+				return func() {
+					c.results.Add(report.Result{Check: name, Status: report.StatusSkipped, Message: msgCancelled, SkipReason: report.SkipReasonCancelled})
+				}, multierr.Combine(errFailed, err)
+			}
+
+			return func() { c.record(name, err) }, multierr.Combine(errFailed, err)
+		}
+
+		return func() {
+			c.record(name, nil)
+
+			c.logger.Info(logMsgSuccess)
+		}, nil
+	}
+}
+
+// checkNodeGroups is the function that checks the node groups, warning rather than failing if there are no GPU nodes, as not every deployment requires
+// them; it skips the check entirely for the deployments that do not.
 //
-// nolint:funlen
-func (c *CloudChecker) Handle(ctx context.Context, _ ...any) ([]any, error) {
+// It returns the function that logs and records the outcome of the check.
+func (c *CloudChecker) checkNodeGroups(ctx context.Context) func() {
 	const (
-		// logMsgStorageClassCheckedSuccessfully is the message that is logged when the storage class is checked successfully.
-		logMsgStorageClassCheckedSuccessfully = "checked storage class successfully"
+		// msgNodeGroupsNotRequired is the reason for skipping the node group check.
+		msgNodeGroupsNotRequired = "the deployment does not require GPU nodes"
 
 		// logMsgNodeGroupsCheckedSuccessfully is the message that is logged when the node groups are checked successfully.
 		logMsgNodeGroupsCheckedSuccessfully = "checked node groups successfully"
@@ -119,69 +159,148 @@ func (c *CloudChecker) Handle(ctx context.Context, _ ...any) ([]any, error) {
 		// logMsgNodeGroupsCheckedWarn is the message that is logged when the node groups are checked with a warning.
 		logMsgNodeGroupsCheckedWarn = "checked node groups; %s"
 
-		// logMsgMySQLCheckedSuccessfully is the message that is logged when the MySQL is checked successfully.
-		logMsgMySQLCheckedSuccessfully = "checked MySQL successfully"
-
-		// logMsgPostgreSQLCheckedSuccessfully is the message that is logged when the PostgreSQL is checked successfully.
-		logMsgPostgreSQLCheckedSuccessfully = "checked PostgreSQL successfully"
-
-		// logMsgTLSCheckedSuccessfully is the message that is logged when the TLS is checked successfully.
-		logMsgTLSCheckedSuccessfully = "checked TLS successfully"
-
-		// logMsgSMTPCheckedSuccessfully is the message that is logged when the SMTP is checked successfully.
-		logMsgSMTPCheckedSuccessfully = "checked SMTP successfully"
-
-		// logMsgSSOCheckedSuccessfully is the message that is logged when the SSO is checked successfully.
-		logMsgSSOCheckedSuccessfully = "checked SSO successfully"
-
-		// logMsgOIDCURLCheckedSuccessfully is the message that is logged when the OIDC URL is checked successfully.
-		logMsgOIDCURLCheckedSuccessfully = "checked OIDC URL successfully"
+		// logMsgNodeGroupsSkipped is the message that is logged when the node group check is skipped.
+		logMsgNodeGroupsSkipped = "skipped node groups check; " + msgNodeGroupsNotRequired
 	)
 
-	if _, err := c.storageClassChecker.Handle(ctx); err != nil {
-		return nil, multierr.Combine(ErrFailedToCheckStorageClass, err)
+	if c.skipNodeGroups {
+		return func() {
+			c.logger.Info(logMsgNodeGroupsSkipped)
+
+			c.results.Add(report.Result{
+				Check:      CheckNameNodeGroups,
+				Status:     report.StatusSkipped,
+				Message:    msgNodeGroupsNotRequired,
+				SkipReason: report.SkipReasonUserRequested,
+			})
+		}
 	}
 
-	c.logger.Info(logMsgStorageClassCheckedSuccessfully)
+	c.checkCtx.CheckStarted(CheckNameNodeGroups)
 
 	if _, err := c.nodeGroupChecker.Handle(ctx); err != nil {
-		c.logger.Logf(log.WarnLevel, logMsgNodeGroupsCheckedWarn, err.Error())
-	} else {
+		return func() {
+			c.logger.Logf(log.WarnLevel, logMsgNodeGroupsCheckedWarn, err.Error())
+
+			c.results.Add(report.Result{Check: CheckNameNodeGroups, Status: report.StatusWarning, Message: err.Error()})
+		}
+	}
+
+	return func() {
 		c.logger.Info(logMsgNodeGroupsCheckedSuccessfully)
+
+		c.record(CheckNameNodeGroups, nil)
 	}
+}
 
-	if _, err := c.mySQLChecker.Handle(ctx); err != nil {
-		return nil, multierr.Combine(ErrFailedToCheckMySQL, err)
+// runChecks is the function that runs the checks, up to maxConcurrency of them at once, and returns the error of the first one that fails.
+//
+// The outcomes of the checks are logged and recorded in the order of the checks, whatever the order they finish in. When the checks run one at a time, the
+// ones after the first that fails are not run; when they run at once, the first that fails cancels the context of the others, the ones that have not
+// started are not run, and the outcome of each of the ones that have is recorded, so that the other checks that fail on their own are reported as failed
+// and logged, and the ones that fail as they are cancelled are reported as skipped.
+func (c *CloudChecker) runChecks(ctx context.Context, checks []checkFunc) error {
+	if c.maxConcurrency <= 1 {
+		for _, check := range checks {
+			outcome, err := check(ctx)
+
+			outcome()
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
 	}
 
-	c.logger.Info(logMsgMySQLCheckedSuccessfully)
+	outcomes := make([]func(), len(checks))
+	errs := make([]error, len(checks))
 
-	if _, err := c.postgresqlChecker.Handle(ctx); err != nil {
-		return nil, multierr.Combine(ErrFailedToCheckPostgreSQL, err)
-	}
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(c.maxConcurrency)
 
-	c.logger.Info(logMsgPostgreSQLCheckedSuccessfully)
+	for i, check := range checks {
+		g.Go(func() error {
+			// The checks that have not started by the time another one fails are not run at all.
+			if gctx.Err() != nil {
+				return nil
+			}
 
-	if _, err := c.tlsChecker.Handle(ctx); err != nil {
-		return nil, multierr.Combine(ErrFailedToCheckTLS, err)
+			outcomes[i], errs[i] = check(gctx)
+
+			return errs[i]
+		})
 	}
 
-	c.logger.Info(logMsgTLSCheckedSuccessfully)
+	err := g.Wait()
+
+	for i, outcome := range outcomes {
+		if outcome == nil {
+			continue
+		}
+
+		outcome()
 
-	if _, err := c.smtpChecker.Handle(ctx); err != nil {
-		return nil, multierr.Combine(ErrFailedToCheckSMTP, err)
+		// The error of the check that failed first is the very one returned by Wait, which is logged by the caller.
+		if errs[i] != nil && errs[i] != err && !cancelled(gctx, errs[i]) { // nolint:errorlint
+			c.logger.Error(errs[i])
+		}
 	}
 
-	c.logger.Info(logMsgSMTPCheckedSuccessfully)
+	return err
+}
+
+// Handle is the function that handles the infrastructure check.
+//
+// Checks in this function are ordered in the same way as they are listed at https://developer.alpha-sense.com/enterprise/technical-requirements, and run
+// up to the maximum concurrency of the options at once; the OIDC URL check runs last, once the others pass, as its result is the return value.
+//
+// The arguments are not used.
+// It returns the JWKS URI on success, or an error on failure.
+func (c *CloudChecker) Handle(ctx context.Context, _ ...any) ([]any, error) {
+	const (
+		// logMsgStorageClassCheckedSuccessfully is the message that is logged when the storage class is checked successfully.
+		logMsgStorageClassCheckedSuccessfully = "checked storage class successfully"
+
+		// logMsgMySQLCheckedSuccessfully is the message that is logged when the MySQL is checked successfully.
+		logMsgMySQLCheckedSuccessfully = "checked MySQL successfully"
 
-	if _, err := c.ssoChecker.Handle(ctx); err != nil {
-		return nil, multierr.Combine(ErrFailedToCheckSSO, err)
+		// logMsgPostgreSQLCheckedSuccessfully is the message that is logged when the PostgreSQL is checked successfully.
+		logMsgPostgreSQLCheckedSuccessfully = "checked PostgreSQL successfully"
+
+		// logMsgTLSCheckedSuccessfully is the message that is logged when the TLS is checked successfully.
+		logMsgTLSCheckedSuccessfully = "checked TLS successfully"
+
+		// logMsgSMTPCheckedSuccessfully is the message that is logged when the SMTP is checked successfully.
+		logMsgSMTPCheckedSuccessfully = "checked SMTP successfully"
+
+		// logMsgSSOCheckedSuccessfully is the message that is logged when the SSO is checked successfully.
+		logMsgSSOCheckedSuccessfully = "checked SSO successfully"
+
+		// logMsgOIDCURLCheckedSuccessfully is the message that is logged when the OIDC URL is checked successfully.
+		logMsgOIDCURLCheckedSuccessfully = "checked OIDC URL successfully"
+	)
+
+	if err := c.runChecks(ctx, []checkFunc{
+		c.check(CheckNameStorageClass, ErrFailedToCheckStorageClass, logMsgStorageClassCheckedSuccessfully, c.storageClassChecker),
+		// The node group check only warns, so it never aborts the others.
+		func(ctx context.Context) (func(), error) { return c.checkNodeGroups(ctx), nil },
+		c.check(CheckNameMySQL, ErrFailedToCheckMySQL, logMsgMySQLCheckedSuccessfully, c.mySQLChecker),
+		c.check(CheckNamePostgreSQL, ErrFailedToCheckPostgreSQL, logMsgPostgreSQLCheckedSuccessfully, c.postgresqlChecker),
+		c.check(CheckNameTLS, ErrFailedToCheckTLS, logMsgTLSCheckedSuccessfully, c.tlsChecker),
+		c.check(CheckNameSMTP, ErrFailedToCheckSMTP, logMsgSMTPCheckedSuccessfully, c.smtpChecker),
+		c.check(CheckNameSSO, ErrFailedToCheckSSO, logMsgSSOCheckedSuccessfully, c.ssoChecker),
+	}); err != nil {
+		return nil, err
 	}
 
-	c.logger.Info(logMsgSSOCheckedSuccessfully)
+	c.checkCtx.CheckStarted(CheckNameOIDCURL)
 
 	jwksURI, err := util.UnwrapValErr[*string](c.oidcChecker.Handle(ctx))
 	if err != nil {
+		c.record(CheckNameOIDCURL, err)
+
 		return nil, multierr.Combine(ErrFailedToCheckOIDCURL, err)
 	}
 
@@ -191,17 +310,28 @@ func (c *CloudChecker) Handle(ctx context.Context, _ ...any) ([]any, error) {
 
 	c.logger.Info(logMsgOIDCURLCheckedSuccessfully)
 
+	c.record(CheckNameOIDCURL, nil)
+
 	return []any{jwksURI}, nil
 }
 
+// Contract is the function that returns the contract of the results of the infrastructure check.
+//
+// It returns the JWKS URI, or nothing if the cloud provider does not use one.
+func (c *CloudChecker) Contract() handler.Contract {
+	return handler.Contract{Results: []reflect.Type{reflect.TypeFor[*string]()}, Optional: true}
+}
+
 // New is the function that creates a new CloudChecker.
-func New(logger *log.Logger, vcloud cloud.Cloud, envConfig *envconfig.EnvConfig, clientset kubernetes.Interface, httpClient *http.Client) *CloudChecker {
+func New(checkCtx handler.CheckContext) *CloudChecker {
 	c := &CloudChecker{
-		logger:     logger,
-		vcloud:     vcloud,
-		envConfig:  envConfig,
-		clientset:  clientset,
-		httpClient: httpClient,
+		checkCtx: checkCtx,
+
+		logger:  checkCtx.Logger,
+		results: checkCtx.Results,
+
+		skipNodeGroups: checkCtx.Options.SkipNodeGroups,
+		maxConcurrency: checkCtx.Options.MaxConcurrency,
 	}
 
 	c.setup()
@@ -0,0 +1,296 @@
+// Package cloudchecker is the package that contains cloud checking related variables and constants.
+package cloudchecker
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/clock"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/report"
+	"github.com/charmbracelet/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/multierr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// TestCloudChecker_checkNodeGroups is a test that tests that the checkNodeGroups function warns about the missing GPU nodes only if the deployment
+// requires them, recording the result of the check.
+func TestCloudChecker_checkNodeGroups(t *testing.T) {
+	gpuNode := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "gpu", Labels: map[string]string{"type": "gpu"}}}
+
+	testCases := []struct {
+		name           string
+		nodes          []runtime.Object
+		skipNodeGroups bool
+		want           string
+		wantWarn       bool
+		wantStatus     report.Status
+		wantSkipReason report.SkipReason
+	}{
+		{name: "GPU nodes", nodes: []runtime.Object{gpuNode}, want: "checked node groups successfully", wantStatus: report.StatusPassed},
+		{name: "No GPU nodes", want: "no nodes with GPU label found", wantWarn: true, wantStatus: report.StatusWarning},
+		{
+			name:           "No GPU nodes not required",
+			skipNodeGroups: true,
+			want:           "skipped node groups check; the deployment does not require GPU nodes",
+			wantStatus:     report.StatusSkipped,
+			wantSkipReason: report.SkipReasonUserRequested,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			results := report.NewCollector(clock.Real{})
+
+			c := New(handler.CheckContext{
+				Logger:    log.New(&buf),
+				Results:   results,
+				Clientset: fake.NewClientset(tc.nodes...),
+				Options:   handler.CheckOptions{SkipNodeGroups: tc.skipNodeGroups},
+			})
+
+			c.checkNodeGroups(context.Background())()
+
+			assert.Contains(t, buf.String(), tc.want)
+			assert.Equal(t, tc.wantWarn, bytes.Contains(buf.Bytes(), []byte("WARN")))
+
+			if got := results.Results(); assert.Len(t, got, 1) {
+				assert.Equal(t, CheckNameNodeGroups, got[0].Check)
+				assert.Equal(t, tc.wantStatus, got[0].Status)
+				assert.Equal(t, tc.wantSkipReason, got[0].SkipReason)
+			}
+		})
+	}
+}
+
+// TestCloudChecker_runChecks is a test that tests that the runChecks function logs the outcomes of the checks that were run in their order and returns the
+// error of the first one that fails, whether the checks run one at a time or at once.
+func TestCloudChecker_runChecks(t *testing.T) {
+	// errFailed is the error that the failing check is wrapped with in the test.
+	errFailed := errors.New("failed to check")
+
+	testCases := []struct {
+		name           string
+		maxConcurrency int
+	}{
+		{name: "Sequential", maxConcurrency: 1},
+		{name: "Default", maxConcurrency: 0},
+		{name: "Concurrent", maxConcurrency: 3},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := New(handler.CheckContext{Logger: log.New(io.Discard), Options: handler.CheckOptions{MaxConcurrency: tc.maxConcurrency}})
+
+			var outcomes []int
+
+			passing := func(i int) checkFunc {
+				return func(context.Context) (func(), error) {
+					return func() { outcomes = append(outcomes, i) }, nil
+				}
+			}
+
+			t.Run("Passed", func(t *testing.T) {
+				outcomes = nil
+
+				require.NoError(t, c.runChecks(context.Background(), []checkFunc{passing(0), passing(1), passing(2)}))
+				assert.Equal(t, []int{0, 1, 2}, outcomes)
+			})
+
+			t.Run("Failed", func(t *testing.T) {
+				outcomes = nil
+
+				// first is closed once the first check passes, which the failing one waits for, so that the first one is not cancelled before it starts.
+				first := make(chan struct{})
+
+				err := c.runChecks(context.Background(), []checkFunc{
+					func(ctx context.Context) (func(), error) {
+						defer close(first)
+
+						return passing(0)(ctx)
+					},
+					func(context.Context) (func(), error) {
+						<-first
+
+						return func() { outcomes = append(outcomes, 1) }, multierr.Combine(errFailed, errors.New("unreachable"))
+					},
+					// The check after the failing one is either not run, or fails as its context is cancelled.
+					func(ctx context.Context) (func(), error) {
+						<-ctx.Done()
+
+						return func() { outcomes = append(outcomes, 2) }, multierr.Combine(errors.New("other"), ctx.Err())
+					},
+				})
+
+				require.ErrorIs(t, err, errFailed)
+				assert.NotErrorIs(t, err, context.Canceled)
+
+				// The outcome of the check after the failing one is logged only if it started before its context was cancelled.
+				if tc.maxConcurrency <= 1 {
+					assert.Equal(t, []int{0, 1}, outcomes)
+				} else {
+					assert.Contains(t, [][]int{{0, 1}, {0, 1, 2}}, outcomes)
+				}
+			})
+		})
+	}
+}
+
+// TestCloudChecker_runChecks_order is a test that tests that the runChecks function logs the outcomes of the checks that run at once in their order rather
+// than in the one they finish in.
+func TestCloudChecker_runChecks_order(t *testing.T) {
+	c := New(handler.CheckContext{Logger: log.New(io.Discard), Options: handler.CheckOptions{MaxConcurrency: 3}})
+
+	var outcomes []int
+
+	// last is closed once the last check finishes, which the first one waits for, so that the first one finishes last.
+	last := make(chan struct{})
+
+	require.NoError(t, c.runChecks(context.Background(), []checkFunc{
+		func(context.Context) (func(), error) {
+			<-last
+
+			return func() { outcomes = append(outcomes, 0) }, nil
+		},
+		func(context.Context) (func(), error) {
+			return func() { outcomes = append(outcomes, 1) }, nil
+		},
+		func(context.Context) (func(), error) {
+			defer close(last)
+
+			return func() { outcomes = append(outcomes, 2) }, nil
+		},
+	}))
+
+	assert.Equal(t, []int{0, 1, 2}, outcomes)
+}
+
+// handlerFunc is the type of the function that is used as a handler in the tests.
+type handlerFunc func(ctx context.Context) error
+
+var _ handler.Handler = handlerFunc(nil)
+
+// Handle is the function that calls the function.
+func (f handlerFunc) Handle(ctx context.Context, _ ...any) ([]any, error) {
+	return nil, f(ctx)
+}
+
+// Contract is the function that returns the contract of the results of the function, which has none.
+func (f handlerFunc) Contract() handler.Contract {
+	return handler.Contract{}
+}
+
+// TestCloudChecker_check_cancelled is a test that tests that the check function records the check that fails as its context is cancelled as skipped,
+// and the one that fails on its own as failed.
+func TestCloudChecker_check_cancelled(t *testing.T) {
+	// errFailed is the error that the failing check is wrapped with in the test.
+	errFailed := errors.New("failed to check")
+
+	testCases := []struct {
+		name           string
+		err            error
+		cancel         bool
+		wantStatus     report.Status
+		wantSkipReason report.SkipReason
+	}{
+		{name: "Failed", err: errors.New("connection refused"), wantStatus: report.StatusFailed},
+		{name: "Failed with the context cancelled", err: errors.New("connection refused"), cancel: true, wantStatus: report.StatusFailed},
+		{name: "Cancelled", err: context.Canceled, cancel: true, wantStatus: report.StatusSkipped, wantSkipReason: report.SkipReasonCancelled},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			results := report.NewCollector(clock.Real{})
+
+			c := New(handler.CheckContext{Logger: log.New(io.Discard), Results: results})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			if tc.cancel {
+				cancel()
+			}
+
+			outcome, err := c.check(CheckNameMySQL, errFailed, "checked MySQL successfully", handlerFunc(func(context.Context) error { return tc.err }))(ctx)
+
+			require.ErrorIs(t, err, errFailed)
+
+			outcome()
+
+			if got := results.Results(); assert.Len(t, got, 1) {
+				assert.Equal(t, CheckNameMySQL, got[0].Check)
+				assert.Equal(t, tc.wantStatus, got[0].Status)
+				assert.Equal(t, tc.wantSkipReason, got[0].SkipReason)
+			}
+		})
+	}
+}
+
+// TestCloudChecker_runChecks_failedAtOnce is a test that tests that the runChecks function reports each of the checks that fail at once as failed, and the
+// one that is cancelled by their failure as skipped.
+func TestCloudChecker_runChecks_failedAtOnce(t *testing.T) {
+	var buf bytes.Buffer
+
+	results := report.NewCollector(clock.Real{})
+
+	c := New(handler.CheckContext{Logger: log.New(&buf), Results: results, Options: handler.CheckOptions{MaxConcurrency: 3}})
+
+	// started is done once all of the checks start, which the failing ones wait for, so that neither is cancelled before it fails.
+	var started sync.WaitGroup
+
+	started.Add(3)
+
+	failing := func(msg string) handlerFunc {
+		return func(context.Context) error {
+			started.Done()
+			started.Wait()
+
+			return errors.New(msg)
+		}
+	}
+
+	err := c.runChecks(context.Background(), []checkFunc{
+		c.check(CheckNameMySQL, ErrFailedToCheckMySQL, "checked MySQL successfully", failing("mysql unreachable")),
+		c.check(CheckNamePostgreSQL, ErrFailedToCheckPostgreSQL, "checked PostgreSQL successfully", failing("postgresql unreachable")),
+		c.check(CheckNameTLS, ErrFailedToCheckTLS, "checked TLS successfully", handlerFunc(func(ctx context.Context) error {
+			started.Done()
+
+			<-ctx.Done()
+
+			return ctx.Err()
+		})),
+	})
+
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFailedToCheckMySQL) || errors.Is(err, ErrFailedToCheckPostgreSQL))
+	assert.NotErrorIs(t, err, context.Canceled)
+
+	got := results.Results()
+	require.Len(t, got, 3)
+
+	assert.Equal(t, CheckNameMySQL, got[0].Check)
+	assert.Equal(t, report.StatusFailed, got[0].Status)
+	assert.Equal(t, "mysql unreachable", got[0].Message)
+
+	assert.Equal(t, CheckNamePostgreSQL, got[1].Check)
+	assert.Equal(t, report.StatusFailed, got[1].Status)
+	assert.Equal(t, "postgresql unreachable", got[1].Message)
+
+	assert.Equal(t, CheckNameTLS, got[2].Check)
+	assert.Equal(t, report.StatusSkipped, got[2].Status)
+	assert.Equal(t, report.SkipReasonCancelled, got[2].SkipReason)
+
+	// The failure that is not returned is logged, as the caller only logs the returned one.
+	assert.Contains(t, buf.String(), "unreachable")
+}
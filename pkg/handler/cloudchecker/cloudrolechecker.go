@@ -0,0 +1,29 @@
+// Package cloudchecker is the package that contains cloud checking related variables and constants.
+package cloudchecker
+
+import (
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/cloud"
+	pkgerrors "github.com/AlphaSense-Engineering/privatecloud-cli/pkg/errors"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/awschecker"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/azurechecker"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/gcpchecker"
+)
+
+// NewCloudRoleChecker is the function that creates the cloud-specific checker of the Crossplane role for the cloud provider of the check context.
+//
+// The JWKS URI is only used on AWS and Azure, and the Google Cloud SDK Docker repository and image and the pod security profile of the options are only
+// used on GCP.
+// It returns an error if the cloud provider is unsupported.
+func NewCloudRoleChecker(checkCtx handler.CheckContext, jwksURI *string) (handler.Handler, error) {
+	switch checkCtx.VCloud {
+	case cloud.AWS:
+		return awschecker.New(checkCtx, jwksURI), nil
+	case cloud.Azure:
+		return azurechecker.New(checkCtx, jwksURI), nil
+	case cloud.GCP:
+		return gcpchecker.New(checkCtx), nil
+	}
+
+	return nil, pkgerrors.NewUnsupportedCloud(checkCtx.VCloud)
+}
@@ -0,0 +1,70 @@
+// Package cloudchecker is the package that contains cloud checking related variables and constants.
+package cloudchecker
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/cloud"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/envconfig"
+	pkgerrors "github.com/AlphaSense-Engineering/privatecloud-cli/pkg/errors"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/awschecker"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/azurechecker"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/gcpchecker"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/k8s/kubeutil"
+	"github.com/charmbracelet/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// TestNewCloudRoleChecker is a test that tests that the NewCloudRoleChecker function selects the checker for the cloud provider.
+func TestNewCloudRoleChecker(t *testing.T) {
+	testCases := []struct {
+		name    string
+		vcloud  cloud.Cloud
+		want    handler.Handler
+		wantErr error
+	}{
+		{name: "AWS", vcloud: cloud.AWS, want: &awschecker.AWSChecker{}},
+		{name: "Azure", vcloud: cloud.Azure, want: &azurechecker.AzureChecker{}},
+		{name: "GCP", vcloud: cloud.GCP, want: &gcpchecker.GCPChecker{}},
+		{name: "Unsupported", vcloud: cloud.Cloud("unsupported"), wantErr: pkgerrors.NewUnsupportedCloud(cloud.Cloud("unsupported"))},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			jwksURI := "irrelevant"
+
+			checkCtx := handler.CheckContext{
+				Logger:        log.New(io.Discard),
+				VCloud:        tc.vcloud,
+				EnvConfig:     &envconfig.EnvConfig{},
+				Clientset:     fake.NewClientset(),
+				DynamicClient: dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()),
+				HTTPClient:    http.DefaultClient,
+				Options: handler.CheckOptions{
+					GoogleCloudSDKDockerRepo:  "google",
+					GoogleCloudSDKDockerImage: "cloud-sdk:latest",
+					PodSecurityProfile:        kubeutil.PodSecurityProfileRestricted,
+				},
+			}
+
+			got, err := NewCloudRoleChecker(checkCtx, &jwksURI)
+
+			if tc.wantErr != nil {
+				assert.EqualError(t, err, tc.wantErr.Error())
+				assert.Nil(t, got)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.IsType(t, tc.want, got)
+		})
+	}
+}
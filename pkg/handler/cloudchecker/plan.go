@@ -0,0 +1,279 @@
+// Package cloudchecker is the package that contains cloud checking related variables and constants.
+package cloudchecker
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/cloud"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
+	pkgerrors "github.com/AlphaSense-Engineering/privatecloud-cli/pkg/errors"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/awsjwtretriever"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/mysqlchecker"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/postgresqlchecker"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/providerconfigchecker"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/smtpchecker"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/ssochecker"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/tlschecker"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/report"
+)
+
+// The names of the checks, which the plan and the results of the checks share.
+const (
+	// CheckNameStorageClass is the name of the storage class check.
+	CheckNameStorageClass = "storage class"
+
+	// CheckNameNodeGroups is the name of the node groups check.
+	CheckNameNodeGroups = "node groups"
+
+	// CheckNameMySQL is the name of the MySQL check.
+	CheckNameMySQL = "MySQL"
+
+	// CheckNamePostgreSQL is the name of the PostgreSQL check.
+	CheckNamePostgreSQL = "PostgreSQL"
+
+	// CheckNameTLS is the name of the TLS check.
+	CheckNameTLS = "TLS"
+
+	// CheckNameSMTP is the name of the SMTP check.
+	CheckNameSMTP = "SMTP"
+
+	// CheckNameSSO is the name of the SSO check.
+	CheckNameSSO = "SSO"
+
+	// CheckNameOIDCURL is the name of the OIDC URL check.
+	CheckNameOIDCURL = "OIDC URL"
+
+	// CheckNameProviderServiceAccounts is the name of the provider service accounts check.
+	CheckNameProviderServiceAccounts = "provider service accounts"
+
+	// CheckNameJWTs is the name of the JWTs check.
+	CheckNameJWTs = "JWTs"
+
+	// CheckNameCrossplaneRole is the name of the Crossplane role check.
+	CheckNameCrossplaneRole = "Crossplane role"
+
+	// CheckNameCrossplaneProviderConfig is the name of the Crossplane ProviderConfig check.
+	CheckNameCrossplaneProviderConfig = "Crossplane ProviderConfig"
+)
+
+// PlannedCheck is the type that describes a check that the pod runs, along with the cluster resources and the endpoints it reads.
+type PlannedCheck struct {
+	// Name is the name of the check.
+	Name string
+	// Reads is the list of the cluster resources and the endpoints that the check reads.
+	Reads []string
+}
+
+// registeredCheck is the type that contains the metadata of a check that the pod may run.
+type registeredCheck struct {
+	// name is the name of the check.
+	name string
+	// reads is the function that returns the list of the cluster resources and the endpoints that the check reads on the cloud provider.
+	reads func(vcloud cloud.Cloud) []string
+	// clouds is the list of the cloud providers the check runs on, or nil if it runs on all of them.
+	clouds []cloud.Cloud
+	// roleOnly is whether the check runs when only the Crossplane role is checked.
+	roleOnly bool
+	// method is the function that returns how the check is performed on the cloud provider, or nil if it only reads the cluster resources.
+	method func(vcloud cloud.Cloud) string
+	// live is whether the check connects to an endpoint outside of the Kubernetes API server.
+	live bool
+	// requirements is the list of the URLs of the sections of the technical requirements documentation that the check validates, of which only the ones
+	// that apply to the cloud provider are reported, or nil if it validates none of the listed sections.
+	requirements []string
+}
+
+// secretReads is a function that returns the reads function of a check that reads a single secret.
+func secretReads(namespace string, name string) func(cloud.Cloud) []string {
+	return func(cloud.Cloud) []string {
+		return []string{fmt.Sprintf("Secret %s/%s", namespace, name)}
+	}
+}
+
+// staticReads is a function that returns the reads function of a check that reads the same resources on every cloud provider.
+func staticReads(reads ...string) func(cloud.Cloud) []string {
+	return func(cloud.Cloud) []string {
+		return reads
+	}
+}
+
+// staticMethod is a function that returns the method function of a check that is performed the same way on every cloud provider.
+func staticMethod(method string) func(cloud.Cloud) string {
+	return func(cloud.Cloud) string {
+		return method
+	}
+}
+
+// constRegisteredChecks is the list of the checks that the pod may run, in the order they are run.
+//
+// Keep it in sync with the Handle functions of the CloudChecker and of the checkers returned by NewCloudRoleChecker.
+//
+// Do not modify this variable, it is supposed to be constant.
+var constRegisteredChecks = []registeredCheck{
+	{name: CheckNameStorageClass, reads: staticReads("StorageClasses"), requirements: []string{DocsPersistentVolumes}},
+	{name: CheckNameNodeGroups, reads: staticReads("Nodes")},
+	{
+		name:         CheckNameMySQL,
+		reads:        secretReads(constant.NamespaceMySQL, mysqlchecker.SecretName),
+		method:       staticMethod("connects to the database with the credentials of the secret"),
+		live:         true,
+		requirements: []string{DocsMySQLDatabaseCluster, DocsMySQLSecrets},
+	},
+	{
+		name:         CheckNamePostgreSQL,
+		reads:        secretReads(constant.NamespacePostgres, postgresqlchecker.SecretName),
+		method:       staticMethod("connects to the database with the credentials of the secret, optionally introspecting the privileges SpiceDB requires"),
+		live:         true,
+		requirements: []string{DocsPostgreSQLDatabaseCluster, DocsPostgreSQLSecrets},
+	},
+	{name: CheckNameTLS, reads: secretReads(constant.NamespaceAlphaSense, tlschecker.SecretName), requirements: []string{DocsTLSSecrets}},
+	{name: CheckNameSMTP, reads: secretReads(constant.NamespaceAlphaSense, smtpchecker.SecretName), requirements: []string{DocsSMTPSecrets}},
+	{name: CheckNameSSO, reads: secretReads(constant.NamespacePlatform, ssochecker.SecretName), requirements: []string{DocsSSOSecrets}},
+	{
+		name:         CheckNameOIDCURL,
+		reads:        staticReads("OIDC discovery document of the environment configuration OIDC URL"),
+		clouds:       []cloud.Cloud{cloud.AWS, cloud.Azure},
+		roleOnly:     true,
+		method:       staticMethod("fetches the OIDC discovery document over HTTPS, warning if the issuer resolves only to private addresses"),
+		live:         true,
+		requirements: []string{DocsAWSOIDC, DocsAzureCrossplaneMI},
+	},
+	{
+		name:         CheckNameProviderServiceAccounts,
+		reads:        staticReads(fmt.Sprintf("ServiceAccounts %s/%s*", constant.NamespaceCrossplane, awsjwtretriever.ServiceAccountsPrefix)),
+		clouds:       []cloud.Cloud{cloud.AWS},
+		roleOnly:     true,
+		requirements: []string{DocsAWSOIDC},
+	},
+	{
+		name: CheckNameJWTs,
+		reads: func(vcloud cloud.Cloud) []string {
+			if vcloud == cloud.AWS {
+				return []string{fmt.Sprintf("ServiceAccounts %s/%s*", constant.NamespaceCrossplane, awsjwtretriever.ServiceAccountsPrefix)}
+			}
+
+			return []string{fmt.Sprintf("ServiceAccount %s/%s", constant.NamespaceCrossplane, constant.ServiceAccountNameAzure)}
+		},
+		clouds:       []cloud.Cloud{cloud.AWS, cloud.Azure},
+		roleOnly:     true,
+		method:       staticMethod("requests the service account tokens and verifies them against the JWKS of the OIDC issuer"),
+		live:         true,
+		requirements: []string{DocsAWSOIDC, DocsAzureCrossplaneMI},
+	},
+	{
+		name: CheckNameCrossplaneRole,
+		reads: func(vcloud cloud.Cloud) []string {
+			if vcloud == cloud.GCP {
+				return []string{
+					fmt.Sprintf("Namespace %s", constant.NamespaceCrossplane),
+					fmt.Sprintf("ServiceAccount %s/%s", constant.NamespaceCrossplane, constant.ServiceAccountNameGCP),
+				}
+			}
+
+			return []string{fmt.Sprintf("permissions of the %s Crossplane role", vcloud)}
+		},
+		roleOnly: true,
+		method: func(vcloud cloud.Cloud) string {
+			switch vcloud {
+			case cloud.AWS:
+				return "reads the IAM role policies directly, assuming the Crossplane role with the service account tokens"
+			case cloud.Azure:
+				return "reads the role definition directly, authenticating with the service account token"
+			}
+
+			return "runs a pod with the Google Cloud SDK as the provider service account"
+		},
+		live:         true,
+		requirements: []string{DocsAWSOIDC, DocsAzureCrossplaneMI},
+	},
+	{
+		name: CheckNameCrossplaneProviderConfig,
+		reads: func(vcloud cloud.Cloud) []string {
+			gvr := providerconfigchecker.GVR(vcloud)
+
+			return []string{fmt.Sprintf("%s.%s %s", gvr.Resource, gvr.Group, providerconfigchecker.ProviderConfigName)}
+		},
+		roleOnly:     true,
+		requirements: []string{DocsAWSOIDC, DocsAzureCrossplaneMI},
+	},
+}
+
+// Plan is the function that returns the checks that the pod runs on the cloud provider, in the order they are run.
+//
+// It returns an error if the cloud provider is unsupported.
+func Plan(vcloud cloud.Cloud, roleOnly bool) ([]PlannedCheck, error) {
+	if !slices.Contains(constSupportedClouds, vcloud) {
+		return nil, pkgerrors.NewUnsupportedCloud(vcloud)
+	}
+
+	var plan []PlannedCheck
+
+	for _, check := range constRegisteredChecks {
+		if check.clouds != nil && !slices.Contains(check.clouds, vcloud) {
+			continue
+		}
+
+		if roleOnly && !check.roleOnly {
+			continue
+		}
+
+		plan = append(plan, PlannedCheck{Name: check.name, Reads: check.reads(vcloud)})
+	}
+
+	return plan, nil
+}
+
+// constReportedChecks is the list of the names of the checks that the results are reported for, in the order they are run.
+//
+// The checks that the Crossplane role is checked with are reported together under CheckNameCrossplaneRole, as they run as a chain that stops at the first
+// failure.
+//
+// Do not modify this variable, it is supposed to be constant.
+var constReportedChecks = []string{
+	CheckNameStorageClass,
+	CheckNameNodeGroups,
+	CheckNameMySQL,
+	CheckNamePostgreSQL,
+	CheckNameTLS,
+	CheckNameSMTP,
+	CheckNameSSO,
+	CheckNameOIDCURL,
+	CheckNameCrossplaneRole,
+}
+
+// skipReason is the function that returns why the check was not run on the cloud provider, along with its description.
+func skipReason(check registeredCheck, vcloud cloud.Cloud, roleOnly bool) (report.SkipReason, string) {
+	switch {
+	case check.clouds != nil && !slices.Contains(check.clouds, vcloud):
+		return report.SkipReasonNotApplicable, fmt.Sprintf("not run on %s", vcloud)
+	case roleOnly && !check.roleOnly:
+		return report.SkipReasonUserRequested, "not run when only the Crossplane role is checked"
+	default:
+		return report.SkipReasonPrerequisiteMissing, "not run as an earlier check failed"
+	}
+}
+
+// CompleteResults is the function that returns the results along with a skipped one for each of the reported checks that has none, as it was not run,
+// so that the results describe every check whether or not it was run.
+func CompleteResults(vcloud cloud.Cloud, roleOnly bool, results []report.Result) []report.Result {
+	completed := append([]report.Result(nil), results...)
+
+	for _, check := range constRegisteredChecks {
+		if !slices.Contains(constReportedChecks, check.name) || slices.ContainsFunc(results, func(r report.Result) bool { return r.Check == check.name }) {
+			continue
+		}
+
+		reason, msg := skipReason(check, vcloud, roleOnly)
+
+		completed = append(completed, report.Result{
+			Kind:       report.KindResult,
+			Check:      check.name,
+			Status:     report.StatusSkipped,
+			Message:    msg,
+			SkipReason: reason,
+		})
+	}
+
+	return completed
+}
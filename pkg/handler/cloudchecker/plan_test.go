@@ -0,0 +1,167 @@
+// Package cloudchecker is the package that contains cloud checking related variables and constants.
+package cloudchecker
+
+import (
+	"testing"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/cloud"
+	pkgerrors "github.com/AlphaSense-Engineering/privatecloud-cli/pkg/errors"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPlan is a test that tests that the Plan function returns the checks for the cloud provider.
+//
+// nolint:funlen
+func TestPlan(t *testing.T) {
+	// constCommonChecks is the list of the names of the checks that run on every cloud provider, unless only the Crossplane role is checked.
+	//
+	// Do not modify this variable, it is supposed to be constant.
+	constCommonChecks := []string{"storage class", "node groups", "MySQL", "PostgreSQL", "TLS", "SMTP", "SSO"}
+
+	testCases := []struct {
+		name      string
+		vcloud    cloud.Cloud
+		roleOnly  bool
+		wantNames []string
+		wantReads map[string][]string
+	}{
+		{
+			name:      "AWS",
+			vcloud:    cloud.AWS,
+			wantNames: append(append([]string{}, constCommonChecks...), "OIDC URL", "provider service accounts", "JWTs", "Crossplane role", "Crossplane ProviderConfig"),
+			wantReads: map[string][]string{
+				"MySQL":                     {"Secret mysql/default-creds"},
+				"PostgreSQL":                {"Secret postgres/spicedb-creds"},
+				"TLS":                       {"Secret alphasense/default-tls"},
+				"SMTP":                      {"Secret alphasense/sender-smtp"},
+				"SSO":                       {"Secret platform/sso-config"},
+				"JWTs":                      {"ServiceAccounts crossplane/aws-*"},
+				"Crossplane ProviderConfig": {"providerconfigs.aws.upbound.io default"},
+			},
+		},
+		{
+			name:      "Azure",
+			vcloud:    cloud.Azure,
+			wantNames: append(append([]string{}, constCommonChecks...), "OIDC URL", "JWTs", "Crossplane role", "Crossplane ProviderConfig"),
+			wantReads: map[string][]string{
+				"JWTs":                      {"ServiceAccount crossplane/azure-provider-sa"},
+				"Crossplane ProviderConfig": {"providerconfigs.azure.upbound.io default"},
+			},
+		},
+		{
+			name:      "GCP",
+			vcloud:    cloud.GCP,
+			wantNames: append(append([]string{}, constCommonChecks...), "Crossplane role", "Crossplane ProviderConfig"),
+			wantReads: map[string][]string{
+				"Crossplane role":           {"Namespace crossplane", "ServiceAccount crossplane/gcp-provider-sa"},
+				"Crossplane ProviderConfig": {"providerconfigs.gcp.upbound.io default"},
+			},
+		},
+		{
+			name:      "AWS role only",
+			vcloud:    cloud.AWS,
+			roleOnly:  true,
+			wantNames: []string{"OIDC URL", "provider service accounts", "JWTs", "Crossplane role", "Crossplane ProviderConfig"},
+		},
+		{
+			name:      "GCP role only",
+			vcloud:    cloud.GCP,
+			roleOnly:  true,
+			wantNames: []string{"Crossplane role", "Crossplane ProviderConfig"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			plan, err := Plan(tc.vcloud, tc.roleOnly)
+			require.NoError(t, err)
+
+			names := make([]string, len(plan))
+
+			for i, check := range plan {
+				names[i] = check.Name
+
+				assert.NotEmpty(t, check.Reads, check.Name)
+
+				if want, ok := tc.wantReads[check.Name]; ok {
+					assert.Equal(t, want, check.Reads, check.Name)
+				}
+			}
+
+			assert.Equal(t, tc.wantNames, names)
+		})
+	}
+}
+
+// TestPlan_unsupportedCloud is a test that tests that the Plan function fails for an unsupported cloud.
+func TestPlan_unsupportedCloud(t *testing.T) {
+	plan, err := Plan(cloud.Cloud("unsupported"), false)
+
+	assert.EqualError(t, err, pkgerrors.NewUnsupportedCloud(cloud.Cloud("unsupported")).Error())
+	assert.Nil(t, plan)
+}
+
+// TestCompleteResults is a test that tests that the CompleteResults function reports the checks that were not run as skipped, telling why, and keeps the
+// results of the ones that were.
+func TestCompleteResults(t *testing.T) {
+	const (
+		// notRun is the reason of the checks that were not run as an earlier check failed.
+		notRun = "not run as an earlier check failed"
+
+		// notRunRoleOnly is the reason of the checks that were not run as only the Crossplane role is checked.
+		notRunRoleOnly = "not run when only the Crossplane role is checked"
+	)
+
+	testCases := []struct {
+		name     string
+		vcloud   cloud.Cloud
+		roleOnly bool
+		results  []report.Result
+		want     []report.Result
+	}{
+		{
+			name:   "Failed on AWS",
+			vcloud: cloud.AWS,
+			results: []report.Result{
+				{Kind: report.KindResult, Check: CheckNameStorageClass, Status: report.StatusPassed},
+				{Kind: report.KindResult, Check: CheckNameMySQL, Status: report.StatusFailed, Message: "access denied"},
+			},
+			want: []report.Result{
+				{Kind: report.KindResult, Check: CheckNameStorageClass, Status: report.StatusPassed},
+				{Kind: report.KindResult, Check: CheckNameMySQL, Status: report.StatusFailed, Message: "access denied"},
+				{Kind: report.KindResult, Check: CheckNameNodeGroups, Status: report.StatusSkipped, Message: notRun, SkipReason: report.SkipReasonPrerequisiteMissing},
+				{Kind: report.KindResult, Check: CheckNamePostgreSQL, Status: report.StatusSkipped, Message: notRun, SkipReason: report.SkipReasonPrerequisiteMissing},
+				{Kind: report.KindResult, Check: CheckNameTLS, Status: report.StatusSkipped, Message: notRun, SkipReason: report.SkipReasonPrerequisiteMissing},
+				{Kind: report.KindResult, Check: CheckNameSMTP, Status: report.StatusSkipped, Message: notRun, SkipReason: report.SkipReasonPrerequisiteMissing},
+				{Kind: report.KindResult, Check: CheckNameSSO, Status: report.StatusSkipped, Message: notRun, SkipReason: report.SkipReasonPrerequisiteMissing},
+				{Kind: report.KindResult, Check: CheckNameOIDCURL, Status: report.StatusSkipped, Message: notRun, SkipReason: report.SkipReasonPrerequisiteMissing},
+				{Kind: report.KindResult, Check: CheckNameCrossplaneRole, Status: report.StatusSkipped, Message: notRun, SkipReason: report.SkipReasonPrerequisiteMissing},
+			},
+		},
+		{
+			name:     "Role only on GCP",
+			vcloud:   cloud.GCP,
+			roleOnly: true,
+			results:  []report.Result{{Kind: report.KindResult, Check: CheckNameCrossplaneRole, Status: report.StatusPassed}},
+			want: []report.Result{
+				{Kind: report.KindResult, Check: CheckNameCrossplaneRole, Status: report.StatusPassed},
+				{Kind: report.KindResult, Check: CheckNameStorageClass, Status: report.StatusSkipped, Message: notRunRoleOnly, SkipReason: report.SkipReasonUserRequested},
+				{Kind: report.KindResult, Check: CheckNameNodeGroups, Status: report.StatusSkipped, Message: notRunRoleOnly, SkipReason: report.SkipReasonUserRequested},
+				{Kind: report.KindResult, Check: CheckNameMySQL, Status: report.StatusSkipped, Message: notRunRoleOnly, SkipReason: report.SkipReasonUserRequested},
+				{Kind: report.KindResult, Check: CheckNamePostgreSQL, Status: report.StatusSkipped, Message: notRunRoleOnly, SkipReason: report.SkipReasonUserRequested},
+				{Kind: report.KindResult, Check: CheckNameTLS, Status: report.StatusSkipped, Message: notRunRoleOnly, SkipReason: report.SkipReasonUserRequested},
+				{Kind: report.KindResult, Check: CheckNameSMTP, Status: report.StatusSkipped, Message: notRunRoleOnly, SkipReason: report.SkipReasonUserRequested},
+				{Kind: report.KindResult, Check: CheckNameSSO, Status: report.StatusSkipped, Message: notRunRoleOnly, SkipReason: report.SkipReasonUserRequested},
+				{Kind: report.KindResult, Check: CheckNameOIDCURL, Status: report.StatusSkipped, Message: "not run on gcp", SkipReason: report.SkipReasonNotApplicable},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, CompleteResults(tc.vcloud, tc.roleOnly, tc.results))
+		})
+	}
+}
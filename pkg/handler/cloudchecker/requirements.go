@@ -0,0 +1,229 @@
+// Package cloudchecker is the package that contains cloud checking related variables and constants.
+package cloudchecker
+
+import (
+	"slices"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/cloud"
+	pkgerrors "github.com/AlphaSense-Engineering/privatecloud-cli/pkg/errors"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/report"
+)
+
+// The URLs of the sections of the technical requirements documentation, which the requirements and the documentation related to the failures share.
+const (
+	// DocsTechnicalRequirements is the URL to the technical requirements documentation, which lists every requirement.
+	DocsTechnicalRequirements = "https://developer.alpha-sense.com/enterprise/technical-requirements"
+
+	// DocsPersistentVolumes is the URL to the documentation for persistent volumes.
+	DocsPersistentVolumes = "https://developer.alpha-sense.com/enterprise/technical-requirements/#persistent-volumes"
+
+	// DocsMySQLDatabaseCluster is the URL to the documentation for MySQL database cluster.
+	DocsMySQLDatabaseCluster = "https://developer.alpha-sense.com/enterprise/technical-requirements/#mysql-database-cluster"
+
+	// DocsMySQLSecrets is the URL to the documentation for MySQL secrets.
+	//
+	// nolint:gosec
+	DocsMySQLSecrets = "https://developer.alpha-sense.com/enterprise/technical-requirements/#mysql-secrets"
+
+	// DocsPostgreSQLDatabaseCluster is the URL to the documentation for PostgreSQL database cluster.
+	DocsPostgreSQLDatabaseCluster = "https://developer.alpha-sense.com/enterprise/technical-requirements/#postgresql-database-cluster"
+
+	// DocsPostgreSQLSecrets is the URL to the documentation for PostgreSQL secrets.
+	//
+	// nolint:gosec
+	DocsPostgreSQLSecrets = "https://developer.alpha-sense.com/enterprise/technical-requirements/#postgresql-secrets"
+
+	// DocsTLSSecrets is the URL to the documentation for TLS secrets.
+	//
+	// nolint:gosec
+	DocsTLSSecrets = "https://developer.alpha-sense.com/enterprise/technical-requirements/#tls-secrets"
+
+	// DocsSMTPSecrets is the URL to the documentation for SMTP secrets.
+	//
+	// nolint:gosec
+	DocsSMTPSecrets = "https://developer.alpha-sense.com/enterprise/technical-requirements/#smtp-credentials-for-email-sending"
+
+	// DocsSSOSecrets is the URL to the documentation for SSO secrets.
+	//
+	// nolint:gosec
+	DocsSSOSecrets = "https://developer.alpha-sense.com/enterprise/technical-requirements/#sso-secret"
+
+	// DocsAWSOIDC is the URL to the documentation for AWS OIDC.
+	DocsAWSOIDC = "https://developer.alpha-sense.com/enterprise/technical-requirements/aws#oidc-provider-for-iam-role-for-service-account"
+
+	// DocsAzureCrossplaneMI is the URL to the documentation for Azure Crossplane Managed Identity.
+	DocsAzureCrossplaneMI = "https://developer.alpha-sense.com/enterprise/technical-requirements/azure#crossplane-managed-identity"
+)
+
+// requirement is the type that contains a section of the technical requirements documentation.
+type requirement struct {
+	// name is the name of the section.
+	name string
+	// url is the URL of the section, which the checks refer to the requirement with.
+	url string
+	// clouds is the list of the cloud providers the requirement applies to, or nil if it applies to all of them.
+	clouds []cloud.Cloud
+}
+
+// constRequirements is the list of the sections of the technical requirements documentation that the failures of the checks link to, in the order they
+// are listed there.
+//
+// The sections that are not listed here, e.g. the permissions of the Crossplane role, are not mapped to the checks, and a requirement that none of the
+// registered checks refers to is reported as not checked.
+//
+// Do not modify this variable, it is supposed to be constant.
+var constRequirements = []requirement{
+	{name: "Persistent volumes", url: DocsPersistentVolumes},
+	{name: "MySQL database cluster", url: DocsMySQLDatabaseCluster},
+	{name: "MySQL secrets", url: DocsMySQLSecrets},
+	{name: "PostgreSQL database cluster", url: DocsPostgreSQLDatabaseCluster},
+	{name: "PostgreSQL secrets", url: DocsPostgreSQLSecrets},
+	{name: "TLS secrets", url: DocsTLSSecrets},
+	{name: "SMTP credentials for email sending", url: DocsSMTPSecrets},
+	{name: "SSO secret", url: DocsSSOSecrets},
+	{name: "OIDC provider for IAM role for service account", url: DocsAWSOIDC, clouds: []cloud.Cloud{cloud.AWS}},
+	{name: "Crossplane managed identity", url: DocsAzureCrossplaneMI, clouds: []cloud.Cloud{cloud.Azure}},
+}
+
+// RequirementStatus is the type of the status of a requirement.
+type RequirementStatus string
+
+const (
+	// RequirementStatusValidated is the status of a requirement that the checks validate, without the results of a run.
+	RequirementStatusValidated RequirementStatus = "validated"
+	// RequirementStatusPassed is the status of a requirement whose checks all passed, with or without a warning.
+	RequirementStatusPassed RequirementStatus = "passed"
+	// RequirementStatusFailed is the status of a requirement of which a check failed.
+	RequirementStatusFailed RequirementStatus = "failed"
+	// RequirementStatusSkipped is the status of a requirement of which a check was not run, and none failed.
+	RequirementStatusSkipped RequirementStatus = "skipped"
+	// RequirementStatusNotChecked is the status of a requirement that none of the checks validate.
+	RequirementStatusNotChecked RequirementStatus = "not checked"
+)
+
+// RequirementCoverage is the type that describes whether and by which checks a requirement is validated on a cloud provider.
+type RequirementCoverage struct {
+	// Name is the name of the section of the technical requirements documentation.
+	Name string `json:"name"`
+	// URL is the URL of the section.
+	URL string `json:"url"`
+	// Checks is the list of the names of the checks that validate the requirement, in the order they are run.
+	Checks []string `json:"checks"`
+	// Status is the status of the requirement.
+	Status RequirementStatus `json:"status"`
+}
+
+// CloudCoverage is the type that describes the coverage of the requirements of a cloud provider.
+type CloudCoverage struct {
+	// Cloud is the cloud provider.
+	Cloud cloud.Cloud `json:"cloud"`
+	// Requirements is the list of the requirements that apply to the cloud provider, in the order they are listed in the documentation.
+	Requirements []RequirementCoverage `json:"requirements"`
+	// Checked is the number of the requirements that the checks validate.
+	Checked int `json:"checked"`
+	// Total is the number of the requirements.
+	Total int `json:"total"`
+}
+
+// reportedCheck is the function that returns the name of the check whose results are reported for the check, as the checks that the Crossplane role is
+// checked with are reported together.
+func reportedCheck(name string) string {
+	if slices.Contains(constReportedChecks, name) {
+		return name
+	}
+
+	return CheckNameCrossplaneRole
+}
+
+// requirementStatus is the function that returns the status of the requirement that the checks validate, from the results of the checks, or
+// RequirementStatusValidated if there are none.
+//
+// The requirement passed only if all of its checks passed.
+func requirementStatus(checks []string, results []report.Result) RequirementStatus {
+	if len(checks) == 0 {
+		return RequirementStatusNotChecked
+	}
+
+	if results == nil {
+		return RequirementStatusValidated
+	}
+
+	var passed, skipped, failed bool
+
+	for _, r := range results {
+		if !slices.ContainsFunc(checks, func(check string) bool { return reportedCheck(check) == r.Check }) {
+			continue
+		}
+
+		switch r.Status {
+		case report.StatusPassed, report.StatusWarning:
+			passed = true
+		case report.StatusSkipped:
+			skipped = true
+		case report.StatusFailed:
+			failed = true
+		}
+	}
+
+	switch {
+	case failed:
+		return RequirementStatusFailed
+	case skipped:
+		return RequirementStatusSkipped
+	case passed:
+		return RequirementStatusPassed
+	default:
+		return RequirementStatusValidated
+	}
+}
+
+// RunCoverage is the function that returns the coverage of the requirements of the cloud provider, with the status of each from the results of a run, or
+// without them if the results are nil.
+//
+// It is derived from the same registry as Plan, so that it cannot diverge from what the pod actually does.
+// It returns an error if the cloud provider is unsupported.
+func RunCoverage(vcloud cloud.Cloud, results []report.Result) (CloudCoverage, error) {
+	if !slices.Contains(constSupportedClouds, vcloud) {
+		return CloudCoverage{}, pkgerrors.NewUnsupportedCloud(vcloud)
+	}
+
+	coverage := CloudCoverage{Cloud: vcloud, Requirements: []RequirementCoverage{}}
+
+	for _, req := range constRequirements {
+		if req.clouds != nil && !slices.Contains(req.clouds, vcloud) {
+			continue
+		}
+
+		checks := []string{}
+
+		for _, check := range constRegisteredChecks {
+			if (check.clouds == nil || slices.Contains(check.clouds, vcloud)) && slices.Contains(check.requirements, req.url) {
+				checks = append(checks, check.name)
+			}
+		}
+
+		status := requirementStatus(checks, results)
+
+		if status != RequirementStatusNotChecked {
+			coverage.Checked++
+		}
+
+		coverage.Total++
+		coverage.Requirements = append(coverage.Requirements, RequirementCoverage{Name: req.name, URL: req.url, Checks: checks, Status: status})
+	}
+
+	return coverage, nil
+}
+
+// Coverage is the function that returns, for every supported cloud provider, the coverage of its requirements by the checks that the pod may run.
+func Coverage() []CloudCoverage {
+	coverages := make([]CloudCoverage, 0, len(constSupportedClouds))
+
+	for _, vcloud := range constSupportedClouds {
+		coverage, _ := RunCoverage(vcloud, nil)
+
+		coverages = append(coverages, coverage)
+	}
+
+	return coverages
+}
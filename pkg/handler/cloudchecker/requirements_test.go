@@ -0,0 +1,149 @@
+// Package cloudchecker is the package that contains cloud checking related variables and constants.
+package cloudchecker
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/cloud"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRegisteredChecks_requirements is a test that tests that every registered check validates a requirement on each of the cloud providers it runs on,
+// apart from the ones the documentation has no section for, and that the checks only refer to the requirements that are listed.
+func TestRegisteredChecks_requirements(t *testing.T) {
+	// unlisted is the list of the cloud providers, per check, on which the check validates none of the listed sections, as the documentation has none
+	// for what it checks there.
+	unlisted := map[string][]cloud.Cloud{
+		CheckNameNodeGroups:               constSupportedClouds,
+		CheckNameCrossplaneRole:           {cloud.GCP},
+		CheckNameCrossplaneProviderConfig: {cloud.GCP},
+	}
+
+	for _, check := range constRegisteredChecks {
+		t.Run(check.name, func(t *testing.T) {
+			if len(unlisted[check.name]) < len(constSupportedClouds) {
+				require.NotEmpty(t, check.requirements)
+			}
+
+			for _, url := range check.requirements {
+				assert.True(t, slices.ContainsFunc(constRequirements, func(req requirement) bool { return req.url == url }), url)
+			}
+
+			for _, vcloud := range constSupportedClouds {
+				if check.clouds != nil && !slices.Contains(check.clouds, vcloud) || slices.Contains(unlisted[check.name], vcloud) {
+					continue
+				}
+
+				coverage, err := RunCoverage(vcloud, nil)
+				require.NoError(t, err)
+
+				assert.True(t, slices.ContainsFunc(coverage.Requirements, func(req RequirementCoverage) bool {
+					return slices.Contains(req.Checks, check.name)
+				}), vcloud)
+			}
+		})
+	}
+}
+
+// TestRunCoverage is a test that tests that the RunCoverage function reports the status of each requirement from the results of the checks that validate
+// it.
+func TestRunCoverage(t *testing.T) {
+	testCases := []struct {
+		name    string
+		vcloud  cloud.Cloud
+		results []report.Result
+		want    map[string]RequirementStatus
+		wantErr bool
+	}{
+		{
+			name:   "No results",
+			vcloud: cloud.AWS,
+			want: map[string]RequirementStatus{
+				DocsPersistentVolumes: RequirementStatusValidated,
+				DocsAWSOIDC:           RequirementStatusValidated,
+			},
+		},
+		{
+			name:   "Results",
+			vcloud: cloud.Azure,
+			results: []report.Result{
+				{Check: CheckNameStorageClass, Status: report.StatusWarning},
+				{Check: CheckNameMySQL, Status: report.StatusFailed},
+				{Check: CheckNameOIDCURL, Status: report.StatusPassed},
+				{Check: CheckNameCrossplaneRole, Status: report.StatusSkipped},
+			},
+			want: map[string]RequirementStatus{
+				DocsPersistentVolumes:    RequirementStatusPassed,
+				DocsMySQLDatabaseCluster: RequirementStatusFailed,
+				DocsMySQLSecrets:         RequirementStatusFailed,
+				DocsTLSSecrets:           RequirementStatusValidated,
+				DocsAzureCrossplaneMI:    RequirementStatusSkipped,
+			},
+		},
+		{
+			name:    "Unsupported cloud",
+			vcloud:  cloud.Cloud("unsupported"),
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			coverage, err := RunCoverage(tc.vcloud, tc.results)
+
+			if tc.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.vcloud, coverage.Cloud)
+			assert.Equal(t, len(coverage.Requirements), coverage.Total)
+
+			got := map[string]RequirementStatus{}
+
+			for _, req := range coverage.Requirements {
+				got[req.URL] = req.Status
+			}
+
+			for url, status := range tc.want {
+				assert.Equal(t, status, got[url], url)
+			}
+		})
+	}
+}
+
+// TestRequirementStatus_notChecked is a test that tests that the requirementStatus function reports the requirement that no check validates as not
+// checked, with or without the results of a run.
+func TestRequirementStatus_notChecked(t *testing.T) {
+	assert.Equal(t, RequirementStatusNotChecked, requirementStatus(nil, nil))
+	assert.Equal(t, RequirementStatusNotChecked, requirementStatus([]string{}, []report.Result{{Check: CheckNameTLS, Status: report.StatusFailed}}))
+}
+
+// TestCoverage is a test that tests that the Coverage function only reports the requirements that apply to each cloud provider, all of which the checks
+// validate.
+func TestCoverage(t *testing.T) {
+	coverages := Coverage()
+
+	require.Len(t, coverages, len(constSupportedClouds))
+
+	for _, c := range coverages {
+		t.Run(string(c.Cloud), func(t *testing.T) {
+			var urls []string
+
+			for _, req := range c.Requirements {
+				assert.Equal(t, RequirementStatusValidated, req.Status, req.URL)
+
+				urls = append(urls, req.URL)
+			}
+
+			assert.Equal(t, c.Cloud == cloud.AWS, slices.Contains(urls, DocsAWSOIDC))
+			assert.Equal(t, c.Cloud == cloud.Azure, slices.Contains(urls, DocsAzureCrossplaneMI))
+			assert.Equal(t, c.Total, c.Checked)
+		})
+	}
+}
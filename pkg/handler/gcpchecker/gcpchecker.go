@@ -4,43 +4,35 @@ package gcpchecker
 import (
 	"context"
 
-	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/envconfig"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/crossplanerolechecker"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/gcpcrossplanerolechecker"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/providerconfigchecker"
 	"github.com/charmbracelet/log"
-	"k8s.io/client-go/kubernetes"
+	"go.uber.org/multierr"
 )
 
 // GCPChecker is the type that contains the infrastructure check functions for GCP.
 type GCPChecker struct {
+	// checkCtx is the check context, passed to the checkers it sets up.
+	checkCtx handler.CheckContext
+
 	// logger is the logger.
 	logger *log.Logger
-	// envConfig is the environment configuration.
-	envConfig *envconfig.EnvConfig
-	// clientset is the Kubernetes client.
-	clientset kubernetes.Interface
-
-	// googleCloudSDKDockerRepo is the Docker repository for the Google Cloud SDK.
-	googleCloudSDKDockerRepo string
-	// googleCloudSDKDockerImage is the Docker image for the Google Cloud SDK.
-	googleCloudSDKDockerImage string
 
 	// crossplaneRoleChecker is the GCP Crossplane role checker.
 	crossplaneRoleChecker *gcpcrossplanerolechecker.GCPCrossplaneRoleChecker
+	// providerConfigChecker is the Crossplane ProviderConfig checker.
+	providerConfigChecker *providerconfigchecker.ProviderConfigChecker
 }
 
 var _ handler.Handler = &GCPChecker{}
 
 // setup is the function that sets up the GCP checker.
 func (c *GCPChecker) setup() {
-	c.crossplaneRoleChecker = gcpcrossplanerolechecker.New(
-		c.logger,
-		c.envConfig,
-		c.clientset,
-		c.googleCloudSDKDockerRepo,
-		c.googleCloudSDKDockerImage,
-	)
+	c.crossplaneRoleChecker = gcpcrossplanerolechecker.New(c.checkCtx)
+
+	c.providerConfigChecker = providerconfigchecker.New(c.checkCtx)
 }
 
 // Handle is the function that handles the infrastructure check.
@@ -54,24 +46,28 @@ func (c *GCPChecker) Handle(ctx context.Context, _ ...any) ([]any, error) {
 
 	c.logger.Info(crossplanerolechecker.LogMsgCrossplaneRoleCheckedSuccessfully)
 
+	if _, err := c.providerConfigChecker.Handle(ctx); err != nil {
+		return nil, multierr.Combine(providerconfigchecker.ErrFailedToCheckProviderConfig, err)
+	}
+
+	c.logger.Info(providerconfigchecker.LogMsgProviderConfigCheckedSuccessfully)
+
 	return nil, nil
 }
 
+// Contract is the function that returns the contract of the results of the infrastructure check.
+//
+// It returns nothing.
+func (c *GCPChecker) Contract() handler.Contract {
+	return handler.Contract{}
+}
+
 // New is the function that creates a new GCPChecker.
-func New(
-	logger *log.Logger,
-	envConfig *envconfig.EnvConfig,
-	clientset kubernetes.Interface,
-	googleCloudSDKDockerRepo string,
-	googleCloudSDKDockerImage string,
-) *GCPChecker {
+func New(checkCtx handler.CheckContext) *GCPChecker {
 	c := &GCPChecker{
-		logger:    logger,
-		envConfig: envConfig,
-		clientset: clientset,
+		checkCtx: checkCtx,
 
-		googleCloudSDKDockerRepo:  googleCloudSDKDockerRepo,
-		googleCloudSDKDockerImage: googleCloudSDKDockerImage,
+		logger: checkCtx.Logger,
 	}
 
 	c.setup()
@@ -3,14 +3,20 @@ package gcpcrossplanerolechecker
 
 import (
 	"context"
+	_ "embed"
 	"errors"
+	"slices"
 	"strings"
 
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/clock"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/cloud/gcpcloudutil"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/envconfig"
 	pkgerrors "github.com/AlphaSense-Engineering/privatecloud-cli/pkg/errors"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/podsecuritychecker"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/k8s/kubeutil"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/util"
 	"github.com/charmbracelet/log"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
@@ -57,175 +63,136 @@ echo "No uxp_provider role found" >&2
 exit 1`
 )
 
-// constExpectedRolePermissions are the expected permissions for the Crossplane role in GCP.
+// expectedRolePermissionsFile is the embedded list of the expected permissions for the Crossplane role in GCP.
+//
+//go:embed expectedpermissions.txt
+var expectedRolePermissionsFile []byte
+
+// constExpectedRolePermissions are the expected permissions for the Crossplane role in GCP, loaded from the embedded list.
 //
 // These are listed at https://developer.alpha-sense.com/enterprise/technical-requirements/gcp.
 //
 // Do not modify this variable, it is supposed to be constant.
-var constExpectedRolePermissions = map[string]struct{}{
-	"cloudsql.backupRuns.create":            {},
-	"cloudsql.backupRuns.delete":            {},
-	"cloudsql.backupRuns.get":               {},
-	"cloudsql.backupRuns.list":              {},
-	"cloudsql.instances.addServerCa":        {},
-	"cloudsql.instances.clone":              {},
-	"cloudsql.instances.connect":            {},
-	"cloudsql.instances.create":             {},
-	"cloudsql.instances.createTagBinding":   {},
-	"cloudsql.instances.delete":             {},
-	"cloudsql.instances.deleteTagBinding":   {},
-	"cloudsql.instances.export":             {},
-	"cloudsql.instances.failover":           {},
-	"cloudsql.instances.get":                {},
-	"cloudsql.instances.import":             {},
-	"cloudsql.instances.list":               {},
-	"cloudsql.instances.listEffectiveTags":  {},
-	"cloudsql.instances.listTagBindings":    {},
-	"cloudsql.instances.resetSslConfig":     {},
-	"cloudsql.instances.restart":            {},
-	"cloudsql.instances.restoreBackup":      {},
-	"cloudsql.instances.update":             {},
-	"cloudsql.users.create":                 {},
-	"cloudsql.users.delete":                 {},
-	"cloudsql.users.get":                    {},
-	"cloudsql.users.list":                   {},
-	"cloudsql.users.update":                 {},
-	"iam.roles.create":                      {},
-	"iam.roles.delete":                      {},
-	"iam.roles.get":                         {},
-	"iam.roles.list":                        {},
-	"iam.roles.undelete":                    {},
-	"iam.roles.update":                      {},
-	"iam.serviceAccountKeys.create":         {},
-	"iam.serviceAccountKeys.delete":         {},
-	"iam.serviceAccountKeys.disable":        {},
-	"iam.serviceAccountKeys.enable":         {},
-	"iam.serviceAccountKeys.get":            {},
-	"iam.serviceAccountKeys.list":           {},
-	"iam.serviceAccounts.create":            {},
-	"iam.serviceAccounts.delete":            {},
-	"iam.serviceAccounts.disable":           {},
-	"iam.serviceAccounts.enable":            {},
-	"iam.serviceAccounts.get":               {},
-	"iam.serviceAccounts.getIamPolicy":      {},
-	"iam.serviceAccounts.list":              {},
-	"iam.serviceAccounts.setIamPolicy":      {},
-	"iam.serviceAccounts.undelete":          {},
-	"iam.serviceAccounts.update":            {},
-	"pubsub.subscriptions.create":           {},
-	"pubsub.subscriptions.delete":           {},
-	"pubsub.subscriptions.get":              {},
-	"pubsub.subscriptions.getIamPolicy":     {},
-	"pubsub.subscriptions.list":             {},
-	"pubsub.subscriptions.setIamPolicy":     {},
-	"pubsub.subscriptions.update":           {},
-	"pubsub.topics.attachSubscription":      {},
-	"pubsub.topics.create":                  {},
-	"pubsub.topics.delete":                  {},
-	"pubsub.topics.detachSubscription":      {},
-	"pubsub.topics.get":                     {},
-	"pubsub.topics.getIamPolicy":            {},
-	"pubsub.topics.list":                    {},
-	"pubsub.topics.setIamPolicy":            {},
-	"pubsub.topics.update":                  {},
-	"pubsub.topics.updateTag":               {},
-	"redis.instances.create":                {},
-	"redis.instances.delete":                {},
-	"redis.instances.export":                {},
-	"redis.instances.get":                   {},
-	"redis.instances.getAuthString":         {},
-	"redis.instances.import":                {},
-	"redis.instances.list":                  {},
-	"redis.instances.update":                {},
-	"redis.instances.updateAuth":            {},
-	"redis.instances.upgrade":               {},
-	"redis.locations.get":                   {},
-	"redis.locations.list":                  {},
-	"redis.operations.delete":               {},
-	"redis.operations.get":                  {},
-	"redis.operations.list":                 {},
-	"resourcemanager.projects.get":          {},
-	"resourcemanager.projects.getIamPolicy": {},
-	"resourcemanager.projects.setIamPolicy": {},
-	"storage.buckets.create":                {},
-	"storage.buckets.createTagBinding":      {},
-	"storage.buckets.delete":                {},
-	"storage.buckets.deleteTagBinding":      {},
-	"storage.buckets.enableObjectRetention": {},
-	"storage.buckets.get":                   {},
-	"storage.buckets.getIamPolicy":          {},
-	"storage.buckets.list":                  {},
-	"storage.buckets.listEffectiveTags":     {},
-	"storage.buckets.listTagBindings":       {},
-	"storage.buckets.setIamPolicy":          {},
-	"storage.buckets.update":                {},
-	"storage.hmacKeys.create":               {},
-	"storage.hmacKeys.delete":               {},
-	"storage.hmacKeys.get":                  {},
-	"storage.hmacKeys.list":                 {},
-	"storage.hmacKeys.update":               {},
-}
+var constExpectedRolePermissions = util.ParsePermissions(expectedRolePermissionsFile)
 
 // GCPCrossplaneRoleChecker is the type that contains the check functions for GCP Crossplane role.
 type GCPCrossplaneRoleChecker struct {
+	// checkCtx is the check context, passed to the pod security checker.
+	checkCtx handler.CheckContext
+
 	// logger is the logger.
 	logger *log.Logger
 	// envConfig is the environment configuration.
 	envConfig *envconfig.EnvConfig
 	// clientset is the Kubernetes client.
 	clientset kubernetes.Interface
+	// clock is the clock that the pod is polled on.
+	clock clock.Clock
+	// namespace is the namespace of the service account of the Crossplane provider, which the pod runs in.
+	namespace string
 
 	// googleCloudSDKDockerRepo is the Docker repository for the Google Cloud SDK.
 	googleCloudSDKDockerRepo string
 	// googleCloudSDKDockerImage is the Docker image for the Google Cloud SDK.
 	googleCloudSDKDockerImage string
+	// podSecurityProfile is the requested security profile of the pod.
+	podSecurityProfile string
+
+	// expectedRolePermissions is the set of the expected permissions, with the override merged in.
+	expectedRolePermissions map[string]struct{}
 }
 
 var _ handler.Handler = &GCPCrossplaneRoleChecker{}
 
-// Handle is the function that handles the GCP Crossplane role check.
-//
-// The arguments are not used.
-// It returns nothing on success, or an error on failure.
-//
-// nolint:funlen
-func (c *GCPCrossplaneRoleChecker) Handle(ctx context.Context, _ ...any) ([]any, error) {
+// buildPod is the function that builds the pod that checks the GCP Crossplane role, with the given pod security profile.
+func (c *GCPCrossplaneRoleChecker) buildPod(podSecurityProfile string) (*corev1.Pod, error) {
+	const (
+		// envVarCloudSDKConfig is the name of the environment variable that sets the configuration directory of the Google Cloud SDK.
+		envVarCloudSDKConfig = "CLOUDSDK_CONFIG"
+
+		// pathCloudSDKConfig is the configuration directory of the Google Cloud SDK, which is writable by any user, unlike the home directory of the
+		// user the pod runs as under the restricted pod security profile.
+		pathCloudSDKConfig = "/tmp/gcloud"
+	)
+
 	pod := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      podName,
-			Namespace: constant.NamespaceCrossplane,
+			Namespace: c.namespace,
 		},
 		Spec: corev1.PodSpec{
 			ServiceAccountName: constant.ServiceAccountNameGCP,
 			Containers: []corev1.Container{{
-				Name: podName,
-				Image: strings.Join(
-					[]string{
-						c.googleCloudSDKDockerRepo,
-						c.googleCloudSDKDockerImage,
-					},
-					string(constant.HTTPPathSeparator),
-				),
+				Name:            podName,
+				Image:           gcpcloudutil.ImageRef(c.googleCloudSDKDockerRepo, c.googleCloudSDKDockerImage),
 				ImagePullPolicy: corev1.PullAlways,
 				Command: []string{
 					"/bin/bash",
 					"-c",
 					bashScript,
-				}},
-			},
+				},
+				Env: []corev1.EnvVar{{Name: envVarCloudSDKConfig, Value: pathCloudSDKConfig}},
+			}},
 			RestartPolicy: corev1.RestartPolicyNever,
 		},
 	}
 
-	clientsetPod := c.clientset.CoreV1().Pods(constant.NamespaceCrossplane)
+	if err := kubeutil.ApplyPodSecurityProfile(&pod.Spec, podSecurityProfile); err != nil {
+		return nil, err
+	}
+
+	return pod, nil
+}
+
+// missingPermissions is a function that returns the expected permissions that are not granted, sorted so that the error lists them in a stable order.
+func missingPermissions(expected map[string]struct{}, granted []string) []string {
+	grantedSet := make(map[string]struct{}, len(granted))
+
+	for _, permission := range granted {
+		grantedSet[permission] = struct{}{}
+	}
+
+	missing := []string{}
 
-	_, err := clientsetPod.Get(ctx, podName, metav1.GetOptions{})
+	for permission := range expected {
+		if _, ok := grantedSet[permission]; !ok {
+			missing = append(missing, permission)
+		}
+	}
+
+	slices.Sort(missing)
+
+	return missing
+}
+
+// Handle is the function that handles the GCP Crossplane role check.
+//
+// The arguments are not used.
+// It returns nothing on success, or an error on failure.
+//
+// nolint:funlen
+func (c *GCPCrossplaneRoleChecker) Handle(ctx context.Context, _ ...any) ([]any, error) {
+	podSecurityProfile, err := util.UnwrapValErr[string](
+		podsecuritychecker.New(c.checkCtx, c.namespace).Handle(ctx, c.podSecurityProfile),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	pod, err := c.buildPod(podSecurityProfile)
+	if err != nil {
+		return nil, err
+	}
+
+	clientsetPod := c.clientset.CoreV1().Pods(c.namespace)
+
+	_, err = clientsetPod.Get(ctx, podName, metav1.GetOptions{})
 	if err == nil {
 		if err := clientsetPod.Delete(ctx, podName, metav1.DeleteOptions{}); err != nil {
 			return nil, err
 		}
 
-		c.logger.Debugf(constant.LogMsgPodDeleted, constant.NamespaceCrossplane, podName)
+		c.logger.Debugf(constant.LogMsgPodDeleted, c.namespace, podName)
 	} else if !k8serrors.IsNotFound(err) {
 		return nil, err
 	}
@@ -234,14 +201,14 @@ func (c *GCPCrossplaneRoleChecker) Handle(ctx context.Context, _ ...any) ([]any,
 		return nil, err
 	}
 
-	c.logger.Debugf(constant.LogMsgPodCreated, constant.NamespaceCrossplane, podName)
+	c.logger.Debugf(constant.LogMsgPodCreated, c.namespace, podName)
 
-	phase, err := kubeutil.WaitForPodToSucceedOrFail(ctx, c.logger, c.clientset, constant.NamespaceCrossplane, podName)
+	phase, err := kubeutil.WaitForPodToSucceedOrFail(ctx, c.logger, c.clock, c.clientset, c.namespace, podName)
 	if err != nil {
 		return nil, err
 	}
 
-	logs, err := kubeutil.PodLogs(ctx, c.logger, c.clientset, constant.NamespaceCrossplane, podName)
+	logs, err := kubeutil.PodLogs(ctx, c.logger, c.clientset, c.namespace, podName)
 	if err != nil {
 		return nil, err
 	}
@@ -256,53 +223,44 @@ func (c *GCPCrossplaneRoleChecker) Handle(ctx context.Context, _ ...any) ([]any,
 		return nil, errors.New(logLine)
 	}
 
-	permissions := strings.Split(logLine, ";")
-
-	missingPermissions := []string{}
-
-	for expectedPermission := range constExpectedRolePermissions {
-		found := false
-
-		for _, permission := range permissions {
-			if permission == expectedPermission {
-				found = true
-
-				break
-			}
-		}
-
-		if !found {
-			missingPermissions = append(missingPermissions, expectedPermission)
-		}
-	}
-
-	if len(missingPermissions) > 0 {
-		return nil, pkgerrors.NewRoleMissingPermissions(missingPermissions)
+	if missing := missingPermissions(c.expectedRolePermissions, strings.Split(logLine, ";")); len(missing) > 0 {
+		return nil, pkgerrors.NewRoleMissingPermissions(missing)
 	}
 
 	if err := clientsetPod.Delete(ctx, podName, metav1.DeleteOptions{}); err != nil {
 		return nil, err
 	}
 
-	c.logger.Debugf(constant.LogMsgPodDeleted, constant.NamespaceCrossplane, podName)
+	c.logger.Debugf(constant.LogMsgPodDeleted, c.namespace, podName)
 
 	return nil, nil
 }
 
+// Contract is the function that returns the contract of the results of the GCP Crossplane role check.
+//
+// It returns nothing.
+func (c *GCPCrossplaneRoleChecker) Contract() handler.Contract {
+	return handler.Contract{}
+}
+
 // New is the function that creates a new GCPCrossplaneRoleChecker.
-func New(
-	logger *log.Logger,
-	envConfig *envconfig.EnvConfig,
-	clientset kubernetes.Interface,
-	googleCloudSDKDockerRepo string,
-	googleCloudSDKDockerImage string,
-) *GCPCrossplaneRoleChecker {
+//
+// The pod runs as the service account of the Crossplane provider in the namespace of the options, or else in the crossplane namespace, and is polled on
+// the clock of the context, or else on the real one.
+func New(checkCtx handler.CheckContext) *GCPCrossplaneRoleChecker {
 	return &GCPCrossplaneRoleChecker{
-		logger:    logger,
-		envConfig: envConfig,
-		clientset: clientset,
+		checkCtx: checkCtx,
+
+		logger:    checkCtx.Logger,
+		envConfig: checkCtx.EnvConfig,
+		clientset: checkCtx.Clientset,
+		clock:     checkCtx.ClockOrReal(),
+		namespace: checkCtx.Options.ProviderServiceAccountsNamespace(),
+
+		googleCloudSDKDockerRepo:  checkCtx.Options.GoogleCloudSDKDockerRepo,
+		googleCloudSDKDockerImage: checkCtx.Options.GoogleCloudSDKDockerImage,
+		podSecurityProfile:        checkCtx.Options.PodSecurityProfile,
 
-		googleCloudSDKDockerRepo:  googleCloudSDKDockerRepo,
-		googleCloudSDKDockerImage: googleCloudSDKDockerImage,
+		expectedRolePermissions: util.MergePermissions(constExpectedRolePermissions, checkCtx.Options.ExpectedPermissionsOverride),
 	}
 }
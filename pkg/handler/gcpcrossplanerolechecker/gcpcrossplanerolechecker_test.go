@@ -0,0 +1,133 @@
+// Package gcpcrossplanerolechecker is the package that contains the check functions for GCP Crossplane role.
+package gcpcrossplanerolechecker
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/clock"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/envconfig"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/k8s/kubeutil"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/util"
+	"github.com/charmbracelet/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// TestGCPCrossplaneRoleChecker_buildPod is a test that tests that the buildPod function hardens the pod under the restricted pod security profile.
+func TestGCPCrossplaneRoleChecker_buildPod(t *testing.T) {
+	c := New(handler.CheckContext{
+		Logger:    log.New(io.Discard),
+		EnvConfig: &envconfig.EnvConfig{},
+		Clientset: fake.NewClientset(),
+		Options: handler.CheckOptions{
+			GoogleCloudSDKDockerRepo:  "google",
+			GoogleCloudSDKDockerImage: "cloud-sdk:latest",
+			PodSecurityProfile:        kubeutil.PodSecurityProfileRestricted,
+		},
+	})
+
+	pod, err := c.buildPod(kubeutil.PodSecurityProfileRestricted)
+	require.NoError(t, err)
+
+	require.NotNil(t, pod.Spec.SecurityContext)
+	assert.True(t, *pod.Spec.SecurityContext.RunAsNonRoot)
+	assert.Equal(t, corev1.SeccompProfileTypeRuntimeDefault, pod.Spec.SecurityContext.SeccompProfile.Type)
+
+	require.Len(t, pod.Spec.Containers, 1)
+
+	container := pod.Spec.Containers[0]
+
+	assert.Equal(t, "google/cloud-sdk:latest", container.Image)
+	require.NotNil(t, container.SecurityContext)
+	assert.False(t, *container.SecurityContext.AllowPrivilegeEscalation)
+	assert.Equal(t, []corev1.Capability{"ALL"}, container.SecurityContext.Capabilities.Drop)
+
+	// The Google Cloud SDK configuration directory must be writable by the non-root user.
+	assert.Contains(t, container.Env, corev1.EnvVar{Name: "CLOUDSDK_CONFIG", Value: "/tmp/gcloud"})
+}
+
+// TestConstExpectedRolePermissions is a test that tests that the embedded list of the expected permissions loads, without comments or duplicates.
+func TestConstExpectedRolePermissions(t *testing.T) {
+	assert.NotEmpty(t, constExpectedRolePermissions)
+	assert.Contains(t, constExpectedRolePermissions, "iam.serviceAccounts.create")
+
+	seen := make(map[string]struct{}, len(constExpectedRolePermissions))
+
+	for _, permission := range constExpectedRolePermissions {
+		assert.NotContains(t, permission, "#")
+		assert.NotContains(t, seen, permission, "duplicate permission %s", permission)
+
+		seen[permission] = struct{}{}
+	}
+}
+
+// TestNew_expectedRolePermissionsOverride is a test that tests that the override of the expected permissions is merged with the embedded ones.
+func TestNew_expectedRolePermissionsOverride(t *testing.T) {
+	c := New(handler.CheckContext{
+		Logger:  log.New(io.Discard),
+		Options: handler.CheckOptions{ExpectedPermissionsOverride: []string{"redis.instances.failover", "-storage.hmacKeys.update"}},
+	})
+
+	assert.Len(t, c.expectedRolePermissions, len(constExpectedRolePermissions))
+	assert.Contains(t, c.expectedRolePermissions, "redis.instances.failover")
+	assert.NotContains(t, c.expectedRolePermissions, "storage.hmacKeys.update")
+}
+
+// TestNew_clock is a test that tests that the pod is polled on the clock of the context, or else on the real one.
+func TestNew_clock(t *testing.T) {
+	fakeClock := clock.NewFake(time.Date(2026, time.October, 14, 0, 0, 0, 0, time.UTC))
+
+	assert.Same(t, fakeClock, New(handler.CheckContext{Logger: log.New(io.Discard), Clock: fakeClock}).clock)
+	assert.Equal(t, clock.Real{}, New(handler.CheckContext{Logger: log.New(io.Discard)}).clock)
+}
+
+// Test_missingPermissions is a test that tests that the missingPermissions function returns the expected permissions that are not granted, sorted, so that
+// the result is the same on every call.
+func Test_missingPermissions(t *testing.T) {
+	expected := util.MergePermissions([]string{"iam.roles.get", "compute.networks.create", "storage.buckets.get", "container.clusters.get"}, nil)
+
+	testCases := []struct {
+		name    string
+		granted []string
+		want    []string
+	}{
+		{name: "All granted", granted: []string{"storage.buckets.get", "iam.roles.get", "container.clusters.get", "compute.networks.create"}, want: []string{}},
+		{
+			name:    "Some granted",
+			granted: []string{"iam.roles.get", "storage.objects.get"},
+			want:    []string{"compute.networks.create", "container.clusters.get", "storage.buckets.get"},
+		},
+		{name: "None granted", granted: nil, want: []string{"compute.networks.create", "container.clusters.get", "iam.roles.get", "storage.buckets.get"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for range 100 {
+				assert.Equal(t, tc.want, missingPermissions(expected, tc.granted))
+			}
+		})
+	}
+}
+
+// Benchmark_missingPermissions is a benchmark that measures the missingPermissions function against the embedded list of the expected permissions, half
+// of which are granted.
+func Benchmark_missingPermissions(b *testing.B) {
+	expected := util.MergePermissions(constExpectedRolePermissions, nil)
+
+	granted := make([]string, 0, len(constExpectedRolePermissions)/2)
+
+	for i, permission := range constExpectedRolePermissions {
+		if i%2 == 0 {
+			granted = append(granted, permission)
+		}
+	}
+
+	for b.Loop() {
+		missingPermissions(expected, granted)
+	}
+}
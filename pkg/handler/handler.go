@@ -3,14 +3,112 @@ package handler
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"reflect"
 
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
 )
 
+// ErrContractViolated is the error that is returned when the results of a Handle function do not match the contract of its handler.
+var ErrContractViolated = errors.New("handler contract violated")
+
 // Handler is the interface that contains the Handle function.
 type Handler interface {
 	// Handle is the function that handles something.
+	//
+	// Its results match the contract returned by Contract.
 	Handle(context.Context, ...any) ([]any, error)
+
+	// Contract is the function that returns the contract of the results of Handle.
+	Contract() Contract
+}
+
+// Contract is the type that declares the results that the Handle function of a handler returns, so that its callers can unwrap them without surprises.
+//
+// Every Handle function returns nil results along with the error on failure, unless PartialOnError is set, and on success either nil if it returns nothing,
+// or exactly the values of the types of Results, in order; it never returns an empty non-nil slice.
+type Contract struct {
+	// Results is the types of the results on success, in order, or empty if the handler returns nothing.
+	Results []reflect.Type
+	// Optional is whether the handler may return nothing on success instead of the results, e.g. when the check does not apply to the cloud provider.
+	Optional bool
+	// Variadic is whether the handler returns any number, at least one, of the values of the single type of Results instead.
+	Variadic bool
+	// PartialOnError is whether the handler may return some of the results along with the error, e.g. when it retrieves them only partially.
+	PartialOnError bool
+}
+
+// Returns is the function that returns the contract of a handler that returns a single value of the type on success.
+func Returns[T any]() Contract {
+	return Contract{Results: []reflect.Type{reflect.TypeFor[T]()}}
+}
+
+// isOfType is the function that returns whether the value can be unwrapped as a value of the type.
+func isOfType(value any, t reflect.Type) bool {
+	if value == nil {
+		return t.Kind() == reflect.Interface
+	}
+
+	return reflect.TypeOf(value).AssignableTo(t)
+}
+
+// validateTypes is the function that returns an error if the results are not of the types of the contract.
+func (c Contract) validateTypes(results []any) error {
+	if c.Variadic {
+		for i, result := range results {
+			if !isOfType(result, c.Results[0]) {
+				return fmt.Errorf("%w: result %d is %T, want %s", ErrContractViolated, i, result, c.Results[0])
+			}
+		}
+
+		return nil
+	}
+
+	if len(results) != len(c.Results) {
+		return fmt.Errorf("%w: %d results, want %d", ErrContractViolated, len(results), len(c.Results))
+	}
+
+	for i, result := range results {
+		if !isOfType(result, c.Results[i]) {
+			return fmt.Errorf("%w: result %d is %T, want %s", ErrContractViolated, i, result, c.Results[i])
+		}
+	}
+
+	return nil
+}
+
+// Validate is the function that returns an error if the results and the error returned by a Handle function do not match the contract.
+func (c Contract) Validate(results []any, err error) error {
+	if results != nil && len(results) == 0 {
+		return fmt.Errorf("%w: empty non-nil results", ErrContractViolated)
+	}
+
+	if err != nil {
+		if results == nil {
+			return nil
+		}
+
+		if !c.PartialOnError {
+			return fmt.Errorf("%w: %d results along with the error", ErrContractViolated, len(results))
+		}
+
+		return c.validateTypes(results)
+	}
+
+	if results == nil {
+		if len(c.Results) == 0 || c.Optional {
+			return nil
+		}
+
+		return fmt.Errorf("%w: no results, want %d", ErrContractViolated, len(c.Results))
+	}
+
+	if len(c.Results) == 0 {
+		return fmt.Errorf("%w: %d results, want none", ErrContractViolated, len(results))
+	}
+
+	return c.validateTypes(results)
 }
 
 // ArgAsType is a helper function that retrieves a specific index of args as a value of specified type, or panics if the conversion fails.
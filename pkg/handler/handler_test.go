@@ -0,0 +1,78 @@
+// Package handler is the package that contains the handler interface.
+package handler
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestContract_Validate is a test that tests that the Validate function of the contract accepts the results that match it and rejects the others.
+//
+// nolint:funlen
+func TestContract_Validate(t *testing.T) {
+	// errFailed is the error of the Handle function in the test.
+	errFailed := errors.New("failed")
+
+	// value is the value of the results in the test.
+	value := "value"
+
+	testCases := []struct {
+		name     string
+		contract Contract
+		results  []any
+		err      error
+		wantErr  bool
+	}{
+		{name: "Nothing", contract: Contract{}},
+		{name: "Nothing with results", contract: Contract{}, results: []any{value}, wantErr: true},
+		{name: "Empty non-nil results", contract: Contract{}, results: []any{}, wantErr: true},
+		{name: "Failure", contract: Returns[string](), err: errFailed},
+		{name: "Failure with results", contract: Returns[string](), results: []any{value}, err: errFailed, wantErr: true},
+		{name: "Single value", contract: Returns[string](), results: []any{value}},
+		{name: "Single value missing", contract: Returns[string](), wantErr: true},
+		{name: "Single value of another type", contract: Returns[string](), results: []any{&value}, wantErr: true},
+		{name: "Too many values", contract: Returns[string](), results: []any{value, value}, wantErr: true},
+		{name: "Typed nil pointer", contract: Returns[*string](), results: []any{(*string)(nil)}},
+		{name: "Untyped nil", contract: Returns[*string](), results: []any{nil}, wantErr: true},
+		{name: "Interface", contract: Returns[error](), results: []any{errFailed}},
+		{name: "Optional missing", contract: Contract{Results: []reflect.Type{reflect.TypeFor[string]()}, Optional: true}},
+		{name: "Variadic", contract: Contract{Results: []reflect.Type{reflect.TypeFor[*string]()}, Variadic: true}, results: []any{&value, &value, &value}},
+		{
+			name:     "Variadic of another type",
+			contract: Contract{Results: []reflect.Type{reflect.TypeFor[*string]()}, Variadic: true},
+			results:  []any{&value, value},
+			wantErr:  true,
+		},
+		{
+			name:     "Partial on error",
+			contract: Contract{Results: []reflect.Type{reflect.TypeFor[*string]()}, Variadic: true, PartialOnError: true},
+			results:  []any{&value},
+			err:      errFailed,
+		},
+		{
+			name:     "Partial on error of another type",
+			contract: Contract{Results: []reflect.Type{reflect.TypeFor[*string]()}, Variadic: true, PartialOnError: true},
+			results:  []any{value},
+			err:      errFailed,
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.contract.Validate(tc.results, tc.err)
+
+			if tc.wantErr {
+				require.ErrorIs(t, err, ErrContractViolated)
+
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
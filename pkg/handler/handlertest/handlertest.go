@@ -0,0 +1,16 @@
+// Package handlertest is the package that contains the helpers of the tests of the handlers.
+package handlertest
+
+import (
+	"testing"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler"
+	"github.com/stretchr/testify/assert"
+)
+
+// AssertContract is the function that asserts that the results and the error returned by the Handle function of the handler match its contract.
+func AssertContract(t testing.TB, h handler.Handler, results []any, err error) bool {
+	t.Helper()
+
+	return assert.NoError(t, h.Contract().Validate(results, err))
+}
@@ -4,10 +4,13 @@ package jwtchecker
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/util"
 	"github.com/MicahParks/keyfunc/v3"
 	"github.com/golang-jwt/jwt/v5"
 )
@@ -21,6 +24,15 @@ var (
 
 	// errJWTNotValid is an error that occurs when the JWT is not valid.
 	errJWTNotValid = errors.New("jwt is not valid")
+
+	// errJWTAudienceMismatch is an error that occurs when the JWT is not minted for the expected audience.
+	errJWTAudienceMismatch = errors.New("jwt audience mismatch")
+
+	// errJWTIssuerMismatch is an error that occurs when the JWT is not issued by the expected issuer.
+	errJWTIssuerMismatch = errors.New("jwt issuer mismatch")
+
+	// errJWTExpired is an error that occurs when the JWT has expired or does not expire.
+	errJWTExpired = errors.New("jwt has expired or has no expiration time")
 )
 
 // JWTChecker is the type that contains the check functions for JWT.
@@ -29,48 +41,133 @@ type JWTChecker struct {
 	httpClient *http.Client
 	// jwksURI is the JWKS URI.
 	jwksURI *string
+	// audience is the audience that the JWTs are expected to be minted for, or empty to not check it.
+	audience string
+	// issuer is the issuer that the JWTs are expected to be issued by, or empty to not check it.
+	issuer string
 }
 
 var _ handler.Handler = &JWTChecker{}
 
+// parserOptions is the function that returns the options of the parser of the JWTs, which validate the claims that the JWTs are expected to have.
+//
+// The JWTs are required to expire if their audience or issuer is checked, as the service account tokens do. The issuer is checked by checkIssuer rather
+// than by the parser, which compares it exactly.
+func (c *JWTChecker) parserOptions() []jwt.ParserOption {
+	var opts []jwt.ParserOption
+
+	if c.audience != constant.EmptyString {
+		opts = append(opts, jwt.WithAudience(c.audience))
+	}
+
+	if c.audience != constant.EmptyString || c.issuer != constant.EmptyString {
+		opts = append(opts, jwt.WithExpirationRequired())
+	}
+
+	return opts
+}
+
+// checkIssuer is the function that returns an error if the JWT is not issued by the issuer of the checker, if set, comparing them regardless of the
+// HTTPS scheme and the trailing slash, as the OIDC checker compares the issuer of the discovery document.
+func (c *JWTChecker) checkIssuer(parsedJWT *jwt.Token) error {
+	if c.issuer == constant.EmptyString {
+		return nil
+	}
+
+	issuer, _ := parsedJWT.Claims.GetIssuer()
+
+	if util.NormalizeIssuer(issuer) != util.NormalizeIssuer(c.issuer) {
+		return fmt.Errorf("%w: jwt has issuer %q, expected %s", errJWTIssuerMismatch, issuer, util.NormalizeIssuer(c.issuer))
+	}
+
+	return nil
+}
+
+// claimsErr is the function that returns a descriptive error for the JWT whose claims are not valid, with the claims that it has, or the error as is if
+// it is not about the claims.
+func (c *JWTChecker) claimsErr(parsedJWT *jwt.Token, err error) error {
+	if parsedJWT == nil {
+		return err
+	}
+
+	switch {
+	case errors.Is(err, jwt.ErrTokenInvalidAudience):
+		audience, _ := parsedJWT.Claims.GetAudience()
+
+		return fmt.Errorf("%w: jwt has audiences %v, expected %s", errJWTAudienceMismatch, []string(audience), c.audience)
+	case errors.Is(err, jwt.ErrTokenExpired), errors.Is(err, jwt.ErrTokenRequiredClaimMissing):
+		return fmt.Errorf("%w: %w", errJWTExpired, err)
+	}
+
+	return err
+}
+
+// keyfunc is the function that fetches the JWKS from the JWKS URI and returns the function that looks up the keys of the JWTs in it.
+func (c *JWTChecker) keyfunc() (jwt.Keyfunc, error) {
+	resp, err := c.httpClient.Get(*c.jwksURI)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() // nolint:errcheck
+
+	respJSON, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	jwksKeyfunc, err := keyfunc.NewJWKSetJSON(respJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	return jwksKeyfunc.Keyfunc, nil
+}
+
 // Handle is the function that handles the JWT checking.
 //
-// The argument is expected to be a slice of JWTs to be checked.
+// The argument is expected to be a slice of JWTs to be checked, which are expected to be signed by one of the keys of the JWKS URI, and to be minted for
+// the audience and issued by the issuer of the checker, if set. The JWKS is fetched once for all of the JWTs.
 // It returns nothing on success, or an error on failure.
 func (c *JWTChecker) Handle(_ context.Context, args ...any) ([]any, error) {
 	jwts := handler.ArgAsType[[]*string](args, 0)
 
-	for _, vjwt := range jwts {
-		resp, err := c.httpClient.Get(*c.jwksURI)
-		if err != nil {
-			return nil, err
-		}
-		defer resp.Body.Close() // nolint:errcheck
-
-		respJSON, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return nil, err
-		}
+	if len(jwts) == 0 {
+		return nil, nil
+	}
 
-		jwksKeyfunc, err := keyfunc.NewJWKSetJSON(respJSON)
-		if err != nil {
-			return nil, err
-		}
+	jwksKeyfunc, err := c.keyfunc()
+	if err != nil {
+		return nil, err
+	}
 
-		parsedJWT, err := jwt.Parse(*vjwt, jwksKeyfunc.Keyfunc)
+	for _, vjwt := range jwts {
+		parsedJWT, err := jwt.Parse(*vjwt, jwksKeyfunc, c.parserOptions()...)
 		if err != nil {
-			return nil, err
+			return nil, c.claimsErr(parsedJWT, err)
 		}
 
 		if !parsedJWT.Valid {
 			return nil, errJWTNotValid
 		}
+
+		if err := c.checkIssuer(parsedJWT); err != nil {
+			return nil, err
+		}
 	}
 
 	return nil, nil
 }
 
+// Contract is the function that returns the contract of the results of the JWT checking.
+//
+// It returns nothing.
+func (c *JWTChecker) Contract() handler.Contract {
+	return handler.Contract{}
+}
+
 // New is the function that creates a new JWTChecker.
-func New(httpClient *http.Client, jwksURI *string) *JWTChecker {
-	return &JWTChecker{httpClient: httpClient, jwksURI: jwksURI}
+//
+// The audience and the issuer of the JWTs are only checked if they are not empty.
+func New(checkCtx handler.CheckContext, jwksURI *string, audience string, issuer string) *JWTChecker {
+	return &JWTChecker{httpClient: checkCtx.HTTPClient, jwksURI: jwksURI, audience: audience, issuer: issuer}
 }
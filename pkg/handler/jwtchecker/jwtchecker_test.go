@@ -3,15 +3,25 @@ package jwtchecker
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/handlertest"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/util"
 	"github.com/MicahParks/jwkset"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // errTokenUnverifiable is an error that occurs when the JWT is unverifiable.
@@ -74,7 +84,7 @@ func setupJWTCheckerTest() *JWTChecker {
 		_ = json.NewEncoder(w).Encode(constJWKsJSON)
 	}))
 
-	return New(mockHTTPServer.Client(), &mockHTTPServer.URL)
+	return New(handler.CheckContext{HTTPClient: mockHTTPServer.Client()}, &mockHTTPServer.URL, constant.EmptyString, constant.EmptyString)
 }
 
 // TestJWTChecker_Check tests the Check method of the JWTChecker.
@@ -110,7 +120,8 @@ func TestJWTChecker_Check(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			jwtChecker := setupJWTCheckerTest()
 
-			_, gotErr := jwtChecker.Handle(context.TODO(), tc.jwts)
+			results, gotErr := jwtChecker.Handle(context.TODO(), tc.jwts)
+			handlertest.AssertContract(t, jwtChecker, results, gotErr)
 
 			if tc.wantErr != nil {
 				assert.Error(t, gotErr, "expected error %v, got %v", tc.wantErr, gotErr)
@@ -118,3 +129,152 @@ func TestJWTChecker_Check(t *testing.T) {
 		})
 	}
 }
+
+// TestJWTChecker_Handle_jwksFetchedOnce is a test that tests that the Handle function fetches the JWKS once for all of the JWTs, and not at all if there
+// are none.
+func TestJWTChecker_Handle_jwksFetchedOnce(t *testing.T) {
+	var requests atomic.Int32
+
+	mockHTTPServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+
+		_ = json.NewEncoder(w).Encode(constJWKsJSON)
+	}))
+	t.Cleanup(mockHTTPServer.Close)
+
+	jwtChecker := New(handler.CheckContext{HTTPClient: mockHTTPServer.Client()}, &mockHTTPServer.URL, constant.EmptyString, constant.EmptyString)
+
+	_, err := jwtChecker.Handle(context.TODO(), []*string{util.Ref(validJWT1), util.Ref(validJWT2), util.Ref(validJWT1)})
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(1), requests.Load())
+
+	_, err = jwtChecker.Handle(context.TODO(), []*string{})
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+// TestJWTChecker_Handle_claims is a test that tests that the Handle function rejects the JWTs that are signed by the right key but are minted for another
+// audience, issued by another issuer, or expired, with a descriptive error, that it compares the issuers regardless of the HTTPS scheme and the trailing
+// slash, and that it does not check the claims that the checker does not expect.
+//
+// nolint:funlen
+func TestJWTChecker_Handle_claims(t *testing.T) {
+	const (
+		// kid is the ID of the key used in the test.
+		kid = "test"
+
+		// audience is the audience used in the test.
+		audience = "amazonaws.com"
+
+		// issuer is the issuer used in the test.
+		issuer = "https://oidc.eks.us-west-2.amazonaws.com/id/1234567890"
+	)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	jwk, err := jwkset.NewJWKFromKey(key.Public(), jwkset.JWKOptions{Metadata: jwkset.JWKMetadataOptions{ALG: jwkset.AlgRS256, KID: kid, USE: jwkset.UseSig}})
+	require.NoError(t, err)
+
+	mockHTTPServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(jwkset.JWKSMarshal{Keys: []jwkset.JWKMarshal{jwk.Marshal()}})
+	}))
+	t.Cleanup(mockHTTPServer.Close)
+
+	sign := func(claims jwt.MapClaims) *string {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = kid
+
+		signed, err := token.SignedString(key)
+		require.NoError(t, err)
+
+		return &signed
+	}
+
+	expiresAt := time.Now().Add(time.Hour).Unix()
+
+	testCases := []struct {
+		name     string
+		audience string
+		issuer   string
+		claims   jwt.MapClaims
+		wantErr  error
+	}{
+		{
+			name:     "Valid",
+			audience: audience,
+			issuer:   issuer,
+			claims:   jwt.MapClaims{"aud": []string{audience}, "iss": issuer, "exp": expiresAt},
+		},
+		{
+			name:     "Wrong audience",
+			audience: audience,
+			issuer:   issuer,
+			claims:   jwt.MapClaims{"aud": []string{"sts.amazonaws.com"}, "iss": issuer, "exp": expiresAt},
+			wantErr:  errJWTAudienceMismatch,
+		},
+		{
+			name:     "Wrong issuer",
+			audience: audience,
+			issuer:   issuer,
+			claims:   jwt.MapClaims{"aud": []string{audience}, "iss": "https://kubernetes.default.svc", "exp": expiresAt},
+			wantErr:  errJWTIssuerMismatch,
+		},
+		{
+			name:     "Issuer without scheme",
+			audience: audience,
+			issuer:   strings.TrimPrefix(issuer, "https://"),
+			claims:   jwt.MapClaims{"aud": []string{audience}, "iss": issuer, "exp": expiresAt},
+		},
+		{
+			name:     "Issuer with trailing slash",
+			audience: audience,
+			issuer:   "https://login.microsoftonline.com/tenant/v2.0/",
+			claims:   jwt.MapClaims{"aud": []string{audience}, "iss": "https://login.microsoftonline.com/tenant/v2.0", "exp": expiresAt},
+		},
+		{
+			name:     "No issuer",
+			audience: audience,
+			issuer:   issuer,
+			claims:   jwt.MapClaims{"aud": []string{audience}, "exp": expiresAt},
+			wantErr:  errJWTIssuerMismatch,
+		},
+		{
+			name:     "Expired",
+			audience: audience,
+			issuer:   issuer,
+			claims:   jwt.MapClaims{"aud": []string{audience}, "iss": issuer, "exp": time.Now().Add(-time.Hour).Unix()},
+			wantErr:  errJWTExpired,
+		},
+		{
+			name:     "No expiration time",
+			audience: audience,
+			issuer:   issuer,
+			claims:   jwt.MapClaims{"aud": []string{audience}, "iss": issuer},
+			wantErr:  errJWTExpired,
+		},
+		{
+			name:   "Claims not checked",
+			claims: jwt.MapClaims{"aud": []string{"sts.amazonaws.com"}, "iss": "https://kubernetes.default.svc"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			jwtChecker := New(handler.CheckContext{HTTPClient: mockHTTPServer.Client()}, &mockHTTPServer.URL, tc.audience, tc.issuer)
+
+			results, gotErr := jwtChecker.Handle(context.TODO(), []*string{sign(tc.claims)})
+			handlertest.AssertContract(t, jwtChecker, results, gotErr)
+
+			if tc.wantErr == nil {
+				assert.NoError(t, gotErr)
+
+				return
+			}
+
+			assert.ErrorIs(t, gotErr, tc.wantErr)
+		})
+	}
+}
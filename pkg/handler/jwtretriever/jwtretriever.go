@@ -3,6 +3,11 @@ package jwtretriever
 
 import (
 	"errors"
+	"time"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
+	"github.com/charmbracelet/log"
+	"github.com/golang-jwt/jwt/v5"
 )
 
 // LogMsgJWTsRetrieved is the message that is logged when the JWTs are retrieved.
@@ -18,8 +23,59 @@ var (
 
 const (
 	// ServiceAccountsNamespace is the namespace where the Crossplane service accounts are located.
-	ServiceAccountsNamespace = "crossplane"
+	ServiceAccountsNamespace = constant.NamespaceCrossplane
 
 	// TokenExpirationSeconds is the expiration seconds of a single JWT.
 	TokenExpirationSeconds = int64(3600)
 )
+
+// clampedLifetimeTolerance is the tolerance of the lifetime of an issued JWT below TokenExpirationSeconds, which covers the time spent issuing and
+// receiving the JWT.
+const clampedLifetimeTolerance = 5 * time.Minute
+
+// errNoExpiration is the error that occurs when the JWT has no expiration.
+var errNoExpiration = errors.New("JWT has no expiration")
+
+// Lifetime is a function that returns the duration from now until the expiration of the JWT, without verifying its signature.
+func Lifetime(token string, now time.Time) (time.Duration, error) {
+	var claims jwt.RegisteredClaims
+
+	if _, _, err := jwt.NewParser().ParseUnverified(token, &claims); err != nil {
+		return 0, err
+	}
+
+	if claims.ExpiresAt == nil {
+		return 0, errNoExpiration
+	}
+
+	return claims.ExpiresAt.Sub(now), nil
+}
+
+// WarnIfLifetimeClamped is a function that logs a warning if the JWT issued for the service account expires materially earlier than
+// TokenExpirationSeconds from now.
+//
+// The API server clamps the lifetime of the requested JWTs to its --service-account-max-token-expiration flag, which shortens the window in which the
+// JWT can be exchanged for the cloud provider credentials. A JWT that cannot be decoded is left to the JWT checker to report.
+func WarnIfLifetimeClamped(logger *log.Logger, serviceAccount string, token string, now time.Time) {
+	const (
+		// logMsgLifetimeNotDecoded is the message that is logged when the lifetime of the JWT cannot be decoded.
+		logMsgLifetimeNotDecoded = "could not decode the lifetime of the JWT of service account %s: %s"
+
+		// logMsgLifetimeClamped is the message that is logged when the lifetime of the JWT is clamped by the API server.
+		logMsgLifetimeClamped = "JWT of service account %s expires in %s instead of the requested %s, check the " +
+			"--service-account-max-token-expiration flag of the API server"
+	)
+
+	lifetime, err := Lifetime(token, now)
+	if err != nil {
+		logger.Debugf(logMsgLifetimeNotDecoded, serviceAccount, err)
+
+		return
+	}
+
+	requested := time.Duration(TokenExpirationSeconds) * time.Second
+
+	if lifetime < requested-clampedLifetimeTolerance {
+		logger.Warnf(logMsgLifetimeClamped, serviceAccount, lifetime.Round(time.Second), requested)
+	}
+}
@@ -0,0 +1,92 @@
+// Package jwtretriever contains the JWT retrieving related variables and constants.
+package jwtretriever
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// signedToken is a function that returns a JWT expiring at the given time, or without an expiration if the time is zero, for testing.
+func signedToken(t *testing.T, expiresAt time.Time) string {
+	t.Helper()
+
+	claims := jwt.RegisteredClaims{Subject: "system:serviceaccount:crossplane:test"}
+
+	if !expiresAt.IsZero() {
+		claims.ExpiresAt = jwt.NewNumericDate(expiresAt)
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("test-key"))
+	require.NoError(t, err)
+
+	return token
+}
+
+// TestWarnIfLifetimeClamped is a test that tests that the WarnIfLifetimeClamped function warns only when the JWT expires materially earlier than
+// requested.
+func TestWarnIfLifetimeClamped(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		name      string
+		token     string
+		wantWarn  bool
+		wantDebug bool
+	}{
+		{
+			name:  "Requested lifetime",
+			token: signedToken(t, now.Add(time.Duration(TokenExpirationSeconds)*time.Second)),
+		},
+		{
+			name:  "Lifetime within tolerance",
+			token: signedToken(t, now.Add(time.Duration(TokenExpirationSeconds)*time.Second-time.Minute)),
+		},
+		{
+			name:     "Clamped lifetime",
+			token:    signedToken(t, now.Add(10*time.Minute)),
+			wantWarn: true,
+		},
+		{
+			name:      "No expiration",
+			token:     signedToken(t, time.Time{}),
+			wantDebug: true,
+		},
+		{
+			name:      "Not a JWT",
+			token:     "token-aws-a",
+			wantDebug: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			logger := log.New(&buf)
+			logger.SetLevel(log.DebugLevel)
+
+			WarnIfLifetimeClamped(logger, "test", tc.token, now)
+
+			out := buf.String()
+
+			if tc.wantWarn {
+				assert.Contains(t, out, "WARN")
+				assert.Contains(t, out, "expires in 10m0s instead of the requested 1h0m0s")
+			} else {
+				assert.NotContains(t, out, "WARN")
+			}
+
+			if tc.wantDebug {
+				assert.Contains(t, out, "could not decode the lifetime")
+			} else if !tc.wantWarn {
+				assert.Empty(t, out)
+			}
+		})
+	}
+}
@@ -3,19 +3,39 @@ package mysqlchecker
 
 import (
 	"context"
+	"crypto/tls"
 	"database/sql"
+	"errors"
 	"fmt"
+	"maps"
+	"net"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
 	pkgerrors "github.com/AlphaSense-Engineering/privatecloud-cli/pkg/errors"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/k8s/kubeutil"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/util"
+	"github.com/charmbracelet/log"
 	"github.com/go-sql-driver/mysql"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
 var (
+	// errInvalidExpectedConfigOverride is the error that is returned when the override of the expected configuration of the MySQL is not valid.
+	errInvalidExpectedConfigOverride = errors.New("invalid override of the expected MySQL configuration")
+
+	// errInvalidSSLMode is the error that is returned when the SSL mode of the secret is not one of the supported ones.
+	errInvalidSSLMode = errors.New("invalid MySQL SSL mode: must be one of disabled, preferred, skip-verify or required")
+
+	// variableNameRegex is the regular expression that the names of the system variables of the MySQL match, which keeps the overrides from injecting
+	// anything into the queries of their values.
+	variableNameRegex = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
 	// constExpectedConfig is the map of expected configuration for the MySQL.
 	//
 	// These are listed at https://developer.alpha-sense.com/enterprise/technical-requirements/#mysql-database-cluster.
@@ -33,32 +53,200 @@ var (
 	}
 )
 
+// MinimumPrefix is the prefix of the values of the override of the expected configuration that are the minimum acceptable ones of the numeric variables,
+// rather than the exact ones.
+const MinimumPrefix = ">="
+
+// expectedValue is the type that contains the expected value of a system variable of the MySQL, along with how the actual value is compared with it.
+type expectedValue struct {
+	// value is the expected value.
+	value string
+	// comparison is how the actual value is compared with the expected one.
+	comparison pkgerrors.Comparison
+}
+
+// compare is the function that returns an error stating the comparison if the actual value of the variable does not match the expected one.
+func (v expectedValue) compare(variable string, got string) error {
+	if v.comparison != pkgerrors.ComparisonAtLeast {
+		if got != v.value {
+			return pkgerrors.NewKeyExpectedGot(variable, v.value, got)
+		}
+
+		return nil
+	}
+
+	minimum, err := strconv.ParseInt(v.value, 10, 64)
+	if err != nil {
+		return err
+	}
+
+	if n, err := strconv.ParseInt(got, 10, 64); err != nil || n < minimum {
+		return pkgerrors.NewKeyExpectedAtLeastGot(variable, v.value, got)
+	}
+
+	return nil
+}
+
+// expectedConfig is the function that returns the expected configuration of the MySQL with the override merged over it.
+//
+// The values of the override that start with MinimumPrefix are the minimum acceptable ones, and the others the exact ones. It returns an error if a name
+// of the override is not the one of a system variable, or if a minimum is not an integer.
+func expectedConfig(override map[string]string) (map[string]expectedValue, error) {
+	config := make(map[string]expectedValue, len(constExpectedConfig)+len(override))
+
+	for variable, value := range constExpectedConfig {
+		config[variable] = expectedValue{value: value, comparison: pkgerrors.ComparisonExact}
+	}
+
+	for variable, value := range override {
+		if !variableNameRegex.MatchString(variable) {
+			return nil, fmt.Errorf("%w: %q is not the name of a system variable", errInvalidExpectedConfigOverride, variable)
+		}
+
+		expected := expectedValue{value: value, comparison: pkgerrors.ComparisonExact}
+
+		if minimum, ok := strings.CutPrefix(value, MinimumPrefix); ok {
+			if _, err := strconv.ParseInt(minimum, 10, 64); err != nil {
+				return nil, fmt.Errorf("%w: the minimum of %s is not an integer: %q", errInvalidExpectedConfigOverride, variable, minimum)
+			}
+
+			expected = expectedValue{value: minimum, comparison: pkgerrors.ComparisonAtLeast}
+		}
+
+		config[variable] = expected
+	}
+
+	return config, nil
+}
+
+// ValidateExpectedConfigOverride is the function that returns an error if the override of the expected configuration of the MySQL is not valid, so that
+// it is reported before the check runs.
+func ValidateExpectedConfigOverride(override map[string]string) error {
+	_, err := expectedConfig(override)
+
+	return err
+}
+
+// tlsConfigName is the name under which the custom TLS configuration is registered with the MySQL driver.
+const tlsConfigName = "privatecloud-cli"
+
+// SecretName is the name of the secret that contains the MySQL credentials.
+const SecretName = "default-creds"
+
+// SecretSSLModeKey is the key of the optional SSL mode in the secret.
+const SecretSSLModeKey = "ssl-mode"
+
+// constSecretKeys is the list of the keys that the check reads from the secret.
+//
+// Do not modify this variable, it is supposed to be constant.
+var constSecretKeys = []string{constant.SecretUsernameKey, constant.SecretPasswordKey, constant.SecretEndpointKey, constant.SecretPortKey, SecretSSLModeKey}
+
+// SecretKeys is the function that returns the keys that the check reads from the secret, which the keys of the options replace.
+func SecretKeys() []string {
+	return slices.Clone(constSecretKeys)
+}
+
+const (
+	// sslModeDisabled is the SSL mode that connects without TLS, even if a CA bundle is given.
+	sslModeDisabled = "disabled"
+	// sslModePreferred is the SSL mode that connects with TLS if the server supports it, without verifying its certificate.
+	sslModePreferred = "preferred"
+	// sslModeSkipVerify is the SSL mode that connects with TLS without verifying the certificate of the server.
+	sslModeSkipVerify = "skip-verify"
+	// sslModeRequired is the SSL mode that connects with TLS, verifying the certificate of the server against the CA bundle if one is given, or against
+	// the certificates of the system otherwise.
+	sslModeRequired = "required"
+)
+
 // MySQLChecker is the type that contains the check functions for the MySQL.
 type MySQLChecker struct {
+	// logger is the logger.
+	logger *log.Logger
 	// clientset is the Kubernetes client.
 	clientset kubernetes.Interface
+	// namespace is the namespace of the secret, under the namespace prefix of the options.
+	namespace string
+	// secretName is the name of the secret that contains the MySQL credentials.
+	secretName string
+	// secretKeys is the keys of the secret that replace the default ones, by the default ones.
+	secretKeys map[string]string
+	// tlsConfig is the TLS configuration to use for the connection, or nil to connect without TLS.
+	tlsConfig *tls.Config
+	// connectTimeout is the maximum duration of establishing the connection.
+	connectTimeout time.Duration
+	// expectedConfigOverride is the override of the expected configuration, merged over the default one.
+	expectedConfigOverride map[string]string
 }
 
 var _ handler.Handler = &MySQLChecker{}
 
+// buildConfig is a function that builds the configuration of the connection to the MySQL from the data of the secret.
+//
+// Without the SSL mode in the secret, the connection uses TLS only if the TLS configuration is given. It returns an error if the SSL mode is not one of the
+// supported ones.
+func (c *MySQLChecker) buildConfig(data map[string]string) (*mysql.Config, error) {
+	cfg := mysql.NewConfig()
+
+	cfg.User = data[constant.SecretUsernameKey]
+	cfg.Passwd = data[constant.SecretPasswordKey]
+	cfg.Net = "tcp"
+	cfg.Addr = fmt.Sprintf("%s:%s", data[constant.SecretEndpointKey], data[constant.SecretPortKey])
+	cfg.Timeout = c.connectTimeout
+
+	switch sslMode := strings.ToLower(data[SecretSSLModeKey]); sslMode {
+	case constant.EmptyString, sslModeRequired:
+		if c.tlsConfig != nil {
+			if err := mysql.RegisterTLSConfig(tlsConfigName, c.tlsConfig); err != nil {
+				return nil, err
+			}
+
+			cfg.TLSConfig = tlsConfigName
+		} else if sslMode == sslModeRequired {
+			cfg.TLSConfig = strconv.FormatBool(true)
+		}
+	case sslModeDisabled:
+	case sslModePreferred, sslModeSkipVerify:
+		// The values of these modes are the names of the TLS configurations that the MySQL driver provides.
+		cfg.TLSConfig = sslMode
+	default:
+		return nil, fmt.Errorf("%w: %q", errInvalidSSLMode, data[SecretSSLModeKey])
+	}
+
+	return cfg, nil
+}
+
+// warnIPEndpoint is a function that warns if the endpoint is an IP address rather than a DNS name, as the IP addresses of the managed databases change on
+// failovers.
+func (c *MySQLChecker) warnIPEndpoint(endpoint string) {
+	// logMsgIPEndpoint is the message that is logged when the endpoint is an IP address.
+	const logMsgIPEndpoint = "MySQL endpoint %s is an IP address, which breaks once the database fails over; use the hostname of the managed endpoint instead"
+
+	if net.ParseIP(endpoint) != nil {
+		c.logger.Warnf(logMsgIPEndpoint, endpoint)
+	}
+}
+
 // Handle is the function that handles the MySQL checking.
 //
+// It warns, without failing, if the endpoint is an IP address rather than a DNS name. The system variables are compared with the expected configuration in
+// the order of their names, so that the first mismatch is the same on every run.
+//
 // The arguments are not used.
 // It returns nothing on success, or an error on failure.
 func (c *MySQLChecker) Handle(ctx context.Context, _ ...any) ([]any, error) {
-	const (
-		// secretName is the name of the secret that contains the MySQL credentials.
-		secretName = "default-creds"
-	)
+	config, err := expectedConfig(c.expectedConfigOverride)
+	if err != nil {
+		return nil, err
+	}
 
-	secret, err := c.clientset.CoreV1().Secrets(constant.NamespaceMySQL).Get(ctx, secretName, metav1.GetOptions{})
+	data, err := kubeutil.GetSecretData(ctx, c.clientset, c.namespace, c.secretName)
 	if err != nil {
 		return nil, err
 	}
 
-	data := util.ConvertMap(secret.Data, util.Identity[string], util.ByteSliceToString)
+	data = kubeutil.ReplaceSecretKeys(data, c.secretKeys)
 
-	if err := util.KeysExistAndNotEmptyOrErr(data, []string{
+	if err := util.KeysExistAndNotBlankOrErr(data, []string{
 		constant.SecretUsernameKey,
 		constant.SecretPasswordKey,
 		constant.SecretEndpointKey,
@@ -67,12 +255,12 @@ func (c *MySQLChecker) Handle(ctx context.Context, _ ...any) ([]any, error) {
 		return nil, err
 	}
 
-	cfg := mysql.NewConfig()
+	c.warnIPEndpoint(data[constant.SecretEndpointKey])
 
-	cfg.User = data[constant.SecretUsernameKey]
-	cfg.Passwd = data[constant.SecretPasswordKey]
-	cfg.Net = "tcp"
-	cfg.Addr = fmt.Sprintf("%s:%s", data[constant.SecretEndpointKey], data[constant.SecretPortKey])
+	cfg, err := c.buildConfig(data)
+	if err != nil {
+		return nil, err
+	}
 
 	db, err := sql.Open("mysql", cfg.FormatDSN())
 	if err != nil {
@@ -84,21 +272,47 @@ func (c *MySQLChecker) Handle(ctx context.Context, _ ...any) ([]any, error) {
 		return nil, err
 	}
 
-	for k, expected := range constExpectedConfig {
+	for _, k := range slices.Sorted(maps.Keys(config)) {
 		var got string
 		if err := db.QueryRowContext(ctx, "SELECT @@"+k).Scan(&got); err != nil {
 			return nil, err
 		}
 
-		if got != expected {
-			return nil, pkgerrors.NewKeyExpectedGot(k, expected, got)
+		if err := config[k].compare(k, got); err != nil {
+			return nil, err
 		}
 	}
 
 	return nil, nil
 }
 
+// Contract is the function that returns the contract of the results of the MySQL checking.
+//
+// It returns nothing.
+func (c *MySQLChecker) Contract() handler.Contract {
+	return handler.Contract{}
+}
+
 // New is a function that returns a new MySQLChecker.
-func New(clientset kubernetes.Interface) *MySQLChecker {
-	return &MySQLChecker{clientset: clientset}
+//
+// The TLS configuration of the options is optional; when it is nil, the connection is established without TLS unless the SSL mode of the secret requires
+// it. The override of the expected configuration of the options is merged over the default one, which is checked as is without it. It reads the secret
+// named in the options, or else the one named SecretName, by the keys of the options that replace the default ones.
+func New(checkCtx handler.CheckContext) *MySQLChecker {
+	secretName := checkCtx.Options.MySQLSecretName
+
+	if secretName == constant.EmptyString {
+		secretName = SecretName
+	}
+
+	return &MySQLChecker{
+		logger:                 checkCtx.Logger,
+		clientset:              checkCtx.Clientset,
+		namespace:              checkCtx.Options.Namespace(constant.NamespaceMySQL),
+		secretName:             secretName,
+		secretKeys:             checkCtx.Options.MySQLSecretKeys,
+		tlsConfig:              checkCtx.Options.DBTLSConfig,
+		connectTimeout:         checkCtx.Options.ConnectTimeout,
+		expectedConfigOverride: checkCtx.Options.MySQLExpectedConfigOverride,
+	}
 }
@@ -0,0 +1,253 @@
+// Package mysqlchecker is the package that contains the check functions for the MySQL.
+package mysqlchecker
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"testing"
+	"time"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
+	pkgerrors "github.com/AlphaSense-Engineering/privatecloud-cli/pkg/errors"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler"
+	"github.com/charmbracelet/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// TestMySQLChecker_buildConfig is a test that tests that the buildConfig function builds the configuration from the secret data and the connect timeout.
+func TestMySQLChecker_buildConfig(t *testing.T) {
+	const connectTimeout = 7 * time.Second
+
+	c := New(handler.CheckContext{Options: handler.CheckOptions{ConnectTimeout: connectTimeout}})
+
+	cfg, err := c.buildConfig(map[string]string{
+		constant.SecretUsernameKey: "user",
+		constant.SecretPasswordKey: "pass",
+		constant.SecretEndpointKey: "db.example.com",
+		constant.SecretPortKey:     "3306",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "user", cfg.User)
+	assert.Equal(t, "pass", cfg.Passwd)
+	assert.Equal(t, "db.example.com:3306", cfg.Addr)
+	assert.Equal(t, connectTimeout, cfg.Timeout)
+	assert.Contains(t, cfg.FormatDSN(), "timeout=7s")
+}
+
+// TestMySQLChecker_buildConfig_sslMode is a test that tests that the buildConfig function sets the TLS configuration from the SSL mode of the secret and the
+// CA bundle of the options, and rejects the SSL mode that is not supported.
+func TestMySQLChecker_buildConfig_sslMode(t *testing.T) {
+	testCases := []struct {
+		name      string
+		sslMode   string
+		tlsConfig *tls.Config
+		want      string
+		wantErr   bool
+	}{
+		{name: "Default", want: constant.EmptyString},
+		{name: "Default with CA bundle", tlsConfig: &tls.Config{MinVersion: tls.VersionTLS12}, want: tlsConfigName},
+		{name: "Disabled with CA bundle", sslMode: "disabled", tlsConfig: &tls.Config{MinVersion: tls.VersionTLS12}, want: constant.EmptyString},
+		{name: "Preferred", sslMode: "preferred", want: "preferred"},
+		{name: "Skip verify", sslMode: "SKIP-VERIFY", want: "skip-verify"},
+		{name: "Required", sslMode: "required", want: "true"},
+		{name: "Required with CA bundle", sslMode: "required", tlsConfig: &tls.Config{MinVersion: tls.VersionTLS12}, want: tlsConfigName},
+		{name: "Invalid", sslMode: "verify-full", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := New(handler.CheckContext{Options: handler.CheckOptions{DBTLSConfig: tc.tlsConfig}})
+
+			cfg, err := c.buildConfig(map[string]string{
+				constant.SecretUsernameKey: "user",
+				constant.SecretPasswordKey: "pass",
+				constant.SecretEndpointKey: "db.example.com",
+				constant.SecretPortKey:     "3306",
+				SecretSSLModeKey:           tc.sslMode,
+			})
+
+			if tc.wantErr {
+				assert.ErrorIs(t, err, errInvalidSSLMode)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.want, cfg.TLSConfig)
+		})
+	}
+}
+
+// TestMySQLChecker_warnIPEndpoint is a test that tests that the warnIPEndpoint function warns when, and only when, the endpoint is an IP address.
+func TestMySQLChecker_warnIPEndpoint(t *testing.T) {
+	testCases := []struct {
+		name     string
+		endpoint string
+		wantWarn bool
+	}{
+		{name: "Hostname", endpoint: "db.example.com"},
+		{name: "IPv4", endpoint: "10.0.12.34", wantWarn: true},
+		{name: "IPv6", endpoint: "fd00::1234", wantWarn: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			New(handler.CheckContext{Logger: log.New(&buf)}).warnIPEndpoint(tc.endpoint)
+
+			if !tc.wantWarn {
+				assert.Empty(t, buf.String())
+
+				return
+			}
+
+			assert.Contains(t, buf.String(), "MySQL endpoint "+tc.endpoint+" is an IP address")
+		})
+	}
+}
+
+// TestExpectedConfig is a test that tests that the expectedConfig function merges the override over the default configuration, telling the minimums apart
+// from the exact values, and rejects the override that is not valid.
+func TestExpectedConfig(t *testing.T) {
+	testCases := []struct {
+		name     string
+		override map[string]string
+		want     map[string]expectedValue
+		wantLen  int
+		wantErr  string
+	}{
+		{
+			name:    "Default",
+			want:    map[string]expectedValue{"wait_timeout": {value: "1800", comparison: pkgerrors.ComparisonExact}},
+			wantLen: len(constExpectedConfig),
+		},
+		{
+			name:     "Override",
+			override: map[string]string{"wait_timeout": ">=1800", "connect_timeout": "10", "max_connections": "500"},
+			want: map[string]expectedValue{
+				"wait_timeout":    {value: "1800", comparison: pkgerrors.ComparisonAtLeast},
+				"connect_timeout": {value: "10", comparison: pkgerrors.ComparisonExact},
+				"max_connections": {value: "500", comparison: pkgerrors.ComparisonExact},
+			},
+			wantLen: len(constExpectedConfig) + 1,
+		},
+		{
+			name:     "Invalid variable name",
+			override: map[string]string{"wait_timeout; DROP TABLE users": "1"},
+			wantErr:  `"wait_timeout; DROP TABLE users" is not the name of a system variable`,
+		},
+		{
+			name:     "Invalid minimum",
+			override: map[string]string{"wait_timeout": ">=half an hour"},
+			wantErr:  `the minimum of wait_timeout is not an integer: "half an hour"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config, err := expectedConfig(tc.override)
+
+			if tc.wantErr != constant.EmptyString {
+				require.ErrorIs(t, err, errInvalidExpectedConfigOverride)
+				assert.ErrorContains(t, err, tc.wantErr)
+				assert.ErrorIs(t, ValidateExpectedConfigOverride(tc.override), errInvalidExpectedConfigOverride)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Len(t, config, tc.wantLen)
+
+			for variable, want := range tc.want {
+				assert.Equal(t, want, config[variable], variable)
+			}
+
+			assert.Equal(t, expectedValue{value: "1", comparison: pkgerrors.ComparisonExact}, config["lower_case_table_names"])
+		})
+	}
+}
+
+// TestExpectedValue_compare is a test that tests that the compare function tells the exact-match failures apart from the threshold ones.
+func TestExpectedValue_compare(t *testing.T) {
+	testCases := []struct {
+		name           string
+		expected       expectedValue
+		got            string
+		wantErr        string
+		wantComparison pkgerrors.Comparison
+	}{
+		{name: "Exact match", expected: expectedValue{value: "1800", comparison: pkgerrors.ComparisonExact}, got: "1800"},
+		{
+			name:           "Exact mismatch",
+			expected:       expectedValue{value: "1800", comparison: pkgerrors.ComparisonExact},
+			got:            "3600",
+			wantErr:        "expected wait_timeout to be 1800, got 3600",
+			wantComparison: pkgerrors.ComparisonExact,
+		},
+		{name: "Above minimum", expected: expectedValue{value: "1800", comparison: pkgerrors.ComparisonAtLeast}, got: "28800"},
+		{name: "At minimum", expected: expectedValue{value: "1800", comparison: pkgerrors.ComparisonAtLeast}, got: "1800"},
+		{
+			name:           "Below minimum",
+			expected:       expectedValue{value: "1800", comparison: pkgerrors.ComparisonAtLeast},
+			got:            "600",
+			wantErr:        "expected wait_timeout to be at least 1800, got 600",
+			wantComparison: pkgerrors.ComparisonAtLeast,
+		},
+		{
+			name:           "Not a number",
+			expected:       expectedValue{value: "1800", comparison: pkgerrors.ComparisonAtLeast},
+			got:            "OFF",
+			wantErr:        "expected wait_timeout to be at least 1800, got OFF",
+			wantComparison: pkgerrors.ComparisonAtLeast,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.expected.compare("wait_timeout", tc.got)
+
+			if tc.wantErr == constant.EmptyString {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			var keyExpectedGotErr *pkgerrors.KeyExpectedGot
+
+			require.ErrorAs(t, err, &keyExpectedGotErr)
+			assert.EqualError(t, err, tc.wantErr)
+			assert.Equal(t, tc.wantComparison, keyExpectedGotErr.Comparison())
+		})
+	}
+}
+
+// TestMySQLChecker_Handle_secret is a test that tests that the Handle function reads the secret named in the options by the keys of the options that
+// replace the default ones, and reports the default keys whose replacing keys are missing.
+func TestMySQLChecker_Handle_secret(t *testing.T) {
+	c := New(handler.CheckContext{
+		Clientset: fake.NewClientset(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: constant.NamespaceMySQL, Name: "db-creds"},
+			Data: map[string][]byte{
+				"username":    []byte("alphasense"),
+				"db-password": []byte("secret"),
+				"endpoint":    []byte("db.example.com"),
+			},
+		}),
+		Options: handler.CheckOptions{
+			MySQLSecretName: "db-creds",
+			MySQLSecretKeys: map[string]string{"password": "db-password", "port": "db-port"},
+		},
+	})
+
+	_, err := c.Handle(context.Background())
+	require.ErrorContains(t, err, pkgerrors.NewKeysMissing([]string{"port"}).Error())
+	assert.NotContains(t, err.Error(), "password")
+}
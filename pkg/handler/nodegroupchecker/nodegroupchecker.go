@@ -54,9 +54,16 @@ func (c *NodeGroupChecker) Handle(ctx context.Context, _ ...any) ([]any, error)
 	return nil, errNoNodesWithGPULabel
 }
 
+// Contract is the function that returns the contract of the results of the node group checking.
+//
+// It returns nothing.
+func (c *NodeGroupChecker) Contract() handler.Contract {
+	return handler.Contract{}
+}
+
 // New is the function that creates a new NodeGroupChecker.
-func New(clientset kubernetes.Interface) *NodeGroupChecker {
+func New(checkCtx handler.CheckContext) *NodeGroupChecker {
 	return &NodeGroupChecker{
-		clientset: clientset,
+		clientset: checkCtx.Clientset,
 	}
 }
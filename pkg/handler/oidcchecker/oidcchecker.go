@@ -5,14 +5,29 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"net"
 	"net/http"
+	"net/url"
+	"reflect"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/clock"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/cloud"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/cloud/gcpcloudutil"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/envconfig"
+	pkgerrors "github.com/AlphaSense-Engineering/privatecloud-cli/pkg/errors"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/awsjwtretriever"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/azurejwtretriever"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/util"
+	"github.com/charmbracelet/log"
+	"go.uber.org/multierr"
 )
 
 var (
@@ -24,8 +39,22 @@ var (
 
 	// errOIDCNoJWKSURI is an error that occurs when the OIDC URL has no jwks_uri field in the response.
 	errOIDCNoJWKSURI = errors.New("no jwks_uri field in response returned from OIDC URL")
+
+	// errOIDCIssuerMismatch is an error that occurs when the issuer in the response returned from the OIDC URL is not the OIDC URL.
+	errOIDCIssuerMismatch = errors.New("issuer in response returned from OIDC URL does not match OIDC URL")
+
+	// errOIDCUnsupportedSigningAlg is an error that occurs when the response returned from the OIDC URL does not list the signing algorithm of the JWTs as
+	// supported.
+	errOIDCUnsupportedSigningAlg = errors.New("signing algorithm of JWTs not supported by OIDC URL")
+
+	// errOIDCUnsupportedAudiences is an error that occurs when the response returned from the OIDC URL lists the supported audiences without the ones of the
+	// JWTs.
+	errOIDCUnsupportedAudiences = errors.New("audiences of JWTs not supported by OIDC URL")
 )
 
+// signingAlg is the signing algorithm of the JWTs, which the service account keys of the clusters sign them with.
+const signingAlg = "RS256"
+
 var (
 	// awsOIDCRegex is the regex for the OIDC URL for AWS.
 	awsOIDCRegex = regexp.MustCompile(
@@ -35,8 +64,26 @@ var (
 
 	// azureOIDCRegex is the regex for the OIDC URL for Azure.
 	azureOIDCRegex = regexp.MustCompile(`^https:\/\/.+\.oic\.prod-aks\.azure\.com\/[\w+-]+\/[\w+-]+\/$`)
+
+	// gcpOIDCRegex is the regex for the OIDC URL for GCP, which is the issuer of the workload identity federation of the GKE cluster.
+	gcpOIDCRegex = regexp.MustCompile(`^https:\/\/container\.googleapis\.com\/v1\/projects\/[\w-]+\/locations\/[\w-]+\/clusters\/[\w-]+$`)
 )
 
+// ValidateURLFormat is the function that returns an error if the OIDC URL does not have the format of the ones of the cloud provider.
+//
+// The OIDC URL is optional on GCP, so the empty one is accepted there.
+func ValidateURLFormat(vcloud cloud.Cloud, oidcURL string) error {
+	bytesOIDCURL := []byte(oidcURL)
+
+	if (vcloud == cloud.AWS && !awsOIDCRegex.Match(bytesOIDCURL)) ||
+		(vcloud == cloud.Azure && !azureOIDCRegex.Match(bytesOIDCURL)) ||
+		(vcloud == cloud.GCP && oidcURL != constant.EmptyString && !gcpOIDCRegex.Match(bytesOIDCURL)) {
+		return errOIDCWrongFormat
+	}
+
+	return nil
+}
+
 // httpGetter is an interface for abstracting the http.Client.Get method.
 //
 // There is no real use for this interface besides mocking in tests.
@@ -45,51 +92,258 @@ type httpGetter interface {
 	Get(string) (*http.Response, error)
 }
 
+// resolver is an interface for abstracting the net.Resolver.LookupIPAddr method.
+//
+// There is no real use for this interface besides mocking in tests.
+type resolver interface {
+	// LookupIPAddr looks up the host and returns its IP addresses.
+	LookupIPAddr(context.Context, string) ([]net.IPAddr, error)
+}
+
+var _ resolver = net.DefaultResolver
+
 // OIDCChecker is the OIDC checker.
 type OIDCChecker struct {
+	// logger is the logger.
+	logger *log.Logger
 	// vcloud is the cloud provider.
 	vcloud cloud.Cloud
 	// envConfig is the environment configuration.
 	envConfig *envconfig.EnvConfig
 	// httpGetter is the HTTP getter.
 	httpGetter httpGetter
+	// resolver is the DNS resolver.
+	resolver resolver
+	// clock is the clock that is compared against the one of the OIDC server.
+	clock clock.Clock
+	// maxClockSkew is the maximum difference between the clock and the one of the OIDC server that is not warned about, or zero to not compare them.
+	maxClockSkew time.Duration
+	// checkDiscovery is whether the discovery document is checked to support the signing algorithm and the audiences of the JWTs.
+	checkDiscovery bool
+	// audiences is the audiences that the discovery document is checked to support, or empty for the one of the JWTs of the cloud provider.
+	audiences []string
 }
 
 var _ handler.Handler = &OIDCChecker{}
 
-// Handle is the function that handles the OIDC checking.
+// constSharedAddressSpace is the shared address space of RFC 6598, which the carrier-grade NATs and some cloud networks use and which is not reachable
+// from the internet.
 //
-// The arguments are not used.
-// It returns the JWKS URI on success, or an error on failure.
-func (c *OIDCChecker) Handle(_ context.Context, _ ...any) ([]any, error) {
+// Do not modify this variable, it is supposed to be constant.
+var constSharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// isInternalIP is a function that returns whether the IP address is not reachable from the internet.
+func isInternalIP(ip net.IP) bool {
+	return ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsUnspecified() || constSharedAddressSpace.Contains(ip)
+}
+
+// hostname is a function that returns the host name of the URL, or an empty string if it cannot be parsed.
+func hostname(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return constant.EmptyString
+	}
+
+	return u.Hostname()
+}
+
+// warnIfInternalOnly is the function that warns if the host resolves only to the IP addresses that are not reachable from the internet.
+//
+// Reaching the URL from the pod does not mean that the cloud provider can reach it: AWS STS, Microsoft Entra ID and Google Cloud STS fetch the discovery
+// document and the JWKS of the issuer from the internet to validate the service account tokens, which fails for the issuers of private clusters.
+func (c *OIDCChecker) warnIfInternalOnly(ctx context.Context, host string) {
 	const (
-		// httpsScheme is the scheme for the HTTPS URL.
-		httpsScheme = "https://"
+		// logMsgFailedToResolve is the message that is logged when the host cannot be resolved.
+		logMsgFailedToResolve = "failed to resolve %s to check if it is reachable from the internet: %v"
 
-		// wellKnownEndpoint is the endpoint for the well-known configuration.
-		wellKnownEndpoint = "/.well-known/openid-configuration"
+		// logMsgInternalOnly is the message that is logged when the host resolves only to the internal IP addresses.
+		logMsgInternalOnly = "%s resolves only to private addresses (%s), so %s may fail to reach it to validate the service account tokens; see %s"
+
+		// docsAWSIRSA is the URL to the documentation for the OIDC provider of the IAM roles for service accounts.
+		docsAWSIRSA = "https://docs.aws.amazon.com/eks/latest/userguide/enable-iam-roles-for-service-accounts.html"
+
+		// docsAzureWorkloadIdentity is the URL to the documentation for the OIDC issuer of the workload identity.
+		docsAzureWorkloadIdentity = "https://learn.microsoft.com/en-us/azure/aks/use-oidc-issuer"
+
+		// docsGCPWorkloadIdentity is the URL to the documentation for the workload identity federation of GKE.
+		docsGCPWorkloadIdentity = "https://cloud.google.com/kubernetes-engine/docs/concepts/workload-identity"
 	)
 
-	// In GCP, we don't need to check the OIDC URL as it's not used.
-	if c.vcloud == cloud.GCP {
-		return nil, nil
+	if host == constant.EmptyString {
+		return
 	}
 
-	oidcURL := c.envConfig.OIDCURL()
+	var ips []net.IP
 
-	bytesOIDCURL := []byte(oidcURL)
+	if ip := net.ParseIP(host); ip != nil {
+		ips = []net.IP{ip}
+	} else {
+		addrs, err := c.resolver.LookupIPAddr(ctx, host)
+		if err != nil {
+			c.logger.Debugf(logMsgFailedToResolve, host, err)
+
+			return
+		}
+
+		for _, addr := range addrs {
+			ips = append(ips, addr.IP)
+		}
+	}
+
+	if len(ips) == 0 {
+		return
+	}
+
+	formattedIPs := make([]string, 0, len(ips))
+
+	for _, ip := range ips {
+		if !isInternalIP(ip) {
+			return
+		}
+
+		formattedIPs = append(formattedIPs, ip.String())
+	}
+
+	authority, docs := "AWS STS", docsAWSIRSA
+
+	switch c.vcloud {
+	case cloud.Azure:
+		authority, docs = "Microsoft Entra ID", docsAzureWorkloadIdentity
+	case cloud.GCP:
+		authority, docs = "Google Cloud STS", docsGCPWorkloadIdentity
+	}
+
+	c.logger.Warnf(logMsgInternalOnly, host, strings.Join(formattedIPs, ", "), authority, docs)
+}
+
+// warnIfClockSkewed is the function that warns if the clock differs from the one of the server, as of the date of its response, by more than the maximum
+// clock skew.
+//
+// The JWTs are validated against their issued at, not before and expiration times, so a skewed clock of the node makes them fail the validation as not valid
+// yet or expired, which does not point at the clock on its own. The response of a cache, such as a CDN in front of the issuer, carries the date of the
+// response of the server it cached along with its age, which is added to the date.
+func (c *OIDCChecker) warnIfClockSkewed(host string, header http.Header) {
+	// logMsgClockSkewed is the message that is logged when the clock differs from the one of the server by more than the maximum clock skew.
+	const logMsgClockSkewed = "clock is %s %s the one of %s, more than %s, so the JWTs may fail validation as not valid yet or expired; " +
+		"check the time synchronization of the nodes"
+
+	date := header.Get("Date")
+
+	if c.maxClockSkew <= 0 || date == constant.EmptyString {
+		return
+	}
+
+	serverTime, err := http.ParseTime(date)
+	if err != nil {
+		return
+	}
 
-	if (c.vcloud == cloud.AWS && !awsOIDCRegex.Match(bytesOIDCURL)) ||
-		(c.vcloud == cloud.Azure && !azureOIDCRegex.Match(bytesOIDCURL)) {
-		return nil, errOIDCWrongFormat
+	if age, err := strconv.Atoi(header.Get("Age")); err == nil && age > 0 {
+		serverTime = serverTime.Add(time.Duration(age) * time.Second)
 	}
 
-	formattedURL := strings.TrimSuffix(oidcURL, string(constant.HTTPPathSeparator)) + wellKnownEndpoint
+	skew, direction := c.clock.Now().Sub(serverTime), "ahead of"
 
-	if !strings.HasPrefix(formattedURL, httpsScheme) {
-		formattedURL = httpsScheme + formattedURL
+	if skew < 0 {
+		skew, direction = -skew, "behind"
 	}
 
+	if skew > c.maxClockSkew {
+		c.logger.Warnf(logMsgClockSkewed, skew.Truncate(time.Second), direction, host, c.maxClockSkew)
+	}
+}
+
+// discoveryDocument is the type that contains the fields of the discovery document returned from the OIDC URL that are checked.
+type discoveryDocument struct {
+	// Issuer is the issuer of the tokens, which is supposed to be the OIDC URL.
+	Issuer *string `json:"issuer,omitempty"`
+	// JWKSURI is the JWKS URI that is used for validating the JWT.
+	JWKSURI *string `json:"jwks_uri,omitempty"`
+	// IDTokenSigningAlgValuesSupported is the signing algorithms of the tokens that the issuer supports.
+	IDTokenSigningAlgValuesSupported []string `json:"id_token_signing_alg_values_supported,omitempty"`
+	// AudiencesSupported is the audiences of the tokens that the issuer supports, which some identity providers list although the OIDC discovery
+	// specification does not define it.
+	AudiencesSupported []string `json:"audiences_supported,omitempty"`
+}
+
+// expectedAudiences is the function that returns the audiences that the discovery document is checked to support.
+func (c *OIDCChecker) expectedAudiences() []string {
+	if len(c.audiences) > 0 {
+		return c.audiences
+	}
+
+	switch c.vcloud {
+	case cloud.Azure:
+		return []string{azurejwtretriever.Audience}
+	case cloud.GCP:
+		return []string{gcpcloudutil.WorkloadIdentityPool(c.envConfig.Spec.CloudSpec.GCP.ProjectID)}
+	}
+
+	return []string{awsjwtretriever.Audience}
+}
+
+// validateDiscovery is the function that returns an error if the discovery document shows that the issuer cannot validate the JWTs, as it does not list
+// their signing algorithm as supported, or lists the supported audiences without the expected ones.
+//
+// The signing algorithms are required by the OIDC discovery specification, so the discovery documents without them are rejected, while the audiences are
+// compared only when listed.
+func (c *OIDCChecker) validateDiscovery(data *discoveryDocument) error {
+	if !slices.Contains(data.IDTokenSigningAlgValuesSupported, signingAlg) {
+		return fmt.Errorf("%w: id_token_signing_alg_values_supported has [%s], expected %s", errOIDCUnsupportedSigningAlg,
+			strings.Join(data.IDTokenSigningAlgValuesSupported, ", "), signingAlg)
+	}
+
+	if len(data.AudiencesSupported) == 0 {
+		return nil
+	}
+
+	var missing []string
+
+	for _, audience := range c.expectedAudiences() {
+		if !slices.Contains(data.AudiencesSupported, audience) {
+			missing = append(missing, audience)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: audiences_supported has [%s], expected %s", errOIDCUnsupportedAudiences, strings.Join(data.AudiencesSupported, ", "),
+			strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// Handle is the function that handles the OIDC checking.
+//
+// It warns if the OIDC URL or the JWKS URI appear to be reachable only from within the private network, as the cloud provider needs to reach them, and if
+// the clock is skewed against the one of the OIDC server.
+// If the discovery document is checked, it also returns an error if the issuer cannot validate the JWTs, as of the discovery document.
+//
+// On GCP, the OIDC URL is optional, as the Crossplane role is checked with the workload identity of the provider service account rather than with the JWTs,
+// so it is only checked if set.
+//
+// The arguments are not used.
+// It returns the JWKS URI on success, nothing if the OIDC URL is not set on GCP, or an error on failure.
+func (c *OIDCChecker) Handle(ctx context.Context, _ ...any) ([]any, error) {
+	// wellKnownEndpoint is the endpoint for the well-known configuration.
+	const wellKnownEndpoint = "/.well-known/openid-configuration"
+
+	// The GCP cloud specification is not required when the OIDC URL is not set, so that the environment configurations without it are checked as before.
+	if gcpSpec := c.envConfig.Spec.CloudSpec.GCP; c.vcloud == cloud.GCP && (gcpSpec == nil || gcpSpec.OIDCURL == constant.EmptyString) {
+		return nil, nil
+	}
+
+	oidcURL, err := c.envConfig.OIDCURL()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ValidateURLFormat(c.vcloud, oidcURL); err != nil {
+		return nil, err
+	}
+
+	formattedURL := util.NormalizeIssuer(oidcURL) + wellKnownEndpoint
+
 	resp, err := c.httpGetter.Get(formattedURL)
 	if err != nil {
 		return nil, err
@@ -100,10 +354,7 @@ func (c *OIDCChecker) Handle(_ context.Context, _ ...any) ([]any, error) {
 		return nil, errOIDCNon200Response
 	}
 
-	var data struct {
-		// JWKSURI is the JWKS URI that is used for validating the JWT.
-		JWKSURI *string `json:"jwks_uri,omitempty"`
-	}
+	var data discoveryDocument
 
 	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
 		return nil, err
@@ -113,14 +364,52 @@ func (c *OIDCChecker) Handle(_ context.Context, _ ...any) ([]any, error) {
 		return nil, errOIDCNoJWKSURI
 	}
 
+	// The issuer is required by the OIDC discovery specification, but it is compared only when present, so that the discovery documents without it are still
+	// accepted as they were before.
+	if data.Issuer != nil && util.NormalizeIssuer(*data.Issuer) != util.NormalizeIssuer(oidcURL) {
+		return nil, multierr.Combine(errOIDCIssuerMismatch, pkgerrors.NewKeyExpectedGot("issuer", util.NormalizeIssuer(oidcURL), util.NormalizeIssuer(*data.Issuer)))
+	}
+
+	if c.checkDiscovery {
+		if err := c.validateDiscovery(&data); err != nil {
+			return nil, err
+		}
+	}
+
+	issuerHost := hostname(formattedURL)
+
+	c.warnIfClockSkewed(issuerHost, resp.Header)
+
+	c.warnIfInternalOnly(ctx, issuerHost)
+
+	if jwksHost := hostname(*data.JWKSURI); jwksHost != issuerHost {
+		c.warnIfInternalOnly(ctx, jwksHost)
+	}
+
 	return []any{data.JWKSURI}, nil
 }
 
+// Contract is the function that returns the contract of the results of the OIDC checking.
+//
+// It returns the JWKS URI, or nothing if the OIDC URL is not set on GCP.
+func (c *OIDCChecker) Contract() handler.Contract {
+	return handler.Contract{Results: []reflect.Type{reflect.TypeFor[*string]()}, Optional: true}
+}
+
 // New is the function that creates a new OIDCChecker.
-func New(vcloud cloud.Cloud, envConfig *envconfig.EnvConfig, httpGetter httpGetter) *OIDCChecker {
+//
+// The hosts of the issuer and of the JWKS URI are resolved with the default resolver, and the clock is the one of the context, or else the real one.
+func New(checkCtx handler.CheckContext) *OIDCChecker {
 	return &OIDCChecker{
-		vcloud:     vcloud,
-		envConfig:  envConfig,
-		httpGetter: httpGetter,
+		logger:     checkCtx.Logger,
+		vcloud:     checkCtx.VCloud,
+		envConfig:  checkCtx.EnvConfig,
+		httpGetter: checkCtx.HTTPClient,
+		resolver:   net.DefaultResolver,
+		clock:      checkCtx.ClockOrReal(),
+
+		maxClockSkew:   checkCtx.Options.MaxClockSkew,
+		checkDiscovery: checkCtx.Options.CheckOIDCDiscovery,
+		audiences:      checkCtx.Options.OIDCAudiences,
 	}
 }
@@ -4,15 +4,24 @@ package oidcchecker
 import (
 	"bytes"
 	"context"
+	"errors"
 	"io"
+	"net"
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/clock"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/cloud"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/envconfig"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/handlertest"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/util"
+	"github.com/charmbracelet/log"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // mockHTTPGetter is a mock implementation of the httpGetter interface.
@@ -21,18 +30,62 @@ type mockHTTPGetter struct {
 	statusCode int
 	// bodyString is the body to return.
 	bodyString string
+	// header is the header to return.
+	header http.Header
+	// gotURL is the URL of the last request.
+	gotURL string
 }
 
 var _ httpGetter = &mockHTTPGetter{}
 
 // Get is a mock implementation of the Get method.
-func (m *mockHTTPGetter) Get(_ string) (*http.Response, error) {
+func (m *mockHTTPGetter) Get(url string) (*http.Response, error) {
+	m.gotURL = url
+
 	return &http.Response{
 		StatusCode: m.statusCode,
+		Header:     m.header,
 		Body:       io.NopCloser(bytes.NewBufferString(m.bodyString)),
 	}, nil
 }
 
+// errMockLookup is the error that the mock resolver returns for the hosts it has no addresses for.
+var errMockLookup = errors.New("no such host")
+
+// mockResolver is a mock implementation of the resolver interface.
+type mockResolver struct {
+	// addrs is the map of the IP addresses of the hosts.
+	addrs map[string][]string
+}
+
+var _ resolver = &mockResolver{}
+
+// LookupIPAddr is a mock implementation of the LookupIPAddr method.
+func (m *mockResolver) LookupIPAddr(_ context.Context, host string) ([]net.IPAddr, error) {
+	addrs, ok := m.addrs[host]
+	if !ok {
+		return nil, errMockLookup
+	}
+
+	ipAddrs := make([]net.IPAddr, 0, len(addrs))
+
+	for _, addr := range addrs {
+		ipAddrs = append(ipAddrs, net.IPAddr{IP: net.ParseIP(addr)})
+	}
+
+	return ipAddrs, nil
+}
+
+// newTestOIDCChecker is a function that creates a new OIDCChecker that uses the given HTTP getter and resolver instead of the real ones.
+func newTestOIDCChecker(logger *log.Logger, vcloud cloud.Cloud, envConfig *envconfig.EnvConfig, httpGetter httpGetter, resolver resolver) *OIDCChecker {
+	c := New(handler.CheckContext{Logger: logger, VCloud: vcloud, EnvConfig: envConfig})
+
+	c.httpGetter = httpGetter
+	c.resolver = resolver
+
+	return c
+}
+
 // TestOIDCChecker_Handle tests the OIDCChecker.Handle method.
 //
 // nolint:funlen
@@ -170,16 +223,19 @@ func TestOIDCChecker_Handle(t *testing.T) {
 				}
 			}
 
-			oidcChecker := New(
+			oidcChecker := newTestOIDCChecker(
+				log.New(io.Discard),
 				tc.cloud,
 				envCfg,
 				&mockHTTPGetter{
 					statusCode: tc.statusCode,
 					bodyString: tc.bodyString,
 				},
+				&mockResolver{},
 			)
 
 			gotJWKSURI, gotErr := oidcChecker.Handle(context.TODO())
+			handlertest.AssertContract(t, oidcChecker, gotJWKSURI, gotErr)
 
 			if tc.wantErr != nil {
 				assert.Equal(t, tc.wantErr, gotErr, "got %v, want %v", gotErr, tc.wantErr)
@@ -191,3 +247,467 @@ func TestOIDCChecker_Handle(t *testing.T) {
 		})
 	}
 }
+
+// TestOIDCChecker_Handle_internalOnly tests that the OIDCChecker.Handle method warns when the issuer or the JWKS URI resolve only to private addresses.
+//
+// nolint:funlen
+func TestOIDCChecker_Handle_internalOnly(t *testing.T) {
+	const (
+		// issuerHost is the host of the OIDC issuer.
+		issuerHost = "example.oic.prod-aks.azure.com"
+
+		// jwksHost is the host of the JWKS URI.
+		jwksHost = "keys.example.com"
+
+		// bodyString is the body of the discovery document.
+		bodyString = `{"jwks_uri": "https://` + jwksHost + `/keys"}`
+	)
+
+	testCases := []struct {
+		name      string
+		addrs     map[string][]string
+		wantWarns []string
+	}{
+		{
+			name:  "Public",
+			addrs: map[string][]string{issuerHost: {"20.50.2.1"}, jwksHost: {"20.50.2.2"}},
+		},
+		{
+			name:      "Private issuer",
+			addrs:     map[string][]string{issuerHost: {"10.0.0.5", "192.168.1.5"}, jwksHost: {"20.50.2.2"}},
+			wantWarns: []string{issuerHost + " resolves only to private addresses (10.0.0.5, 192.168.1.5)"},
+		},
+		{
+			name:  "Partly public issuer",
+			addrs: map[string][]string{issuerHost: {"10.0.0.5", "20.50.2.1"}, jwksHost: {"20.50.2.2"}},
+		},
+		{
+			name:      "Shared address space issuer",
+			addrs:     map[string][]string{issuerHost: {"100.64.0.5", "100.127.255.1"}, jwksHost: {"20.50.2.2"}},
+			wantWarns: []string{issuerHost + " resolves only to private addresses (100.64.0.5, 100.127.255.1)"},
+		},
+		{
+			name:  "Next to the shared address space issuer",
+			addrs: map[string][]string{issuerHost: {"100.128.0.5"}, jwksHost: {"20.50.2.2"}},
+		},
+		{
+			name:      "Private JWKS URI",
+			addrs:     map[string][]string{issuerHost: {"20.50.2.1"}, jwksHost: {"fd00::1"}},
+			wantWarns: []string{jwksHost + " resolves only to private addresses (fd00::1)"},
+		},
+		{
+			name: "Unresolvable",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			envCfg := &envconfig.EnvConfig{
+				Spec: envconfig.Spec{
+					CloudSpec: envconfig.CloudSpec{
+						Provider: string(cloud.Azure),
+						Azure:    &envconfig.AzureSpec{OIDCURL: "https://" + issuerHost + "/foo/bar/"},
+					},
+				},
+			}
+
+			oidcChecker := newTestOIDCChecker(
+				log.New(&buf),
+				cloud.Azure,
+				envCfg,
+				&mockHTTPGetter{statusCode: http.StatusOK, bodyString: bodyString},
+				&mockResolver{addrs: tc.addrs},
+			)
+
+			_, err := oidcChecker.Handle(context.TODO())
+			require.NoError(t, err)
+
+			if tc.wantWarns == nil {
+				assert.NotContains(t, buf.String(), "WARN")
+
+				return
+			}
+
+			for _, want := range tc.wantWarns {
+				assert.Contains(t, buf.String(), want)
+			}
+
+			assert.Contains(t, buf.String(), "Microsoft Entra ID")
+		})
+	}
+}
+
+// TestOIDCChecker_Handle_azure tests that the OIDCChecker.Handle method fetches the discovery document of the Azure issuer from the well-known URL, extracts
+// the JWKS URI, and compares the issuer regardless of its trailing slash.
+//
+// nolint:funlen
+func TestOIDCChecker_Handle_azure(t *testing.T) {
+	const (
+		// oidcURL is the Azure OIDC URL, with the trailing slash that the format requires.
+		oidcURL = "https://eastus.oic.prod-aks.azure.com/00000000-0000-0000-0000-000000000000/11111111-1111-1111-1111-111111111111/"
+
+		// wantURL is the expected URL of the discovery document.
+		wantURL = "https://eastus.oic.prod-aks.azure.com/00000000-0000-0000-0000-000000000000/11111111-1111-1111-1111-111111111111" +
+			"/.well-known/openid-configuration"
+
+		// jwksURI is the JWKS URI in the discovery document.
+		jwksURI = "https://eastus.oic.prod-aks.azure.com/00000000-0000-0000-0000-000000000000/11111111-1111-1111-1111-111111111111/openid/v1/jwks"
+	)
+
+	testCases := []struct {
+		name       string
+		bodyString string
+		wantErr    error
+	}{
+		{
+			name:       "Issuer with trailing slash",
+			bodyString: `{"issuer": "` + oidcURL + `", "jwks_uri": "` + jwksURI + `"}`,
+		},
+		{
+			name:       "Issuer without trailing slash",
+			bodyString: `{"issuer": "` + strings.TrimSuffix(oidcURL, "/") + `", "jwks_uri": "` + jwksURI + `"}`,
+		},
+		{
+			name:       "No issuer",
+			bodyString: `{"jwks_uri": "` + jwksURI + `"}`,
+		},
+		{
+			name:       "Other issuer",
+			bodyString: `{"issuer": "https://westus.oic.prod-aks.azure.com/other/cluster/", "jwks_uri": "` + jwksURI + `"}`,
+			wantErr:    errOIDCIssuerMismatch,
+		},
+		{
+			name:       "No JWKS URI",
+			bodyString: `{"issuer": "` + oidcURL + `"}`,
+			wantErr:    errOIDCNoJWKSURI,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			envCfg := &envconfig.EnvConfig{
+				Spec: envconfig.Spec{
+					CloudSpec: envconfig.CloudSpec{
+						Provider: string(cloud.Azure),
+						Azure:    &envconfig.AzureSpec{OIDCURL: oidcURL},
+					},
+				},
+			}
+
+			getter := &mockHTTPGetter{statusCode: http.StatusOK, bodyString: tc.bodyString}
+
+			got, err := newTestOIDCChecker(log.New(io.Discard), cloud.Azure, envCfg, getter, &mockResolver{}).Handle(context.TODO())
+
+			assert.Equal(t, wantURL, getter.gotURL)
+
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, []any{util.Ref(jwksURI)}, got)
+		})
+	}
+}
+
+// TestOIDCChecker_Handle_gcp tests that the OIDCChecker.Handle method checks the OIDC URL of the workload identity federation of the GKE cluster only if it
+// is set, fetching its discovery document from the well-known URL and extracting the JWKS URI.
+//
+// nolint:funlen
+func TestOIDCChecker_Handle_gcp(t *testing.T) {
+	const (
+		// oidcURL is the GCP OIDC URL.
+		oidcURL = "https://container.googleapis.com/v1/projects/test-project/locations/us-central1/clusters/test-cluster"
+
+		// wantURL is the expected URL of the discovery document.
+		wantURL = oidcURL + "/.well-known/openid-configuration"
+
+		// jwksURI is the JWKS URI in the discovery document.
+		jwksURI = oidcURL + "/jwks"
+	)
+
+	testCases := []struct {
+		name           string
+		gcpSpec        *envconfig.GCPSpec
+		checkDiscovery bool
+		bodyString     string
+		wantURL        string
+		want           []any
+		wantErr        error
+	}{
+		{
+			name: "No GCP cloud specification",
+		},
+		{
+			name:    "No OIDC URL",
+			gcpSpec: &envconfig.GCPSpec{ProjectID: "test-project"},
+		},
+		{
+			name:       "Valid OIDC URL",
+			gcpSpec:    &envconfig.GCPSpec{ProjectID: "test-project", OIDCURL: oidcURL},
+			bodyString: `{"issuer": "` + oidcURL + `", "jwks_uri": "` + jwksURI + `"}`,
+			wantURL:    wantURL,
+			want:       []any{util.Ref(jwksURI)},
+		},
+		{
+			name:           "Discovery with workload identity pool",
+			gcpSpec:        &envconfig.GCPSpec{ProjectID: "test-project", OIDCURL: oidcURL},
+			checkDiscovery: true,
+			bodyString: `{"issuer": "` + oidcURL + `", "jwks_uri": "` + jwksURI + `", "id_token_signing_alg_values_supported": ["RS256"], ` +
+				`"audiences_supported": ["test-project.svc.id.goog"]}`,
+			wantURL: wantURL,
+			want:    []any{util.Ref(jwksURI)},
+		},
+		{
+			name:           "Discovery without workload identity pool",
+			gcpSpec:        &envconfig.GCPSpec{ProjectID: "test-project", OIDCURL: oidcURL},
+			checkDiscovery: true,
+			bodyString: `{"issuer": "` + oidcURL + `", "jwks_uri": "` + jwksURI + `", "id_token_signing_alg_values_supported": ["RS256"], ` +
+				`"audiences_supported": ["other-project.svc.id.goog"]}`,
+			wantURL: wantURL,
+			wantErr: errOIDCUnsupportedAudiences,
+		},
+		{
+			name:       "Other issuer",
+			gcpSpec:    &envconfig.GCPSpec{ProjectID: "test-project", OIDCURL: oidcURL},
+			bodyString: `{"issuer": "https://container.googleapis.com/v1/projects/other/locations/us-central1/clusters/other", "jwks_uri": "` + jwksURI + `"}`,
+			wantURL:    wantURL,
+			wantErr:    errOIDCIssuerMismatch,
+		},
+		{
+			name:    "Wrong format",
+			gcpSpec: &envconfig.GCPSpec{ProjectID: "test-project", OIDCURL: "https://container.googleapis.com/v1/projects/test-project"},
+			wantErr: errOIDCWrongFormat,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			envCfg := &envconfig.EnvConfig{
+				Spec: envconfig.Spec{
+					CloudSpec: envconfig.CloudSpec{
+						Provider: string(cloud.GCP),
+						GCP:      tc.gcpSpec,
+					},
+				},
+			}
+
+			getter := &mockHTTPGetter{statusCode: http.StatusOK, bodyString: tc.bodyString}
+
+			c := newTestOIDCChecker(log.New(io.Discard), cloud.GCP, envCfg, getter, &mockResolver{})
+			c.checkDiscovery = tc.checkDiscovery
+
+			got, err := c.Handle(context.TODO())
+			handlertest.AssertContract(t, c, got, err)
+
+			assert.Equal(t, tc.wantURL, getter.gotURL)
+
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+// TestOIDCChecker_Handle_clockSkew is a test that tests that the Handle function warns if the clock is skewed against the Date header of the response of
+// the OIDC server, along with its Age header for the cached responses, by more than the maximum clock skew.
+func TestOIDCChecker_Handle_clockSkew(t *testing.T) {
+	now := time.Date(2026, time.January, 2, 3, 4, 5, 0, time.UTC)
+
+	testCases := []struct {
+		name         string
+		date         string
+		age          string
+		maxClockSkew time.Duration
+		wantWarn     string
+	}{
+		{
+			name:         "Clock ahead",
+			date:         now.Add(-5 * time.Minute).Format(http.TimeFormat),
+			maxClockSkew: time.Minute,
+			wantWarn:     "clock is 5m0s ahead of the one of oidc.eks.us-west-2.amazonaws.com, more than 1m0s",
+		},
+		{
+			name:         "Clock behind",
+			date:         now.Add(2 * time.Minute).Format(http.TimeFormat),
+			maxClockSkew: time.Minute,
+			wantWarn:     "clock is 2m0s behind the one of oidc.eks.us-west-2.amazonaws.com, more than 1m0s",
+		},
+		{
+			name:         "Within the maximum clock skew",
+			date:         now.Add(10 * time.Second).Format(http.TimeFormat),
+			maxClockSkew: time.Minute,
+		},
+		{
+			name:         "Cached by a CDN",
+			date:         now.Add(-time.Hour).Format(http.TimeFormat),
+			age:          "3590",
+			maxClockSkew: time.Minute,
+		},
+		{
+			name:         "Cached by a CDN with the clock ahead",
+			date:         now.Add(-time.Hour).Format(http.TimeFormat),
+			age:          "3300",
+			maxClockSkew: time.Minute,
+			wantWarn:     "clock is 5m0s ahead of the one of oidc.eks.us-west-2.amazonaws.com, more than 1m0s",
+		},
+		{
+			name:         "Invalid Age header",
+			date:         now.Add(10 * time.Second).Format(http.TimeFormat),
+			age:          "soon",
+			maxClockSkew: time.Minute,
+		},
+		{
+			name:         "No Date header",
+			maxClockSkew: time.Minute,
+		},
+		{
+			name: "Disabled",
+			date: now.Add(-time.Hour).Format(http.TimeFormat),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			envConfig := &envconfig.EnvConfig{}
+			envConfig.Spec.CloudSpec.Provider = string(cloud.AWS)
+			envConfig.Spec.CloudSpec.AWS = &envconfig.AWSSpec{OIDCURL: "oidc.eks.us-west-2.amazonaws.com/id/foo"}
+
+			header := http.Header{}
+
+			if tc.date != constant.EmptyString {
+				header.Set("Date", tc.date)
+			}
+
+			if tc.age != constant.EmptyString {
+				header.Set("Age", tc.age)
+			}
+
+			getter := &mockHTTPGetter{statusCode: http.StatusOK, bodyString: `{"jwks_uri": "https://oidc.eks.us-west-2.amazonaws.com/id/foo/keys"}`, header: header}
+
+			c := newTestOIDCChecker(log.New(&buf), cloud.AWS, envConfig, getter, &mockResolver{})
+			c.clock = clock.NewFake(now)
+			c.maxClockSkew = tc.maxClockSkew
+
+			_, err := c.Handle(context.Background())
+			require.NoError(t, err)
+
+			if tc.wantWarn == constant.EmptyString {
+				assert.NotContains(t, buf.String(), "clock is")
+
+				return
+			}
+
+			assert.Contains(t, buf.String(), tc.wantWarn)
+		})
+	}
+}
+
+// TestOIDCChecker_Handle_discovery is a test that tests that the Handle function checks that the discovery document supports the signing algorithm and the
+// audiences of the JWTs only when it is asked to.
+//
+// nolint:funlen
+func TestOIDCChecker_Handle_discovery(t *testing.T) {
+	// jwksURI is the JWKS URI in the discovery documents.
+	const jwksURI = `"jwks_uri": "https://oidc.eks.us-west-2.amazonaws.com/id/foo/keys"`
+
+	testCases := []struct {
+		name           string
+		vcloud         cloud.Cloud
+		bodyString     string
+		checkDiscovery bool
+		audiences      []string
+		wantErr        error
+	}{
+		{
+			name:       "Not checked",
+			vcloud:     cloud.AWS,
+			bodyString: `{` + jwksURI + `}`,
+		},
+		{
+			name:           "Supported signing algorithm",
+			vcloud:         cloud.AWS,
+			bodyString:     `{` + jwksURI + `, "id_token_signing_alg_values_supported": ["ES256", "RS256"]}`,
+			checkDiscovery: true,
+		},
+		{
+			name:           "No signing algorithms",
+			vcloud:         cloud.AWS,
+			bodyString:     `{` + jwksURI + `}`,
+			checkDiscovery: true,
+			wantErr:        errOIDCUnsupportedSigningAlg,
+		},
+		{
+			name:           "Other signing algorithms",
+			vcloud:         cloud.AWS,
+			bodyString:     `{` + jwksURI + `, "id_token_signing_alg_values_supported": ["ES256"]}`,
+			checkDiscovery: true,
+			wantErr:        errOIDCUnsupportedSigningAlg,
+		},
+		{
+			name:           "Supported audience",
+			vcloud:         cloud.AWS,
+			bodyString:     `{` + jwksURI + `, "id_token_signing_alg_values_supported": ["RS256"], "audiences_supported": ["amazonaws.com"]}`,
+			checkDiscovery: true,
+		},
+		{
+			name:           "Unsupported audience",
+			vcloud:         cloud.Azure,
+			bodyString:     `{` + jwksURI + `, "id_token_signing_alg_values_supported": ["RS256"], "audiences_supported": ["amazonaws.com"]}`,
+			checkDiscovery: true,
+			wantErr:        errOIDCUnsupportedAudiences,
+		},
+		{
+			name:           "Custom audiences",
+			vcloud:         cloud.AWS,
+			bodyString:     `{` + jwksURI + `, "id_token_signing_alg_values_supported": ["RS256"], "audiences_supported": ["sts.amazonaws.com", "custom"]}`,
+			checkDiscovery: true,
+			audiences:      []string{"custom", "sts.amazonaws.com"},
+		},
+		{
+			name:           "Unsupported custom audience",
+			vcloud:         cloud.AWS,
+			bodyString:     `{` + jwksURI + `, "id_token_signing_alg_values_supported": ["RS256"], "audiences_supported": ["amazonaws.com"]}`,
+			checkDiscovery: true,
+			audiences:      []string{"amazonaws.com", "custom"},
+			wantErr:        errOIDCUnsupportedAudiences,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			envConfig := &envconfig.EnvConfig{}
+			envConfig.Spec.CloudSpec.Provider = string(tc.vcloud)
+			envConfig.Spec.CloudSpec.AWS = &envconfig.AWSSpec{OIDCURL: "oidc.eks.us-west-2.amazonaws.com/id/foo"}
+			envConfig.Spec.CloudSpec.Azure = &envconfig.AzureSpec{OIDCURL: "https://example.oic.prod-aks.azure.com/foo/bar/"}
+
+			getter := &mockHTTPGetter{statusCode: http.StatusOK, bodyString: tc.bodyString}
+
+			c := newTestOIDCChecker(log.New(io.Discard), tc.vcloud, envConfig, getter, &mockResolver{})
+			c.checkDiscovery = tc.checkDiscovery
+			c.audiences = tc.audiences
+
+			results, err := c.Handle(context.Background())
+			handlertest.AssertContract(t, c, results, err)
+
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
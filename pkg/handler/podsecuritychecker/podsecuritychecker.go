@@ -0,0 +1,92 @@
+// Package podsecuritychecker is the package that contains the check functions for the PodSecurity admission of a namespace.
+package podsecuritychecker
+
+import (
+	"context"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/k8s/kubeutil"
+	"github.com/charmbracelet/log"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// LabelEnforce is the label of the namespace that sets the PodSecurity standard enforced on its pods.
+	LabelEnforce = "pod-security.kubernetes.io/enforce"
+
+	// LevelRestricted is the PodSecurity standard that requires the pods to be hardened.
+	LevelRestricted = "restricted"
+)
+
+// PodSecurityChecker is the type that contains the check functions for the PodSecurity admission of a namespace.
+type PodSecurityChecker struct {
+	// logger is the logger.
+	logger *log.Logger
+	// clientset is the Kubernetes client.
+	clientset kubernetes.Interface
+	// namespace is the namespace in which the pod is created.
+	namespace string
+}
+
+var _ handler.Handler = &PodSecurityChecker{}
+
+// Handle is the function that handles the PodSecurity admission checking.
+//
+// The argument is expected to be the requested pod security profile.
+// It returns the pod security profile to create the pod in the namespace with, which is the restricted one if the namespace enforces the restricted
+// PodSecurity standard, or the requested one otherwise. It does not fail if the namespace cannot be read, as the pod creation reports the rejection
+// anyway.
+func (c *PodSecurityChecker) Handle(ctx context.Context, args ...any) ([]any, error) {
+	const (
+		// logMsgNamespaceNotRead is the message that is logged when the namespace cannot be read.
+		logMsgNamespaceNotRead = "could not read the PodSecurity standard enforced in the %s namespace: %s"
+
+		// logMsgRestrictedCompliant is the message that is logged when the namespace enforces the restricted PodSecurity standard and the requested pod
+		// security profile complies with it.
+		logMsgRestrictedCompliant = "%s namespace enforces the " + LevelRestricted + " PodSecurity standard, which the pod security profile complies with"
+
+		// logMsgRestrictedEnforced is the message that is logged when the namespace enforces the restricted PodSecurity standard.
+		logMsgRestrictedEnforced = "%s namespace enforces the " + LevelRestricted + " PodSecurity standard, using the " +
+			kubeutil.PodSecurityProfileRestricted + " pod security profile instead of %s"
+	)
+
+	profile := handler.ArgAsType[string](args, 0)
+
+	if err := kubeutil.ValidatePodSecurityProfile(profile); err != nil {
+		return nil, err
+	}
+
+	namespace, err := c.clientset.CoreV1().Namespaces().Get(ctx, c.namespace, metav1.GetOptions{})
+	if err != nil {
+		c.logger.Debugf(logMsgNamespaceNotRead, c.namespace, err)
+
+		return []any{profile}, nil
+	}
+
+	if namespace.Labels[LabelEnforce] != LevelRestricted {
+		return []any{profile}, nil
+	}
+
+	if profile != kubeutil.PodSecurityProfileRestricted {
+		c.logger.Warnf(logMsgRestrictedEnforced, c.namespace, profile)
+
+		return []any{kubeutil.PodSecurityProfileRestricted}, nil
+	}
+
+	c.logger.Debugf(logMsgRestrictedCompliant, c.namespace)
+
+	return []any{profile}, nil
+}
+
+// Contract is the function that returns the contract of the results of the PodSecurity admission checking.
+//
+// It returns the pod security profile.
+func (c *PodSecurityChecker) Contract() handler.Contract {
+	return handler.Returns[string]()
+}
+
+// New is a function that returns a new PodSecurityChecker.
+func New(checkCtx handler.CheckContext, namespace string) *PodSecurityChecker {
+	return &PodSecurityChecker{logger: checkCtx.Logger, clientset: checkCtx.Clientset, namespace: namespace}
+}
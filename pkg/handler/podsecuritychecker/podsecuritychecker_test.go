@@ -0,0 +1,105 @@
+// Package podsecuritychecker is the package that contains the check functions for the PodSecurity admission of a namespace.
+package podsecuritychecker
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/handlertest"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/k8s/kubeutil"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/util"
+	"github.com/charmbracelet/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// TestPodSecurityChecker_Handle is a test that tests the Handle function of the PodSecurityChecker.
+func TestPodSecurityChecker_Handle(t *testing.T) {
+	const namespace = "test"
+
+	testCases := []struct {
+		name     string
+		labels   map[string]string
+		missing  bool
+		profile  string
+		want     string
+		wantWarn bool
+		wantErr  error
+	}{
+		{
+			name:    "Unlabeled namespace",
+			profile: kubeutil.PodSecurityProfileUnconfined,
+			want:    kubeutil.PodSecurityProfileUnconfined,
+		},
+		{
+			name:    "Baseline namespace",
+			labels:  map[string]string{LabelEnforce: "baseline"},
+			profile: kubeutil.PodSecurityProfileUnconfined,
+			want:    kubeutil.PodSecurityProfileUnconfined,
+		},
+		{
+			name:    "Restricted namespace with the restricted profile",
+			labels:  map[string]string{LabelEnforce: LevelRestricted},
+			profile: kubeutil.PodSecurityProfileRestricted,
+			want:    kubeutil.PodSecurityProfileRestricted,
+		},
+		{
+			name:     "Restricted namespace with the unconfined profile",
+			labels:   map[string]string{LabelEnforce: LevelRestricted},
+			profile:  kubeutil.PodSecurityProfileUnconfined,
+			want:     kubeutil.PodSecurityProfileRestricted,
+			wantWarn: true,
+		},
+		{
+			name:    "Missing namespace",
+			missing: true,
+			profile: kubeutil.PodSecurityProfileUnconfined,
+			want:    kubeutil.PodSecurityProfileUnconfined,
+		},
+		{
+			name:    "Invalid profile",
+			profile: "privileged",
+			wantErr: kubeutil.ErrInvalidPodSecurityProfile,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var objects []runtime.Object
+
+			if !tc.missing {
+				objects = append(objects, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace, Labels: tc.labels}})
+			}
+
+			var buf bytes.Buffer
+
+			c := New(handler.CheckContext{Logger: log.New(&buf), Clientset: fake.NewClientset(objects...)}, namespace)
+
+			results, err := c.Handle(context.Background(), tc.profile)
+			handlertest.AssertContract(t, c, results, err)
+
+			got, err := util.UnwrapValErr[string](results, err)
+
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+
+			if tc.wantWarn {
+				assert.Contains(t, buf.String(), "enforces the restricted PodSecurity standard")
+			} else {
+				assert.Empty(t, buf.String())
+			}
+		})
+	}
+}
@@ -3,21 +3,104 @@ package postgresqlchecker
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"maps"
+	"net"
 	"net/url"
+	"regexp"
+	"slices"
+	"time"
 
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
+	pkgerrors "github.com/AlphaSense-Engineering/privatecloud-cli/pkg/errors"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/k8s/kubeutil"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/util"
+	"github.com/charmbracelet/log"
 	"github.com/jackc/pgx/v5"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
+var (
+	// errInvalidExpectedConfigOverride is the error that is returned when the override of the expected configuration of the PostgreSQL is not valid.
+	errInvalidExpectedConfigOverride = errors.New("invalid override of the expected PostgreSQL configuration")
+
+	// settingNameRegex is the regular expression that the names of the settings of the PostgreSQL match, including the ones of the extensions, which are
+	// qualified with their name.
+	settingNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+	// constExpectedConfig is the map of expected configuration for the PostgreSQL.
+	//
+	// It is empty, as the Private Cloud documentation does not require any setting of the PostgreSQL; the settings to expect are given with the override.
+	//
+	// Do not modify this variable, it is supposed to be constant.
+	constExpectedConfig = map[string]string{}
+)
+
+// expectedConfig is the function that returns the expected configuration of the PostgreSQL with the override merged over it.
+//
+// It returns an error if a name of the override is not the one of a setting.
+func expectedConfig(override map[string]string) (map[string]string, error) {
+	config := maps.Clone(constExpectedConfig)
+
+	for setting, value := range override {
+		if !settingNameRegex.MatchString(setting) {
+			return nil, fmt.Errorf("%w: %q is not the name of a setting", errInvalidExpectedConfigOverride, setting)
+		}
+
+		config[setting] = value
+	}
+
+	return config, nil
+}
+
+// ValidateExpectedConfigOverride is the function that returns an error if the override of the expected configuration of the PostgreSQL is not valid, so
+// that it is reported before the check runs.
+func ValidateExpectedConfigOverride(override map[string]string) error {
+	_, err := expectedConfig(override)
+
+	return err
+}
+
+// SecretName is the name of the secret that contains the PostgreSQL credentials.
+//
+// nolint:gosec
+const SecretName = "spicedb-creds"
+
+// constSecretKeys is the list of the keys that the check reads from the secret.
+//
+// Do not modify this variable, it is supposed to be constant.
+var constSecretKeys = []string{constant.SecretUsernameKey, constant.SecretPasswordKey, constant.SecretEndpointKey, constant.SecretPortKey}
+
+// SecretKeys is the function that returns the keys that the check reads from the secret, which the keys of the options replace.
+func SecretKeys() []string {
+	return slices.Clone(constSecretKeys)
+}
+
 // PostgreSQLChecker is the type that contains the check functions for the PostgreSQL.
 type PostgreSQLChecker struct {
+	// logger is the logger.
+	logger *log.Logger
 	// clientset is the Kubernetes client.
 	clientset kubernetes.Interface
+	// namespace is the namespace of the secret, under the namespace prefix of the options.
+	namespace string
+	// secretName is the name of the secret that contains the PostgreSQL credentials.
+	secretName string
+	// secretKeys is the keys of the secret that replace the default ones, by the default ones.
+	secretKeys map[string]string
+	// tlsConfig is the TLS configuration to use for the connection, or nil to connect without TLS.
+	tlsConfig *tls.Config
+	// connectTimeout is the maximum duration of establishing the connection.
+	connectTimeout time.Duration
+	// checkSpiceDB is whether the capabilities that SpiceDB requires from the user are checked.
+	checkSpiceDB bool
+	// spiceDBDatabase is the name of the database that SpiceDB uses.
+	spiceDBDatabase string
+	// expectedConfigOverride is the override of the expected configuration, merged over the default one.
+	expectedConfigOverride map[string]string
 }
 
 var _ handler.Handler = &PostgreSQLChecker{}
@@ -36,41 +119,87 @@ func (c *PostgreSQLChecker) buildConnString(username string, password string, en
 
 		// sslmodeDisable is the disabled SSL mode value in the query parameters.
 		sslmodeDisable = "disable"
+
+		// sslmodeVerifyFull is the SSL mode value in the query parameters that verifies both the certificate chain and the host name.
+		sslmodeVerifyFull = "verify-full"
 	)
 
+	sslmode := sslmodeDisable
+
+	if c.tlsConfig != nil {
+		sslmode = sslmodeVerifyFull
+	}
+
 	u := &url.URL{
 		Scheme: scheme,
 		User:   url.UserPassword(username, password),
 		Host:   fmt.Sprintf("%s:%s", endpoint, port),
 		Path:   database,
 		RawQuery: url.Values{
-			sslmodeKey: []string{sslmodeDisable},
+			sslmodeKey: []string{sslmode},
 		}.Encode(),
 	}
 
 	return u.String()
 }
 
+// buildConnConfig is a function that builds the configuration of the connection to the PostgreSQL from the data of the secret.
+func (c *PostgreSQLChecker) buildConnConfig(data map[string]string) (*pgx.ConnConfig, error) {
+	connString := c.buildConnString(
+		data[constant.SecretUsernameKey],
+		data[constant.SecretPasswordKey],
+		data[constant.SecretEndpointKey],
+		data[constant.SecretPortKey],
+	)
+
+	connConfig, err := pgx.ParseConfig(connString)
+	if err != nil {
+		return nil, err
+	}
+
+	connConfig.ConnectTimeout = c.connectTimeout
+
+	if c.tlsConfig != nil {
+		connConfig.TLSConfig = c.tlsConfig.Clone()
+		connConfig.TLSConfig.ServerName = data[constant.SecretEndpointKey]
+	}
+
+	return connConfig, nil
+}
+
+// warnIPEndpoint is a function that warns if the endpoint is an IP address rather than a DNS name, as the IP addresses of the managed databases change on
+// failovers.
+func (c *PostgreSQLChecker) warnIPEndpoint(endpoint string) {
+	// logMsgIPEndpoint is the message that is logged when the endpoint is an IP address.
+	const logMsgIPEndpoint = "PostgreSQL endpoint %s is an IP address, which breaks once the database fails over; use the hostname of the managed endpoint " +
+		"instead"
+
+	if net.ParseIP(endpoint) != nil {
+		c.logger.Warnf(logMsgIPEndpoint, endpoint)
+	}
+}
+
 // Handle is the function that handles the PostgreSQL checking.
 //
+// It warns, without failing, if the endpoint is an IP address rather than a DNS name. The settings are compared with the expected configuration in the
+// order of their names, so that the first mismatch is the same on every run.
+//
 // The arguments are not used.
 // It returns nothing on success, or an error on failure.
 func (c *PostgreSQLChecker) Handle(ctx context.Context, _ ...any) ([]any, error) {
-	const (
-		// secretName is the name of the secret that contains the PostgreSQL credentials.
-		//
-		// nolint:gosec
-		secretName = "spicedb-creds"
-	)
+	config, err := expectedConfig(c.expectedConfigOverride)
+	if err != nil {
+		return nil, err
+	}
 
-	secret, err := c.clientset.CoreV1().Secrets(constant.NamespacePostgres).Get(ctx, secretName, metav1.GetOptions{})
+	data, err := kubeutil.GetSecretData(ctx, c.clientset, c.namespace, c.secretName)
 	if err != nil {
 		return nil, err
 	}
 
-	data := util.ConvertMap(secret.Data, util.Identity[string], util.ByteSliceToString)
+	data = kubeutil.ReplaceSecretKeys(data, c.secretKeys)
 
-	if err := util.KeysExistAndNotEmptyOrErr(data, []string{
+	if err := util.KeysExistAndNotBlankOrErr(data, []string{
 		constant.SecretUsernameKey,
 		constant.SecretPasswordKey,
 		constant.SecretEndpointKey,
@@ -79,14 +208,14 @@ func (c *PostgreSQLChecker) Handle(ctx context.Context, _ ...any) ([]any, error)
 		return nil, err
 	}
 
-	connString := c.buildConnString(
-		data[constant.SecretUsernameKey],
-		data[constant.SecretPasswordKey],
-		data[constant.SecretEndpointKey],
-		data[constant.SecretPortKey],
-	)
+	c.warnIPEndpoint(data[constant.SecretEndpointKey])
+
+	connConfig, err := c.buildConnConfig(data)
+	if err != nil {
+		return nil, err
+	}
 
-	conn, err := pgx.Connect(ctx, connString)
+	conn, err := pgx.ConnectConfig(ctx, connConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -96,10 +225,63 @@ func (c *PostgreSQLChecker) Handle(ctx context.Context, _ ...any) ([]any, error)
 		return nil, err
 	}
 
+	for _, k := range slices.Sorted(maps.Keys(config)) {
+		var got string
+		if err := conn.QueryRow(ctx, "SELECT current_setting($1)", k).Scan(&got); err != nil {
+			return nil, err
+		}
+
+		if got != config[k] {
+			return nil, pkgerrors.NewKeyExpectedGot(k, config[k], got)
+		}
+	}
+
+	if c.checkSpiceDB {
+		if err := checkSpiceDBCapabilities(ctx, conn, c.spiceDBDatabase, constSpiceDBRequiredExtensions); err != nil {
+			return nil, err
+		}
+	}
+
 	return nil, nil
 }
 
+// Contract is the function that returns the contract of the results of the PostgreSQL checking.
+//
+// It returns nothing.
+func (c *PostgreSQLChecker) Contract() handler.Contract {
+	return handler.Contract{}
+}
+
 // New is a function that returns a new PostgreSQLChecker.
-func New(clientset kubernetes.Interface) *PostgreSQLChecker {
-	return &PostgreSQLChecker{clientset: clientset}
+//
+// The TLS configuration of the options is optional; when it is nil, the connection is established without TLS. The capabilities that SpiceDB requires are
+// checked only when the options ask for it, as some managed PostgreSQL services restrict the introspection they rely on, on the database named in the
+// options, or else the one named SpiceDBDatabase. The override of the expected
+// configuration of the options is merged over the default one, which is empty. It reads the secret named in the options, or else the
+// one named SecretName, by the keys of the options that replace the default ones.
+func New(checkCtx handler.CheckContext) *PostgreSQLChecker {
+	secretName := checkCtx.Options.PostgreSQLSecretName
+
+	if secretName == constant.EmptyString {
+		secretName = SecretName
+	}
+
+	spiceDBDatabase := checkCtx.Options.SpiceDBDatabase
+
+	if spiceDBDatabase == constant.EmptyString {
+		spiceDBDatabase = SpiceDBDatabase
+	}
+
+	return &PostgreSQLChecker{
+		logger:                 checkCtx.Logger,
+		clientset:              checkCtx.Clientset,
+		namespace:              checkCtx.Options.Namespace(constant.NamespacePostgres),
+		secretName:             secretName,
+		secretKeys:             checkCtx.Options.PostgreSQLSecretKeys,
+		tlsConfig:              checkCtx.Options.DBTLSConfig,
+		connectTimeout:         checkCtx.Options.ConnectTimeout,
+		checkSpiceDB:           checkCtx.Options.CheckSpiceDB,
+		spiceDBDatabase:        spiceDBDatabase,
+		expectedConfigOverride: checkCtx.Options.PostgreSQLExpectedConfigOverride,
+	}
 }
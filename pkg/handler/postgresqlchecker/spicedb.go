@@ -0,0 +1,114 @@
+// Package postgresqlchecker is the package that contains the check functions for the PostgreSQL.
+package postgresqlchecker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/multierr"
+)
+
+var (
+	// ErrMissingSpiceDBCapabilities is the error that is returned when the PostgreSQL user lacks the capabilities that SpiceDB requires.
+	ErrMissingSpiceDBCapabilities = errors.New("postgresql user lacks capabilities required by SpiceDB")
+
+	// errFailedToIntrospectPostgreSQL is the error that is returned when the capabilities of the PostgreSQL user cannot be introspected.
+	errFailedToIntrospectPostgreSQL = errors.New("failed to introspect PostgreSQL capabilities, managed PostgreSQL may restrict the introspection")
+)
+
+// SpiceDBDatabase is the default name of the database that SpiceDB uses.
+const SpiceDBDatabase = "spicedb"
+
+// constSpiceDBRequiredExtensions is the list of the PostgreSQL extensions that SpiceDB requires to be installable.
+//
+// SpiceDB does not require any extensions at the moment; the list is kept so that the check covers them once it does.
+//
+// Do not modify this variable, it is supposed to be constant.
+var constSpiceDBRequiredExtensions = []string{}
+
+// querier is the interface of the PostgreSQL connection that runs the introspection queries, implemented by *pgx.Conn.
+type querier interface {
+	// QueryRow is the function that runs the query and returns at most one row.
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+var _ querier = &pgx.Conn{}
+
+// queryBool is a function that runs the query returning a single boolean column.
+func queryBool(ctx context.Context, q querier, sql string, args ...any) (bool, error) {
+	var v bool
+
+	if err := q.QueryRow(ctx, sql, args...).Scan(&v); err != nil {
+		return false, multierr.Combine(errFailedToIntrospectPostgreSQL, err)
+	}
+
+	return v, nil
+}
+
+// checkSpiceDBCapabilities is a function that checks that the PostgreSQL user has the capabilities that SpiceDB requires.
+//
+// If the database exists, the user is required to be able to connect to it and to create schemas in it; otherwise, the user is required to be able to
+// create it. Each of the extensions is required to be available for installation. All of the missing capabilities are reported at once.
+func checkSpiceDBCapabilities(ctx context.Context, q querier, database string, extensions []string) error {
+	const (
+		// queryDatabaseExists is the query that returns whether the database exists.
+		queryDatabaseExists = "SELECT EXISTS (SELECT 1 FROM pg_database WHERE datname = $1)"
+
+		// queryHasDatabasePrivilege is the query that returns whether the current user has the privilege on the database.
+		queryHasDatabasePrivilege = "SELECT has_database_privilege($1, $2)"
+
+		// queryCanCreateDatabase is the query that returns whether the current user can create databases.
+		queryCanCreateDatabase = "SELECT rolcreatedb OR rolsuper FROM pg_roles WHERE rolname = current_user"
+
+		// queryExtensionAvailable is the query that returns whether the extension is available for installation.
+		queryExtensionAvailable = "SELECT EXISTS (SELECT 1 FROM pg_available_extensions WHERE name = $1)"
+	)
+
+	var missing error
+
+	exists, err := queryBool(ctx, q, queryDatabaseExists, database)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		for _, privilege := range []string{"CONNECT", "CREATE"} {
+			ok, err := queryBool(ctx, q, queryHasDatabasePrivilege, database, privilege)
+			if err != nil {
+				return err
+			}
+
+			if !ok {
+				missing = multierr.Append(missing, fmt.Errorf("no %s privilege on database %s", privilege, database))
+			}
+		}
+	} else {
+		ok, err := queryBool(ctx, q, queryCanCreateDatabase)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			missing = multierr.Append(missing, fmt.Errorf("database %s does not exist and the user cannot create databases", database))
+		}
+	}
+
+	for _, extension := range extensions {
+		ok, err := queryBool(ctx, q, queryExtensionAvailable, extension)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			missing = multierr.Append(missing, fmt.Errorf("extension %s is not available for installation", extension))
+		}
+	}
+
+	if missing != nil {
+		return multierr.Combine(ErrMissingSpiceDBCapabilities, missing)
+	}
+
+	return nil
+}
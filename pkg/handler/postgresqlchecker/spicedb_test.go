@@ -0,0 +1,146 @@
+// Package postgresqlchecker is the package that contains the check functions for the PostgreSQL.
+package postgresqlchecker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// errStubQuery is the error that the stub querier returns for the queries it has no answer for.
+var errStubQuery = errors.New("permission denied for table pg_authid")
+
+// stubRow is the type that implements the pgx.Row interface, returning a single boolean value or an error.
+type stubRow struct {
+	// value is the value of the row.
+	value bool
+	// err is the error that is returned instead of the value.
+	err error
+}
+
+var _ pgx.Row = stubRow{}
+
+// Scan is the function that scans the value of the row into the destination.
+func (r stubRow) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+
+	*(dest[0].(*bool)) = r.value
+
+	return nil
+}
+
+// stubQuerier is the type that implements the querier interface, answering the queries from a map keyed by the query and its arguments.
+type stubQuerier struct {
+	// answers is the map of the answers, keyed by the query and its arguments joined with spaces.
+	answers map[string]bool
+}
+
+var _ querier = &stubQuerier{}
+
+// QueryRow is the function that returns the answer to the query, or an error if there is none.
+func (q *stubQuerier) QueryRow(_ context.Context, sql string, args ...any) pgx.Row {
+	value, ok := q.answers[stubKey(sql, args...)]
+	if !ok {
+		return stubRow{err: errStubQuery}
+	}
+
+	return stubRow{value: value}
+}
+
+// stubKey is a function that returns the key of the answer of the stub querier for the query and its arguments.
+func stubKey(sql string, args ...any) string {
+	return strings.TrimSpace(sql + " " + fmt.Sprint(args...))
+}
+
+// TestCheckSpiceDBCapabilities is a test that tests the checkSpiceDBCapabilities function.
+//
+// nolint:funlen
+func TestCheckSpiceDBCapabilities(t *testing.T) {
+	const (
+		// database is the name of the database used in the tests.
+		database = "spicedb"
+
+		// extension is the name of the extension used in the tests.
+		extension = "pg_trgm"
+	)
+
+	var (
+		databaseExists      = stubKey("SELECT EXISTS (SELECT 1 FROM pg_database WHERE datname = $1)", database)
+		hasConnect          = stubKey("SELECT has_database_privilege($1, $2)", database, "CONNECT")
+		hasCreate           = stubKey("SELECT has_database_privilege($1, $2)", database, "CREATE")
+		canCreateDatabase   = stubKey("SELECT rolcreatedb OR rolsuper FROM pg_roles WHERE rolname = current_user")
+		extensionsAvailable = stubKey("SELECT EXISTS (SELECT 1 FROM pg_available_extensions WHERE name = $1)", extension)
+	)
+
+	testCases := []struct {
+		name        string
+		answers     map[string]bool
+		extensions  []string
+		wantErr     error
+		wantMissing []string
+	}{
+		{
+			name:    "Database exists with privileges",
+			answers: map[string]bool{databaseExists: true, hasConnect: true, hasCreate: true},
+		},
+		{
+			name:        "Database exists without privileges",
+			answers:     map[string]bool{databaseExists: true, hasConnect: false, hasCreate: false},
+			wantErr:     ErrMissingSpiceDBCapabilities,
+			wantMissing: []string{"no CONNECT privilege on database spicedb", "no CREATE privilege on database spicedb"},
+		},
+		{
+			name:    "Database missing and user can create it",
+			answers: map[string]bool{databaseExists: false, canCreateDatabase: true},
+		},
+		{
+			name:        "Database missing and user cannot create it",
+			answers:     map[string]bool{databaseExists: false, canCreateDatabase: false},
+			wantErr:     ErrMissingSpiceDBCapabilities,
+			wantMissing: []string{"database spicedb does not exist and the user cannot create databases"},
+		},
+		{
+			name:       "Extension available",
+			answers:    map[string]bool{databaseExists: false, canCreateDatabase: true, extensionsAvailable: true},
+			extensions: []string{extension},
+		},
+		{
+			name:        "Extension unavailable and database missing",
+			answers:     map[string]bool{databaseExists: false, canCreateDatabase: false, extensionsAvailable: false},
+			extensions:  []string{extension},
+			wantErr:     ErrMissingSpiceDBCapabilities,
+			wantMissing: []string{"the user cannot create databases", "extension pg_trgm is not available for installation"},
+		},
+		{
+			name:    "Introspection restricted",
+			answers: map[string]bool{databaseExists: false},
+			wantErr: errFailedToIntrospectPostgreSQL,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkSpiceDBCapabilities(context.Background(), &stubQuerier{answers: tc.answers}, database, tc.extensions)
+
+			if tc.wantErr == nil {
+				require.NoError(t, err)
+
+				return
+			}
+
+			require.ErrorIs(t, err, tc.wantErr)
+
+			for _, missing := range tc.wantMissing {
+				assert.ErrorContains(t, err, missing)
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+// Package providerconfigchecker is the package that contains the check functions for the Crossplane ProviderConfig.
+package providerconfigchecker
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/cloud"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/cloud/awscloudutil"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/envconfig"
+	pkgerrors "github.com/AlphaSense-Engineering/privatecloud-cli/pkg/errors"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler"
+	"go.uber.org/multierr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// LogMsgProviderConfigCheckedSuccessfully is the message that is logged when the Crossplane ProviderConfig is checked successfully.
+const LogMsgProviderConfigCheckedSuccessfully = "checked Crossplane ProviderConfig successfully"
+
+var (
+	// ErrFailedToCheckProviderConfig is the error that occurs when the Crossplane ProviderConfig is not checked.
+	ErrFailedToCheckProviderConfig = errors.New("failed to check Crossplane ProviderConfig")
+
+	// errFailedToGetProviderConfig is the error that occurs when the Crossplane ProviderConfig cannot be retrieved.
+	errFailedToGetProviderConfig = errors.New("failed to get Crossplane ProviderConfig")
+
+	// errProviderConfigMismatch is the error that occurs when the Crossplane ProviderConfig does not match the environment configuration.
+	errProviderConfigMismatch = errors.New("crossplane ProviderConfig does not match environment configuration")
+)
+
+// ProviderConfigName is the name of the Crossplane ProviderConfig that is checked.
+const ProviderConfigName = "default"
+
+// providerConfigResource is the name of the Crossplane ProviderConfig resource.
+const providerConfigResource = "providerconfigs"
+
+// providerConfigVersion is the version of the Crossplane ProviderConfig resource.
+const providerConfigVersion = "v1beta1"
+
+// GVR is a function that returns the group version resource of the Crossplane ProviderConfig for the cloud.
+func GVR(vcloud cloud.Cloud) schema.GroupVersionResource {
+	return schema.GroupVersionResource{
+		Group:    string(vcloud) + ".upbound.io",
+		Version:  providerConfigVersion,
+		Resource: providerConfigResource,
+	}
+}
+
+// expectedField is the type that represents a field of the Crossplane ProviderConfig and its expected value.
+type expectedField struct {
+	// path is the path of the field in the ProviderConfig.
+	path []string
+	// value is the expected value of the field.
+	value string
+}
+
+// ProviderConfigChecker is the type that contains the check functions for the Crossplane ProviderConfig.
+type ProviderConfigChecker struct {
+	// vcloud is the cloud provider.
+	vcloud cloud.Cloud
+	// envConfig is the environment configuration.
+	envConfig *envconfig.EnvConfig
+	// dynamicClient is the Kubernetes dynamic client.
+	dynamicClient dynamic.Interface
+}
+
+var _ handler.Handler = &ProviderConfigChecker{}
+
+// expectedFields is a function that returns the fields of the Crossplane ProviderConfig and the values they are expected to have, as implied by the
+// environment configuration.
+func (c *ProviderConfigChecker) expectedFields() ([]expectedField, error) {
+	const (
+		// sourceWebIdentity is the credentials source for the AWS provider, assuming the Crossplane role with a web identity token.
+		sourceWebIdentity = "WebIdentity"
+
+		// sourceOIDCTokenFile is the credentials source for the Azure provider, using the workload identity.
+		sourceOIDCTokenFile = "OIDCTokenFile"
+
+		// sourceInjectedIdentity is the credentials source for the GCP provider, using the workload identity of the provider service account.
+		sourceInjectedIdentity = "InjectedIdentity"
+	)
+
+	pathSource := []string{"spec", "credentials", "source"}
+
+	switch c.vcloud {
+	case cloud.AWS:
+		awsSpec, err := c.envConfig.AWS()
+		if err != nil {
+			return nil, err
+		}
+
+		return []expectedField{
+			{path: pathSource, value: sourceWebIdentity},
+			{path: []string{"spec", "credentials", "webIdentity", "roleARN"}, value: awscloudutil.ARN(
+				awsSpec.AccountID,
+				c.envConfig.Spec.ClusterName,
+				awscloudutil.ARNTypeRole,
+				awscloudutil.CrossplaneRoleName(c.envConfig.Spec.ClusterName),
+				nil,
+			)},
+		}, nil
+	case cloud.Azure:
+		azureSpec, err := c.envConfig.Azure()
+		if err != nil {
+			return nil, err
+		}
+
+		return []expectedField{
+			{path: pathSource, value: sourceOIDCTokenFile},
+			{path: []string{"spec", "clientID"}, value: azureSpec.ClientID},
+			{path: []string{"spec", "tenantID"}, value: azureSpec.TenantID},
+			{path: []string{"spec", "subscriptionID"}, value: azureSpec.SubscriptionID},
+		}, nil
+	case cloud.GCP:
+		gcpSpec, err := c.envConfig.GCP()
+		if err != nil {
+			return nil, err
+		}
+
+		return []expectedField{
+			{path: pathSource, value: sourceInjectedIdentity},
+			{path: []string{"spec", "projectID"}, value: gcpSpec.ProjectID},
+		}, nil
+	}
+
+	return nil, pkgerrors.NewUnsupportedCloud(c.vcloud)
+}
+
+// Handle is the function that handles the Crossplane ProviderConfig check.
+//
+// The arguments are not used.
+// It returns nothing on success, or an error on failure.
+func (c *ProviderConfigChecker) Handle(ctx context.Context, _ ...any) ([]any, error) {
+	fields, err := c.expectedFields()
+	if err != nil {
+		return nil, err
+	}
+
+	providerConfig, err := c.dynamicClient.Resource(GVR(c.vcloud)).Get(ctx, ProviderConfigName, metav1.GetOptions{})
+	if err != nil {
+		return nil, multierr.Combine(errFailedToGetProviderConfig, err)
+	}
+
+	var mismatches error
+
+	for _, field := range fields {
+		got, _, err := unstructured.NestedString(providerConfig.Object, field.path...)
+		if err != nil {
+			return nil, multierr.Combine(errFailedToGetProviderConfig, err)
+		}
+
+		if got != field.value {
+			mismatches = multierr.Append(mismatches, pkgerrors.NewKeyExpectedGot(strings.Join(field.path, "."), field.value, got))
+		}
+	}
+
+	if mismatches != nil {
+		return nil, multierr.Combine(errProviderConfigMismatch, mismatches)
+	}
+
+	return nil, nil
+}
+
+// Contract is the function that returns the contract of the results of the Crossplane ProviderConfig check.
+//
+// It returns nothing.
+func (c *ProviderConfigChecker) Contract() handler.Contract {
+	return handler.Contract{}
+}
+
+// New is the function that creates a new ProviderConfigChecker.
+func New(checkCtx handler.CheckContext) *ProviderConfigChecker {
+	return &ProviderConfigChecker{
+		vcloud:        checkCtx.VCloud,
+		envConfig:     checkCtx.EnvConfig,
+		dynamicClient: checkCtx.DynamicClient,
+	}
+}
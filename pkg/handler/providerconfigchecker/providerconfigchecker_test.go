@@ -0,0 +1,188 @@
+// Package providerconfigchecker is the package that contains the check functions for the Crossplane ProviderConfig.
+package providerconfigchecker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/cloud"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/envconfig"
+	pkgerrors "github.com/AlphaSense-Engineering/privatecloud-cli/pkg/errors"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/handlertest"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+const (
+	// testClusterName is the cluster name used in the tests.
+	testClusterName = "cluster"
+
+	// testAWSRoleARN is the role ARN that is implied by the AWS environment configuration used in the tests.
+	testAWSRoleARN = "arn:aws:iam::123456789012:role/web-identity/cluster/crossplane-provider-cluster"
+)
+
+// testEnvConfig is a function that returns the environment configuration for the cloud used in the tests.
+func testEnvConfig(vcloud cloud.Cloud) *envconfig.EnvConfig {
+	return &envconfig.EnvConfig{
+		Spec: envconfig.Spec{
+			ClusterName: testClusterName,
+			CloudSpec: envconfig.CloudSpec{
+				Provider: string(vcloud),
+				AWS:      &envconfig.AWSSpec{AccountID: "123456789012"},
+				Azure: &envconfig.AzureSpec{
+					ClientID:       "client",
+					TenantID:       "tenant",
+					SubscriptionID: "subscription",
+				},
+				GCP: &envconfig.GCPSpec{ProjectID: "project"},
+			},
+		},
+	}
+}
+
+// testProviderConfig is a function that returns a fake unstructured ProviderConfig for the cloud with the given spec.
+func testProviderConfig(vcloud cloud.Cloud, name string, spec map[string]any) *unstructured.Unstructured {
+	gvr := GVR(vcloud)
+
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": gvr.GroupVersion().String(),
+		"kind":       "ProviderConfig",
+		"metadata":   map[string]any{"name": name},
+		"spec":       spec,
+	}}
+}
+
+// TestProviderConfigChecker_Handle is a test that tests the Handle function of the ProviderConfigChecker.
+//
+// nolint:funlen
+func TestProviderConfigChecker_Handle(t *testing.T) {
+	testCases := []struct {
+		name           string
+		vcloud         cloud.Cloud
+		providerConfig *unstructured.Unstructured
+		wantErr        error
+		wantMismatch   error
+	}{
+		{
+			name:   "AWS valid",
+			vcloud: cloud.AWS,
+			providerConfig: testProviderConfig(cloud.AWS, ProviderConfigName, map[string]any{
+				"credentials": map[string]any{"source": "WebIdentity", "webIdentity": map[string]any{"roleARN": testAWSRoleARN}},
+			}),
+		},
+		{
+			name:   "AWS wrong role ARN",
+			vcloud: cloud.AWS,
+			providerConfig: testProviderConfig(cloud.AWS, ProviderConfigName, map[string]any{
+				"credentials": map[string]any{"source": "WebIdentity", "webIdentity": map[string]any{"roleARN": "arn:aws:iam::123456789012:role/other"}},
+			}),
+			wantErr:      errProviderConfigMismatch,
+			wantMismatch: pkgerrors.NewKeyExpectedGot("spec.credentials.webIdentity.roleARN", testAWSRoleARN, "arn:aws:iam::123456789012:role/other"),
+		},
+		{
+			name:   "AWS wrong credentials source",
+			vcloud: cloud.AWS,
+			providerConfig: testProviderConfig(cloud.AWS, ProviderConfigName, map[string]any{
+				"credentials": map[string]any{"source": "Secret", "webIdentity": map[string]any{"roleARN": testAWSRoleARN}},
+			}),
+			wantErr:      errProviderConfigMismatch,
+			wantMismatch: pkgerrors.NewKeyExpectedGot("spec.credentials.source", "WebIdentity", "Secret"),
+		},
+		{
+			name:   "Azure valid",
+			vcloud: cloud.Azure,
+			providerConfig: testProviderConfig(cloud.Azure, ProviderConfigName, map[string]any{
+				"credentials":    map[string]any{"source": "OIDCTokenFile"},
+				"clientID":       "client",
+				"tenantID":       "tenant",
+				"subscriptionID": "subscription",
+			}),
+		},
+		{
+			name:   "Azure missing client ID",
+			vcloud: cloud.Azure,
+			providerConfig: testProviderConfig(cloud.Azure, ProviderConfigName, map[string]any{
+				"credentials":    map[string]any{"source": "OIDCTokenFile"},
+				"tenantID":       "tenant",
+				"subscriptionID": "subscription",
+			}),
+			wantErr:      errProviderConfigMismatch,
+			wantMismatch: pkgerrors.NewKeyExpectedGot("spec.clientID", "client", ""),
+		},
+		{
+			name:   "GCP valid",
+			vcloud: cloud.GCP,
+			providerConfig: testProviderConfig(cloud.GCP, ProviderConfigName, map[string]any{
+				"credentials": map[string]any{"source": "InjectedIdentity"},
+				"projectID":   "project",
+			}),
+		},
+		{
+			name:   "GCP wrong project ID",
+			vcloud: cloud.GCP,
+			providerConfig: testProviderConfig(cloud.GCP, ProviderConfigName, map[string]any{
+				"credentials": map[string]any{"source": "InjectedIdentity"},
+				"projectID":   "other",
+			}),
+			wantErr:      errProviderConfigMismatch,
+			wantMismatch: pkgerrors.NewKeyExpectedGot("spec.projectID", "project", "other"),
+		},
+		{
+			name:   "ProviderConfig not found",
+			vcloud: cloud.GCP,
+			providerConfig: testProviderConfig(cloud.GCP, "other", map[string]any{
+				"credentials": map[string]any{"source": "InjectedIdentity"},
+				"projectID":   "project",
+			}),
+			wantErr: errFailedToGetProviderConfig,
+		},
+		{
+			name:   "Field of wrong type",
+			vcloud: cloud.GCP,
+			providerConfig: testProviderConfig(cloud.GCP, ProviderConfigName, map[string]any{
+				"credentials": "InjectedIdentity",
+				"projectID":   "project",
+			}),
+			wantErr: errFailedToGetProviderConfig,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), tc.providerConfig)
+
+			c := New(handler.CheckContext{VCloud: tc.vcloud, EnvConfig: testEnvConfig(tc.vcloud), DynamicClient: dynamicClient})
+
+			results, err := c.Handle(context.Background())
+			handlertest.AssertContract(t, c, results, err)
+
+			if tc.wantErr == nil {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			assert.ErrorIs(t, err, tc.wantErr)
+
+			if tc.wantMismatch != nil {
+				assert.ErrorContains(t, err, tc.wantMismatch.Error())
+			}
+		})
+	}
+}
+
+// TestProviderConfigChecker_Handle_unsupportedCloud is a test that tests that the Handle function fails for an unsupported cloud.
+func TestProviderConfigChecker_Handle_unsupportedCloud(t *testing.T) {
+	c := New(handler.CheckContext{
+		VCloud:        cloud.Cloud("unsupported"),
+		EnvConfig:     testEnvConfig(cloud.Cloud("unsupported")),
+		DynamicClient: dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()),
+	})
+
+	_, err := c.Handle(context.Background())
+
+	assert.ErrorContains(t, err, pkgerrors.NewUnsupportedCloud(cloud.Cloud("unsupported")).Error())
+}
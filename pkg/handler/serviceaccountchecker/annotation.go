@@ -0,0 +1,74 @@
+// Package serviceaccountchecker is the package that contains the check functions for the Crossplane provider service accounts.
+package serviceaccountchecker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ErrUnexpectedAnnotation is the error that is returned when the service account does not have the expected value of the annotation.
+var ErrUnexpectedAnnotation = errors.New("unexpected annotation of the service account")
+
+// AnnotationChecker is the type that contains the check functions for the annotation of a service account in the namespace of the Crossplane providers.
+type AnnotationChecker struct {
+	// clientset is the Kubernetes client.
+	clientset kubernetes.Interface
+	// namespace is the namespace of the service account.
+	namespace string
+	// name is the name of the service account.
+	name string
+	// key is the key of the annotation.
+	key string
+	// value is the expected value of the annotation.
+	value string
+}
+
+var _ handler.Handler = &AnnotationChecker{}
+
+// Handle is the function that handles the annotation of the service account checking.
+//
+// The arguments are not used.
+// It returns nothing on success, including when the service account does not exist, as it is then created along with the annotation, or an error on
+// failure.
+func (c *AnnotationChecker) Handle(ctx context.Context, _ ...any) ([]any, error) {
+	sa, err := c.clientset.CoreV1().ServiceAccounts(c.namespace).Get(ctx, c.name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if value, ok := sa.Annotations[c.key]; !ok || value != c.value {
+		return nil, fmt.Errorf("%w: %s/%s has %s=%q, expected %q", ErrUnexpectedAnnotation, c.namespace, c.name, c.key, value, c.value)
+	}
+
+	return nil, nil
+}
+
+// Contract is the function that returns the contract of the results of the annotation of the service account checking.
+//
+// It returns nothing.
+func (c *AnnotationChecker) Contract() handler.Contract {
+	return handler.Contract{}
+}
+
+// NewAnnotation is a function that returns a new AnnotationChecker.
+//
+// The service account is looked up in the namespace of the options, or else in the crossplane namespace.
+func NewAnnotation(checkCtx handler.CheckContext, name string, key string, value string) *AnnotationChecker {
+	return &AnnotationChecker{
+		clientset: checkCtx.Clientset,
+		namespace: checkCtx.Options.ProviderServiceAccountsNamespace(),
+		name:      name,
+		key:       key,
+		value:     value,
+	}
+}
@@ -0,0 +1,89 @@
+// Package serviceaccountchecker is the package that contains the check functions for the Crossplane provider service accounts.
+package serviceaccountchecker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/handlertest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// TestAnnotationChecker_Handle is a test that tests the Handle function of the AnnotationChecker.
+func TestAnnotationChecker_Handle(t *testing.T) {
+	const (
+		// key is the key of the annotation.
+		key = "iam.gke.io/gcp-service-account"
+
+		// value is the expected value of the annotation.
+		value = "uxp-provider-acme@project.iam.gserviceaccount.com"
+	)
+
+	testCases := []struct {
+		name        string
+		namespace   string
+		annotations map[string]string
+		noSA        bool
+		wantErr     string
+	}{
+		{
+			name:        "Expected annotation",
+			annotations: map[string]string{key: value},
+		},
+		{
+			name:    "Missing annotation",
+			wantErr: `crossplane/gcp-provider-sa has iam.gke.io/gcp-service-account="", expected "` + value + `"`,
+		},
+		{
+			name:        "Other annotation",
+			annotations: map[string]string{key: "other@project.iam.gserviceaccount.com"},
+			wantErr:     `crossplane/gcp-provider-sa has iam.gke.io/gcp-service-account="other@project.iam.gserviceaccount.com"`,
+		},
+		{
+			name: "No service account",
+			noSA: true,
+		},
+		{
+			name:      "Namespace of the options",
+			namespace: "crossplane-system",
+			wantErr:   `crossplane-system/gcp-provider-sa has iam.gke.io/gcp-service-account=""`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			options := handler.CheckOptions{CrossplaneNamespace: tc.namespace}
+
+			var objects []runtime.Object
+
+			if !tc.noSA {
+				objects = append(objects, &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{
+					Name:        constant.ServiceAccountNameGCP,
+					Namespace:   options.ProviderServiceAccountsNamespace(),
+					Annotations: tc.annotations,
+				}})
+			}
+
+			c := NewAnnotation(handler.CheckContext{Clientset: fake.NewClientset(objects...), Options: options}, constant.ServiceAccountNameGCP, key, value)
+
+			results, err := c.Handle(context.Background())
+			handlertest.AssertContract(t, c, results, err)
+
+			if tc.wantErr != constant.EmptyString {
+				assert.ErrorIs(t, err, ErrUnexpectedAnnotation)
+				assert.ErrorContains(t, err, tc.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
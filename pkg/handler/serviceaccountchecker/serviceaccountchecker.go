@@ -0,0 +1,83 @@
+// Package serviceaccountchecker is the package that contains the check functions for the Crossplane provider service accounts.
+package serviceaccountchecker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ErrNoProviderServiceAccounts is the error that is returned when no Crossplane provider service accounts are found.
+var ErrNoProviderServiceAccounts = errors.New("no Crossplane provider service accounts found")
+
+// ServiceAccountChecker is the type that contains the check functions for the Crossplane provider service accounts.
+type ServiceAccountChecker struct {
+	// clientset is the Kubernetes client.
+	clientset kubernetes.Interface
+	// namespace is the namespace of the provider service accounts.
+	namespace string
+	// prefix is the prefix of the names of the provider service accounts.
+	prefix string
+	// excluded is the list of the names of the service accounts matching the prefix that are not provider service accounts, such as the one created by
+	// the application itself.
+	excluded []string
+}
+
+var _ handler.Handler = &ServiceAccountChecker{}
+
+// Handle is the function that handles the Crossplane provider service accounts checking.
+//
+// The arguments are not used.
+// It returns the number of the service accounts in the namespace matching the prefix, apart from the excluded ones, on success, or an error on
+// failure.
+func (c *ServiceAccountChecker) Handle(ctx context.Context, _ ...any) ([]any, error) {
+	serviceAccounts, err := c.clientset.CoreV1().ServiceAccounts(c.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var count int
+
+	for _, sa := range serviceAccounts.Items {
+		if strings.HasPrefix(sa.Name, c.prefix) && !slices.Contains(c.excluded, sa.Name) {
+			count++
+		}
+	}
+
+	if count == 0 {
+		return nil, fmt.Errorf(
+			"%w: no %s* service accounts in the %s namespace out of %d, did the first install step run?",
+			ErrNoProviderServiceAccounts,
+			c.prefix,
+			c.namespace,
+			len(serviceAccounts.Items),
+		)
+	}
+
+	return []any{count}, nil
+}
+
+// Contract is the function that returns the contract of the results of the Crossplane provider service accounts checking.
+//
+// It returns the number of the service accounts.
+func (c *ServiceAccountChecker) Contract() handler.Contract {
+	return handler.Returns[int]()
+}
+
+// New is a function that returns a new ServiceAccountChecker.
+//
+// The service accounts are looked up in the namespace of the options, or else in the crossplane namespace.
+func New(checkCtx handler.CheckContext, prefix string, excluded ...string) *ServiceAccountChecker {
+	return &ServiceAccountChecker{
+		clientset: checkCtx.Clientset,
+		namespace: checkCtx.Options.ProviderServiceAccountsNamespace(),
+		prefix:    prefix,
+		excluded:  excluded,
+	}
+}
@@ -0,0 +1,106 @@
+// Package serviceaccountchecker is the package that contains the check functions for the Crossplane provider service accounts.
+package serviceaccountchecker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/handlertest"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// TestServiceAccountChecker_Handle is a test that tests the Handle function of the ServiceAccountChecker.
+func TestServiceAccountChecker_Handle(t *testing.T) {
+	testCases := []struct {
+		name            string
+		namespace       string
+		serviceAccounts map[string][]string
+		want            int
+		wantErr         string
+	}{
+		{
+			name: "Matching service accounts",
+			serviceAccounts: map[string][]string{
+				constant.NamespaceCrossplane: {"aws-a", "aws-b", constant.ServiceAccountNameAWS, "default"},
+			},
+			want: 2,
+		},
+		{
+			name: "Excluded service account only",
+			serviceAccounts: map[string][]string{
+				constant.NamespaceCrossplane: {constant.ServiceAccountNameAWS, "default"},
+			},
+			wantErr: "no aws-* service accounts in the crossplane namespace out of 2, did the first install step run?",
+		},
+		{
+			name: "No matching service accounts",
+			serviceAccounts: map[string][]string{
+				constant.NamespaceCrossplane: {"default", "crossplane"},
+			},
+			wantErr: "no aws-* service accounts in the crossplane namespace out of 2, did the first install step run?",
+		},
+		{
+			name: "Matching service accounts in another namespace only",
+			serviceAccounts: map[string][]string{
+				constant.NamespaceAlphaSense: {"aws-a"},
+			},
+			wantErr: "no aws-* service accounts in the crossplane namespace out of 0, did the first install step run?",
+		},
+		{
+			name:      "Matching service accounts in the namespace of the options",
+			namespace: "crossplane-system",
+			serviceAccounts: map[string][]string{
+				"crossplane-system":          {"aws-a", constant.ServiceAccountNameAWS},
+				constant.NamespaceCrossplane: {"aws-b", "aws-c"},
+			},
+			want: 1,
+		},
+		{
+			name:      "Matching service accounts in the crossplane namespace only",
+			namespace: "crossplane-system",
+			serviceAccounts: map[string][]string{
+				constant.NamespaceCrossplane: {"aws-a"},
+			},
+			wantErr: "no aws-* service accounts in the crossplane-system namespace out of 0, did the first install step run?",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var objects []runtime.Object
+
+			for ns, names := range tc.serviceAccounts {
+				for _, name := range names {
+					objects = append(objects, &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns}})
+				}
+			}
+
+			checkCtx := handler.CheckContext{Clientset: fake.NewClientset(objects...), Options: handler.CheckOptions{CrossplaneNamespace: tc.namespace}}
+
+			c := New(checkCtx, "aws-", constant.ServiceAccountNameAWS)
+
+			results, err := c.Handle(context.Background())
+			handlertest.AssertContract(t, c, results, err)
+
+			got, err := util.UnwrapValErr[int](results, err)
+
+			if tc.wantErr != constant.EmptyString {
+				assert.ErrorIs(t, err, ErrNoProviderServiceAccounts)
+				assert.ErrorContains(t, err, tc.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
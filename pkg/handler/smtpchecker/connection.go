@@ -0,0 +1,123 @@
+// Package smtpchecker is the package that contains the check functions for the SMTP.
+package smtpchecker
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
+)
+
+var (
+	// errFailedToConnect is the error that is returned when the SMTP server cannot be connected to.
+	errFailedToConnect = errors.New("failed to connect to the SMTP server")
+
+	// errSTARTTLSNotSupported is the error that is returned when the SMTP server does not support STARTTLS on a port other than the implicit TLS one.
+	errSTARTTLSNotSupported = errors.New("SMTP server does not support STARTTLS")
+
+	// errAuthNotSupported is the error that is returned when the SMTP server does not support authentication.
+	errAuthNotSupported = errors.New("SMTP server does not support authentication")
+
+	// errFailedToAuthenticate is the error that is returned when the SMTP server rejects the credentials of the secret.
+	errFailedToAuthenticate = errors.New("failed to authenticate to the SMTP server")
+
+	// errSenderRejected is the error that is returned when the SMTP server rejects the address of the secret as the sender.
+	errSenderRejected = errors.New("SMTP server rejected the sender address")
+)
+
+// implicitTLSPort is the port of the SMTP servers that expect the TLS handshake right away, rather than upgrading the connection with STARTTLS.
+const implicitTLSPort = "465"
+
+// checkSMTP is the function that connects to the SMTP server of the secret data, upgrades the connection with STARTTLS unless the port is the implicit TLS
+// one, authenticates with the credentials and checks that the address is accepted as the sender, without sending anything.
+//
+// It returns an error if any of these steps fails.
+func (c *SMTPChecker) checkSMTP(ctx context.Context, data map[string]string) error {
+	host := data[secretHostKey]
+	addr := net.JoinHostPort(host, data[constant.SecretPortKey])
+
+	if c.connectTimeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, c.connectTimeout)
+		defer cancel()
+	}
+
+	tlsConfig := &tls.Config{} // nolint:gosec
+	if c.tlsConfig != nil {
+		tlsConfig = c.tlsConfig.Clone()
+	}
+
+	tlsConfig.ServerName = host
+
+	implicitTLS := data[constant.SecretPortKey] == implicitTLSPort
+
+	var (
+		conn net.Conn
+		err  error
+	)
+
+	if implicitTLS {
+		conn, err = (&tls.Dialer{Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w %s: %w", errFailedToConnect, addr, err)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			_ = conn.Close()
+
+			return fmt.Errorf("%w %s: %w", errFailedToConnect, addr, err)
+		}
+	}
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		_ = conn.Close()
+
+		return fmt.Errorf("%w %s: %w", errFailedToConnect, addr, err)
+	}
+
+	defer func() { _ = client.Close() }()
+
+	if err := client.Hello("localhost"); err != nil {
+		return fmt.Errorf("%w %s: %w", errFailedToConnect, addr, err)
+	}
+
+	if !implicitTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("%w on %s", errSTARTTLSNotSupported, addr)
+		}
+
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("%w %s: %w", errFailedToConnect, addr, err)
+		}
+	}
+
+	if ok, _ := client.Extension("AUTH"); !ok {
+		return fmt.Errorf("%w on %s", errAuthNotSupported, addr)
+	}
+
+	if err := client.Auth(smtp.PlainAuth(constant.EmptyString, data[constant.SecretUsernameKey], data[constant.SecretPasswordKey], host)); err != nil {
+		return fmt.Errorf("%w %s as %s: %w", errFailedToAuthenticate, addr, data[constant.SecretUsernameKey], err)
+	}
+
+	if err := client.Mail(data[secretAddressKey]); err != nil {
+		return fmt.Errorf("%w %s: %w", errSenderRejected, data[secretAddressKey], err)
+	}
+
+	// Reset the transaction, so that nothing is sent, before quitting.
+	if err := client.Reset(); err != nil {
+		return fmt.Errorf("%w %s: %w", errFailedToConnect, addr, err)
+	}
+
+	return client.Quit()
+}
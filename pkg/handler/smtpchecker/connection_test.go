@@ -0,0 +1,197 @@
+// Package smtpchecker is the package that contains the check functions for the SMTP.
+package smtpchecker
+
+import (
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"math/big"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler"
+	"github.com/stretchr/testify/require"
+)
+
+// testTLSConfigs is a function that generates a self-signed test certificate for 127.0.0.1 and returns the TLS configurations of the server presenting it
+// and of the client trusting it.
+func testTLSConfigs(t *testing.T) (*tls.Config, *tls.Config) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "privatecloud-cli test"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(cert)
+
+	return &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}, MinVersion: tls.VersionTLS12},
+		&tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12}
+}
+
+// serveSMTP is a function that serves a single SMTP session on the listener, which supports STARTTLS if the TLS configuration is not nil, and accepts
+// only the given credentials with the PLAIN authentication.
+func serveSMTP(t *testing.T, listener net.Listener, tlsConfig *tls.Config, username string, password string) {
+	t.Helper()
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+
+		defer func() { _ = conn.Close() }()
+
+		reader := bufio.NewReader(conn)
+		reply := func(lines ...string) { _, _ = conn.Write([]byte(strings.Join(lines, "\r\n") + "\r\n")) }
+
+		reply("220 smtp.example.com ESMTP")
+
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+
+			command, argument, _ := strings.Cut(strings.TrimSpace(line), " ")
+
+			switch strings.ToUpper(command) {
+			case "EHLO":
+				if _, ok := conn.(*tls.Conn); !ok && tlsConfig != nil {
+					reply("250-smtp.example.com", "250-STARTTLS", "250 AUTH PLAIN")
+				} else {
+					reply("250-smtp.example.com", "250 AUTH PLAIN")
+				}
+			case "STARTTLS":
+				reply("220 ready to start TLS")
+
+				conn = tls.Server(conn, tlsConfig)
+				reader = bufio.NewReader(conn)
+			case "AUTH":
+				credentials, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(argument, "PLAIN "))
+				if string(credentials) == "\x00"+username+"\x00"+password {
+					reply("235 authentication succeeded")
+				} else {
+					reply("535 authentication credentials invalid")
+				}
+			case "MAIL", "RSET":
+				reply("250 ok")
+			case "QUIT":
+				reply("221 bye")
+
+				return
+			default:
+				reply("502 command not implemented")
+			}
+		}
+	}()
+}
+
+// TestSMTPChecker_checkSMTP is a test that tests that the checkSMTP function connects to the SMTP server with STARTTLS, verifying it against the root CAs
+// of the options, and authenticates with the credentials of the secret, and describes the step that fails otherwise.
+func TestSMTPChecker_checkSMTP(t *testing.T) {
+	serverTLSConfig, clientTLSConfig := testTLSConfigs(t)
+
+	testCases := []struct {
+		name      string
+		startTLS  bool
+		untrusted bool
+		password  string
+		closed    bool
+		wantErr   error
+	}{
+		{
+			name:     "Valid",
+			startTLS: true,
+			password: "secret",
+		},
+		{
+			name:      "Untrusted certificate",
+			startTLS:  true,
+			untrusted: true,
+			password:  "secret",
+			wantErr:   errFailedToConnect,
+		},
+		{
+			name:     "Wrong password",
+			startTLS: true,
+			password: "wrong",
+			wantErr:  errFailedToAuthenticate,
+		},
+		{
+			name:     "No STARTTLS",
+			password: "secret",
+			wantErr:  errSTARTTLSNotSupported,
+		},
+		{
+			name:     "Unreachable",
+			password: "secret",
+			closed:   true,
+			wantErr:  errFailedToConnect,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+
+			t.Cleanup(func() { _ = listener.Close() })
+
+			if tc.closed {
+				require.NoError(t, listener.Close())
+			} else if tc.startTLS {
+				serveSMTP(t, listener, serverTLSConfig, "alphasense", "secret")
+			} else {
+				serveSMTP(t, listener, nil, "alphasense", "secret")
+			}
+
+			options := handler.CheckOptions{ConnectTimeout: 5 * time.Second, RootCAs: clientTLSConfig.RootCAs}
+
+			if tc.untrusted {
+				options.RootCAs = nil
+			}
+
+			c := New(handler.CheckContext{Options: options})
+
+			err = c.checkSMTP(context.Background(), map[string]string{
+				constant.SecretUsernameKey: "alphasense",
+				constant.SecretPasswordKey: tc.password,
+				secretAddressKey:           "noreply@example.com",
+				secretHostKey:              "127.0.0.1",
+				constant.SecretPortKey:     strconv.Itoa(listener.Addr().(*net.TCPAddr).Port),
+			})
+
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
@@ -3,18 +3,54 @@ package smtpchecker
 
 import (
 	"context"
+	"crypto/tls"
+	"slices"
+	"time"
 
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/k8s/kubeutil"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/util"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
+const (
+	// SecretName is the name of the secret that contains the SMTP credentials.
+	SecretName = "sender-smtp" // nolint:gosec
+
+	// secretAddressKey is the key of the address in the secret.
+	secretAddressKey = "address"
+	// secretHostKey is the key of the host in the secret.
+	secretHostKey = "host"
+)
+
+// constSecretKeys is the list of the keys that the check reads from the secret.
+//
+// Do not modify this variable, it is supposed to be constant.
+var constSecretKeys = []string{constant.SecretUsernameKey, constant.SecretPasswordKey, secretAddressKey, secretHostKey, constant.SecretPortKey}
+
+// SecretKeys is the function that returns the keys that the check reads from the secret, which the keys of the options replace.
+func SecretKeys() []string {
+	return slices.Clone(constSecretKeys)
+}
+
 // SMTPChecker is the type that contains the check functions for the SMTP.
 type SMTPChecker struct {
 	// clientset is the Kubernetes client.
 	clientset kubernetes.Interface
+	// namespace is the namespace of the secret, under the namespace prefix of the options.
+	namespace string
+	// secretName is the name of the secret that contains the SMTP credentials.
+	secretName string
+	// secretKeys is the keys of the secret that replace the default ones, by the default ones.
+	secretKeys map[string]string
+	// checkConnection is whether the SMTP server is connected to, and the credentials of the secret are authenticated with.
+	checkConnection bool
+	// connectTimeout is the maximum duration of the connection to the SMTP server, from dialing it to quitting.
+	connectTimeout time.Duration
+	// tlsConfig is the TLS configuration of the connection to the SMTP server, or nil for the roots of the system; its server name is set to the host.
+	tlsConfig *tls.Config
 }
 
 var _ handler.Handler = &SMTPChecker{}
@@ -24,37 +60,59 @@ var _ handler.Handler = &SMTPChecker{}
 // The arguments are not used.
 // It returns the SMTP secret on success, or an error on failure.
 func (c *SMTPChecker) Handle(ctx context.Context, _ ...any) ([]any, error) {
-	const (
-		// secretName is the name of the secret that contains the SMTP credentials.
-		secretName = "sender-smtp" // nolint:gosec
-
-		// secretAddressKey is the key of the address in the secret.
-		secretAddressKey = "address"
-		// secretHostKey is the key of the host in the secret.
-		secretHostKey = "host"
-	)
-
-	secret, err := c.clientset.CoreV1().Secrets(constant.NamespaceAlphaSense).Get(ctx, secretName, metav1.GetOptions{})
+	secret, err := kubeutil.GetSecret(ctx, c.clientset, c.namespace, c.secretName)
 	if err != nil {
 		return nil, err
 	}
 
-	data := util.ConvertMap(secret.Data, util.Identity[string], util.ByteSliceToString)
+	data := kubeutil.ReplaceSecretKeys(util.ConvertMap(secret.Data, util.Identity[string], util.ByteSliceToString), c.secretKeys)
 
-	if err := util.KeysExistAndNotEmptyOrErr(data, []string{
-		constant.SecretUsernameKey,
-		constant.SecretPasswordKey,
-		secretAddressKey,
-		secretHostKey,
-		constant.SecretPortKey,
-	}); err != nil {
+	if err := util.KeysExistAndNotBlankOrErr(data, constSecretKeys); err != nil {
 		return nil, err
 	}
 
+	if c.checkConnection {
+		if err := c.checkSMTP(ctx, data); err != nil {
+			return nil, err
+		}
+	}
+
 	return []any{secret}, nil
 }
 
+// Contract is the function that returns the contract of the results of the SMTP checking.
+//
+// It returns the SMTP secret.
+func (c *SMTPChecker) Contract() handler.Contract {
+	return handler.Returns[*corev1.Secret]()
+}
+
 // New is a function that returns a new SMTPChecker.
-func New(clientset kubernetes.Interface) *SMTPChecker {
-	return &SMTPChecker{clientset: clientset}
+//
+// It checks the secret named in the options, or else the one named SecretName, by the keys of the options that replace the default ones. The SMTP server
+// is connected to only when the options ask for it, so that the check can still run where the server cannot be reached from, with the database connect
+// timeout of the options. The SMTP server is verified against the root CAs of the options, as the TLS configuration of the databases is only meant for
+// them.
+func New(checkCtx handler.CheckContext) *SMTPChecker {
+	secretName := checkCtx.Options.SMTPSecretName
+
+	if secretName == constant.EmptyString {
+		secretName = SecretName
+	}
+
+	var tlsConfig *tls.Config
+
+	if checkCtx.Options.RootCAs != nil {
+		tlsConfig = &tls.Config{RootCAs: checkCtx.Options.RootCAs, MinVersion: tls.VersionTLS12}
+	}
+
+	return &SMTPChecker{
+		clientset:       checkCtx.Clientset,
+		namespace:       checkCtx.Options.Namespace(constant.NamespaceAlphaSense),
+		secretName:      secretName,
+		secretKeys:      checkCtx.Options.SMTPSecretKeys,
+		checkConnection: checkCtx.Options.CheckSMTPConnection,
+		connectTimeout:  checkCtx.Options.ConnectTimeout,
+		tlsConfig:       tlsConfig,
+	}
 }
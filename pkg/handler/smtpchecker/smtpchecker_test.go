@@ -0,0 +1,158 @@
+// Package smtpchecker is the package that contains the check functions for the SMTP.
+package smtpchecker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
+	pkgerrors "github.com/AlphaSense-Engineering/privatecloud-cli/pkg/errors"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/handlertest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// TestSMTPChecker_Handle is a test that tests that the Handle function rejects the SMTP secrets with the values that are missing, empty or
+// whitespace-only.
+func TestSMTPChecker_Handle(t *testing.T) {
+	valid := func() map[string][]byte {
+		return map[string][]byte{
+			"username": []byte("alphasense"),
+			"password": []byte("secret"),
+			"address":  []byte("noreply@example.com"),
+			"host":     []byte("smtp.example.com"),
+			"port":     []byte("587"),
+		}
+	}
+
+	testCases := []struct {
+		name    string
+		modify  func(map[string][]byte)
+		wantErr string
+	}{
+		{
+			name:   "Valid",
+			modify: func(map[string][]byte) {},
+		},
+		{
+			name:    "Missing",
+			modify:  func(data map[string][]byte) { delete(data, "host") },
+			wantErr: "keys missing: host",
+		},
+		{
+			name:    "Trailing newline only",
+			modify:  func(data map[string][]byte) { data["password"] = []byte("\n") },
+			wantErr: "keys whitespace-only: password",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data := valid()
+
+			tc.modify(data)
+
+			c := New(handler.CheckContext{Clientset: fake.NewClientset(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: constant.NamespaceAlphaSense, Name: SecretName},
+				Data:       data,
+			})})
+
+			got, err := c.Handle(context.Background())
+			handlertest.AssertContract(t, c, got, err)
+
+			if tc.wantErr != constant.EmptyString {
+				require.ErrorContains(t, err, tc.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Len(t, got, 1)
+		})
+	}
+}
+
+// TestSMTPChecker_Handle_namespacePrefix is a test that tests that the Handle function reads the secret from the namespace under the namespace prefix.
+func TestSMTPChecker_Handle_namespacePrefix(t *testing.T) {
+	secret := func(namespace string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: SecretName},
+			Data: map[string][]byte{
+				"username": []byte("alphasense"),
+				"password": []byte("secret"),
+				"address":  []byte("noreply@example.com"),
+				"host":     []byte("smtp.example.com"),
+				"port":     []byte("587"),
+			},
+		}
+	}
+
+	c := New(handler.CheckContext{
+		Clientset: fake.NewClientset(secret("tenant1-" + constant.NamespaceAlphaSense)),
+		Options:   handler.CheckOptions{NamespacePrefix: "tenant1"},
+	})
+
+	_, err := c.Handle(context.Background())
+	require.NoError(t, err)
+
+	c = New(handler.CheckContext{
+		Clientset: fake.NewClientset(secret(constant.NamespaceAlphaSense)),
+		Options:   handler.CheckOptions{NamespacePrefix: "tenant1"},
+	})
+
+	_, err = c.Handle(context.Background())
+	require.ErrorContains(t, err, "secret tenant1-alphasense/"+SecretName+" not found")
+}
+
+// TestSMTPChecker_Handle_secretName is a test that tests that the Handle function reads the secret named in the options instead of the default one.
+func TestSMTPChecker_Handle_secretName(t *testing.T) {
+	c := New(handler.CheckContext{
+		Clientset: fake.NewClientset(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: constant.NamespaceAlphaSense, Name: "smtp-relay"},
+			Data: map[string][]byte{
+				"username": []byte("alphasense"),
+				"password": []byte("secret"),
+				"address":  []byte("noreply@example.com"),
+				"host":     []byte("smtp.example.com"),
+				"port":     []byte("587"),
+			},
+		}),
+		Options: handler.CheckOptions{SMTPSecretName: "smtp-relay"},
+	})
+
+	got, err := c.Handle(context.Background())
+	handlertest.AssertContract(t, c, got, err)
+	require.NoError(t, err)
+
+	_, err = New(handler.CheckContext{Clientset: fake.NewClientset()}).Handle(context.Background())
+	require.ErrorContains(t, err, "secret alphasense/"+SecretName+" not found")
+}
+
+// TestSMTPChecker_Handle_secretKeys is a test that tests that the Handle function reads the secret by the keys of the options that replace the default
+// ones, and reports the default keys whose replacing keys are missing.
+func TestSMTPChecker_Handle_secretKeys(t *testing.T) {
+	clientset := fake.NewClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: constant.NamespaceAlphaSense, Name: SecretName},
+		Data: map[string][]byte{
+			"user":     []byte("alphasense"),
+			"password": []byte("secret"),
+			"address":  []byte("noreply@example.com"),
+			"server":   []byte("smtp.example.com"),
+			"port":     []byte("587"),
+		},
+	})
+
+	c := New(handler.CheckContext{Clientset: clientset, Options: handler.CheckOptions{SMTPSecretKeys: map[string]string{"username": "user", "host": "server"}}})
+
+	got, err := c.Handle(context.Background())
+	handlertest.AssertContract(t, c, got, err)
+	require.NoError(t, err)
+
+	_, err = New(handler.CheckContext{Clientset: clientset, Options: handler.CheckOptions{SMTPSecretKeys: map[string]string{"username": "user"}}}).
+		Handle(context.Background())
+	require.ErrorContains(t, err, pkgerrors.NewKeysMissing([]string{"host"}).Error())
+}
@@ -0,0 +1,69 @@
+// Package ssochecker is the package that contains the check functions for the SSO.
+package ssochecker
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var (
+	// errInvalidSAMLMetadata is the error that is returned when the metadata of the SAML identity provider cannot be parsed as SAML metadata.
+	errInvalidSAMLMetadata = errors.New("invalid SAML metadata")
+
+	// errNoIDPSSODescriptor is the error that is returned when the metadata of the SAML identity provider describes no identity provider.
+	errNoIDPSSODescriptor = errors.New("SAML metadata has no EntityDescriptor with an IDPSSODescriptor")
+)
+
+// samlMetadataNamespace is the XML namespace of the SAML metadata.
+const samlMetadataNamespace = "urn:oasis:names:tc:SAML:2.0:metadata"
+
+const (
+	// elementEntityDescriptor is the name of the element of the SAML metadata that describes an entity.
+	elementEntityDescriptor = "EntityDescriptor"
+
+	// elementEntitiesDescriptor is the name of the element of the SAML metadata that groups several entities.
+	elementEntitiesDescriptor = "EntitiesDescriptor"
+)
+
+// samlMetadata is the type of an element of the SAML metadata, either an EntityDescriptor or an EntitiesDescriptor that groups several of them.
+type samlMetadata struct {
+	// XMLName is the name of the element.
+	XMLName xml.Name
+	// IDPSSODescriptors is the list of the descriptors of the identity provider of the EntityDescriptor element.
+	IDPSSODescriptors []struct{} `xml:"urn:oasis:names:tc:SAML:2.0:metadata IDPSSODescriptor"`
+	// EntityDescriptors is the list of the entities of the EntitiesDescriptor element.
+	EntityDescriptors []samlMetadata `xml:"urn:oasis:names:tc:SAML:2.0:metadata EntityDescriptor"`
+	// EntitiesDescriptors is the list of the groups of entities nested in the EntitiesDescriptor element.
+	EntitiesDescriptors []samlMetadata `xml:"urn:oasis:names:tc:SAML:2.0:metadata EntitiesDescriptor"`
+}
+
+// describesIdP is the function that returns whether the element is, or groups, an EntityDescriptor with an IDPSSODescriptor.
+func (m samlMetadata) describesIdP() bool {
+	if m.XMLName.Local == elementEntityDescriptor {
+		return len(m.IDPSSODescriptors) > 0
+	}
+
+	return slices.ContainsFunc(m.EntityDescriptors, samlMetadata.describesIdP) || slices.ContainsFunc(m.EntitiesDescriptors, samlMetadata.describesIdP)
+}
+
+// validateSAMLMetadata is the function that validates that the metadata of the SAML identity provider is SAML metadata that contains an EntityDescriptor
+// with an IDPSSODescriptor, which is what the identity provider is configured from at login.
+func validateSAMLMetadata(metadata string) error {
+	var m samlMetadata
+
+	if err := xml.Unmarshal([]byte(metadata), &m); err != nil {
+		return fmt.Errorf("%w: %w", errInvalidSAMLMetadata, err)
+	}
+
+	if m.XMLName.Space != samlMetadataNamespace || (m.XMLName.Local != elementEntityDescriptor && m.XMLName.Local != elementEntitiesDescriptor) {
+		return fmt.Errorf("%w: unexpected root element %s", errInvalidSAMLMetadata, m.XMLName.Local)
+	}
+
+	if !m.describesIdP() {
+		return errNoIDPSSODescriptor
+	}
+
+	return nil
+}
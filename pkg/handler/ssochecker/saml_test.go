@@ -0,0 +1,73 @@
+// Package ssochecker is the package that contains the check functions for the SSO.
+package ssochecker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSAMLMetadata is the metadata of a SAML identity provider.
+const testSAMLMetadata = `<?xml version="1.0"?>
+<md:EntityDescriptor xmlns:md="urn:oasis:names:tc:SAML:2.0:metadata" entityID="https://idp.example.com/saml">
+  <md:IDPSSODescriptor protocolSupportEnumeration="urn:oasis:names:tc:SAML:2.0:protocol">
+    <md:SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect" Location="https://idp.example.com/sso"/>
+  </md:IDPSSODescriptor>
+</md:EntityDescriptor>`
+
+// TestValidateSAMLMetadata is a test that tests that the validateSAMLMetadata function accepts the metadata that describes an identity provider, on its
+// own or among other entities, and rejects the malformed metadata or the one of other entities.
+func TestValidateSAMLMetadata(t *testing.T) {
+	testCases := []struct {
+		name     string
+		metadata string
+		wantErr  error
+	}{
+		{
+			name:     "EntityDescriptor",
+			metadata: testSAMLMetadata,
+		},
+		{
+			name: "EntitiesDescriptor",
+			metadata: `<EntitiesDescriptor xmlns="urn:oasis:names:tc:SAML:2.0:metadata">
+  <EntityDescriptor entityID="https://sp.example.com"><SPSSODescriptor/></EntityDescriptor>
+  <EntityDescriptor entityID="https://idp.example.com"><IDPSSODescriptor/></EntityDescriptor>
+</EntitiesDescriptor>`,
+		},
+		{
+			name:     "Malformed",
+			metadata: `<md:EntityDescriptor xmlns:md="urn:oasis:names:tc:SAML:2.0:metadata"><md:IDPSSODescriptor>`,
+			wantErr:  errInvalidSAMLMetadata,
+		},
+		{
+			name:     "Other namespace",
+			metadata: `<EntityDescriptor><IDPSSODescriptor/></EntityDescriptor>`,
+			wantErr:  errInvalidSAMLMetadata,
+		},
+		{
+			name:     "Other root element",
+			metadata: `<md:IDPSSODescriptor xmlns:md="urn:oasis:names:tc:SAML:2.0:metadata"/>`,
+			wantErr:  errInvalidSAMLMetadata,
+		},
+		{
+			name:     "Service provider only",
+			metadata: `<EntityDescriptor xmlns="urn:oasis:names:tc:SAML:2.0:metadata" entityID="https://sp.example.com"><SPSSODescriptor/></EntityDescriptor>`,
+			wantErr:  errNoIDPSSODescriptor,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateSAMLMetadata(tc.metadata)
+
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
@@ -3,47 +3,187 @@ package ssochecker
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/k8s/kubeutil"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/util"
+	"go.uber.org/multierr"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
+var (
+	// errInvalidSSOConfig is the error that is returned when an SSO configuration is invalid, along with the secret that contains it.
+	errInvalidSSOConfig = errors.New("invalid SSO configuration")
+
+	// errNoSSOSecrets is the error that is returned when no secret matches the label selector of the SSO configurations.
+	errNoSSOSecrets = errors.New("no SSO secrets match the label selector")
+)
+
+// SecretName is the name of the secret that contains the SSO configuration.
+const SecretName = "sso-config" // nolint:gosec
+
+const (
+	// typeSAML is the type of the SSO configuration of a SAML identity provider.
+	typeSAML = "SAML"
+
+	// typeOIDC is the type of the SSO configuration of an OIDC identity provider.
+	typeOIDC = "OIDC"
+)
+
+const (
+	// keySAMLEntityID is the key of the entity ID of the SAML identity provider in the secret.
+	keySAMLEntityID = "saml-entityid"
+
+	// keySAMLMetadata is the key of the metadata XML of the SAML identity provider in the secret.
+	keySAMLMetadata = "saml-metadata"
+
+	// keyOIDCIssuer is the key of the issuer of the OIDC identity provider in the secret, the presence of which makes the configuration an OIDC one.
+	keyOIDCIssuer = "oidc-issuer"
+
+	// keyOIDCClientID is the key of the client ID of the OIDC identity provider in the secret.
+	keyOIDCClientID = "oidc-client-id"
+
+	// keyOIDCClientSecret is the key of the client secret of the OIDC identity provider in the secret.
+	//
+	// nolint:gosec
+	keyOIDCClientSecret = "oidc-client-secret"
+)
+
 // SSOChecker is the type that contains the check functions for the SSO.
 type SSOChecker struct {
 	// clientset is the Kubernetes client.
 	clientset kubernetes.Interface
+	// namespace is the namespace of the secrets, under the namespace prefix of the options.
+	namespace string
+	// secretNames is the names of the secrets that contain the SSO configurations, used if the secret selector is empty.
+	secretNames []string
+	// secretSelector is the label selector of the secrets that contain the SSO configurations.
+	secretSelector string
+	// samlKeys is the keys that the SAML configurations are required to have besides the entity ID, whose metadata is validated if they are set.
+	samlKeys []string
 }
 
 var _ handler.Handler = &SSOChecker{}
 
+// ssoType is the function that returns the type of the SSO configuration of the data of the secret.
+//
+// The configurations without the OIDC issuer are SAML ones, as the SAML configuration was the only one supported before.
+func ssoType(data map[string]string) string {
+	if _, ok := data[keyOIDCIssuer]; ok {
+		return typeOIDC
+	}
+
+	return typeSAML
+}
+
+// validate is the function that validates the SSO configuration of the data of the secret according to its type.
+//
+// The SAML configurations are required to have the entity ID and the SAML keys; if there are SAML keys, the metadata of the configuration is also
+// validated if it has any.
+func validate(data map[string]string, vtype string, samlKeys []string) error {
+	if vtype == typeOIDC {
+		return util.KeysExistAndNotBlankOrErr(data, []string{keyOIDCIssuer, keyOIDCClientID, keyOIDCClientSecret})
+	}
+
+	if err := util.KeysExistAndNotBlankOrErr(data, append([]string{keySAMLEntityID}, samlKeys...)); err != nil {
+		return err
+	}
+
+	if metadata, ok := data[keySAMLMetadata]; ok && len(samlKeys) > 0 {
+		return validateSAMLMetadata(metadata)
+	}
+
+	return nil
+}
+
+// secrets is the function that returns the secrets that contain the SSO configurations, along with the errors of the secrets that cannot be obtained.
+func (c *SSOChecker) secrets(ctx context.Context) ([]corev1.Secret, error) {
+	if c.secretSelector != constant.EmptyString {
+		list, err := c.clientset.CoreV1().Secrets(c.namespace).List(ctx, metav1.ListOptions{LabelSelector: c.secretSelector})
+		if err != nil {
+			return nil, err
+		}
+
+		if len(list.Items) == 0 {
+			return nil, fmt.Errorf("%w: %s", errNoSSOSecrets, c.secretSelector)
+		}
+
+		return list.Items, nil
+	}
+
+	var (
+		secrets []corev1.Secret
+		errs    error
+	)
+
+	for _, name := range c.secretNames {
+		secret, err := kubeutil.GetSecret(ctx, c.clientset, c.namespace, name)
+		if err != nil {
+			errs = multierr.Append(errs, err)
+
+			continue
+		}
+
+		secrets = append(secrets, *secret)
+	}
+
+	return secrets, errs
+}
+
 // Handle is the function that handles the SSO checking.
 //
+// Each of the SSO configurations is validated according to its type, and the errors of all of them are returned together, each naming the secret that
+// contains the invalid configuration.
+//
 // The arguments are not used.
 // It returns nothing on success, or an error on failure.
 func (c *SSOChecker) Handle(ctx context.Context, _ ...any) ([]any, error) {
-	const (
-		// secretName is the name of the secret that contains the SSO configuration.
-		secretName = "sso-config" // nolint:gosec
-	)
+	secrets, errs := c.secrets(ctx)
 
-	secret, err := c.clientset.CoreV1().Secrets(constant.NamespacePlatform).Get(ctx, secretName, metav1.GetOptions{})
-	if err != nil {
-		return nil, err
-	}
+	for _, secret := range secrets {
+		data := util.ConvertMap(secret.Data, util.Identity[string], util.ByteSliceToString)
 
-	data := util.ConvertMap(secret.Data, util.Identity[string], util.ByteSliceToString)
+		vtype := ssoType(data)
 
-	if err := util.KeysExistAndNotEmptyOrErr(data, []string{"saml-entityid"}); err != nil {
-		return nil, err
+		if err := validate(data, vtype, c.samlKeys); err != nil {
+			errs = multierr.Append(errs, multierr.Combine(fmt.Errorf("%w: %s/%s (%s)", errInvalidSSOConfig, secret.Namespace, secret.Name, vtype), err))
+		}
+	}
+
+	if errs != nil {
+		return nil, errs
 	}
 
 	return nil, nil
 }
 
+// Contract is the function that returns the contract of the results of the SSO checking.
+//
+// It returns nothing.
+func (c *SSOChecker) Contract() handler.Contract {
+	return handler.Contract{}
+}
+
 // New is a function that returns a new SSOChecker.
-func New(clientset kubernetes.Interface) *SSOChecker {
-	return &SSOChecker{clientset: clientset}
+//
+// It checks the secrets of the options selected by the label selector, or else named, or else the single secret named SecretName.
+func New(checkCtx handler.CheckContext) *SSOChecker {
+	secretNames := checkCtx.Options.SSOSecretNames
+
+	if len(secretNames) == 0 {
+		secretNames = []string{SecretName}
+	}
+
+	return &SSOChecker{
+		clientset:      checkCtx.Clientset,
+		namespace:      checkCtx.Options.Namespace(constant.NamespacePlatform),
+		secretNames:    secretNames,
+		secretSelector: checkCtx.Options.SSOSecretSelector,
+		samlKeys:       checkCtx.Options.SSOSAMLKeys,
+	}
 }
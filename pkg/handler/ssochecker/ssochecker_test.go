@@ -0,0 +1,179 @@
+// Package ssochecker is the package that contains the check functions for the SSO.
+package ssochecker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/handlertest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// labelSSO is the label of the secrets of the SSO configurations used in the test.
+const labelSSO = "alpha-sense.com/sso"
+
+// secret is a function that returns a secret of an SSO configuration in the platform namespace, labeled as one.
+func secret(name string, data map[string]string) *corev1.Secret {
+	s := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: constant.NamespacePlatform, Labels: map[string]string{labelSSO: "true"}},
+		Data:       map[string][]byte{},
+	}
+
+	for k, v := range data {
+		s.Data[k] = []byte(v)
+	}
+
+	return s
+}
+
+// TestSSOChecker_Handle is a test that tests that the Handle function validates each of the SSO configurations according to its type and reports all of the
+// invalid ones.
+//
+// nolint:funlen
+func TestSSOChecker_Handle(t *testing.T) {
+	objects := []runtime.Object{
+		secret(SecretName, map[string]string{keySAMLEntityID: "https://idp.example.com/saml"}),
+		secret("sso-okta", map[string]string{keyOIDCIssuer: "https://okta.example.com", keyOIDCClientID: "alphasense", keyOIDCClientSecret: "secret"}),
+		secret("sso-azure", map[string]string{keyOIDCIssuer: "https://login.example.com", keyOIDCClientID: "alphasense"}),
+		secret("sso-adfs", map[string]string{keySAMLEntityID: constant.EmptyString}),
+		secret("sso-echo", map[string]string{keySAMLEntityID: "\n"}),
+	}
+
+	testCases := []struct {
+		name      string
+		options   handler.CheckOptions
+		wantErrs  []string
+		wantValid []string
+	}{
+		{
+			name: "Default",
+		},
+		{
+			name:    "Valid SAML and OIDC",
+			options: handler.CheckOptions{SSOSecretNames: []string{SecretName, "sso-okta"}},
+		},
+		{
+			name:    "Mixed by name",
+			options: handler.CheckOptions{SSOSecretNames: []string{SecretName, "sso-okta", "sso-azure", "sso-adfs"}},
+			wantErrs: []string{
+				"invalid SSO configuration: platform/sso-azure (OIDC); keys missing: oidc-client-secret",
+				"invalid SSO configuration: platform/sso-adfs (SAML); keys empty: saml-entityid",
+			},
+			wantValid: []string{SecretName, "sso-okta"},
+		},
+		{
+			name:     "Whitespace-only",
+			options:  handler.CheckOptions{SSOSecretNames: []string{"sso-echo"}},
+			wantErrs: []string{"invalid SSO configuration: platform/sso-echo (SAML); keys whitespace-only: saml-entityid"},
+		},
+		{
+			name:      "Mixed by label selector",
+			options:   handler.CheckOptions{SSOSecretNames: []string{SecretName}, SSOSecretSelector: labelSSO + "=true"},
+			wantErrs:  []string{"platform/sso-azure (OIDC)", "platform/sso-adfs (SAML)"},
+			wantValid: []string{SecretName, "sso-okta"},
+		},
+		{
+			name:     "Missing",
+			options:  handler.CheckOptions{SSOSecretNames: []string{"sso-okta", "sso-missing"}},
+			wantErrs: []string{"secret platform/sso-missing not found"},
+		},
+		{
+			name:     "No match",
+			options:  handler.CheckOptions{SSOSecretSelector: labelSSO + "=false"},
+			wantErrs: []string{"no SSO secrets match the label selector: " + labelSSO + "=false"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := New(handler.CheckContext{Clientset: fake.NewClientset(objects...), Options: tc.options})
+
+			results, err := c.Handle(context.Background())
+			handlertest.AssertContract(t, c, results, err)
+
+			if tc.wantErrs == nil {
+				require.NoError(t, err)
+
+				return
+			}
+
+			require.Error(t, err)
+
+			for _, want := range tc.wantErrs {
+				assert.ErrorContains(t, err, want)
+			}
+
+			for _, valid := range tc.wantValid {
+				assert.NotContains(t, err.Error(), "platform/"+valid+" ")
+			}
+		})
+	}
+}
+
+// TestSSOChecker_Handle_samlKeys is a test that tests that the Handle function only requires the entity ID of the SAML configurations by default, and
+// otherwise also requires the SAML keys and validates the metadata.
+func TestSSOChecker_Handle_samlKeys(t *testing.T) {
+	// keySAMLCert is the key of the certificate of the SAML identity provider in the secret.
+	const keySAMLCert = "saml-cert"
+
+	testCases := []struct {
+		name     string
+		samlKeys []string
+		data     map[string]string
+		wantErr  string
+	}{
+		{
+			name: "Default",
+			data: map[string]string{keySAMLEntityID: "https://idp.example.com/saml", keySAMLMetadata: "<not XML"},
+		},
+		{
+			name:     "Valid",
+			samlKeys: []string{keySAMLMetadata, keySAMLCert},
+			data:     map[string]string{keySAMLEntityID: "https://idp.example.com/saml", keySAMLMetadata: testSAMLMetadata, keySAMLCert: "certificate"},
+		},
+		{
+			name:     "Missing certificate",
+			samlKeys: []string{keySAMLMetadata, keySAMLCert},
+			data:     map[string]string{keySAMLEntityID: "https://idp.example.com/saml", keySAMLMetadata: testSAMLMetadata},
+			wantErr:  "invalid SSO configuration: platform/sso-config (SAML); keys missing: saml-cert",
+		},
+		{
+			name:     "Malformed metadata",
+			samlKeys: []string{keySAMLCert},
+			data:     map[string]string{keySAMLEntityID: "https://idp.example.com/saml", keySAMLMetadata: "<not XML", keySAMLCert: "certificate"},
+			wantErr:  "invalid SSO configuration: platform/sso-config (SAML); invalid SAML metadata",
+		},
+		{
+			name:     "OIDC",
+			samlKeys: []string{keySAMLMetadata},
+			data:     map[string]string{keyOIDCIssuer: "https://okta.example.com", keyOIDCClientID: "alphasense", keyOIDCClientSecret: "secret"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := New(handler.CheckContext{
+				Clientset: fake.NewClientset(secret(SecretName, tc.data)),
+				Options:   handler.CheckOptions{SSOSAMLKeys: tc.samlKeys},
+			})
+
+			results, err := c.Handle(context.Background())
+			handlertest.AssertContract(t, c, results, err)
+
+			if tc.wantErr == constant.EmptyString {
+				require.NoError(t, err)
+
+				return
+			}
+
+			assert.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
@@ -4,47 +4,116 @@ package storageclasschecker
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
 	"strconv"
+	"strings"
 
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/cloud"
+	pkgerrors "github.com/AlphaSense-Engineering/privatecloud-cli/pkg/errors"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
-// errNoDefaultStorageClass is the error that is returned when no default storage class is found.
-var errNoDefaultStorageClass = errors.New("no default storage class found")
+var (
+	// ErrNoDefaultStorageClass is the error that is returned when no default storage class is found.
+	ErrNoDefaultStorageClass = errors.New("no default storage class found")
+
+	// errUnexpectedProvisioner is the error that is returned when the provisioner of the default storage class is not one of the expected ones.
+	errUnexpectedProvisioner = errors.New("unexpected provisioner of the default storage class")
+)
+
+// DefaultStorageClassAnnotation is the annotation that is used to determine if a storage class is the default.
+const DefaultStorageClassAnnotation = "storageclass.kubernetes.io/is-default-class"
+
+// constExpectedProvisioners is the map of the cloud providers to the provisioners expected of the default storage class, the CSI drivers of their disks.
+//
+// Do not modify this variable, it is supposed to be constant.
+var constExpectedProvisioners = map[cloud.Cloud][]string{
+	cloud.AWS:   {"ebs.csi.aws.com"},
+	cloud.Azure: {"disk.csi.azure.com"},
+	cloud.GCP:   {"pd.csi.storage.gke.io"},
+}
 
 // StorageClassChecker is the type that contains the check functions for the storage class.
 type StorageClassChecker struct {
 	// clientset is the Kubernetes client.
 	clientset kubernetes.Interface
+	// vcloud is the cloud provider.
+	vcloud cloud.Cloud
+	// checkProvisioner is whether the provisioner of the default storage class is checked.
+	checkProvisioner bool
+	// provisioners is the provisioners expected of the default storage class, replacing the ones of the cloud provider if not empty.
+	provisioners []string
 }
 
 var _ handler.Handler = &StorageClassChecker{}
 
+// expectedProvisioners is the function that returns the provisioners expected of the default storage class.
+func (c *StorageClassChecker) expectedProvisioners() ([]string, error) {
+	if len(c.provisioners) > 0 {
+		return c.provisioners, nil
+	}
+
+	provisioners, ok := constExpectedProvisioners[c.vcloud]
+	if !ok {
+		return nil, pkgerrors.NewUnsupportedCloud(c.vcloud)
+	}
+
+	return provisioners, nil
+}
+
 // Handle is the function that handles the storage class checking.
 //
+// If the provisioner is checked, the default storage class must also be provisioned by one of the expected provisioners, e.g. a cluster on Azure must not
+// default to the EBS provisioner of AWS.
+//
 // The arguments are not used.
 // It returns nothing on success, or an error on failure.
 func (c *StorageClassChecker) Handle(ctx context.Context, _ ...any) ([]any, error) {
-	// defaultStorageClassAnnotation is the annotation that is used to determine if a storage class is the default.
-	const defaultStorageClassAnnotation = "storageclass.kubernetes.io/is-default-class"
-
 	storageClasses, err := c.clientset.StorageV1().StorageClasses().List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return nil, err
 	}
 
 	for _, sc := range storageClasses.Items {
-		if sc.Annotations[defaultStorageClassAnnotation] == strconv.FormatBool(true) {
+		if sc.Annotations[DefaultStorageClassAnnotation] != strconv.FormatBool(true) {
+			continue
+		}
+
+		if !c.checkProvisioner {
 			return nil, nil
 		}
+
+		expected, err := c.expectedProvisioners()
+		if err != nil {
+			return nil, err
+		}
+
+		if !slices.Contains(expected, sc.Provisioner) {
+			return nil, fmt.Errorf("%w: %s has %s, expected one of %s", errUnexpectedProvisioner, sc.Name, sc.Provisioner, strings.Join(expected, ", "))
+		}
+
+		return nil, nil
 	}
 
-	return nil, errNoDefaultStorageClass
+	return nil, ErrNoDefaultStorageClass
+}
+
+// Contract is the function that returns the contract of the results of the storage class checking.
+//
+// It returns nothing.
+func (c *StorageClassChecker) Contract() handler.Contract {
+	return handler.Contract{}
 }
 
 // New is a function that returns a new StorageClassChecker.
-func New(clientset kubernetes.Interface) *StorageClassChecker {
-	return &StorageClassChecker{clientset: clientset}
+func New(checkCtx handler.CheckContext) *StorageClassChecker {
+	return &StorageClassChecker{
+		clientset:        checkCtx.Clientset,
+		vcloud:           checkCtx.VCloud,
+		checkProvisioner: checkCtx.Options.CheckStorageClassProvisioner,
+		provisioners:     checkCtx.Options.StorageClassProvisioners,
+	}
 }
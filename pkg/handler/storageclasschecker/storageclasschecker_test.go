@@ -0,0 +1,120 @@
+// Package storageclasschecker is the package that contains the check functions for the storage class.
+package storageclasschecker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/cloud"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/handlertest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// storageClass is a function that returns a storage class with the provisioner, annotated as the default one if isDefault is true.
+func storageClass(name string, provisioner string, isDefault bool) *storagev1.StorageClass {
+	sc := &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: name}, Provisioner: provisioner}
+
+	if isDefault {
+		sc.Annotations = map[string]string{"storageclass.kubernetes.io/is-default-class": "true"}
+	}
+
+	return sc
+}
+
+// TestStorageClassChecker_Handle is a test that tests that the Handle function checks the provisioner of the default storage class against the expected ones
+// of the cloud provider, only when asked to.
+//
+// nolint:funlen
+func TestStorageClassChecker_Handle(t *testing.T) {
+	testCases := []struct {
+		name           string
+		vcloud         cloud.Cloud
+		options        handler.CheckOptions
+		storageClasses []runtime.Object
+		wantErr        string
+	}{
+		{
+			name:           "No default",
+			vcloud:         cloud.AWS,
+			storageClasses: []runtime.Object{storageClass("gp3", "ebs.csi.aws.com", false)},
+			wantErr:        "no default storage class found",
+		},
+		{
+			name:           "Provisioner not checked",
+			vcloud:         cloud.Azure,
+			storageClasses: []runtime.Object{storageClass("gp3", "ebs.csi.aws.com", true)},
+		},
+		{
+			name:           "AWS",
+			vcloud:         cloud.AWS,
+			options:        handler.CheckOptions{CheckStorageClassProvisioner: true},
+			storageClasses: []runtime.Object{storageClass("standard", "kubernetes.io/no-provisioner", false), storageClass("gp3", "ebs.csi.aws.com", true)},
+		},
+		{
+			name:           "Azure",
+			vcloud:         cloud.Azure,
+			options:        handler.CheckOptions{CheckStorageClassProvisioner: true},
+			storageClasses: []runtime.Object{storageClass("managed-csi", "disk.csi.azure.com", true)},
+		},
+		{
+			name:           "GCP",
+			vcloud:         cloud.GCP,
+			options:        handler.CheckOptions{CheckStorageClassProvisioner: true},
+			storageClasses: []runtime.Object{storageClass("standard-rwo", "pd.csi.storage.gke.io", true)},
+		},
+		{
+			name:           "EBS on Azure",
+			vcloud:         cloud.Azure,
+			options:        handler.CheckOptions{CheckStorageClassProvisioner: true},
+			storageClasses: []runtime.Object{storageClass("gp3", "ebs.csi.aws.com", true)},
+			wantErr:        "unexpected provisioner of the default storage class: gp3 has ebs.csi.aws.com, expected one of disk.csi.azure.com",
+		},
+		{
+			name:           "In-tree on GCP",
+			vcloud:         cloud.GCP,
+			options:        handler.CheckOptions{CheckStorageClassProvisioner: true},
+			storageClasses: []runtime.Object{storageClass("standard", "kubernetes.io/gce-pd", true)},
+			wantErr:        "unexpected provisioner of the default storage class: standard has kubernetes.io/gce-pd, expected one of pd.csi.storage.gke.io",
+		},
+		{
+			name:   "Configured",
+			vcloud: cloud.AWS,
+			options: handler.CheckOptions{
+				CheckStorageClassProvisioner: true,
+				StorageClassProvisioners:     []string{"ebs.csi.aws.com", "efs.csi.aws.com"},
+			},
+			storageClasses: []runtime.Object{storageClass("efs", "efs.csi.aws.com", true)},
+		},
+		{
+			name:           "Unsupported cloud",
+			vcloud:         cloud.Cloud("oci"),
+			options:        handler.CheckOptions{CheckStorageClassProvisioner: true},
+			storageClasses: []runtime.Object{storageClass("oci-bv", "blockvolume.csi.oraclecloud.com", true)},
+			wantErr:        "oci",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := New(handler.CheckContext{VCloud: tc.vcloud, Clientset: fake.NewClientset(tc.storageClasses...), Options: tc.options})
+
+			results, err := c.Handle(context.Background())
+			handlertest.AssertContract(t, c, results, err)
+
+			if tc.wantErr != constant.EmptyString {
+				assert.ErrorContains(t, err, tc.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
@@ -4,38 +4,50 @@ package tlschecker
 import (
 	"context"
 	"crypto/tls"
+	"errors"
+	"fmt"
 
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/k8s/kubeutil"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/util"
 	corev1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
+// errUnexpectedSecretType is the error that is returned when the TLS secret is not of the TLS type, which the ingress controllers and the other consumers
+// of the certificate may require.
+var errUnexpectedSecretType = errors.New("unexpected type of the TLS secret")
+
+// SecretName is the name of the secret that contains the TLS credentials.
+const SecretName = "default-tls"
+
 // TLSChecker is the type that contains the check functions for the TLS.
 type TLSChecker struct {
 	// clientset is the Kubernetes client.
 	clientset kubernetes.Interface
+	// namespace is the namespace of the secret, under the namespace prefix of the options.
+	namespace string
 }
 
 var _ handler.Handler = &TLSChecker{}
 
 // Handle is the function that handles the TLS checking.
 //
+// The secret must be of the kubernetes.io/tls type, and contain the certificate and the private key that form a key pair.
+//
 // The arguments are not used.
 // It returns the TLS secret on success, or an error on failure.
 func (c *TLSChecker) Handle(ctx context.Context, _ ...any) ([]any, error) {
-	const (
-		// secretName is the name of the secret that contains the TLS credentials.
-		secretName = "default-tls"
-	)
-
-	secret, err := c.clientset.CoreV1().Secrets(constant.NamespaceAlphaSense).Get(ctx, secretName, metav1.GetOptions{})
+	secret, err := kubeutil.GetSecret(ctx, c.clientset, c.namespace, SecretName)
 	if err != nil {
 		return nil, err
 	}
 
+	if secret.Type != corev1.SecretTypeTLS {
+		return nil, fmt.Errorf("%w: %s/%s is %s, expected %s", errUnexpectedSecretType, secret.Namespace, secret.Name, secret.Type, corev1.SecretTypeTLS)
+	}
+
 	data := secret.Data
 
 	if err := util.KeysExistAndNotEmptyOrErr(data, []string{corev1.TLSCertKey, corev1.TLSPrivateKeyKey}); err != nil {
@@ -49,7 +61,17 @@ func (c *TLSChecker) Handle(ctx context.Context, _ ...any) ([]any, error) {
 	return []any{secret}, nil
 }
 
+// Contract is the function that returns the contract of the results of the TLS checking.
+//
+// It returns the TLS secret.
+func (c *TLSChecker) Contract() handler.Contract {
+	return handler.Returns[*corev1.Secret]()
+}
+
 // New is a function that returns a new TLSChecker.
-func New(clientset kubernetes.Interface) *TLSChecker {
-	return &TLSChecker{clientset: clientset}
+func New(checkCtx handler.CheckContext) *TLSChecker {
+	return &TLSChecker{
+		clientset: checkCtx.Clientset,
+		namespace: checkCtx.Options.Namespace(constant.NamespaceAlphaSense),
+	}
 }
@@ -0,0 +1,107 @@
+// Package tlschecker is the package that contains the check functions for the TLS.
+package tlschecker
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/handler/handlertest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// testKeyPairPEM is a function that generates a self-signed test certificate and its private key and returns them PEM encoded.
+func testKeyPairPEM(t *testing.T) ([]byte, []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "privatecloud-cli test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// TestTLSChecker_Handle is a test that tests that the Handle function accepts only the TLS secrets of the TLS type with a valid key pair.
+func TestTLSChecker_Handle(t *testing.T) {
+	certPEM, keyPEM := testKeyPairPEM(t)
+
+	testCases := []struct {
+		name       string
+		secretType corev1.SecretType
+		data       map[string][]byte
+		wantErr    error
+		wantAnyErr bool
+	}{
+		{
+			name:       "TLS",
+			secretType: corev1.SecretTypeTLS,
+			data:       map[string][]byte{corev1.TLSCertKey: certPEM, corev1.TLSPrivateKeyKey: keyPEM},
+		},
+		{
+			name:       "Opaque",
+			secretType: corev1.SecretTypeOpaque,
+			data:       map[string][]byte{corev1.TLSCertKey: certPEM, corev1.TLSPrivateKeyKey: keyPEM},
+			wantErr:    errUnexpectedSecretType,
+		},
+		{
+			name:       "Missing key",
+			secretType: corev1.SecretTypeTLS,
+			data:       map[string][]byte{corev1.TLSCertKey: certPEM},
+			wantAnyErr: true,
+		},
+		{
+			name:       "Mismatched key pair",
+			secretType: corev1.SecretTypeTLS,
+			data:       map[string][]byte{corev1.TLSCertKey: certPEM, corev1.TLSPrivateKeyKey: certPEM},
+			wantAnyErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := New(handler.CheckContext{Clientset: fake.NewClientset(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: constant.NamespaceAlphaSense, Name: SecretName},
+				Type:       tc.secretType,
+				Data:       tc.data,
+			})})
+
+			got, err := c.Handle(context.Background())
+			handlertest.AssertContract(t, c, got, err)
+
+			switch {
+			case tc.wantErr != nil:
+				require.ErrorIs(t, err, tc.wantErr)
+			case tc.wantAnyErr:
+				require.Error(t, err)
+			default:
+				require.NoError(t, err)
+				assert.Len(t, got, 1)
+			}
+		})
+	}
+}
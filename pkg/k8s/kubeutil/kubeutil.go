@@ -4,16 +4,20 @@ package kubeutil
 import (
 	"bufio"
 	"context"
+	"encoding/base64"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/clock"
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
 	"github.com/charmbracelet/log"
 	"go.uber.org/multierr"
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -29,15 +33,61 @@ var (
 
 	// errFailedToReadPodLogStream is the error that is returned when the pod log stream cannot be read.
 	errFailedToReadPodLogStream = errors.New("failed to read Pod log stream")
+
+	// errInvalidKubeConfigData is the error that is returned when the base64 encoded Kubernetes configuration cannot be decoded or loaded.
+	errInvalidKubeConfigData = errors.New("invalid Kubernetes configuration data")
+
+	// errNoKubeConfigContexts is the error that is returned when a context is asked for but the in-cluster configuration is used, which has no contexts.
+	errNoKubeConfigContexts = errors.New("in-cluster Kubernetes configuration has no contexts")
 )
 
-// Config returns a Kubernetes configuration based on the provided path,
-// or the path in the KUBECONFIG environment variable, or the default path.
-func Config(path string) (config *rest.Config, pathToUse string, err error) {
+// PathInCluster is the path that Config returns when we are running in a cluster. This is not a real path,
+// it's just a placeholder to indicate that we are running in a cluster.
+const PathInCluster = "cluster"
+
+// PathData is the path that Config returns when the configuration is loaded from the base64 encoded data. This is not a real path either, there is no file.
+const PathData = "data"
+
+// Config returns a Kubernetes configuration based on, in order of precedence, the provided base64 encoded data, the provided path, the base64 encoded data in
+// the KUBECONFIG_DATA environment variable, the path in the KUBECONFIG environment variable, or the default path.
+//
+// The data is decoded in memory, so that the ephemeral runners that get the configuration as a secret do not have to write it to disk.
+//
+// The in-cluster configuration is used when the file at the resolved path does not exist.
+func Config(path string, data string) (*rest.Config, string, error) {
+	return loadConfig(path, data, constant.EmptyString, false)
+}
+
+// PodConfig returns a Kubernetes configuration as Config does, except that the in-cluster configuration takes precedence over the default path when running
+// in a cluster, so that a stray Kubernetes configuration file at the default path is not picked up by the pod.
+//
+// Running in a cluster is detected by the KUBERNETES_SERVICE_HOST environment variable. Only the pod command prefers the in-cluster configuration, as the
+// other commands may run in a pod, e.g. of a CI runner, against another cluster.
+func PodConfig(path string, data string) (*rest.Config, string, error) {
+	return loadConfig(path, data, constant.EmptyString, true)
+}
+
+// ContextConfig returns the Kubernetes configuration of the named context, rather than the current one, of the Kubernetes configuration that Config
+// resolves, along with its path.
+//
+// It returns an error if the in-cluster configuration would be used, as it has no contexts, or if the Kubernetes configuration has no context of the name.
+func ContextConfig(path string, data string, kubeContext string) (*rest.Config, string, error) {
+	return loadConfig(path, data, kubeContext, false)
+}
+
+// loadConfig returns the Kubernetes configuration of the named context, or of the current one if the name is empty, as resolved by Config, or by PodConfig
+// if the in-cluster configuration is preferred, along with its path.
+func loadConfig(path string, data string, kubeContext string, preferInCluster bool) (config *rest.Config, pathToUse string, err error) {
 	const (
 		// kubeConfigEnvVar is the environment variable that contains the path to the Kubernetes configuration file.
 		kubeConfigEnvVar = "KUBECONFIG"
 
+		// kubeConfigDataEnvVar is the environment variable that contains the base64 encoded Kubernetes configuration.
+		kubeConfigDataEnvVar = "KUBECONFIG_DATA"
+
+		// serviceHostEnvVar is the environment variable that Kubernetes sets in every container, which signals that we are running in a cluster.
+		serviceHostEnvVar = "KUBERNETES_SERVICE_HOST"
+
 		// pathHomeKubeDir is the Kubernetes directory name within the home directory.
 		pathHomeKubeDir = ".kube"
 
@@ -45,10 +95,18 @@ func Config(path string) (config *rest.Config, pathToUse string, err error) {
 		pathKubeDirConfig = "config"
 	)
 
+	if data != constant.EmptyString {
+		return configFromData(data, kubeContext)
+	}
+
 	if path != constant.EmptyString {
 		pathToUse = path
+	} else if envData := os.Getenv(kubeConfigDataEnvVar); envData != constant.EmptyString {
+		return configFromData(envData, kubeContext)
 	} else if envPath := os.Getenv(kubeConfigEnvVar); envPath != constant.EmptyString {
 		pathToUse = envPath
+	} else if preferInCluster && os.Getenv(serviceHostEnvVar) != constant.EmptyString {
+		return inClusterConfig(kubeContext)
 	} else {
 		var pathHome string
 
@@ -61,32 +119,82 @@ func Config(path string) (config *rest.Config, pathToUse string, err error) {
 	}
 
 	if _, err = os.Stat(pathToUse); os.IsNotExist(err) {
-		// pathToUseCluster is the path that we return when we are running in a cluster. This is not a real path,
-		// it's just a placeholder to indicate that we are running in a cluster.
-		const pathToUseCluster = "cluster"
+		return inClusterConfig(kubeContext)
+	}
 
-		pathToUse = pathToUseCluster
+	config, err = clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		&clientcmd.ClientConfigLoadingRules{ExplicitPath: pathToUse},
+		&clientcmd.ConfigOverrides{CurrentContext: kubeContext},
+	).ClientConfig()
+	if err != nil {
+		return nil, pathToUse, err
+	}
 
-		config, err = rest.InClusterConfig()
-		if err != nil {
-			return nil, pathToUse, err
-		}
+	return config, pathToUse, nil
+}
 
-		return config, pathToUse, nil
+// configFromData returns the Kubernetes configuration of the named context, or of the current one if the name is empty, decoded from the base64 encoded
+// data along with the PathData path.
+func configFromData(data string, kubeContext string) (*rest.Config, string, error) {
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(data))
+	if err != nil {
+		return nil, PathData, multierr.Combine(errInvalidKubeConfigData, err)
 	}
 
-	config, err = clientcmd.BuildConfigFromFlags(constant.EmptyString, pathToUse)
+	rawConfig, err := clientcmd.Load(decoded)
 	if err != nil {
-		return nil, pathToUse, err
+		return nil, PathData, multierr.Combine(errInvalidKubeConfigData, err)
 	}
 
-	return config, pathToUse, nil
+	config, err := clientcmd.NewNonInteractiveClientConfig(*rawConfig, kubeContext, &clientcmd.ConfigOverrides{}, nil).ClientConfig()
+	if err != nil {
+		return nil, PathData, multierr.Combine(errInvalidKubeConfigData, err)
+	}
+
+	return config, PathData, nil
+}
+
+// inClusterConfig returns the in-cluster Kubernetes configuration along with the PathInCluster path, or an error if a context is asked for, as it has no
+// contexts.
+func inClusterConfig(kubeContext string) (*rest.Config, string, error) {
+	if kubeContext != constant.EmptyString {
+		return nil, PathInCluster, fmt.Errorf("%w, asked for %s", errNoKubeConfigContexts, kubeContext)
+	}
+
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, PathInCluster, err
+	}
+
+	return config, PathInCluster, nil
+}
+
+// IsRetryable returns whether the error returned by the Kubernetes API server is transient, so that the request can be retried.
+func IsRetryable(err error) bool {
+	return k8serrors.IsServerTimeout(err) ||
+		k8serrors.IsTimeout(err) ||
+		k8serrors.IsTooManyRequests(err) ||
+		k8serrors.IsServiceUnavailable(err) ||
+		k8serrors.IsInternalError(err)
+}
+
+// CurrentContext returns the name of the current context in the Kubernetes configuration file at the given path.
+func CurrentContext(path string) (string, error) {
+	config, err := clientcmd.LoadFromFile(path)
+	if err != nil {
+		return constant.EmptyString, err
+	}
+
+	return config.CurrentContext, nil
 }
 
-// WaitForPodToSucceedOrFail waits for the pod to succeed or fail.
+// WaitForPodToSucceedOrFail waits for the pod to succeed or fail, polling it every second on the clock.
+//
+// It returns the error of the context if the context is done before the pod succeeds or fails, e.g. once its deadline is exceeded.
 func WaitForPodToSucceedOrFail(
 	ctx context.Context,
 	logger *log.Logger,
+	clk clock.Clock,
 	clientset kubernetes.Interface,
 	namespace string,
 	podName string,
@@ -124,7 +232,11 @@ func WaitForPodToSucceedOrFail(
 			break
 		}
 
-		time.Sleep(time.Second)
+		select {
+		case <-ctx.Done():
+			return corev1.PodUnknown, ctx.Err()
+		case <-clk.After(time.Second):
+		}
 	}
 
 	return phase, nil
@@ -0,0 +1,414 @@
+// Package kubeutil provides utilities for interacting with Kubernetes.
+package kubeutil
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/clock"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
+	"github.com/charmbracelet/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// testKubeConfig is the Kubernetes configuration file content for testing.
+const testKubeConfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: test
+  cluster:
+    server: https://127.0.0.1:6443
+contexts:
+- name: test
+  context:
+    cluster: test
+    user: test
+current-context: test
+users:
+- name: test
+  user:
+    token: test
+`
+
+// TestConfig is a test that tests that the PodConfig function prefers the in-cluster configuration over a stray file at the default path when running in a
+// cluster, and that the Config function does not.
+func TestConfig(t *testing.T) {
+	testCases := []struct {
+		name        string
+		serviceHost string
+		kubeConfig  bool
+		explicit    bool
+		pod         bool
+		wantCluster bool
+	}{
+		{
+			name:       "Default path outside of a cluster",
+			kubeConfig: true,
+		},
+		{
+			name:        "Stray file at the default path in a cluster",
+			serviceHost: "10.0.0.1",
+			kubeConfig:  true,
+			pod:         true,
+			wantCluster: true,
+		},
+		{
+			name:        "Default path in a cluster",
+			serviceHost: "10.0.0.1",
+			kubeConfig:  true,
+		},
+		{
+			name:       "Default path of the pod outside of a cluster",
+			kubeConfig: true,
+			pod:        true,
+		},
+		{
+			name:        "No file outside of a cluster",
+			wantCluster: true,
+		},
+		{
+			name:        "Explicit path in a cluster",
+			serviceHost: "10.0.0.1",
+			kubeConfig:  true,
+			explicit:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			home := t.TempDir()
+			defaultPath := filepath.Join(home, ".kube", "config")
+
+			t.Setenv("HOME", home)
+			t.Setenv("KUBECONFIG", "")
+			t.Setenv("KUBERNETES_SERVICE_HOST", tc.serviceHost)
+			t.Setenv("KUBERNETES_SERVICE_PORT", "443")
+
+			if tc.kubeConfig {
+				require.NoError(t, os.MkdirAll(filepath.Dir(defaultPath), 0o700))
+				require.NoError(t, os.WriteFile(defaultPath, []byte(testKubeConfig), 0o600))
+			}
+
+			var path string
+
+			if tc.explicit {
+				path = defaultPath
+			}
+
+			load := Config
+
+			if tc.pod {
+				load = PodConfig
+			}
+
+			config, gotPath, err := load(path, constant.EmptyString)
+
+			if tc.wantCluster {
+				// The in-cluster configuration cannot be loaded outside of a real pod, as the service account token is missing.
+				assert.Equal(t, PathInCluster, gotPath)
+				assert.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, defaultPath, gotPath)
+			assert.Equal(t, "https://127.0.0.1:6443", config.Host)
+		})
+	}
+}
+
+// TestConfig_data is a test that tests that the Config function decodes the base64 encoded data, which takes precedence over the path when passed, and
+// over the KUBECONFIG environment variable but not the path when set in the KUBECONFIG_DATA environment variable.
+//
+// nolint:funlen
+func TestConfig_data(t *testing.T) {
+	// hostData is the host of the Kubernetes configuration in the data.
+	const hostData = "https://10.0.0.2:6443"
+
+	data := base64.StdEncoding.EncodeToString([]byte(strings.ReplaceAll(testKubeConfig, "https://127.0.0.1:6443", hostData)))
+
+	testCases := []struct {
+		name      string
+		path      bool
+		data      string
+		envData   string
+		envPath   bool
+		wantPath  string
+		wantHost  string
+		wantError bool
+	}{
+		{
+			name:     "Data",
+			data:     data,
+			wantPath: PathData,
+			wantHost: hostData,
+		},
+		{
+			name:     "Data with line breaks",
+			data:     data[:10] + "\n" + data[10:] + "\n",
+			wantPath: PathData,
+			wantHost: hostData,
+		},
+		{
+			name:     "Data over path",
+			path:     true,
+			data:     data,
+			wantPath: PathData,
+			wantHost: hostData,
+		},
+		{
+			name:     "Environment variable data",
+			envData:  data,
+			envPath:  true,
+			wantPath: PathData,
+			wantHost: hostData,
+		},
+		{
+			name:     "Path over environment variable data",
+			path:     true,
+			envData:  data,
+			wantHost: "https://127.0.0.1:6443",
+		},
+		{
+			name:      "Invalid base64",
+			data:      "not base64!",
+			wantPath:  PathData,
+			wantError: true,
+		},
+		{
+			name:      "Invalid Kubernetes configuration",
+			data:      base64.StdEncoding.EncodeToString([]byte("clusters: [")),
+			wantPath:  PathData,
+			wantError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config")
+
+			require.NoError(t, os.WriteFile(path, []byte(testKubeConfig), 0o600))
+
+			t.Setenv("KUBECONFIG", constant.EmptyString)
+			t.Setenv("KUBECONFIG_DATA", tc.envData)
+			t.Setenv("KUBERNETES_SERVICE_HOST", constant.EmptyString)
+
+			if tc.envPath {
+				t.Setenv("KUBECONFIG", path)
+			}
+
+			var argPath string
+
+			if tc.path {
+				argPath = path
+			}
+
+			config, gotPath, err := Config(argPath, tc.data)
+
+			if tc.wantPath == constant.EmptyString {
+				tc.wantPath = path
+			}
+
+			assert.Equal(t, tc.wantPath, gotPath)
+
+			if tc.wantError {
+				require.ErrorIs(t, err, errInvalidKubeConfigData)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantHost, config.Host)
+		})
+	}
+}
+
+// testMultiContextKubeConfig is the Kubernetes configuration file content with several contexts for testing.
+const testMultiContextKubeConfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: east
+  cluster:
+    server: https://10.0.1.1:6443
+- name: west
+  cluster:
+    server: https://10.0.2.1:6443
+contexts:
+- name: east
+  context:
+    cluster: east
+    user: test
+- name: west
+  context:
+    cluster: west
+    user: test
+current-context: east
+users:
+- name: test
+  user:
+    token: test
+`
+
+// TestContextConfig is a test that tests that the ContextConfig function returns the Kubernetes configuration of the named context, rather than the current
+// one, from both the path and the data, and returns an error for the context that does not exist or when the in-cluster configuration would be used.
+func TestContextConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+
+	require.NoError(t, os.WriteFile(path, []byte(testMultiContextKubeConfig), 0o600))
+
+	data := base64.StdEncoding.EncodeToString([]byte(testMultiContextKubeConfig))
+
+	testCases := []struct {
+		name        string
+		path        string
+		data        string
+		kubeContext string
+		serviceHost string
+		wantPath    string
+		wantHost    string
+		wantErr     bool
+	}{
+		{
+			name:        "Current context",
+			path:        path,
+			kubeContext: constant.EmptyString,
+			wantPath:    path,
+			wantHost:    "https://10.0.1.1:6443",
+		},
+		{
+			name:        "Other context",
+			path:        path,
+			kubeContext: "west",
+			wantPath:    path,
+			wantHost:    "https://10.0.2.1:6443",
+		},
+		{
+			name:        "Other context of the data",
+			data:        data,
+			kubeContext: "west",
+			wantPath:    PathData,
+			wantHost:    "https://10.0.2.1:6443",
+		},
+		{
+			name:        "Missing context",
+			path:        path,
+			kubeContext: "north",
+			wantPath:    path,
+			wantErr:     true,
+		},
+		{
+			name:        "In a cluster",
+			kubeContext: "west",
+			serviceHost: "10.0.0.1",
+			wantPath:    PathInCluster,
+			wantErr:     true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("KUBECONFIG", constant.EmptyString)
+			t.Setenv("KUBECONFIG_DATA", constant.EmptyString)
+			t.Setenv("KUBERNETES_SERVICE_HOST", tc.serviceHost)
+
+			config, gotPath, err := ContextConfig(tc.path, tc.data, tc.kubeContext)
+
+			assert.Equal(t, tc.wantPath, gotPath)
+
+			if tc.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantHost, config.Host)
+		})
+	}
+}
+
+// TestWaitForPodToSucceedOrFail is a test that tests that the WaitForPodToSucceedOrFail function polls the pod every second on the clock until it succeeds or
+// fails.
+func TestWaitForPodToSucceedOrFail(t *testing.T) {
+	testCases := []struct {
+		name       string
+		phases     []corev1.PodPhase
+		wantPhase  corev1.PodPhase
+		wantSleeps []time.Duration
+	}{
+		{name: "Succeeded at once", phases: []corev1.PodPhase{corev1.PodSucceeded}, wantPhase: corev1.PodSucceeded},
+		{
+			name:       "Succeeded after running",
+			phases:     []corev1.PodPhase{corev1.PodPending, corev1.PodRunning, corev1.PodSucceeded},
+			wantPhase:  corev1.PodSucceeded,
+			wantSleeps: []time.Duration{time.Second, time.Second},
+		},
+		{
+			name:       "Failed",
+			phases:     []corev1.PodPhase{corev1.PodRunning, corev1.PodFailed},
+			wantPhase:  corev1.PodFailed,
+			wantSleeps: []time.Duration{time.Second},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			clientset := fake.NewClientset()
+
+			var gets int
+
+			clientset.PrependReactor("get", "pods", func(k8stesting.Action) (bool, runtime.Object, error) {
+				pod := &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "default"},
+					Status:     corev1.PodStatus{Phase: tc.phases[gets]},
+				}
+
+				gets++
+
+				return true, pod, nil
+			})
+
+			clk := clock.NewFake(time.Time{})
+
+			phase, err := WaitForPodToSucceedOrFail(context.Background(), log.New(io.Discard), clk, clientset, "default", "pod")
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.wantPhase, phase)
+			assert.Equal(t, len(tc.phases), gets)
+			assert.Equal(t, tc.wantSleeps, clk.Sleeps())
+		})
+	}
+}
+
+// TestWaitForPodToSucceedOrFail_contextDone is a test that tests that the WaitForPodToSucceedOrFail function stops polling the pod that never succeeds or
+// fails once the context is done.
+func TestWaitForPodToSucceedOrFail_contextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clientset := fake.NewClientset()
+
+	clientset.PrependReactor("get", "pods", func(k8stesting.Action) (bool, runtime.Object, error) {
+		cancel()
+
+		return true, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "default"}, Status: corev1.PodStatus{Phase: corev1.PodRunning}}, nil
+	})
+
+	phase, err := WaitForPodToSucceedOrFail(ctx, log.New(io.Discard), clock.NewFake(time.Time{}), clientset, "default", "pod")
+	require.ErrorIs(t, err, context.Canceled)
+
+	assert.Equal(t, corev1.PodUnknown, phase)
+}
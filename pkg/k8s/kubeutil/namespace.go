@@ -0,0 +1,40 @@
+// Package kubeutil provides utilities for interacting with Kubernetes.
+package kubeutil
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// ErrInvalidNamespacePrefix is the error that is returned when the namespace prefix does not make valid namespace names.
+var ErrInvalidNamespacePrefix = errors.New("invalid namespace prefix")
+
+// namespacePrefixSeparator is the separator between the namespace prefix and the namespace.
+const namespacePrefixSeparator = "-"
+
+// PrefixNamespace returns the namespace of the install under the prefix, e.g. tenant1-mysql for the tenant1 prefix, or the namespace itself if the prefix is
+// empty.
+func PrefixNamespace(prefix string, namespace string) string {
+	if prefix == constant.EmptyString {
+		return namespace
+	}
+
+	return prefix + namespacePrefixSeparator + namespace
+}
+
+// ValidateNamespacePrefix returns an error if any of the namespaces under the prefix is not a valid namespace name.
+func ValidateNamespacePrefix(prefix string, namespaces ...string) error {
+	for _, namespace := range namespaces {
+		prefixed := PrefixNamespace(prefix, namespace)
+
+		if errs := validation.IsDNS1123Label(prefixed); len(errs) > 0 {
+			return fmt.Errorf("%w %q: %s is not a valid namespace: %s", ErrInvalidNamespacePrefix, prefix, prefixed, strings.Join(errs, "; "))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+// Package kubeutil provides utilities for interacting with Kubernetes.
+package kubeutil
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPrefixNamespace is a test that tests that the PrefixNamespace function prepends the prefix to the namespace, if any.
+func TestPrefixNamespace(t *testing.T) {
+	assert.Equal(t, "mysql", PrefixNamespace(constant.EmptyString, "mysql"))
+	assert.Equal(t, "tenant1-mysql", PrefixNamespace("tenant1", "mysql"))
+}
+
+// TestValidateNamespacePrefix is a test that tests that the ValidateNamespacePrefix function rejects the prefixes that make invalid namespace names.
+func TestValidateNamespacePrefix(t *testing.T) {
+	testCases := []struct {
+		name    string
+		prefix  string
+		wantErr bool
+	}{
+		{name: "Empty", prefix: constant.EmptyString},
+		{name: "Valid", prefix: "tenant1"},
+		{name: "Uppercase", prefix: "Tenant1", wantErr: true},
+		{name: "Trailing separator", prefix: "tenant1_", wantErr: true},
+		{name: "Too long", prefix: strings.Repeat("a", 60), wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateNamespacePrefix(tc.prefix, "mysql", "alphasense")
+			if tc.wantErr {
+				require.ErrorIs(t, err, ErrInvalidNamespacePrefix)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
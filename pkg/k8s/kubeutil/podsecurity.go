@@ -0,0 +1,69 @@
+// Package kubeutil provides utilities for interacting with Kubernetes.
+package kubeutil
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ErrInvalidPodSecurityProfile is the error that is returned when the pod security profile is invalid.
+var ErrInvalidPodSecurityProfile = errors.New("invalid pod security profile: must be " + PodSecurityProfileRestricted + " or " + PodSecurityProfileUnconfined)
+
+const (
+	// PodSecurityProfileRestricted is the pod security profile that hardens the pods to comply with the restricted PodSecurity standard.
+	PodSecurityProfileRestricted = "restricted"
+
+	// PodSecurityProfileUnconfined is the pod security profile that leaves the security context of the pods unset, for the clusters where the pods
+	// need elevated access.
+	PodSecurityProfileUnconfined = "unconfined"
+)
+
+// NonRootUID is the user and group ID that the pods run as under the restricted pod security profile, which is the ID of the nobody user.
+const NonRootUID = int64(65534)
+
+// constPodSecurityProfiles is the list of the valid pod security profiles.
+//
+// Do not modify this variable, it is supposed to be constant.
+var constPodSecurityProfiles = []string{PodSecurityProfileRestricted, PodSecurityProfileUnconfined}
+
+// ValidatePodSecurityProfile returns an error if the pod security profile is invalid.
+func ValidatePodSecurityProfile(profile string) error {
+	if !slices.Contains(constPodSecurityProfiles, profile) {
+		return ErrInvalidPodSecurityProfile
+	}
+
+	return nil
+}
+
+// ApplyPodSecurityProfile sets the security context of the pod spec and of all of its containers according to the pod security profile.
+//
+// Under the restricted profile, the pod runs as NonRootUID with the runtime default seccomp profile, and its containers cannot escalate privileges and
+// drop all capabilities, as required by the restricted PodSecurity standard.
+func ApplyPodSecurityProfile(spec *corev1.PodSpec, profile string) error {
+	if err := ValidatePodSecurityProfile(profile); err != nil {
+		return err
+	}
+
+	if profile == PodSecurityProfileUnconfined {
+		return nil
+	}
+
+	spec.SecurityContext = &corev1.PodSecurityContext{
+		RunAsNonRoot:   util.Ref(true),
+		RunAsUser:      util.Ref(NonRootUID),
+		RunAsGroup:     util.Ref(NonRootUID),
+		SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+	}
+
+	for i := range spec.Containers {
+		spec.Containers[i].SecurityContext = &corev1.SecurityContext{
+			AllowPrivilegeEscalation: util.Ref(false),
+			Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+		}
+	}
+
+	return nil
+}
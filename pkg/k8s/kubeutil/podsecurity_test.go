@@ -0,0 +1,62 @@
+// Package kubeutil provides utilities for interacting with Kubernetes.
+package kubeutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// TestApplyPodSecurityProfile is a test that tests that the ApplyPodSecurityProfile function hardens the pod spec only under the restricted profile.
+func TestApplyPodSecurityProfile(t *testing.T) {
+	testCases := []struct {
+		name         string
+		profile      string
+		wantHardened bool
+		wantErr      error
+	}{
+		{name: "Restricted", profile: PodSecurityProfileRestricted, wantHardened: true},
+		{name: "Unconfined", profile: PodSecurityProfileUnconfined},
+		{name: "Invalid", profile: "privileged", wantErr: ErrInvalidPodSecurityProfile},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			spec := &corev1.PodSpec{Containers: []corev1.Container{{Name: "a"}, {Name: "b"}}}
+
+			err := ApplyPodSecurityProfile(spec, tc.profile)
+
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			if !tc.wantHardened {
+				assert.Nil(t, spec.SecurityContext)
+
+				for _, container := range spec.Containers {
+					assert.Nil(t, container.SecurityContext)
+				}
+
+				return
+			}
+
+			require.NotNil(t, spec.SecurityContext)
+			assert.True(t, *spec.SecurityContext.RunAsNonRoot)
+			assert.Equal(t, NonRootUID, *spec.SecurityContext.RunAsUser)
+			assert.Equal(t, NonRootUID, *spec.SecurityContext.RunAsGroup)
+			assert.Equal(t, corev1.SeccompProfileTypeRuntimeDefault, spec.SecurityContext.SeccompProfile.Type)
+
+			for _, container := range spec.Containers {
+				require.NotNil(t, container.SecurityContext)
+				assert.False(t, *container.SecurityContext.AllowPrivilegeEscalation)
+				assert.Equal(t, []corev1.Capability{"ALL"}, container.SecurityContext.Capabilities.Drop)
+			}
+		})
+	}
+}
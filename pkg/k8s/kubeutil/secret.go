@@ -0,0 +1,103 @@
+// Package kubeutil provides utilities for interacting with Kubernetes.
+package kubeutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	pkgerrors "github.com/AlphaSense-Engineering/privatecloud-cli/pkg/errors"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+var (
+	// ErrFailedToGetSecret is the error that is returned when the secret cannot be obtained for another reason than it not existing.
+	ErrFailedToGetSecret = errors.New("failed to get Secret")
+
+	// ErrUnknownSecretKey is the error that is returned when a key of the secret is replaced that the check does not read.
+	ErrUnknownSecretKey = errors.New("unknown key of the Secret")
+)
+
+// GetSecret retrieves the secret, returning a SecretNotFound error if it does not exist, so that a missing secret reads the same across the checks.
+func GetSecret(ctx context.Context, clientset kubernetes.Interface, namespace string, name string) (*corev1.Secret, error) {
+	secret, err := clientset.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil, pkgerrors.NewSecretNotFound(namespace, name, err)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w %s/%s: %w", ErrFailedToGetSecret, namespace, name, err)
+	}
+
+	return secret, nil
+}
+
+// GetSecretData retrieves the data of the secret as strings, failing the same way as GetSecret.
+func GetSecretData(ctx context.Context, clientset kubernetes.Interface, namespace string, name string) (map[string]string, error) {
+	secret, err := GetSecret(ctx, clientset, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return util.ConvertMap(secret.Data, util.Identity[string], util.ByteSliceToString), nil
+}
+
+// ReplaceSecretKeys returns the data of the secret with the value of the key that replaces each of the default keys stored under the default key, so that
+// the checks read the secrets whose keys differ from the default ones by the default keys; a default key whose replacing key is missing is missing too.
+//
+// The data is not modified.
+func ReplaceSecretKeys(data map[string]string, keys map[string]string) map[string]string {
+	if len(keys) == 0 {
+		return data
+	}
+
+	replaced := maps.Clone(data)
+
+	for defaultKey, key := range keys {
+		value, ok := data[key]
+		if !ok {
+			delete(replaced, defaultKey)
+
+			continue
+		}
+
+		replaced[defaultKey] = value
+	}
+
+	return replaced
+}
+
+// ValidateSecretKeys returns an error if any of the keys that the keys replace is not one of the default keys that the check reads.
+func ValidateSecretKeys(keys map[string]string, defaultKeys []string) error {
+	for _, defaultKey := range slices.Sorted(maps.Keys(keys)) {
+		if !slices.Contains(defaultKeys, defaultKey) {
+			return fmt.Errorf("%w: %q is not one of %s", ErrUnknownSecretKey, defaultKey, strings.Join(defaultKeys, ", "))
+		}
+	}
+
+	return nil
+}
+
+// SecretKeys returns the keys that the check reads from the secret, in the order of the default keys, each of them replaced by its key of the keys if any.
+func SecretKeys(defaultKeys []string, keys map[string]string) []string {
+	secretKeys := make([]string, 0, len(defaultKeys))
+
+	for _, defaultKey := range defaultKeys {
+		if key, ok := keys[defaultKey]; ok {
+			secretKeys = append(secretKeys, key)
+
+			continue
+		}
+
+		secretKeys = append(secretKeys, defaultKey)
+	}
+
+	return secretKeys
+}
@@ -0,0 +1,133 @@
+// Package kubeutil provides utilities for interacting with Kubernetes.
+package kubeutil
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	pkgerrors "github.com/AlphaSense-Engineering/privatecloud-cli/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// TestGetSecretData is a test that tests that the GetSecretData function returns the data of the secret, and names the secret that is not found or that
+// cannot be obtained.
+func TestGetSecretData(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "default-creds", Namespace: "mysql"},
+		Data:       map[string][]byte{"username": []byte("admin")},
+	}
+
+	testCases := []struct {
+		name         string
+		secretName   string
+		reactorErr   error
+		want         map[string]string
+		wantErr      string
+		wantNotFound bool
+	}{
+		{
+			name:       "Found",
+			secretName: "default-creds",
+			want:       map[string]string{"username": "admin"},
+		},
+		{
+			name:         "Not found",
+			secretName:   "missing",
+			wantErr:      "secret mysql/missing not found",
+			wantNotFound: true,
+		},
+		{
+			name:       "Forbidden",
+			secretName: "default-creds",
+			reactorErr: errors.New("forbidden"),
+			wantErr:    "failed to get Secret mysql/default-creds: forbidden",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			clientset := fake.NewClientset(secret)
+
+			if tc.reactorErr != nil {
+				clientset.PrependReactor("get", "secrets", func(k8stesting.Action) (bool, runtime.Object, error) {
+					return true, nil, tc.reactorErr
+				})
+			}
+
+			got, err := GetSecretData(context.Background(), clientset, "mysql", tc.secretName)
+
+			if tc.wantErr == "" {
+				require.NoError(t, err)
+				assert.Equal(t, tc.want, got)
+
+				return
+			}
+
+			require.EqualError(t, err, tc.wantErr)
+
+			var notFound *pkgerrors.SecretNotFound
+
+			assert.Equal(t, tc.wantNotFound, errors.As(err, &notFound))
+			assert.Equal(t, tc.wantNotFound, k8serrors.IsNotFound(err))
+		})
+	}
+}
+
+// TestReplaceSecretKeys is a test that tests that the ReplaceSecretKeys function stores the value of each of the replacing keys under the default key,
+// without modifying the data.
+func TestReplaceSecretKeys(t *testing.T) {
+	data := map[string]string{"username": "admin", "db-password": "secret", "password": "stale"}
+
+	testCases := []struct {
+		name string
+		keys map[string]string
+		want map[string]string
+	}{
+		{name: "No keys", want: data},
+		{
+			name: "Replaced key",
+			keys: map[string]string{"password": "db-password"},
+			want: map[string]string{"username": "admin", "db-password": "secret", "password": "secret"},
+		},
+		{
+			name: "Missing replacing key",
+			keys: map[string]string{"username": "db-user"},
+			want: map[string]string{"db-password": "secret", "password": "stale"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, ReplaceSecretKeys(data, tc.keys))
+			assert.Equal(t, "stale", data["password"])
+		})
+	}
+}
+
+// TestValidateSecretKeys is a test that tests that the ValidateSecretKeys function rejects the keys that replace a key that is not one of the default ones.
+func TestValidateSecretKeys(t *testing.T) {
+	defaultKeys := []string{"username", "password"}
+
+	require.NoError(t, ValidateSecretKeys(nil, defaultKeys))
+	require.NoError(t, ValidateSecretKeys(map[string]string{"password": "db-password"}, defaultKeys))
+
+	err := ValidateSecretKeys(map[string]string{"passwd": "db-password"}, defaultKeys)
+	require.ErrorIs(t, err, ErrUnknownSecretKey)
+	assert.ErrorContains(t, err, `"passwd" is not one of username, password`)
+}
+
+// TestSecretKeys is a test that tests that the SecretKeys function returns the default keys, each replaced by its key of the keys if any.
+func TestSecretKeys(t *testing.T) {
+	defaultKeys := []string{"username", "password", "port"}
+
+	assert.Equal(t, defaultKeys, SecretKeys(defaultKeys, nil))
+	assert.Equal(t, []string{"username", "db-password", "port"}, SecretKeys(defaultKeys, map[string]string{"password": "db-password"}))
+}
@@ -0,0 +1,132 @@
+// Package podprotocol is the package that contains the protocol of the messages that the pod writes for the Check command, along with its log lines.
+package podprotocol
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/clock"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/report"
+	"go.uber.org/multierr"
+)
+
+var (
+	// errFailedToWriteMessage is the error that is returned when a message cannot be written.
+	errFailedToWriteMessage = errors.New("failed to write pod message")
+
+	// errFailedToParseMessage is the error that is returned when a line that carries the schema version is not a valid message.
+	errFailedToParseMessage = errors.New("failed to parse pod message")
+)
+
+// SchemaVersion is the version of the schema of the messages that this version of the CLI writes and reads.
+//
+// Bump it whenever a field or a type of the messages changes its meaning or is removed, so that the skew between the CLI and the pod is detected; adding
+// a field or a type does not require it, as the readers ignore the ones they do not know.
+const SchemaVersion = 1
+
+// MessageType is the type of the type of a message, which tells what the message carries.
+type MessageType string
+
+const (
+	// TypeVersion is the type of the message that carries the version of the pod, written first.
+	TypeVersion MessageType = "version"
+	// TypeCheckStart is the type of the message that carries the name of a check as it starts.
+	TypeCheckStart MessageType = "check-start"
+	// TypeCheckResult is the type of the message that carries the result of a check.
+	TypeCheckResult MessageType = "check-result"
+	// TypeSummary is the type of the message that carries the summary of the results, written after all of them.
+	TypeSummary MessageType = "summary"
+)
+
+// PodMessage is the type that contains a message that the pod writes for the Check command, one per line, interleaved with the log lines.
+//
+// The log lines do not carry the schema version, which tells the messages apart from them.
+type PodMessage struct {
+	// Schema is the version of the schema of the message, set by the writer.
+	Schema int `json:"schema"`
+	// Type is the type of the message.
+	Type MessageType `json:"type"`
+	// Time is the time at which the message was written, set by the writer.
+	Time time.Time `json:"time"`
+	// Version is the version of the pod, only set in the messages of the TypeVersion type.
+	Version string `json:"version,omitempty"`
+	// Check is the name of the check, only set in the messages of the TypeCheckStart type.
+	Check string `json:"check,omitempty"`
+	// Result is the result of the check, only set in the messages of the TypeCheckResult type.
+	Result *report.Result `json:"result,omitempty"`
+	// Summary is the summary of the results, only set in the messages of the TypeSummary type.
+	Summary *report.Summary `json:"summary,omitempty"`
+}
+
+// Writer is the type that writes the messages as newline-delimited JSON, each in a single write, so that they are not interleaved with the log lines
+// written to the same writer.
+//
+// It is safe for concurrent use.
+type Writer struct {
+	// mu is the mutex that serializes the writes.
+	mu sync.Mutex
+	// out is the writer that the messages are written to.
+	out io.Writer
+	// clock is the clock that the messages are timestamped with.
+	clock clock.Clock
+}
+
+// Write is the function that writes the message, setting its schema version and timestamping it with the current time in UTC.
+//
+// It returns an error if the message cannot be written.
+func (w *Writer) Write(m PodMessage) error {
+	m.Schema = SchemaVersion
+	m.Time = w.clock.Now().UTC()
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		return multierr.Combine(errFailedToWriteMessage, err)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, err := w.out.Write(append(data, '\n')); err != nil {
+		return multierr.Combine(errFailedToWriteMessage, err)
+	}
+
+	return nil
+}
+
+// NewWriter is the function that creates a new Writer that writes to the writer, timestamping the messages with the clock.
+func NewWriter(out io.Writer, clk clock.Clock) *Writer {
+	return &Writer{out: out, clock: clk}
+}
+
+// Parse is the function that parses the line into a message.
+//
+// It returns whether the line is a message, as the log lines do not carry the schema version, or an error if the line is not JSON or carries the schema
+// version without being a valid message.
+func Parse(line []byte) (PodMessage, bool, error) {
+	// probe is the type that tells the messages apart from the log lines.
+	type probe struct {
+		// Schema is the version of the schema of the message, nil for the log lines.
+		Schema *int `json:"schema"`
+	}
+
+	var p probe
+
+	if err := json.Unmarshal(line, &p); err != nil {
+		return PodMessage{}, false, err
+	}
+
+	if p.Schema == nil {
+		return PodMessage{}, false, nil
+	}
+
+	var m PodMessage
+
+	if err := json.Unmarshal(line, &m); err != nil {
+		return PodMessage{}, false, multierr.Combine(errFailedToParseMessage, err)
+	}
+
+	return m, true, nil
+}
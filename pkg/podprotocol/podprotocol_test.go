@@ -0,0 +1,162 @@
+// Package podprotocol is the package that contains the protocol of the messages that the pod writes for the Check command, along with its log lines.
+package podprotocol
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/clock"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// errFailingWriter is the error that the failing writer returns.
+var errFailingWriter = errors.New("disk full")
+
+// failingWriter is the type that implements the io.Writer interface, failing every write.
+type failingWriter struct{}
+
+// Write is the function that fails the write.
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errFailingWriter
+}
+
+// TestWriter_roundTrip is a test that tests that the messages of every type that the Writer writes are parsed back alike, with the schema version and the
+// time set by the writer.
+func TestWriter_roundTrip(t *testing.T) {
+	now := time.Date(2026, time.January, 2, 3, 4, 5, 0, time.UTC)
+
+	testCases := []struct {
+		name    string
+		message PodMessage
+	}{
+		{name: "Version", message: PodMessage{Type: TypeVersion, Version: "1.2.3"}},
+		{name: "Check start", message: PodMessage{Type: TypeCheckStart, Check: "MySQL"}},
+		{
+			name: "Check result",
+			message: PodMessage{Type: TypeCheckResult, Result: &report.Result{
+				Kind:    report.KindResult,
+				Check:   "MySQL",
+				Status:  report.StatusFailed,
+				Message: "access denied",
+				Time:    now,
+			}},
+		},
+		{
+			name:    "Summary",
+			message: PodMessage{Type: TypeSummary, Summary: &report.Summary{Kind: report.KindSummary, Total: 2, Passed: 1, Failed: 1}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			require.NoError(t, NewWriter(&buf, clock.NewFake(now)).Write(tc.message))
+
+			line, ok := strings.CutSuffix(buf.String(), "\n")
+			require.True(t, ok, "the message is not terminated with a newline")
+			assert.NotContains(t, line, "\n")
+
+			got, ok, err := Parse([]byte(line))
+			require.NoError(t, err)
+			require.True(t, ok)
+
+			want := tc.message
+			want.Schema = SchemaVersion
+			want.Time = now
+
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+// TestParse is a test that tests that the Parse function tells the messages apart from the log lines by the schema version, keeping the schema version of
+// the messages that do not match the one of the CLI.
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name    string
+		line    string
+		want    PodMessage
+		wantOK  bool
+		wantErr error
+	}{
+		{
+			name: "Log line",
+			line: `{"time":"2026/01/02 03:04:05","level":"info","msg":"checked the storage class"}`,
+		},
+		{
+			name:   "Newer schema",
+			line:   `{"schema":2,"type":"progress","time":"2026-01-02T03:04:05Z"}`,
+			want:   PodMessage{Schema: 2, Type: "progress", Time: time.Date(2026, time.January, 2, 3, 4, 5, 0, time.UTC)},
+			wantOK: true,
+		},
+		{
+			name:    "Invalid message",
+			line:    `{"schema":1,"type":"check-result","result":"passed"}`,
+			wantErr: errFailedToParseMessage,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok, err := Parse([]byte(tc.line))
+
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.wantOK, ok)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+
+	_, _, err := Parse([]byte("not JSON"))
+	assert.Error(t, err)
+}
+
+// TestWriter_Write_concurrent is a test that tests that the concurrent writes of the Writer are not interleaved, each message being on a line of its own.
+func TestWriter_Write_concurrent(t *testing.T) {
+	// count is the number of the messages written concurrently.
+	const count = 50
+
+	var buf bytes.Buffer
+
+	w := NewWriter(&buf, clock.NewFake(time.Time{}))
+
+	var wg sync.WaitGroup
+
+	for range count {
+		wg.Go(func() {
+			assert.NoError(t, w.Write(PodMessage{Type: TypeCheckStart, Check: strings.Repeat("a", 1000)}))
+		})
+	}
+
+	wg.Wait()
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, count)
+
+	for _, line := range lines {
+		_, ok, err := Parse([]byte(line))
+		require.NoError(t, err)
+		assert.True(t, ok)
+	}
+}
+
+// TestWriter_Write_error is a test that tests that the Write function returns an error if the message cannot be written.
+func TestWriter_Write_error(t *testing.T) {
+	err := NewWriter(failingWriter{}, clock.NewFake(time.Time{})).Write(PodMessage{Type: TypeVersion})
+
+	assert.ErrorIs(t, err, errFailedToWriteMessage)
+	assert.ErrorIs(t, err, errFailingWriter)
+}
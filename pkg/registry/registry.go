@@ -0,0 +1,322 @@
+// Package registry is the package that contains the functions that query the container image registries.
+package registry
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
+	"go.uber.org/multierr"
+)
+
+var (
+	// ErrImageNotFound is the error that is returned when the registry does not have the manifest of the image.
+	ErrImageNotFound = errors.New("image not found")
+
+	// ErrUnauthorized is the error that is returned when the registry refuses to serve the manifest of the image with the credentials given, if any.
+	ErrUnauthorized = errors.New("unauthorized to pull the image")
+
+	// ErrUnexpectedStatus is the error that is returned when the registry responds with a status other than the ones above.
+	ErrUnexpectedStatus = errors.New("unexpected status of the registry")
+
+	// ErrInvalidReference is the error that is returned when the image reference cannot be split into the registry, the repository and the tag or digest.
+	ErrInvalidReference = errors.New("invalid image reference")
+
+	// errFailedToGetToken is the error that is returned when the token of the registry cannot be obtained.
+	errFailedToGetToken = errors.New("failed to get registry token")
+
+	// errFailedToDecodeDockerConfig is the error that is returned when the Docker configuration of the image pull secret cannot be decoded.
+	errFailedToDecodeDockerConfig = errors.New("failed to decode Docker configuration")
+)
+
+const (
+	// dockerHubDomain is the domain of Docker Hub as it appears in the image references, which is the default one.
+	dockerHubDomain = "docker.io"
+
+	// dockerHubHost is the host of the registry API of Docker Hub.
+	dockerHubHost = "registry-1.docker.io"
+
+	// dockerHubAuthKey is the key of Docker Hub in the Docker configuration files written by docker login.
+	dockerHubAuthKey = "https://index.docker.io/v1/"
+
+	// officialRepositoryPrefix is the prefix of the repositories of the official images of Docker Hub, which the references omit.
+	officialRepositoryPrefix = "library/"
+
+	// defaultTag is the tag of the image references that have neither a tag nor a digest.
+	defaultTag = "latest"
+
+	// schemeBearer is the scheme of the WWW-Authenticate challenge that asks for a token.
+	schemeBearer = "Bearer"
+)
+
+// constManifestMediaTypes is the list of the media types of the manifests that are accepted, the single-platform and the multi-platform ones of both OCI and
+// Docker.
+//
+// Do not modify this variable, it is supposed to be constant.
+var constManifestMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// constChallengeParamRegexp is the regular expression that matches the parameters of the WWW-Authenticate challenge.
+//
+// Do not modify this variable, it is supposed to be constant.
+var constChallengeParamRegexp = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// Reference is the type that contains the parts of an image reference that locate its manifest.
+type Reference struct {
+	// Host is the host of the registry API, with the optional port.
+	Host string
+	// Repository is the repository of the image in the registry.
+	Repository string
+	// TagOrDigest is the tag or the digest of the image.
+	TagOrDigest string
+}
+
+// ParseReference is a function that splits the image reference into the host of the registry, the repository and the tag or digest, defaulting them the way
+// the container runtimes do: Docker Hub for the references without a registry, the library repositories for its single-component ones, and the latest
+// tag.
+func ParseReference(ref string) (Reference, error) {
+	name, digest, hasDigest := strings.Cut(ref, "@")
+
+	tagOrDigest := digest
+
+	if lastSlash, lastColon := strings.LastIndex(name, "/"), strings.LastIndex(name, ":"); lastColon > lastSlash {
+		if !hasDigest {
+			tagOrDigest = name[lastColon+1:]
+		}
+
+		name = name[:lastColon]
+	}
+
+	if tagOrDigest == constant.EmptyString {
+		tagOrDigest = defaultTag
+	}
+
+	host, repository := dockerHubDomain, name
+
+	if domain, rest, ok := strings.Cut(name, "/"); ok && (strings.ContainsAny(domain, ".:") || domain == "localhost") {
+		host, repository = domain, rest
+	}
+
+	if repository == constant.EmptyString {
+		return Reference{}, fmt.Errorf("%w: %q", ErrInvalidReference, ref)
+	}
+
+	if host == dockerHubDomain {
+		host = dockerHubHost
+
+		if !strings.Contains(repository, "/") {
+			repository = officialRepositoryPrefix + repository
+		}
+	}
+
+	return Reference{Host: host, Repository: repository, TagOrDigest: tagOrDigest}, nil
+}
+
+// Credentials is the type that contains the credentials of a registry.
+type Credentials struct {
+	// Username is the username.
+	Username string
+	// Password is the password or the access token.
+	Password string
+}
+
+// CredentialsFromDockerConfig is a function that returns the credentials of the registry host from the Docker configuration of an image pull secret, or nil
+// if it has none for the host.
+func CredentialsFromDockerConfig(data []byte, host string) (*Credentials, error) {
+	// dockerConfig is the struct that represents the Docker configuration.
+	type dockerConfig struct {
+		// Auths is the map of the registries to their credentials.
+		Auths map[string]struct {
+			// Username is the username.
+			Username string `json:"username"`
+			// Password is the password.
+			Password string `json:"password"`
+			// Auth is the base64 encoded username and password, separated by a colon.
+			Auth string `json:"auth"`
+		} `json:"auths"`
+	}
+
+	var config dockerConfig
+
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, multierr.Combine(errFailedToDecodeDockerConfig, err)
+	}
+
+	keys := []string{host, "https://" + host, "https://" + host + "/v1/", "https://" + host + "/v2/"}
+
+	if host == dockerHubHost {
+		keys = append(keys, dockerHubDomain, dockerHubAuthKey)
+	}
+
+	for _, key := range keys {
+		auth, ok := config.Auths[key]
+		if !ok {
+			continue
+		}
+
+		if auth.Auth == constant.EmptyString {
+			return &Credentials{Username: auth.Username, Password: auth.Password}, nil
+		}
+
+		decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+		if err != nil {
+			return nil, multierr.Combine(errFailedToDecodeDockerConfig, err)
+		}
+
+		username, password, _ := strings.Cut(string(decoded), ":")
+
+		return &Credentials{Username: username, Password: password}, nil
+	}
+
+	return nil, nil
+}
+
+// headManifest is a function that requests the manifest of the image with the authorization, if any, and returns the response.
+func headManifest(ctx context.Context, client *http.Client, ref Reference, authorization string) (*http.Response, error) {
+	manifestURL := (&url.URL{Scheme: "https", Host: ref.Host, Path: "/v2/" + ref.Repository + "/manifests/" + ref.TagOrDigest}).String()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, manifestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", strings.Join(constManifestMediaTypes, ", "))
+
+	if authorization != constant.EmptyString {
+		req.Header.Set("Authorization", authorization)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	_ = resp.Body.Close()
+
+	return resp, nil
+}
+
+// token is a function that obtains the token that the Bearer challenge of the registry asks for, authenticating with the credentials, if any.
+func token(ctx context.Context, client *http.Client, challenge string, creds *Credentials) (string, error) {
+	params := map[string]string{}
+
+	for _, match := range constChallengeParamRegexp.FindAllStringSubmatch(challenge, -1) {
+		params[match[1]] = match[2]
+	}
+
+	tokenURL, err := url.Parse(params["realm"])
+	if err != nil || tokenURL.Host == constant.EmptyString {
+		return constant.EmptyString, fmt.Errorf("%w: invalid realm %q", errFailedToGetToken, params["realm"])
+	}
+
+	query := tokenURL.Query()
+
+	for _, key := range []string{"service", "scope"} {
+		if params[key] != constant.EmptyString {
+			query.Set(key, params[key])
+		}
+	}
+
+	tokenURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, tokenURL.String(), nil)
+	if err != nil {
+		return constant.EmptyString, err
+	}
+
+	if creds != nil {
+		req.SetBasicAuth(creds.Username, creds.Password)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return constant.EmptyString, multierr.Combine(errFailedToGetToken, err)
+	}
+	defer resp.Body.Close() // nolint:errcheck
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return constant.EmptyString, ErrUnauthorized
+	default:
+		return constant.EmptyString, fmt.Errorf("%w: token endpoint responded with %s", errFailedToGetToken, resp.Status)
+	}
+
+	// tokenResponse is the struct that represents the response of the token endpoint, which sets either of the fields.
+	type tokenResponse struct {
+		// Token is the token.
+		Token string `json:"token"`
+		// AccessToken is the token, under its OAuth 2.0 name.
+		AccessToken string `json:"access_token"` // nolint:tagliatelle
+	}
+
+	var body tokenResponse
+
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return constant.EmptyString, multierr.Combine(errFailedToGetToken, err)
+	}
+
+	if body.Token != constant.EmptyString {
+		return body.Token, nil
+	}
+
+	return body.AccessToken, nil
+}
+
+// CheckManifest is a function that checks that the registry serves the manifest of the image, answering the Bearer or Basic challenge of the registry with
+// the credentials, if any.
+//
+// It returns nil if the image is available, ErrImageNotFound if it does not exist, ErrUnauthorized if the registry refuses to serve it, or another error if
+// the registry cannot be queried.
+func CheckManifest(ctx context.Context, client *http.Client, ref Reference, creds *Credentials) error {
+	resp, err := headManifest(ctx, client, ref, constant.EmptyString)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+
+		var authorization string
+
+		switch {
+		case strings.HasPrefix(challenge, schemeBearer+" "):
+			t, err := token(ctx, client, challenge, creds)
+			if err != nil {
+				return err
+			}
+
+			authorization = schemeBearer + " " + t
+		case creds != nil:
+			authorization = "Basic " + base64.StdEncoding.EncodeToString([]byte(creds.Username+":"+creds.Password))
+		default:
+			return ErrUnauthorized
+		}
+
+		if resp, err = headManifest(ctx, client, ref, authorization); err != nil {
+			return err
+		}
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return ErrImageNotFound
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrUnauthorized
+	default:
+		return fmt.Errorf("%w: %s", ErrUnexpectedStatus, resp.Status)
+	}
+}
@@ -0,0 +1,250 @@
+// Package registry is the package that contains the functions that query the container image registries.
+package registry
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseReference is a test that tests that the ParseReference function splits and defaults the image references.
+func TestParseReference(t *testing.T) {
+	testCases := []struct {
+		name    string
+		ref     string
+		want    Reference
+		wantErr bool
+	}{
+		{
+			name: "Official image",
+			ref:  "busybox",
+			want: Reference{Host: "registry-1.docker.io", Repository: "library/busybox", TagOrDigest: "latest"},
+		},
+		{
+			name: "Docker Hub with tag",
+			ref:  "alphasense/privatecloud-installer:1.2.3",
+			want: Reference{Host: "registry-1.docker.io", Repository: "alphasense/privatecloud-installer", TagOrDigest: "1.2.3"},
+		},
+		{
+			name: "Registry with port",
+			ref:  "localhost:5000/installer",
+			want: Reference{Host: "localhost:5000", Repository: "installer", TagOrDigest: "latest"},
+		},
+		{
+			name: "Digest",
+			ref:  "ghcr.io/acme/installer:1.2.3@sha256:abc",
+			want: Reference{Host: "ghcr.io", Repository: "acme/installer", TagOrDigest: "sha256:abc"},
+		},
+		{
+			name:    "Empty",
+			ref:     "ghcr.io/",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseReference(tc.ref)
+
+			if tc.wantErr {
+				require.ErrorIs(t, err, ErrInvalidReference)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+// TestCredentialsFromDockerConfig is a test that tests that the CredentialsFromDockerConfig function returns the credentials of the host.
+func TestCredentialsFromDockerConfig(t *testing.T) {
+	auth := base64.StdEncoding.EncodeToString([]byte("user:pass"))
+
+	testCases := []struct {
+		name    string
+		config  string
+		host    string
+		want    *Credentials
+		wantErr bool
+	}{
+		{
+			name:   "Auth",
+			config: `{"auths":{"ghcr.io":{"auth":"` + auth + `"}}}`,
+			host:   "ghcr.io",
+			want:   &Credentials{Username: "user", Password: "pass"},
+		},
+		{
+			name:   "Username and password",
+			config: `{"auths":{"https://ghcr.io":{"username":"user","password":"pass"}}}`,
+			host:   "ghcr.io",
+			want:   &Credentials{Username: "user", Password: "pass"},
+		},
+		{
+			name:   "Docker Hub",
+			config: `{"auths":{"https://index.docker.io/v1/":{"auth":"` + auth + `"}}}`,
+			host:   "registry-1.docker.io",
+			want:   &Credentials{Username: "user", Password: "pass"},
+		},
+		{
+			name:   "Other host",
+			config: `{"auths":{"quay.io":{"auth":"` + auth + `"}}}`,
+			host:   "ghcr.io",
+		},
+		{
+			name:    "Malformed",
+			config:  `{"auths":`,
+			host:    "ghcr.io",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := CredentialsFromDockerConfig([]byte(tc.config), tc.host)
+
+			if tc.wantErr {
+				require.ErrorIs(t, err, errFailedToDecodeDockerConfig)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+// TestCheckManifest is a test that tests that the CheckManifest function maps the responses of a stub registry, answering its challenges.
+//
+// nolint:funlen
+func TestCheckManifest(t *testing.T) {
+	// token is the token that the stub registry issues for the credentials.
+	const token = "token"
+
+	creds := &Credentials{Username: "user", Password: "pass"}
+
+	testCases := []struct {
+		name    string
+		status  int
+		scheme  string
+		creds   *Credentials
+		wantErr error
+	}{
+		{
+			name:   "Available",
+			status: http.StatusOK,
+		},
+		{
+			name:    "Not found",
+			status:  http.StatusNotFound,
+			wantErr: ErrImageNotFound,
+		},
+		{
+			name:    "Unauthorized",
+			status:  http.StatusUnauthorized,
+			wantErr: ErrUnauthorized,
+		},
+		{
+			name:    "Unexpected status",
+			status:  http.StatusInternalServerError,
+			wantErr: ErrUnexpectedStatus,
+		},
+		{
+			name:   "Bearer challenge",
+			status: http.StatusOK,
+			scheme: "Bearer",
+			creds:  creds,
+		},
+		{
+			name:    "Bearer challenge without credentials",
+			status:  http.StatusOK,
+			scheme:  "Bearer",
+			wantErr: ErrUnauthorized,
+		},
+		{
+			name:   "Basic challenge",
+			status: http.StatusOK,
+			scheme: "Basic",
+			creds:  creds,
+		},
+		{
+			name:    "Basic challenge without credentials",
+			status:  http.StatusOK,
+			scheme:  "Basic",
+			wantErr: ErrUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var server *httptest.Server
+
+			server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/token" {
+					if username, password, ok := r.BasicAuth(); !ok || username != creds.Username || password != creds.Password {
+						w.WriteHeader(http.StatusUnauthorized)
+
+						return
+					}
+
+					assert.Equal(t, "repository:acme/installer:pull", r.URL.Query().Get("scope"))
+
+					_, _ = w.Write([]byte(`{"token":"` + token + `"}`))
+
+					return
+				}
+
+				assert.Equal(t, http.MethodHead, r.Method)
+				assert.Equal(t, "/v2/acme/installer/manifests/1.2.3", r.URL.Path)
+
+				authorized := false
+
+				switch tc.scheme {
+				case "Bearer":
+					authorized = r.Header.Get("Authorization") == "Bearer "+token
+
+					w.Header().Set("WWW-Authenticate", `Bearer realm="`+server.URL+`/token",service="registry",scope="repository:acme/installer:pull"`)
+				case "Basic":
+					username, password, ok := r.BasicAuth()
+					authorized = ok && username == creds.Username && password == creds.Password
+
+					w.Header().Set("WWW-Authenticate", `Basic realm="registry"`)
+				default:
+					authorized = true
+				}
+
+				if !authorized {
+					w.WriteHeader(http.StatusUnauthorized)
+
+					return
+				}
+
+				w.WriteHeader(tc.status)
+			}))
+			defer server.Close()
+
+			serverURL, err := url.Parse(server.URL)
+			require.NoError(t, err)
+
+			ref := Reference{Host: serverURL.Host, Repository: "acme/installer", TagOrDigest: "1.2.3"}
+
+			err = CheckManifest(context.Background(), server.Client(), ref, tc.creds)
+
+			if tc.wantErr == nil {
+				require.NoError(t, err)
+
+				return
+			}
+
+			require.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
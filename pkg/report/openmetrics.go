@@ -0,0 +1,81 @@
+// Package report is the package that contains the report of the results of the checks.
+package report
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"go.uber.org/multierr"
+)
+
+const (
+	// metricCheckStatus is the name of the gauge of the status of each check, one series per status, of which only the one of the status of the check is
+	// 1, as the state sets of Prometheus are.
+	metricCheckStatus = "privatecloud_cli_check_status"
+
+	// metricStatus is the name of the gauge of the overall status of the run, one series per status, of which only the one of the status of the run is 1.
+	metricStatus = "privatecloud_cli_status"
+
+	// metricLastRunTimestamp is the name of the gauge of the time at which the run finished, in seconds since the Unix epoch.
+	metricLastRunTimestamp = "privatecloud_cli_last_run_timestamp_seconds"
+)
+
+// constStatuses is the list of the statuses that the gauges of the statuses have a series for, in the order they are written.
+//
+// Do not modify this variable, it is supposed to be constant.
+var constStatuses = []Status{StatusPassed, StatusWarning, StatusFailed, StatusSkipped}
+
+// labelValueReplacer is the replacer that escapes the label values, as required by the OpenMetrics text format.
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// WriteOpenMetrics is the function that writes the results, the overall status of the run and the time at which it finished to the writer in the
+// OpenMetrics text format, which the Prometheus text parsers, such as the one of the textfile collector of node_exporter, also read.
+//
+// It returns an error if the metrics cannot be written.
+func WriteOpenMetrics(w io.Writer, results []Result, status Status, finishedAt time.Time) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, "# TYPE %s gauge\n", metricCheckStatus)
+	fmt.Fprintf(bw, "# HELP %s Whether the status of the check in the last run is the one of the status label.\n", metricCheckStatus)
+
+	for _, r := range results {
+		check := labelValueReplacer.Replace(r.Check)
+		target := labelValueReplacer.Replace(r.Target)
+
+		for _, s := range constStatuses {
+			fmt.Fprintf(bw, "%s{check=\"%s\",target=\"%s\",status=\"%s\"} %d\n", metricCheckStatus, check, target, s, stateValue(r.Status == s))
+		}
+	}
+
+	fmt.Fprintf(bw, "# TYPE %s gauge\n", metricStatus)
+	fmt.Fprintf(bw, "# HELP %s Whether the overall status of the last run is the one of the status label.\n", metricStatus)
+
+	for _, s := range constStatuses {
+		fmt.Fprintf(bw, "%s{status=\"%s\"} %d\n", metricStatus, s, stateValue(status == s))
+	}
+
+	fmt.Fprintf(bw, "# TYPE %s gauge\n", metricLastRunTimestamp)
+	fmt.Fprintf(bw, "# UNIT %s seconds\n", metricLastRunTimestamp)
+	fmt.Fprintf(bw, "# HELP %s The time at which the last run finished.\n", metricLastRunTimestamp)
+	fmt.Fprintf(bw, "%s %d\n", metricLastRunTimestamp, finishedAt.Unix())
+
+	fmt.Fprint(bw, "# EOF\n")
+
+	if err := bw.Flush(); err != nil {
+		return multierr.Combine(errFailedToWriteReport, err)
+	}
+
+	return nil
+}
+
+// stateValue is the function that returns the value of the series of a state set, 1 if the state is the current one and 0 otherwise.
+func stateValue(current bool) int {
+	if current {
+		return 1
+	}
+
+	return 0
+}
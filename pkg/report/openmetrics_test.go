@@ -0,0 +1,74 @@
+// Package report is the package that contains the report of the results of the checks.
+package report
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWriteOpenMetrics is a test that tests that the WriteOpenMetrics function writes a series per status of each check and of the run, of which only the
+// one of the current status is 1, along with the time of the run, in the OpenMetrics text format.
+func TestWriteOpenMetrics(t *testing.T) {
+	// sampleRegex is the regular expression that the sample lines of the OpenMetrics text format match.
+	sampleRegex := regexp.MustCompile(`^[a-z_]+(\{([a-z]+="([^"\\]|\\.)*",?)+\})? -?[0-9]+$`)
+
+	finishedAt := time.Date(2026, time.January, 2, 3, 5, 5, 0, time.UTC)
+
+	var buf bytes.Buffer
+
+	require.NoError(t, WriteOpenMetrics(&buf, []Result{
+		{Check: "storage class", Status: StatusPassed},
+		{Check: "SSO", Target: `platform/"sso"`, Status: StatusFailed, Message: "invalid SSO configuration"},
+	}, StatusFailed, finishedAt))
+
+	want := `# TYPE privatecloud_cli_check_status gauge
+# HELP privatecloud_cli_check_status Whether the status of the check in the last run is the one of the status label.
+privatecloud_cli_check_status{check="storage class",target="",status="passed"} 1
+privatecloud_cli_check_status{check="storage class",target="",status="warning"} 0
+privatecloud_cli_check_status{check="storage class",target="",status="failed"} 0
+privatecloud_cli_check_status{check="storage class",target="",status="skipped"} 0
+privatecloud_cli_check_status{check="SSO",target="platform/\"sso\"",status="passed"} 0
+privatecloud_cli_check_status{check="SSO",target="platform/\"sso\"",status="warning"} 0
+privatecloud_cli_check_status{check="SSO",target="platform/\"sso\"",status="failed"} 1
+privatecloud_cli_check_status{check="SSO",target="platform/\"sso\"",status="skipped"} 0
+# TYPE privatecloud_cli_status gauge
+# HELP privatecloud_cli_status Whether the overall status of the last run is the one of the status label.
+privatecloud_cli_status{status="passed"} 0
+privatecloud_cli_status{status="warning"} 0
+privatecloud_cli_status{status="failed"} 1
+privatecloud_cli_status{status="skipped"} 0
+# TYPE privatecloud_cli_last_run_timestamp_seconds gauge
+# UNIT privatecloud_cli_last_run_timestamp_seconds seconds
+# HELP privatecloud_cli_last_run_timestamp_seconds The time at which the last run finished.
+privatecloud_cli_last_run_timestamp_seconds 1767323105
+# EOF
+`
+
+	assert.Equal(t, want, buf.String())
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+
+	assert.Equal(t, "# EOF", lines[len(lines)-1])
+
+	for _, line := range lines {
+		if strings.HasPrefix(line, "# ") {
+			continue
+		}
+
+		assert.Regexp(t, sampleRegex, line)
+	}
+}
+
+// TestWriteOpenMetrics_failingWriter is a test that tests that the WriteOpenMetrics function returns an error if the metrics cannot be written.
+func TestWriteOpenMetrics_failingWriter(t *testing.T) {
+	err := WriteOpenMetrics(failingWriter{}, []Result{{Check: "TLS", Status: StatusPassed}}, StatusPassed, time.Now())
+
+	assert.ErrorIs(t, err, errFailedToWriteReport)
+	assert.ErrorIs(t, err, errFailingWriter)
+}
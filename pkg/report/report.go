@@ -0,0 +1,297 @@
+// Package report is the package that contains the report of the results of the checks.
+package report
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/clock"
+	pkgerrors "github.com/AlphaSense-Engineering/privatecloud-cli/pkg/errors"
+	"github.com/r3labs/diff/v3"
+	"go.uber.org/multierr"
+)
+
+var (
+	// ErrWriterClosed is the error that is returned when a result is written after the summary.
+	ErrWriterClosed = errors.New("report writer is closed")
+
+	// errFailedToWriteReport is the error that is returned when an entry of the report cannot be written.
+	errFailedToWriteReport = errors.New("failed to write report")
+)
+
+// Status is the type of the status of a check.
+type Status string
+
+const (
+	// StatusPassed is the status of a check that passed.
+	StatusPassed Status = "passed"
+	// StatusWarning is the status of a check that passed with a warning.
+	StatusWarning Status = "warning"
+	// StatusFailed is the status of a check that failed.
+	StatusFailed Status = "failed"
+	// StatusSkipped is the status of a check that was not run.
+	StatusSkipped Status = "skipped"
+)
+
+// SkipReason is the type of the reason why a check was not run.
+type SkipReason string
+
+const (
+	// SkipReasonUserRequested is the reason of a check that was not run as the flags or the environment configuration ask not to run it.
+	SkipReasonUserRequested SkipReason = "user-requested"
+	// SkipReasonNotApplicable is the reason of a check that was not run as it does not apply to the cloud provider.
+	SkipReasonNotApplicable SkipReason = "not-applicable-for-provider"
+	// SkipReasonPrerequisiteMissing is the reason of a check that was not run as what it requires, such as an earlier check or the credentials, is
+	// missing.
+	SkipReasonPrerequisiteMissing SkipReason = "prerequisite-missing"
+	// SkipReasonCancelled is the reason of a check that was not completed as it was cancelled once another check that ran at the same time failed.
+	SkipReasonCancelled SkipReason = "cancelled"
+)
+
+// Kind is the type of the kind of an entry of the report, which tells the results apart from the summary in the stream.
+type Kind string
+
+const (
+	// KindResult is the kind of the entry that contains the result of a check.
+	KindResult Kind = "result"
+	// KindSummary is the kind of the entry that contains the summary, written last.
+	KindSummary Kind = "summary"
+)
+
+// Result is the type that contains the result of a check.
+type Result struct {
+	// Kind is the kind of the entry, set by the writer.
+	Kind Kind `json:"kind"`
+	// Check is the name of the check.
+	Check string `json:"check"`
+	// Target is what the check was run against, such as a database or a service account, empty if the check has a single target.
+	Target string `json:"target,omitempty"`
+	// Status is the status of the check.
+	Status Status `json:"status"`
+	// Message is the error, the warning or the reason for skipping, empty if the check passed.
+	Message string `json:"message,omitempty"`
+	// SkipReason is the reason why the check was not run, empty unless it was skipped.
+	SkipReason SkipReason `json:"skipReason,omitempty"`
+	// Changes is the difference between the expected and the actual policy documents, with the path, the from and to values, and the type of each
+	// change, empty unless the check failed on a mismatch of them.
+	Changes diff.Changelog `json:"changes,omitempty"`
+	// Time is the time at which the check finished, set by the collector, zero if the result is streamed.
+	Time time.Time `json:"time,omitzero"`
+}
+
+// NewFailed is the function that returns the result of a check that failed with the error, with the changes if the error carries a changelog, so that
+// the tools reconciling the drift do not have to parse them out of the message.
+func NewFailed(check string, target string, err error) Result {
+	r := Result{Check: check, Target: target, Status: StatusFailed, Message: err.Error()}
+
+	var changelogErr *pkgerrors.ErrWithChangelog
+
+	if errors.As(err, &changelogErr) {
+		r.Changes = changelogErr.Changelog()
+	}
+
+	return r
+}
+
+// NewResult is the function that returns the result of a check that failed with the error, as NewFailed does, or of a check that passed if the error is
+// nil.
+func NewResult(check string, target string, err error) Result {
+	if err != nil {
+		return NewFailed(check, target, err)
+	}
+
+	return Result{Check: check, Target: target, Status: StatusPassed}
+}
+
+// Summary is the type that contains the number of the results of each status, written after all of the results.
+type Summary struct {
+	// Kind is the kind of the entry, set by the writer.
+	Kind Kind `json:"kind"`
+	// Total is the number of the results.
+	Total int `json:"total"`
+	// Passed is the number of the checks that passed.
+	Passed int `json:"passed"`
+	// Warning is the number of the checks that passed with a warning.
+	Warning int `json:"warning"`
+	// Failed is the number of the checks that failed.
+	Failed int `json:"failed"`
+	// Skipped is the number of the checks that were not run.
+	Skipped int `json:"skipped"`
+}
+
+// add is the function that counts the result in the summary.
+func (s *Summary) add(status Status) {
+	s.Total++
+
+	switch status {
+	case StatusPassed:
+		s.Passed++
+	case StatusWarning:
+		s.Warning++
+	case StatusFailed:
+		s.Failed++
+	case StatusSkipped:
+		s.Skipped++
+	}
+}
+
+// Summarize is the function that returns the summary of the results.
+func Summarize(results []Result) Summary {
+	s := Summary{Kind: KindSummary}
+
+	for _, r := range results {
+		s.add(r.Status)
+	}
+
+	return s
+}
+
+// SkippedCheck is the type that contains a check that was not run, along with the reason why.
+type SkippedCheck struct {
+	// Check is the name of the check.
+	Check string `json:"check"`
+	// Target is what the check would have been run against, empty if the check has a single target.
+	Target string `json:"target,omitempty"`
+	// Reason is the reason why the check was not run, empty if the results are of a Pod older than the reasons.
+	Reason SkipReason `json:"reason,omitempty"`
+	// Message is the description of the reason why the check was not run.
+	Message string `json:"message,omitempty"`
+}
+
+// Skipped is the function that returns the checks of the results that were not run, in the order of the results, or an empty list if every check was run.
+func Skipped(results []Result) []SkippedCheck {
+	skipped := []SkippedCheck{}
+
+	for _, r := range results {
+		if r.Status != StatusSkipped {
+			continue
+		}
+
+		skipped = append(skipped, SkippedCheck{Check: r.Check, Target: r.Target, Reason: r.SkipReason, Message: r.Message})
+	}
+
+	return skipped
+}
+
+// StreamWriter is the type that writes the report as newline-delimited JSON, one line per result as soon as it is written, followed by the summary.
+//
+// Only the counts of the summary are kept in memory, so the memory it uses does not grow with the number of the results. It is safe for concurrent use.
+type StreamWriter struct {
+	// mu is the mutex that serializes the writes.
+	mu sync.Mutex
+	// encoder is the JSON encoder, which terminates each entry with a newline.
+	encoder *json.Encoder
+	// summary is the summary of the results written so far.
+	summary Summary
+	// closed is whether the summary is written.
+	closed bool
+}
+
+// Write is the function that writes the result to the stream.
+//
+// It returns an error if the result cannot be written, or if the summary is already written.
+func (w *StreamWriter) Write(r Result) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return ErrWriterClosed
+	}
+
+	r.Kind = KindResult
+
+	if err := w.encoder.Encode(r); err != nil {
+		return multierr.Combine(errFailedToWriteReport, err)
+	}
+
+	w.summary.add(r.Status)
+
+	return nil
+}
+
+// Close is the function that writes the summary to the stream, after which no more results can be written.
+//
+// It returns the summary, or an error if the summary cannot be written or is already written.
+func (w *StreamWriter) Close() (Summary, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return w.summary, ErrWriterClosed
+	}
+
+	w.closed = true
+
+	if err := w.encoder.Encode(w.summary); err != nil {
+		return w.summary, multierr.Combine(errFailedToWriteReport, err)
+	}
+
+	return w.summary, nil
+}
+
+// NewStreamWriter is the function that creates a new StreamWriter that writes to the writer.
+func NewStreamWriter(out io.Writer) *StreamWriter {
+	return &StreamWriter{
+		encoder: json.NewEncoder(out),
+		summary: Summary{Kind: KindSummary},
+	}
+}
+
+// Collector is the type that keeps the results in memory, in the order they are added, so that they can be reported together once all of the checks ran.
+//
+// A nil Collector drops the results, so that the checks can add them whether or not they are reported. It is safe for concurrent use.
+type Collector struct {
+	// mu is the mutex that serializes the additions.
+	mu sync.Mutex
+	// clock is the clock that the results are timestamped with.
+	clock clock.Clock
+	// results is the list of the results added so far.
+	results []Result
+	// onAdd is the function that is called with each of the results as it is added, or nil.
+	onAdd func(Result)
+}
+
+// Add is the function that adds the result to the collector, timestamping it with the current time in UTC.
+func (c *Collector) Add(r Result) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	r.Kind = KindResult
+	r.Time = c.clock.Now().UTC()
+
+	c.results = append(c.results, r)
+
+	if c.onAdd != nil {
+		c.onAdd(r)
+	}
+}
+
+// Results is the function that returns a copy of the results added so far.
+func (c *Collector) Results() []Result {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]Result(nil), c.results...)
+}
+
+// NewCollector is the function that creates a new Collector that timestamps the results with the clock.
+func NewCollector(clk clock.Clock) *Collector {
+	return &Collector{clock: clk}
+}
+
+// NewStreamingCollector is the function that creates a new Collector that timestamps the results with the clock and calls the function with each of them
+// as it is added, in the order they are kept, so that they can be reported as soon as each check finishes.
+func NewStreamingCollector(clk clock.Clock, onAdd func(Result)) *Collector {
+	return &Collector{clock: clk, onAdd: onAdd}
+}
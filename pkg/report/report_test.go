@@ -0,0 +1,235 @@
+// Package report is the package that contains the report of the results of the checks.
+package report
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/clock"
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
+	pkgerrors "github.com/AlphaSense-Engineering/privatecloud-cli/pkg/errors"
+	"github.com/r3labs/diff/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// errFailingWriter is the error that the failing writer returns.
+var errFailingWriter = errors.New("disk full")
+
+// failingWriter is the type that implements the io.Writer interface, failing every write.
+type failingWriter struct{}
+
+// Write is the function that fails the write.
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errFailingWriter
+}
+
+// lines is a function that splits the stream into its lines, decoding each of them into a map.
+func lines(t *testing.T, stream string) []map[string]any {
+	t.Helper()
+
+	var got []map[string]any
+
+	scanner := bufio.NewScanner(strings.NewReader(stream))
+
+	for scanner.Scan() {
+		var entry map[string]any
+
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry), "line %q is not a JSON object", scanner.Text())
+
+		got = append(got, entry)
+	}
+
+	require.NoError(t, scanner.Err())
+
+	return got
+}
+
+// TestStreamWriter is a test that tests that the StreamWriter writes each result as a line as soon as it is written, followed by the summary.
+func TestStreamWriter(t *testing.T) {
+	var buf bytes.Buffer
+
+	w := NewStreamWriter(&buf)
+
+	require.NoError(t, w.Write(Result{Check: "MySQL", Target: "mysql/default-creds", Status: StatusPassed}))
+
+	assert.Equal(t, []map[string]any{
+		{"kind": "result", "check": "MySQL", "target": "mysql/default-creds", "status": "passed"},
+	}, lines(t, buf.String()))
+
+	require.NoError(t, w.Write(Result{Check: "Node groups", Status: StatusWarning, Message: "no GPU node group"}))
+	require.NoError(t, w.Write(Result{Check: "SSO", Status: StatusFailed, Message: "secret not found"}))
+	require.NoError(t, w.Write(Result{Check: "Crossplane role", Status: StatusSkipped, Message: "role only"}))
+
+	summary, err := w.Close()
+	require.NoError(t, err)
+
+	want := Summary{Kind: KindSummary, Total: 4, Passed: 1, Warning: 1, Failed: 1, Skipped: 1}
+
+	assert.Equal(t, want, summary)
+
+	got := lines(t, buf.String())
+
+	require.Len(t, got, 5)
+
+	assert.Equal(t, map[string]any{"kind": "result", "check": "SSO", "status": "failed", "message": "secret not found"}, got[2])
+	assert.Equal(t, map[string]any{
+		"kind":    "summary",
+		"total":   float64(4),
+		"passed":  float64(1),
+		"warning": float64(1),
+		"failed":  float64(1),
+		"skipped": float64(1),
+	}, got[4])
+}
+
+// TestNewFailed is a test that tests that the NewFailed function includes the changelog of the error in the result, whether it is wrapped or not.
+func TestNewFailed(t *testing.T) {
+	changelog := diff.Changelog{{Type: diff.UPDATE, Path: []string{"Statement", "0", "Effect"}, From: "Allow", To: "Deny"}}
+
+	testCases := []struct {
+		name        string
+		err         error
+		wantChanges diff.Changelog
+	}{
+		{name: "Plain error", err: errFailingWriter},
+		{name: "Changelog", err: pkgerrors.NewErrWithChangelog(errFailingWriter, changelog), wantChanges: changelog},
+		{name: "Wrapped changelog", err: fmt.Errorf("wrapped: %w", pkgerrors.NewErrWithChangelog(errFailingWriter, changelog)), wantChanges: changelog},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := NewFailed("AWS Crossplane role", constant.EmptyString, tc.err)
+
+			assert.Equal(t, StatusFailed, got.Status)
+			assert.Equal(t, tc.err.Error(), got.Message)
+			assert.Equal(t, tc.wantChanges, got.Changes)
+		})
+	}
+}
+
+// TestStreamWriter_closed is a test that tests that the StreamWriter rejects the results and the summary written after the summary.
+func TestStreamWriter_closed(t *testing.T) {
+	var buf bytes.Buffer
+
+	w := NewStreamWriter(&buf)
+
+	_, err := w.Close()
+	require.NoError(t, err)
+
+	require.ErrorIs(t, w.Write(Result{Check: "TLS", Status: StatusPassed}), ErrWriterClosed)
+
+	_, err = w.Close()
+	require.ErrorIs(t, err, ErrWriterClosed)
+
+	assert.Len(t, lines(t, buf.String()), 1)
+}
+
+// TestStreamWriter_failingWriter is a test that tests that the StreamWriter returns the errors of the underlying writer without counting the result.
+func TestStreamWriter_failingWriter(t *testing.T) {
+	w := NewStreamWriter(failingWriter{})
+
+	require.ErrorIs(t, w.Write(Result{Check: "TLS", Status: StatusPassed}), errFailingWriter)
+
+	summary, err := w.Close()
+	require.ErrorIs(t, err, errFailingWriter)
+
+	assert.Equal(t, 0, summary.Total)
+}
+
+// TestStreamWriter_concurrent is a test that tests that the StreamWriter keeps each line intact when the results are written concurrently.
+func TestStreamWriter_concurrent(t *testing.T) {
+	// count is the number of the results written concurrently.
+	const count = 100
+
+	var buf bytes.Buffer
+
+	w := NewStreamWriter(&buf)
+
+	var wg sync.WaitGroup
+
+	for i := range count {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			assert.NoError(t, w.Write(Result{Check: "Service account", Target: strconv.Itoa(i), Status: StatusPassed}))
+		}()
+	}
+
+	wg.Wait()
+
+	summary, err := w.Close()
+	require.NoError(t, err)
+
+	assert.Equal(t, count, summary.Passed)
+	assert.Len(t, lines(t, buf.String()), count+1)
+}
+
+// TestCollector is a test that tests that the Collector keeps the results in order, timestamped, and that a nil Collector drops them.
+func TestCollector(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	c := NewCollector(clock.NewFake(now))
+
+	c.Add(Result{Check: "storage class", Status: StatusPassed})
+	c.Add(Result{Check: "node groups", Status: StatusWarning, Message: "no nodes with GPU label found"})
+
+	assert.Equal(t, []Result{
+		{Kind: KindResult, Check: "storage class", Status: StatusPassed, Time: now},
+		{Kind: KindResult, Check: "node groups", Status: StatusWarning, Message: "no nodes with GPU label found", Time: now},
+	}, c.Results())
+	assert.Equal(t, Summary{Kind: KindSummary, Total: 2, Passed: 1, Warning: 1}, Summarize(c.Results()))
+
+	var nilCollector *Collector
+
+	nilCollector.Add(Result{Check: "storage class", Status: StatusPassed})
+
+	assert.Empty(t, nilCollector.Results())
+}
+
+// TestNewStreamingCollector is a test that tests that the streaming Collector calls the function with each result, timestamped, as it is added.
+func TestNewStreamingCollector(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	var streamed []Result
+
+	c := NewStreamingCollector(clock.NewFake(now), func(r Result) {
+		streamed = append(streamed, r)
+	})
+
+	c.Add(Result{Check: "storage class", Status: StatusPassed})
+
+	assert.Equal(t, []Result{{Kind: KindResult, Check: "storage class", Status: StatusPassed, Time: now}}, streamed)
+
+	c.Add(Result{Check: "node groups", Status: StatusFailed})
+
+	assert.Equal(t, c.Results(), streamed)
+}
+
+// TestSkipped is a test that tests that the Skipped function lists the checks that were not run, in order, along with the reason why.
+func TestSkipped(t *testing.T) {
+	results := []Result{
+		{Check: "storage class", Status: StatusPassed},
+		{Check: "node groups", Status: StatusSkipped, Message: "the deployment does not require GPU nodes", SkipReason: SkipReasonUserRequested},
+		{Check: "MySQL", Target: "primary", Status: StatusFailed, Message: "access denied"},
+		{Check: "TLS", Status: StatusSkipped, Message: "not run as an earlier check failed", SkipReason: SkipReasonPrerequisiteMissing},
+		{Check: "OIDC URL", Status: StatusSkipped, Message: "not run on gcp", SkipReason: SkipReasonNotApplicable},
+	}
+
+	assert.Equal(t, []SkippedCheck{
+		{Check: "node groups", Reason: SkipReasonUserRequested, Message: "the deployment does not require GPU nodes"},
+		{Check: "TLS", Reason: SkipReasonPrerequisiteMissing, Message: "not run as an earlier check failed"},
+		{Check: "OIDC URL", Reason: SkipReasonNotApplicable, Message: "not run on gcp"},
+	}, Skipped(results))
+	assert.Equal(t, []SkippedCheck{}, Skipped(results[:1]))
+}
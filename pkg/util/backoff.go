@@ -0,0 +1,181 @@
+// Package util is the package that contains the utility functions.
+package util
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/clock"
+	"go.uber.org/multierr"
+)
+
+var (
+	// ErrInvalidRetries is the error that is returned when the number of retries is negative.
+	ErrInvalidRetries = errors.New("invalid number of retries: must not be negative")
+
+	// ErrInvalidRetryBaseDelay is the error that is returned when the base delay between the retries is not positive.
+	ErrInvalidRetryBaseDelay = errors.New("invalid retry base delay: must be positive")
+
+	// ErrInvalidRetryBudget is the error that is returned when the retry budget is negative.
+	ErrInvalidRetryBudget = errors.New("invalid retry budget: must not be negative")
+
+	// ErrRetryBudgetExhausted is the error that is returned when the retry budget has no room left for the next retry.
+	ErrRetryBudgetExhausted = errors.New("retry budget exhausted")
+)
+
+const (
+	// defaultBackoffFactor is the default factor by which the delay between the retries grows.
+	defaultBackoffFactor = 2
+
+	// defaultBackoffMaxDelay is the default maximum delay between the retries.
+	defaultBackoffMaxDelay = 30 * time.Second
+
+	// defaultBackoffJitter is the default fraction of the delay between the retries that is randomized.
+	defaultBackoffJitter = 0.2
+)
+
+// BackoffPolicy is the type that describes how many times and how long apart an operation is retried.
+type BackoffPolicy struct {
+	// Retries is the number of the retries after the first attempt.
+	Retries int
+	// BaseDelay is the delay before the first retry.
+	BaseDelay time.Duration
+	// Factor is the factor by which the delay grows with each retry.
+	Factor float64
+	// MaxDelay is the maximum delay between the retries.
+	MaxDelay time.Duration
+	// Jitter is the fraction of the delay, between 0 and 1, by which the delay is randomly shortened, so that concurrent retries spread out.
+	Jitter float64
+}
+
+// NewBackoffPolicy returns a BackoffPolicy with the given number of retries and base delay and the default factor, maximum delay and jitter.
+func NewBackoffPolicy(retries int, baseDelay time.Duration) (BackoffPolicy, error) {
+	if retries < 0 {
+		return BackoffPolicy{}, ErrInvalidRetries
+	}
+
+	if baseDelay <= 0 {
+		return BackoffPolicy{}, ErrInvalidRetryBaseDelay
+	}
+
+	return BackoffPolicy{
+		Retries:   retries,
+		BaseDelay: baseDelay,
+		Factor:    defaultBackoffFactor,
+		MaxDelay:  defaultBackoffMaxDelay,
+		Jitter:    defaultBackoffJitter,
+	}, nil
+}
+
+// Backoff returns the delays before each of the retries of the policy.
+//
+// The delay before the retry i is BaseDelay * Factor^i, capped at MaxDelay, then shortened by up to Jitter of itself, scaled by the value returned by
+// random, which is expected to be in the [0, 1) range.
+func Backoff(policy BackoffPolicy, random func() float64) []time.Duration {
+	delays := make([]time.Duration, policy.Retries)
+
+	for i := range delays {
+		delay := math.Min(float64(policy.BaseDelay)*math.Pow(policy.Factor, float64(i)), float64(policy.MaxDelay))
+
+		delays[i] = time.Duration(delay * (1 - policy.Jitter*random()))
+	}
+
+	return delays
+}
+
+// Jitter returns the interval lengthened or shortened by up to jitter of itself, scaled by the value returned by random, which is expected to be in the [0, 1)
+// range, so that the intervals of concurrent polls spread out.
+//
+// A jitter of 0 returns the interval as is.
+func Jitter(interval time.Duration, jitter float64, random func() float64) time.Duration {
+	if jitter == 0 {
+		return interval
+	}
+
+	return time.Duration(float64(interval) * (1 + jitter*(2*random()-1)))
+}
+
+// RetryBudget is the type that bounds the total time that all of the retries of a run sleep for, so that the retries of the individual operations do not
+// compound into a very long run. It is safe for concurrent use, and a nil RetryBudget is unlimited.
+type RetryBudget struct {
+	// mu is the mutex that guards the remaining time.
+	mu sync.Mutex
+	// unlimited is whether the retries can sleep for any time in total.
+	unlimited bool
+	// remaining is the time that the retries can still sleep for.
+	remaining time.Duration
+}
+
+// NewRetryBudget returns a RetryBudget that lets all of the retries sleep for up to maxTotal in total, or an unlimited one if maxTotal is zero.
+func NewRetryBudget(maxTotal time.Duration) (*RetryBudget, error) {
+	if maxTotal < 0 {
+		return nil, ErrInvalidRetryBudget
+	}
+
+	return &RetryBudget{unlimited: maxTotal == 0, remaining: maxTotal}, nil
+}
+
+// take reserves the delay from the budget, returning false and reserving nothing if the budget has less than the delay left.
+func (b *RetryBudget) take(delay time.Duration) bool {
+	if b == nil || b.unlimited {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if delay > b.remaining {
+		return false
+	}
+
+	b.remaining -= delay
+
+	return true
+}
+
+// Remaining returns the time that the retries can still sleep for, or -1 if the budget is unlimited.
+func (b *RetryBudget) Remaining() time.Duration {
+	if b == nil || b.unlimited {
+		return -1
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.remaining
+}
+
+// RetryWithBackoff calls fn until it succeeds, returns an error for which isRetryable returns false, or the retries of the policy are exhausted, sleeping
+// for the delays returned by Backoff in between on the clock.
+//
+// Each delay is taken from the budget, which is shared by all of the retries of the run; once the budget has no room left for the next delay, the retries
+// stop early. A nil budget is unlimited.
+//
+// It returns nil on success, or the last error returned by fn otherwise, combined with the context error if the context is done while sleeping, or with
+// ErrRetryBudgetExhausted if the budget stopped the retries.
+func RetryWithBackoff(
+	ctx context.Context, clk clock.Clock, policy BackoffPolicy, budget *RetryBudget, isRetryable func(error) bool, fn func(context.Context) error,
+) error {
+	delays := Backoff(policy, rand.Float64)
+
+	for i := 0; ; i++ {
+		err := fn(ctx)
+		if err == nil || i == len(delays) || !isRetryable(err) {
+			return err
+		}
+
+		if !budget.take(delays[i]) {
+			return multierr.Combine(ErrRetryBudgetExhausted, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return multierr.Combine(ctx.Err(), err)
+		case <-clk.After(delays[i]):
+		}
+	}
+}
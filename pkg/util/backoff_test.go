@@ -0,0 +1,297 @@
+// Package util is the package that contains the utility functions.
+package util
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNewBackoffPolicy is a test that tests that the NewBackoffPolicy function validates the number of retries and the base delay.
+func TestNewBackoffPolicy(t *testing.T) {
+	testCases := []struct {
+		name      string
+		retries   int
+		baseDelay time.Duration
+		wantErr   error
+	}{
+		{name: "Valid", retries: 3, baseDelay: time.Second},
+		{name: "No retries", retries: 0, baseDelay: time.Second},
+		{name: "Negative retries", retries: -1, baseDelay: time.Second, wantErr: ErrInvalidRetries},
+		{name: "Zero base delay", retries: 3, wantErr: ErrInvalidRetryBaseDelay},
+		{name: "Negative base delay", retries: 3, baseDelay: -time.Second, wantErr: ErrInvalidRetryBaseDelay},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			policy, err := NewBackoffPolicy(tc.retries, tc.baseDelay)
+
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, BackoffPolicy{
+				Retries:   tc.retries,
+				BaseDelay: tc.baseDelay,
+				Factor:    defaultBackoffFactor,
+				MaxDelay:  defaultBackoffMaxDelay,
+				Jitter:    defaultBackoffJitter,
+			}, policy)
+		})
+	}
+}
+
+// TestBackoff is a test that tests the sequence of the delays returned by the Backoff function.
+func TestBackoff(t *testing.T) {
+	policy := BackoffPolicy{Retries: 6, BaseDelay: time.Second, Factor: 2, MaxDelay: 10 * time.Second, Jitter: 0.5}
+
+	testCases := []struct {
+		name   string
+		policy BackoffPolicy
+		random float64
+		want   []time.Duration
+	}{
+		{
+			name:   "No jitter drawn",
+			policy: policy,
+			random: 0,
+			want:   []time.Duration{1 * time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second},
+		},
+		{
+			name:   "Half of the jitter drawn",
+			policy: policy,
+			random: 0.5,
+			want: []time.Duration{
+				750 * time.Millisecond,
+				1500 * time.Millisecond,
+				3 * time.Second,
+				6 * time.Second,
+				7500 * time.Millisecond,
+				7500 * time.Millisecond,
+			},
+		},
+		{
+			name:   "Constant delay",
+			policy: BackoffPolicy{Retries: 3, BaseDelay: time.Second, Factor: 1, MaxDelay: time.Minute},
+			random: 0.9,
+			want:   []time.Duration{time.Second, time.Second, time.Second},
+		},
+		{
+			name:   "No retries",
+			policy: BackoffPolicy{BaseDelay: time.Second, Factor: 2, MaxDelay: time.Minute},
+			want:   []time.Duration{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, Backoff(tc.policy, func() float64 { return tc.random }))
+		})
+	}
+}
+
+// TestBackoff_jitterBounds is a test that tests that the jittered delays stay between the delays without jitter and shortened by the whole jitter.
+func TestBackoff_jitterBounds(t *testing.T) {
+	policy, err := NewBackoffPolicy(5, 100*time.Millisecond)
+	require.NoError(t, err)
+
+	upper := Backoff(policy, func() float64 { return 0 })
+	lower := Backoff(policy, func() float64 { return 1 })
+
+	for range 100 {
+		for i, delay := range Backoff(policy, rand.Float64) {
+			assert.LessOrEqual(t, delay, upper[i])
+			assert.GreaterOrEqual(t, delay, lower[i])
+		}
+	}
+}
+
+// TestJitter is a test that tests that the Jitter function keeps the interval within the jitter of itself, and leaves it as is when the jitter is disabled.
+func TestJitter(t *testing.T) {
+	// interval is the interval used in the test.
+	const interval = 30 * time.Second
+
+	assert.Equal(t, 27*time.Second, Jitter(interval, 0.1, func() float64 { return 0 }))
+	assert.Equal(t, interval, Jitter(interval, 0.1, func() float64 { return 0.5 }))
+	assert.Equal(t, interval, Jitter(interval, 0, rand.Float64))
+
+	for range 1000 {
+		d := Jitter(interval, 0.1, rand.Float64)
+
+		assert.GreaterOrEqual(t, d, 27*time.Second)
+		assert.Less(t, d, 33*time.Second)
+	}
+}
+
+// TestRetryWithBackoff is a test that tests that the RetryWithBackoff function retries only the retryable errors, and at most the number of retries of the
+// policy, sleeping on the clock before each retry.
+func TestRetryWithBackoff(t *testing.T) {
+	var (
+		// errRetryable is the error that is retried in the test.
+		errRetryable = errors.New("retryable")
+
+		// errPermanent is the error that is not retried in the test.
+		errPermanent = errors.New("permanent")
+	)
+
+	testCases := []struct {
+		name         string
+		errs         []error
+		wantAttempts int
+		wantSleeps   []time.Duration
+		wantErr      error
+	}{
+		{name: "First attempt succeeds", errs: []error{nil}, wantAttempts: 1},
+		{name: "Succeeds after retries", errs: []error{errRetryable, errRetryable, nil}, wantAttempts: 3, wantSleeps: []time.Duration{time.Second, 2 * time.Second}},
+		{
+			name:         "Permanent error",
+			errs:         []error{errRetryable, errPermanent, nil},
+			wantAttempts: 2,
+			wantSleeps:   []time.Duration{time.Second},
+			wantErr:      errPermanent,
+		},
+		{
+			name:         "Retries exhausted",
+			errs:         []error{errRetryable, errRetryable, errRetryable, errRetryable, nil},
+			wantAttempts: 4,
+			wantSleeps:   []time.Duration{time.Second, 2 * time.Second, 4 * time.Second},
+			wantErr:      errRetryable,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			policy, err := NewBackoffPolicy(3, time.Second)
+			require.NoError(t, err)
+
+			policy.Jitter = 0
+
+			clk := clock.NewFake(time.Time{})
+
+			var attempts int
+
+			err = RetryWithBackoff(context.Background(), clk, policy, nil, func(err error) bool { return errors.Is(err, errRetryable) }, func(context.Context) error {
+				attempts++
+
+				return tc.errs[attempts-1]
+			})
+
+			assert.Equal(t, tc.wantAttempts, attempts)
+			assert.Equal(t, tc.wantSleeps, clk.Sleeps())
+
+			if tc.wantErr == nil {
+				require.NoError(t, err)
+
+				return
+			}
+
+			require.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+// TestRetryWithBackoff_contextCancelled is a test that tests that the RetryWithBackoff function stops sleeping as soon as the context is done.
+func TestRetryWithBackoff_contextCancelled(t *testing.T) {
+	// errRetryable is the error that is retried in the test.
+	errRetryable := errors.New("retryable")
+
+	policy, err := NewBackoffPolicy(3, time.Hour)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	var attempts int
+
+	start := time.Now()
+
+	err = RetryWithBackoff(ctx, clock.Real{}, policy, nil, func(error) bool { return true }, func(context.Context) error {
+		attempts++
+
+		return errRetryable
+	})
+
+	assert.Less(t, time.Since(start), time.Minute)
+	assert.Equal(t, 1, attempts)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.ErrorIs(t, err, errRetryable)
+}
+
+// TestNewRetryBudget is a test that tests that the NewRetryBudget function validates the maximum total time and that a zero one is unlimited.
+func TestNewRetryBudget(t *testing.T) {
+	_, err := NewRetryBudget(-time.Second)
+	require.ErrorIs(t, err, ErrInvalidRetryBudget)
+
+	budget, err := NewRetryBudget(0)
+	require.NoError(t, err)
+	assert.Equal(t, time.Duration(-1), budget.Remaining())
+
+	budget, err = NewRetryBudget(time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, budget.Remaining())
+
+	assert.Equal(t, time.Duration(-1), (*RetryBudget)(nil).Remaining())
+}
+
+// TestRetryWithBackoff_budget is a test that tests that the budget shared by the calls of the RetryWithBackoff function caps the total time that their
+// retries sleep for.
+func TestRetryWithBackoff_budget(t *testing.T) {
+	// errRetryable is the error that is retried in the test.
+	errRetryable := errors.New("retryable")
+
+	policy, err := NewBackoffPolicy(3, time.Second)
+	require.NoError(t, err)
+
+	policy.Jitter = 0
+
+	budget, err := NewRetryBudget(10 * time.Second)
+	require.NoError(t, err)
+
+	clk := clock.NewFake(time.Time{})
+
+	var attempts int
+
+	retry := func() error {
+		return RetryWithBackoff(context.Background(), clk, policy, budget, func(error) bool { return true }, func(context.Context) error {
+			attempts++
+
+			return errRetryable
+		})
+	}
+
+	// The first operation sleeps for 1s, 2s and 4s, exhausting its retries with 3s of the budget left.
+	err = retry()
+	require.ErrorIs(t, err, errRetryable)
+	require.NotErrorIs(t, err, ErrRetryBudgetExhausted)
+	assert.Equal(t, 4, attempts)
+	assert.Equal(t, 3*time.Second, budget.Remaining())
+
+	// The second operation sleeps for 1s and 2s, then has no room left for the 4s.
+	err = retry()
+	require.ErrorIs(t, err, errRetryable)
+	require.ErrorIs(t, err, ErrRetryBudgetExhausted)
+	assert.Equal(t, 7, attempts)
+	assert.Equal(t, time.Duration(0), budget.Remaining())
+
+	// The third operation is not retried at all.
+	err = retry()
+	require.ErrorIs(t, err, ErrRetryBudgetExhausted)
+	assert.Equal(t, 8, attempts)
+
+	var total time.Duration
+
+	for _, d := range clk.Sleeps() {
+		total += d
+	}
+
+	assert.Equal(t, 10*time.Second, total)
+}
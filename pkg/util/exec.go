@@ -2,18 +2,45 @@ package util
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"os/exec"
+	"strings"
 
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
+	pkgerrors "github.com/AlphaSense-Engineering/privatecloud-cli/pkg/errors"
 	"github.com/charmbracelet/log"
+	"go.uber.org/multierr"
 )
 
+// stderrTailLines is the maximum number of the last lines of the standard error output that are included in the error of a failed command.
+const stderrTailLines = 10
+
+// tail is a function that returns the last n non-empty lines of the string, joined with "; ".
+func tail(s string, n int) string {
+	// separator is the separator between the lines of the tail.
+	const separator = "; "
+
+	lines := strings.FieldsFunc(s, func(r rune) bool { return r == '\n' })
+
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+
+	return strings.Join(lines, separator)
+}
+
 // Exec is the function that executes a command.
 func Exec(l *log.Logger, outBuf *bytes.Buffer, bin string, args ...string) error {
+	return ExecContext(context.Background(), l, outBuf, bin, args...)
+}
+
+// ExecContext is the function that executes a command, killing it if the context is done before the command completes.
+func ExecContext(ctx context.Context, l *log.Logger, outBuf *bytes.Buffer, bin string, args ...string) error {
 	// logMsgRunningCommand is the message that is logged when running a command.
 	const logMsgRunningCommand = "running command: %s"
 
-	cmd := exec.Command(bin, args...)
+	cmd := exec.CommandContext(ctx, bin, args...)
 
 	var writer io.Writer
 
@@ -25,9 +52,25 @@ func Exec(l *log.Logger, outBuf *bytes.Buffer, bin string, args ...string) error
 
 	cmd.Stdout = writer
 
-	cmd.Stderr = &LogErrorWriter{Logger: l}
+	var errBuf bytes.Buffer
+
+	cmd.Stderr = io.MultiWriter(&LogErrorWriter{Logger: l}, &errBuf)
 
 	l.Debugf(logMsgRunningCommand, cmd.String())
 
-	return cmd.Run()
+	if err := cmd.Run(); err != nil {
+		if stderrTail := tail(errBuf.String(), stderrTailLines); stderrTail != constant.EmptyString {
+			err = pkgerrors.NewCommandFailed(err, stderrTail)
+		}
+
+		// If the command was killed because the context is done, surface the context error so that callers can tell a timeout or a cancellation
+		// apart from a failure of the command itself.
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return multierr.Combine(ctxErr, err)
+		}
+
+		return err
+	}
+
+	return nil
 }
@@ -0,0 +1,67 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestExecContext is a test that tests the ExecContext function.
+func TestExecContext(t *testing.T) {
+	logger := log.New(io.Discard)
+
+	t.Run("Successful command", func(t *testing.T) {
+		var outBuf bytes.Buffer
+
+		err := ExecContext(context.Background(), logger, &outBuf, "echo", "hello")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "hello\n", outBuf.String())
+	})
+
+	t.Run("Failing command writing to stderr", func(t *testing.T) {
+		err := ExecContext(context.Background(), logger, nil, "sh", "-c", "echo first >&2; echo 'error: validation failed' >&2; exit 1")
+
+		assert.ErrorContains(t, err, "exit status 1")
+		assert.ErrorContains(t, err, "first; error: validation failed")
+
+		var exitErr *exec.ExitError
+
+		assert.ErrorAs(t, err, &exitErr)
+	})
+
+	t.Run("Failing command writing more lines to stderr than the tail", func(t *testing.T) {
+		err := ExecContext(context.Background(), logger, nil, "sh", "-c", "for i in $(seq 1 20); do echo line$i >&2; done; exit 1")
+
+		assert.ErrorContains(t, err, "line11; ")
+		assert.NotContains(t, err.Error(), "line10;")
+	})
+
+	t.Run("Cancelled long-running command", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		time.AfterFunc(100*time.Millisecond, cancel)
+
+		start := time.Now()
+
+		err := ExecContext(ctx, logger, nil, "sleep", "10")
+
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Less(t, time.Since(start), 5*time.Second)
+	})
+
+	t.Run("Timed out long-running command", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		err := ExecContext(ctx, logger, nil, "sleep", "10")
+
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
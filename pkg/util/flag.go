@@ -3,8 +3,10 @@ package util
 
 import (
 	"strconv"
+	"time"
 
 	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
+	"github.com/charmbracelet/log"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
@@ -55,3 +57,51 @@ func FlagInt(cmd *cobra.Command, name string) int {
 
 	return intValue
 }
+
+// flagAnnotationSensitive is the annotation that marks the flag as holding a sensitive value.
+const flagAnnotationSensitive = "privatecloud-cli/sensitive"
+
+// RedactedValue is the value that is shown instead of a sensitive value, such as the one of a sensitive flag.
+const RedactedValue = "<redacted>"
+
+// MarkFlagSensitive marks the flag as holding a sensitive value, so that its value is redacted when logged.
+func MarkFlagSensitive(cmd *cobra.Command, name string) {
+	_ = cmd.Flags().SetAnnotation(name, flagAnnotationSensitive, []string{strconv.FormatBool(true)})
+}
+
+// LogEffectiveConfig logs the effective values of all of the flags of the command at the debug level, redacting the sensitive ones.
+func LogEffectiveConfig(l *log.Logger, cmd *cobra.Command) {
+	// logMsgEffectiveConfig is the message that is logged for each flag of the effective configuration.
+	const logMsgEffectiveConfig = "effective configuration: --%s=%s (changed: %t)"
+
+	// flagHelp is the name of the help flag that is added by Cobra, which is not part of the configuration.
+	const flagHelp = "help"
+
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		if flag.Name == flagHelp {
+			return
+		}
+
+		val := flagVal(flag)
+
+		if _, ok := flag.Annotations[flagAnnotationSensitive]; ok && val != constant.EmptyString {
+			val = RedactedValue
+		}
+
+		l.Debugf(logMsgEffectiveConfig, flag.Name, val, flag.Changed)
+	})
+}
+
+// FlagDuration returns the value of the flag as a duration or the default value if the flag is not a duration.
+func FlagDuration(cmd *cobra.Command, name string) time.Duration {
+	flag := cmd.Flag(name)
+
+	val := flagVal(flag)
+
+	durationValue, err := time.ParseDuration(val)
+	if err != nil {
+		return DiscardErr(time.ParseDuration(flag.DefValue))
+	}
+
+	return durationValue
+}
@@ -0,0 +1,125 @@
+// Package util is the package that contains the utility functions.
+package util
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
+	"go.uber.org/multierr"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// ErrInvalidHTTPSProxy is the error that is returned when the HTTPS proxy is not a valid URL.
+var ErrInvalidHTTPSProxy = errors.New("invalid HTTPS proxy")
+
+// NormalizeIssuer is a function that returns the issuer with the HTTPS scheme and without the trailing slash, so that the issuers that differ only by
+// them compare equal, e.g. the Azure issuers, which the discovery document and the JWTs may have without the trailing slash that the OIDC URL requires,
+// and the AWS ones, whose OIDC URL has no scheme.
+func NormalizeIssuer(issuer string) string {
+	// httpsScheme is the scheme for the HTTPS URL.
+	const httpsScheme = "https://"
+
+	issuer = strings.TrimSuffix(issuer, string(constant.HTTPPathSeparator))
+
+	if !strings.HasPrefix(issuer, httpsScheme) {
+		issuer = httpsScheme + issuer
+	}
+
+	return issuer
+}
+
+// proxyURL returns the URL of the proxy, with the http scheme if it has none, as the transport defaults the proxies without a scheme to it.
+func proxyURL(proxy string) string {
+	// schemeSeparator is the separator of the scheme of a URL.
+	const schemeSeparator = "://"
+
+	if !strings.Contains(proxy, schemeSeparator) {
+		return "http" + schemeSeparator + proxy
+	}
+
+	return proxy
+}
+
+// RedactedProxy returns the proxy with its password, if any, redacted, so that it can be logged. The proxy without a scheme is parsed as an http one, as
+// the transport does, and anything before the '@' of the one that cannot be parsed is redacted.
+func RedactedProxy(proxy string) string {
+	u, err := url.Parse(proxyURL(proxy))
+	if err != nil {
+		if i := strings.LastIndex(proxy, "@"); i >= 0 {
+			return RedactedValue + proxy[i:]
+		}
+
+		return proxy
+	}
+
+	if u.User == nil {
+		return proxy
+	}
+
+	return u.Redacted()
+}
+
+// NewProxyConfig returns the proxy configuration of the environment, with the HTTPS proxy and the hosts that bypass it replaced by the given ones if they
+// are not empty, or an error if the HTTPS proxy is not a valid URL.
+func NewProxyConfig(httpsProxy string, noProxy string) (*httpproxy.Config, error) {
+	cfg := httpproxy.FromEnvironment()
+
+	if httpsProxy != constant.EmptyString {
+		cfg.HTTPSProxy = httpsProxy
+	}
+
+	if noProxy != constant.EmptyString {
+		cfg.NoProxy = noProxy
+	}
+
+	if cfg.HTTPSProxy == constant.EmptyString {
+		return cfg, nil
+	}
+
+	// The proxy is validated here, as the transport silently ignores the one that is not a valid URL and connects directly.
+	if u, err := url.Parse(proxyURL(cfg.HTTPSProxy)); err != nil {
+		return nil, multierr.Combine(ErrInvalidHTTPSProxy, err)
+	} else if u.Host == constant.EmptyString {
+		return nil, fmt.Errorf("%w: %q has no host", ErrInvalidHTTPSProxy, cfg.HTTPSProxy)
+	}
+
+	return cfg, nil
+}
+
+// NewHTTPClient returns an HTTP client that gives up on establishing the connection, on the TLS handshake and on waiting for the response headers once the
+// connect timeout elapses.
+//
+// The requests go through the proxies of the proxy configuration, or of the environment if it is nil, and the servers are verified against the root CAs,
+// or the ones of the system if it is nil.
+func NewHTTPClient(connectTimeout time.Duration, proxyConfig *httpproxy.Config, rootCAs *x509.CertPool) *http.Client {
+	// keepAlive is the interval between the keep-alive probes of the connections, the same as the one of the default transport.
+	const keepAlive = 30 * time.Second
+
+	transport := http.DefaultTransport.(*http.Transport).Clone() // nolint:forcetypeassert
+
+	transport.DialContext = (&net.Dialer{Timeout: connectTimeout, KeepAlive: keepAlive}).DialContext
+	transport.TLSHandshakeTimeout = connectTimeout
+	transport.ResponseHeaderTimeout = connectTimeout
+
+	if proxyConfig != nil {
+		proxyFunc := proxyConfig.ProxyFunc()
+
+		transport.Proxy = func(req *http.Request) (*url.URL, error) {
+			return proxyFunc(req.URL)
+		}
+	}
+
+	if rootCAs != nil {
+		transport.TLSClientConfig = &tls.Config{RootCAs: rootCAs, MinVersion: tls.VersionTLS12}
+	}
+
+	return &http.Client{Transport: transport}
+}
@@ -3,7 +3,9 @@ package util
 
 import (
 	"reflect"
+	"strings"
 
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
 	pkgerrors "github.com/AlphaSense-Engineering/privatecloud-cli/pkg/errors"
 	"go.uber.org/multierr"
 )
@@ -49,6 +51,23 @@ func KeysNotEmpty[K comparable, V any](input map[K]V, keys []K) (bool, []K) {
 	return len(emptyKeys) == 0, emptyKeys
 }
 
+// KeysNotBlank is a function that checks if the values of the keys are not whitespace-only in the map.
+//
+// The values that are missing or empty are not whitespace-only, they are reported by KeysExist and KeysNotEmpty instead.
+func KeysNotBlank[K comparable](input map[K]string, keys []K) (bool, []K) {
+	blankKeys := []K{}
+
+	for _, k := range keys {
+		if v := input[k]; v == constant.EmptyString || strings.TrimSpace(v) != constant.EmptyString {
+			continue
+		}
+
+		blankKeys = append(blankKeys, k)
+	}
+
+	return len(blankKeys) == 0, blankKeys
+}
+
 const (
 	// KeysMissingBitmask is the bitmask for the keys missing.
 	KeysMissingBitmask = 1 << iota // 1
@@ -92,3 +111,15 @@ func KeysExistAndNotEmptyOrErr[K comparable, V any](input map[K]V, keys []K) err
 
 	return nil
 }
+
+// KeysExistAndNotBlankOrErr is a function that checks if the keys exist and are not empty in the map like KeysExistAndNotEmptyOrErr, and also returns an
+// error if their values are whitespace-only, which is the common mistake of creating a secret from the output of echo.
+func KeysExistAndNotBlankOrErr[K comparable](input map[K]string, keys []K) error {
+	err := KeysExistAndNotEmptyOrErr(input, keys)
+
+	if notBlank, blankKeys := KeysNotBlank(input, keys); !notBlank {
+		err = multierr.Append(err, pkgerrors.NewKeysBlank(blankKeys))
+	}
+
+	return err
+}
@@ -0,0 +1,62 @@
+// Package util is the package that contains the utility functions.
+package util
+
+import (
+	"errors"
+	"testing"
+
+	pkgerrors "github.com/AlphaSense-Engineering/privatecloud-cli/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestKeysExistAndNotBlankOrErr is a test that tests that the KeysExistAndNotBlankOrErr function reports the missing, empty and whitespace-only values.
+func TestKeysExistAndNotBlankOrErr(t *testing.T) {
+	testCases := []struct {
+		name      string
+		input     map[string]string
+		keys      []string
+		wantBlank bool
+		wantErrs  []string
+	}{
+		{
+			name:  "Valid",
+			input: map[string]string{"username": "admin", "password": " secret\n"},
+			keys:  []string{"username", "password"},
+		},
+		{
+			name:      "Trailing newline only",
+			input:     map[string]string{"username": "admin", "password": "\n"},
+			keys:      []string{"username", "password"},
+			wantBlank: true,
+			wantErrs:  []string{"keys whitespace-only: password"},
+		},
+		{
+			name:      "Missing, empty and whitespace-only",
+			input:     map[string]string{"username": "", "port": " \t"},
+			keys:      []string{"username", "password", "port"},
+			wantBlank: true,
+			wantErrs:  []string{"keys missing: password", "keys empty: username, password", "keys whitespace-only: port"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := KeysExistAndNotBlankOrErr(tc.input, tc.keys)
+
+			if tc.wantErrs == nil {
+				require.NoError(t, err)
+
+				return
+			}
+
+			var blankErr *pkgerrors.KeysBlank[string]
+
+			assert.Equal(t, tc.wantBlank, errors.As(err, &blankErr))
+
+			for _, want := range tc.wantErrs {
+				assert.ErrorContains(t, err, want)
+			}
+		})
+	}
+}
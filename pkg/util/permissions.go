@@ -0,0 +1,60 @@
+// Package util is the package that contains the utility functions.
+package util
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+
+	"github.com/AlphaSense-Engineering/privatecloud-cli/pkg/constant"
+)
+
+const (
+	// permissionsCommentPrefix is the prefix of the comment lines of a list of permissions.
+	permissionsCommentPrefix = "#"
+
+	// permissionsRemovalPrefix is the prefix of the permissions of an override that are removed from the expected ones.
+	permissionsRemovalPrefix = "-"
+)
+
+// ParsePermissions is a function that parses a list of permissions, one per line, ignoring the blank lines and the lines starting with '#'.
+func ParsePermissions(data []byte) []string {
+	permissions := []string{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == constant.EmptyString || strings.HasPrefix(line, permissionsCommentPrefix) {
+			continue
+		}
+
+		permissions = append(permissions, line)
+	}
+
+	return permissions
+}
+
+// MergePermissions is a function that returns the set of the base permissions with the permissions of the override merged in.
+//
+// The permissions of the override are added to the set, except those starting with '-', which are removed from it.
+func MergePermissions(base []string, override []string) map[string]struct{} {
+	merged := make(map[string]struct{}, len(base)+len(override))
+
+	for _, permission := range base {
+		merged[permission] = struct{}{}
+	}
+
+	for _, permission := range override {
+		if removed, ok := strings.CutPrefix(permission, permissionsRemovalPrefix); ok {
+			delete(merged, removed)
+
+			continue
+		}
+
+		merged[permission] = struct{}{}
+	}
+
+	return merged
+}
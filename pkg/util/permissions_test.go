@@ -0,0 +1,51 @@
+// Package util is the package that contains the utility functions.
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestParsePermissions is a test that tests that the ParsePermissions function skips the blank lines and the comments, trimming the permissions.
+func TestParsePermissions(t *testing.T) {
+	data := []byte("# comment\n\niam.roles.get\n  iam.roles.list  \r\n-iam.roles.delete\n")
+
+	assert.Equal(t, []string{"iam.roles.get", "iam.roles.list", "-iam.roles.delete"}, ParsePermissions(data))
+	assert.Empty(t, ParsePermissions(nil))
+}
+
+// TestMergePermissions is a test that tests the MergePermissions function.
+func TestMergePermissions(t *testing.T) {
+	base := []string{"iam.roles.get", "iam.roles.list", "iam.roles.delete"}
+
+	testCases := []struct {
+		name     string
+		override []string
+		want     map[string]struct{}
+	}{
+		{
+			name:     "No override",
+			override: nil,
+			want:     map[string]struct{}{"iam.roles.get": {}, "iam.roles.list": {}, "iam.roles.delete": {}},
+		},
+		{
+			name:     "Added and removed",
+			override: []string{"iam.roles.update", "-iam.roles.delete", "-iam.roles.unknown"},
+			want:     map[string]struct{}{"iam.roles.get": {}, "iam.roles.list": {}, "iam.roles.update": {}},
+		},
+		{
+			name:     "Already expected",
+			override: []string{"iam.roles.get"},
+			want:     map[string]struct{}{"iam.roles.get": {}, "iam.roles.list": {}, "iam.roles.delete": {}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, MergePermissions(base, tc.override))
+		})
+	}
+
+	assert.Len(t, base, 3, "the base permissions must not be modified")
+}
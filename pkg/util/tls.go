@@ -0,0 +1,84 @@
+// Package util is the package that contains the utility functions.
+package util
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+
+	"go.uber.org/multierr"
+)
+
+var (
+	// ErrFailedToParseCABundle is the error that is returned when the CA bundle cannot be parsed.
+	ErrFailedToParseCABundle = errors.New("failed to parse CA bundle")
+
+	// errNoCertificatesInCABundle is the error that is returned when the CA bundle contains no PEM encoded certificates.
+	errNoCertificatesInCABundle = errors.New("no PEM encoded certificates found in CA bundle")
+)
+
+// addCABundle adds the certificates from the PEM encoded CA bundle to the pool, returning an error if it contains none or if any of them cannot be parsed.
+func addCABundle(pool *x509.CertPool, caBundle []byte) error {
+	// pemBlockTypeCertificate is the type of the PEM block that contains a certificate.
+	const pemBlockTypeCertificate = "CERTIFICATE"
+
+	var count int
+
+	for rest := caBundle; ; {
+		var block *pem.Block
+
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+
+		if block.Type != pemBlockTypeCertificate {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return multierr.Combine(ErrFailedToParseCABundle, err)
+		}
+
+		pool.AddCert(cert)
+
+		count++
+	}
+
+	if count == 0 {
+		return multierr.Combine(ErrFailedToParseCABundle, errNoCertificatesInCABundle)
+	}
+
+	return nil
+}
+
+// TLSConfigFromCABundle returns a TLS configuration that trusts the certificates from the PEM encoded CA bundle.
+func TLSConfigFromCABundle(caBundle []byte) (*tls.Config, error) {
+	pool := x509.NewCertPool()
+
+	if err := addCABundle(pool, caBundle); err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		RootCAs:    pool,
+		MinVersion: tls.VersionTLS12,
+	}, nil
+}
+
+// SystemCertPoolWithCABundle returns the pool of the certificates of the system along with the ones from the PEM encoded CA bundle, e.g. of the internal CA
+// of a corporate proxy, or of the bundle alone if the ones of the system cannot be read.
+func SystemCertPoolWithCABundle(caBundle []byte) (*x509.CertPool, error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+
+	if err := addCABundle(pool, caBundle); err != nil {
+		return nil, err
+	}
+
+	return pool, nil
+}
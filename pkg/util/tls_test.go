@@ -0,0 +1,102 @@
+// Package util is the package that contains the utility functions.
+package util
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testCAPEM is a function that generates a self-signed test CA and returns it PEM encoded.
+func testCAPEM(t *testing.T) []byte {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "privatecloud-cli test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+// TestTLSConfigFromCABundle is a test that tests the TLSConfigFromCABundle function.
+func TestTLSConfigFromCABundle(t *testing.T) {
+	caPEM := testCAPEM(t)
+
+	testCases := []struct {
+		name     string
+		caBundle []byte
+		wantErr  bool
+	}{
+		{
+			name:     "Valid CA",
+			caBundle: caPEM,
+		},
+		{
+			name:     "Valid CA preceded by a non-certificate block",
+			caBundle: append(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: []byte("irrelevant")}), caPEM...),
+		},
+		{
+			name:     "Empty bundle",
+			caBundle: []byte{},
+			wantErr:  true,
+		},
+		{
+			name:     "Not PEM encoded",
+			caBundle: []byte("not a certificate"),
+			wantErr:  true,
+		},
+		{
+			name:     "Malformed certificate",
+			caBundle: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("garbage")}),
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := TLSConfigFromCABundle(tc.caBundle)
+
+			if tc.wantErr {
+				assert.ErrorIs(t, err, ErrFailedToParseCABundle)
+				assert.Nil(t, got)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotNil(t, got.RootCAs)
+		})
+	}
+}
+
+// TestSystemCertPoolWithCABundle is a test that tests that the SystemCertPoolWithCABundle function adds the certificates of the CA bundle to the pool, and
+// rejects the bundles as the TLSConfigFromCABundle function does.
+func TestSystemCertPoolWithCABundle(t *testing.T) {
+	pool, err := SystemCertPoolWithCABundle(testCAPEM(t))
+	require.NoError(t, err)
+	assert.NotNil(t, pool)
+
+	_, err = SystemCertPoolWithCABundle([]byte("not a certificate"))
+	assert.ErrorIs(t, err, ErrFailedToParseCABundle)
+}